@@ -7,11 +7,25 @@ import (
 	"os"
 	"strings"
 
+	"github.com/Legit-Labs/legitify/internal/activity"
+	"github.com/Legit-Labs/legitify/internal/branches"
+	glclient "github.com/Legit-Labs/legitify/internal/clients/gitlab"
 	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/Legit-Labs/legitify/internal/common/rotation"
+	"github.com/Legit-Labs/legitify/internal/common/scheduler"
+	"github.com/Legit-Labs/legitify/internal/common/severity"
+	"github.com/Legit-Labs/legitify/internal/compliance"
+	"github.com/Legit-Labs/legitify/internal/confidence"
+	"github.com/Legit-Labs/legitify/internal/logger"
 
 	"github.com/Legit-Labs/legitify/internal/outputer/formatter"
 	"github.com/Legit-Labs/legitify/internal/outputer/scheme/converter"
+	"github.com/Legit-Labs/legitify/internal/sampling"
+	"github.com/Legit-Labs/legitify/internal/store"
+	"github.com/Legit-Labs/legitify/internal/tracing"
+	"github.com/Legit-Labs/legitify/internal/workflows"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"github.com/spf13/viper"
 )
@@ -21,15 +35,55 @@ func init() {
 }
 
 const (
-	argOrg          = "org"
-	argRepository   = "repo"
-	argPoliciesPath = "policies-path"
-	argNamespace    = "namespace"
-	argOutputFormat = "output-format"
-	argOutputScheme = "output-scheme"
-	argColor        = "color"
-	argScorecard    = "scorecard"
-	argFailedOnly   = "failed-only"
+	argOrg            = "org"
+	argRepository     = "repo"
+	argPoliciesPath   = "policies-path"
+	argNamespace      = "namespace"
+	argOutputFormat   = "output-format"
+	argOutputScheme   = "output-scheme"
+	argOutputTemplate = "output-template"
+	argColor          = "color"
+	argScorecard      = "scorecard"
+	argFailedOnly     = "failed-only"
+	argMinConfidence  = "min-confidence"
+	argPolicyTags     = "policy-tags"
+	argExcludedTags   = "exclude-policy-tags"
+	argCompliance     = "compliance"
+	argIdentitySource = "identity-source"
+	argContacts       = "security-contacts"
+	argSkipPolicy     = "skip-policy"
+	argCreateCheckRun = "create-check-runs"
+	argGhasMatrixFile = "ghas-matrix-file"
+	argSeverityLabels = "severity-labels"
+	argSecretMaxAge   = "secret-max-age"
+	argTrustedActions = "trusted-action-publishers"
+	argSuspicious     = "suspicious-files"
+	argBranches       = "protected-branches"
+	argWebhookDomains = "webhook-allowed-domains"
+	argSensitiveOrgs  = "high-sensitivity-orgs"
+	argSubgroups      = "subgroups"
+	argActivityDays   = "audit-log-activity-days"
+	argDormantAdmin   = "dormant-admin-days"
+	argTelemetry      = "telemetry-endpoint"
+	argTelemetryLog   = "telemetry-log"
+	argFields         = "fields"
+	argUploadCodeScan = "upload-code-scanning"
+	argCodeScanRepo   = "code-scanning-repo"
+	argCreateIssues   = "create-issues"
+	argIssuesRepo     = "issues-repo"
+	argMetricsFile    = "metrics-file"
+	argMetricsAddr    = "metrics-addr"
+	argStore          = "store"
+	argSample         = "sample"
+	argConcurrency    = "max-concurrency"
+	argOrgConcurrency = "max-org-concurrency"
+	argPublishPerOrg  = "publish-per-org"
+	argPerOrgDir      = "per-org-output-dir"
+	argOtlpEndpoint   = "otlp-endpoint"
+	argScanID         = "scan-id"
+	argLogLevel       = "log-level"
+	argLogFormat      = "log-format"
+	argLogFile        = "log-file"
 )
 
 func toOptionsString(options []string) string {
@@ -50,6 +104,7 @@ func newAnalyzeCommand() *cobra.Command {
 	schemeTypes := toOptionsString(converter.SchemeTypes())
 	colorWhens := toOptionsString(ColorOptions())
 	scorecardWhens := toOptionsString(scorecardOptions())
+	frameworks := toOptionsString(compliance.FrameworkNames())
 
 	viper.AutomaticEnv()
 	flags := analyzeCmd.Flags()
@@ -61,9 +116,50 @@ func newAnalyzeCommand() *cobra.Command {
 	flags.StringSliceVarP(&analyzeArgs.Namespaces, argNamespace, "n", namespace.All, "which namespace to run")
 	flags.StringVarP(&analyzeArgs.OutputFormat, argOutputFormat, "f", formatter.Human, "output format "+formats)
 	flags.StringVarP(&analyzeArgs.OutputScheme, argOutputScheme, "", converter.DefaultScheme, "output scheme "+schemeTypes)
+	flags.StringSliceVarP(&analyzeArgs.Fields, argFields, "", nil, "fields of the json output to include (e.g. policyInfo.title,violations.canonicalLink) or to drop (prefixed with '-', e.g. -policyInfo.remediationSteps)")
+	flags.StringVarP(&analyzeArgs.OutputTemplate, argOutputTemplate, "", "", "go template file to render the output with (overrides --output-format)")
 	flags.StringVarP(&analyzeArgs.ColorWhen, argColor, "", DefaultColorOption, "when to use coloring "+colorWhens)
 	flags.StringVarP(&analyzeArgs.ScorecardWhen, argScorecard, "", DefaultScOption, "Whether to run additional scorecard checks "+scorecardWhens)
 	flags.BoolVarP(&analyzeArgs.FailedOnly, argFailedOnly, "", false, "Only show violated policied (do not show succeeded/skipped)")
+	flags.StringVarP(&analyzeArgs.MinConfidence, argMinConfidence, "", confidence.Low, "only show results of at least the given confidence (results based on partially collected data, e.g. due to missing permissions, are of lower confidence) "+toOptionsString(confidence.Levels()))
+	flags.StringSliceVarP(&analyzeArgs.PolicyTags, argPolicyTags, "", nil, "only run policies that have at least one of the given tags (e.g. supply-chain,access-control)")
+	flags.StringSliceVarP(&analyzeArgs.ExcludedTags, argExcludedTags, "", nil, "do not run policies that have any of the given tags")
+	flags.StringSliceVarP(&analyzeArgs.SkippedPolicy, argSkipPolicy, "", nil, "policy to exclude from the evaluation (can be repeated), either the policy name or <namespace>.<policy name>")
+	flags.BoolVarP(&analyzeArgs.CreateCheckRuns, argCreateCheckRun, "", false, "create a check run summarizing the violations on each scanned repository (requires a GitHub App token, e.g. in GitHub Actions)")
+	flags.BoolVarP(&analyzeArgs.SuspiciousFiles, argSuspicious, "", false, "look for files that usually contain secrets (e.g. .env, id_rsa) in the default branch of the repositories, by their names")
+	flags.StringSliceVarP(&analyzeArgs.ProtectedBranches, argBranches, "", nil, "name patterns of non-default branches that should be protected as well (e.g. release/*,hotfix/*)")
+	flags.StringSliceVarP(&analyzeArgs.WebhookDomains, argWebhookDomains, "", nil, "domains the webhooks may deliver to, including their subdomains (e.g. example.com,slack.com); webhooks to other hosts are reported")
+	flags.StringSliceVarP(&analyzeArgs.SensitiveOrgs, argSensitiveOrgs, "", nil, "organizations (or GitLab groups) classified as high-sensitivity, which are expected to restrict access by IP and to avoid shared runners (e.g. org1,org2)")
+	flags.StringVarP(&analyzeArgs.Subgroups, argSubgroups, "", glclient.SubgroupsAll, "GitLab only: which groups to collect: all the owned groups and their subgroups, only the groups given with --org by their full paths, or only the top-level groups "+toOptionsString(glclient.SubgroupsModes()))
+	flags.IntVarP(&analyzeArgs.ActivityDays, argActivityDays, "", 0, "opt-in: collect the members' activity from the organization audit log (or the GitLab group audit events) of the given number of days, and report the members without any activity in that period (enterprise organizations or GitLab Premium groups only, may take a while for large organizations)")
+	flags.IntVarP(&analyzeArgs.DormantAdminDays, argDormantAdmin, "", activity.DefaultDormantAdminDays, "number of days without activity after which an organization admin is reported as dormant")
+	flags.BoolVarP(&analyzeArgs.UploadCodeScanning, argUploadCodeScan, "", false, "upload the results (as SARIF) to the code scanning alerts of each scanned repository")
+	flags.StringVarP(&analyzeArgs.CodeScanningRepo, argCodeScanRepo, "", "", "with --"+argUploadCodeScan+": upload all the results to the code scanning alerts of this repository instead (owner/repo_name)")
+	flags.BoolVarP(&analyzeArgs.CreateIssues, argCreateIssues, "", false, "open a tracking issue per violated policy in each affected repository (and close it once resolved)")
+	flags.StringVarP(&analyzeArgs.IssuesRepo, argIssuesRepo, "", "", "with --"+argCreateIssues+": track the violations of all scanned entities in this repository instead (owner/repo_name)")
+	flags.StringVarP(&analyzeArgs.MetricsFile, argMetricsFile, "", "", "write Prometheus metrics (findings, collection durations, API calls) to the given file (e.g. for the node_exporter textfile collector)")
+	flags.StringVarP(&analyzeArgs.MetricsAddr, argMetricsAddr, "", "", "serve Prometheus metrics on the given address (e.g. :9090); legitify keeps serving the results after the analysis until interrupted")
+	flags.StringVarP(&analyzeArgs.Store, argStore, "", "", "save the results in a store for later commands (e.g. history), selected by its DSN: file://<directory> (or a directory path), sqlite://<file>, postgres://<user>:<password>@<host>/<database> or dynamodb://<table>?region=<region>")
+	flags.StringVarP(&analyzeArgs.Sample, argSample, "", "", "quick scan of a sample of the repositories (e.g. 10%), stratified by visibility & activity; the results are marked as sampled")
+	flags.IntVarP(&analyzeArgs.Concurrency, argConcurrency, "", scheduler.DefaultWorkers, "maximal number of repositories collected concurrently (across all organizations, which are served in turns)")
+	flags.IntVarP(&analyzeArgs.OrgConcurrency, argOrgConcurrency, "", 0, "maximal number of repositories collected concurrently per organization (defaults to --"+argConcurrency+")")
+	flags.BoolVarP(&analyzeArgs.PublishPerOrg, argPublishPerOrg, "", false, "write (and publish) the results of each organization as soon as it's analyzed, in addition to the combined output")
+	flags.StringVarP(&analyzeArgs.PerOrgOutputDir, argPerOrgDir, "", ".", "with --"+argPublishPerOrg+": directory to write the result document of each organization to (legitify-<org>.<ext>)")
+	flags.StringVarP(&analyzeArgs.OtlpEndpoint, argOtlpEndpoint, "", "", "export OpenTelemetry traces of the collection & analysis to an OTLP/HTTP collector (e.g. http://localhost:4318, can be set via the environment variable OTEL_EXPORTER_OTLP_ENDPOINT)")
+	flags.StringVarP(&analyzeArgs.TelemetryEndpoint, argTelemetry, "", "", "opt-in: send an anonymous usage report (run duration, entity counts & the names of the options used) to the given endpoint")
+	flags.StringVarP(&analyzeArgs.TelemetryLog, argTelemetryLog, "", "", "append a local copy of exactly what the usage telemetry sends to the given file (written even without --"+argTelemetry+")")
+	flags.StringVarP(&analyzeArgs.ScanID, argScanID, "", "", "ID of the scan, included in the logs, output, metrics & published results to correlate them (defaults to a random UUID)")
+	flags.StringVarP(&analyzeArgs.LogLevel, argLogLevel, "", logger.InfoLevel.String(), "minimal level of the logged messages "+toOptionsString(logger.Levels()))
+	flags.StringVarP(&analyzeArgs.LogFormat, argLogFormat, "", logger.TextFormat, "format of the logged messages "+toOptionsString(logger.Formats()))
+	flags.StringVarP(&analyzeArgs.LogFile, argLogFile, "", "", "log file, defaults to the error log (--"+ArgErrorFile+")")
+	flags.StringVarP(&analyzeArgs.GhasMatrixFile, argGhasMatrixFile, "", "", "write a per-repository GitHub Advanced Security coverage matrix (csv) to the given file")
+	flags.StringVarP(&analyzeArgs.ConfigFile, argConfigFile, "", "", "yaml config file with default values for the command options (keyed by the option names)")
+	flags.StringVarP(&analyzeArgs.IdentitySource, argIdentitySource, "", "", "HR/IdP users export (csv or SCIM json) to cross-check the organization members against")
+	flags.StringVarP(&analyzeArgs.SecurityContacts, argContacts, "", "", "yaml file mapping organizations and repositories (owner/name) to their security contacts & escalation channels, added to the violations and the published issues & check runs")
+	flags.StringToStringVarP(&analyzeArgs.SeverityLabels, argSeverityLabels, "", nil, "display severities using custom labels (e.g. critical=P1,high=P2,medium=P3,low=P4)")
+	flags.StringToIntVarP(&analyzeArgs.SecretMaxAge, argSecretMaxAge, "", nil, "maximal age in days of the actions secrets before they should be rotated, per severity (default high=365,medium=180)")
+	flags.StringSliceVarP(&analyzeArgs.TrustedPublishers, argTrustedActions, "", workflows.DefaultTrustedPublishers, "owners of the actions that may be used in the workflows without pinning them to a commit SHA")
+	flags.StringVarP(&analyzeArgs.Compliance, argCompliance, "", "", "group and score the results by the controls of a compliance framework "+frameworks)
 
 	return analyzeCmd
 }
@@ -85,10 +181,159 @@ func validateAnalyzeArgs() error {
 		return err
 	}
 
+	if analyzeArgs.OutputTemplate != "" {
+		if err := formatter.ValidateOutputTemplate(analyzeArgs.OutputTemplate); err != nil {
+			return err
+		}
+	}
+
+	if analyzeArgs.Compliance != "" {
+		if err := compliance.ValidateFramework(analyzeArgs.Compliance); err != nil {
+			return err
+		}
+	}
+
+	if len(analyzeArgs.Fields) > 0 {
+		if analyzeArgs.OutputFormat != formatter.Json || analyzeArgs.OutputTemplate != "" || analyzeArgs.Compliance != "" {
+			return fmt.Errorf("--%s is only supported for the json output format (without a template or a compliance report)", argFields)
+		}
+		if _, err := formatter.ParseFields(analyzeArgs.Fields); err != nil {
+			return err
+		}
+	}
+
+	if _, err := logger.ParseLevel(analyzeArgs.LogLevel); err != nil {
+		return err
+	}
+
+	if err := logger.ValidateFormat(analyzeArgs.LogFormat); err != nil {
+		return err
+	}
+
+	if !confidence.IsValid(analyzeArgs.MinConfidence) {
+		return fmt.Errorf("invalid --%s: %s (expected one of %s)", argMinConfidence, analyzeArgs.MinConfidence, toOptionsString(confidence.Levels()))
+	}
+
 	if err := ValidateScorecardOption(analyzeArgs.ScorecardWhen); err != nil {
 		return err
 	}
 
+	if _, err := rotation.ParseMaxAge(analyzeArgs.SecretMaxAge); err != nil {
+		return err
+	}
+
+	if analyzeArgs.CreateCheckRuns && analyzeArgs.ScmType != scm_type.GitHub {
+		return fmt.Errorf("--%s is only supported for GitHub", argCreateCheckRun)
+	}
+
+	if analyzeArgs.UploadCodeScanning && analyzeArgs.ScmType != scm_type.GitHub {
+		return fmt.Errorf("--%s is only supported for GitHub", argUploadCodeScan)
+	}
+
+	if analyzeArgs.CodeScanningRepo != "" {
+		if !analyzeArgs.UploadCodeScanning {
+			return fmt.Errorf("--%s requires --%s", argCodeScanRepo, argUploadCodeScan)
+		}
+		if _, err := validateRepositories([]string{analyzeArgs.CodeScanningRepo}); err != nil {
+			return err
+		}
+	}
+
+	if analyzeArgs.CreateIssues && analyzeArgs.ScmType != scm_type.GitHub {
+		return fmt.Errorf("--%s is only supported for GitHub", argCreateIssues)
+	}
+
+	if analyzeArgs.IssuesRepo != "" {
+		if !analyzeArgs.CreateIssues {
+			return fmt.Errorf("--%s requires --%s", argIssuesRepo, argCreateIssues)
+		}
+		if _, err := validateRepositories([]string{analyzeArgs.IssuesRepo}); err != nil {
+			return err
+		}
+	}
+
+	if analyzeArgs.GhasMatrixFile != "" && analyzeArgs.ScmType != scm_type.GitHub {
+		return fmt.Errorf("--%s is only supported for GitHub", argGhasMatrixFile)
+	}
+
+	if analyzeArgs.Sample != "" {
+		if analyzeArgs.ScmType != scm_type.GitHub {
+			return fmt.Errorf("--%s is only supported for GitHub", argSample)
+		}
+		if len(analyzeArgs.Repositories) != 0 {
+			return fmt.Errorf("cannot use --%s & --repo options together", argSample)
+		}
+		if _, err := sampling.ParsePercent(analyzeArgs.Sample); err != nil {
+			return err
+		}
+	}
+
+	if len(analyzeArgs.ProtectedBranches) != 0 {
+		if analyzeArgs.ScmType != scm_type.GitHub {
+			return fmt.Errorf("--%s is only supported for GitHub", argBranches)
+		}
+		if err := branches.ValidatePatterns(analyzeArgs.ProtectedBranches); err != nil {
+			return err
+		}
+	}
+
+	if len(analyzeArgs.WebhookDomains) != 0 && analyzeArgs.ScmType != scm_type.GitHub {
+		return fmt.Errorf("--%s is only supported for GitHub", argWebhookDomains)
+	}
+
+	if analyzeArgs.Subgroups != glclient.SubgroupsAll {
+		if analyzeArgs.ScmType != scm_type.GitLab {
+			return fmt.Errorf("--%s is only supported for GitLab", argSubgroups)
+		}
+		if analyzeArgs.Subgroups != glclient.SubgroupsSelected && analyzeArgs.Subgroups != glclient.SubgroupsNone {
+			return fmt.Errorf("invalid --%s: %s (expected one of %s)", argSubgroups, analyzeArgs.Subgroups, toOptionsString(glclient.SubgroupsModes()))
+		}
+		if analyzeArgs.Subgroups == glclient.SubgroupsSelected && len(analyzeArgs.Organizations) == 0 {
+			return fmt.Errorf("--%s=%s requires the groups' full paths (--%s)", argSubgroups, glclient.SubgroupsSelected, argOrg)
+		}
+	}
+
+	if analyzeArgs.ActivityDays != 0 {
+		if analyzeArgs.ActivityDays < 0 {
+			return fmt.Errorf("--%s must not be negative", argActivityDays)
+		}
+	}
+
+	if analyzeArgs.DormantAdminDays < 1 {
+		return fmt.Errorf("--%s must be at least 1", argDormantAdmin)
+	}
+
+	if analyzeArgs.Concurrency < 1 {
+		return fmt.Errorf("--%s must be at least 1", argConcurrency)
+	}
+	if analyzeArgs.OrgConcurrency < 0 {
+		return fmt.Errorf("--%s must not be negative", argOrgConcurrency)
+	}
+
+	if analyzeArgs.Store != "" {
+		if err := store.Validate(analyzeArgs.Store); err != nil {
+			return fmt.Errorf("invalid --%s: %v", argStore, err)
+		}
+	}
+
+	if analyzeArgs.PublishPerOrg {
+		if info, err := os.Stat(analyzeArgs.PerOrgOutputDir); err != nil || !info.IsDir() {
+			return fmt.Errorf("--%s must be an existing directory: %s", argPerOrgDir, analyzeArgs.PerOrgOutputDir)
+		}
+	}
+
+	if analyzeArgs.Offline {
+		if IsScorecardEnabled(analyzeArgs.ScorecardWhen) {
+			return fmt.Errorf("cannot use --%s & --%s options together (scorecard depends on external services)", ArgOffline, argScorecard)
+		}
+		if analyzeArgs.OtlpEndpoint != "" {
+			return fmt.Errorf("cannot use --%s & --%s options together (tracing is exported to an external collector)", ArgOffline, argOtlpEndpoint)
+		}
+		if analyzeArgs.TelemetryEndpoint != "" {
+			return fmt.Errorf("cannot use --%s & --%s options together", ArgOffline, argTelemetry)
+		}
+	}
+
 	if len(analyzeArgs.Organizations) != 0 && len(analyzeArgs.Repositories) != 0 {
 		return fmt.Errorf("cannot use --org & --repo options together")
 	}
@@ -97,18 +342,25 @@ func validateAnalyzeArgs() error {
 }
 
 func executeAnalyzeCommand(cmd *cobra.Command, _args []string) error {
-	analyzeArgs.ApplyEnvVars()
-
-	// to make sure scorecard works
-	if err := os.Setenv("GITHUB_AUTH_TOKEN", analyzeArgs.Token); err != nil {
+	if err := applyConfigFile(cmd.Flags(), analyzeArgs.ConfigFile); err != nil {
 		return err
 	}
 
+	analyzeArgs.ApplyEnvVars()
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		analyzeArgs.usedFlags = append(analyzeArgs.usedFlags, flag.Name)
+	})
+
 	err := validateAnalyzeArgs()
 	if err != nil {
 		return err
 	}
 
+	// to make sure scorecard works (after the validation, which resolves the token)
+	if err = os.Setenv("GITHUB_AUTH_TOKEN", analyzeArgs.Token); err != nil {
+		return err
+	}
+
 	if err = setErrorFile(analyzeArgs.ErrorFile); err != nil {
 		return err
 	}
@@ -123,7 +375,30 @@ func executeAnalyzeCommand(cmd *cobra.Command, _args []string) error {
 		return err
 	}
 
+	if err = severity.SetLabels(analyzeArgs.SeverityLabels); err != nil {
+		return err
+	}
+
+	if analyzeArgs.ScanID == "" {
+		if analyzeArgs.ScanID, err = newScanID(); err != nil {
+			return err
+		}
+	}
+
+	if err = initLogger(&analyzeArgs); err != nil {
+		return err
+	}
+
 	stdErrLog := log.New(os.Stderr, "", 0)
+	stdErrLog.Printf("Scan ID: %s", analyzeArgs.ScanID)
+	if analyzeArgs.Offline {
+		stdErrLog.Printf("Offline mode: outbound requests other than to the SCM endpoint are blocked")
+	}
+
+	if analyzeArgs.OtlpEndpoint != "" {
+		tracing.Init(analyzeArgs.OtlpEndpoint, "legitify analyze")
+		tracing.RootSpan().SetAttribute("scan.id", analyzeArgs.ScanID)
+	}
 
 	var executor = &analyzeExecutor{}
 
@@ -131,6 +406,8 @@ func executeAnalyzeCommand(cmd *cobra.Command, _args []string) error {
 		executor, err = setupGitHub(&analyzeArgs, stdErrLog)
 	} else if analyzeArgs.ScmType == scm_type.GitLab {
 		executor, err = setupGitLab(&analyzeArgs, stdErrLog)
+	} else if analyzeArgs.ScmType == scm_type.CodeCommit {
+		executor, err = setupCodeCommit(&analyzeArgs, stdErrLog)
 	} else {
 		// shouldn't happen since scm type is validated before
 		return fmt.Errorf("invalid scm type %s", analyzeArgs.ScmType)
@@ -140,5 +417,24 @@ func executeAnalyzeCommand(cmd *cobra.Command, _args []string) error {
 		return err
 	}
 
-	return executor.Run()
+	if analyzeArgs.MetricsAddr == "" {
+		return runTraced(executor)
+	}
+
+	serverErrors := startMetricsServer(analyzeArgs.MetricsAddr, stdErrLog)
+	if err = runTraced(executor); err != nil {
+		return err
+	}
+
+	stdErrLog.Printf("Analysis finished, serving metrics on %s (press Ctrl+C to stop)", analyzeArgs.MetricsAddr)
+	return <-serverErrors
+}
+
+// runTraced runs the analysis and exports its traces (when tracing is enabled)
+func runTraced(executor *analyzeExecutor) error {
+	err := executor.Run()
+	if shutdownErr := tracing.Shutdown(err); shutdownErr != nil {
+		log.Printf("failed to export traces: %v", shutdownErr)
+	}
+	return err
 }
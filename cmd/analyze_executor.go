@@ -1,11 +1,17 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
 	"github.com/Legit-Labs/legitify/cmd/progressbar"
 	"github.com/Legit-Labs/legitify/internal/analyzers"
+	"github.com/Legit-Labs/legitify/internal/collectors"
 	"github.com/Legit-Labs/legitify/internal/collectors/collectors_manager"
+	"github.com/Legit-Labs/legitify/internal/common/group_waiter"
 	"github.com/Legit-Labs/legitify/internal/enricher"
 	"github.com/Legit-Labs/legitify/internal/outputer"
+	"github.com/Legit-Labs/legitify/internal/publishers"
+	"github.com/Legit-Labs/legitify/internal/tracing"
 	"log"
 	"os"
 )
@@ -15,6 +21,9 @@ type analyzeExecutor struct {
 	analyzer        analyzers.Analyzer
 	enricherManager enricher.EnricherManager
 	out             outputer.Outputer
+	publishers      []publishers.Publisher
+	// perOrganization is set when the results of each organization are published as soon as it's analyzed
+	perOrganization *organizationPublishing
 	log             *log.Logger
 }
 
@@ -22,26 +31,40 @@ func initializeAnalyzeExecutor(manager collectors_manager.CollectorManager,
 	analyzer analyzers.Analyzer,
 	enricherManager enricher.EnricherManager,
 	outputer outputer.Outputer,
+	publishers []publishers.Publisher,
+	perOrganization *organizationPublishing,
 	log *log.Logger) *analyzeExecutor {
 	return &analyzeExecutor{
 		manager:         manager,
 		analyzer:        analyzer,
 		enricherManager: enricherManager,
 		out:             outputer,
+		publishers:      publishers,
+		perOrganization: perOrganization,
 		log:             log,
 	}
 }
 
 func (r *analyzeExecutor) Run() error {
 	r.log.Printf("Gathering collection metadata...")
+	_, span := tracing.Start(context.Background(), "collect metadata")
 	collectionMetadata := r.manager.CollectMetadata()
+	span.End(nil)
 	progressBar := progressbar.NewProgressBar(collectionMetadata)
 
 	// TODO progressBar should run before collection starts and wait for channels to read from
 	collectionChannels := r.manager.Collect()
-	pWaiter := progressBar.Run(collectionChannels.Progress)
-	analyzedDataChan := r.analyzer.Analyze(collectionChannels.Collected)
-	enrichedDataChan := r.enricherManager.Enrich(analyzedDataChan)
+	var pWaiter group_waiter.Waitable
+	var enrichedDataChan <-chan enricher.EnrichedData
+	if r.perOrganization != nil {
+		progress := make(chan collectors.CollectionMetric)
+		pWaiter = progressBar.Run(progress)
+		enrichedDataChan = r.analyzePerOrganization(collectionChannels, progress)
+	} else {
+		pWaiter = progressBar.Run(collectionChannels.Progress)
+		analyzedDataChan := r.analyzer.Analyze(collectionChannels.Collected)
+		enrichedDataChan = r.enricherManager.Enrich(analyzedDataChan)
+	}
 	outputWaiter := r.out.Digest(enrichedDataChan)
 
 	// Wait for progress bars to finish before outputting
@@ -50,5 +73,37 @@ func (r *analyzeExecutor) Run() error {
 	// Wait for output to be digested
 	outputWaiter.Wait()
 
-	return r.out.Output(os.Stdout)
+	_, span = tracing.Start(context.Background(), "output")
+	err := r.out.Output(os.Stdout)
+	span.End(err)
+	if err != nil {
+		return err
+	}
+
+	if err = r.publish(); err != nil {
+		return err
+	}
+
+	if r.perOrganization != nil {
+		return r.perOrganization.err()
+	}
+	return nil
+}
+
+func (r *analyzeExecutor) publish() error {
+	for _, publisher := range r.publishers {
+		if r.perOrganization != nil && publishers.PublishesPerOrganization(publisher) {
+			// already published per organization
+			continue
+		}
+		r.log.Printf("Publishing results to %s...", publisher.Name())
+		_, span := tracing.Start(context.Background(), fmt.Sprintf("publish %s", publisher.Name()))
+		err := publisher.Publish(r.out.Results())
+		span.End(err)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/Legit-Labs/legitify/internal/collectors"
+	"github.com/Legit-Labs/legitify/internal/collectors/collectors_manager"
+	"github.com/Legit-Labs/legitify/internal/common/group_waiter"
+	"github.com/Legit-Labs/legitify/internal/enricher"
+	"github.com/Legit-Labs/legitify/internal/outputer"
+	"github.com/Legit-Labs/legitify/internal/outputer/formatter"
+	"github.com/Legit-Labs/legitify/internal/publishers"
+)
+
+// organizationPublishing writes and publishes the results of each organization as soon as it's analyzed (--publish-per-org)
+type organizationPublishing struct {
+	outputDir   string
+	extension   string
+	newOutputer func() (outputer.Outputer, error)
+
+	errorsMutex sync.Mutex
+	errors      []string
+}
+
+// provideOrganizationPublishing returns nil unless the results should be published per organization
+func provideOrganizationPublishing(ctx context.Context, analyzeArgs *args) *organizationPublishing {
+	if !analyzeArgs.PublishPerOrg {
+		return nil
+	}
+
+	return &organizationPublishing{
+		outputDir: analyzeArgs.PerOrgOutputDir,
+		extension: outputExtension(analyzeArgs),
+		newOutputer: func() (outputer.Outputer, error) {
+			return provideOutputer(ctx, analyzeArgs)
+		},
+	}
+}
+
+func outputExtension(analyzeArgs *args) string {
+	if analyzeArgs.OutputTemplate != "" {
+		return ".txt"
+	}
+
+	switch analyzeArgs.OutputFormat {
+	case formatter.Json:
+		return ".json"
+	case formatter.Sarif:
+		return ".sarif"
+	default:
+		return ".txt"
+	}
+}
+
+// outputPath is the path of the organization result document
+func (p *organizationPublishing) outputPath(org string) string {
+	name := strings.NewReplacer("/", "_", "\\", "_").Replace(org)
+	return filepath.Join(p.outputDir, "legitify-"+name+p.extension)
+}
+
+func (p *organizationPublishing) addError(org string, err error) {
+	p.errorsMutex.Lock()
+	defer p.errorsMutex.Unlock()
+	p.errors = append(p.errors, fmt.Sprintf("%s: %v", org, err))
+}
+
+func (p *organizationPublishing) err() error {
+	p.errorsMutex.Lock()
+	defer p.errorsMutex.Unlock()
+
+	if len(p.errors) == 0 {
+		return nil
+	}
+	return fmt.Errorf("failed to publish the results of some organizations:\n%s", strings.Join(p.errors, "\n"))
+}
+
+// analyzePerOrganization analyzes the collected data of each organization in its own pipeline, which is closed (and published)
+// once the organization collection is finished. The enriched data of all the organizations is returned for the combined output,
+// and the progress metrics are forwarded to the given channel.
+func (r *analyzeExecutor) analyzePerOrganization(channels collectors_manager.CollectorChannels, progress chan<- collectors.CollectionMetric) <-chan enricher.EnrichedData {
+	combined := make(chan enricher.EnrichedData)
+
+	go func() {
+		defer close(combined)
+		defer close(progress)
+
+		gw := group_waiter.New()
+		pipelines := make(map[string]chan collectors.CollectedData)
+		finish := func(org string) {
+			if pipeline, ok := pipelines[org]; ok {
+				close(pipeline)
+				delete(pipelines, org)
+			}
+		}
+
+		// the collected data of an organization is always received before its completion,
+		// so both are handled by this loop to close each pipeline after all of its data was sent
+		collected, metrics := channels.Collected, channels.Progress
+		for collected != nil || metrics != nil {
+			select {
+			case data, ok := <-collected:
+				if !ok {
+					collected = nil
+					continue
+				}
+				pipeline, exists := pipelines[data.Organization]
+				if !exists {
+					pipeline = make(chan collectors.CollectedData)
+					pipelines[data.Organization] = pipeline
+					org := data.Organization
+					gw.Do(func() {
+						r.analyzeOrganization(org, pipeline, combined)
+					})
+				}
+				pipeline <- data
+			case metric, ok := <-metrics:
+				if !ok {
+					metrics = nil
+					continue
+				}
+				if metric.Organization != "" {
+					finish(metric.Organization)
+				}
+				progress <- metric
+			}
+		}
+
+		// e.g. the owners of specific repositories (--repo) are only finished once the whole collection is
+		for org := range pipelines {
+			finish(org)
+		}
+		gw.Wait()
+	}()
+
+	return combined
+}
+
+func (r *analyzeExecutor) analyzeOrganization(org string, collected <-chan collectors.CollectedData, combined chan<- enricher.EnrichedData) {
+	enriched := r.enricherManager.Enrich(r.analyzer.Analyze(collected))
+
+	out, err := r.perOrganization.newOutputer()
+	if err != nil {
+		r.perOrganization.addError(org, err)
+		for data := range enriched {
+			combined <- data
+		}
+		return
+	}
+
+	organizationData := make(chan enricher.EnrichedData)
+	outputWaiter := out.Digest(organizationData)
+	for data := range enriched {
+		organizationData <- data
+		combined <- data
+	}
+	close(organizationData)
+	outputWaiter.Wait()
+
+	if err = r.publishOrganization(org, out); err != nil {
+		r.perOrganization.addError(org, err)
+		return
+	}
+	r.log.Printf("Published the results of %s", org)
+}
+
+func (r *analyzeExecutor) publishOrganization(org string, out outputer.Outputer) error {
+	file, err := os.Create(r.perOrganization.outputPath(org))
+	if err != nil {
+		return err
+	}
+	err = out.Output(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, publisher := range r.publishers {
+		if !publishers.PublishesPerOrganization(publisher) {
+			continue
+		}
+		if err = publisher.Publish(out.Results()); err != nil {
+			return fmt.Errorf("%s: %v", publisher.Name(), err)
+		}
+	}
+
+	return nil
+}
@@ -11,3 +11,8 @@ type Client interface {
 	Organizations() ([]types.Organization, error)
 	Repositories() ([]types.RepositoryWithOwner, error)
 }
+
+// VersionedClient is implemented by the clients of servers whose features depend on their version
+type VersionedClient interface {
+	UnsupportedFeatures() []string
+}
@@ -1,44 +1,124 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+
 	"github.com/Legit-Labs/legitify/internal/common/scm_type"
+	"github.com/Legit-Labs/legitify/internal/login"
+	"github.com/Legit-Labs/legitify/internal/network"
+	"github.com/Legit-Labs/legitify/internal/offline"
+	"github.com/Legit-Labs/legitify/internal/token_source"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
 type args struct {
-	Token         string
-	Endpoint      string
-	ScmType       scm_type.ScmType
-	Organizations []string
-	Repositories  []string
-	PoliciesPath  []string
-	Namespaces    []string
-	ColorWhen     string
-	OutputFile    string
-	ErrorFile     string
-	OutputFormat  string
-	OutputScheme  string
-	ScorecardWhen string
-	FailedOnly    bool
+	Token              string
+	TokenFile          string
+	TokenCommand       string
+	VaultPath          string
+	VaultField         string
+	VaultAuth          string
+	VaultAuthMount     string
+	VaultRole          string
+	Endpoint           string
+	ScmType            scm_type.ScmType
+	Offline            bool
+	CABundle           string
+	ClientCert         string
+	ClientKey          string
+	Proxy              string
+	Organizations      []string
+	Repositories       []string
+	PoliciesPath       []string
+	Namespaces         []string
+	ColorWhen          string
+	OutputFile         string
+	ErrorFile          string
+	OutputFormat       string
+	OutputScheme       string
+	OutputTemplate     string
+	ScorecardWhen      string
+	FailedOnly         bool
+	MinConfidence      string
+	PolicyTags         []string
+	ExcludedTags       []string
+	Compliance         string
+	IdentitySource     string
+	SecurityContacts   string
+	SkippedPolicy      []string
+	ConfigFile         string
+	CreateCheckRuns    bool
+	GhasMatrixFile     string
+	SeverityLabels     map[string]string
+	SecretMaxAge       map[string]int
+	TrustedPublishers  []string
+	SuspiciousFiles    bool
+	Fields             []string
+	UploadCodeScanning bool
+	CodeScanningRepo   string
+	CreateIssues       bool
+	IssuesRepo         string
+	MetricsFile        string
+	MetricsAddr        string
+	Store              string
+	Sample             string
+	Concurrency        int
+	OrgConcurrency     int
+	PublishPerOrg      bool
+	ProtectedBranches  []string
+	WebhookDomains     []string
+	SensitiveOrgs      []string
+	Subgroups          string
+	ActivityDays       int
+	DormantAdminDays   int
+	TelemetryEndpoint  string
+	TelemetryLog       string
+	PerOrgOutputDir    string
+	OtlpEndpoint       string
+	ScanID             string
+	LogLevel           string
+	LogFormat          string
+	LogFile            string
+	// tokenRefresher reads the token again when it expires during the run (set for the token sources that support it)
+	tokenRefresher token_source.Refresher
+	// usedFlags are the names of the options set for the run (reported by the usage telemetry)
+	usedFlags []string
 }
 
 const (
 	ArgErrorFile  = "error-file"
 	ArgOutputFile = "output-file"
 	ArgToken      = "github-token"
+	ArgTokenFile  = "token-file"
+	ArgTokenCmd   = "token-command"
+	ArgVaultPath  = "vault-path"
+	ArgVaultField = "vault-field"
+	ArgVaultAuth  = "vault-auth"
+	ArgVaultMount = "vault-auth-mount"
+	ArgVaultRole  = "vault-role"
 	ArgServerUrl  = "server-url"
 	ScmType       = "scm"
+	ArgOffline    = "offline"
+	ArgCABundle   = "ca-bundle"
+	ArgClientCert = "client-cert"
+	ArgClientKey  = "client-key"
+	ArgProxy      = "proxy"
 )
 
 const (
 	EnvToken     = "github_token"
 	NewEnvToken  = "legitify_token"
 	EnvServerUrl = "server_url"
+	// the standard OpenTelemetry exporter endpoint variable
+	EnvOtlpEndpoint = "otel_exporter_otlp_endpoint"
 )
 
 func (a *args) ApplyEnvVars() {
-	if a.Token == "" {
+	if a.Token == "" && a.TokenFile == "" && a.TokenCommand == "" && a.VaultPath == "" {
 		// backwards compatibility: support both LEGITIFY_TOKEN and GITHUB_TOKEN environment variables.
 		// In the future we'll remove the GITHUB_TOKEN option
 		a.Token = viper.GetString(NewEnvToken)
@@ -50,14 +130,30 @@ func (a *args) ApplyEnvVars() {
 	if a.Endpoint == "" {
 		a.Endpoint = viper.GetString(EnvServerUrl)
 	}
+
+	if a.OtlpEndpoint == "" {
+		a.OtlpEndpoint = viper.GetString(EnvOtlpEndpoint)
+	}
 }
 
 func (a *args) addCommonOptions(flags *pflag.FlagSet) {
 	flags.StringVarP(&a.Token, ArgToken, "t", "", "token to authenticate with github (required unless environment variable LEGITIFY_AUTH_TOKEN is set)")
+	flags.StringVarP(&a.TokenFile, ArgTokenFile, "", "", "read the token from a file (or from stdin, for '-'), instead of passing it as an argument")
+	flags.StringVarP(&a.TokenCommand, ArgTokenCmd, "", "", "read the token from the output of a command, e.g. of a secret manager (\"op read op://vault/legitify/token\")")
+	flags.StringVarP(&a.VaultPath, ArgVaultPath, "", "", "read the token from a HashiCorp Vault secret (e.g. secret/data/legitify), using VAULT_ADDR; it's read again when its lease expires")
+	flags.StringVarP(&a.VaultField, ArgVaultField, "", token_source.DefaultVaultField, "the field of the Vault secret that holds the token")
+	flags.StringVarP(&a.VaultAuth, ArgVaultAuth, "", token_source.VaultAuthToken, "Vault auth method "+toOptionsString(token_source.VaultAuthMethods())+" (VAULT_TOKEN, or VAULT_ROLE_ID & VAULT_SECRET_ID for approle)")
+	flags.StringVarP(&a.VaultAuthMount, ArgVaultMount, "", "", "mount path of the Vault auth method (defaults to the auth method name)")
+	flags.StringVarP(&a.VaultRole, ArgVaultRole, "", "", "the role of the Vault kubernetes auth method")
 	flags.StringVarP(&a.Endpoint, ArgServerUrl, "", "", "github/gitlab endpoint to use instead of the Cloud API (can be set via the environment variable SERVER_URL)")
 	flags.StringVarP(&a.OutputFile, ArgOutputFile, "o", "", "output file, defaults to stdout")
 	flags.StringVarP(&a.ErrorFile, ArgErrorFile, "e", "error.log", "error log path")
-	flags.StringVarP(&a.ScmType, ScmType, "", scm_type.GitHub, "server type (GitHub, GitLab), defaults to GitHub")
+	flags.StringVarP(&a.ScmType, ScmType, "", scm_type.GitHub, "server type (GitHub, GitLab, CodeCommit), defaults to GitHub")
+	flags.BoolVarP(&a.Offline, ArgOffline, "", false, "air-gapped mode: block any outbound request other than to the SCM endpoint (e.g. scorecard dependencies and telemetry)")
+	flags.StringVarP(&a.CABundle, ArgCABundle, "", "", "PEM file of additional certificate authorities to trust (e.g. of a TLS-intercepting proxy)")
+	flags.StringVarP(&a.ClientCert, ArgClientCert, "", "", "PEM file of a client certificate for mutual TLS (requires --client-key)")
+	flags.StringVarP(&a.ClientKey, ArgClientKey, "", "", "PEM file of the client certificate key")
+	flags.StringVarP(&a.Proxy, ArgProxy, "", "", "HTTP(S) proxy url (overrides the HTTPS_PROXY/HTTP_PROXY environment variables)")
 }
 
 func (a *args) validateCommonOptions() error {
@@ -65,5 +161,100 @@ func (a *args) validateCommonOptions() error {
 		return err
 	}
 
+	// configured first, so the token sources use it too
+	err := network.Configure(network.Options{
+		CABundle:   a.CABundle,
+		ClientCert: a.ClientCert,
+		ClientKey:  a.ClientKey,
+		Proxy:      a.Proxy,
+	})
+	if err != nil {
+		return err
+	}
+
+	vaultHost, err := a.resolveToken()
+	if err != nil {
+		return err
+	}
+
+	if a.Offline {
+		// enforced at runtime from here on: the clients are only created after the validation.
+		// the offline mode wraps the configured transport, and allows the Vault requests that refresh the token
+		if err := offline.Enable(a.ScmType, a.Endpoint, vaultHost); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolveToken reads the token from the token file, command or Vault (at most one token source may be set),
+// and returns the Vault host (if the token is read from Vault)
+func (a *args) resolveToken() (string, error) {
+	sources := 0
+	for _, source := range []string{a.Token, a.TokenFile, a.TokenCommand, a.VaultPath} {
+		if source != "" {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return "", fmt.Errorf("only one of --%s, --%s, --%s and --%s can be set", ArgToken, ArgTokenFile, ArgTokenCmd, ArgVaultPath)
+	}
+
+	var err error
+	if a.TokenFile != "" {
+		a.Token, err = token_source.FromFile(a.TokenFile, os.Stdin)
+	} else if a.TokenCommand != "" {
+		a.Token, err = token_source.FromCommand(a.TokenCommand)
+	} else if a.VaultPath != "" {
+		return a.resolveVaultToken()
+	} else if a.Token == "" && a.ScmType == scm_type.GitHub {
+		err = a.resolveStoredToken()
+	}
+	return "", err
+}
+
+// resolveStoredToken reads the token stored by the login command (if there is one)
+func (a *args) resolveStoredToken() error {
+	host, err := login.Host(a.Endpoint)
+	if err != nil {
+		return err
+	}
+	token, err := login.LoadToken(host)
+	if err != nil {
+		// without a stored token, the missing token is reported by the client
+		return nil
+	}
+	if token.Expired() {
+		if err = login.RemoveExpiredToken(context.Background(), a.Endpoint, host, viper.GetString(EnvOAuthAppSecret), *token); err != nil {
+			return fmt.Errorf("the stored token of %s expired and was removed, but it couldn't be revoked: %v", host, err)
+		}
+		return fmt.Errorf("the stored token of %s expired and was removed, run legitify %s again", host, cmdLogin)
+	}
+	a.Token = token.AccessToken
 	return nil
 }
+
+func (a *args) resolveVaultToken() (string, error) {
+	source, err := token_source.NewVaultSource(token_source.VaultOptions{
+		Path:       a.VaultPath,
+		Field:      a.VaultField,
+		AuthMethod: a.VaultAuth,
+		AuthMount:  a.VaultAuthMount,
+		Role:       a.VaultRole,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if a.Token, err = source.Token(); err != nil {
+		return "", err
+	}
+	a.tokenRefresher = source
+
+	parsed, err := url.Parse(source.Address())
+	if err != nil {
+		return "", err
+	}
+	return parsed.Hostname(), nil
+}
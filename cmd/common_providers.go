@@ -4,26 +4,69 @@ import (
 	"context"
 	"fmt"
 	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/Legit-Labs/legitify/internal/common/rotation"
 	"github.com/Legit-Labs/legitify/internal/common/scm_type"
+	"github.com/Legit-Labs/legitify/internal/contacts"
 	"github.com/Legit-Labs/legitify/internal/context_utils"
+	"github.com/Legit-Labs/legitify/internal/identity"
 	"github.com/Legit-Labs/legitify/internal/opa"
 	"github.com/Legit-Labs/legitify/internal/opa/opa_engine"
 	"github.com/Legit-Labs/legitify/internal/outputer"
+	"github.com/Legit-Labs/legitify/internal/outputer/formatter"
+	"github.com/Legit-Labs/legitify/internal/publishers"
+	"github.com/Legit-Labs/legitify/internal/publishers/metrics"
+	storepublisher "github.com/Legit-Labs/legitify/internal/publishers/store"
+	"github.com/Legit-Labs/legitify/internal/publishers/telemetry"
+	"github.com/Legit-Labs/legitify/internal/sampling"
+	"github.com/Legit-Labs/legitify/internal/store"
+	"github.com/Legit-Labs/legitify/internal/webhooks"
+	"github.com/Legit-Labs/legitify/internal/workflows"
 	"log"
 )
 
+// commonPublishers are the publishers supported by all the SCM types
+func commonPublishers(analyzeArgs *args) ([]publishers.Publisher, error) {
+	var result []publishers.Publisher
+	if analyzeArgs.MetricsFile != "" || analyzeArgs.MetricsAddr != "" {
+		result = append(result, metrics.NewMetricsPublisher(analyzeArgs.MetricsFile))
+	}
+	if analyzeArgs.TelemetryEndpoint != "" || analyzeArgs.TelemetryLog != "" {
+		result = append(result, telemetry.NewTelemetryPublisher(analyzeArgs.TelemetryEndpoint, analyzeArgs.TelemetryLog, analyzeArgs.ScmType, analyzeArgs.usedFlags))
+	}
+	if analyzeArgs.Store != "" {
+		// the store is opened before the analysis, so an unusable store fails the command instead of losing the results
+		resultStore, err := store.Open(analyzeArgs.Store)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open the --%s: %v", argStore, err)
+		}
+		result = append(result, storepublisher.NewStorePublisher(resultStore))
+	}
+
+	return result, nil
+}
+
 func provideGenericClient(args *args) (Client, error) {
 	if args.ScmType == scm_type.GitHub {
 		return provideGitHubClient(args)
 	} else if args.ScmType == scm_type.GitLab {
 		return provideGitLabClient(args)
+	} else if args.ScmType == scm_type.CodeCommit {
+		return provideCodeCommitClient(args)
 	} else {
 		return nil, fmt.Errorf("invalid scm type")
 	}
 }
 
-func provideOutputer(ctx context.Context, analyzeArgs *args) outputer.Outputer {
-	return outputer.NewOutputer(ctx, analyzeArgs.OutputFormat, analyzeArgs.OutputScheme, analyzeArgs.FailedOnly)
+func provideOutputer(ctx context.Context, analyzeArgs *args) (outputer.Outputer, error) {
+	var fields *formatter.FieldSelection
+	if len(analyzeArgs.Fields) > 0 {
+		selection, err := formatter.ParseFields(analyzeArgs.Fields)
+		if err != nil {
+			return nil, err
+		}
+		fields = selection
+	}
+	return outputer.NewOutputer(ctx, analyzeArgs.OutputFormat, analyzeArgs.OutputScheme, analyzeArgs.FailedOnly, analyzeArgs.OutputTemplate, analyzeArgs.Compliance, fields), nil
 }
 
 func provideOpa(analyzeArgs *args) (opa_engine.Enginer, error) {
@@ -56,6 +99,54 @@ func provideContext(client Client, logger *log.Logger) (context.Context, error)
 		IsScorecardEnabled(analyzeArgs.ScorecardWhen),
 		IsScorecardVerbose(analyzeArgs.ScorecardWhen))
 
+	ctx = context_utils.NewContextWithPolicyTags(ctx, analyzeArgs.PolicyTags, analyzeArgs.ExcludedTags)
+	ctx = context_utils.NewContextWithSkippedPolicies(ctx, analyzeArgs.SkippedPolicy)
+	ctx = context_utils.NewContextWithScanID(ctx, analyzeArgs.ScanID)
+	ctx = context_utils.NewContextWithMinConfidence(ctx, analyzeArgs.MinConfidence)
+
+	secretMaxAge, err := rotation.ParseMaxAge(analyzeArgs.SecretMaxAge)
+	if err != nil {
+		return nil, err
+	}
+	ctx = context_utils.NewContextWithSecretMaxAge(ctx, secretMaxAge)
+	ctx = context_utils.NewContextWithSuspiciousFiles(ctx, analyzeArgs.SuspiciousFiles)
+	ctx = context_utils.NewContextWithTrustedActionPublishers(ctx, workflows.NormalizePublishers(analyzeArgs.TrustedPublishers))
+	ctx = context_utils.NewContextWithProtectedBranches(ctx, analyzeArgs.ProtectedBranches)
+	ctx = context_utils.NewContextWithWebhookDomains(ctx, webhooks.NormalizeDomains(analyzeArgs.WebhookDomains))
+	ctx = context_utils.NewContextWithHighSensitivityOrgs(ctx, analyzeArgs.SensitiveOrgs)
+	ctx = context_utils.NewContextWithAuditLogActivityDays(ctx, analyzeArgs.ActivityDays)
+	ctx = context_utils.NewContextWithDormantAdminDays(ctx, analyzeArgs.DormantAdminDays)
+	ctx = context_utils.NewContextWithConcurrency(ctx, analyzeArgs.Concurrency, analyzeArgs.OrgConcurrency)
+
+	if analyzeArgs.Sample != "" {
+		percent, err := sampling.ParsePercent(analyzeArgs.Sample)
+		if err != nil {
+			return nil, err
+		}
+		ctx = context_utils.NewContextWithSample(ctx, percent)
+		logger.Printf("Note: this is a sampled scan of %d%% of the repositories; run a full scan for the complete results\n\n", percent)
+	}
+
+	if analyzeArgs.IdentitySource != "" {
+		source, err := identity.Load(analyzeArgs.IdentitySource)
+		if err != nil {
+			return nil, err
+		}
+		ctx = context_utils.NewContextWithIdentitySource(ctx, source)
+	}
+
+	if analyzeArgs.SecurityContacts != "" {
+		directory, err := contacts.Load(analyzeArgs.SecurityContacts)
+		if err != nil {
+			return nil, err
+		}
+		ctx = context_utils.NewContextWithSecurityContacts(ctx, directory)
+	}
+
+	if versioned, ok := client.(VersionedClient); ok {
+		ctx = context_utils.NewContextWithUnsupportedFeatures(ctx, versioned.UnsupportedFeatures())
+	}
+
 	if !IsScorecardEnabled(analyzeArgs.ScorecardWhen) {
 		logger.Printf("Note: to get the OpenSSF scorecard results for the organization repositories use the --scorecard option\n\n")
 	}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const argConfigFile = "config"
+
+// applyConfigFile sets the flags that weren't explicitly passed on the command line from a config file.
+// The config file keys are the flag names, e.g.:
+//
+//	org: [org1, org2]
+//	skip-policy: [repository_not_maintained]
+//	failed-only: true
+//	severity-labels:
+//	  critical: P1
+//	  high: P2
+func applyConfigFile(flags *pflag.FlagSet, path string) error {
+	if path == "" {
+		return nil
+	}
+
+	config := viper.New()
+	config.SetConfigFile(path)
+	if err := config.ReadInConfig(); err != nil {
+		return fmt.Errorf("failed to read config file %s: %v", path, err)
+	}
+
+	for _, key := range config.AllKeys() {
+		flag := flags.Lookup(key)
+		if flag == nil {
+			// map options are flattened into their nested keys (e.g. severity-labels.critical)
+			flag = flags.Lookup(strings.SplitN(key, ".", 2)[0])
+			if flag != nil && !isMapFlag(flag) {
+				flag = nil
+			}
+		}
+		if flag == nil || key == argConfigFile {
+			return fmt.Errorf("unknown option in config file %s: %s", path, key)
+		}
+	}
+
+	var err error
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if err != nil || flag.Changed || !config.IsSet(flag.Name) {
+			return
+		}
+
+		value := config.GetString(flag.Name)
+		if strings.HasSuffix(flag.Value.Type(), "Slice") {
+			value = strings.Join(config.GetStringSlice(flag.Name), ",")
+		} else if isMapFlag(flag) {
+			var pairs []string
+			for k, v := range config.GetStringMapString(flag.Name) {
+				pairs = append(pairs, k+"="+v)
+			}
+			sort.Strings(pairs)
+			value = strings.Join(pairs, ",")
+		}
+
+		if setErr := flags.Set(flag.Name, value); setErr != nil {
+			err = fmt.Errorf("invalid value for %s in config file %s: %v", flag.Name, path, setErr)
+		}
+	})
+
+	return err
+}
+
+func isMapFlag(flag *pflag.Flag) bool {
+	return strings.HasPrefix(flag.Value.Type(), "stringTo")
+}
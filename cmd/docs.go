@@ -75,6 +75,7 @@ type PolicyDoc struct {
 	Severity    string
 	Remediation []string
 	Threat      []string
+	Tags        []string
 }
 
 func newPolicyDoc(policy *ast.Rule, ref *ast.AnnotationsRef) PolicyDoc {
@@ -85,6 +86,7 @@ func newPolicyDoc(policy *ast.Rule, ref *ast.AnnotationsRef) PolicyDoc {
 		Severity:    ref.Annotations.Custom["severity"].(string),
 		Remediation: resolveStringArray(ref.Annotations.Custom["remediationSteps"]),
 		Threat:      resolveStringArray(ref.Annotations.Custom["threat"]),
+		Tags:        resolveStringArray(ref.Annotations.Custom["tags"]),
 	}
 }
 
@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Legit-Labs/legitify/internal/clients/github"
+	glclient "github.com/Legit-Labs/legitify/internal/clients/gitlab"
+	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/Legit-Labs/legitify/internal/common/permissions"
+	"github.com/Legit-Labs/legitify/internal/common/types"
+	"github.com/Legit-Labs/legitify/internal/doctor"
+	"github.com/Legit-Labs/legitify/internal/opa"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	rootCmd.AddCommand(newDoctorCommand())
+}
+
+const (
+	cmdDoctor = "doctor"
+
+	checkConnectivity   = "connectivity"
+	checkToken          = "token"
+	checkGraphQL        = "graphql api"
+	checkRESTRateLimit  = "rest rate limit"
+	checkGraphQLLimit   = "graphql rate limit"
+	checkServerFeatures = "server features"
+	checkOrganizations  = "organizations"
+	checkPolicies       = "policies"
+
+	// lowRateLimitRatio is the remaining share of the rate limit below which a long analysis is likely to wait for the reset
+	lowRateLimitRatio = 0.2
+	// tokenExpiryWarning is how soon before its expiry the token is reported
+	tokenExpiryWarning = 7 * 24 * time.Hour
+)
+
+var doctorArgs args
+
+func newDoctorCommand() *cobra.Command {
+	doctorCmd := &cobra.Command{
+		Use:   cmdDoctor,
+		Short: `Check the token, the connectivity and the rate limits before an analysis`,
+		Long: `Run the preflight checks of an analysis: the connectivity to the API (and to the GraphQL API of GitHub), the token scopes,
+the role in each organization and the remaining rate limit.
+Prints the policies that will be skipped in each organization because of a missing scope, an insufficient role or a feature the
+server doesn't support. Policies of repositories are evaluated with the organization role (owners administer all the repositories,
+members may administer some of them).
+Exits with an error if any of the checks failed.`,
+		RunE:         executeDoctorCommand,
+		SilenceUsage: true,
+	}
+
+	viper.AutomaticEnv()
+	flags := doctorCmd.Flags()
+	doctorArgs.addCommonOptions(flags)
+	flags.StringSliceVarP(&doctorArgs.Organizations, argOrg, "", nil, "specific organizations to check")
+	flags.StringSliceVarP(&doctorArgs.Namespaces, argNamespace, "n", namespace.All, "which namespaces to report the skipped policies of")
+	flags.StringSliceVarP(&doctorArgs.PoliciesPath, argPoliciesPath, "p", []string{}, "directory containing opa policies")
+
+	return doctorCmd
+}
+
+func executeDoctorCommand(cmd *cobra.Command, _args []string) error {
+	doctorArgs.ApplyEnvVars()
+
+	report := doctor.NewReport()
+	if err := doctorArgs.validateCommonOptions(); err != nil {
+		// the token sources are resolved by the validation, so their errors are reported as a failed check
+		report.Add(checkToken, doctor.StatusFailed, "%v", err)
+		report.Print(os.Stdout)
+		return fmt.Errorf("preflight checks failed")
+	}
+	if err := namespace.ValidateNamespaces(doctorArgs.Namespaces); err != nil {
+		return err
+	}
+
+	if err := setErrorFile(doctorArgs.ErrorFile); err != nil {
+		return err
+	}
+	if err := setOutputFile(doctorArgs.OutputFile); err != nil {
+		return err
+	}
+
+	runDoctorChecks(report)
+
+	report.Print(os.Stdout)
+	fmt.Printf("\n%s\n", report.Summary())
+	if report.Failed() {
+		return fmt.Errorf("preflight checks failed")
+	}
+	return nil
+}
+
+func runDoctorChecks(report *doctor.Report) {
+	client, err := provideGenericClient(&doctorArgs)
+	if err != nil {
+		report.Add(checkConnectivity, doctor.StatusFailed, "%v", err)
+		return
+	}
+
+	switch c := client.(type) {
+	case *github.Client:
+		if !checkGitHub(report, c) {
+			return
+		}
+	case *glclient.Client:
+		report.Add(checkConnectivity, doctor.StatusOK, "connected to %s", endpointName(doctorArgs.Endpoint, "gitlab.com"))
+		checkGitLabToken(report, c.TokenInfo())
+	default:
+		report.Add(checkConnectivity, doctor.StatusOK, "connected to %s", doctorArgs.ScmType)
+	}
+
+	orgs, err := client.Organizations()
+	if err != nil {
+		report.Add(checkOrganizations, doctor.StatusFailed, "%v", err)
+		return
+	}
+	checkOrganizationRoles(report, orgs)
+
+	var unsupported []string
+	if versioned, ok := client.(VersionedClient); ok {
+		unsupported = versioned.UnsupportedFeatures()
+	}
+	checkDegradedPolicies(report, orgs, client.Scopes(), unsupported)
+}
+
+// checkGitHub checks the connectivity, the token scopes, the GraphQL API, the rate limits and the server features,
+// and returns false if the API can't be used
+func checkGitHub(report *doctor.Report, client *github.Client) bool {
+	login, err := client.Viewer()
+	if err != nil {
+		report.Add(checkConnectivity, doctor.StatusFailed, "%v", err)
+		return false
+	}
+	if client.IsGithubCloud() {
+		report.Add(checkConnectivity, doctor.StatusOK, "connected to github.com as %s", login)
+	} else {
+		report.Add(checkConnectivity, doctor.StatusOK, "connected to %s (GitHub Enterprise Server %s) as %s",
+			doctorArgs.Endpoint, client.ServerVersion(), login)
+	}
+
+	if missing := permissions.MissingScopes(client.Scopes(), permissions.FullAnalysisScopes); len(missing) > 0 {
+		report.Add(checkToken, doctor.StatusWarning, "missing the scopes %s (required for a full analysis)", strings.Join(missing, ", "))
+	} else {
+		report.Add(checkToken, doctor.StatusOK, "has all the scopes required for a full analysis")
+	}
+
+	graphQLLimit, err := client.GraphQLRateLimit()
+	if err != nil {
+		report.Add(checkGraphQL, doctor.StatusFailed, "unavailable: %v", err)
+	} else {
+		report.Add(checkGraphQL, doctor.StatusOK, "available")
+	}
+
+	restLimit, restErr := client.RESTRateLimit()
+	checkRateLimit(report, checkRESTRateLimit, restLimit, restErr)
+	if err == nil {
+		checkRateLimit(report, checkGraphQLLimit, graphQLLimit, nil)
+	}
+
+	if !client.IsGithubCloud() {
+		if unsupported := client.UnsupportedFeatures(); len(unsupported) > 0 {
+			report.Add(checkServerFeatures, doctor.StatusWarning, "unsupported by the server version: %s", strings.Join(unsupported, ", "))
+		} else {
+			report.Add(checkServerFeatures, doctor.StatusOK, "all the features are supported")
+		}
+	}
+
+	return true
+}
+
+func checkRateLimit(report *doctor.Report, name string, limit *github.RateLimit, err error) {
+	switch {
+	case err != nil:
+		report.Add(name, doctor.StatusWarning, "failed to read the rate limit: %v", err)
+	case limit == nil:
+		report.Add(name, doctor.StatusOK, "not limited by the server")
+	case float64(limit.Remaining) < lowRateLimitRatio*float64(limit.Limit):
+		report.Add(name, doctor.StatusWarning, "%d/%d requests remaining until %s, a large analysis may wait for the reset",
+			limit.Remaining, limit.Limit, limit.Reset.Format(time.Kitchen))
+	default:
+		report.Add(name, doctor.StatusOK, "%d/%d requests remaining until %s", limit.Remaining, limit.Limit, limit.Reset.Format(time.Kitchen))
+	}
+}
+
+func checkGitLabToken(report *doctor.Report, info *glclient.TokenInfo) {
+	message := info.Type
+	if info.Scopes != nil {
+		message += fmt.Sprintf(" with the scopes %s", strings.Join(info.Scopes, ", "))
+	}
+	if info.ExpiresAt != nil {
+		message += fmt.Sprintf(", expires on %s", info.ExpiresAt.Format("2006-01-02"))
+	}
+
+	switch {
+	case info.ExpiresAt != nil && time.Until(*info.ExpiresAt) < tokenExpiryWarning:
+		report.Add(checkToken, doctor.StatusWarning, "%s (expires soon)", message)
+	case info.Scopes == nil:
+		report.Add(checkToken, doctor.StatusWarning, "%s (the scopes can't be read, make sure it has %s)", message, strings.Join(glclient.RequiredScopes, ", "))
+	case info.Type == glclient.TokenTypeProject:
+		report.Add(checkToken, doctor.StatusWarning, "%s (only the repository namespace can be analyzed)", message)
+	default:
+		report.Add(checkToken, doctor.StatusOK, "%s", message)
+	}
+}
+
+func checkOrganizationRoles(report *doctor.Report, orgs []types.Organization) {
+	if len(orgs) == 0 {
+		report.Add(checkOrganizations, doctor.StatusWarning, "no organizations are associated with the token")
+		return
+	}
+
+	owner, member := groupByMembership(orgs)
+	if len(member) > 0 {
+		var names []string
+		for _, org := range member {
+			names = append(names, org.Name)
+		}
+		report.Add(checkOrganizations, doctor.StatusWarning, "owner of %d, member of %d (partial analysis of %s)",
+			len(owner), len(member), strings.Join(names, ", "))
+	} else {
+		report.Add(checkOrganizations, doctor.StatusOK, "owner of all %d organizations", len(owner))
+	}
+}
+
+func checkDegradedPolicies(report *doctor.Report, orgs []types.Organization, scopes permissions.TokenScopes, unsupported []string) {
+	engine, err := opa.Load(doctorArgs.PoliciesPath, doctorArgs.ScmType)
+	if err != nil {
+		report.Add(checkPolicies, doctor.StatusFailed, "%v", err)
+		return
+	}
+	policies := doctor.PoliciesFromAnnotations(engine.Annotations().Flatten(), doctorArgs.Namespaces)
+
+	// the unsupported features are prerequisites of the policies that depend on them
+	unavailable := make(map[string]bool)
+	for _, feature := range unsupported {
+		unavailable[feature] = true
+	}
+
+	skipped := 0
+	for _, org := range orgs {
+		roles := []permissions.Role{org.Role}
+		if org.Role == permissions.OrgRoleOwner {
+			roles = append(roles, permissions.RepoRoleAdmin)
+		}
+		degraded := doctor.DegradedPolicies(policies, roles, scopes, unavailable)
+		skipped += len(degraded)
+		report.AddDegraded(fmt.Sprintf("%s (%s)", org.Name, org.Role), degraded)
+	}
+
+	if skipped > 0 {
+		report.Add(checkPolicies, doctor.StatusWarning, "%d policies, some will be skipped (see below)", len(policies))
+	} else {
+		report.Add(checkPolicies, doctor.StatusOK, "%d policies, none will be skipped", len(policies))
+	}
+}
+
+func endpointName(endpoint string, cloud string) string {
+	if endpoint == "" {
+		return cloud
+	}
+	return endpoint
+}
@@ -0,0 +1,266 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Legit-Labs/legitify/internal/analyzers/skippers"
+	"github.com/Legit-Labs/legitify/internal/collectors"
+	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/Legit-Labs/legitify/internal/common/scm_type"
+	"github.com/Legit-Labs/legitify/internal/context_utils"
+	"github.com/Legit-Labs/legitify/internal/opa"
+	"github.com/Legit-Labs/legitify/internal/opa/opa_engine"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	rootCmd.AddCommand(newExplainCommand())
+}
+
+const (
+	cmdExplain = "explain"
+	argEntity  = "entity"
+)
+
+var (
+	explainArgs   args
+	explainEntity string
+)
+
+func newExplainCommand() *cobra.Command {
+	explainCmd := &cobra.Command{
+		Use:   cmdExplain + " <policy>",
+		Short: `Explain why a policy did (or didn't) fire for a single entity`,
+		Long: `Collect a single entity and re-evaluate a single policy against it, printing the collected fields used by the policy
+and a trace of the policy evaluation.
+The policy is either the policy name or <namespace>.<policy name> (e.g. repository.code_review_not_required).
+The entity is the organization (e.g. --entity org1), or <organization>/<name> for repositories and runner groups (e.g. --entity org1/repo1).
+Currently supported for GitHub only.`,
+		Args:         cobra.ExactArgs(1),
+		RunE:         executeExplainCommand,
+		SilenceUsage: true,
+	}
+
+	viper.AutomaticEnv()
+	flags := explainCmd.Flags()
+	explainArgs.addCommonOptions(flags)
+	flags.StringVarP(&explainEntity, argEntity, "", "", "the entity to evaluate the policy against (<org> or <org>/<name>)")
+	flags.StringSliceVarP(&explainArgs.PoliciesPath, argPoliciesPath, "p", []string{}, "directory containing opa policies")
+	_ = explainCmd.MarkFlagRequired(argEntity)
+
+	return explainCmd
+}
+
+func executeExplainCommand(cmd *cobra.Command, cmdArgs []string) error {
+	explainArgs.ApplyEnvVars()
+
+	if err := explainArgs.validateCommonOptions(); err != nil {
+		return err
+	}
+	if explainArgs.ScmType != scm_type.GitHub {
+		return fmt.Errorf("%s is currently supported for GitHub only", cmdExplain)
+	}
+
+	if err := setErrorFile(explainArgs.ErrorFile); err != nil {
+		return err
+	}
+	if err := setOutputFile(explainArgs.OutputFile); err != nil {
+		return err
+	}
+
+	engine, err := opa.Load(explainArgs.PoliciesPath, explainArgs.ScmType)
+	if err != nil {
+		return err
+	}
+
+	ns, policyName, err := resolvePolicy(engine, cmdArgs[0])
+	if err != nil {
+		return err
+	}
+
+	org, name, err := parseEntity(ns, explainEntity)
+	if err != nil {
+		return err
+	}
+
+	ctx, data, err := collectEntity(ns, org, name)
+	if err != nil {
+		return err
+	}
+
+	explanation, err := engine.Explain(ctx, ns, policyName, data.Entity)
+	if err != nil {
+		return err
+	}
+
+	return printExplanation(ctx, data, explanation)
+}
+
+// resolvePolicy finds the namespace of the policy (unless it's already qualified)
+func resolvePolicy(engine opa_engine.Enginer, policy string) (namespace.Namespace, string, error) {
+	var matches []string
+	for _, annotation := range engine.Annotations().Flatten() {
+		qualified := strings.TrimPrefix(annotation.Path.String(), "data.")
+		parts := strings.Split(qualified, ".")
+		if len(parts) != 2 {
+			continue
+		}
+		if qualified == policy || parts[1] == policy {
+			matches = append(matches, qualified)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", "", fmt.Errorf("unknown policy %s", policy)
+	case 1:
+		parts := strings.Split(matches[0], ".")
+		if err := namespace.ValidateNamespaces([]namespace.Namespace{parts[0]}); err != nil {
+			return "", "", err
+		}
+		return parts[0], parts[1], nil
+	default:
+		sort.Strings(matches)
+		return "", "", fmt.Errorf("policy %s is ambiguous, use one of: %s", policy, strings.Join(matches, ", "))
+	}
+}
+
+// parseEntity splits the entity into the organization and the name of the entity in it
+func parseEntity(ns namespace.Namespace, entity string) (org string, name string, err error) {
+	parts := strings.Split(entity, "/")
+	switch {
+	case len(parts) == 1 && ns != namespace.Repository && ns != namespace.RunnerGroup:
+		return parts[0], parts[0], nil
+	case len(parts) == 2 && (ns == namespace.Repository || ns == namespace.RunnerGroup):
+		return parts[0], parts[1], nil
+	case ns == namespace.Repository || ns == namespace.RunnerGroup:
+		return "", "", fmt.Errorf("invalid entity %s for a %s policy, expected \"<org>/<name>\"", entity, ns)
+	default:
+		return "", "", fmt.Errorf("invalid entity %s for a %s policy, expected \"<org>\"", entity, ns)
+	}
+}
+
+// collectEntity runs the namespace collector on the organization (or repository) and returns the requested entity
+func collectEntity(ns namespace.Namespace, org string, name string) (context.Context, *collectors.CollectedData, error) {
+	var ctx context.Context
+	if ns == namespace.Repository {
+		repos, err := validateRepositories([]string{org + "/" + name})
+		if err != nil {
+			return nil, nil, err
+		}
+		ctx = context_utils.NewContextWithRepos(repos)
+	} else {
+		explainArgs.Organizations = []string{org}
+		ctx = context_utils.NewContextWithOrg(explainArgs.Organizations)
+	}
+
+	client, err := provideGitHubClient(&explainArgs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx = context_utils.NewContextWithScorecard(ctx, false, false)
+	ctx = context_utils.NewContextWithTokenScopes(ctx, client.Scopes())
+
+	collector := provideGitHubCollectors(ctx, client, &args{Namespaces: []namespace.Namespace{ns}})[0]
+	channels := collector.Collect()
+	go func() {
+		for range channels.Progress {
+		}
+	}()
+	go func() {
+		for missing := range channels.MissingPermission {
+			fmt.Printf("Note: %s (missing the %s permission for %s)\n", missing.Effect, missing.Permission, missing.Entity)
+		}
+	}()
+
+	var found *collectors.CollectedData
+	for data := range channels.Collected {
+		if found == nil && strings.EqualFold(data.Entity.Name(), name) {
+			data := data
+			found = &data
+		}
+	}
+
+	if found == nil {
+		return nil, nil, fmt.Errorf("%s entity %s was not found (or can't be accessed with the given token)", ns, explainEntity)
+	}
+
+	return ctx, found, nil
+}
+
+func printExplanation(ctx context.Context, data *collectors.CollectedData, explanation *opa_engine.Explanation) error {
+	title := ""
+	if explanation.Annotations != nil {
+		title = fmt.Sprintf(" (%s)", explanation.Annotations.Title)
+	}
+	fmt.Printf("\nPolicy: %s%s\n", strings.TrimPrefix(explanation.FullyQualifiedPolicyName, "data."), title)
+	fmt.Printf("Entity: %s (%s)\n", explainEntity, data.CanonicalLink)
+	fmt.Printf("Result: %s\n", explanationStatus(ctx, data, explanation))
+
+	if explanation.IsViolation {
+		if extra, ok := explanation.Result.(map[string]interface{}); ok {
+			fmt.Printf("\nViolations:\n")
+			if err := printJson(extra); err != nil {
+				return err
+			}
+		}
+	}
+
+	fmt.Printf("\nCollected fields used by the policy:\n")
+	if len(explanation.InputFields) == 0 {
+		fmt.Printf("  (none)\n")
+	}
+	for _, field := range explanation.InputFields {
+		if !field.Defined {
+			fmt.Printf("  %s: <undefined>\n", field.Path)
+			continue
+		}
+		value, err := json.Marshal(field.Value)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("  %s: %s\n", field.Path, value)
+	}
+
+	fmt.Printf("\nEvaluation trace:\n%s", explanation.Trace)
+	return nil
+}
+
+func explanationStatus(ctx context.Context, data *collectors.CollectedData, explanation *opa_engine.Explanation) string {
+	if explanation.Annotations != nil {
+		result := opa_engine.QueryResult{
+			PolicyName:               explanation.FullyQualifiedPolicyName[strings.LastIndex(explanation.FullyQualifiedPolicyName, ".")+1:],
+			FullyQualifiedPolicyName: explanation.FullyQualifiedPolicyName,
+			Annotations:              explanation.Annotations,
+			ExtraData:                explanation.Result,
+			IsViolation:              explanation.IsViolation,
+		}
+		if skippers.NewSkipper(ctx).ShouldSkip(*data, result) {
+			return "SKIPPED (missing scopes or prerequisites; the evaluation below is for reference only)"
+		}
+	}
+
+	switch {
+	case explanation.Result == nil:
+		return "PASSED (the policy rule is undefined for this entity)"
+	case explanation.IsViolation:
+		return "FAILED"
+	default:
+		return "PASSED"
+	}
+}
+
+func printJson(value interface{}) error {
+	data, err := json.MarshalIndent(value, "  ", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Printf("  %s\n", data)
+	return nil
+}
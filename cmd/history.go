@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Legit-Labs/legitify/internal/common/scm_type"
+	"github.com/Legit-Labs/legitify/internal/history"
+	"github.com/Legit-Labs/legitify/internal/store"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	rootCmd.AddCommand(newHistoryCommand())
+}
+
+const (
+	cmdHistory      = "history"
+	argAt           = "at"
+	argSnapshotDate = "snapshot-date"
+	argPolicy       = "policy"
+	historyDate     = "2006-01-02"
+)
+
+var (
+	historyArgs         args
+	historyInputFile    string
+	historyStore        string
+	historyAt           string
+	historySnapshotDate string
+	historyPolicies     []string
+)
+
+func newHistoryCommand() *cobra.Command {
+	historyCmd := &cobra.Command{
+		Use:   cmdHistory,
+		Short: `[experimental] Reconstruct the approximate posture at a past date from a previous analysis and the audit log`,
+		Long: `Reconstruct the approximate status of the policies at a past date (e.g. "was branch protection enabled on March 1st?"),
+by undoing the organization audit log events that happened since then on top of the results of a previous analysis
+(json format, flattened scheme), given as a file or read from the store the analysis saved its results in (the latest results).
+Only some policies can be reconstructed (e.g. branch protection, vulnerability alerts, secret scanning, 2FA requirement and SAML SSO),
+the status of the rest is assumed unchanged (reported with reconstructed: false).
+The audit log is available for organizations that are part of an enterprise only, and is retained for a limited period.
+Currently supported for GitHub only.`,
+		RunE:         executeHistoryCommand,
+		SilenceUsage: true,
+	}
+
+	viper.AutomaticEnv()
+	flags := historyCmd.Flags()
+	historyArgs.addCommonOptions(flags)
+	flags.StringVarP(&historyInputFile, argPlanInputFile, "i", "", "results of a previous analysis (json format, flattened scheme)")
+	flags.StringVarP(&historyStore, argStore, "", "", "read the latest results from the store of a previous analysis (analyze --"+argStore+") instead of a file")
+	flags.StringVarP(&historyAt, argAt, "", "", "the date to reconstruct the posture at (YYYY-MM-DD)")
+	flags.StringVarP(&historySnapshotDate, argSnapshotDate, "", "", "the date of the previous analysis (YYYY-MM-DD), defaults to the modification time of the input file")
+	flags.StringSliceVarP(&historyArgs.Organizations, argOrg, "", nil, "organizations to read the audit log of")
+	flags.StringSliceVarP(&historyPolicies, argPolicy, "", nil, "policies to reconstruct (all by default), either the policy name or <namespace>.<policy name>")
+	_ = historyCmd.MarkFlagRequired(argAt)
+	_ = historyCmd.MarkFlagRequired(argOrg)
+
+	return historyCmd
+}
+
+func executeHistoryCommand(cmd *cobra.Command, _args []string) error {
+	historyArgs.ApplyEnvVars()
+
+	if err := historyArgs.validateCommonOptions(); err != nil {
+		return err
+	}
+	if historyArgs.ScmType != scm_type.GitHub {
+		return fmt.Errorf("%s is currently supported for GitHub only", cmdHistory)
+	}
+
+	at, err := time.Parse(historyDate, historyAt)
+	if err != nil {
+		return fmt.Errorf("invalid --%s date: %v", argAt, err)
+	}
+
+	input, snapshotTime, err := readSnapshot()
+	if err != nil {
+		return err
+	}
+	if !at.Before(snapshotTime) {
+		return fmt.Errorf("--%s (%s) must be before the date of the previous analysis (%s)", argAt, historyAt, snapshotTime.Format(historyDate))
+	}
+
+	snapshot, err := history.SnapshotFromFlattenedJson(input)
+	if err != nil {
+		return err
+	}
+
+	if err = setErrorFile(historyArgs.ErrorFile); err != nil {
+		return err
+	}
+	if err = setOutputFile(historyArgs.OutputFile); err != nil {
+		return err
+	}
+
+	client, err := provideGitHubClient(&historyArgs)
+	if err != nil {
+		return err
+	}
+
+	var events []history.Event
+	for _, org := range historyArgs.Organizations {
+		orgEvents, err := history.FetchEvents(context.Background(), client.Client(), org, at, snapshotTime)
+		if err != nil {
+			return err
+		}
+		events = append(events, orgEvents...)
+	}
+
+	report := history.Reconstruct(snapshot, snapshotTime, at, events, historyPolicies)
+	data, err := yaml.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+// readSnapshot reads the results of the previous analysis, and the time of the analysis, from the input file or the store
+func readSnapshot() ([]byte, time.Time, error) {
+	if (historyInputFile == "") == (historyStore == "") {
+		return nil, time.Time{}, fmt.Errorf("exactly one of --%s and --%s is required", argPlanInputFile, argStore)
+	}
+
+	if historyStore != "" {
+		resultStore, err := store.Open(historyStore)
+		if err != nil {
+			return nil, time.Time{}, fmt.Errorf("invalid --%s: %v", argStore, err)
+		}
+		input, at, err := resultStore.Latest()
+		if err != nil {
+			return nil, time.Time{}, fmt.Errorf("failed to read the results from %s: %v", resultStore.Name(), err)
+		}
+		if historySnapshotDate != "" {
+			at, err = resolveSnapshotTime("")
+		}
+		return input, at, err
+	}
+
+	input, err := os.ReadFile(historyInputFile)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to read %s: %v", historyInputFile, err)
+	}
+	snapshotTime, err := resolveSnapshotTime(historyInputFile)
+	return input, snapshotTime, err
+}
+
+func resolveSnapshotTime(inputFile string) (time.Time, error) {
+	if historySnapshotDate != "" {
+		snapshotTime, err := time.Parse(historyDate, historySnapshotDate)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid --%s date: %v", argSnapshotDate, err)
+		}
+		return snapshotTime, nil
+	}
+
+	info, err := os.Stat(inputFile)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
@@ -0,0 +1,308 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	glclient "github.com/Legit-Labs/legitify/internal/clients/gitlab"
+	"github.com/Legit-Labs/legitify/internal/common/permissions"
+	"github.com/Legit-Labs/legitify/internal/common/scm_type"
+	"github.com/Legit-Labs/legitify/internal/common/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	rootCmd.AddCommand(newInitCommand())
+}
+
+const (
+	cmdInit           = "init"
+	argForce          = "force"
+	defaultConfigFile = "legitify.yaml"
+)
+
+func newInitCommand() *cobra.Command {
+	initCmd := &cobra.Command{
+		Use:   cmdInit,
+		Short: `Interactively create a starter config file for the analyze command`,
+		Long: `Walk through the SCM type, the token creation and its scopes, and the organizations to analyze,
+and write a config file that can be used with: legitify analyze --config <file>.
+The token itself is not written to the config file.`,
+		RunE:         executeInitCommand,
+		SilenceUsage: true,
+	}
+
+	viper.AutomaticEnv()
+	flags := initCmd.Flags()
+	flags.StringP(argConfigFile, "c", defaultConfigFile, "path of the config file to create")
+	flags.Bool(argForce, false, "overwrite the config file if it already exists")
+
+	return initCmd
+}
+
+func executeInitCommand(cmd *cobra.Command, _args []string) error {
+	flags := cmd.Flags()
+	path, err := flags.GetString(argConfigFile)
+	if err != nil {
+		return err
+	}
+	force, err := flags.GetBool(argForce)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(path); err == nil && !force {
+		return fmt.Errorf("%s already exists (use --%s to overwrite it)", path, argForce)
+	}
+
+	wizard := newInitWizard(os.Stdin, os.Stdout)
+	config, err := wizard.run()
+	if err != nil {
+		return err
+	}
+
+	if err = writeInitConfig(path, config); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nConfig file written to %s\n", path)
+	fmt.Printf("Run the analysis with:\n  LEGITIFY_TOKEN=<your_token> legitify analyze --config %s\n", path)
+	return nil
+}
+
+// initWizard asks the questions needed to create a starter config file
+type initWizard struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func newInitWizard(in io.Reader, out io.Writer) *initWizard {
+	return &initWizard{
+		in:  bufio.NewReader(in),
+		out: out,
+	}
+}
+
+func (w *initWizard) run() (map[string]interface{}, error) {
+	config := make(map[string]interface{})
+
+	// CodeCommit is authenticated with the AWS credentials of the environment rather than a token
+	scmType, err := w.askChoice("Which SCM do you want to analyze?", []scm_type.ScmType{scm_type.GitHub, scm_type.GitLab}, scm_type.GitHub)
+	if err != nil {
+		return nil, err
+	}
+	config[ScmType] = scmType
+
+	endpoint, err := w.ask("Server URL (leave empty for the cloud service)", viper.GetString(EnvServerUrl))
+	if err != nil {
+		return nil, err
+	}
+	if endpoint != "" {
+		config[ArgServerUrl] = endpoint
+	}
+
+	w.printTokenGuidance(scmType, endpoint)
+	token, err := w.token()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := provideGenericClient(&args{
+		Token:    token,
+		Endpoint: endpoint,
+		ScmType:  scmType,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect with the given token: %v", err)
+	}
+
+	w.verifyScopes(scmType, client.Scopes())
+
+	orgs, err := client.Organizations()
+	if err != nil {
+		return nil, err
+	}
+	selected, err := w.selectOrganizations(orgs)
+	if err != nil {
+		return nil, err
+	}
+	if len(selected) > 0 {
+		config[argOrg] = selected
+	}
+
+	return config, nil
+}
+
+func (w *initWizard) printTokenGuidance(scmType scm_type.ScmType, endpoint string) {
+	if endpoint == "" {
+		if scmType == scm_type.GitLab {
+			endpoint = "https://gitlab.com"
+		} else {
+			endpoint = "https://github.com"
+		}
+	}
+	endpoint = strings.TrimSuffix(endpoint, "/")
+
+	fmt.Fprintln(w.out)
+	if scmType == scm_type.GitLab {
+		fmt.Fprintf(w.out, "Create a personal access token with the '%s' scope at:\n  %s/-/profile/personal_access_tokens\n",
+			strings.Join(glclient.RequiredScopes, ", "), endpoint)
+		fmt.Fprintln(w.out, "(a group access token with the Owner role, or an OAuth token with the same scope, works as well)")
+	} else {
+		fmt.Fprintf(w.out, "Create a personal access token (classic) at:\n  %s/settings/tokens/new\n", endpoint)
+		fmt.Fprintf(w.out, "with the following scopes for a full analysis:\n  %s\n", strings.Join(permissions.FullAnalysisScopes, ", "))
+	}
+	fmt.Fprintln(w.out)
+}
+
+// token uses the token from the environment if it's set, and asks for it (without echoing it) otherwise
+func (w *initWizard) token() (string, error) {
+	for _, env := range []string{NewEnvToken, EnvToken} {
+		if token := viper.GetString(env); token != "" {
+			fmt.Fprintf(w.out, "Using the token from the %s environment variable\n", strings.ToUpper(env))
+			return token, nil
+		}
+	}
+
+	fmt.Fprint(w.out, "Token: ")
+	var token string
+	if stdin := int(os.Stdin.Fd()); term.IsTerminal(stdin) {
+		raw, err := term.ReadPassword(stdin)
+		fmt.Fprintln(w.out)
+		if err != nil {
+			return "", err
+		}
+		token = string(raw)
+	} else {
+		line, err := w.readLine()
+		if err != nil {
+			return "", err
+		}
+		token = line
+	}
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", fmt.Errorf("a token is required")
+	}
+
+	return token, nil
+}
+
+func (w *initWizard) verifyScopes(scmType scm_type.ScmType, scopes permissions.TokenScopes) {
+	if scmType != scm_type.GitHub {
+		return
+	}
+
+	missing := permissions.MissingScopes(scopes, permissions.FullAnalysisScopes)
+	if len(missing) == 0 {
+		fmt.Fprintf(w.out, "The token has all the scopes needed for a full analysis\n\n")
+		return
+	}
+
+	fmt.Fprintf(w.out, "Warning: the token is missing the following scopes: %s\n", strings.Join(missing, ", "))
+	fmt.Fprintf(w.out, "Policies that require these scopes will be skipped\n\n")
+}
+
+func (w *initWizard) selectOrganizations(orgs []types.Organization) ([]string, error) {
+	if len(orgs) == 0 {
+		fmt.Fprintf(w.out, "No organizations are associated with this token, all the accessible repositories will be analyzed\n")
+		return nil, nil
+	}
+
+	fmt.Fprintln(w.out, "Organizations:")
+	for i, org := range orgs {
+		fmt.Fprintf(w.out, "  %d. %s (%s)\n", i+1, org.Name, org.Role)
+	}
+
+	for {
+		answer, err := w.ask("Organizations to analyze (comma separated numbers, empty for all)", "")
+		if err != nil {
+			return nil, err
+		}
+		if answer == "" {
+			return nil, nil
+		}
+
+		selected, err := parseSelection(answer, orgs)
+		if err == nil {
+			return selected, nil
+		}
+		fmt.Fprintln(w.out, err)
+	}
+}
+
+func parseSelection(answer string, orgs []types.Organization) ([]string, error) {
+	var selected []string
+	for _, field := range strings.Split(answer, ",") {
+		index, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || index < 1 || index > len(orgs) {
+			return nil, fmt.Errorf("invalid selection: %s", strings.TrimSpace(field))
+		}
+		selected = append(selected, orgs[index-1].Name)
+	}
+
+	return selected, nil
+}
+
+func (w *initWizard) askChoice(question string, options []string, defaultValue string) (string, error) {
+	for {
+		answer, err := w.ask(fmt.Sprintf("%s %s", question, toOptionsString(options)), defaultValue)
+		if err != nil {
+			return "", err
+		}
+		answer = strings.ToLower(answer)
+		for _, option := range options {
+			if answer == option {
+				return answer, nil
+			}
+		}
+		fmt.Fprintf(w.out, "invalid choice: %s\n", answer)
+	}
+}
+
+func (w *initWizard) ask(question string, defaultValue string) (string, error) {
+	if defaultValue != "" {
+		fmt.Fprintf(w.out, "%s [%s]: ", question, defaultValue)
+	} else {
+		fmt.Fprintf(w.out, "%s: ", question)
+	}
+
+	answer, err := w.readLine()
+	if err != nil {
+		return "", err
+	}
+	if answer == "" {
+		return defaultValue, nil
+	}
+
+	return answer, nil
+}
+
+func (w *initWizard) readLine() (string, error) {
+	line, err := w.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", fmt.Errorf("failed to read the answer: %v", err)
+	}
+
+	return strings.TrimSpace(line), nil
+}
+
+func writeInitConfig(path string, config map[string]interface{}) error {
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	const header = "# legitify analyze options (keyed by the option names), created by 'legitify init'\n" +
+		"# the token is not stored here: set the LEGITIFY_TOKEN environment variable\n"
+
+	return os.WriteFile(path, append([]byte(header), data...), 0600)
+}
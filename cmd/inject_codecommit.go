@@ -0,0 +1,51 @@
+//go:build wireinject
+// +build wireinject
+
+package cmd
+
+import (
+	"context"
+	ccclient "github.com/Legit-Labs/legitify/internal/clients/codecommit"
+	"github.com/Legit-Labs/legitify/internal/collectors"
+	"github.com/Legit-Labs/legitify/internal/collectors/codecommit"
+	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/Legit-Labs/legitify/internal/publishers"
+	"github.com/google/wire"
+	"log"
+)
+
+func setupCodeCommit(analyzeArgs *args, log *log.Logger) (*analyzeExecutor, error) {
+	wire.Build(
+		wire.Bind(new(Client), new(*ccclient.Client)),
+		analyzeProviderSet,
+		provideCodeCommitClient,
+		provideCodeCommitCollectors,
+		provideCodeCommitPublishers,
+	)
+	return nil, nil
+}
+
+func provideCodeCommitPublishers(analyzeArgs *args) ([]publishers.Publisher, error) {
+	return commonPublishers(analyzeArgs)
+}
+
+func provideCodeCommitCollectors(ctx context.Context, client *ccclient.Client, analyzeArgs *args) []collectors.Collector {
+	var collectorsMapping = map[namespace.Namespace]func(ctx context.Context, client *ccclient.Client) collectors.Collector{
+		namespace.Repository: codecommit.NewRepositoryCollector,
+	}
+
+	var result []collectors.Collector
+	for _, ns := range analyzeArgs.Namespaces {
+		if creator, ok := collectorsMapping[ns]; ok {
+			result = append(result, creator(ctx, client))
+		}
+	}
+
+	return result
+}
+
+// provideCodeCommitClient creates a client authenticated with the AWS credentials of the environment (the token isn't used);
+// the organizations are the AWS account IDs
+func provideCodeCommitClient(analyzeArgs *args) (*ccclient.Client, error) {
+	return ccclient.NewClient(context.Background(), analyzeArgs.Endpoint, analyzeArgs.Organizations)
+}
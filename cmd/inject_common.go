@@ -14,6 +14,7 @@ import (
 var analyzeProviderSet = wire.NewSet(
 	provideOpa,
 	provideOutputer,
+	provideOrganizationPublishing,
 	provideContext,
 	analyzers.NewAnalyzer,
 	skippers.NewSkipper,
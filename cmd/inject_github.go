@@ -9,6 +9,8 @@ import (
 	"github.com/Legit-Labs/legitify/internal/collectors"
 	github2 "github.com/Legit-Labs/legitify/internal/collectors/github"
 	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/Legit-Labs/legitify/internal/publishers"
+	github3 "github.com/Legit-Labs/legitify/internal/publishers/github"
 	"github.com/google/wire"
 	"log"
 )
@@ -19,6 +21,7 @@ func setupGitHub(analyzeArgs *args, log *log.Logger) (*analyzeExecutor, error) {
 		analyzeProviderSet,
 		provideGitHubClient,
 		provideGitHubCollectors,
+		provideGitHubPublishers,
 	)
 	return nil, nil
 }
@@ -41,7 +44,28 @@ func provideGitHubCollectors(ctx context.Context, client *github.Client, analyze
 	return result
 }
 
+func provideGitHubPublishers(ctx context.Context, client *github.Client, analyzeArgs *args) ([]publishers.Publisher, error) {
+	result, err := commonPublishers(analyzeArgs)
+	if err != nil {
+		return nil, err
+	}
+	if analyzeArgs.CreateCheckRuns {
+		result = append(result, github3.NewChecksPublisher(ctx, client))
+	}
+	if analyzeArgs.UploadCodeScanning {
+		result = append(result, github3.NewCodeScanningPublisher(ctx, client, analyzeArgs.CodeScanningRepo))
+	}
+	if analyzeArgs.CreateIssues {
+		result = append(result, github3.NewIssuesPublisher(ctx, client, analyzeArgs.IssuesRepo))
+	}
+	if analyzeArgs.GhasMatrixFile != "" {
+		result = append(result, github3.NewGhasMatrixPublisher(analyzeArgs.GhasMatrixFile))
+	}
+
+	return result, nil
+}
+
 func provideGitHubClient(analyzeArgs *args) (*github.Client, error) {
 	return github.NewClient(context.Background(), analyzeArgs.Token, analyzeArgs.Endpoint,
-		analyzeArgs.Organizations, false)
+		analyzeArgs.Organizations, false, analyzeArgs.tokenRefresher)
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/Legit-Labs/legitify/internal/collectors"
 	"github.com/Legit-Labs/legitify/internal/collectors/gitlab"
 	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/Legit-Labs/legitify/internal/publishers"
 	"github.com/google/wire"
 	"log"
 )
@@ -19,13 +20,19 @@ func setupGitLab(analyzeArgs *args, log *log.Logger) (*analyzeExecutor, error) {
 		analyzeProviderSet,
 		provideGitLabClient,
 		provideGitLabCollectors,
+		provideGitLabPublishers,
 	)
 	return nil, nil
 }
 
+func provideGitLabPublishers(analyzeArgs *args) ([]publishers.Publisher, error) {
+	return commonPublishers(analyzeArgs)
+}
+
 func provideGitLabCollectors(ctx context.Context, client *glclient.Client, analyzeArgs *args) []collectors.Collector {
 	var collectorsMapping = map[namespace.Namespace]func(ctx context.Context, client *glclient.Client) collectors.Collector{
 		namespace.Organization: gitlab.NewGroupCollector,
+		namespace.Repository:   gitlab.NewProjectCollector,
 	}
 
 	var result []collectors.Collector
@@ -39,5 +46,5 @@ func provideGitLabCollectors(ctx context.Context, client *glclient.Client, analy
 }
 
 func provideGitLabClient(analyzeArgs *args) (*glclient.Client, error) {
-	return glclient.NewClient(context.Background(), analyzeArgs.Token, analyzeArgs.Endpoint, analyzeArgs.Organizations, false)
+	return glclient.NewClient(context.Background(), analyzeArgs.Token, analyzeArgs.Endpoint, analyzeArgs.Organizations, analyzeArgs.Subgroups, false, analyzeArgs.tokenRefresher)
 }
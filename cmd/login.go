@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Legit-Labs/legitify/internal/login"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	rootCmd.AddCommand(newLoginCommand())
+}
+
+const (
+	cmdLogin        = "login"
+	argClientID     = "client-id"
+	argScopes       = "scopes"
+	argPrivateRepos = "private-repos"
+	argLifetime     = "lifetime"
+	argLogout       = "logout"
+	EnvOAuthAppID   = "legitify_oauth_client_id"
+	// EnvOAuthAppSecret is the client secret of the OAuth app, to revoke its tokens (it's not stored)
+	EnvOAuthAppSecret = "legitify_oauth_client_secret"
+)
+
+var loginArgs struct {
+	Endpoint     string
+	ClientID     string
+	Scopes       []string
+	PrivateRepos bool
+	Lifetime     time.Duration
+	Logout       bool
+}
+
+func newLoginCommand() *cobra.Command {
+	loginCmd := &cobra.Command{
+		Use:   cmdLogin,
+		Short: `Log in to GitHub with the OAuth device flow and store the token in the OS keychain`,
+		Long: `Authorize legitify in the browser (with GitHub's device authorization flow) instead of creating a personal access token.
+The token is stored in the OS keychain (macOS keychain, or the Secret Service on Linux; Windows isn't supported) and is used by
+the other commands when no other token is given. It requires a GitHub App (preferably with user token expiration, so GitHub expires
+its tokens) or an OAuth app with the device flow enabled.
+By default only read-only scopes are requested, which cover the public repositories; use --private-repos to request the repo scope
+(which also grants write access to all your repositories). The stored token is used until it expires, or for --lifetime.
+OAuth app tokens don't expire on GitHub: they are revoked when they expire (or on --logout) if LEGITIFY_OAUTH_CLIENT_SECRET is set.`,
+		RunE:         executeLoginCommand,
+		SilenceUsage: true,
+	}
+
+	viper.AutomaticEnv()
+	flags := loginCmd.Flags()
+	flags.StringVarP(&loginArgs.Endpoint, ArgServerUrl, "", "", "GitHub Enterprise Server URL to log in to instead of GitHub Cloud (can be set via the environment variable SERVER_URL)")
+	flags.StringVarP(&loginArgs.ClientID, argClientID, "", "", "client id of the OAuth app (can be set via the environment variable LEGITIFY_OAUTH_CLIENT_ID)")
+	flags.StringSliceVarP(&loginArgs.Scopes, argScopes, "", nil, "the scopes to request, instead of the read-only defaults (ignored for GitHub Apps, which use the app permissions)")
+	flags.BoolVarP(&loginArgs.PrivateRepos, argPrivateRepos, "", false, "request the repo scope to scan private repositories (it also grants write access to them)")
+	flags.DurationVarP(&loginArgs.Lifetime, argLifetime, "", login.DefaultTokenLifetime, "how long the stored token is used before logging in again (tokens that expire earlier are used until they expire)")
+	flags.BoolVarP(&loginArgs.Logout, argLogout, "", false, "remove the stored token from the OS keychain")
+
+	return loginCmd
+}
+
+func executeLoginCommand(cmd *cobra.Command, _args []string) error {
+	if loginArgs.Endpoint == "" {
+		loginArgs.Endpoint = viper.GetString(EnvServerUrl)
+	}
+	host, err := login.Host(loginArgs.Endpoint)
+	if err != nil {
+		return err
+	}
+	if err = login.CheckSupported(); err != nil {
+		return err
+	}
+	clientSecret := viper.GetString(EnvOAuthAppSecret)
+
+	if loginArgs.Logout {
+		return logout(host, clientSecret)
+	}
+
+	if loginArgs.ClientID == "" {
+		loginArgs.ClientID = viper.GetString(EnvOAuthAppID)
+	}
+	if loginArgs.ClientID == "" {
+		return fmt.Errorf("missing OAuth app client id (--%s or LEGITIFY_OAUTH_CLIENT_ID)", argClientID)
+	}
+
+	if loginArgs.Lifetime <= 0 {
+		return fmt.Errorf("--%s must be positive", argLifetime)
+	}
+	scopes := loginArgs.Scopes
+	if len(scopes) == 0 {
+		scopes = login.Scopes(loginArgs.PrivateRepos)
+	}
+	for _, scope := range scopes {
+		if scope == login.PrivateRepoScope {
+			fmt.Fprintf(os.Stderr, "Warning: the %s scope grants write access to all your repositories (it has no read-only alternative), "+
+				"and is stored in the OS keychain until the token expires\n", login.PrivateRepoScope)
+			break
+		}
+	}
+
+	flow := login.NewDeviceFlow(loginArgs.Endpoint, loginArgs.ClientID, scopes)
+	code, err := flow.RequestCode(context.Background())
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Open %s and enter the code: %s\n", code.VerificationURI, code.UserCode)
+	fmt.Fprintf(os.Stderr, "Waiting for the authorization...\n")
+	token, err := flow.PollToken(context.Background(), code)
+	if err != nil {
+		return err
+	}
+
+	token.LimitLifetime(loginArgs.Lifetime)
+	if err = login.StoreToken(host, *token); err != nil {
+		return err
+	}
+
+	fmt.Printf("Logged in to %s, the token is stored in the OS keychain", host)
+	if token.Scopes != "" {
+		fmt.Printf(" (scopes: %s)", strings.ReplaceAll(token.Scopes, ",", ", "))
+	}
+	fmt.Printf(", it expires at %s", token.ExpiresAt.Local().Format("2006-01-02 15:04"))
+	fmt.Println()
+	if !token.ServerExpiry && clientSecret == "" {
+		fmt.Fprintf(os.Stderr, "Warning: the OAuth app's token doesn't expire on GitHub, and without %s it isn't revoked when it expires "+
+			"(prefer a GitHub App with user token expiration)\n", strings.ToUpper(EnvOAuthAppSecret))
+	}
+	return nil
+}
+
+// logout removes the stored token, and revokes it if the client secret is set
+func logout(host string, clientSecret string) error {
+	token, err := login.LoadToken(host)
+	if err != nil {
+		return err
+	}
+	if err = login.DeleteToken(host); err != nil {
+		return err
+	}
+	fmt.Printf("Removed the stored token of %s\n", host)
+
+	if clientSecret == "" {
+		if !token.ServerExpiry {
+			fmt.Fprintf(os.Stderr, "Warning: the token is still valid on GitHub, set %s to revoke it, or revoke it in the applications settings of your account\n",
+				strings.ToUpper(EnvOAuthAppSecret))
+		}
+		return nil
+	}
+	if err = login.RevokeToken(context.Background(), loginArgs.Endpoint, clientSecret, *token); err != nil {
+		return err
+	}
+	fmt.Printf("Revoked the token on %s\n", host)
+	return nil
+}
@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/Legit-Labs/legitify/internal/metrics"
+)
+
+// startMetricsServer serves the metrics in the background; the returned channel reports the server failure
+func startMetricsServer(addr string, log *log.Logger) <-chan error {
+	errors := make(chan error, 1)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Default().Handler())
+
+	go func() {
+		log.Printf("Serving metrics on %s/metrics", addr)
+		errors <- http.ListenAndServe(addr, mux)
+	}()
+
+	return errors
+}
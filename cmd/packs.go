@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Legit-Labs/legitify/internal/common/scm_type"
+	"github.com/Legit-Labs/legitify/internal/opa"
+	"github.com/Legit-Labs/legitify/internal/packs"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	rootCmd.AddCommand(newPacksCommand())
+}
+
+const (
+	cmdPacks              = "packs"
+	argPacksIndex         = "index"
+	argPacksPublicKey     = "public-key"
+	envPacksIndex         = "legitify_packs_index"
+	envPacksPublicKeys    = "legitify_packs_public_keys"
+	defaultPacksDirectory = "legitify-policies"
+)
+
+var (
+	packsIndex      string
+	packsPublicKeys []string
+	packsPath       string
+	packsScmType    string
+)
+
+func newPacksCommand() *cobra.Command {
+	packsCmd := &cobra.Command{
+		Use:   cmdPacks,
+		Short: `List and install community policy packs from a packs index`,
+		Long: `Community policy packs are sets of domain-specific policies (e.g. fintech, healthcare) listed in a packs index.
+There is no default index: the index is a json file (https URL or local path) set with --index or the LEGITIFY_PACKS_INDEX
+environment variable. The index itself isn't trusted: each pack's name, version and digest are signed, and installed packs
+are verified against the trusted ed25519 public keys (--public-key or LEGITIFY_PACKS_PUBLIC_KEYS).
+Installed packs can be used with: legitify analyze --policies-path <policies path>.`,
+	}
+
+	viper.AutomaticEnv()
+	packsCmd.PersistentFlags().StringVarP(&packsIndex, argPacksIndex, "", "", "https URL or path of the packs index (can be set via the environment variable LEGITIFY_PACKS_INDEX)")
+
+	packsCmd.AddCommand(newPacksListCommand())
+	packsCmd.AddCommand(newPacksInstallCommand())
+
+	return packsCmd
+}
+
+func newPacksListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:          "list",
+		Short:        `List the packs of the index`,
+		Args:         cobra.NoArgs,
+		RunE:         executePacksListCommand,
+		SilenceUsage: true,
+	}
+}
+
+func newPacksInstallCommand() *cobra.Command {
+	installCmd := &cobra.Command{
+		Use:          "install <pack> [<pack>...]",
+		Short:        `Download, verify and install packs into the policies path`,
+		Args:         cobra.MinimumNArgs(1),
+		RunE:         executePacksInstallCommand,
+		SilenceUsage: true,
+	}
+
+	flags := installCmd.Flags()
+	flags.StringSliceVarP(&packsPublicKeys, argPacksPublicKey, "", nil, "trusted base64 ed25519 public key of the packs signatures (can be repeated, or set via the environment variable LEGITIFY_PACKS_PUBLIC_KEYS)")
+	flags.StringVarP(&packsPath, argPoliciesPath, "p", defaultPacksDirectory, "directory to install the packs into (a sub-directory per pack)")
+	flags.StringVarP(&packsScmType, ScmType, "", scm_type.GitHub, "server type (GitHub, GitLab, CodeCommit) the packs policies are validated against")
+
+	return installCmd
+}
+
+func loadPacksIndex() (*packs.Index, error) {
+	if packsIndex == "" {
+		packsIndex = viper.GetString(envPacksIndex)
+	}
+	if packsIndex == "" {
+		return nil, fmt.Errorf("the packs index is required (use --%s or the %s environment variable)", argPacksIndex, strings.ToUpper(envPacksIndex))
+	}
+
+	return packs.LoadIndex(packsIndex)
+}
+
+func executePacksListCommand(cmd *cobra.Command, _args []string) error {
+	index, err := loadPacksIndex()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tVERSION\tTAGS\tDESCRIPTION")
+	for _, pack := range index.Packs {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", pack.Name, pack.Version, strings.Join(pack.Tags, ","), pack.Description)
+	}
+
+	return w.Flush()
+}
+
+func executePacksInstallCommand(cmd *cobra.Command, names []string) error {
+	if err := scm_type.Validate(packsScmType); err != nil {
+		return err
+	}
+
+	index, err := loadPacksIndex()
+	if err != nil {
+		return err
+	}
+
+	if len(packsPublicKeys) == 0 {
+		if env := viper.GetString(envPacksPublicKeys); env != "" {
+			packsPublicKeys = strings.Split(env, ",")
+		}
+	}
+	keys, err := packs.ParsePublicKeys(packsPublicKeys)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		pack, err := index.Find(name)
+		if err != nil {
+			return err
+		}
+
+		archive, err := packs.Download(pack, keys)
+		if err != nil {
+			return err
+		}
+
+		// make sure the pack compiles together with the built-in policies before it replaces the installed version
+		installed, err := packs.Install(pack, archive, packsPath, func(dir string) error {
+			_, err := opa.Load([]string{dir}, packsScmType)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		packPath := filepath.Join(packsPath, pack.Name)
+
+		fmt.Printf("Installed %s %s (%d policy files) into %s\n", pack.Name, pack.Version, len(installed), packPath)
+	}
+
+	fmt.Printf("Run the analysis with: legitify analyze --policies-path %s\n", packsPath)
+	return nil
+}
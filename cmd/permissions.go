@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	glclient "github.com/Legit-Labs/legitify/internal/clients/gitlab"
+	"github.com/Legit-Labs/legitify/internal/collectors"
+	ghcollectors "github.com/Legit-Labs/legitify/internal/collectors/github"
+	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/Legit-Labs/legitify/internal/common/scm_type"
+	"github.com/Legit-Labs/legitify/internal/doctor"
+	"github.com/Legit-Labs/legitify/internal/opa"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(newPermissionsCommand())
+}
+
+const cmdPermissions = "permissions"
+
+var permissionsArgs args
+
+func newPermissionsCommand() *cobra.Command {
+	permissionsCmd := &cobra.Command{
+		Use:   cmdPermissions,
+		Short: `Print the token scopes and roles required by each namespace`,
+		Long: `Print the minimal token scopes for a full analysis of each namespace, with the scopes (and the lowest role they're effective with)
+that the collection and each policy need.
+The report is generated from the permissions the collectors declare and the required scopes of the policies, so it also covers
+custom policies (--policies-path). No token is needed.`,
+		RunE:         executePermissionsCommand,
+		SilenceUsage: true,
+	}
+
+	flags := permissionsCmd.Flags()
+	flags.StringSliceVarP(&permissionsArgs.Namespaces, argNamespace, "n", namespace.All, "which namespaces to report")
+	flags.StringSliceVarP(&permissionsArgs.PoliciesPath, argPoliciesPath, "p", []string{}, "directory containing opa policies")
+	flags.StringVarP(&permissionsArgs.ScmType, ScmType, "", scm_type.GitHub, "server type (GitHub, GitLab), defaults to GitHub")
+	flags.StringVarP(&permissionsArgs.OutputFile, ArgOutputFile, "o", "", "output file, defaults to stdout")
+
+	return permissionsCmd
+}
+
+func executePermissionsCommand(cmd *cobra.Command, _args []string) error {
+	if err := scm_type.Validate(permissionsArgs.ScmType); err != nil {
+		return err
+	}
+	if permissionsArgs.ScmType == scm_type.CodeCommit {
+		return fmt.Errorf("%s is supported for GitHub and GitLab only (CodeCommit uses the IAM permissions of the AWS credentials)", cmdPermissions)
+	}
+	if err := namespace.ValidateNamespaces(permissionsArgs.Namespaces); err != nil {
+		return err
+	}
+	if err := setOutputFile(permissionsArgs.OutputFile); err != nil {
+		return err
+	}
+
+	engine, err := opa.Load(permissionsArgs.PoliciesPath, permissionsArgs.ScmType)
+	if err != nil {
+		return err
+	}
+	policies := doctor.PoliciesFromAnnotations(engine.Annotations().Flatten(), permissionsArgs.Namespaces)
+
+	if permissionsArgs.ScmType == scm_type.GitLab {
+		fmt.Printf("The token (a personal, group or OAuth token) requires the %s scope; the groups are analyzed with the Owner role,\n"+
+			"and the projects the token has at least the Maintainer role in.\n\n", strings.Join(glclient.RequiredScopes, ", "))
+	}
+
+	printed := false
+	for _, ns := range permissionsArgs.Namespaces {
+		var required []collectors.RequiredPermission
+		if permissionsArgs.ScmType == scm_type.GitHub {
+			required = ghcollectors.RequiredPermissions(ns)
+		}
+		requirements := doctor.NewRequirements(ns, required, policies)
+		if requirements.IsEmpty() {
+			continue
+		}
+		if printed {
+			fmt.Println()
+		}
+		requirements.Print(os.Stdout)
+		printed = true
+	}
+
+	return nil
+}
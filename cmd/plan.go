@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Legit-Labs/legitify/internal/common/scm_type"
+	"github.com/Legit-Labs/legitify/internal/plan"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	rootCmd.AddCommand(newPlanCommand())
+}
+
+const (
+	cmdPlan          = "plan"
+	argPlanInputFile = "input-file"
+)
+
+func newPlanCommand() *cobra.Command {
+	planCmd := &cobra.Command{
+		Use:   cmdPlan,
+		Short: `Convert analysis results into an ordered remediation plan (as a yaml)`,
+		Long: `Convert the results of a previous analysis (json format, flattened scheme) into a remediation plan.
+Each step of the plan fixes a single policy across all of its affected entities.
+Steps are ordered by severity and then by the number of affected entities, and grouped by the remediation action
+(with its API call, and a terraform example where there's a resource for it) that fixes them.`,
+		RunE: executePlanCommand,
+	}
+	flags := planCmd.Flags()
+	flags.StringP(argPlanInputFile, "i", "", "analysis results file (json format, flattened scheme)")
+	flags.StringP(ArgOutputFile, "o", "", "output file, defaults to stdout")
+	flags.StringP(ScmType, "", scm_type.GitHub, "server type of the analysis (GitHub, GitLab, CodeCommit), defaults to GitHub")
+	_ = planCmd.MarkFlagRequired(argPlanInputFile)
+
+	return planCmd
+}
+
+func executePlanCommand(cmd *cobra.Command, args []string) error {
+	flags := cmd.Flags()
+
+	inputFile, err := flags.GetString(argPlanInputFile)
+	if err != nil {
+		return err
+	}
+
+	scmType, err := flags.GetString(ScmType)
+	if err != nil {
+		return err
+	}
+	if err = scm_type.Validate(scmType); err != nil {
+		return err
+	}
+
+	input, err := os.ReadFile(inputFile)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", inputFile, err)
+	}
+
+	outputFile, err := flags.GetString(ArgOutputFile)
+	if err != nil {
+		return err
+	} else if outputFile != "" {
+		if err = setOutputFile(outputFile); err != nil {
+			return err
+		}
+	}
+
+	result, err := plan.FromFlattenedJson(input, scmType)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stdout.Write(data)
+	return err
+}
@@ -54,6 +54,9 @@ func (pb *ProgressBar) Run(progress <-chan collectors.CollectionMetric) group_wa
 	p, bars := pb.createBars()
 	go func() {
 		for data := range progress {
+			if data.Organization != "" {
+				continue
+			}
 			displayName := data.Namespace
 			val, ok := bars[displayName]
 
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/Legit-Labs/legitify/internal/common/scm_type"
+	"github.com/Legit-Labs/legitify/internal/orgdefaults"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	rootCmd.AddCommand(newProposeDefaultsCommand())
+}
+
+const (
+	cmdProposeDefaults = "propose-defaults"
+	argDryRun          = "dry-run"
+)
+
+var (
+	proposeArgs      args
+	proposeInputFile string
+	proposeOrg       string
+	proposeDryRun    bool
+)
+
+func newProposeDefaultsCommand() *cobra.Command {
+	proposeCmd := &cobra.Command{
+		Use:   cmdProposeDefaults,
+		Short: `Open a draft pull request with recommended defaults in the organization's .github repository`,
+		Long: `Propose recommended defaults (a SECURITY.md, a secure starter workflow template and a dependabot.yml template)
+to the organization's .github repository, based on the policies that fail in the results of a previous analysis
+(json format, flattened scheme).
+Only the files that help to fix failing policies, and that don't already exist in the repository, are proposed.
+Currently supported for GitHub only.`,
+		RunE:         executeProposeDefaultsCommand,
+		SilenceUsage: true,
+	}
+
+	viper.AutomaticEnv()
+	flags := proposeCmd.Flags()
+	proposeArgs.addCommonOptions(flags)
+	flags.StringVarP(&proposeInputFile, argPlanInputFile, "i", "", "results of a previous analysis (json format, flattened scheme)")
+	flags.StringVarP(&proposeOrg, argOrg, "", "", "the organization to propose the defaults to")
+	flags.BoolVarP(&proposeDryRun, argDryRun, "", false, "print the proposed files instead of opening the pull request")
+	_ = proposeCmd.MarkFlagRequired(argPlanInputFile)
+	_ = proposeCmd.MarkFlagRequired(argOrg)
+
+	return proposeCmd
+}
+
+func executeProposeDefaultsCommand(cmd *cobra.Command, _args []string) error {
+	proposeArgs.ApplyEnvVars()
+
+	if err := proposeArgs.validateCommonOptions(); err != nil {
+		return err
+	}
+	if proposeArgs.ScmType != scm_type.GitHub {
+		return fmt.Errorf("%s is currently supported for GitHub only", cmdProposeDefaults)
+	}
+
+	input, err := os.ReadFile(proposeInputFile)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", proposeInputFile, err)
+	}
+
+	failing, err := orgdefaults.FailingPoliciesFromFlattenedJson(input, proposeOrg)
+	if err != nil {
+		return err
+	}
+
+	if err = setErrorFile(proposeArgs.ErrorFile); err != nil {
+		return err
+	}
+	if err = setOutputFile(proposeArgs.OutputFile); err != nil {
+		return err
+	}
+
+	proposal := orgdefaults.Propose(proposeOrg, failing)
+	if len(proposal.Files) == 0 {
+		fmt.Printf("No defaults to propose: none of the related policies fail in %s\n", proposeOrg)
+		return nil
+	}
+
+	if proposeDryRun {
+		data, err := yaml.Marshal(proposal)
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+
+	client, err := provideGitHubClient(&proposeArgs)
+	if err != nil {
+		return err
+	}
+
+	pr, err := orgdefaults.OpenPullRequest(context.Background(), client.Client(), proposal)
+	if err != nil {
+		return err
+	}
+	if pr == nil {
+		fmt.Printf("No defaults to propose: the %s repository of %s already has all the proposed files\n", orgdefaults.Repository, proposeOrg)
+		return nil
+	}
+
+	fmt.Printf("Opened a draft pull request with %d files: %s\n", len(proposal.Files), pr.GetHTMLURL())
+	return nil
+}
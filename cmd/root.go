@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 
+	"github.com/Legit-Labs/legitify/internal/opa"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -13,6 +14,13 @@ var rootCmd = &cobra.Command{
 	Use:   "legitify",
 	Short: "Strengthen the security posture of your GitHub organization!",
 	Long:  `Detect and remediate misconfigurations, security and compliance issues across all your GitHub assets with ease.`,
+	// the policies are embedded in the binary, make sure they weren't corrupted (e.g. in a container image) before running
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := opa.VerifyBundles(); err != nil {
+			return fmt.Errorf("embedded policies verification failed: %w", err)
+		}
+		return nil
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
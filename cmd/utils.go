@@ -3,6 +3,8 @@ package cmd
 import (
 	"log"
 	"os"
+
+	"github.com/Legit-Labs/legitify/internal/logger"
 )
 
 func setErrorFile(path string) error {
@@ -15,6 +17,24 @@ func setErrorFile(path string) error {
 	return nil
 }
 
+// initLogger sets up the structured logger (writing to the error log unless a log file is given)
+func initLogger(a *args) error {
+	level, err := logger.ParseLevel(a.LogLevel)
+	if err != nil {
+		return err
+	}
+
+	out := log.Writer()
+	if a.LogFile != "" {
+		if out, err = openForWrite(a.LogFile); err != nil {
+			return err
+		}
+	}
+
+	logger.Init(out, level, a.LogFormat, logger.Fields{"scan_id": a.ScanID})
+	return nil
+}
+
 func openForWrite(path string) (*os.File, error) {
 	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
 	if err != nil {
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme/converter"
+	"github.com/Legit-Labs/legitify/schemas"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(newValidateOutputCommand())
+}
+
+const (
+	cmdValidateOutput = "validate-output"
+	argPrintSchema    = "print-schema"
+)
+
+var (
+	validateOutputScheme string
+	printSchema          bool
+)
+
+func newValidateOutputCommand() *cobra.Command {
+	validateCmd := &cobra.Command{
+		Use:   cmdValidateOutput + " <results.json>",
+		Short: `Validate a json output against the JSON schema of its output scheme`,
+		Long: `Validate a json output (legitify analyze -f json) against the published JSON schema of its output scheme.
+Use it to check parsers of the output are compatible with a legitify version, and to detect breaking changes of the output.
+Use --print-schema to print the JSON schema of the output scheme instead.
+Note: outputs with selected fields (--fields) don't match the schema.`,
+		Args:         cobra.MaximumNArgs(1),
+		RunE:         executeValidateOutputCommand,
+		SilenceUsage: true,
+	}
+
+	schemeTypes := toOptionsString(converter.SchemeTypes())
+	flags := validateCmd.Flags()
+	flags.StringVarP(&validateOutputScheme, argOutputScheme, "", converter.DefaultScheme, "output scheme of the results "+schemeTypes)
+	flags.BoolVarP(&printSchema, argPrintSchema, "", false, "print the JSON schema of the output scheme")
+
+	return validateCmd
+}
+
+func executeValidateOutputCommand(cmd *cobra.Command, cmdArgs []string) error {
+	if err := converter.ValidateOutputScheme(validateOutputScheme); err != nil {
+		return err
+	}
+
+	if printSchema {
+		schema, err := schemas.Get(validateOutputScheme)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(schema))
+		return nil
+	}
+
+	if len(cmdArgs) != 1 {
+		return fmt.Errorf("expected the json output file to validate (or --%s)", argPrintSchema)
+	}
+
+	output, err := os.ReadFile(cmdArgs[0])
+	if err != nil {
+		return err
+	}
+
+	errors, err := schemas.Validate(validateOutputScheme, output)
+	if err != nil {
+		return err
+	}
+	if len(errors) > 0 {
+		return fmt.Errorf("%s is not a valid %s output:\n%s", cmdArgs[0], validateOutputScheme, strings.Join(errors, "\n"))
+	}
+
+	fmt.Printf("%s is a valid %s output\n", cmdArgs[0], validateOutputScheme)
+	return nil
+}
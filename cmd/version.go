@@ -1,9 +1,12 @@
 package cmd
 
 import (
+	"embed"
 	"fmt"
 
+	"github.com/Legit-Labs/legitify/internal/common/scm_type"
 	"github.com/Legit-Labs/legitify/internal/version"
+	"github.com/Legit-Labs/legitify/policies"
 
 	"github.com/spf13/cobra"
 )
@@ -20,11 +23,34 @@ var versionCmd = &cobra.Command{
 	Use:   versionCmdText,
 	Short: "Print the version number",
 	Long:  `Print the version number`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		fmt.Println(GetVersion())
+		return printPolicyDigests()
 	},
 }
 
+// printPolicyDigests prints the checksums of the embedded policies, to verify which policies a binary (or image) was built with
+func printPolicyDigests() error {
+	bundles := []struct {
+		scmType scm_type.ScmType
+		bundle  embed.FS
+	}{
+		{scm_type.GitHub, policies.GitHubBundle},
+		{scm_type.GitLab, policies.GitLabBundle},
+		{scm_type.CodeCommit, policies.CodeCommitBundle},
+	}
+
+	for _, b := range bundles {
+		digest, err := policies.Digest(b.bundle)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s policies %s\n", b.scmType, digest)
+	}
+
+	return nil
+}
+
 func GetVersion() string {
 	return version.ReadableVersion
 }
@@ -10,17 +10,54 @@ import (
 	"context"
 	"github.com/Legit-Labs/legitify/internal/analyzers"
 	"github.com/Legit-Labs/legitify/internal/analyzers/skippers"
+	"github.com/Legit-Labs/legitify/internal/clients/codecommit"
 	"github.com/Legit-Labs/legitify/internal/clients/github"
 	"github.com/Legit-Labs/legitify/internal/clients/gitlab"
 	"github.com/Legit-Labs/legitify/internal/collectors"
+	codecommit2 "github.com/Legit-Labs/legitify/internal/collectors/codecommit"
 	"github.com/Legit-Labs/legitify/internal/collectors/collectors_manager"
 	github2 "github.com/Legit-Labs/legitify/internal/collectors/github"
 	gitlab2 "github.com/Legit-Labs/legitify/internal/collectors/gitlab"
 	"github.com/Legit-Labs/legitify/internal/common/namespace"
 	"github.com/Legit-Labs/legitify/internal/enricher"
+	"github.com/Legit-Labs/legitify/internal/publishers"
+	github3 "github.com/Legit-Labs/legitify/internal/publishers/github"
 	"log"
 )
 
+// Injectors from inject_codecommit.go:
+
+func setupCodeCommit(analyzeArgs2 *args, log2 *log.Logger) (*analyzeExecutor, error) {
+	client, err := provideCodeCommitClient(analyzeArgs2)
+	if err != nil {
+		return nil, err
+	}
+	context, err := provideContext(client, log2)
+	if err != nil {
+		return nil, err
+	}
+	v := provideCodeCommitCollectors(context, client, analyzeArgs2)
+	collectorManager := collectors_manager.NewCollectorsManager(v)
+	enginer, err := provideOpa(analyzeArgs2)
+	if err != nil {
+		return nil, err
+	}
+	skipper := skippers.NewSkipper(context)
+	analyzer := analyzers.NewAnalyzer(context, enginer, skipper)
+	enricherManager := enricher.NewEnricherManager(context)
+	outputer, err := provideOutputer(context, analyzeArgs2)
+	if err != nil {
+		return nil, err
+	}
+	v2, err := provideCodeCommitPublishers(analyzeArgs2)
+	if err != nil {
+		return nil, err
+	}
+	cmdOrganizationPublishing := provideOrganizationPublishing(context, analyzeArgs2)
+	cmdAnalyzeExecutor := initializeAnalyzeExecutor(collectorManager, analyzer, enricherManager, outputer, v2, cmdOrganizationPublishing, log2)
+	return cmdAnalyzeExecutor, nil
+}
+
 // Injectors from inject_github.go:
 
 func setupGitHub(analyzeArgs2 *args, log2 *log.Logger) (*analyzeExecutor, error) {
@@ -41,8 +78,16 @@ func setupGitHub(analyzeArgs2 *args, log2 *log.Logger) (*analyzeExecutor, error)
 	skipper := skippers.NewSkipper(context)
 	analyzer := analyzers.NewAnalyzer(context, enginer, skipper)
 	enricherManager := enricher.NewEnricherManager(context)
-	outputer := provideOutputer(context, analyzeArgs2)
-	cmdAnalyzeExecutor := initializeAnalyzeExecutor(collectorManager, analyzer, enricherManager, outputer, log2)
+	outputer, err := provideOutputer(context, analyzeArgs2)
+	if err != nil {
+		return nil, err
+	}
+	v2, err := provideGitHubPublishers(context, client, analyzeArgs2)
+	if err != nil {
+		return nil, err
+	}
+	cmdOrganizationPublishing := provideOrganizationPublishing(context, analyzeArgs2)
+	cmdAnalyzeExecutor := initializeAnalyzeExecutor(collectorManager, analyzer, enricherManager, outputer, v2, cmdOrganizationPublishing, log2)
 	return cmdAnalyzeExecutor, nil
 }
 
@@ -66,11 +111,44 @@ func setupGitLab(analyzeArgs2 *args, log2 *log.Logger) (*analyzeExecutor, error)
 	skipper := skippers.NewSkipper(context)
 	analyzer := analyzers.NewAnalyzer(context, enginer, skipper)
 	enricherManager := enricher.NewEnricherManager(context)
-	outputer := provideOutputer(context, analyzeArgs2)
-	cmdAnalyzeExecutor := initializeAnalyzeExecutor(collectorManager, analyzer, enricherManager, outputer, log2)
+	outputer, err := provideOutputer(context, analyzeArgs2)
+	if err != nil {
+		return nil, err
+	}
+	v2, err := provideGitLabPublishers(analyzeArgs2)
+	if err != nil {
+		return nil, err
+	}
+	cmdOrganizationPublishing := provideOrganizationPublishing(context, analyzeArgs2)
+	cmdAnalyzeExecutor := initializeAnalyzeExecutor(collectorManager, analyzer, enricherManager, outputer, v2, cmdOrganizationPublishing, log2)
 	return cmdAnalyzeExecutor, nil
 }
 
+// inject_codecommit.go:
+
+func provideCodeCommitPublishers(analyzeArgs2 *args) ([]publishers.Publisher, error) {
+	return commonPublishers(analyzeArgs2)
+}
+
+func provideCodeCommitCollectors(ctx context.Context, client *codecommit.Client, analyzeArgs2 *args) []collectors.Collector {
+	var collectorsMapping = map[namespace.Namespace]func(ctx context.Context, client *codecommit.Client) collectors.Collector{namespace.Repository: codecommit2.NewRepositoryCollector}
+
+	var result []collectors.Collector
+	for _, ns := range analyzeArgs2.Namespaces {
+		if creator, ok := collectorsMapping[ns]; ok {
+			result = append(result, creator(ctx, client))
+		}
+	}
+
+	return result
+}
+
+// provideCodeCommitClient creates a client authenticated with the AWS credentials of the environment (the token isn't used);
+// the organizations are the AWS account IDs
+func provideCodeCommitClient(analyzeArgs2 *args) (*codecommit.Client, error) {
+	return codecommit.NewClient(context.Background(), analyzeArgs2.Endpoint, analyzeArgs2.Organizations)
+}
+
 // inject_github.go:
 
 func provideGitHubCollectors(ctx context.Context, client *github.Client, analyzeArgs2 *args) []collectors.Collector {
@@ -85,15 +163,40 @@ func provideGitHubCollectors(ctx context.Context, client *github.Client, analyze
 	return result
 }
 
+func provideGitHubPublishers(ctx context.Context, client *github.Client, analyzeArgs2 *args) ([]publishers.Publisher, error) {
+	result, err := commonPublishers(analyzeArgs2)
+	if err != nil {
+		return nil, err
+	}
+	if analyzeArgs2.CreateCheckRuns {
+		result = append(result, github3.NewChecksPublisher(ctx, client))
+	}
+	if analyzeArgs2.UploadCodeScanning {
+		result = append(result, github3.NewCodeScanningPublisher(ctx, client, analyzeArgs2.CodeScanningRepo))
+	}
+	if analyzeArgs2.CreateIssues {
+		result = append(result, github3.NewIssuesPublisher(ctx, client, analyzeArgs2.IssuesRepo))
+	}
+	if analyzeArgs2.GhasMatrixFile != "" {
+		result = append(result, github3.NewGhasMatrixPublisher(analyzeArgs2.GhasMatrixFile))
+	}
+
+	return result, nil
+}
+
 func provideGitHubClient(analyzeArgs2 *args) (*github.Client, error) {
 	return github.NewClient(context.Background(), analyzeArgs2.Token, analyzeArgs2.Endpoint, analyzeArgs2.
-		Organizations, false)
+		Organizations, false, analyzeArgs2.tokenRefresher)
 }
 
 // inject_gitlab.go:
 
+func provideGitLabPublishers(analyzeArgs2 *args) ([]publishers.Publisher, error) {
+	return commonPublishers(analyzeArgs2)
+}
+
 func provideGitLabCollectors(ctx context.Context, client *gitlab.Client, analyzeArgs2 *args) []collectors.Collector {
-	var collectorsMapping = map[namespace.Namespace]func(ctx context.Context, client *gitlab.Client) collectors.Collector{namespace.Organization: gitlab2.NewGroupCollector}
+	var collectorsMapping = map[namespace.Namespace]func(ctx context.Context, client *gitlab.Client) collectors.Collector{namespace.Organization: gitlab2.NewGroupCollector, namespace.Repository: gitlab2.NewProjectCollector}
 
 	var result []collectors.Collector
 	for _, ns := range analyzeArgs2.Namespaces {
@@ -106,5 +209,5 @@ func provideGitLabCollectors(ctx context.Context, client *gitlab.Client, analyze
 }
 
 func provideGitLabClient(analyzeArgs2 *args) (*gitlab.Client, error) {
-	return gitlab.NewClient(context.Background(), analyzeArgs2.Token, analyzeArgs2.Endpoint, analyzeArgs2.Organizations, false)
+	return gitlab.NewClient(context.Background(), analyzeArgs2.Token, analyzeArgs2.Endpoint, analyzeArgs2.Organizations, analyzeArgs2.Subgroups, false, analyzeArgs2.tokenRefresher)
 }
@@ -0,0 +1,33 @@
+package activity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Legit-Labs/legitify/internal/clients/github/types"
+)
+
+// DefaultDormantAdminDays is the number of days without activity after which an admin is considered dormant
+const DefaultDormantAdminDays = 90
+
+// Phrase returns the audit log search phrase of the events of the last days
+func Phrase(days int, now time.Time) string {
+	return fmt.Sprintf("created:>=%s", now.AddDate(0, 0, -days).UTC().Format("2006-01-02"))
+}
+
+// LastActive returns the time of the most recent audit log event of each actor (by the lower-cased login)
+func LastActive(entries []types.AuditLogEntry) map[string]time.Time {
+	result := make(map[string]time.Time)
+	for _, entry := range entries {
+		if entry.Actor == "" {
+			continue
+		}
+		actor := strings.ToLower(entry.Actor)
+		timestamp := time.UnixMilli(entry.Timestamp)
+		if last, ok := result[actor]; !ok || timestamp.After(last) {
+			result[actor] = timestamp
+		}
+	}
+	return result
+}
@@ -0,0 +1,30 @@
+package activity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Legit-Labs/legitify/internal/clients/github/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPhrase(t *testing.T) {
+	now := time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)
+	require.Equal(t, "created:>=2024-01-31", Phrase(60, now))
+}
+
+func TestLastActive(t *testing.T) {
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(48 * time.Hour)
+
+	lastActive := LastActive([]types.AuditLogEntry{
+		{Action: "git.push", Actor: "Alice", Timestamp: older.UnixMilli()},
+		{Action: "repo.create", Actor: "alice", Timestamp: newer.UnixMilli()},
+		{Action: "git.clone", Actor: "bob", Timestamp: older.UnixMilli()},
+		{Action: "org.update_member", Actor: "", Timestamp: newer.UnixMilli()},
+	})
+
+	require.Len(t, lastActive, 2)
+	require.True(t, newer.Equal(lastActive["alice"]))
+	require.True(t, older.Equal(lastActive["bob"]))
+}
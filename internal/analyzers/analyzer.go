@@ -2,11 +2,17 @@ package analyzers
 
 import (
 	"context"
+	"fmt"
 	"github.com/Legit-Labs/legitify/internal/analyzers/parsing_utils"
 	"github.com/Legit-Labs/legitify/internal/analyzers/skippers"
 	githubcollected "github.com/Legit-Labs/legitify/internal/collected"
 	"github.com/Legit-Labs/legitify/internal/common/namespace"
-	"log"
+	"github.com/Legit-Labs/legitify/internal/confidence"
+	"github.com/Legit-Labs/legitify/internal/context_utils"
+	"github.com/Legit-Labs/legitify/internal/logger"
+	"github.com/Legit-Labs/legitify/internal/tracing"
+	"github.com/Legit-Labs/legitify/internal/waivers"
+	"strings"
 
 	"github.com/Legit-Labs/legitify/internal/collectors"
 	"github.com/Legit-Labs/legitify/internal/common/group_waiter"
@@ -21,11 +27,14 @@ const (
 	PolicyPassed  PolicyStatus = "PASSED"
 	PolicyFailed  PolicyStatus = "FAILED"
 	PolicySkipped PolicyStatus = "SKIPPED"
+	// PolicyWaived is a violation that was accepted (see the waivers package)
+	PolicyWaived PolicyStatus = "WAIVED"
 )
 
 type AnalyzedData struct {
 	Entity                   githubcollected.Entity
 	Namespace                namespace.Namespace
+	Organization             string
 	PolicyName               string
 	FullyQualifiedPolicyName string
 	Title                    string
@@ -37,6 +46,9 @@ type AnalyzedData struct {
 	CanonicalLink            string
 	ExtraData                interface{}
 	Status                   PolicyStatus
+	Waiver                   *waivers.Waiver
+	// Confidence is lowered by policies whose input was partially collected (nil for high confidence)
+	Confidence *confidence.Confidence
 }
 
 type Analyzer interface {
@@ -61,6 +73,7 @@ func newAnalyzedData(collectedData collectors.CollectedData, result opa_engine.Q
 	return AnalyzedData{
 		Entity:                   collectedData.Entity,
 		Namespace:                collectedData.Namespace,
+		Organization:             collectedData.Organization,
 		PolicyName:               result.PolicyName,
 		FullyQualifiedPolicyName: result.FullyQualifiedPolicyName,
 		Annotations:              result.Annotations,
@@ -72,6 +85,7 @@ func newAnalyzedData(collectedData collectors.CollectedData, result opa_engine.Q
 		CanonicalLink:            collectedData.Entity.CanonicalLink(),
 		ExtraData:                result.ExtraData,
 		Status:                   status,
+		Confidence:               resolveConfidence(result, status),
 	}
 }
 
@@ -84,15 +98,29 @@ func (a *analyzer) Analyze(dataChannel <-chan collectors.CollectedData) <-chan A
 		for data := range dataChannel {
 			data := data
 			gw.Do(func() {
+				_, span := tracing.Start(a.context, fmt.Sprintf("evaluate %s policies", data.Namespace))
+				span.SetAttribute("namespace", data.Namespace)
+				span.SetAttribute("entity", data.Entity.Name())
 				results, err := a.engine.Query(a.context, data.Namespace, data.Entity)
+				span.End(err)
 				if err != nil {
-					log.Printf("Failed to query opa %s: %s", data.Namespace, err)
+					logger.With(logger.Fields{"namespace": data.Namespace, "entity": data.Entity.Name()}).WithError(err).Errorf("failed to query opa")
 					return
 				}
 
 				for _, result := range results {
+					if !a.isPolicySelected(result) {
+						continue
+					}
 					status := a.resolvePolicyStatus(data, result)
-					outputChannel <- newAnalyzedData(data, result, status)
+					analyzed := newAnalyzedData(data, result, status)
+					if status == PolicyFailed {
+						if waiver, waived := findWaiver(data, result); waived {
+							analyzed.Status = PolicyWaived
+							analyzed.Waiver = waiver
+						}
+					}
+					outputChannel <- analyzed
 				}
 			})
 		}
@@ -102,6 +130,52 @@ func (a *analyzer) Analyze(dataChannel <-chan collectors.CollectedData) <-chan A
 	return outputChannel
 }
 
+// isPolicySelected checks the policy against the --skip-policy and --policy-tags/--exclude-policy-tags filters.
+// A policy is selected if it wasn't skipped, has at least one of the requested tags (or no tags were requested)
+// and none of the excluded ones.
+func (a *analyzer) isPolicySelected(result opa_engine.QueryResult) bool {
+	if isPolicySkipped(result, context_utils.GetSkippedPolicies(a.context)) {
+		return false
+	}
+
+	var policyTags []string
+	if result.Annotations != nil {
+		policyTags = parsing_utils.ResolveAnnotation(result.Annotations.Custom["tags"])
+	}
+
+	if hasAnyTag(policyTags, context_utils.GetExcludedPolicyTags(a.context)) {
+		return false
+	}
+
+	requested := context_utils.GetPolicyTags(a.context)
+	return len(requested) == 0 || hasAnyTag(policyTags, requested)
+}
+
+// isPolicySkipped matches either the policy name (e.g. stale_admin_found)
+// or its namespace-qualified name (e.g. member.stale_admin_found)
+func isPolicySkipped(result opa_engine.QueryResult, skipped []string) bool {
+	qualifiedName := strings.TrimPrefix(result.FullyQualifiedPolicyName, "data.")
+	for _, policyName := range skipped {
+		if policyName == result.PolicyName || policyName == qualifiedName {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasAnyTag(policyTags []string, tags []string) bool {
+	for _, tag := range tags {
+		for _, policyTag := range policyTags {
+			if strings.EqualFold(tag, policyTag) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 func (a *analyzer) resolvePolicyStatus(data collectors.CollectedData, opaResult opa_engine.QueryResult) PolicyStatus {
 	if a.skipper.ShouldSkip(data, opaResult) {
 		return PolicySkipped
@@ -114,15 +188,32 @@ func (a *analyzer) resolvePolicyStatus(data collectors.CollectedData, opaResult
 	return PolicyFailed
 }
 
+// resolveConfidence returns the confidence of the evaluated policies (a skipped policy wasn't evaluated)
+func resolveConfidence(result opa_engine.QueryResult, status PolicyStatus) *confidence.Confidence {
+	if status == PolicySkipped {
+		return nil
+	}
+	return result.Confidence
+}
+
+func findWaiver(data collectors.CollectedData, result opa_engine.QueryResult) (*waivers.Waiver, bool) {
+	waivable, ok := data.Entity.(waivers.Waivable)
+	if !ok {
+		return nil, false
+	}
+
+	return waivers.Find(waivable.Waivers(), result.PolicyName, strings.TrimPrefix(result.FullyQualifiedPolicyName, "data."))
+}
+
 func resolveSeverity(qResult opa_engine.QueryResult) severity.Severity {
 	s := severity.Unknown
 	raw := qResult.Annotations.Custom["severity"]
 	sRaw, ok := raw.(string)
 
 	if !ok {
-		log.Printf("Invalid severity type \"%T\" for policy %s\n", sRaw, qResult.FullyQualifiedPolicyName)
+		logger.With(logger.Fields{"policy": qResult.FullyQualifiedPolicyName}).Errorf("invalid severity type \"%T\"", raw)
 	} else if !severity.IsValid(sRaw) {
-		log.Printf("Invalid severity value \"%s\" for policy %s\n", sRaw, qResult.FullyQualifiedPolicyName)
+		logger.With(logger.Fields{"policy": qResult.FullyQualifiedPolicyName}).Errorf("invalid severity value \"%s\"", sRaw)
 	} else {
 		s = sRaw
 	}
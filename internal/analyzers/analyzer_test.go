@@ -6,8 +6,12 @@ import (
 	githubcollected "github.com/Legit-Labs/legitify/internal/collected"
 	"github.com/Legit-Labs/legitify/internal/common/permissions"
 	"github.com/Legit-Labs/legitify/internal/common/scm_type"
+	"github.com/Legit-Labs/legitify/internal/confidence"
 	"github.com/Legit-Labs/legitify/internal/context_utils"
 	"github.com/Legit-Labs/legitify/internal/opa"
+	"github.com/Legit-Labs/legitify/internal/opa/opa_engine"
+	"github.com/Legit-Labs/legitify/internal/waivers"
+	"github.com/open-policy-agent/opa/ast"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -16,6 +20,14 @@ import (
 	"github.com/Legit-Labs/legitify/internal/collectors"
 )
 
+type nullEntity struct {
+	githubcollected.Entity
+}
+
+func (nullEntity) Name() string {
+	return "null"
+}
+
 func TestAnalyzerSanity(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
@@ -29,10 +41,6 @@ func TestAnalyzerSanity(t *testing.T) {
 	analyzer := NewAnalyzer(ctx, engine, skippers.NewSkipper(ctx))
 	require.NotNilf(t, analyzer, "failed to create analyzer")
 
-	type nullEntity struct {
-		githubcollected.Entity
-	}
-
 	someData := collectors.CollectedData{
 		Namespace: "test",
 		Entity:    nullEntity{},
@@ -46,3 +54,96 @@ func TestAnalyzerSanity(t *testing.T) {
 	// Run
 	analyzer.Analyze(data)
 }
+
+func TestAnalyzerPolicyTags(t *testing.T) {
+	tagged := func(tags ...interface{}) opa_engine.QueryResult {
+		return opa_engine.QueryResult{
+			Annotations: &ast.Annotations{
+				Custom: map[string]interface{}{"tags": tags},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		tags     []string
+		excluded []string
+		result   opa_engine.QueryResult
+		selected bool
+	}{
+		{"no filters", nil, nil, tagged("supply-chain"), true},
+		{"matching tag", []string{"supply-chain"}, nil, tagged("webhooks", "supply-chain"), true},
+		{"case insensitive", []string{"Supply-Chain"}, nil, tagged("supply-chain"), true},
+		{"non matching tag", []string{"webhooks"}, nil, tagged("supply-chain"), false},
+		{"untagged policy", []string{"webhooks"}, nil, tagged(), false},
+		{"excluded tag", nil, []string{"webhooks"}, tagged("webhooks", "supply-chain"), false},
+		{"exclusion wins", []string{"supply-chain"}, []string{"webhooks"}, tagged("webhooks", "supply-chain"), false},
+	}
+
+	for _, test := range tests {
+		ctx := context_utils.NewContextWithPolicyTags(context.Background(), test.tags, test.excluded)
+		a := &analyzer{context: ctx}
+		require.Equal(t, test.selected, a.isPolicySelected(test.result), test.name)
+	}
+}
+
+func TestAnalyzerSkipPolicy(t *testing.T) {
+	result := opa_engine.QueryResult{
+		PolicyName:               "stale_admin_found",
+		FullyQualifiedPolicyName: "data.member.stale_admin_found",
+		Annotations:              &ast.Annotations{},
+	}
+
+	tests := []struct {
+		name     string
+		skipped  []string
+		selected bool
+	}{
+		{"nothing skipped", nil, true},
+		{"skipped by name", []string{"stale_admin_found"}, false},
+		{"skipped by qualified name", []string{"member.stale_admin_found"}, false},
+		{"other namespace", []string{"repository.stale_admin_found"}, true},
+		{"other policy", []string{"stale_member_found"}, true},
+	}
+
+	for _, test := range tests {
+		ctx := context_utils.NewContextWithSkippedPolicies(context.Background(), test.skipped)
+		a := &analyzer{context: ctx}
+		require.Equal(t, test.selected, a.isPolicySelected(result), test.name)
+	}
+}
+
+type waivedEntity struct {
+	githubcollected.Entity
+	waivers []waivers.Waiver
+}
+
+func (w waivedEntity) Waivers() []waivers.Waiver {
+	return w.waivers
+}
+
+func TestFindWaiver(t *testing.T) {
+	data := collectors.CollectedData{
+		Entity: waivedEntity{waivers: []waivers.Waiver{{Policy: "repository.forking_allowed", Justification: "open source"}}},
+	}
+
+	waiver, waived := findWaiver(data, opa_engine.QueryResult{PolicyName: "forking_allowed", FullyQualifiedPolicyName: "data.repository.forking_allowed"})
+	require.True(t, waived)
+	require.Equal(t, "open source", waiver.Justification)
+
+	_, waived = findWaiver(data, opa_engine.QueryResult{PolicyName: "code_review_not_required", FullyQualifiedPolicyName: "data.repository.code_review_not_required"})
+	require.False(t, waived)
+
+	_, waived = findWaiver(collectors.CollectedData{Entity: waivedEntity{}.Entity}, opa_engine.QueryResult{PolicyName: "forking_allowed"})
+	require.False(t, waived, "entities without waivers are never waived")
+}
+
+func TestResolveConfidence(t *testing.T) {
+	partial := &confidence.Confidence{Level: confidence.Low, Reason: "partial data"}
+	result := opa_engine.QueryResult{PolicyName: "code_review_can_be_bypassed", Confidence: partial}
+
+	require.Equal(t, partial, resolveConfidence(result, PolicyFailed))
+	require.Equal(t, partial, resolveConfidence(result, PolicyPassed))
+	require.Nil(t, resolveConfidence(result, PolicySkipped), "skipped policies weren't evaluated")
+	require.Nil(t, resolveConfidence(opa_engine.QueryResult{}, PolicyFailed))
+}
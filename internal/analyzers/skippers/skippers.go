@@ -6,8 +6,8 @@ import (
 	"github.com/Legit-Labs/legitify/internal/collectors"
 	"github.com/Legit-Labs/legitify/internal/common/permissions"
 	"github.com/Legit-Labs/legitify/internal/context_utils"
+	"github.com/Legit-Labs/legitify/internal/logger"
 	"github.com/Legit-Labs/legitify/internal/opa/opa_engine"
-	"log"
 )
 
 type Skipper interface {
@@ -16,18 +16,45 @@ type Skipper interface {
 
 type IsPrerequisitesSatisfied func(data collectors.CollectedData) bool
 
+// serverFeatures are the prerequisites that are satisfied unless the server version doesn't have the feature
+// (named after the GitHub client features)
+var serverFeatures = []string{
+	"codespaces",
+	"secret_scanning_push_protection",
+	"oidc_subject_claim",
+	"security_managers",
+	"dependency_graph_sbom",
+	"custom_repository_roles",
+	"fork_pr_contributor_approval",
+	"webhook_deliveries",
+	"ip_allow_list",
+	"saml_sso_identities",
+}
+
 func NewSkipper(ctx context.Context) Skipper {
-	return &skipper{
-		ctx: ctx,
-		prerequisitesCheckers: map[string]IsPrerequisitesSatisfied{
-			"premium": func(data collectors.CollectedData) bool {
-				return data.Context.Premium()
-			},
-			"scorecard_enabled": func(data collectors.CollectedData) bool {
-				return context_utils.GetScorecardEnabled(ctx)
-			},
+	checkers := map[string]IsPrerequisitesSatisfied{
+		"premium": func(data collectors.CollectedData) bool {
+			return data.Context.Premium()
+		},
+		"scorecard_enabled": func(data collectors.CollectedData) bool {
+			return context_utils.GetScorecardEnabled(ctx)
+		},
+		"identity_source": func(data collectors.CollectedData) bool {
+			_, ok := context_utils.GetIdentitySource(ctx)
+			return ok
 		},
 	}
+	for _, feature := range serverFeatures {
+		feature := feature
+		checkers[feature] = func(data collectors.CollectedData) bool {
+			return context_utils.IsFeatureSupported(ctx, feature)
+		}
+	}
+
+	return &skipper{
+		ctx:                   ctx,
+		prerequisitesCheckers: checkers,
+	}
 }
 
 type skipper struct {
@@ -40,7 +67,7 @@ func (sm *skipper) ShouldSkip(data collectors.CollectedData, violation opa_engin
 
 	sufficient, missingPrerequisite := sm.arePrerequisitesSatisfied(prerequisites, data)
 	if !sufficient {
-		log.Printf("Skipping policy: %s, missing prerequisite: %s\n", violation.PolicyName, missingPrerequisite)
+		logger.With(logger.Fields{"policy": violation.PolicyName, "entity": data.Entity.Name(), "prerequisite": missingPrerequisite}).Infof("skipping policy, missing prerequisite")
 		return true
 	}
 
@@ -49,7 +76,7 @@ func (sm *skipper) ShouldSkip(data collectors.CollectedData, violation opa_engin
 
 	sufficient, missingScope := sufficientScopes(data.Context.Roles(), currentScopes, scopes)
 	if !sufficient {
-		log.Printf("Skipping policy: %s, missing scope: %s\n", violation.PolicyName, missingScope)
+		logger.With(logger.Fields{"policy": violation.PolicyName, "entity": data.Entity.Name(), "scope": missingScope}).Infof("skipping policy, missing scope")
 		return true
 	}
 
@@ -0,0 +1,43 @@
+package skippers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Legit-Labs/legitify/internal/collectors"
+	"github.com/Legit-Labs/legitify/internal/common/permissions"
+	"github.com/Legit-Labs/legitify/internal/context_utils"
+	"github.com/Legit-Labs/legitify/internal/opa/opa_engine"
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/stretchr/testify/require"
+)
+
+type testContext struct{}
+
+func (testContext) Premium() bool             { return true }
+func (testContext) Roles() []permissions.Role { return []permissions.Role{permissions.OrgRoleOwner} }
+
+type testEntity struct{}
+
+func (testEntity) ViolationEntityType() string { return "organization" }
+func (testEntity) CanonicalLink() string       { return "" }
+func (testEntity) Name() string                { return "org" }
+func (testEntity) ID() int64                   { return 1 }
+
+func TestSkipUnsupportedFeatures(t *testing.T) {
+	data := collectors.CollectedData{Context: testContext{}, Entity: testEntity{}}
+	policy := func(prerequisite string) opa_engine.QueryResult {
+		return opa_engine.QueryResult{
+			PolicyName:  "policy",
+			Annotations: &ast.Annotations{Custom: map[string]interface{}{"prerequisites": []interface{}{prerequisite}}},
+		}
+	}
+
+	ctx := context_utils.NewContextWithTokenScopes(context.Background(), permissions.ParseTokenScopes(permissions.FullAnalysisScopes))
+	skipper := NewSkipper(context_utils.NewContextWithUnsupportedFeatures(ctx, []string{"oidc_subject_claim"}))
+	require.True(t, skipper.ShouldSkip(data, policy("oidc_subject_claim")))
+	require.False(t, skipper.ShouldSkip(data, policy("secret_scanning_push_protection")))
+	require.True(t, skipper.ShouldSkip(data, policy("unknown")))
+
+	require.False(t, NewSkipper(ctx).ShouldSkip(data, policy("oidc_subject_claim")))
+}
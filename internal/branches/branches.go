@@ -0,0 +1,40 @@
+package branches
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/Legit-Labs/legitify/internal/clients/github/types"
+)
+
+// Branch is the protection of a non-default branch that matches the configured branch name patterns
+type Branch struct {
+	Name string `json:"name"`
+	// Protected is whether the branch has a branch protection rule
+	Protected bool `json:"protected"`
+	// RequiredApprovingReviewCount and AllowsForcePushes are collected for protected branches (given admin permissions)
+	RequiredApprovingReviewCount *int  `json:"required_approving_review_count"`
+	AllowsForcePushes            *bool `json:"allows_force_pushes"`
+	// Rules are the active ruleset rules that apply to the branch
+	Rules []types.BranchRule `json:"rules"`
+}
+
+// ValidatePatterns verifies the branch name patterns (e.g. release/*) are valid
+func ValidatePatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid branch name pattern %s: %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Match returns whether the branch name matches any of the patterns ('*' doesn't match '/', like the branch protection patterns)
+func Match(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+package branches
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePatterns(t *testing.T) {
+	require.NoError(t, ValidatePatterns([]string{"release/*", "hotfix-*", "v[0-9]*"}))
+	require.Error(t, ValidatePatterns([]string{"release/["}))
+}
+
+func TestMatch(t *testing.T) {
+	patterns := []string{"release/*", "hotfix/*"}
+
+	require.True(t, Match(patterns, "release/1.0"))
+	require.True(t, Match(patterns, "hotfix/login"))
+	require.False(t, Match(patterns, "release/1.0/fix"))
+	require.False(t, Match(patterns, "feature/release"))
+	require.False(t, Match(nil, "main"))
+}
@@ -0,0 +1,125 @@
+package codecommit
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// The access levels of a principal to a repository
+const (
+	AccessNone  = ""
+	AccessRead  = "read"
+	AccessWrite = "write"
+	AccessAdmin = "admin"
+)
+
+var accessRanks = map[string]int{AccessNone: 0, AccessRead: 1, AccessWrite: 2, AccessAdmin: 3}
+
+// the actions that grant each access level
+var accessActions = []struct {
+	level  string
+	action string
+}{
+	{AccessAdmin, "codecommit:DeleteRepository"},
+	{AccessWrite, "codecommit:GitPush"},
+	{AccessRead, "codecommit:GitPull"},
+}
+
+// the access levels granted by the AWS managed policies (their documents aren't collected)
+var awsManagedPolicies = map[string]string{
+	"arn:aws:iam::aws:policy/AdministratorAccess":     AccessAdmin,
+	"arn:aws:iam::aws:policy/PowerUserAccess":         AccessAdmin,
+	"arn:aws:iam::aws:policy/AWSCodeCommitFullAccess": AccessAdmin,
+	"arn:aws:iam::aws:policy/AWSCodeCommitPowerUser":  AccessWrite,
+	"arn:aws:iam::aws:policy/AWSCodeCommitReadOnly":   AccessRead,
+}
+
+type policyDocument struct {
+	Statement oneOrMany[statement] `json:"Statement"`
+}
+
+type statement struct {
+	Effect   string            `json:"Effect"`
+	Action   oneOrMany[string] `json:"Action"`
+	Resource oneOrMany[string] `json:"Resource"`
+}
+
+// oneOrMany is a policy element that is either a single value or a list of values
+type oneOrMany[T any] []T
+
+func (o *oneOrMany[T]) UnmarshalJSON(data []byte) error {
+	var many []T
+	if err := json.Unmarshal(data, &many); err == nil {
+		*o = many
+		return nil
+	}
+	var one T
+	if err := json.Unmarshal(data, &one); err != nil {
+		return err
+	}
+	*o = []T{one}
+	return nil
+}
+
+// AccessLevel returns the highest access level the principal's policies grant to the repository.
+// Only the Allow statements with Action and Resource are evaluated: Deny statements, conditions, permissions boundaries
+// and service control policies aren't, so the result is an upper bound of the principal's access.
+func (d *AuthorizationDetails) AccessLevel(principal Principal, repositoryArn string) string {
+	level := AccessNone
+	raise := func(granted string) {
+		if accessRanks[granted] > accessRanks[level] {
+			level = granted
+		}
+	}
+
+	for _, document := range principal.InlinePolicies {
+		raise(documentAccessLevel(document, repositoryArn))
+	}
+	for _, arn := range principal.ManagedPolicies {
+		if granted, ok := awsManagedPolicies[arn]; ok {
+			raise(granted)
+		} else if document, ok := d.Policies[arn]; ok {
+			raise(documentAccessLevel(document, repositoryArn))
+		}
+	}
+
+	return level
+}
+
+func documentAccessLevel(document string, repositoryArn string) string {
+	var parsed policyDocument
+	if err := json.Unmarshal([]byte(document), &parsed); err != nil {
+		return AccessNone
+	}
+
+	level := AccessNone
+	for _, s := range parsed.Statement {
+		if s.Effect != "Allow" || !anyMatch(s.Resource, repositoryArn, false) {
+			continue
+		}
+		for _, a := range accessActions {
+			if accessRanks[a.level] > accessRanks[level] && anyMatch(s.Action, a.action, true) {
+				level = a.level
+			}
+		}
+	}
+	return level
+}
+
+// anyMatch returns whether the value matches any of the IAM patterns ('*' and '?' wildcards)
+func anyMatch(patterns []string, value string, ignoreCase bool) bool {
+	for _, pattern := range patterns {
+		expr := regexp.QuoteMeta(pattern)
+		expr = strings.ReplaceAll(expr, `\*`, ".*")
+		expr = strings.ReplaceAll(expr, `\?`, ".")
+		expr = "^" + expr + "$"
+		if ignoreCase {
+			expr = "(?i)" + expr
+		}
+		if matched, _ := regexp.MatchString(expr, value); matched {
+			return true
+		}
+	}
+	return false
+}
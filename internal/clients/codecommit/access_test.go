@@ -0,0 +1,33 @@
+package codecommit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessLevel(t *testing.T) {
+	repositoryArn := "arn:aws:codecommit:us-east-1:111111111111:payments"
+	details := &AuthorizationDetails{
+		Policies: map[string]string{
+			"arn:aws:iam::111111111111:policy/developers": `{"Statement": [
+				{"Effect": "Allow", "Action": ["codecommit:Git*"], "Resource": "arn:aws:codecommit:us-east-1:111111111111:pay*"},
+				{"Effect": "Allow", "Action": "codecommit:*", "Resource": "arn:aws:codecommit:us-east-1:111111111111:sandbox"}
+			]}`,
+		},
+	}
+
+	readOnly := Principal{InlinePolicies: []string{`{"Statement": {"Effect": "Allow", "Action": "codecommit:gitpull", "Resource": "*"}}`}}
+	require.Equal(t, AccessRead, details.AccessLevel(readOnly, repositoryArn))
+
+	developer := Principal{ManagedPolicies: []string{"arn:aws:iam::111111111111:policy/developers"}}
+	require.Equal(t, AccessWrite, details.AccessLevel(developer, repositoryArn))
+	require.Equal(t, AccessAdmin, details.AccessLevel(developer, "arn:aws:codecommit:us-east-1:111111111111:sandbox"))
+	require.Equal(t, AccessNone, details.AccessLevel(developer, "arn:aws:codecommit:us-east-1:111111111111:billing"))
+
+	admin := Principal{ManagedPolicies: []string{"arn:aws:iam::aws:policy/AdministratorAccess"}}
+	require.Equal(t, AccessAdmin, details.AccessLevel(admin, repositoryArn))
+
+	denied := Principal{InlinePolicies: []string{`{"Statement": {"Effect": "Deny", "Action": "*", "Resource": "*"}}`}}
+	require.Equal(t, AccessNone, details.AccessLevel(denied, repositoryArn))
+}
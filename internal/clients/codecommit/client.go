@@ -0,0 +1,325 @@
+package codecommit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/Legit-Labs/legitify/internal/common/permissions"
+	"github.com/Legit-Labs/legitify/internal/common/scm_type"
+	"github.com/Legit-Labs/legitify/internal/common/types"
+	"github.com/Legit-Labs/legitify/internal/metrics"
+	"github.com/Legit-Labs/legitify/internal/tracing"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/codecommit"
+	cctypes "github.com/aws/aws-sdk-go-v2/service/codecommit/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+)
+
+const (
+	// batchSize is the maximal number of repositories of a BatchGetRepositories request
+	batchSize = 25
+)
+
+// Client wraps the CodeCommit and IAM clients of the AWS SDK.
+// The credentials and the region are resolved by the default AWS configuration chain (environment, shared config and
+// credentials files, SSO, and the instance or task role).
+type Client struct {
+	context    context.Context
+	codecommit *codecommit.Client
+	iam        *iam.Client
+	region     string
+	accounts   []string
+
+	lock         sync.Mutex
+	repositories []*RepositoryMetadata
+	templates    map[string]*ApprovalRuleTemplate
+	authDetails  *AuthorizationDetails
+}
+
+// Region returns the region of the default AWS configuration (AWS_REGION, or the region of the AWS profile)
+func Region() string {
+	cfg, err := config.LoadDefaultConfig(context.Background())
+	if err != nil {
+		return ""
+	}
+	return cfg.Region
+}
+
+// Endpoint returns the CodeCommit endpoint of the region
+func Endpoint(region string) string {
+	return fmt.Sprintf("https://codecommit.%s.amazonaws.com", region)
+}
+
+// NewClient creates a CodeCommit client; the endpoint (e.g. of a VPC endpoint) defaults to the regional endpoint,
+// and accounts optionally filters the repositories by their AWS account IDs.
+func NewClient(ctx context.Context, endpoint string, accounts []string) (*Client, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the AWS configuration: %v", err)
+	}
+	if cfg.Region == "" {
+		return nil, fmt.Errorf("missing AWS region (set AWS_REGION, or the region of the AWS profile)")
+	}
+	// fails early, rather than on every request
+	if _, err = cfg.Credentials.Retrieve(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get the AWS credentials: %v", err)
+	}
+
+	// the API requests are traced and measured like the other SCMs' (the credentials providers keep the SDK's client,
+	// which applies AWS_CA_BUNDLE)
+	httpClient := &http.Client{Transport: tracing.NewTransport(metrics.NewTransport(http.DefaultTransport, scm_type.CodeCommit), scm_type.CodeCommit)}
+	codecommitClient := codecommit.NewFromConfig(cfg, func(o *codecommit.Options) {
+		o.HTTPClient = httpClient
+		if endpoint != "" {
+			o.EndpointResolver = codecommit.EndpointResolverFromURL(endpoint)
+		}
+	})
+	iamClient := iam.NewFromConfig(cfg, func(o *iam.Options) {
+		o.HTTPClient = httpClient
+	})
+
+	return &Client{
+		context:    ctx,
+		codecommit: codecommitClient,
+		iam:        iamClient,
+		region:     cfg.Region,
+		accounts:   accounts,
+		templates:  make(map[string]*ApprovalRuleTemplate),
+	}, nil
+}
+
+func (c *Client) Region() string {
+	return c.region
+}
+
+// RepositoryMetadata is the metadata of a repository, as returned by BatchGetRepositories
+type RepositoryMetadata struct {
+	AccountID      string
+	RepositoryID   string
+	RepositoryName string
+	Description    string
+	DefaultBranch  string
+	CloneURLHTTP   string
+	CloneURLSSH    string
+	Arn            string
+}
+
+func newRepositoryMetadata(r *cctypes.RepositoryMetadata) *RepositoryMetadata {
+	return &RepositoryMetadata{
+		AccountID:      aws.ToString(r.AccountId),
+		RepositoryID:   aws.ToString(r.RepositoryId),
+		RepositoryName: aws.ToString(r.RepositoryName),
+		Description:    aws.ToString(r.RepositoryDescription),
+		DefaultBranch:  aws.ToString(r.DefaultBranch),
+		CloneURLHTTP:   aws.ToString(r.CloneUrlHttp),
+		CloneURLSSH:    aws.ToString(r.CloneUrlSsh),
+		Arn:            aws.ToString(r.Arn),
+	}
+}
+
+// RepositoriesMetadata returns the metadata of the repositories of the region (filtered by the accounts)
+func (c *Client) RepositoriesMetadata() ([]*RepositoryMetadata, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.repositories != nil {
+		return c.repositories, nil
+	}
+
+	var names []string
+	paginator := codecommit.NewListRepositoriesPaginator(c.codecommit, &codecommit.ListRepositoriesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(c.context)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range page.Repositories {
+			names = append(names, aws.ToString(r.RepositoryName))
+		}
+	}
+
+	result := []*RepositoryMetadata{}
+	for start := 0; start < len(names); start += batchSize {
+		end := start + batchSize
+		if end > len(names) {
+			end = len(names)
+		}
+		output, err := c.codecommit.BatchGetRepositories(c.context, &codecommit.BatchGetRepositoriesInput{RepositoryNames: names[start:end]})
+		if err != nil {
+			return nil, err
+		}
+		for i := range output.Repositories {
+			r := newRepositoryMetadata(&output.Repositories[i])
+			if c.accountSelected(r.AccountID) {
+				result = append(result, r)
+			}
+		}
+	}
+
+	c.repositories = result
+	return result, nil
+}
+
+func (c *Client) accountSelected(account string) bool {
+	if len(c.accounts) == 0 {
+		return true
+	}
+	for _, selected := range c.accounts {
+		if selected == account {
+			return true
+		}
+	}
+	return false
+}
+
+// Repository returns the metadata of the repository
+func (c *Client) Repository(name string) (*RepositoryMetadata, error) {
+	output, err := c.codecommit.GetRepository(c.context, &codecommit.GetRepositoryInput{RepositoryName: aws.String(name)})
+	if err != nil {
+		return nil, err
+	}
+	if output.RepositoryMetadata == nil {
+		return nil, fmt.Errorf("missing metadata of repository %s", name)
+	}
+	return newRepositoryMetadata(output.RepositoryMetadata), nil
+}
+
+// ApprovalRuleTemplate is an approval rule template; its content is a JSON document with the approval rules
+type ApprovalRuleTemplate struct {
+	Name    string
+	Content string
+}
+
+// AssociatedApprovalRuleTemplates returns the approval rule templates associated with the repository
+func (c *Client) AssociatedApprovalRuleTemplates(repository string) ([]*ApprovalRuleTemplate, error) {
+	var names []string
+	paginator := codecommit.NewListAssociatedApprovalRuleTemplatesForRepositoryPaginator(c.codecommit,
+		&codecommit.ListAssociatedApprovalRuleTemplatesForRepositoryInput{RepositoryName: aws.String(repository)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(c.context)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, page.ApprovalRuleTemplateNames...)
+	}
+
+	result := []*ApprovalRuleTemplate{}
+	for _, name := range names {
+		template, err := c.approvalRuleTemplate(name)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, template)
+	}
+	return result, nil
+}
+
+// approvalRuleTemplate returns the template by its name (templates are shared by repositories, so they are cached)
+func (c *Client) approvalRuleTemplate(name string) (*ApprovalRuleTemplate, error) {
+	c.lock.Lock()
+	template, ok := c.templates[name]
+	c.lock.Unlock()
+	if ok {
+		return template, nil
+	}
+
+	output, err := c.codecommit.GetApprovalRuleTemplate(c.context, &codecommit.GetApprovalRuleTemplateInput{ApprovalRuleTemplateName: aws.String(name)})
+	if err != nil {
+		return nil, err
+	}
+	template = &ApprovalRuleTemplate{Name: name}
+	if output.ApprovalRuleTemplate != nil {
+		template.Content = aws.ToString(output.ApprovalRuleTemplate.ApprovalRuleTemplateContent)
+	}
+
+	c.lock.Lock()
+	c.templates[name] = template
+	c.lock.Unlock()
+	return template, nil
+}
+
+// Trigger is a repository trigger, which notifies an SNS topic or invokes a Lambda function on repository events
+type Trigger struct {
+	Name           string
+	DestinationArn string
+	Branches       []string
+	Events         []string
+}
+
+func (c *Client) RepositoryTriggers(repository string) ([]*Trigger, error) {
+	output, err := c.codecommit.GetRepositoryTriggers(c.context, &codecommit.GetRepositoryTriggersInput{RepositoryName: aws.String(repository)})
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*Trigger{}
+	for _, t := range output.Triggers {
+		trigger := &Trigger{
+			Name:           aws.ToString(t.Name),
+			DestinationArn: aws.ToString(t.DestinationArn),
+			Branches:       t.Branches,
+			Events:         []string{},
+		}
+		for _, event := range t.Events {
+			trigger.Events = append(trigger.Events, string(event))
+		}
+		result = append(result, trigger)
+	}
+	return result, nil
+}
+
+func (c *Client) IsAnalyzable(repo types.RepositoryWithOwner) (bool, error) {
+	metadata, err := c.Repository(repo.Name)
+	if err != nil {
+		return false, err
+	}
+	if metadata.AccountID != repo.Owner {
+		return false, fmt.Errorf("repository %s belongs to account %s", repo.Name, metadata.AccountID)
+	}
+	return true, nil
+}
+
+func (c *Client) Scopes() permissions.TokenScopes {
+	return permissions.TokenScopes{}
+}
+
+// Organizations returns the AWS accounts of the repositories
+func (c *Client) Organizations() ([]types.Organization, error) {
+	repositories, err := c.RepositoriesMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make(map[string]bool)
+	for _, r := range repositories {
+		accounts[r.AccountID] = true
+	}
+
+	var result []types.Organization
+	for account := range accounts {
+		result = append(result, types.Organization{Name: account, Role: permissions.OrgRoleOwner})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result, nil
+}
+
+// Repositories returns the repositories as <account id>/<repository name>
+func (c *Client) Repositories() ([]types.RepositoryWithOwner, error) {
+	repositories, err := c.RepositoriesMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []types.RepositoryWithOwner
+	for _, r := range repositories {
+		result = append(result, types.RepositoryWithOwner{Owner: r.AccountID, Name: r.RepositoryName, Role: permissions.RepoRoleAdmin})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,75 @@
+package codecommit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// the CodeCommit API of the test server, by the operation of the request target
+var testResponses = map[string]string{
+	"ListRepositories": `{"repositories": [{"repositoryName": "payments"}, {"repositoryName": "billing"}]}`,
+	"BatchGetRepositories": `{"repositories": [
+		{"accountId": "111111111111", "repositoryName": "payments", "defaultBranch": "main", "Arn": "arn:aws:codecommit:us-east-1:111111111111:payments"},
+		{"accountId": "222222222222", "repositoryName": "billing", "defaultBranch": "main", "Arn": "arn:aws:codecommit:us-east-1:222222222222:billing"}
+	]}`,
+	"GetRepositoryTriggers": `{"triggers": [{"name": "notify", "destinationArn": "arn:aws:sns:us-east-1:333333333333:builds", "events": ["all"]}]}`,
+}
+
+func TestClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Contains(t, r.Header.Get("Authorization"), "Credential=AKIDEXAMPLE/")
+		operation := strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "CodeCommit_20150413.")
+		response, ok := testResponses[operation]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			response = `{"__type": "InvalidOperation", "message": "unexpected operation"}`
+		}
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	// the default configuration chain reads the credentials and the region from the environment
+	dir := t.TempDir()
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY")
+	t.Setenv("AWS_REGION", "us-east-1")
+
+	client, err := NewClient(context.Background(), server.URL, []string{"111111111111"})
+	require.NoError(t, err)
+	require.Equal(t, "us-east-1", client.Region())
+
+	repositories, err := client.RepositoriesMetadata()
+	require.NoError(t, err)
+	require.Len(t, repositories, 1)
+	require.Equal(t, "payments", repositories[0].RepositoryName)
+	require.Equal(t, "arn:aws:codecommit:us-east-1:111111111111:payments", repositories[0].Arn)
+
+	triggers, err := client.RepositoryTriggers("payments")
+	require.NoError(t, err)
+	require.Equal(t, []*Trigger{{Name: "notify", DestinationArn: "arn:aws:sns:us-east-1:333333333333:builds", Events: []string{"all"}}}, triggers)
+
+	_, err = client.AssociatedApprovalRuleTemplates("payments")
+	require.ErrorContains(t, err, "unexpected operation")
+}
+
+func TestNewClientWithoutRegion(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+
+	_, err := NewClient(context.Background(), "", nil)
+	require.ErrorContains(t, err, "missing AWS region")
+}
@@ -0,0 +1,87 @@
+package codecommit
+
+import (
+	"net/url"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// Principal is an IAM user, group or role with its inline policy documents and its attached managed policies
+type Principal struct {
+	Type string
+	Name string
+	Arn  string
+	// InlinePolicies are the (decoded) inline policy documents
+	InlinePolicies []string
+	// ManagedPolicies are the ARNs of the attached managed policies
+	ManagedPolicies []string
+}
+
+// AuthorizationDetails are the IAM principals of the account and the default versions of its customer managed policies
+type AuthorizationDetails struct {
+	Principals []Principal
+	// Policies are the documents of the customer managed policies by their ARNs (AWS managed policies aren't included)
+	Policies map[string]string
+}
+
+// AuthorizationDetails returns the IAM authorization details of the account (requires iam:GetAccountAuthorizationDetails)
+func (c *Client) AuthorizationDetails() (*AuthorizationDetails, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.authDetails != nil {
+		return c.authDetails, nil
+	}
+
+	result := &AuthorizationDetails{Policies: make(map[string]string)}
+	paginator := iam.NewGetAccountAuthorizationDetailsPaginator(c.iam, &iam.GetAccountAuthorizationDetailsInput{
+		Filter: []iamtypes.EntityType{iamtypes.EntityTypeUser, iamtypes.EntityTypeGroup, iamtypes.EntityTypeRole, iamtypes.EntityTypeLocalManagedPolicy},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(c.context)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, u := range page.UserDetailList {
+			result.Principals = append(result.Principals, newPrincipal("user", u.UserName, u.Arn, u.UserPolicyList, u.AttachedManagedPolicies))
+		}
+		for _, g := range page.GroupDetailList {
+			result.Principals = append(result.Principals, newPrincipal("group", g.GroupName, g.Arn, g.GroupPolicyList, g.AttachedManagedPolicies))
+		}
+		for _, r := range page.RoleDetailList {
+			result.Principals = append(result.Principals, newPrincipal("role", r.RoleName, r.Arn, r.RolePolicyList, r.AttachedManagedPolicies))
+		}
+		for _, p := range page.Policies {
+			for _, v := range p.PolicyVersionList {
+				if v.IsDefaultVersion {
+					result.Policies[aws.ToString(p.Arn)] = decodeDocument(aws.ToString(v.Document))
+				}
+			}
+		}
+	}
+
+	c.authDetails = result
+	return result, nil
+}
+
+func newPrincipal(principalType string, name *string, arn *string, inline []iamtypes.PolicyDetail, managed []iamtypes.AttachedPolicy) Principal {
+	principal := Principal{Type: principalType, Name: aws.ToString(name), Arn: aws.ToString(arn)}
+	for _, policy := range inline {
+		principal.InlinePolicies = append(principal.InlinePolicies, decodeDocument(aws.ToString(policy.PolicyDocument)))
+	}
+	for _, policy := range managed {
+		principal.ManagedPolicies = append(principal.ManagedPolicies, aws.ToString(policy.PolicyArn))
+	}
+	return principal
+}
+
+// decodeDocument decodes a policy document, which is URL-encoded in the API responses
+func decodeDocument(document string) string {
+	decoded, err := url.PathUnescape(document)
+	if err != nil {
+		return document
+	}
+	return decoded
+}
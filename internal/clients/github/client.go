@@ -3,12 +3,19 @@ package github
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/Legit-Labs/legitify/internal/clients/github/types"
 	"github.com/Legit-Labs/legitify/internal/common/group_waiter"
+	"github.com/Legit-Labs/legitify/internal/common/scm_type"
 	commontypes "github.com/Legit-Labs/legitify/internal/common/types"
-	"log"
+	"github.com/Legit-Labs/legitify/internal/logger"
+	"github.com/Legit-Labs/legitify/internal/metrics"
+	"github.com/Legit-Labs/legitify/internal/token_source"
+	"github.com/Legit-Labs/legitify/internal/tracing"
 	"net/http"
+	"net/url"
 	"regexp"
 	"strings"
 	"sync"
@@ -34,17 +41,35 @@ type Client struct {
 	scopes           permissions.TokenScopes
 	graphQLRawClient *http.Client
 	serverUrl        string
+	serverVersion    string
 }
 
 func isBadRequest(err error) bool {
 	return err.Error() == "Bad credentials"
 }
 
-func newHttpClients(ctx context.Context, token string) (client *http.Client, graphQL *http.Client) {
-	ts := oauth2.StaticTokenSource(
+// refresherTokenSource reads the token from the refresher on every request (the refresher caches it until it expires)
+type refresherTokenSource struct {
+	refresher token_source.Refresher
+}
+
+func (s refresherTokenSource) Token() (*oauth2.Token, error) {
+	token, err := s.refresher.Token()
+	if err != nil {
+		return nil, err
+	}
+	return &oauth2.Token{AccessToken: token}, nil
+}
+
+func newHttpClients(ctx context.Context, token string, refresher token_source.Refresher) (client *http.Client, graphQL *http.Client) {
+	var ts oauth2.TokenSource = oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: token},
 	)
+	if refresher != nil {
+		ts = refresherTokenSource{refresher: refresher}
+	}
 	tc := oauth2.NewClient(ctx, ts)
+	tc.Transport = tracing.NewTransport(metrics.NewTransport(tc.Transport, scm_type.GitHub), scm_type.GitHub)
 
 	acceptHeader := experimentalApiAcceptHeader
 	clientWithAcceptHeader := NewClientWithAcceptHeader(tc.Transport, &acceptHeader)
@@ -52,14 +77,15 @@ func newHttpClients(ctx context.Context, token string) (client *http.Client, gra
 	return tc, clientWithAcceptHeader
 }
 
-func NewClient(ctx context.Context, token string, githubEndpoint string, org []string, fillCache bool) (*Client, error) {
+// NewClient creates a GitHub client; the optional refresher replaces the token when it expires during the run
+func NewClient(ctx context.Context, token string, githubEndpoint string, org []string, fillCache bool, refresher token_source.Refresher) (*Client, error) {
 	client := &Client{
 		orgs:      org,
 		context:   ctx,
 		serverUrl: strings.TrimRight(githubEndpoint, "/"),
 	}
 
-	if err := client.initClients(ctx, token); err != nil {
+	if err := client.initClients(ctx, token, refresher); err != nil {
 		return nil, err
 	}
 
@@ -76,9 +102,18 @@ func NewClient(ctx context.Context, token string, githubEndpoint string, org []s
 	}
 
 	if client.IsGithubCloud() {
-		log.Printf("Using Github Cloud")
+		logger.Infof("Using Github Cloud")
 	} else {
-		log.Printf("Using Github Enterprise Endpoint: %s\n\n", client.serverUrl)
+		version, err := client.detectServerVersion()
+		if err != nil {
+			// without the version, all the features are assumed to be available
+			logger.With(logger.Fields{"endpoint": client.serverUrl}).WithError(err).Warnf("failed to detect the GitHub Enterprise Server version")
+		}
+		client.serverVersion = version
+		logger.With(logger.Fields{"endpoint": client.serverUrl, "version": version}).Infof("Using Github Enterprise Endpoint")
+		if unsupported := client.UnsupportedFeatures(); len(unsupported) > 0 {
+			logger.With(logger.Fields{"version": version, "features": strings.Join(unsupported, ", ")}).Infof("features unavailable in this server version are skipped")
+		}
 	}
 
 	return client, nil
@@ -96,7 +131,7 @@ func (c *Client) IsGithubCloud() bool {
 	return c.serverUrl == ""
 }
 
-func (c *Client) initClients(ctx context.Context, token string) error {
+func (c *Client) initClients(ctx context.Context, token string, refresher token_source.Refresher) error {
 	if err := c.validateToken(token); err != nil {
 		return err
 	}
@@ -104,7 +139,7 @@ func (c *Client) initClients(ctx context.Context, token string) error {
 	var ghClient *gh.Client
 	var graphQLClient *githubv4.Client
 
-	rawClient, graphQLRawClient := newHttpClients(ctx, token)
+	rawClient, graphQLRawClient := newHttpClients(ctx, token, refresher)
 	if c.IsGithubCloud() {
 		ghClient = gh.NewClient(rawClient)
 		graphQLClient = githubv4.NewClient(graphQLRawClient)
@@ -335,7 +370,7 @@ func (c *Client) collectSpecificOrganizations() ([]githubcollected.ExtendedOrg,
 
 		role, err := c.getRole(*org.Login)
 		if err != nil {
-			log.Println(err.Error())
+			logger.With(logger.Fields{"org": *org.Login}).WithError(err).Errorf("failed to get the organization role")
 		} else {
 			res = append(res, githubcollected.NewExtendedOrg(org, role))
 		}
@@ -368,6 +403,619 @@ func (c *Client) GetActionsTokenPermissions(url string) (*types.TokenPermissions
 	return &p, nil
 }
 
+func (c *Client) GetActionsPermissionsForOrganization(organization string) (*types.ActionsPermissions, error) {
+	u := fmt.Sprintf("orgs/%s/actions/permissions", organization)
+	return c.getActionsPermissions(u)
+}
+
+func (c *Client) GetActionsPermissionsForRepository(organization string, repository string) (*types.ActionsPermissions, error) {
+	u := fmt.Sprintf("repos/%s/%s/actions/permissions", organization, repository)
+	return c.getActionsPermissions(u)
+}
+
+func (c *Client) getActionsPermissions(url string) (*types.ActionsPermissions, error) {
+	req, err := c.client.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	p := types.ActionsPermissions{}
+	_, err = c.client.Do(c.context, req, &p)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// GetForkPRContributorApprovalForOrganization returns which fork pull requests of the organization public repositories
+// require an approval to run workflows.
+func (c *Client) GetForkPRContributorApprovalForOrganization(organization string) (*types.ForkPRContributorApproval, error) {
+	return c.getForkPRContributorApproval(fmt.Sprintf("orgs/%s/actions/permissions/fork-pr-contributor-approval", organization))
+}
+
+// GetForkPRContributorApprovalForRepository returns which fork pull requests of the (public) repository
+// require an approval to run workflows.
+func (c *Client) GetForkPRContributorApprovalForRepository(owner string, repository string) (*types.ForkPRContributorApproval, error) {
+	return c.getForkPRContributorApproval(fmt.Sprintf("repos/%s/%s/actions/permissions/fork-pr-contributor-approval", owner, repository))
+}
+
+func (c *Client) getForkPRContributorApproval(url string) (*types.ForkPRContributorApproval, error) {
+	req, err := c.client.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	approval := types.ForkPRContributorApproval{}
+	_, err = c.client.Do(c.context, req, &approval)
+	if err != nil {
+		return nil, err
+	}
+	return &approval, nil
+}
+
+// GetOIDCSubjectClaimForOrganization returns the organization's OIDC subject claim template.
+func (c *Client) GetOIDCSubjectClaimForOrganization(organization string) (*types.OIDCSubjectClaim, error) {
+	return c.getOIDCSubjectClaim(fmt.Sprintf("orgs/%s/actions/oidc/customization/sub", organization))
+}
+
+// GetOIDCSubjectClaimForRepository returns the repository's OIDC subject claim template
+// (use_default means the repository uses the default, or its organization's, template).
+func (c *Client) GetOIDCSubjectClaimForRepository(owner string, repository string) (*types.OIDCSubjectClaim, error) {
+	return c.getOIDCSubjectClaim(fmt.Sprintf("repos/%s/%s/actions/oidc/customization/sub", owner, repository))
+}
+
+func (c *Client) getOIDCSubjectClaim(url string) (*types.OIDCSubjectClaim, error) {
+	req, err := c.client.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	claim := types.OIDCSubjectClaim{}
+	_, err = c.client.Do(c.context, req, &claim)
+	if err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
+
+// GetRepositoryCustomProperty returns the value of the repository custom property (empty if it isn't set).
+// Multi-select values are joined with ";".
+func (c *Client) GetRepositoryCustomProperty(owner string, repository string, property string) (string, error) {
+	u := fmt.Sprintf("repos/%s/%s/properties/values", owner, repository)
+	req, err := c.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return "", err
+	}
+
+	var values []struct {
+		PropertyName string          `json:"property_name"`
+		Value        json.RawMessage `json:"value"`
+	}
+	_, err = c.client.Do(c.context, req, &values)
+	if err != nil {
+		return "", err
+	}
+
+	for _, v := range values {
+		if v.PropertyName != property {
+			continue
+		}
+		var single string
+		if err = json.Unmarshal(v.Value, &single); err == nil {
+			return single, nil
+		}
+		var multi []string
+		if err = json.Unmarshal(v.Value, &multi); err == nil {
+			return strings.Join(multi, ";"), nil
+		}
+	}
+
+	return "", nil
+}
+
+// GetRequiredWorkflows lists the organization's required workflows (none if the feature isn't available).
+func (c *Client) GetRequiredWorkflows(org string) ([]types.RequiredWorkflow, error) {
+	var workflows []types.RequiredWorkflow
+
+	err := PaginateResults(func(opts *gh.ListOptions) (*gh.Response, error) {
+		u := fmt.Sprintf("orgs/%s/actions/required_workflows?per_page=100", org)
+		if opts.Page > 0 {
+			u += fmt.Sprintf("&page=%d", opts.Page)
+		}
+		req, err := c.client.NewRequest("GET", u, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var p struct {
+			RequiredWorkflows []types.RequiredWorkflow `json:"required_workflows"`
+		}
+		resp, err := c.client.Do(c.context, req, &p)
+		if err != nil {
+			return nil, err
+		}
+
+		workflows = append(workflows, p.RequiredWorkflows...)
+		return resp, nil
+	})
+	if isNotFound(err) {
+		return nil, nil
+	}
+
+	return workflows, err
+}
+
+// GetOrganizationRulesets returns the organization's rulesets, including their conditions and rules
+// (none if rulesets aren't available).
+func (c *Client) GetOrganizationRulesets(org string) ([]types.Ruleset, error) {
+	var summaries []types.Ruleset
+
+	err := PaginateResults(func(opts *gh.ListOptions) (*gh.Response, error) {
+		u := fmt.Sprintf("orgs/%s/rulesets?per_page=100", org)
+		if opts.Page > 0 {
+			u += fmt.Sprintf("&page=%d", opts.Page)
+		}
+		req, err := c.client.NewRequest("GET", u, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []types.Ruleset
+		resp, err := c.client.Do(c.context, req, &page)
+		if err != nil {
+			return nil, err
+		}
+
+		summaries = append(summaries, page...)
+		return resp, nil
+	})
+	if isNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	// the rulesets list doesn't include the conditions & rules
+	rulesets := make([]types.Ruleset, 0, len(summaries))
+	for _, summary := range summaries {
+		req, err := c.client.NewRequest("GET", fmt.Sprintf("orgs/%s/rulesets/%d", org, summary.ID), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var ruleset types.Ruleset
+		if _, err = c.client.Do(c.context, req, &ruleset); err != nil {
+			return nil, err
+		}
+		rulesets = append(rulesets, ruleset)
+	}
+
+	return rulesets, nil
+}
+
+// GetRepositoryRulesets returns the rulesets that apply to the repository (including the organization rulesets),
+// with their conditions, rules and bypass actors (none if rulesets aren't available).
+func (c *Client) GetRepositoryRulesets(owner string, repository string) ([]types.Ruleset, error) {
+	var summaries []types.Ruleset
+
+	err := PaginateResults(func(opts *gh.ListOptions) (*gh.Response, error) {
+		u := fmt.Sprintf("repos/%s/%s/rulesets?includes_parents=true&per_page=100", owner, repository)
+		if opts.Page > 0 {
+			u += fmt.Sprintf("&page=%d", opts.Page)
+		}
+		req, err := c.client.NewRequest("GET", u, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []types.Ruleset
+		resp, err := c.client.Do(c.context, req, &page)
+		if err != nil {
+			return nil, err
+		}
+
+		summaries = append(summaries, page...)
+		return resp, nil
+	})
+	if isNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	rulesets := make([]types.Ruleset, 0, len(summaries))
+	for _, summary := range summaries {
+		u := fmt.Sprintf("repos/%s/%s/rulesets/%d?includes_parents=true", owner, repository, summary.ID)
+		req, err := c.client.NewRequest("GET", u, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var ruleset types.Ruleset
+		if _, err = c.client.Do(c.context, req, &ruleset); err != nil {
+			return nil, err
+		}
+		rulesets = append(rulesets, ruleset)
+	}
+
+	return rulesets, nil
+}
+
+// GetBranchRules returns the active ruleset rules that apply to the branch (none if rulesets aren't available)
+func (c *Client) GetBranchRules(owner string, repository string, branch string) ([]types.BranchRule, error) {
+	var rules []types.BranchRule
+
+	err := PaginateResults(func(opts *gh.ListOptions) (*gh.Response, error) {
+		u := fmt.Sprintf("repos/%s/%s/rules/branches/%s?per_page=100", owner, repository, url.PathEscape(branch))
+		if opts.Page > 0 {
+			u += fmt.Sprintf("&page=%d", opts.Page)
+		}
+		req, err := c.client.NewRequest("GET", u, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []types.BranchRule
+		resp, err := c.client.Do(c.context, req, &page)
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, page...)
+		return resp, nil
+	})
+	if isNotFound(err) {
+		return nil, nil
+	}
+
+	return rules, err
+}
+
+// GetCodeownersErrors returns the errors of the CODEOWNERS file of the default branch, as detected by GitHub (none if there is no CODEOWNERS file)
+func (c *Client) GetCodeownersErrors(owner string, repository string) ([]types.CodeownersError, error) {
+	u := fmt.Sprintf("repos/%s/%s/codeowners/errors", owner, repository)
+	req, err := c.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var p struct {
+		Errors []types.CodeownersError `json:"errors"`
+	}
+	_, err = c.client.Do(c.context, req, &p)
+	if isNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p.Errors, nil
+}
+
+// GetTeamMembers returns the logins of the members of the organization team (including the members of its child teams)
+func (c *Client) GetTeamMembers(org string, slug string) ([]string, error) {
+	var members []string
+
+	err := PaginateResults(func(opts *gh.ListOptions) (*gh.Response, error) {
+		opts.PerPage = 100
+		users, resp, err := c.Client().Teams.ListTeamMembersBySlug(c.context, org, slug, &gh.TeamListTeamMembersOptions{ListOptions: *opts})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, user := range users {
+			members = append(members, user.GetLogin())
+		}
+		return resp, nil
+	})
+
+	return members, err
+}
+
+// GetTeamSlugs returns the slugs of the organization teams, by their ids
+func (c *Client) GetTeamSlugs(org string) (map[int64]string, error) {
+	slugs := make(map[int64]string)
+
+	err := PaginateResults(func(opts *gh.ListOptions) (*gh.Response, error) {
+		opts.PerPage = 100
+		teams, resp, err := c.Client().Teams.ListTeams(c.context, org, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, team := range teams {
+			slugs[team.GetID()] = team.GetSlug()
+		}
+		return resp, nil
+	})
+
+	return slugs, err
+}
+
+// GetCustomRepositoryRoles returns the organization custom repository roles (available for enterprise organizations)
+func (c *Client) GetCustomRepositoryRoles(org string) ([]types.CustomRepositoryRole, error) {
+	u := fmt.Sprintf("orgs/%s/custom-repository-roles", org)
+	req, err := c.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var p struct {
+		CustomRoles []types.CustomRepositoryRole `json:"custom_roles"`
+	}
+	_, err = c.client.Do(c.context, req, &p)
+	if err != nil {
+		return nil, err
+	}
+	return p.CustomRoles, nil
+}
+
+// GetSecurityManagerTeams returns the teams that are assigned the security manager role of the organization
+func (c *Client) GetSecurityManagerTeams(org string) ([]*gh.Team, error) {
+	u := fmt.Sprintf("orgs/%s/security-managers", org)
+	req, err := c.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var teams []*gh.Team
+	_, err = c.client.Do(c.context, req, &teams)
+	if err != nil {
+		return nil, err
+	}
+	return teams, nil
+}
+
+// GetOrganizationCodespaces returns the codespaces of the organization repositories
+func (c *Client) GetOrganizationCodespaces(org string) ([]types.Codespace, error) {
+	var codespaces []types.Codespace
+
+	err := PaginateResults(func(opts *gh.ListOptions) (*gh.Response, error) {
+		u := fmt.Sprintf("orgs/%s/codespaces?per_page=100", org)
+		if opts.Page > 0 {
+			u += fmt.Sprintf("&page=%d", opts.Page)
+		}
+		req, err := c.client.NewRequest("GET", u, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var p struct {
+			Codespaces []types.Codespace `json:"codespaces"`
+		}
+		resp, err := c.client.Do(c.context, req, &p)
+		if err != nil {
+			return nil, err
+		}
+
+		codespaces = append(codespaces, p.Codespaces...)
+		return resp, nil
+	})
+
+	return codespaces, err
+}
+
+// GetOrganizationCodespacesSecrets returns the organization codespaces secrets (without their values)
+func (c *Client) GetOrganizationCodespacesSecrets(org string) ([]*gh.Secret, error) {
+	var secrets []*gh.Secret
+
+	err := PaginateResults(func(opts *gh.ListOptions) (*gh.Response, error) {
+		u := fmt.Sprintf("orgs/%s/codespaces/secrets?per_page=100", org)
+		if opts.Page > 0 {
+			u += fmt.Sprintf("&page=%d", opts.Page)
+		}
+		req, err := c.client.NewRequest("GET", u, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var p gh.Secrets
+		resp, err := c.client.Do(c.context, req, &p)
+		if err != nil {
+			return nil, err
+		}
+
+		secrets = append(secrets, p.Secrets...)
+		return resp, nil
+	})
+
+	return secrets, err
+}
+
+// GetAuditLogEntries returns the organization audit log events (web & git) that match the search phrase, from the most recent
+// (the audit log is only available for enterprise organizations)
+func (c *Client) GetAuditLogEntries(org string, phrase string) ([]types.AuditLogEntry, error) {
+	return c.GetRecentAuditLogEntries(org, phrase, 0)
+}
+
+// GetRecentAuditLogEntries returns up to limit of the most recent organization audit log events that match the search phrase,
+// and stops paging once it has them (a limit of 0 returns all of them)
+func (c *Client) GetRecentAuditLogEntries(org string, phrase string, limit int) ([]types.AuditLogEntry, error) {
+	var result []types.AuditLogEntry
+	after := ""
+	perPage := 100
+	if limit > 0 && limit < perPage {
+		perPage = limit
+	}
+
+	for {
+		u := fmt.Sprintf("orgs/%s/audit-log?phrase=%s&include=all&per_page=%d&order=desc", org, url.QueryEscape(phrase), perPage)
+		if after != "" {
+			u += "&after=" + url.QueryEscape(after)
+		}
+		req, err := c.client.NewRequest("GET", u, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var entries []types.AuditLogEntry
+		resp, err := c.client.Do(c.context, req, &entries)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, entries...)
+
+		if limit > 0 && len(result) >= limit {
+			return result[:limit], nil
+		}
+		if resp.After == "" {
+			return result, nil
+		}
+		after = resp.After
+	}
+}
+
+func isNotFound(err error) bool {
+	var errResp *gh.ErrorResponse
+	return errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound
+}
+
+// GetSecurityAndAnalysis returns the repository's GitHub Advanced Security features status
+// (including features that are not yet available in the go-github Repository struct).
+func (c *Client) GetSecurityAndAnalysis(owner string, repository string) (*types.SecurityAndAnalysis, error) {
+	u := fmt.Sprintf("repos/%s/%s", owner, repository)
+	req, err := c.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var p struct {
+		SecurityAndAnalysis *types.SecurityAndAnalysis `json:"security_and_analysis"`
+	}
+	_, err = c.client.Do(c.context, req, &p)
+	if err != nil {
+		return nil, err
+	}
+	return p.SecurityAndAnalysis, nil
+}
+
+// GetRepositoriesSecurityAndAnalysis lists the organization repositories with their GitHub Advanced Security features status.
+func (c *Client) GetRepositoriesSecurityAndAnalysis(org string) ([]types.RepositorySecurityAndAnalysis, error) {
+	var repositories []types.RepositorySecurityAndAnalysis
+
+	err := PaginateResults(func(opts *gh.ListOptions) (*gh.Response, error) {
+		u := fmt.Sprintf("orgs/%s/repos?per_page=100", org)
+		if opts.Page > 0 {
+			u += fmt.Sprintf("&page=%d", opts.Page)
+		}
+		req, err := c.client.NewRequest("GET", u, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []types.RepositorySecurityAndAnalysis
+		resp, err := c.client.Do(c.context, req, &page)
+		if err != nil {
+			return nil, err
+		}
+
+		repositories = append(repositories, page...)
+		return resp, nil
+	})
+
+	return repositories, err
+}
+
+// GetRepositorySelfHostedRunners lists the self-hosted runners registered to the repository.
+func (c *Client) GetRepositorySelfHostedRunners(owner string, repository string) ([]types.SelfHostedRunner, error) {
+	return c.listSelfHostedRunners(fmt.Sprintf("repos/%s/%s/actions/runners", owner, repository))
+}
+
+// GetRunnerGroupRunners lists the self-hosted runners of the organization runner group.
+func (c *Client) GetRunnerGroupRunners(org string, groupID int64) ([]types.SelfHostedRunner, error) {
+	return c.listSelfHostedRunners(fmt.Sprintf("orgs/%s/actions/runner-groups/%d/runners", org, groupID))
+}
+
+// GetRunnerGroupWorkflowRestrictions returns the workflows the runner group is restricted to (not exposed by go-github).
+func (c *Client) GetRunnerGroupWorkflowRestrictions(org string, groupID int64) (*types.RunnerGroupWorkflowRestrictions, error) {
+	u := fmt.Sprintf("orgs/%s/actions/runner-groups/%d", org, groupID)
+	req, err := c.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var p types.RunnerGroupWorkflowRestrictions
+	_, err = c.client.Do(c.context, req, &p)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// GetRunnerGroupRepositoriesCount returns the number of repositories selected to use the runner group.
+func (c *Client) GetRunnerGroupRepositoriesCount(org string, groupID int64) (int, error) {
+	repos, _, err := c.client.Actions.ListRepositoryAccessRunnerGroup(c.context, org, groupID, &gh.ListOptions{PerPage: 1})
+	if err != nil {
+		return 0, err
+	}
+	return repos.GetTotalCount(), nil
+}
+
+// listSelfHostedRunners lists the runners of the given runners API path.
+// go-github doesn't expose whether the runners are ephemeral, so the API is called directly.
+func (c *Client) listSelfHostedRunners(path string) ([]types.SelfHostedRunner, error) {
+	var runners []types.SelfHostedRunner
+
+	err := PaginateResults(func(opts *gh.ListOptions) (*gh.Response, error) {
+		u := fmt.Sprintf("%s?per_page=100", path)
+		if opts.Page > 0 {
+			u += fmt.Sprintf("&page=%d", opts.Page)
+		}
+		req, err := c.client.NewRequest("GET", u, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var p struct {
+			Runners []types.SelfHostedRunner `json:"runners"`
+		}
+		resp, err := c.client.Do(c.context, req, &p)
+		if err != nil {
+			return nil, err
+		}
+
+		runners = append(runners, p.Runners...)
+		return resp, nil
+	})
+
+	return runners, err
+}
+
+// IsCodeScanningEnabled checks whether the repository has any code scanning analysis.
+// IsDependencyGraphEnabled returns whether the repository's dependency graph is enabled, by requesting its SBOM
+// (which fails with a dependency graph specific error when it's disabled).
+func (c *Client) IsDependencyGraphEnabled(owner string, repository string) (bool, error) {
+	req, err := c.client.NewRequest("GET", fmt.Sprintf("repos/%s/%s/dependency-graph/sbom", owner, repository), nil)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = c.client.Do(c.context, req, nil)
+	if err != nil {
+		var errResp *gh.ErrorResponse
+		if errors.As(err, &errResp) && isNotFound(err) && strings.Contains(strings.ToLower(errResp.Message), "dependency graph") {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (c *Client) IsCodeScanningEnabled(owner string, repository string) (bool, error) {
+	opts := &gh.AnalysesListOptions{ListOptions: gh.ListOptions{PerPage: 1}}
+	analyses, resp, err := c.client.CodeScanning.ListAnalysesForRepo(c.context, owner, repository, opts)
+	if err != nil {
+		// no analysis found (404) or advanced security is disabled for the repository (403)
+		if resp != nil && (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusForbidden) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return len(analyses) > 0, nil
+}
+
 func (c *Client) IsAnalyzable(repository commontypes.RepositoryWithOwner) (bool, error) {
 	var repo struct {
 		Repository struct {
@@ -0,0 +1,124 @@
+package query_cost
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MaxNodes is the maximal number of nodes GitHub allows a single graphql query to return
+	MaxNodes = 500000
+	// MaxPageSize is the maximal page size (first/last) of a graphql connection
+	MaxPageSize = 100
+)
+
+var pageSizeArgument = regexp.MustCompile(`\b(?:first|last)\s*:\s*(\$?\w+)`)
+
+// Estimate is the cost of a graphql query, precomputed from its shape (as GitHub calculates it)
+type Estimate struct {
+	// Nodes is the maximal number of nodes the query may return
+	Nodes int
+	// Requests is the number of requests needed to fulfill each of the connections of the query
+	Requests int
+	// Cost is the rate limit points the query costs: the requests divided by 100 (at least 1)
+	Cost int
+}
+
+// Of precomputes the cost of a githubv4 query struct; the page sizes are either literals (first: 50)
+// or variables (first: $pageSize) that are looked up in the query variables.
+func Of(query interface{}, variables map[string]interface{}) (Estimate, error) {
+	var estimate Estimate
+	if err := walk(reflect.TypeOf(query), 1, variables, &estimate, map[reflect.Type]bool{}); err != nil {
+		return Estimate{}, err
+	}
+
+	estimate.Cost = int(math.Round(float64(estimate.Requests) / 100))
+	if estimate.Cost < 1 {
+		estimate.Cost = 1
+	}
+	return estimate, nil
+}
+
+// walk counts the connections of the type; parents is the maximal number of objects the type is fetched for
+func walk(t reflect.Type, parents int, variables map[string]interface{}, estimate *Estimate, visiting map[reflect.Type]bool) error {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || visiting[t] {
+		return nil
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		objects := parents
+		pageSize, isConnection, err := connectionPageSize(field.Tag.Get("graphql"), variables)
+		if err != nil {
+			return fmt.Errorf("%s: %v", field.Name, err)
+		}
+		if isConnection {
+			estimate.Requests += parents
+			objects = parents * pageSize
+			estimate.Nodes += objects
+		}
+
+		if err = walk(field.Type, objects, variables, estimate, visiting); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func connectionPageSize(tag string, variables map[string]interface{}) (int, bool, error) {
+	match := pageSizeArgument.FindStringSubmatch(tag)
+	if match == nil {
+		return 0, false, nil
+	}
+
+	value := match[1]
+	if strings.HasPrefix(value, "$") {
+		variable, ok := variables[strings.TrimPrefix(value, "$")]
+		if !ok {
+			return 0, false, fmt.Errorf("missing page size variable %s", value)
+		}
+		value = fmt.Sprint(variable)
+	}
+
+	pageSize, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid page size %s", value)
+	}
+	return pageSize, true, nil
+}
+
+// PageSize returns the largest page size (up to maxPageSize) of the page size variable,
+// for which the query returns at most maxNodes nodes.
+func PageSize(query interface{}, variables map[string]interface{}, variable string, maxPageSize int, maxNodes int) (int, error) {
+	withPageSize := make(map[string]interface{}, len(variables)+1)
+	for k, v := range variables {
+		withPageSize[k] = v
+	}
+
+	for pageSize := maxPageSize; pageSize > 1; pageSize-- {
+		withPageSize[variable] = pageSize
+		estimate, err := Of(query, withPageSize)
+		if err != nil {
+			return 0, err
+		}
+		if estimate.Nodes <= maxNodes {
+			return pageSize, nil
+		}
+	}
+
+	return 1, nil
+}
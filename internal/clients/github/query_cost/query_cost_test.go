@@ -0,0 +1,46 @@
+package query_cost
+
+import (
+	"testing"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/require"
+)
+
+type topicsQuery struct {
+	Organization struct {
+		Repositories struct {
+			Nodes []struct {
+				Name   string
+				Topics struct {
+					Nodes []struct {
+						Name string
+					}
+				} `graphql:"repositoryTopics(first: 20)"`
+			}
+		} `graphql:"repositories(first: $pageSize, after: $cursor)"`
+	} `graphql:"organization(login: $login)"`
+}
+
+func TestOf(t *testing.T) {
+	estimate, err := Of(topicsQuery{}, map[string]interface{}{"pageSize": githubv4.Int(50)})
+	require.NoError(t, err)
+	require.Equal(t, Estimate{Nodes: 50 + 50*20, Requests: 1 + 50, Cost: 1}, estimate)
+
+	estimate, err = Of(topicsQuery{}, map[string]interface{}{"pageSize": 300})
+	require.NoError(t, err)
+	require.Equal(t, 3, estimate.Cost)
+
+	_, err = Of(topicsQuery{}, nil)
+	require.Error(t, err)
+}
+
+func TestPageSize(t *testing.T) {
+	pageSize, err := PageSize(topicsQuery{}, nil, "pageSize", MaxPageSize, 1000)
+	require.NoError(t, err)
+	require.Equal(t, 47, pageSize) // 47 * 21 nodes
+
+	pageSize, err = PageSize(topicsQuery{}, nil, "pageSize", MaxPageSize, MaxNodes)
+	require.NoError(t, err)
+	require.Equal(t, MaxPageSize, pageSize)
+}
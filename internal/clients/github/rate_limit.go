@@ -0,0 +1,72 @@
+package github
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	gh "github.com/google/go-github/v44/github"
+	"github.com/shurcooL/githubv4"
+)
+
+// RateLimit is the request quota of the token in the current rate limit window
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// Viewer returns the login of the user the token belongs to
+func (c *Client) Viewer() (string, error) {
+	user, _, err := c.Client().Users.Get(c.context, "")
+	if err != nil {
+		return "", err
+	}
+	return user.GetLogin(), nil
+}
+
+// RESTRateLimit returns the rate limit of the REST API, or nil if the server doesn't limit the rate
+// (GitHub Enterprise Server with rate limiting disabled)
+func (c *Client) RESTRateLimit() (*RateLimit, error) {
+	limits, _, err := c.Client().RateLimits(c.context)
+	if err != nil {
+		var ghErr *gh.ErrorResponse
+		if errors.As(err, &ghErr) && ghErr.Response.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if limits.Core == nil {
+		return nil, nil
+	}
+
+	return &RateLimit{
+		Limit:     limits.Core.Limit,
+		Remaining: limits.Core.Remaining,
+		Reset:     limits.Core.Reset.Time,
+	}, nil
+}
+
+// GraphQLRateLimit returns the rate limit of the GraphQL API (or nil if the server doesn't limit the rate),
+// and fails if the GraphQL API is unavailable
+func (c *Client) GraphQLRateLimit() (*RateLimit, error) {
+	var query struct {
+		RateLimit *struct {
+			Limit     githubv4.Int
+			Remaining githubv4.Int
+			ResetAt   githubv4.DateTime
+		}
+	}
+	if err := c.GraphQLClient().Query(c.context, &query, nil); err != nil {
+		return nil, err
+	}
+	if query.RateLimit == nil {
+		return nil, nil
+	}
+
+	return &RateLimit{
+		Limit:     int(query.RateLimit.Limit),
+		Remaining: int(query.RateLimit.Remaining),
+		Reset:     query.RateLimit.ResetAt.Time,
+	}, nil
+}
@@ -1,6 +1,192 @@
 package types
 
+import "fmt"
+
 type TokenPermissions struct {
 	DefaultWorkflowPermissions   *string `json:"default_workflow_permissions,omitempty"`
 	CanApprovePullRequestReviews *bool   `json:"can_approve_pull_request_reviews,omitempty"`
 }
+
+// ActionsPermissions is the Actions permissions policy of an organization or a repository
+// (including the SHA pinning requirement, which is not yet available in go-github)
+type ActionsPermissions struct {
+	// Enabled is set for repositories only
+	Enabled             *bool   `json:"enabled,omitempty"`
+	EnabledRepositories *string `json:"enabled_repositories,omitempty"`
+	// AllowedActions is one of: all, local_only, selected
+	AllowedActions     *string `json:"allowed_actions,omitempty"`
+	SelectedActionsURL *string `json:"selected_actions_url,omitempty"`
+	ShaPinningRequired *bool   `json:"sha_pinning_required,omitempty"`
+}
+
+// ForkPRContributorApproval is the policy of the fork pull requests workflows that require an approval to run
+type ForkPRContributorApproval struct {
+	// ApprovalPolicy is one of: first_time_contributors_new_to_github, first_time_contributors, all_external_contributors
+	ApprovalPolicy *string `json:"approval_policy,omitempty"`
+}
+
+// OIDCSubjectClaim is the template of the 'sub' claim of the Actions OIDC tokens
+type OIDCSubjectClaim struct {
+	UseDefault       *bool    `json:"use_default,omitempty"`
+	IncludeClaimKeys []string `json:"include_claim_keys"`
+}
+
+type SecurityAndAnalysisStatus struct {
+	Status *string `json:"status,omitempty"`
+}
+
+type SecurityAndAnalysis struct {
+	AdvancedSecurity             *SecurityAndAnalysisStatus `json:"advanced_security,omitempty"`
+	SecretScanning               *SecurityAndAnalysisStatus `json:"secret_scanning,omitempty"`
+	SecretScanningPushProtection *SecurityAndAnalysisStatus `json:"secret_scanning_push_protection,omitempty"`
+	DependabotSecurityUpdates    *SecurityAndAnalysisStatus `json:"dependabot_security_updates,omitempty"`
+}
+
+// RepositorySecurityAndAnalysis is a repository of the organization repositories list, with its security and analysis settings
+// (which are listed for the repositories the viewer administers only)
+type RepositorySecurityAndAnalysis struct {
+	Name                string               `json:"name"`
+	Private             bool                 `json:"private"`
+	Archived            bool                 `json:"archived"`
+	SecurityAndAnalysis *SecurityAndAnalysis `json:"security_and_analysis"`
+}
+
+type RunnerLabel struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// SelfHostedRunner is a self-hosted runner registered to a repository
+type SelfHostedRunner struct {
+	ID     int64         `json:"id"`
+	Name   string        `json:"name"`
+	OS     string        `json:"os"`
+	Status string        `json:"status"`
+	Busy   bool          `json:"busy"`
+	Labels []RunnerLabel `json:"labels"`
+	// Ephemeral runners are removed after running a single job
+	Ephemeral bool `json:"ephemeral"`
+}
+
+type RunnerGroupWorkflowRestrictions struct {
+	RestrictedToWorkflows *bool    `json:"restricted_to_workflows,omitempty"`
+	SelectedWorkflows     []string `json:"selected_workflows,omitempty"`
+}
+
+// RequiredWorkflow is an organization workflow that is required to pass on the pull requests of its repositories
+// (the Actions required workflows, superseded by the rulesets workflows rule)
+type RequiredWorkflow struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// Scope is either "all" or "selected" repositories
+	Scope string `json:"scope"`
+	Ref   string `json:"ref,omitempty"`
+	State string `json:"state,omitempty"`
+}
+
+// Ruleset is a repository ruleset (of a repository or an organization)
+type Ruleset struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Target string `json:"target,omitempty"`
+	// Enforcement is one of: active, evaluate, disabled
+	Enforcement string             `json:"enforcement"`
+	Conditions  *RulesetConditions `json:"conditions,omitempty"`
+	Rules       []RulesetRule      `json:"rules"`
+	// SourceType is either Repository or Organization
+	SourceType string `json:"source_type,omitempty"`
+	Source     string `json:"source,omitempty"`
+	// BypassActors are only visible to the users that can edit the ruleset
+	BypassActors []RulesetBypassActor `json:"bypass_actors,omitempty"`
+}
+
+// RulesetBypassActor may bypass the ruleset rules, either always or only through pull requests
+type RulesetBypassActor struct {
+	ActorID *int64 `json:"actor_id,omitempty"`
+	// ActorType is one of: RepositoryRole, Team, Integration, OrganizationAdmin
+	ActorType  string `json:"actor_type"`
+	BypassMode string `json:"bypass_mode"`
+}
+
+type RulesetConditions struct {
+	RefName        *RulesetNameCondition `json:"ref_name,omitempty"`
+	RepositoryName *RulesetNameCondition `json:"repository_name,omitempty"`
+}
+
+// RulesetNameCondition includes and excludes names by patterns (or the special ~ALL and ~DEFAULT_BRANCH values)
+type RulesetNameCondition struct {
+	Include []string `json:"include"`
+	Exclude []string `json:"exclude"`
+}
+
+type RulesetRule struct {
+	Type       string                 `json:"type"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+}
+
+// BranchRule is an active ruleset rule that applies to a branch
+type BranchRule struct {
+	RulesetRule
+	RulesetSourceType string `json:"ruleset_source_type"`
+	RulesetSource     string `json:"ruleset_source"`
+	RulesetID         int64  `json:"ruleset_id"`
+}
+
+// CodeownersError is an error of the CODEOWNERS file detected by GitHub (e.g. an invalid pattern or an unknown owner)
+type CodeownersError struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Kind    string `json:"kind"`
+	Source  string `json:"source"`
+	Message string `json:"message"`
+	Path    string `json:"path"`
+}
+
+// AuditLogEntry is an organization audit log event (with the fields that aren't yet available in the go-github AuditEntry struct)
+type AuditLogEntry struct {
+	Action string `json:"action"`
+	Actor  string `json:"actor"`
+	// Timestamp is in milliseconds since the epoch
+	Timestamp            int64  `json:"@timestamp"`
+	OAuthApplication     string `json:"oauth_application"`
+	OAuthApplicationName string `json:"oauth_application_name"`
+	OAuthApplicationID   int64  `json:"oauth_application_id"`
+}
+
+// OAuthApplicationDisplayName returns the name of the OAuth application the event refers to (its id if the name is missing)
+func (e AuditLogEntry) OAuthApplicationDisplayName() string {
+	switch {
+	case e.OAuthApplicationName != "":
+		return e.OAuthApplicationName
+	case e.OAuthApplication != "":
+		return e.OAuthApplication
+	}
+	return fmt.Sprintf("%d", e.OAuthApplicationID)
+}
+
+// CustomRepositoryRole is an organization custom repository role: a base role with additional fine-grained permissions
+type CustomRepositoryRole struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// BaseRole is one of: read, triage, write, maintain
+	BaseRole    string   `json:"base_role"`
+	Permissions []string `json:"permissions"`
+}
+
+// Codespace is a codespace of an organization repository
+type Codespace struct {
+	Name  string `json:"name"`
+	State string `json:"state"`
+	Owner struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+	Repository struct {
+		FullName string `json:"full_name"`
+		Private  bool   `json:"private"`
+	} `json:"repository"`
+	Machine *struct {
+		Name string `json:"name"`
+	} `json:"machine"`
+}
@@ -0,0 +1,122 @@
+package github
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Feature is an API feature that older GitHub Enterprise Server versions don't have
+type Feature string
+
+const (
+	FeatureRulesets                        Feature = "rulesets"
+	FeatureOrganizationRulesets            Feature = "organization_rulesets"
+	FeatureCustomProperties                Feature = "custom_properties"
+	FeatureCodespaces                      Feature = "codespaces"
+	FeatureSecretScanningPushProtection    Feature = "secret_scanning_push_protection"
+	FeatureRunnerGroupWorkflowRestrictions Feature = "runner_group_workflow_restrictions"
+	FeatureOIDCSubjectClaim                Feature = "oidc_subject_claim"
+	FeatureSecurityManagers                Feature = "security_managers"
+	FeatureDependencyGraphSBOM             Feature = "dependency_graph_sbom"
+	FeatureCustomRepositoryRoles           Feature = "custom_repository_roles"
+	FeatureForkPRContributorApproval       Feature = "fork_pr_contributor_approval"
+	FeatureInteractionLimits               Feature = "interaction_limits"
+	FeatureWebhookDeliveries               Feature = "webhook_deliveries"
+	FeatureProjectsV2                      Feature = "projects_v2"
+	FeatureIPAllowList                     Feature = "ip_allow_list"
+	FeatureSamlSSOIdentities               Feature = "saml_sso_identities"
+)
+
+// featureMinimalVersions are the GitHub Enterprise Server versions that introduced the features
+// (an empty version means the feature is only available on GitHub Cloud)
+var featureMinimalVersions = map[Feature]string{
+	FeatureRulesets:                        "3.10",
+	FeatureOrganizationRulesets:            "3.11",
+	FeatureCustomProperties:                "3.12",
+	FeatureCodespaces:                      "",
+	FeatureSecretScanningPushProtection:    "3.5",
+	FeatureRunnerGroupWorkflowRestrictions: "3.6",
+	FeatureOIDCSubjectClaim:                "3.7",
+	FeatureSecurityManagers:                "3.7",
+	FeatureDependencyGraphSBOM:             "3.9",
+	FeatureCustomRepositoryRoles:           "3.10",
+	FeatureForkPRContributorApproval:       "",
+	FeatureInteractionLimits:               "",
+	FeatureWebhookDeliveries:               "3.2",
+	FeatureProjectsV2:                      "3.7",
+	FeatureIPAllowList:                     "",
+	FeatureSamlSSOIdentities:               "",
+}
+
+// ServerVersion returns the GitHub Enterprise Server version (empty for GitHub Cloud, or if it couldn't be detected)
+func (c *Client) ServerVersion() string {
+	return c.serverVersion
+}
+
+// IsFeatureSupported checks whether the server version has the feature (assumed if the version couldn't be detected)
+func (c *Client) IsFeatureSupported(feature Feature) bool {
+	if c.IsGithubCloud() || c.serverVersion == "" {
+		return true
+	}
+	minimal, ok := featureMinimalVersions[feature]
+	if !ok {
+		return true
+	}
+	if minimal == "" {
+		return false
+	}
+	return versionAtLeast(c.serverVersion, minimal)
+}
+
+// UnsupportedFeatures returns the features the server version doesn't have
+func (c *Client) UnsupportedFeatures() []string {
+	var result []string
+	for feature := range featureMinimalVersions {
+		if !c.IsFeatureSupported(feature) {
+			result = append(result, string(feature))
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// detectServerVersion reads the installed version from the GitHub Enterprise Server meta endpoint
+func (c *Client) detectServerVersion() (string, error) {
+	req, err := c.client.NewRequest("GET", "meta", nil)
+	if err != nil {
+		return "", err
+	}
+
+	var meta struct {
+		InstalledVersion string `json:"installed_version"`
+	}
+	resp, err := c.client.Do(c.context, req, &meta)
+	if err != nil {
+		return "", err
+	}
+	if meta.InstalledVersion != "" {
+		return meta.InstalledVersion, nil
+	}
+
+	// older versions only report it in a response header (e.g. "enterprise-server@3.9.4")
+	header := resp.Header.Get("X-GitHub-Enterprise-Version")
+	return strings.TrimPrefix(header, "enterprise-server@"), nil
+}
+
+// versionAtLeast compares dotted versions numerically (e.g. "3.9.4" < "3.10")
+func versionAtLeast(version string, minimal string) bool {
+	actual := strings.Split(version, ".")
+	expected := strings.Split(minimal, ".")
+	for i, part := range expected {
+		if i >= len(actual) {
+			return false
+		}
+		a, _ := strconv.Atoi(actual[i])
+		e, _ := strconv.Atoi(part)
+		if a != e {
+			return a > e
+		}
+	}
+	return true
+}
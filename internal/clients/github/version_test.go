@@ -0,0 +1,42 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionAtLeast(t *testing.T) {
+	require.True(t, versionAtLeast("3.10.2", "3.10"))
+	require.True(t, versionAtLeast("3.11", "3.10"))
+	require.True(t, versionAtLeast("4.0.0", "3.12"))
+	require.False(t, versionAtLeast("3.9.4", "3.10"))
+	require.False(t, versionAtLeast("3", "3.10"))
+}
+
+func TestIsFeatureSupported(t *testing.T) {
+	cloud := &Client{}
+	require.True(t, cloud.IsFeatureSupported(FeatureCodespaces))
+
+	undetected := &Client{serverUrl: "https://github.example.com"}
+	require.True(t, undetected.IsFeatureSupported(FeatureRulesets))
+
+	server := &Client{serverUrl: "https://github.example.com", serverVersion: "3.10.5"}
+	require.True(t, server.IsFeatureSupported(FeatureRulesets))
+	require.False(t, server.IsFeatureSupported(FeatureOrganizationRulesets))
+	require.False(t, server.IsFeatureSupported(FeatureCodespaces))
+	require.True(t, server.IsFeatureSupported(FeatureSecretScanningPushProtection))
+	require.False(t, server.IsFeatureSupported(FeatureForkPRContributorApproval))
+	require.True(t, server.IsFeatureSupported(FeatureWebhookDeliveries))
+	require.False(t, server.IsFeatureSupported(FeatureIPAllowList))
+	require.Equal(t, []string{"codespaces", "custom_properties", "fork_pr_contributor_approval", "interaction_limits", "ip_allow_list",
+		"organization_rulesets", "saml_sso_identities"}, server.UnsupportedFeatures())
+
+	old := &Client{serverUrl: "https://github.example.com", serverVersion: "3.6.2"}
+	require.True(t, old.IsFeatureSupported(FeatureRunnerGroupWorkflowRestrictions))
+	require.False(t, old.IsFeatureSupported(FeatureOIDCSubjectClaim))
+	require.False(t, old.IsFeatureSupported(FeatureSecurityManagers))
+	require.False(t, old.IsFeatureSupported(FeatureDependencyGraphSBOM))
+	require.False(t, old.IsFeatureSupported(FeatureCustomRepositoryRoles))
+	require.False(t, old.IsFeatureSupported(FeatureProjectsV2))
+}
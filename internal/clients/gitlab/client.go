@@ -2,10 +2,21 @@ package gitlab
 
 import (
 	"context"
+	"fmt"
+	"github.com/Legit-Labs/legitify/internal/common/group_waiter"
 	"github.com/Legit-Labs/legitify/internal/common/permissions"
+	"github.com/Legit-Labs/legitify/internal/common/scm_type"
 	"github.com/Legit-Labs/legitify/internal/common/types"
+	"github.com/Legit-Labs/legitify/internal/logger"
+	"github.com/Legit-Labs/legitify/internal/metrics"
+	"github.com/Legit-Labs/legitify/internal/token_source"
+	"github.com/Legit-Labs/legitify/internal/tracing"
 	"github.com/patrickmn/go-cache"
 	"github.com/xanzy/go-gitlab"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
 )
 
 const (
@@ -13,24 +24,68 @@ const (
 	allGroupsFilter = ""
 )
 
+// The subgroups modes control which groups are collected:
+// all the owned groups and their subgroups, only the groups given by their full paths (without their subgroups), or only the top-level groups
+const (
+	SubgroupsAll      = "all"
+	SubgroupsSelected = "selected"
+	SubgroupsNone     = "none"
+)
+
+func SubgroupsModes() []string {
+	return []string{SubgroupsAll, SubgroupsSelected, SubgroupsNone}
+}
+
 type Client struct {
-	context context.Context
-	client  *gitlab.Client
-	cache   *cache.Cache
-	orgs    []string
+	context   context.Context
+	client    *gitlab.Client
+	cache     *cache.Cache
+	orgs      []string
+	subgroups string
+	tokenInfo *TokenInfo
 }
 
 func (c *Client) Client() *gitlab.Client {
 	return c.client
 }
 
-func NewClient(ctx context.Context, token string, endpoint string, orgs []string, fillCache bool) (*Client, error) {
-	var config []gitlab.ClientOptionFunc
+// NewClient creates a GitLab client; the optional refresher replaces the token when it expires during the run
+func NewClient(ctx context.Context, token string, endpoint string, orgs []string, subgroups string, fillCache bool, refresher token_source.Refresher) (*Client, error) {
+	if token == "" {
+		return nil, fmt.Errorf("missing token")
+	}
+
+	httpClient := &http.Client{Transport: tracing.NewTransport(metrics.NewTransport(http.DefaultTransport, scm_type.GitLab), scm_type.GitLab)}
+	tokenInfo, err := detectToken(ctx, httpClient, endpoint, token)
+	if err != nil {
+		return nil, err
+	}
+	if err = tokenInfo.validateScopes(); err != nil {
+		return nil, err
+	}
+	logTokenInfo(tokenInfo)
+
+	if refresher != nil {
+		header, prefix := "PRIVATE-TOKEN", ""
+		if tokenInfo.Type == TokenTypeOAuth {
+			header, prefix = "Authorization", "Bearer "
+		}
+		httpClient = &http.Client{Transport: tracing.NewTransport(metrics.NewTransport(
+			token_source.NewHeaderTransport(http.DefaultTransport, header, prefix, refresher), scm_type.GitLab), scm_type.GitLab)}
+	}
+	config := []gitlab.ClientOptionFunc{
+		gitlab.WithHTTPClient(httpClient),
+	}
 	if endpoint != "" {
-		config = []gitlab.ClientOptionFunc{gitlab.WithBaseURL(endpoint)}
+		config = append(config, gitlab.WithBaseURL(endpoint))
 	}
 
-	git, err := gitlab.NewClient(token, config...)
+	var git *gitlab.Client
+	if tokenInfo.Type == TokenTypeOAuth {
+		git, err = gitlab.NewOAuthClient(token, config...)
+	} else {
+		git, err = gitlab.NewClient(token, config...)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -39,11 +94,17 @@ func NewClient(ctx context.Context, token string, endpoint string, orgs []string
 		orgs = []string{allGroupsFilter}
 	}
 
+	if subgroups == "" {
+		subgroups = SubgroupsAll
+	}
+
 	result := &Client{
-		context: ctx,
-		client:  git,
-		cache:   cache.New(cache.NoExpiration, cache.NoExpiration),
-		orgs:    orgs,
+		context:   ctx,
+		client:    git,
+		cache:     cache.New(cache.NoExpiration, cache.NoExpiration),
+		orgs:      orgs,
+		subgroups: subgroups,
+		tokenInfo: tokenInfo,
 	}
 
 	if fillCache {
@@ -55,6 +116,29 @@ func NewClient(ctx context.Context, token string, endpoint string, orgs []string
 	return result, nil
 }
 
+// TokenInfo returns the type and the scopes of the token
+func (c *Client) TokenInfo() *TokenInfo {
+	return c.tokenInfo
+}
+
+func logTokenInfo(info *TokenInfo) {
+	fields := logger.Fields{"type": info.Type}
+	if info.Scopes != nil {
+		fields["scopes"] = strings.Join(info.Scopes, ", ")
+	}
+	if info.ExpiresAt != nil {
+		fields["expires_at"] = info.ExpiresAt.Format("2006-01-02")
+	}
+	logger.With(fields).Infof("Using a GitLab %s", info.Type)
+
+	switch info.Type {
+	case TokenTypeGroup:
+		logger.Infof("a group access token can only access its group, with its subgroups and projects (the Owner role is required to read the group settings)")
+	case TokenTypeProject:
+		logger.Infof("a project access token can only access its project, so only the repository namespace can be analyzed")
+	}
+}
+
 func (c *Client) IsAnalyzable(repo types.RepositoryWithOwner) (bool, error) {
 	_, _, err := c.Client().Projects.GetProject(repo.String(), nil)
 	if err != nil {
@@ -133,11 +217,31 @@ func (c *Client) Groups() ([]*gitlab.Group, error) {
 		return groups.([]*gitlab.Group), nil
 	}
 
+	switch c.subgroups {
+	case SubgroupsSelected:
+		return c.selectedGroups()
+	case SubgroupsNone:
+		return c.ownedGroups(true)
+	}
+
+	owned, err := c.ownedGroups(false)
+	if err != nil {
+		return nil, err
+	}
+	return c.withDescendants(owned)
+}
+
+// ownedGroups lists the groups owned by the user that match the organizations filter
+func (c *Client) ownedGroups(topLevelOnly bool) ([]*gitlab.Group, error) {
 	var result []*gitlab.Group
 
 	ownedGroups := true
 	for _, group := range c.orgs {
+		group := group
 		options := gitlab.ListGroupsOptions{Owned: &ownedGroups, Search: &group}
+		if topLevelOnly {
+			options.TopLevelOnly = &topLevelOnly
+		}
 
 		err := PaginateResults(func(opts *gitlab.ListOptions) (*gitlab.Response, error) {
 			groups, resp, err := c.Client().Groups.ListGroups(&options)
@@ -158,6 +262,76 @@ func (c *Client) Groups() ([]*gitlab.Group, error) {
 	return result, nil
 }
 
+// selectedGroups gets the groups given by their full paths
+func (c *Client) selectedGroups() ([]*gitlab.Group, error) {
+	var result []*gitlab.Group
+
+	for _, path := range c.orgs {
+		group, _, err := c.Client().Groups.GetGroup(path, &gitlab.GetGroupOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get group %s: %v", path, err)
+		}
+		result = append(result, group)
+	}
+
+	return result, nil
+}
+
+// withDescendants adds the subgroups (of all levels) of the given groups, traversing the groups trees in parallel
+func (c *Client) withDescendants(groups []*gitlab.Group) ([]*gitlab.Group, error) {
+	seen := make(map[int]bool)
+	for _, g := range groups {
+		seen[g.ID] = true
+	}
+
+	var mutex sync.Mutex
+	var firstErr error
+	result := append([]*gitlab.Group{}, groups...)
+
+	gw := group_waiter.New()
+	for _, g := range groups {
+		// the descendants of a subgroup are listed with its ancestor's
+		if seen[g.ParentID] {
+			continue
+		}
+		g := g
+		gw.Do(func() {
+			options := gitlab.ListDescendantGroupsOptions{}
+			err := PaginateResults(func(opts *gitlab.ListOptions) (*gitlab.Response, error) {
+				descendants, resp, err := c.Client().Groups.ListDescendantGroups(g.ID, &options)
+				if err != nil {
+					return nil, err
+				}
+
+				mutex.Lock()
+				for _, d := range descendants {
+					if !seen[d.ID] {
+						seen[d.ID] = true
+						result = append(result, d)
+					}
+				}
+				mutex.Unlock()
+
+				return resp, nil
+			}, &options.ListOptions)
+
+			if err != nil {
+				mutex.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to list the subgroups of %s: %v", g.FullPath, err)
+				}
+				mutex.Unlock()
+			}
+		})
+	}
+	gw.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return result, nil
+}
+
 func (c *Client) GroupHooks(gid int) ([]*gitlab.GroupHook, error) {
 	var result []*gitlab.GroupHook
 
@@ -181,3 +355,434 @@ func (c *Client) GroupHooks(gid int) ([]*gitlab.GroupHook, error) {
 
 	return result, nil
 }
+
+func (c *Client) Environments(pid int) ([]*gitlab.Environment, error) {
+	var result []*gitlab.Environment
+
+	options := &gitlab.ListEnvironmentsOptions{}
+
+	err := PaginateResults(func(opts *gitlab.ListOptions) (*gitlab.Response, error) {
+		environments, resp, err := c.Client().Environments.ListEnvironments(pid, options)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, environments...)
+
+		return resp, nil
+	}, &options.ListOptions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (c *Client) ProtectedEnvironments(pid int) ([]*gitlab.ProtectedEnvironment, error) {
+	var result []*gitlab.ProtectedEnvironment
+
+	options := &gitlab.ListProtectedEnvironmentsOptions{}
+	casted := (*gitlab.ListOptions)(options)
+
+	err := PaginateResults(func(opts *gitlab.ListOptions) (*gitlab.Response, error) {
+		environments, resp, err := c.Client().ProtectedEnvironments.ListProtectedEnvironments(pid, options)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, environments...)
+
+		return resp, nil
+	}, casted)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (c *Client) ProjectVariables(pid int) ([]*gitlab.ProjectVariable, error) {
+	var result []*gitlab.ProjectVariable
+
+	options := &gitlab.ListProjectVariablesOptions{}
+	casted := (*gitlab.ListOptions)(options)
+
+	err := PaginateResults(func(opts *gitlab.ListOptions) (*gitlab.Response, error) {
+		variables, resp, err := c.Client().ProjectVariables.ListVariables(pid, options)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, variables...)
+
+		return resp, nil
+	}, casted)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (c *Client) GroupVariables(gid int) ([]*gitlab.GroupVariable, error) {
+	var result []*gitlab.GroupVariable
+
+	options := &gitlab.ListGroupVariablesOptions{}
+	casted := (*gitlab.ListOptions)(options)
+
+	err := PaginateResults(func(opts *gitlab.ListOptions) (*gitlab.Response, error) {
+		variables, resp, err := c.Client().GroupVariables.ListVariables(gid, options)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, variables...)
+
+		return resp, nil
+	}, casted)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (c *Client) ProjectDeployTokens(pid int) ([]*gitlab.DeployToken, error) {
+	var result []*gitlab.DeployToken
+
+	options := &gitlab.ListProjectDeployTokensOptions{}
+	casted := (*gitlab.ListOptions)(options)
+
+	err := PaginateResults(func(opts *gitlab.ListOptions) (*gitlab.Response, error) {
+		tokens, resp, err := c.Client().DeployTokens.ListProjectDeployTokens(pid, options)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, tokens...)
+
+		return resp, nil
+	}, casted)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (c *Client) GroupDeployTokens(gid int) ([]*gitlab.DeployToken, error) {
+	var result []*gitlab.DeployToken
+
+	options := &gitlab.ListGroupDeployTokensOptions{}
+	casted := (*gitlab.ListOptions)(options)
+
+	err := PaginateResults(func(opts *gitlab.ListOptions) (*gitlab.Response, error) {
+		tokens, resp, err := c.Client().DeployTokens.ListGroupDeployTokens(gid, options)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, tokens...)
+
+		return resp, nil
+	}, casted)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (c *Client) GroupMembers(gid int) ([]*gitlab.GroupMember, error) {
+	var result []*gitlab.GroupMember
+
+	options := &gitlab.ListGroupMembersOptions{}
+
+	err := PaginateResults(func(opts *gitlab.ListOptions) (*gitlab.Response, error) {
+		members, resp, err := c.Client().Groups.ListGroupMembers(gid, options)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, members...)
+
+		return resp, nil
+	}, &options.ListOptions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ScimIdentity is an identity provisioned by SCIM to a top-level group (available on GitLab.com)
+type ScimIdentity struct {
+	ExternUID string `json:"extern_uid"`
+	UserID    int    `json:"user_id"`
+	Active    bool   `json:"active"`
+}
+
+// GroupScimIdentities returns the SCIM identities of the top-level group (not supported by go-gitlab yet)
+func (c *Client) GroupScimIdentities(gid int) ([]ScimIdentity, error) {
+	req, err := c.Client().NewRequest(http.MethodGet, fmt.Sprintf("groups/%d/scim/identities", gid), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var identities []ScimIdentity
+	_, err = c.Client().Do(req, &identities)
+	if err != nil {
+		return nil, err
+	}
+
+	return identities, nil
+}
+
+func (c *Client) ProjectRunners(pid int) ([]*gitlab.Runner, error) {
+	var result []*gitlab.Runner
+
+	options := &gitlab.ListProjectRunnersOptions{}
+
+	err := PaginateResults(func(opts *gitlab.ListOptions) (*gitlab.Response, error) {
+		runners, resp, err := c.Client().Runners.ListProjectRunners(pid, options)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, runners...)
+
+		return resp, nil
+	}, &options.ListOptions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (c *Client) PipelineSchedules(pid int) ([]*gitlab.PipelineSchedule, error) {
+	var result []*gitlab.PipelineSchedule
+
+	options := &gitlab.ListPipelineSchedulesOptions{}
+	casted := (*gitlab.ListOptions)(options)
+
+	err := PaginateResults(func(opts *gitlab.ListOptions) (*gitlab.Response, error) {
+		schedules, resp, err := c.Client().PipelineSchedules.ListPipelineSchedules(pid, options)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, schedules...)
+
+		return resp, nil
+	}, casted)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ProjectMembers returns the members of the project (including the inherited ones), filtered by the given users (all of them if empty)
+func (c *Client) ProjectMembers(pid int, userIDs []int) ([]*gitlab.ProjectMember, error) {
+	var result []*gitlab.ProjectMember
+
+	options := &gitlab.ListProjectMembersOptions{}
+	if len(userIDs) > 0 {
+		options.UserIDs = &userIDs
+	}
+
+	err := PaginateResults(func(opts *gitlab.ListOptions) (*gitlab.Response, error) {
+		members, resp, err := c.Client().ProjectMembers.ListAllProjectMembers(pid, options)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, members...)
+
+		return resp, nil
+	}, &options.ListOptions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ContainerRegistryTagProtectionRule protects container image tags matching a pattern from being pushed or deleted
+// by users below the minimum access levels. A rule without a minimum access level makes the tags immutable.
+type ContainerRegistryTagProtectionRule struct {
+	ID                          int     `json:"id"`
+	TagNamePattern              string  `json:"tag_name_pattern"`
+	MinimumAccessLevelForPush   *string `json:"minimum_access_level_for_push"`
+	MinimumAccessLevelForDelete *string `json:"minimum_access_level_for_delete"`
+}
+
+// ContainerRegistryTagProtectionRules returns the container registry tag protection rules of the project (not supported by go-gitlab yet)
+func (c *Client) ContainerRegistryTagProtectionRules(pid int) ([]ContainerRegistryTagProtectionRule, error) {
+	req, err := c.Client().NewRequest(http.MethodGet, fmt.Sprintf("projects/%d/registry/protection/tag/rules", pid), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []ContainerRegistryTagProtectionRule
+	_, err = c.Client().Do(req, &rules)
+	if err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func (c *Client) ProtectedTags(pid int) ([]*gitlab.ProtectedTag, error) {
+	var result []*gitlab.ProtectedTag
+
+	options := &gitlab.ListProtectedTagsOptions{}
+	casted := (*gitlab.ListOptions)(options)
+
+	err := PaginateResults(func(opts *gitlab.ListOptions) (*gitlab.Response, error) {
+		tags, resp, err := c.Client().ProtectedTags.ListProtectedTags(pid, options)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, tags...)
+
+		return resp, nil
+	}, casted)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// RecentReleases returns the latest releases of the project (by release date), up to count releases
+func (c *Client) RecentReleases(pid int, count int) ([]*gitlab.Release, error) {
+	options := &gitlab.ListReleasesOptions{
+		ListOptions: gitlab.ListOptions{PerPage: count},
+		OrderBy:     gitlab.String("released_at"),
+		Sort:        gitlab.String("desc"),
+	}
+
+	releases, _, err := c.Client().Releases.ListReleases(pid, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return releases, nil
+}
+
+// ApprovalSetting is a group merge request approval setting, which can be locked for the group's projects
+type ApprovalSetting struct {
+	Value  bool `json:"value"`
+	Locked bool `json:"locked"`
+}
+
+// GroupApprovalSettings are the merge request approval settings of a group (available on GitLab Premium)
+type GroupApprovalSettings struct {
+	AllowAuthorApproval                         ApprovalSetting `json:"allow_author_approval"`
+	AllowCommitterApproval                      ApprovalSetting `json:"allow_committer_approval"`
+	AllowOverridesToApproverListPerMergeRequest ApprovalSetting `json:"allow_overrides_to_approver_list_per_merge_request"`
+	RetainApprovalsOnPush                       ApprovalSetting `json:"retain_approvals_on_push"`
+	RequirePasswordToApprove                    ApprovalSetting `json:"require_password_to_approve"`
+}
+
+// GroupApprovalSettings returns the merge request approval settings of the group (not supported by go-gitlab yet)
+func (c *Client) GroupApprovalSettings(gid int) (*GroupApprovalSettings, error) {
+	req, err := c.Client().NewRequest(http.MethodGet, fmt.Sprintf("groups/%d/merge_request_approval_setting", gid), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var settings GroupApprovalSettings
+	_, err = c.Client().Do(req, &settings)
+	if err != nil {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+func (c *Client) ProjectAccessTokens(pid int) ([]*gitlab.ProjectAccessToken, error) {
+	var result []*gitlab.ProjectAccessToken
+
+	options := &gitlab.ListProjectAccessTokensOptions{}
+	casted := (*gitlab.ListOptions)(options)
+
+	err := PaginateResults(func(opts *gitlab.ListOptions) (*gitlab.Response, error) {
+		tokens, resp, err := c.Client().ProjectAccessTokens.ListProjectAccessTokens(pid, options)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, tokens...)
+
+		return resp, nil
+	}, casted)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (c *Client) GroupAccessTokens(gid int) ([]*gitlab.GroupAccessToken, error) {
+	var result []*gitlab.GroupAccessToken
+
+	options := &gitlab.ListGroupAccessTokensOptions{}
+	casted := (*gitlab.ListOptions)(options)
+
+	err := PaginateResults(func(opts *gitlab.ListOptions) (*gitlab.Response, error) {
+		tokens, resp, err := c.Client().GroupAccessTokens.ListGroupAccessTokens(gid, options)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, tokens...)
+
+		return resp, nil
+	}, casted)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GroupAuditEvents returns the audit events of the group since the given time (available on GitLab Premium)
+func (c *Client) GroupAuditEvents(gid int, since time.Time) ([]*gitlab.AuditEvent, error) {
+	var result []*gitlab.AuditEvent
+
+	options := &gitlab.ListAuditEventsOptions{CreatedAfter: &since}
+
+	err := PaginateResults(func(opts *gitlab.ListOptions) (*gitlab.Response, error) {
+		events, resp, err := c.Client().AuditEvents.ListGroupAuditEvents(gid, options)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, events...)
+
+		return resp, nil
+	}, &options.ListOptions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,140 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// The token types: personal, group and project access tokens are sent in the PRIVATE-TOKEN header, and OAuth tokens as bearer tokens.
+// Group and project access tokens belong to bot users that are members of their group (or project) only.
+const (
+	TokenTypePersonal = "personal access token"
+	TokenTypeGroup    = "group access token"
+	TokenTypeProject  = "project access token"
+	TokenTypeOAuth    = "OAuth token"
+)
+
+// RequiredScopes are the scopes a scan needs (it only reads, so the api scope, which includes read_api, isn't required)
+var RequiredScopes = []string{"read_api"}
+
+// TokenInfo describes the token legitify authenticates with
+type TokenInfo struct {
+	Type string
+	// Scopes are nil if they couldn't be read (GitLab versions before 15.5)
+	Scopes    []string
+	ExpiresAt *time.Time
+}
+
+// validateScopes checks that the token can read the API (if its scopes are known)
+func (t *TokenInfo) validateScopes() error {
+	if t.Scopes == nil {
+		return nil
+	}
+	for _, scope := range t.Scopes {
+		if scope == "read_api" || scope == "api" {
+			return nil
+		}
+	}
+	return fmt.Errorf("the GitLab %s is missing the required scopes: %s (it has: %s)",
+		t.Type, strings.Join(RequiredScopes, ", "), strings.Join(t.Scopes, ", "))
+}
+
+// webURL returns the GitLab web URL of the endpoint (which may include the API path)
+func webURL(endpoint string) string {
+	if endpoint == "" {
+		return "https://gitlab.com"
+	}
+	endpoint = strings.TrimRight(endpoint, "/")
+	return strings.TrimSuffix(endpoint, "/api/v4")
+}
+
+// detectToken identifies the type and the scopes of the token: access tokens (personal, group or project ones) describe
+// themselves at /personal_access_tokens/self, and OAuth tokens at /oauth/token/info
+func detectToken(ctx context.Context, client *http.Client, endpoint string, token string) (*TokenInfo, error) {
+	base := webURL(endpoint)
+
+	var self struct {
+		Scopes    []string `json:"scopes"`
+		ExpiresAt *string  `json:"expires_at"`
+	}
+	status, err := getJSON(ctx, client, base+"/api/v4/personal_access_tokens/self", "PRIVATE-TOKEN", token, &self)
+	if err != nil {
+		return nil, err
+	}
+
+	switch status {
+	case http.StatusOK:
+		info := &TokenInfo{Type: TokenTypePersonal, Scopes: self.Scopes}
+		if self.Scopes == nil {
+			info.Scopes = []string{}
+		}
+		if self.ExpiresAt != nil {
+			if expiresAt, err := time.Parse("2006-01-02", *self.ExpiresAt); err == nil {
+				info.ExpiresAt = &expiresAt
+			}
+		}
+
+		var user struct {
+			Bot      bool   `json:"bot"`
+			Username string `json:"username"`
+		}
+		if status, err = getJSON(ctx, client, base+"/api/v4/user", "PRIVATE-TOKEN", token, &user); err == nil && status == http.StatusOK && user.Bot {
+			// the bot users of the access tokens are named group_<id>_bot_<hash> and project_<id>_bot_<hash>
+			if strings.HasPrefix(user.Username, "group_") {
+				info.Type = TokenTypeGroup
+			} else if strings.HasPrefix(user.Username, "project_") {
+				info.Type = TokenTypeProject
+			}
+		}
+		return info, nil
+
+	case http.StatusUnauthorized:
+		var oauth struct {
+			Scope     []string `json:"scope"`
+			ExpiresIn *int     `json:"expires_in"`
+		}
+		status, err = getJSON(ctx, client, base+"/oauth/token/info", "Authorization", "Bearer "+token, &oauth)
+		if err != nil {
+			return nil, err
+		}
+		if status != http.StatusOK {
+			return nil, fmt.Errorf("invalid GitLab token (make sure it's not expired or revoked)")
+		}
+		info := &TokenInfo{Type: TokenTypeOAuth, Scopes: oauth.Scope}
+		if oauth.Scope == nil {
+			info.Scopes = []string{}
+		}
+		if oauth.ExpiresIn != nil {
+			expiresAt := time.Now().Add(time.Duration(*oauth.ExpiresIn) * time.Second)
+			info.ExpiresAt = &expiresAt
+		}
+		return info, nil
+
+	default:
+		// older GitLab versions don't describe the token, so it's assumed to be a personal access token
+		return &TokenInfo{Type: TokenTypePersonal}, nil
+	}
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, header string, value string, result interface{}) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set(header, value)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, nil
+	}
+	return resp.StatusCode, json.NewDecoder(resp.Body).Decode(result)
+}
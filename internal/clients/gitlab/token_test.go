@@ -0,0 +1,81 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTokenServer(t *testing.T, selfStatus int, username string, oauthScopes []string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v4/personal_access_tokens/self":
+			require.Equal(t, "token", r.Header.Get("PRIVATE-TOKEN"))
+			w.WriteHeader(selfStatus)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"scopes": []string{"read_api"}, "expires_at": "2030-01-01"})
+		case "/api/v4/user":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"bot": username != "user", "username": username})
+		case "/oauth/token/info":
+			if oauthScopes == nil {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"scope": oauthScopes, "expires_in": 7200})
+		}
+	}))
+}
+
+func TestDetectToken(t *testing.T) {
+	for _, test := range []struct {
+		username string
+		expected string
+	}{
+		{"user", TokenTypePersonal},
+		{"group_42_bot_3f1a", TokenTypeGroup},
+		{"project_7_bot_9c2b", TokenTypeProject},
+	} {
+		server := newTokenServer(t, http.StatusOK, test.username, nil)
+		info, err := detectToken(context.Background(), server.Client(), server.URL+"/api/v4/", "token")
+		server.Close()
+		require.NoError(t, err)
+		require.Equal(t, test.expected, info.Type)
+		require.Equal(t, []string{"read_api"}, info.Scopes)
+		require.Equal(t, "2030-01-01", info.ExpiresAt.Format("2006-01-02"))
+		require.NoError(t, info.validateScopes())
+	}
+}
+
+func TestDetectOAuthToken(t *testing.T) {
+	server := newTokenServer(t, http.StatusUnauthorized, "", []string{"read_user"})
+	defer server.Close()
+
+	info, err := detectToken(context.Background(), server.Client(), server.URL, "token")
+	require.NoError(t, err)
+	require.Equal(t, TokenTypeOAuth, info.Type)
+	require.NotNil(t, info.ExpiresAt)
+	require.Error(t, info.validateScopes())
+}
+
+func TestDetectInvalidToken(t *testing.T) {
+	server := newTokenServer(t, http.StatusUnauthorized, "", nil)
+	defer server.Close()
+
+	_, err := detectToken(context.Background(), server.Client(), server.URL, "token")
+	require.Error(t, err)
+}
+
+func TestDetectTokenOfOlderVersions(t *testing.T) {
+	server := newTokenServer(t, http.StatusNotFound, "", nil)
+	defer server.Close()
+
+	info, err := detectToken(context.Background(), server.Client(), server.URL, "token")
+	require.NoError(t, err)
+	require.Equal(t, TokenTypePersonal, info.Type)
+	require.Nil(t, info.Scopes)
+	require.NoError(t, info.validateScopes())
+}
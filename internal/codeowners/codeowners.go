@@ -0,0 +1,192 @@
+package codeowners
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Legit-Labs/legitify/internal/clients/github/types"
+)
+
+// Paths are the possible paths of the CODEOWNERS file, in the order GitHub looks for it
+var Paths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+const (
+	// UnknownOwner is the kind of the errors of owners that don't exist (or don't have write access to the repository)
+	UnknownOwner = "Unknown owner"
+	InvalidOwner = "Invalid owner"
+	// InvalidPattern is the kind of the errors of patterns using gitignore syntax that CODEOWNERS doesn't support
+	InvalidPattern = "Invalid pattern"
+)
+
+var (
+	userOwner  = regexp.MustCompile(`^@[A-Za-z0-9](?:[A-Za-z0-9-]*[A-Za-z0-9])?$`)
+	teamOwner  = regexp.MustCompile(`^@[A-Za-z0-9](?:[A-Za-z0-9-]*[A-Za-z0-9])?/[A-Za-z0-9._-]+$`)
+	emailOwner = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+)
+
+// Error is an invalid line of the CODEOWNERS file, which GitHub ignores
+type Error struct {
+	Line    int    `json:"line"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// Rule is a valid rule of the CODEOWNERS file: the owners of the files matching the pattern
+type Rule struct {
+	Line    int      `json:"line"`
+	Pattern string   `json:"pattern"`
+	Owners  []string `json:"owners"`
+}
+
+// File is the CODEOWNERS file of a repository
+type File struct {
+	Exists bool   `json:"exists"`
+	Path   string `json:"path,omitempty"`
+	// Rules is the number of the valid rules (patterns with owners)
+	Rules int `json:"rules"`
+	// Owners are the (sorted and unique) owners of the rules: @user, @org/team or an email address
+	Owners []string `json:"owners"`
+	// Ownership are the valid rules, in the order of the file (the last matching rule wins)
+	Ownership []Rule  `json:"ownership"`
+	Errors    []Error `json:"errors"`
+	// UnknownOwners are the owners that GitHub couldn't find
+	UnknownOwners []string `json:"unknown_owners"`
+}
+
+// Missing is the CODEOWNERS file of a repository without one
+func Missing() *File {
+	return &File{Exists: false, Owners: []string{}, Ownership: []Rule{}, Errors: []Error{}, UnknownOwners: []string{}}
+}
+
+// Parse parses the content of a CODEOWNERS file, validating the syntax of its lines
+func Parse(path string, content []byte) *File {
+	file := &File{Exists: true, Path: path, Owners: []string{}, Ownership: []Rule{}, Errors: []Error{}, UnknownOwners: []string{}}
+	owners := make(map[string]bool)
+
+	for i, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(stripComment(line))
+		if len(fields) == 0 {
+			continue
+		}
+
+		pattern := fields[0]
+		if err := validatePattern(pattern); err != "" {
+			file.Errors = append(file.Errors, Error{Line: i + 1, Kind: InvalidPattern, Message: err})
+			continue
+		}
+
+		valid := true
+		for _, owner := range fields[1:] {
+			if !isValidOwner(owner) {
+				file.Errors = append(file.Errors, Error{Line: i + 1, Kind: InvalidOwner, Message: fmt.Sprintf("%s is not a user, a team or an email address", owner)})
+				valid = false
+			}
+		}
+		// a pattern without owners removes the ownership of the matching files
+		if !valid || len(fields) == 1 {
+			continue
+		}
+
+		file.Rules++
+		file.Ownership = append(file.Ownership, Rule{Line: i + 1, Pattern: pattern, Owners: fields[1:]})
+		for _, owner := range fields[1:] {
+			owners[owner] = true
+		}
+	}
+
+	for owner := range owners {
+		file.Owners = append(file.Owners, owner)
+	}
+	sort.Strings(file.Owners)
+
+	return file
+}
+
+// AddErrors adds the errors GitHub detected in the file (e.g. owners that don't exist), which can't be detected by parsing it
+func (f *File) AddErrors(errors []types.CodeownersError) {
+	unknown := make(map[string]bool)
+	for _, e := range errors {
+		if f.hasError(e.Line, e.Kind) {
+			continue
+		}
+		f.Errors = append(f.Errors, Error{Line: e.Line, Kind: e.Kind, Message: e.Message})
+		if e.Kind == UnknownOwner {
+			if owner := tokenAt(e.Source, e.Column); owner != "" {
+				unknown[owner] = true
+			}
+		}
+	}
+
+	for owner := range unknown {
+		f.UnknownOwners = append(f.UnknownOwners, owner)
+	}
+	sort.Strings(f.UnknownOwners)
+	sort.SliceStable(f.Errors, func(i, j int) bool {
+		return f.Errors[i].Line < f.Errors[j].Line
+	})
+}
+
+// Teams returns the (sorted and unique) teams (@org/team) that own code
+func (f *File) Teams() []string {
+	unique := make(map[string]bool)
+	for _, owner := range f.Owners {
+		if teamOwner.MatchString(owner) {
+			unique[strings.ToLower(owner)] = true
+		}
+	}
+
+	teams := make([]string, 0, len(unique))
+	for team := range unique {
+		teams = append(teams, team)
+	}
+	sort.Strings(teams)
+	return teams
+}
+
+func (f *File) hasError(line int, kind string) bool {
+	for _, e := range f.Errors {
+		if e.Line == line && e.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func stripComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		if line[i] == '#' && (i == 0 || line[i-1] != '\\') {
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// validatePattern returns why the pattern is invalid (empty if it's valid)
+func validatePattern(pattern string) string {
+	switch {
+	case strings.HasPrefix(pattern, "!"):
+		return fmt.Sprintf("negated patterns are not supported: %s", pattern)
+	case strings.ContainsAny(pattern, "[]"):
+		return fmt.Sprintf("character ranges are not supported: %s", pattern)
+	}
+	return ""
+}
+
+func isValidOwner(owner string) bool {
+	return userOwner.MatchString(owner) || teamOwner.MatchString(owner) || emailOwner.MatchString(owner)
+}
+
+// tokenAt returns the whitespace separated token at the (1-based) column of the line
+func tokenAt(line string, column int) string {
+	if column < 1 || column > len(line) {
+		return ""
+	}
+	start := column - 1
+	end := start
+	for end < len(line) && line[end] != ' ' && line[end] != '\t' {
+		end++
+	}
+	return line[start:end]
+}
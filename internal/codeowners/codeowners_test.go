@@ -0,0 +1,53 @@
+package codeowners
+
+import (
+	"testing"
+
+	"github.com/Legit-Labs/legitify/internal/clients/github/types"
+	"github.com/stretchr/testify/require"
+)
+
+const content = `# default owners
+*       @org/platform admin@example.com
+/docs/  @docs-writer # inline comment
+\#file  @octocat
+/build/
+!vendor @org/platform
+*.[ch]  @org/c-team
+*.go    @org/go-team not-an-owner
+`
+
+func TestParse(t *testing.T) {
+	file := Parse(".github/CODEOWNERS", []byte(content))
+
+	require.True(t, file.Exists)
+	require.Equal(t, ".github/CODEOWNERS", file.Path)
+	require.Equal(t, 3, file.Rules)
+	require.Equal(t, []string{"@docs-writer", "@octocat", "@org/platform", "admin@example.com"}, file.Owners)
+	require.Equal(t, Rule{Line: 2, Pattern: "*", Owners: []string{"@org/platform", "admin@example.com"}}, file.Ownership[0])
+	require.Equal(t, []string{"@org/platform"}, file.Teams())
+	require.Equal(t, []Error{
+		{Line: 6, Kind: InvalidPattern, Message: "negated patterns are not supported: !vendor"},
+		{Line: 7, Kind: InvalidPattern, Message: "character ranges are not supported: *.[ch]"},
+		{Line: 8, Kind: InvalidOwner, Message: "not-an-owner is not a user, a team or an email address"},
+	}, file.Errors)
+}
+
+func TestAddErrors(t *testing.T) {
+	file := Parse("CODEOWNERS", []byte(content))
+	file.AddErrors([]types.CodeownersError{
+		{Line: 3, Column: 9, Kind: UnknownOwner, Source: "/docs/  @docs-writer # inline comment", Message: "Unknown owner on line 3"},
+		{Line: 6, Column: 1, Kind: InvalidPattern, Source: "!vendor @org/platform", Message: "Invalid pattern on line 6"},
+	})
+
+	require.Equal(t, []string{"@docs-writer"}, file.UnknownOwners)
+	require.Len(t, file.Errors, 4)
+	require.Equal(t, 3, file.Errors[0].Line)
+	require.Equal(t, UnknownOwner, file.Errors[0].Kind)
+}
+
+func TestMissing(t *testing.T) {
+	file := Missing()
+	require.False(t, file.Exists)
+	require.Zero(t, file.Rules)
+}
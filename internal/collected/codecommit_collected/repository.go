@@ -0,0 +1,74 @@
+package codecommit_collected
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	"github.com/Legit-Labs/legitify/internal/common/namespace"
+)
+
+// ApprovalRule is an approval rule template associated with the repository
+type ApprovalRule struct {
+	Template string `json:"template"`
+	// RequiredApprovals is the number of approvals the template requires (the maximum of its statements)
+	RequiredApprovals   int      `json:"required_approvals"`
+	ApprovalPoolMembers []string `json:"approval_pool_members"`
+	// DestinationReferences are the branches the template applies to (all branches if empty)
+	DestinationReferences []string `json:"destination_references"`
+	// AppliesToDefaultBranch is whether the template applies to pull requests into the default branch
+	AppliesToDefaultBranch bool `json:"applies_to_default_branch"`
+}
+
+// Trigger is a repository trigger, which notifies an SNS topic or invokes a Lambda function on repository events
+type Trigger struct {
+	Name           string   `json:"name"`
+	DestinationArn string   `json:"destination_arn"`
+	Events         []string `json:"events"`
+	Branches       []string `json:"branches"`
+	// CrossAccount is whether the destination belongs to a different AWS account than the repository
+	CrossAccount bool `json:"cross_account"`
+}
+
+// Access is the access level an IAM principal's policies grant to the repository (one of: read, write, admin).
+// Deny statements, conditions, permissions boundaries and service control policies aren't evaluated.
+type Access struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Arn   string `json:"arn"`
+	Level string `json:"level"`
+}
+
+type Repository struct {
+	AccountID      string `json:"account_id"`
+	Region         string `json:"region"`
+	RepositoryID   string `json:"repository_id"`
+	RepositoryName string `json:"name"`
+	Description    string `json:"description"`
+	Arn            string `json:"arn"`
+	DefaultBranch  string `json:"default_branch"`
+	// ApprovalRules is nil if the approval rule templates couldn't be collected
+	ApprovalRules []ApprovalRule `json:"approval_rules"`
+	// Triggers is nil if the triggers couldn't be collected
+	Triggers []Trigger `json:"triggers"`
+	// Access is nil if the IAM authorization details couldn't be collected (requires iam:GetAccountAuthorizationDetails)
+	Access []Access `json:"access"`
+}
+
+func (r Repository) ViolationEntityType() string {
+	return namespace.Repository
+}
+
+func (r Repository) CanonicalLink() string {
+	return fmt.Sprintf("https://%s.console.aws.amazon.com/codesuite/codecommit/repositories/%s/browse?region=%s", r.Region, r.RepositoryName, r.Region)
+}
+
+func (r Repository) Name() string {
+	return r.RepositoryName
+}
+
+// ID is derived from the repository ID (a UUID)
+func (r Repository) ID() int64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(r.RepositoryID))
+	return int64(hash.Sum64() >> 1)
+}
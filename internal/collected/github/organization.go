@@ -1,8 +1,12 @@
 package githubcollected
 
 import (
+	"github.com/Legit-Labs/legitify/internal/clients/github/types"
 	"github.com/Legit-Labs/legitify/internal/common/namespace"
 	"github.com/Legit-Labs/legitify/internal/common/permissions"
+	"github.com/Legit-Labs/legitify/internal/ghas"
+	"github.com/Legit-Labs/legitify/internal/webhooks"
+	"time"
 
 	"github.com/google/go-github/v44/github"
 )
@@ -40,10 +44,173 @@ func (e ExtendedOrg) IsFree() bool {
 	return e.Plan.GetName() == orgPlanFree
 }
 
+// RepositoryTransfer is a repository that was transferred into (incoming) or out of (outgoing) the organization
+type RepositoryTransfer struct {
+	Repository string `json:"repository"`
+	Direction  string `json:"direction"`
+	Actor      string `json:"actor"`
+	CreatedAt  string `json:"created_at"`
+}
+
+const (
+	TransferIncoming = "incoming"
+	TransferOutgoing = "outgoing"
+)
+
+// OAuthAppRestrictions is the organization's third-party OAuth application access policy, as recorded in the audit log
+type OAuthAppRestrictions struct {
+	// Enabled is nil if the policy wasn't changed in the audit log (the restrictions are enabled by default for new organizations)
+	Enabled   *bool  `json:"enabled"`
+	ChangedAt string `json:"changed_at,omitempty"`
+	ChangedBy string `json:"changed_by,omitempty"`
+	// ApprovedApplications and DeniedApplications are by the most recent decision about each application
+	ApprovedApplications []string `json:"approved_applications"`
+	DeniedApplications   []string `json:"denied_applications"`
+}
+
+// SecurityManagerTeam is a team that is assigned the security manager role of the organization
+type SecurityManagerTeam struct {
+	Name    string   `json:"name"`
+	Slug    string   `json:"slug"`
+	Members []string `json:"members"`
+	// OutsideCollaborators are the members of the team that aren't members of the organization
+	OutsideCollaborators []string `json:"outside_collaborators"`
+}
+
+// Domain is a domain the organization verified (or, for domains it doesn't own, approved) for its email notifications
+type Domain struct {
+	Domain   string `json:"domain"`
+	Verified bool   `json:"verified"`
+	Approved bool   `json:"approved"`
+}
+
+// SamlSSO is the organization's SAML single sign-on configuration and the members' linked identities.
+// Whether SSO is enforced isn't exposed by the API, but enforcing it removes the members without a linked identity,
+// so unlinked members mean it isn't enforced.
+type SamlSSO struct {
+	// Configured is whether an identity provider is configured for the organization (rather than for its enterprise account)
+	Configured      bool     `json:"configured"`
+	LinkedMembers   int      `json:"linked_members"`
+	UnlinkedMembers int      `json:"unlinked_members"`
+	UnlinkedLogins  []string `json:"unlinked_logins"`
+}
+
+// IPAllowList is the organization's IP allow list configuration
+type IPAllowList struct {
+	Enabled bool `json:"enabled"`
+	// InstalledAppsAllowed is whether the IP allow lists of the installed GitHub Apps are allowed as well
+	InstalledAppsAllowed bool               `json:"installed_apps_allowed"`
+	Entries              []IPAllowListEntry `json:"entries"`
+}
+
+type IPAllowListEntry struct {
+	Value  string `json:"value"`
+	Name   string `json:"name"`
+	Active bool   `json:"active"`
+}
+
+// Codespaces are the organization's codespaces secrets and the codespaces of its repositories.
+// The codespaces policies (who can create codespaces, the allowed machine types and the port forwarding visibility)
+// aren't exposed by the API.
+type Codespaces struct {
+	Secrets    []CodespacesSecret `json:"secrets"`
+	Codespaces []Codespace        `json:"codespaces"`
+}
+
+type CodespacesSecret struct {
+	Name string `json:"name"`
+	// Visibility is one of: all, private, selected
+	Visibility string `json:"visibility"`
+}
+
+type Codespace struct {
+	Name       string `json:"name"`
+	Owner      string `json:"owner"`
+	Repository string `json:"repository"`
+	// PrivateRepository is whether the codespace's repository is private (or internal)
+	PrivateRepository bool   `json:"private_repository"`
+	Machine           string `json:"machine"`
+	State             string `json:"state"`
+}
+
+// Package is a package published under the organization
+type Package struct {
+	Name string `json:"name"`
+	// Type is one of: npm, maven, rubygems, docker, nuget, container
+	Type string `json:"type"`
+	// Visibility is one of: public, private, internal
+	Visibility string `json:"visibility"`
+	// Repository is the full name of the linked repository (empty if the package isn't linked to a repository)
+	Repository        string `json:"repository"`
+	RepositoryPrivate bool   `json:"repository_private"`
+}
+
+// Project is an organization project board (a project or a classic project)
+type Project struct {
+	Title   string `json:"title"`
+	Number  int    `json:"number"`
+	Classic bool   `json:"classic"`
+	Public  bool   `json:"public"`
+	Closed  bool   `json:"closed"`
+}
+
+// InteractionLimit is a temporary limit on who can comment, open issues and create pull requests in the public repositories
+type InteractionLimit struct {
+	// Limit is one of: existing_users, contributors_only, collaborators_only (empty if there's no active limit)
+	Limit string `json:"limit"`
+	// Origin is where the limit was set: organization or repository
+	Origin    string `json:"origin,omitempty"`
+	ExpiresAt string `json:"expires_at,omitempty"`
+}
+
+// NewInteractionLimit converts the interaction restriction of an organization or a repository (nil for none)
+func NewInteractionLimit(restriction *github.InteractionRestriction) *InteractionLimit {
+	if restriction == nil {
+		return &InteractionLimit{}
+	}
+	limit := &InteractionLimit{Limit: restriction.GetLimit(), Origin: restriction.GetOrigin()}
+	if restriction.ExpiresAt != nil {
+		limit.ExpiresAt = restriction.ExpiresAt.Format(time.RFC3339)
+	}
+	return limit
+}
+
 type Organization struct {
 	Organization *ExtendedOrg   `json:"organization"`
 	SamlEnabled  *bool          `json:"saml_enabled,omitempty"`
 	Hooks        []*github.Hook `json:"hooks"`
+	// HooksSettings are the security settings of the hooks (parsed from their configuration)
+	HooksSettings []webhooks.Settings `json:"hooks_settings"`
+	// HooksHealth is the delivery health of the hooks, by their recent deliveries
+	HooksHealth []webhooks.Health `json:"hooks_health"`
+	// AppInstallations are the GitHub Apps installed on the organization
+	AppInstallations []*github.Installation `json:"app_installations"`
+	// SamlSSO is only available for enterprise organizations
+	SamlSSO *SamlSSO `json:"saml_sso,omitempty"`
+	// HighSensitivity is whether the organization was classified as high-sensitivity (see --high-sensitivity-orgs)
+	HighSensitivity bool `json:"high_sensitivity"`
+	// IPAllowList is only available for enterprise organizations
+	IPAllowList *IPAllowList `json:"ip_allow_list,omitempty"`
+	// Codespaces is nil if the codespaces couldn't be collected (requires organization admin permissions)
+	Codespaces *Codespaces `json:"codespaces"`
+	// Packages is nil if the packages couldn't be collected (requires the read:packages scope)
+	Packages []Package `json:"packages"`
+	// Projects is nil if the projects couldn't be collected
+	Projects []Project `json:"projects"`
+	// InteractionLimit is nil if the interaction limit couldn't be collected (requires organization admin permissions)
+	InteractionLimit *InteractionLimit `json:"interaction_limit"`
+	// Domains is nil if the domains couldn't be collected (requires organization admin permissions)
+	Domains []Domain `json:"domains"`
+	// SecurityManagerTeams is nil if the security managers couldn't be collected (requires organization admin permissions)
+	SecurityManagerTeams []SecurityManagerTeam `json:"security_manager_teams"`
+	// CustomRepositoryRoles are only available for enterprise organizations
+	CustomRepositoryRoles []types.CustomRepositoryRole `json:"custom_repository_roles"`
+	// OAuthAppRestrictions is only available for enterprise organizations (collected from the audit log)
+	OAuthAppRestrictions *OAuthAppRestrictions `json:"oauth_app_restrictions,omitempty"`
+	// RepositoryTransfers is only available for enterprise organizations (collected from the audit log)
+	RepositoryTransfers []RepositoryTransfer `json:"repository_transfers,omitempty"`
+	// GHASCoverage is the GitHub Advanced Security coverage of the organization repositories
+	GHASCoverage *ghas.Coverage `json:"ghas_coverage"`
 	UserRole     permissions.OrganizationRole
 }
 
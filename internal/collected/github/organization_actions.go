@@ -3,14 +3,34 @@ package githubcollected
 import (
 	"fmt"
 	"github.com/Legit-Labs/legitify/internal/clients/github/types"
+	"time"
 
 	"github.com/google/go-github/v44/github"
 )
 
 type OrganizationActions struct {
-	Organization       ExtendedOrg                `json:"organization"`
-	ActionsPermissions *github.ActionsPermissions `json:"actions_permissions"`
-	TokenPermissions   *types.TokenPermissions    `json:"token_permissions"`
+	Organization       ExtendedOrg               `json:"organization"`
+	ActionsPermissions *types.ActionsPermissions `json:"actions_permissions"`
+	// SelectedActions is the allow-list of actions (collected only when allowed_actions is "selected")
+	SelectedActions  *github.ActionsAllowed           `json:"selected_actions"`
+	TokenPermissions *types.TokenPermissions          `json:"token_permissions"`
+	OIDCSubjectClaim *types.OIDCSubjectClaim          `json:"oidc_subject_claim"`
+	ForkPRApproval   *types.ForkPRContributorApproval `json:"fork_pr_approval"`
+	Secrets          []ActionsSecret                  `json:"secrets"`
+	// RequiredWorkflows are the workflows that are required to pass on the pull requests of the organization repositories
+	RequiredWorkflows []types.RequiredWorkflow `json:"required_workflows"`
+	// WorkflowRulesets are the organization rulesets that require workflows to pass (rulesets with a "workflows" rule)
+	WorkflowRulesets []types.Ruleset `json:"workflow_rulesets"`
+	// SecretMaxAge is the configured maximal age (in days) of the secrets, keyed by the (lower case) severity
+	SecretMaxAge map[string]int `json:"secret_max_age_days"`
+}
+
+type ActionsSecret struct {
+	Name       string     `json:"name"`
+	Visibility string     `json:"visibility,omitempty"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+	// AgeDays is the number of days since the secret was last updated (nil when the update time isn't available)
+	AgeDays *int `json:"age_days,omitempty"`
 }
 
 func (o OrganizationActions) ViolationEntityType() string {
@@ -6,16 +6,47 @@ import (
 	"github.com/google/go-github/v44/github"
 )
 
+const (
+	ActivitySourceAuditLog = "audit_log"
+	// ActivitySourceEvents is the user's recent events (the events API only covers the last 90 days)
+	ActivitySourceEvents = "events"
+)
+
+// MemberActivity is the most recent activity of an organization admin, and where it was taken from
+type MemberActivity struct {
+	// LastActive is the time (ns) of the most recent activity, or 0 if none was found
+	LastActive int    `json:"last_active"`
+	Source     string `json:"source"`
+}
+
 type OrganizationMember struct {
 	User       *github.User `json:"user"`
 	LastActive int          `json:"last_active"`
 	IsAdmin    bool         `json:"is_admin"`
+	// InIdentitySource is only set when an identity source (HR/IdP export) is provided
+	InIdentitySource *bool `json:"in_identity_source,omitempty"`
+	// AuditLogLastActive is only set when the audit log activity is collected (see --audit-log-activity-days):
+	// the time (ns) of the member's most recent audit log event in the collected window, or 0 if there is none
+	AuditLogLastActive *int `json:"audit_log_last_active,omitempty"`
+	// Activity is only collected for the admins
+	Activity *MemberActivity `json:"activity,omitempty"`
+}
+
+type OrganizationInvitation struct {
+	Login     string `json:"login"`
+	Email     string `json:"email"`
+	CreatedAt int    `json:"created_at"`
 }
 
 type OrganizationMembers struct {
-	Organization  ExtendedOrg          `json:"organization"`
-	Members       []OrganizationMember `json:"members"`
-	HasLastActive bool                 `json:"has_last_active"`
+	Organization       ExtendedOrg              `json:"organization"`
+	Members            []OrganizationMember     `json:"members"`
+	HasLastActive      bool                     `json:"has_last_active"`
+	PendingInvitations []OrganizationInvitation `json:"pending_invitations"`
+	// ActivityWindowDays is the number of days of audit log activity that was collected (0 if it wasn't)
+	ActivityWindowDays int `json:"activity_window_days"`
+	// DormantAdminDays is the number of days without activity after which an admin is considered dormant
+	DormantAdminDays int `json:"dormant_admin_days"`
 }
 
 func NewOrganizationMember(user *github.User, lastActive int, memberType string) OrganizationMember {
@@ -26,6 +57,14 @@ func NewOrganizationMember(user *github.User, lastActive int, memberType string)
 	}
 }
 
+func NewOrganizationInvitation(invitation *github.Invitation) OrganizationInvitation {
+	return OrganizationInvitation{
+		Login:     invitation.GetLogin(),
+		Email:     invitation.GetEmail(),
+		CreatedAt: int(invitation.GetCreatedAt().UnixNano()),
+	}
+}
+
 func (o OrganizationMembers) ViolationEntityType() string {
 	return "organization members"
 }
@@ -1,9 +1,17 @@
 package githubcollected
 
 import (
+	"github.com/Legit-Labs/legitify/internal/branches"
 	"github.com/Legit-Labs/legitify/internal/clients/github/types"
+	"github.com/Legit-Labs/legitify/internal/codeowners"
 	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/Legit-Labs/legitify/internal/dependabot"
+	"github.com/Legit-Labs/legitify/internal/enforcement"
 	"github.com/Legit-Labs/legitify/internal/scorecard"
+	"github.com/Legit-Labs/legitify/internal/suspicious"
+	"github.com/Legit-Labs/legitify/internal/waivers"
+	"github.com/Legit-Labs/legitify/internal/webhooks"
+	"github.com/Legit-Labs/legitify/internal/workflows"
 	"github.com/google/go-github/v44/github"
 	"github.com/shurcooL/githubv4"
 )
@@ -27,17 +35,42 @@ type GitHubQLRepositoryCollaborators struct {
 	Edges []GitHubQLRepositoryCollaboratorsEdge `json:"edges" graphql:"edges"`
 }
 
+type GitHubQLRepositoryTopics struct {
+	Nodes []struct {
+		Topic struct {
+			Name string `json:"name"`
+		} `json:"topic"`
+	} `json:"nodes"`
+}
+
 type GitHubQLRepository struct {
 	Name               string `json:"name"`
-	RebaseMergeAllowed bool
+	RebaseMergeAllowed bool   `json:"rebase_merge_allowed"`
 	Url                string
 	DatabaseId         int64
-	IsPrivate          bool               `json:"is_private"`
-	ForkingAllowed     bool               `json:"allow_forking"`
-	IsArchived         bool               `json:"is_archived"`
-	DefaultBranchRef   *GitHubQLBranch    `json:"default_branch"`
-	PushedAt           *githubv4.DateTime `json:"pushed_at"`
-	ViewerPermission   string             `json:"viewerPermission"`
+	IsPrivate          bool                     `json:"is_private"`
+	ForkingAllowed     bool                     `json:"allow_forking"`
+	IsArchived         bool                     `json:"is_archived"`
+	DefaultBranchRef   *GitHubQLBranch          `json:"default_branch"`
+	PushedAt           *githubv4.DateTime       `json:"pushed_at"`
+	ViewerPermission   string                   `json:"viewerPermission"`
+	RepositoryTopics   GitHubQLRepositoryTopics `json:"repository_topics" graphql:"repositoryTopics(first: 20)"`
+	// the merge settings of the repository's pull requests
+	MergeCommitAllowed  bool `json:"merge_commit_allowed"`
+	SquashMergeAllowed  bool `json:"squash_merge_allowed"`
+	AutoMergeAllowed    bool `json:"auto_merge_allowed"`
+	DeleteBranchOnMerge bool `json:"delete_branch_on_merge"`
+	// AllowUpdateBranch is whether the pull requests always suggest updating their head branch (when it's behind the base branch)
+	AllowUpdateBranch bool `json:"allow_update_branch"`
+	// LicenseInfo is the license detected by GitHub (null when the repository has no license)
+	LicenseInfo *GitHubQLLicense `json:"license_info"`
+}
+
+// GitHubQLLicense is a repository license; unrecognized licenses have the "other" key (and the NOASSERTION SPDX id)
+type GitHubQLLicense struct {
+	Key    string  `json:"key"`
+	Name   string  `json:"name"`
+	SpdxId *string `json:"spdx_id"`
 }
 
 type GitHubQLBranchProtectionRule struct {
@@ -54,6 +87,35 @@ type GitHubQLBranchProtectionRule struct {
 	RequiresConversationResolution *bool `json:"requires_conversation_resolution,omitempty"`
 	RequiresCommitSignatures       *bool `json:"requires_commit_signatures,omitempty"`
 	RestrictsReviewDismissals      *bool `json:"restricts_review_dismissals,omitempty"`
+	RequireLastPushApproval        *bool `json:"require_last_push_approval,omitempty"`
+	RequiresDeployments            *bool `json:"requires_deployments,omitempty"`
+	// RequiredDeploymentEnvironments are the environments that must be successfully deployed to before merging
+	RequiredDeploymentEnvironments []string `json:"required_deployment_environments,omitempty"`
+	// LockBranch is whether the branch is read-only
+	LockBranch *bool `json:"lock_branch,omitempty"`
+	// BypassPullRequestAllowances are the actors that can bypass the required pull requests (and their reviews)
+	BypassPullRequestAllowances *GitHubQLBypassAllowances `json:"bypass_pull_request_allowances,omitempty" graphql:"bypassPullRequestAllowances(first: 50)"`
+}
+
+type GitHubQLBypassActor struct {
+	App struct {
+		DatabaseId *int64 `json:"id"`
+		Name       string `json:"name"`
+	} `json:"app" graphql:"... on App"`
+	Team struct {
+		DatabaseId *int64 `json:"id"`
+		Slug       string `json:"slug"`
+	} `json:"team" graphql:"... on Team"`
+	User struct {
+		DatabaseId *int64 `json:"id"`
+		Login      string `json:"login"`
+	} `json:"user" graphql:"... on User"`
+}
+
+type GitHubQLBypassAllowances struct {
+	Nodes []struct {
+		Actor GitHubQLBypassActor `json:"actor"`
+	} `json:"nodes"`
 }
 
 type GitHubQLBranch struct {
@@ -61,15 +123,82 @@ type GitHubQLBranch struct {
 	BranchProtectionRule *GitHubQLBranchProtectionRule `json:"branch_protection_rule"`
 }
 
+// SecurityPolicy is the vulnerability disclosure policy (SECURITY.md) of a repository
+type SecurityPolicy struct {
+	Exists bool   `json:"exists"`
+	Path   string `json:"path,omitempty"`
+	// Inherited is whether the policy is the organization's default (of its public .github repository)
+	Inherited bool `json:"inherited"`
+}
+
+// Pages is the GitHub Pages configuration of a repository
+type Pages struct {
+	Enabled      bool   `json:"enabled"`
+	SourceBranch string `json:"source_branch,omitempty"`
+	SourcePath   string `json:"source_path,omitempty"`
+	CustomDomain string `json:"custom_domain,omitempty"`
+	// HTTPSEnforced is whether HTTP requests are redirected to HTTPS (always true for the default github.io domain)
+	HTTPSEnforced bool `json:"https_enforced"`
+	// Public is whether the site is publicly accessible (sites of private repositories can be restricted to the repository readers on GitHub Enterprise Cloud)
+	Public bool `json:"public"`
+}
+
+// OutsideCollaborator is a repository collaborator that isn't a member of the organization
+type OutsideCollaborator struct {
+	Login string `json:"login"`
+	// Permission is the collaborator's highest permission: admin, maintain, write, triage or read
+	Permission string `json:"permission"`
+}
+
 type Repository struct {
 	Repository                   *GitHubQLRepository               `json:"repository"`
 	VulnerabilityAlertsEnabled   *bool                             `json:"vulnerability_alerts_enabled"`
 	NoBranchProtectionPermission bool                              `json:"no_branch_protection_permission"`
 	Scorecard                    *scorecard.Result                 `json:"scorecard,omitempty"`
 	Hooks                        []*github.Hook                    `json:"hooks"`
+	HooksSettings                []webhooks.Settings               `json:"hooks_settings"`
+	HooksHealth                  []webhooks.Health                 `json:"hooks_health"`
 	Collaborators                []*github.User                    `json:"collaborators"`
+	OutsideCollaborators         []OutsideCollaborator             `json:"outside_collaborators"`
 	ActionsTokenPermissions      *types.TokenPermissions           `json:"actions_token_permissions"`
+	ActionsPermissions           *types.ActionsPermissions         `json:"actions_permissions"`
+	SelectedActions              *github.ActionsAllowed            `json:"selected_actions"`
 	DependencyGraphManifests     *GitHubQLDependencyGraphManifests `json:"dependency_graph_manifests"`
+	DependencyGraphEnabled       *bool                             `json:"dependency_graph_enabled"`
+	SecurityAndAnalysis          *types.SecurityAndAnalysis        `json:"security_and_analysis"`
+	CodeScanningEnabled          *bool                             `json:"code_scanning_enabled"`
+	SelfHostedRunners            []types.SelfHostedRunner          `json:"self_hosted_runners"`
+	OIDCSubjectClaim             *types.OIDCSubjectClaim           `json:"oidc_subject_claim"`
+	// ForkPRApproval is collected for public repositories only
+	ForkPRApproval *types.ForkPRContributorApproval `json:"fork_pr_approval"`
+	// Rulesets are the repository and organization rulesets that apply to the repository
+	Rulesets []types.Ruleset `json:"rulesets"`
+	// DefaultBranchRules are the active ruleset rules of the default branch, which are equivalent to its branch protection
+	DefaultBranchRules []types.BranchRule `json:"default_branch_rules"`
+	// Branches are the non-default branches that match the --protected-branches patterns
+	Branches []branches.Branch `json:"branches"`
+	// SuspiciousFiles are the files of the default branch whose names suggest they contain secrets (collected with --suspicious-files)
+	SuspiciousFiles []suspicious.File `json:"suspicious_files,omitempty"`
+	// DependabotConfig is the Dependabot version updates configuration of the default branch
+	DependabotConfig *dependabot.Config `json:"dependabot_config"`
+	// Codeowners is the CODEOWNERS file of the default branch
+	Codeowners *codeowners.File `json:"codeowners"`
+	// CodeownersTeams are the members (logins) of the organization teams that own code in the CODEOWNERS file (@org/team, lower cased)
+	CodeownersTeams map[string][]string `json:"codeowners_teams"`
+	// Enforcement is who can push unreviewed code to the default branch, computed from its protections and the collaborators roles
+	Enforcement *enforcement.Enforcement `json:"enforcement"`
+	// SecurityPolicy is the repository's security policy, or the organization's default one
+	SecurityPolicy *SecurityPolicy `json:"security_policy"`
+	// InteractionLimit is collected for public repositories only (it includes the limit inherited from the organization)
+	InteractionLimit *InteractionLimit `json:"interaction_limit"`
+	// Pages is nil if the pages configuration couldn't be collected
+	Pages *Pages `json:"pages"`
+	// Workflows are the parsed workflow files of the default branch
+	Workflows []*workflows.Workflow `json:"workflows"`
+	// TrustedActionPublishers is the configured allow-list of actions owners that don't have to be pinned to a commit SHA
+	TrustedActionPublishers []string `json:"trusted_action_publishers"`
+	// AcceptedViolations are the policies waived by the repository metadata (see the waivers package)
+	AcceptedViolations []waivers.Waiver `json:"waivers,omitempty"`
 }
 
 func (r Repository) ViolationEntityType() string {
@@ -84,6 +213,19 @@ func (r Repository) Name() string {
 	return r.Repository.Name
 }
 
+func (r Repository) Waivers() []waivers.Waiver {
+	return r.AcceptedViolations
+}
+
+// Topics returns the names of the repository topics
+func (r Repository) Topics() []string {
+	var topics []string
+	for _, node := range r.Repository.RepositoryTopics.Nodes {
+		topics = append(topics, node.Topic.Name)
+	}
+	return topics
+}
+
 func (r Repository) ID() int64 {
 	// Deliberately using the Org; see membersList enricher
 	return r.Repository.DatabaseId
@@ -2,12 +2,18 @@ package githubcollected
 
 import (
 	"fmt"
+
+	"github.com/Legit-Labs/legitify/internal/clients/github/types"
 	"github.com/google/go-github/v44/github"
 )
 
 type RunnerGroup struct {
-	Organization ExtendedOrg         `json:"organization"`
-	RunnerGroup  *github.RunnerGroup `json:"runner_group"`
+	Organization ExtendedOrg              `json:"organization"`
+	RunnerGroup  *github.RunnerGroup      `json:"runner_group"`
+	Runners      []types.SelfHostedRunner `json:"runners"`
+	// SelectedRepositoriesCount is the number of repositories allowed to use the group (when its visibility is "selected")
+	SelectedRepositoriesCount *int                                   `json:"selected_repositories_count"`
+	WorkflowRestrictions      *types.RunnerGroupWorkflowRestrictions `json:"workflow_restrictions"`
 }
 
 func (o RunnerGroup) ViolationEntityType() string {
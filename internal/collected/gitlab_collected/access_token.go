@@ -0,0 +1,49 @@
+package gitlab_collected
+
+import (
+	"time"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// AccessToken is an active project or group access token (its value is never collected)
+type AccessToken struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+	// AccessLevel is the role of the token's bot user (e.g. 40 for maintainer, 50 for owner)
+	AccessLevel int `json:"access_level"`
+	// ExpiresAt is empty if the token never expires
+	ExpiresAt  string `json:"expires_at,omitempty"`
+	LastUsedAt string `json:"last_used_at,omitempty"`
+}
+
+func newAccessToken(name string, scopes []string, accessLevel gitlab.AccessLevelValue, expiresAt *gitlab.ISOTime, lastUsedAt *time.Time) AccessToken {
+	token := AccessToken{Name: name, Scopes: scopes, AccessLevel: int(accessLevel)}
+	if expiresAt != nil {
+		token.ExpiresAt = expiresAt.String()
+	}
+	if lastUsedAt != nil {
+		token.LastUsedAt = lastUsedAt.Format(time.RFC3339)
+	}
+	return token
+}
+
+func NewProjectAccessTokens(tokens []*gitlab.ProjectAccessToken) []AccessToken {
+	result := []AccessToken{}
+	for _, token := range tokens {
+		if token.Active && !token.Revoked {
+			result = append(result, newAccessToken(token.Name, token.Scopes, token.AccessLevel, token.ExpiresAt, token.LastUsedAt))
+		}
+	}
+	return result
+}
+
+func NewGroupAccessTokens(tokens []*gitlab.GroupAccessToken) []AccessToken {
+	result := []AccessToken{}
+	for _, token := range tokens {
+		if token.Active && !token.Revoked {
+			result = append(result, newAccessToken(token.Name, token.Scopes, token.AccessLevel, token.ExpiresAt, token.LastUsedAt))
+		}
+	}
+	return result
+}
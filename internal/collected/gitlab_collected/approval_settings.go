@@ -0,0 +1,15 @@
+package gitlab_collected
+
+// ApprovalSettings are the merge request approval settings of a project or a group
+type ApprovalSettings struct {
+	// ResetApprovalsOnPush is whether approvals are removed when new commits are pushed to the merge request
+	ResetApprovalsOnPush bool `json:"reset_approvals_on_push"`
+	// PreventAuthorApproval is whether the merge request author is prevented from approving it
+	PreventAuthorApproval bool `json:"prevent_author_approval"`
+	// PreventCommittersApproval is whether users who committed to the merge request are prevented from approving it
+	PreventCommittersApproval bool `json:"prevent_committers_approval"`
+	// PreventApproversOverride is whether editing the approval rules in merge requests is prevented
+	PreventApproversOverride bool `json:"prevent_approvers_override"`
+	// RequireReauthentication is whether approvers have to re-authenticate (enter their password) to approve
+	RequireReauthentication bool `json:"require_reauthentication"`
+}
@@ -0,0 +1,24 @@
+package gitlab_collected
+
+// AuditEvent is a group audit event that changed a member's role or a visibility
+type AuditEvent struct {
+	Author string `json:"author"`
+	// Target is the user (of a membership change) or the entity (of a visibility change) the event applies to
+	Target    string `json:"target"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	CreatedAt string `json:"created_at"`
+}
+
+// AuditEvents summarizes the group audit events of the collected period (see --audit-log-activity-days).
+// Audit events are available on GitLab Premium, and only record administrative actions (not every use of GitLab).
+type AuditEvents struct {
+	// WindowDays is the number of days of audit events that were collected
+	WindowDays int `json:"window_days"`
+	// PermissionChanges are the members that were added to the group or whose role was changed
+	PermissionChanges []AuditEvent `json:"permission_changes"`
+	// VisibilityChanges are the changes of the visibility of the group or its projects
+	VisibilityChanges []AuditEvent `json:"visibility_changes"`
+	// InactiveMembers are the usernames of the group members who didn't author any audit event in the period
+	InactiveMembers []string `json:"inactive_members"`
+}
@@ -0,0 +1,16 @@
+package gitlab_collected
+
+// ContainerRegistry is the project container registry configuration
+type ContainerRegistry struct {
+	// AccessLevel is one of: enabled (same visibility as the project), private (project members only), disabled
+	AccessLevel string `json:"access_level"`
+	// CleanupPolicyEnabled is whether the cleanup policy (container expiration policy) removes old image tags
+	CleanupPolicyEnabled bool   `json:"cleanup_policy_enabled"`
+	CleanupCadence       string `json:"cleanup_cadence"`
+	CleanupKeepN         int    `json:"cleanup_keep_n"`
+	CleanupOlderThan     string `json:"cleanup_older_than"`
+	// ProtectedTagPatterns is nil if the tag protection rules couldn't be collected (they are available since GitLab 17.8)
+	ProtectedTagPatterns []string `json:"protected_tag_patterns"`
+	// ImmutableTagPatterns are the tag patterns that can't be overwritten or deleted by anyone (available since GitLab 18.0)
+	ImmutableTagPatterns []string `json:"immutable_tag_patterns"`
+}
@@ -0,0 +1,29 @@
+package gitlab_collected
+
+import (
+	"time"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// DeployToken is a deploy token of a project or a group (its value is never collected).
+// When the token was last used isn't exposed by the API.
+type DeployToken struct {
+	Name     string   `json:"name"`
+	Username string   `json:"username"`
+	Scopes   []string `json:"scopes"`
+	// ExpiresAt is empty if the token never expires
+	ExpiresAt string `json:"expires_at,omitempty"`
+}
+
+func NewDeployTokens(tokens []*gitlab.DeployToken) []DeployToken {
+	result := []DeployToken{}
+	for _, token := range tokens {
+		collected := DeployToken{Name: token.Name, Username: token.Username, Scopes: token.Scopes}
+		if token.ExpiresAt != nil {
+			collected.ExpiresAt = token.ExpiresAt.Format(time.RFC3339)
+		}
+		result = append(result, collected)
+	}
+	return result
+}
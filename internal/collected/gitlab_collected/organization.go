@@ -8,6 +8,23 @@ import (
 type Organization struct {
 	*gitlab.Group
 	Hooks []*gitlab.GroupHook `json:"hooks"`
+	// Variables is nil if the CI/CD variables couldn't be collected
+	Variables []Variable `json:"variables"`
+	// DeployTokens is nil if the deploy tokens couldn't be collected
+	DeployTokens []DeployToken `json:"deploy_tokens"`
+	// AccessTokens are the active group access tokens (nil if they couldn't be collected)
+	AccessTokens []AccessToken `json:"access_tokens"`
+	// MaxAccessTokenLifetime is the instance's maximum lifetime (in days) of access tokens, 0 if it isn't enforced.
+	// It's collected for top-level groups when the token is of an instance administrator (nil otherwise).
+	MaxAccessTokenLifetime *int `json:"max_access_token_lifetime"`
+	// ApprovalSettings is nil if the merge request approval settings couldn't be collected (they are available on GitLab Premium)
+	ApprovalSettings *ApprovalSettings `json:"approval_settings"`
+	// AuditEvents is only collected when requested (with --audit-log-activity-days), nil otherwise
+	AuditEvents *AuditEvents `json:"audit_events,omitempty"`
+	// SamlSSO is only collected for top-level groups
+	SamlSSO *SamlSSO `json:"saml_sso,omitempty"`
+	// RunnerRegistrationTokenEnabled is whether the group has a (legacy) runner registration token; the token itself is removed from the group
+	RunnerRegistrationTokenEnabled bool `json:"runner_registration_token_enabled"`
 }
 
 func (o Organization) ViolationEntityType() string {
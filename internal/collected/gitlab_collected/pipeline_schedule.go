@@ -0,0 +1,14 @@
+package gitlab_collected
+
+// PipelineSchedule is a project pipeline schedule, whose pipelines run with its owner's permissions
+type PipelineSchedule struct {
+	ID          int    `json:"id"`
+	Description string `json:"description"`
+	Ref         string `json:"ref"`
+	Active      bool   `json:"active"`
+	Owner       string `json:"owner"`
+	// OwnerState is one of: active, blocked, deactivated (and the other GitLab user states)
+	OwnerState string `json:"owner_state"`
+	// OwnerIsMember is whether the owner is still a member of the project (directly or through its groups)
+	OwnerIsMember bool `json:"owner_is_member"`
+}
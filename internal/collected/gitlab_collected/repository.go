@@ -0,0 +1,62 @@
+package gitlab_collected
+
+import (
+	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/xanzy/go-gitlab"
+)
+
+// Environment is a project environment with its protection (protected environments are available on GitLab Premium)
+type Environment struct {
+	Name string `json:"name"`
+	// Tier is one of: production, staging, testing, development, other
+	Tier      string `json:"tier"`
+	Protected bool   `json:"protected"`
+	// RequiredApprovalCount is the number of approvals required to deploy to the environment (0 if it isn't protected)
+	RequiredApprovalCount int `json:"required_approval_count"`
+	// DeployAccessLevels are the descriptions of who can deploy to the protected environment
+	DeployAccessLevels []string `json:"deploy_access_levels"`
+}
+
+type Repository struct {
+	*gitlab.Project
+	// Environments is nil if the environments couldn't be collected
+	Environments []Environment `json:"environments"`
+	// Variables is nil if the CI/CD variables couldn't be collected
+	Variables []Variable `json:"variables"`
+	// DeployTokens is nil if the deploy tokens couldn't be collected
+	DeployTokens []DeployToken `json:"deploy_tokens"`
+	// AccessTokens are the active project access tokens (nil if they couldn't be collected)
+	AccessTokens []AccessToken `json:"access_tokens"`
+	// PipelineSchedules is nil if the pipeline schedules couldn't be collected
+	PipelineSchedules []PipelineSchedule `json:"pipeline_schedules"`
+	// Runners is nil if the runners couldn't be collected
+	Runners []Runner `json:"runners"`
+	// ProtectedTags is nil if the protected tags couldn't be collected
+	ProtectedTags []ProtectedTag `json:"protected_tags"`
+	// Releases are the latest releases of the project (nil if they couldn't be collected)
+	Releases []Release `json:"releases"`
+	// ApprovalSettings is nil if the merge request approval settings couldn't be collected
+	ApprovalSettings *ApprovalSettings `json:"approval_settings"`
+	// ContainerRegistry is nil if the container registry settings couldn't be collected
+	ContainerRegistry *ContainerRegistry `json:"container_registry"`
+	// RunnerRegistrationTokenEnabled is whether the project has a (legacy) runner registration token; the token itself is removed from the project
+	RunnerRegistrationTokenEnabled bool `json:"runner_registration_token_enabled"`
+	// HighSensitivity is whether the project's group was classified as high-sensitivity (see --high-sensitivity-orgs)
+	HighSensitivity bool `json:"high_sensitivity"`
+}
+
+func (r Repository) ViolationEntityType() string {
+	return namespace.Repository
+}
+
+func (r Repository) CanonicalLink() string {
+	return r.WebURL
+}
+
+func (r Repository) Name() string {
+	return r.PathWithNamespace
+}
+
+func (r Repository) ID() int64 {
+	return int64(r.Project.ID)
+}
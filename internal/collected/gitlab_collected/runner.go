@@ -0,0 +1,12 @@
+package gitlab_collected
+
+// Runner is a runner available to a project. Whether it runs privileged containers (its executor configuration) isn't exposed by the API.
+type Runner struct {
+	ID          int    `json:"id"`
+	Description string `json:"description"`
+	// Type is one of: instance_type (shared), group_type, project_type
+	Type   string `json:"type"`
+	Shared bool   `json:"shared"`
+	Paused bool   `json:"paused"`
+	Online bool   `json:"online"`
+}
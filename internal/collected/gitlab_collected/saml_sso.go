@@ -0,0 +1,13 @@
+package gitlab_collected
+
+// SamlSSO is the SAML single sign-on status of a top-level group (available on GitLab.com), by its members' linked identities.
+// Whether SSO is enforced isn't exposed by the API.
+type SamlSSO struct {
+	// Configured is whether any of the group members has a linked SAML identity (which requires a configured identity provider)
+	Configured        bool     `json:"configured"`
+	LinkedMembers     int      `json:"linked_members"`
+	UnlinkedMembers   int      `json:"unlinked_members"`
+	UnlinkedUsernames []string `json:"unlinked_usernames"`
+	// ScimIdentities is the number of active identities provisioned by SCIM (nil if they couldn't be collected)
+	ScimIdentities *int `json:"scim_identities"`
+}
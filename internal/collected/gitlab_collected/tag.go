@@ -0,0 +1,14 @@
+package gitlab_collected
+
+// ProtectedTag is a protected tag name pattern (wildcards are supported, e.g. v*) with who is allowed to create matching tags
+type ProtectedTag struct {
+	Name               string   `json:"name"`
+	CreateAccessLevels []string `json:"create_access_levels"`
+}
+
+// Release is one of the latest releases of a project, with whether its tag is protected
+type Release struct {
+	Name         string `json:"name"`
+	TagName      string `json:"tag_name"`
+	TagProtected bool   `json:"tag_protected"`
+}
@@ -0,0 +1,12 @@
+package gitlab_collected
+
+// Variable is the metadata of a CI/CD variable of a project or a group (its value is never collected)
+type Variable struct {
+	Key string `json:"key"`
+	// Type is one of: env_var, file
+	Type      string `json:"type"`
+	Protected bool   `json:"protected"`
+	Masked    bool   `json:"masked"`
+	// EnvironmentScope is the environments the variable is available to ("*" for all of them)
+	EnvironmentScope string `json:"environment_scope"`
+}
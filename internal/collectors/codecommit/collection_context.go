@@ -0,0 +1,24 @@
+package codecommit
+
+import (
+	"github.com/Legit-Labs/legitify/internal/common/permissions"
+)
+
+type collectionContext struct {
+	roles []permissions.Role
+}
+
+func newCollectionContext(roles []permissions.Role) collectionContext {
+	return collectionContext{
+		roles: roles,
+	}
+}
+
+func (c collectionContext) Premium() bool {
+	// CodeCommit doesn't have paid tiers
+	return true
+}
+
+func (c collectionContext) Roles() []permissions.Role {
+	return c.roles
+}
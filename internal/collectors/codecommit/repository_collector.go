@@ -0,0 +1,230 @@
+package codecommit
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/Legit-Labs/legitify/internal/clients/codecommit"
+	"github.com/Legit-Labs/legitify/internal/collected/codecommit_collected"
+	"github.com/Legit-Labs/legitify/internal/collectors"
+	"github.com/Legit-Labs/legitify/internal/common/group_waiter"
+	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/Legit-Labs/legitify/internal/common/permissions"
+	"github.com/Legit-Labs/legitify/internal/context_utils"
+	"github.com/Legit-Labs/legitify/internal/logger"
+	"golang.org/x/net/context"
+)
+
+type repositoryCollector struct {
+	collectors.BaseCollector
+	Client  *codecommit.Client
+	Context context.Context
+}
+
+func NewRepositoryCollector(ctx context.Context, client *codecommit.Client) collectors.Collector {
+	c := &repositoryCollector{
+		Client:  client,
+		Context: ctx,
+	}
+	collectors.InitBaseCollector(&c.BaseCollector, c)
+	return c
+}
+
+func (c *repositoryCollector) Namespace() namespace.Namespace {
+	return namespace.Repository
+}
+
+func (c *repositoryCollector) CollectMetadata() collectors.Metadata {
+	repositories, err := c.repositories()
+	res := collectors.Metadata{}
+
+	if err != nil {
+		logger.WithError(err).Errorf("failed to collect repositories")
+	} else {
+		res.TotalEntities = len(repositories)
+	}
+
+	return res
+}
+
+// repositories returns the metadata of the repositories, filtered by the selected repositories (--repo)
+func (c *repositoryCollector) repositories() ([]*codecommit.RepositoryMetadata, error) {
+	repositories, err := c.Client.RepositoriesMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	selected, ok := context_utils.GetRepositories(c.Context)
+	if !ok {
+		return repositories, nil
+	}
+
+	var result []*codecommit.RepositoryMetadata
+	for _, r := range repositories {
+		for _, s := range selected {
+			if s.Owner == r.AccountID && s.Name == r.RepositoryName {
+				result = append(result, r)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (c *repositoryCollector) Collect() collectors.SubCollectorChannels {
+	return c.WrappedCollection(func() {
+		repositories, err := c.repositories()
+		if err != nil {
+			logger.WithError(err).Errorf("failed to collect repositories")
+			return
+		}
+
+		authDetails, err := c.Client.AuthorizationDetails()
+		if err != nil {
+			authDetails = nil
+			logger.WithError(err).Errorf("failed to collect the IAM authorization details, the repositories' access won't be collected")
+		}
+
+		// the repositories are reported by their account, which is finished once all of its repositories were collected
+		var accounts []string
+		for _, r := range repositories {
+			accounts = append(accounts, r.AccountID)
+		}
+		countdown := c.NewOrganizationsCountdown(accounts)
+
+		gw := group_waiter.New()
+
+		for _, r := range repositories {
+			r := r
+			gw.Do(func() {
+				defer countdown.Done(r.AccountID)
+				approvalRules, err := c.collectApprovalRules(r)
+				if err != nil {
+					logger.With(logger.Fields{"repository": r.RepositoryName}).WithError(err).Errorf("failed to query repository approval rule templates")
+				}
+
+				triggers, err := c.collectTriggers(r)
+				if err != nil {
+					logger.With(logger.Fields{"repository": r.RepositoryName}).WithError(err).Errorf("failed to query repository triggers")
+				}
+
+				var access []codecommit_collected.Access
+				if authDetails != nil {
+					access = collectAccess(authDetails, r.Arn)
+				}
+
+				entity := codecommit_collected.Repository{
+					AccountID:      r.AccountID,
+					Region:         c.Client.Region(),
+					RepositoryID:   r.RepositoryID,
+					RepositoryName: r.RepositoryName,
+					Description:    r.Description,
+					Arn:            r.Arn,
+					DefaultBranch:  r.DefaultBranch,
+					ApprovalRules:  approvalRules,
+					Triggers:       triggers,
+					Access:         access,
+				}
+
+				c.CollectDataWithContext(r.AccountID, &entity, entity.CanonicalLink(), newCollectionContext([]permissions.Role{permissions.RepoRoleAdmin}))
+				c.CollectionChangeByOne()
+			})
+		}
+
+		gw.Wait()
+	})
+}
+
+type approvalRuleTemplateContent struct {
+	DestinationReferences []string `json:"DestinationReferences"`
+	Statements            []struct {
+		Type                    string   `json:"Type"`
+		NumberOfApprovalsNeeded int      `json:"NumberOfApprovalsNeeded"`
+		ApprovalPoolMembers     []string `json:"ApprovalPoolMembers"`
+	} `json:"Statements"`
+}
+
+// collectApprovalRules collects the approval rule templates associated with the repository
+func (c *repositoryCollector) collectApprovalRules(r *codecommit.RepositoryMetadata) ([]codecommit_collected.ApprovalRule, error) {
+	templates, err := c.Client.AssociatedApprovalRuleTemplates(r.RepositoryName)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []codecommit_collected.ApprovalRule{}
+	for _, template := range templates {
+		var content approvalRuleTemplateContent
+		if err = json.Unmarshal([]byte(template.Content), &content); err != nil {
+			logger.With(logger.Fields{"template": template.Name}).WithError(err).Warnf("failed to parse the approval rule template")
+			continue
+		}
+
+		rule := codecommit_collected.ApprovalRule{
+			Template:              template.Name,
+			ApprovalPoolMembers:   []string{},
+			DestinationReferences: []string{},
+		}
+		for _, statement := range content.Statements {
+			if statement.NumberOfApprovalsNeeded > rule.RequiredApprovals {
+				rule.RequiredApprovals = statement.NumberOfApprovalsNeeded
+			}
+			rule.ApprovalPoolMembers = append(rule.ApprovalPoolMembers, statement.ApprovalPoolMembers...)
+		}
+		rule.DestinationReferences = append(rule.DestinationReferences, content.DestinationReferences...)
+		rule.AppliesToDefaultBranch = len(content.DestinationReferences) == 0
+		for _, ref := range content.DestinationReferences {
+			if ref == "refs/heads/"+r.DefaultBranch {
+				rule.AppliesToDefaultBranch = true
+			}
+		}
+		result = append(result, rule)
+	}
+
+	return result, nil
+}
+
+// collectTriggers collects the repository triggers, with whether their destinations are in other accounts
+func (c *repositoryCollector) collectTriggers(r *codecommit.RepositoryMetadata) ([]codecommit_collected.Trigger, error) {
+	triggers, err := c.Client.RepositoryTriggers(r.RepositoryName)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []codecommit_collected.Trigger{}
+	for _, trigger := range triggers {
+		result = append(result, codecommit_collected.Trigger{
+			Name:           trigger.Name,
+			DestinationArn: trigger.DestinationArn,
+			Events:         trigger.Events,
+			Branches:       trigger.Branches,
+			CrossAccount:   arnAccount(trigger.DestinationArn) != r.AccountID,
+		})
+	}
+	return result, nil
+}
+
+// arnAccount returns the account ID of the ARN (arn:partition:service:region:account-id:resource)
+func arnAccount(arn string) string {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) < 6 {
+		return ""
+	}
+	return parts[4]
+}
+
+// collectAccess summarizes the access levels the IAM principals of the account have to the repository
+func collectAccess(details *codecommit.AuthorizationDetails, repositoryArn string) []codecommit_collected.Access {
+	result := []codecommit_collected.Access{}
+	for _, principal := range details.Principals {
+		level := details.AccessLevel(principal, repositoryArn)
+		if level == codecommit.AccessNone {
+			continue
+		}
+		result = append(result, codecommit_collected.Access{
+			Type:  principal.Type,
+			Name:  principal.Name,
+			Arn:   principal.Arn,
+			Level: level,
+		})
+	}
+	return result
+}
@@ -14,6 +14,8 @@ type CollectionMetric struct {
 	CollectionChange int
 	Finished         bool
 	Namespace        string
+	// Organization is set (with Finished) when the collection of a single organization is finished
+	Organization string
 }
 
 type CollectedDataContext interface {
@@ -26,6 +28,8 @@ type CollectedData struct {
 	Entity        collected.Entity
 	Namespace     namespace.Namespace
 	CanonicalLink string
+	// Organization is the organization (or owner) of the entity
+	Organization string
 }
 
 type SubCollectorChannels struct {
@@ -2,6 +2,8 @@ package collectors
 
 import (
 	"fmt"
+	"sync"
+
 	"github.com/Legit-Labs/legitify/internal/collected"
 	githubcollected "github.com/Legit-Labs/legitify/internal/collected/github"
 	"github.com/Legit-Labs/legitify/internal/common/permissions"
@@ -41,6 +43,7 @@ func (b *BaseCollector) CollectData(org githubcollected.ExtendedOrg, entity coll
 		Entity:        entity,
 		Namespace:     b.Namespace(),
 		CanonicalLink: canonicalLink,
+		Organization:  org.Name(),
 		Context: &collectedDataContext{
 			roles:        viewerRoles,
 			organization: org,
@@ -48,12 +51,13 @@ func (b *BaseCollector) CollectData(org githubcollected.ExtendedOrg, entity coll
 	}
 }
 
-func (b *BaseCollector) CollectDataWithContext(entity collected.Entity, canonicalLink string, ctx CollectedDataContext) {
+func (b *BaseCollector) CollectDataWithContext(org string, entity collected.Entity, canonicalLink string, ctx CollectedDataContext) {
 
 	b.collectedChan <- CollectedData{
 		Entity:        entity,
 		Namespace:     b.Namespace(),
 		CanonicalLink: canonicalLink,
+		Organization:  org,
 		Context:       ctx,
 	}
 }
@@ -69,6 +73,48 @@ func (b *BaseCollector) CollectionChangeByOne() {
 	b.CollectionChange(1)
 }
 
+// OrganizationFinished reports that all the entities of the organization were collected
+func (b *BaseCollector) OrganizationFinished(org string) {
+	b.progressChan <- CollectionMetric{
+		Namespace:    b.Namespace(),
+		Finished:     true,
+		Organization: org,
+	}
+}
+
+// OrganizationsCountdown reports each organization as finished once all of its entities were collected, for collectors
+// that collect the entities of all the organizations together
+type OrganizationsCountdown struct {
+	collector *BaseCollector
+	mutex     sync.Mutex
+	remaining map[string]int
+}
+
+// NewOrganizationsCountdown counts the entities of each organization (an organization per entity)
+func (b *BaseCollector) NewOrganizationsCountdown(orgs []string) *OrganizationsCountdown {
+	remaining := make(map[string]int)
+	for _, org := range orgs {
+		remaining[org]++
+	}
+	return &OrganizationsCountdown{
+		collector: b,
+		remaining: remaining,
+	}
+}
+
+// Done records that an entity of the organization was handled (collected or not), and reports the organization as finished
+// after its last entity
+func (c *OrganizationsCountdown) Done(org string) {
+	c.mutex.Lock()
+	c.remaining[org]--
+	last := c.remaining[org] == 0
+	c.mutex.Unlock()
+
+	if last {
+		c.collector.OrganizationFinished(org)
+	}
+}
+
 func (b *BaseCollector) IssueMissingPermissions(missingPermissions ...MissingPermission) {
 	for _, p := range missingPermissions {
 		b.missingPermChan <- p
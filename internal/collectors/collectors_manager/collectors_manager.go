@@ -1,15 +1,25 @@
 package collectors_manager
 
 import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
 	"github.com/Legit-Labs/legitify/internal/collectors"
 	"github.com/Legit-Labs/legitify/internal/common/group_waiter"
+	"github.com/Legit-Labs/legitify/internal/metrics"
+	"github.com/Legit-Labs/legitify/internal/tracing"
 
 	"github.com/Legit-Labs/legitify/internal/common/namespace"
 )
 
 type CollectorChannels struct {
 	Collected <-chan collectors.CollectedData
-	Progress  <-chan collectors.CollectionMetric
+	// Progress also reports each organization once all the collectors finished collecting it
+	// (a metric with the Organization set and no Namespace)
+	Progress <-chan collectors.CollectionMetric
 }
 
 type CollectorManager interface {
@@ -66,8 +76,13 @@ func (m *manager) Collect() CollectorChannels {
 			collectors.CollectMissingPermissions(missingPermissionsChannel)
 		})
 
+		organizations := newOrganizationsTracker(len(m.collectors))
 		gw := group_waiter.New()
 		for _, c := range m.collectors {
+			c := c
+			start := time.Now()
+			_, span := tracing.Start(context.Background(), fmt.Sprintf("collect %s", c.Namespace()))
+			span.SetAttribute("namespace", c.Namespace())
 			collectionChannels := c.Collect()
 
 			gw.Do(func() {
@@ -80,6 +95,10 @@ func (m *manager) Collect() CollectorChannels {
 					case x, ok := <-pb:
 						if !ok {
 							pb = nil
+						} else if x.Organization != "" {
+							if organizations.finished(x.Organization) {
+								progressChan <- organizationFinished(x.Organization)
+							}
 						} else {
 							progressChan <- x
 						}
@@ -102,6 +121,8 @@ func (m *manager) Collect() CollectorChannels {
 					}
 				}
 
+				metrics.Default().RecordCollectionDuration(c.Namespace(), time.Since(start))
+				span.End(nil)
 				progressChan <- collectors.CollectionMetric{
 					Finished:  true,
 					Namespace: c.Namespace(),
@@ -109,6 +130,10 @@ func (m *manager) Collect() CollectorChannels {
 			})
 		}
 		gw.Wait()
+		// organizations that weren't reported by all the collectors (e.g. due to errors) are finished as well
+		for _, org := range organizations.unfinished() {
+			progressChan <- organizationFinished(org)
+		}
 		close(missingPermissionsChannel)
 		permWait.Wait()
 	}()
@@ -118,3 +143,49 @@ func (m *manager) Collect() CollectorChannels {
 		Progress:  progressChan,
 	}
 }
+
+func organizationFinished(org string) collectors.CollectionMetric {
+	return collectors.CollectionMetric{
+		Finished:     true,
+		Organization: org,
+	}
+}
+
+// organizationsTracker counts the collectors that finished collecting each organization
+type organizationsTracker struct {
+	mutex      sync.Mutex
+	collectors int
+	counts     map[string]int
+}
+
+func newOrganizationsTracker(collectors int) *organizationsTracker {
+	return &organizationsTracker{
+		collectors: collectors,
+		counts:     make(map[string]int),
+	}
+}
+
+// finished records that a collector finished the organization, and returns whether all the collectors finished it
+func (t *organizationsTracker) finished(org string) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.counts[org]++
+	return t.counts[org] == t.collectors
+}
+
+// unfinished returns the (sorted) organizations that some of the collectors didn't finish
+func (t *organizationsTracker) unfinished() []string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var orgs []string
+	for org, count := range t.counts {
+		if count < t.collectors {
+			orgs = append(orgs, org)
+		}
+	}
+	sort.Strings(orgs)
+
+	return orgs
+}
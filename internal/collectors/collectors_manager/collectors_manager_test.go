@@ -0,0 +1,18 @@
+package collectors_manager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrganizationsTracker(t *testing.T) {
+	tracker := newOrganizationsTracker(2)
+
+	require.False(t, tracker.finished("org1"))
+	require.False(t, tracker.finished("org2"))
+	require.True(t, tracker.finished("org1"))
+	require.False(t, tracker.finished("org3"))
+
+	require.Equal(t, []string{"org2", "org3"}, tracker.unfinished())
+}
@@ -2,18 +2,27 @@ package github
 
 import (
 	"fmt"
+	"time"
+
 	"github.com/Legit-Labs/legitify/internal/collectors"
-	"log"
+	"github.com/Legit-Labs/legitify/internal/common/rotation"
+	"github.com/Legit-Labs/legitify/internal/context_utils"
+	"github.com/Legit-Labs/legitify/internal/logger"
 
 	ghclient "github.com/Legit-Labs/legitify/internal/clients/github"
+	"github.com/Legit-Labs/legitify/internal/clients/github/types"
 	ghcollected "github.com/Legit-Labs/legitify/internal/collected/github"
 	"github.com/Legit-Labs/legitify/internal/common/namespace"
 	"github.com/Legit-Labs/legitify/internal/common/permissions"
+	"github.com/google/go-github/v44/github"
 	"golang.org/x/net/context"
 )
 
 const (
-	orgActionPermEffect = "Cannot read organization actions settings"
+	// workflowsRuleType is the type of the ruleset rule that requires workflows to pass
+	workflowsRuleType = "workflows"
+	// selectedActionsPolicy is the allowed_actions value of an allow-list of actions
+	selectedActionsPolicy = "selected"
 )
 
 type actionCollector struct {
@@ -40,7 +49,7 @@ func (c *actionCollector) CollectMetadata() collectors.Metadata {
 	res := collectors.Metadata{}
 
 	if err != nil {
-		log.Printf("failed to collect organizations %s", err)
+		logger.WithError(err).Errorf("failed to collect organizations")
 	} else {
 		res.TotalEntities = len(orgs)
 	}
@@ -53,17 +62,64 @@ func (c *actionCollector) Collect() collectors.SubCollectorChannels {
 		orgs, err := c.client.CollectOrganizations()
 
 		if err != nil {
-			log.Printf("failed to collect organizations %s", err)
+			logger.WithError(err).Errorf("failed to collect organizations")
 			return
 		}
 
 		for _, org := range orgs {
 			actionsPermissions, err1 := c.client.GetActionsTokenPermissionsForOrganization(org.Name())
-			actionsData, _, err2 := c.client.Client().Organizations.GetActionsPermissions(c.context, org.Name())
+			actionsData, err2 := c.client.GetActionsPermissionsForOrganization(org.Name())
 
 			if err1 != nil || err2 != nil {
 				entityName := fmt.Sprintf("%s/%s", namespace.Organization, org.Name())
-				perm := collectors.NewMissingPermission(permissions.OrgAdmin, entityName, orgActionPermEffect, namespace.Organization)
+				perm := orgActionsSettingsPermission.Missing(entityName)
+				c.IssueMissingPermissions(perm)
+			}
+
+			var selectedActions *github.ActionsAllowed
+			if actionsData != nil && actionsData.AllowedActions != nil && *actionsData.AllowedActions == selectedActionsPolicy {
+				var err error
+				selectedActions, _, err = c.client.Client().Organizations.GetActionsAllowed(c.context, org.Name())
+				if err != nil {
+					// If we can't get the allowed actions, rego will ignore it (as nil)
+					logger.With(logger.Fields{"org": org.Name()}).WithError(err).Errorf("error getting the allowed actions")
+				}
+			}
+
+			var oidcSubjectClaim *types.OIDCSubjectClaim
+			if c.client.IsFeatureSupported(ghclient.FeatureOIDCSubjectClaim) {
+				oidcSubjectClaim, err = c.client.GetOIDCSubjectClaimForOrganization(org.Name())
+				if err != nil {
+					// If we can't get the subject claim template, rego will ignore it (as nil)
+					logger.With(logger.Fields{"org": org.Name()}).WithError(err).Errorf("error getting the actions OIDC subject claim")
+				}
+			} else {
+				c.IssueMissingPermissions(collectors.NewUnsupportedFeature(org.Name(), "Cannot read the actions OIDC subject claim", namespace.Organization))
+			}
+
+			secrets, err := c.collectSecrets(org.Name())
+			if err != nil {
+				entityName := fmt.Sprintf("%s/%s", namespace.Organization, org.Name())
+				perm := orgActionsSecretsPermission.Missing(entityName)
+				c.IssueMissingPermissions(perm)
+			}
+
+			var forkPRApproval *types.ForkPRContributorApproval
+			if c.client.IsFeatureSupported(ghclient.FeatureForkPRContributorApproval) {
+				forkPRApproval, err = c.client.GetForkPRContributorApprovalForOrganization(org.Name())
+				if err != nil {
+					// If we can't get the fork pull requests approval policy, rego will ignore it (as nil)
+					logger.With(logger.Fields{"org": org.Name()}).WithError(err).Errorf("error getting the fork pull requests approval policy")
+				}
+			} else {
+				c.IssueMissingPermissions(collectors.NewUnsupportedFeature(org.Name(), "Cannot read the fork pull requests approval policy", namespace.Organization))
+			}
+
+			requiredWorkflows, workflowRulesets, err := c.collectRequiredWorkflows(org.Name())
+			if err != nil {
+				logger.With(logger.Fields{"org": org.Name()}).WithError(err).Errorf("error getting the required workflows")
+				entityName := fmt.Sprintf("%s/%s", namespace.Organization, org.Name())
+				perm := orgRequiredWorkflowsPermission.Missing(entityName)
 				c.IssueMissingPermissions(perm)
 			}
 
@@ -73,10 +129,79 @@ func (c *actionCollector) Collect() collectors.SubCollectorChannels {
 				ghcollected.OrganizationActions{
 					Organization:       org,
 					ActionsPermissions: actionsData,
+					SelectedActions:    selectedActions,
 					TokenPermissions:   actionsPermissions,
+					OIDCSubjectClaim:   oidcSubjectClaim,
+					Secrets:            secrets,
+					ForkPRApproval:     forkPRApproval,
+					RequiredWorkflows:  requiredWorkflows,
+					WorkflowRulesets:   workflowRulesets,
+					SecretMaxAge:       context_utils.GetSecretMaxAge(c.context).PolicyInput(),
 				},
 				org.CanonicalLink(),
 				[]permissions.Role{org.Role})
+			c.OrganizationFinished(org.Name())
 		}
 	})
 }
+
+// collectSecrets lists the organization actions secrets (names and metadata only) and computes their age
+func (c *actionCollector) collectSecrets(org string) ([]ghcollected.ActionsSecret, error) {
+	var result []ghcollected.ActionsSecret
+	now := time.Now()
+
+	err := ghclient.PaginateResults(func(opts *github.ListOptions) (*github.Response, error) {
+		secrets, resp, err := c.client.Client().Actions.ListOrgSecrets(c.context, org, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, secret := range secrets.Secrets {
+			collected := ghcollected.ActionsSecret{
+				Name:       secret.Name,
+				Visibility: secret.Visibility,
+			}
+			if !secret.UpdatedAt.IsZero() {
+				updatedAt := secret.UpdatedAt.Time
+				age := rotation.AgeInDays(updatedAt, now)
+				collected.UpdatedAt = &updatedAt
+				collected.AgeDays = &age
+			}
+			result = append(result, collected)
+		}
+
+		return resp, nil
+	})
+
+	return result, err
+}
+
+// collectRequiredWorkflows collects both the (legacy) required workflows and the rulesets that require workflows
+func (c *actionCollector) collectRequiredWorkflows(org string) ([]types.RequiredWorkflow, []types.Ruleset, error) {
+	requiredWorkflows, err := c.client.GetRequiredWorkflows(org)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !c.client.IsFeatureSupported(ghclient.FeatureOrganizationRulesets) {
+		c.IssueMissingPermissions(collectors.NewUnsupportedFeature(org, "Cannot read the organization rulesets that require workflows", namespace.Organization))
+		return requiredWorkflows, nil, nil
+	}
+
+	rulesets, err := c.client.GetOrganizationRulesets(org)
+	if err != nil {
+		return requiredWorkflows, nil, err
+	}
+
+	var workflowRulesets []types.Ruleset
+	for _, ruleset := range rulesets {
+		for _, rule := range ruleset.Rules {
+			if rule.Type == workflowsRuleType {
+				workflowRulesets = append(workflowRulesets, ruleset)
+				break
+			}
+		}
+	}
+
+	return requiredWorkflows, workflowRulesets, nil
+}
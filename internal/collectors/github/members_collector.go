@@ -2,8 +2,10 @@ package github
 
 import (
 	"fmt"
+	"github.com/Legit-Labs/legitify/internal/activity"
 	"github.com/Legit-Labs/legitify/internal/collectors"
-	"log"
+	"github.com/Legit-Labs/legitify/internal/logger"
+	"strings"
 	"time"
 
 	"github.com/Legit-Labs/legitify/internal/common/group_waiter"
@@ -12,6 +14,7 @@ import (
 	ghclient "github.com/Legit-Labs/legitify/internal/clients/github"
 	ghcollected "github.com/Legit-Labs/legitify/internal/collected/github"
 	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/Legit-Labs/legitify/internal/context_utils"
 	"github.com/google/go-github/v44/github"
 	"github.com/shurcooL/githubv4"
 	"golang.org/x/net/context"
@@ -49,7 +52,7 @@ func (c *memberCollector) CollectMetadata() collectors.Metadata {
 	orgs, err := c.Client.CollectOrganizations()
 
 	if err != nil {
-		log.Printf("failed to collect organization %s", err)
+		logger.WithError(err).Errorf("failed to collect organizations")
 		return collectors.Metadata{}
 	}
 
@@ -84,7 +87,7 @@ func (c *memberCollector) Collect() collectors.SubCollectorChannels {
 		orgs, err := c.Client.CollectOrganizations()
 
 		if err != nil {
-			log.Printf("failed to collect organizations %s", err)
+			logger.WithError(err).Errorf("failed to collect organizations")
 			return
 		}
 
@@ -110,14 +113,22 @@ func (c *memberCollector) Collect() collectors.SubCollectorChannels {
 
 			}
 
+			c.matchIdentitySource(enrichedMembers)
+			activityWindowDays := c.collectAuditLogActivity(org, enrichedMembers)
+			c.collectAdminsActivity(org, enrichedMembers)
+
 			c.CollectData(org,
 				ghcollected.OrganizationMembers{
-					Organization:  org,
-					Members:       enrichedMembers,
-					HasLastActive: hasLastActive,
+					Organization:       org,
+					Members:            enrichedMembers,
+					HasLastActive:      hasLastActive,
+					PendingInvitations: c.collectPendingInvitations(&org),
+					ActivityWindowDays: activityWindowDays,
+					DormantAdminDays:   context_utils.GetDormantAdminDays(c.Context),
 				},
 				org.CanonicalLink(),
 				[]permissions.Role{org.Role})
+			c.OrganizationFinished(org.Name())
 		}
 	})
 }
@@ -135,9 +146,12 @@ func (c *memberCollector) enrichMembers(org *ghcollected.ExtendedOrg, members []
 				c.IssueMissingPermissions(perm)
 				return
 			}
+			// members without any audit log event are kept (with no last active time), for the audit log activity policies
+			var lastActive int
 			if !memberLastActive.IsZero() {
-				resChannel <- ghcollected.NewOrganizationMember(localMember, int(memberLastActive.UnixNano()), memberType)
+				lastActive = int(memberLastActive.UnixNano())
 			}
+			resChannel <- ghcollected.NewOrganizationMember(localMember, lastActive, memberType)
 		})
 	}
 
@@ -164,7 +178,7 @@ func (c *memberCollector) collectMembers(org, memberType string) []*github.User
 		members, resp, err := c.Client.Client().Organizations.ListMembers(c.Context, org, listMemOpts)
 
 		if err != nil {
-			log.Printf("error collecting members of type %s for org %s: %s\n", memberType, org, err)
+			logger.With(logger.Fields{"org": org, "member_type": memberType}).WithError(err).Errorf("error collecting members")
 			return nil, err
 		}
 
@@ -175,6 +189,29 @@ func (c *memberCollector) collectMembers(org, memberType string) []*github.User
 	return membersByType
 }
 
+func (c *memberCollector) collectPendingInvitations(org *ghcollected.ExtendedOrg) []ghcollected.OrganizationInvitation {
+	invitations := []ghcollected.OrganizationInvitation{}
+
+	err := ghclient.PaginateResults(func(opts *github.ListOptions) (*github.Response, error) {
+		pending, resp, err := c.Client.Client().Organizations.ListPendingOrgInvitations(c.Context, org.Name(), opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, invitation := range pending {
+			invitations = append(invitations, ghcollected.NewOrganizationInvitation(invitation))
+		}
+		return resp, nil
+	})
+
+	if err != nil {
+		perm := orgInvitationsPermission.Missing(org.Name())
+		c.IssueMissingPermissions(perm)
+	}
+
+	return invitations
+}
+
 // collectMemberLastActiveTime will search and retrieve the most recent timestamp where a member was seen active,
 // based on both web and git activity.
 // Note: Org must be part of an enterprise.
@@ -202,15 +239,87 @@ func (c *memberCollector) collectMemberLastActiveTime(org, actor string) (*time.
 	return &LastActive, nil
 }
 
+// collectAuditLogActivity sets the most recent audit log activity of the members, if it was requested (enterprise organizations only).
+// The events of the whole window are read at once, so members without any activity in the window are detected as well.
+// Returns the number of days that were collected (0 if none were).
+func (c *memberCollector) collectAuditLogActivity(org ghcollected.ExtendedOrg, members []ghcollected.OrganizationMember) int {
+	days := context_utils.GetAuditLogActivityDays(c.Context)
+	if days == 0 || !org.IsEnterprise() {
+		return 0
+	}
+
+	entries, err := c.Client.GetAuditLogEntries(org.Name(), activity.Phrase(days, time.Now()))
+	if err != nil {
+		perm := orgAuditLogActivityPermission.Missing(org.Name())
+		c.IssueMissingPermissions(perm)
+		logger.With(logger.Fields{"org": org.Name()}).WithError(err).Errorf("failed to collect the audit log activity")
+		return 0
+	}
+
+	lastActive := activity.LastActive(entries)
+	for i := range members {
+		var nanos int
+		if last, ok := lastActive[strings.ToLower(members[i].User.GetLogin())]; ok {
+			nanos = int(last.UnixNano())
+		}
+		members[i].AuditLogLastActive = &nanos
+	}
+
+	return days
+}
+
+// collectAdminsActivity sets the most recent activity of the admins: from the audit log for enterprise organizations,
+// otherwise from the admins' recent events
+func (c *memberCollector) collectAdminsActivity(org ghcollected.ExtendedOrg, members []ghcollected.OrganizationMember) {
+	for i := range members {
+		if !members[i].IsAdmin {
+			continue
+		}
+
+		if org.IsEnterprise() {
+			lastActive := members[i].LastActive
+			if members[i].AuditLogLastActive != nil && *members[i].AuditLogLastActive > lastActive {
+				lastActive = *members[i].AuditLogLastActive
+			}
+			members[i].Activity = &ghcollected.MemberActivity{LastActive: lastActive, Source: ghcollected.ActivitySourceAuditLog}
+			continue
+		}
+
+		login := members[i].User.GetLogin()
+		events, _, err := c.Client.Client().Activity.ListEventsPerformedByUser(c.Context, login, false, &github.ListOptions{PerPage: 1})
+		if err != nil {
+			logger.With(logger.Fields{"org": org.Name(), "member": login}).WithError(err).Errorf("failed to collect the admin's recent events")
+			continue
+		}
+		activity := &ghcollected.MemberActivity{Source: ghcollected.ActivitySourceEvents}
+		if len(events) > 0 {
+			activity.LastActive = int(events[0].GetCreatedAt().UnixNano())
+		}
+		members[i].Activity = activity
+	}
+}
+
+// matchIdentitySource marks the members that are known to the identity source (if one was provided)
+func (c *memberCollector) matchIdentitySource(members []ghcollected.OrganizationMember) {
+	source, ok := context_utils.GetIdentitySource(c.Context)
+	if !ok {
+		return
+	}
+
+	for i := range members {
+		user := members[i].User
+		found := source.Contains(user.GetLogin(), user.GetEmail())
+		members[i].InIdentitySource = &found
+	}
+}
+
 const (
-	orgMemberLastActiveEffect = "Cannot read organization member last active time"
-	orgInfoEffect             = "Cannot read organization information"
-	orgNotEnterpriseEffect    = "Some information cannot be collected because the organization is not part of an enterprise"
+	orgNotEnterpriseEffect = "Some information cannot be collected because the organization is not part of an enterprise"
 )
 
 func (c *memberCollector) memberMissingPermission(org *ghcollected.ExtendedOrg, member *github.User) collectors.MissingPermission {
 	entityName := fmt.Sprintf("%s (%s)", *member.Login, org.Name())
-	return collectors.NewMissingPermission(permissions.OrgAdmin, entityName, orgMemberLastActiveEffect, namespace.Member)
+	return orgMemberLastActivePermission.Missing(entityName)
 }
 
 func (c *memberCollector) checkOrgMissingPermissions(org ghcollected.ExtendedOrg) []collectors.MissingPermission {
@@ -218,7 +327,7 @@ func (c *memberCollector) checkOrgMissingPermissions(org ghcollected.ExtendedOrg
 	entityName := org.Name()
 
 	if org.Plan == nil {
-		perm := collectors.NewMissingPermission(permissions.OrgRead, entityName, orgInfoEffect, namespace.Organization)
+		perm := orgInfoPermission.Missing(entityName)
 		missingPermissions = append(missingPermissions, perm)
 	} else if !org.IsEnterprise() {
 		perm := collectors.NewMissingPermission(permissions.OrgRead, entityName, orgNotEnterpriseEffect, namespace.Organization)
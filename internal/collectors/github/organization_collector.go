@@ -1,8 +1,18 @@
 package github
 
 import (
+	"errors"
+	"fmt"
+	"github.com/Legit-Labs/legitify/internal/clients/github/types"
 	"github.com/Legit-Labs/legitify/internal/collectors"
-	"log"
+	"github.com/Legit-Labs/legitify/internal/context_utils"
+	"github.com/Legit-Labs/legitify/internal/ghas"
+	"github.com/Legit-Labs/legitify/internal/logger"
+	"github.com/Legit-Labs/legitify/internal/webhooks"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/google/go-github/v44/github"
 
@@ -49,7 +59,7 @@ func (c *organizationCollector) CollectMetadata() collectors.Metadata {
 	res := collectors.Metadata{}
 
 	if err != nil {
-		log.Printf("failed to collect organizations %s", err)
+		logger.WithError(err).Errorf("failed to collect organizations")
 	} else {
 		res.TotalEntities = len(orgs)
 	}
@@ -62,7 +72,7 @@ func (c *organizationCollector) Collect() collectors.SubCollectorChannels {
 		orgs, err := c.Client.CollectOrganizations()
 
 		if err != nil {
-			log.Printf("failed to collect organizations %s", err)
+			logger.WithError(err).Errorf("failed to collect organizations")
 			return
 		}
 
@@ -73,6 +83,7 @@ func (c *organizationCollector) Collect() collectors.SubCollectorChannels {
 				extend := c.collectExtraData(&org)
 				c.CollectData(org, extend, *extend.Organization.HTMLURL, []permissions.Role{org.Role})
 				c.CollectionChangeByOne()
+				c.OrganizationFinished(org.Name())
 			})
 		}
 		gw.Wait()
@@ -84,22 +95,330 @@ func (c *organizationCollector) collectExtraData(org *ghcollected.ExtendedOrg) g
 
 	if err != nil {
 		samlEnabled = nil
-		log.Printf("failed to collect saml data for %s, %s", org.Name(), err)
+		logger.With(logger.Fields{"org": org.Name()}).WithError(err).Errorf("failed to collect saml data")
 	}
 
 	hooks, err := c.collectOrgWebhooks(org.Name())
 	if err != nil {
 		hooks = nil
-		log.Printf("failed to collect webhooks data for %s, %s", org.Name(), err)
+		logger.With(logger.Fields{"org": org.Name()}).WithError(err).Errorf("failed to collect webhooks data")
+	}
+
+	var hooksHealth []webhooks.Health
+	var hooksSettings []webhooks.Settings
+	deliveriesSupported := c.Client.IsFeatureSupported(ghclient.FeatureWebhookDeliveries)
+	if !deliveriesSupported && len(hooks) > 0 {
+		c.IssueMissingPermissions(collectors.NewUnsupportedFeature(org.Name(), "Cannot read the organization webhook deliveries", namespace.Organization))
+	}
+	for _, hook := range hooks {
+		hooksSettings = append(hooksSettings, webhooks.NewSettings(hook, context_utils.GetWebhookDomains(c.Context)))
+		if !deliveriesSupported {
+			continue
+		}
+		health, err := collectWebhookHealth(hook, func(opts *github.ListCursorOptions) ([]*github.HookDelivery, *github.Response, error) {
+			return c.Client.Client().Organizations.ListHookDeliveries(c.Context, org.Name(), hook.GetID(), opts)
+		})
+		if err != nil {
+			logger.With(logger.Fields{"org": org.Name()}).WithError(err).Errorf("failed to collect webhook %d deliveries", hook.GetID())
+			continue
+		}
+		hooksHealth = append(hooksHealth, health)
+	}
+
+	installations, err := c.collectAppInstallations(org.Name())
+	if err != nil {
+		installations = nil
+		logger.With(logger.Fields{"org": org.Name()}).WithError(err).Errorf("failed to collect app installations")
+	}
+
+	domains, err := c.collectDomains(org.Name())
+	if err != nil {
+		domains = nil
+		logger.With(logger.Fields{"org": org.Name()}).WithError(err).Errorf("failed to collect domains")
+	}
+
+	securityManagers, err := c.collectSecurityManagers(org.Name())
+	if err != nil {
+		securityManagers = nil
+		logger.With(logger.Fields{"org": org.Name()}).WithError(err).Errorf("failed to collect security managers")
+	}
+
+	codespaces, err := c.collectCodespaces(org.Name())
+	if err != nil {
+		codespaces = nil
+		logger.With(logger.Fields{"org": org.Name()}).WithError(err).Errorf("failed to collect codespaces")
+	}
+
+	packages, err := c.collectPackages(org.Name())
+	if err != nil {
+		packages = nil
+		logger.With(logger.Fields{"org": org.Name()}).WithError(err).Errorf("failed to collect packages")
+	}
+
+	projects, err := c.collectProjects(org.Name())
+	if err != nil {
+		projects = nil
+		logger.With(logger.Fields{"org": org.Name()}).WithError(err).Errorf("failed to collect projects")
+	}
+
+	interactionLimit, err := c.collectInteractionLimit(org.Name())
+	if err != nil {
+		interactionLimit = nil
+		logger.With(logger.Fields{"org": org.Name()}).WithError(err).Errorf("failed to collect the interaction limit")
+	}
+
+	var transfers []ghcollected.RepositoryTransfer
+	var oauthRestrictions *ghcollected.OAuthAppRestrictions
+	var customRoles []types.CustomRepositoryRole
+	var ipAllowList *ghcollected.IPAllowList
+	var samlSSO *ghcollected.SamlSSO
+	if org.IsEnterprise() {
+		transfers, err = c.collectRepositoryTransfers(org.Name())
+		if err != nil {
+			transfers = nil
+			logger.With(logger.Fields{"org": org.Name()}).WithError(err).Errorf("failed to collect repository transfers")
+		}
+
+		oauthRestrictions, err = c.collectOAuthAppRestrictions(org.Name())
+		if err != nil {
+			oauthRestrictions = nil
+			logger.With(logger.Fields{"org": org.Name()}).WithError(err).Errorf("failed to collect oauth app restrictions")
+		}
+
+		if c.Client.IsFeatureSupported(ghclient.FeatureCustomRepositoryRoles) {
+			customRoles, err = c.Client.GetCustomRepositoryRoles(org.Name())
+			if err != nil {
+				customRoles = nil
+				perm := orgCustomRolesPermission.Missing(org.Name())
+				c.IssueMissingPermissions(perm)
+				logger.With(logger.Fields{"org": org.Name()}).WithError(err).Errorf("failed to collect custom repository roles")
+			}
+		} else {
+			c.IssueMissingPermissions(collectors.NewUnsupportedFeature(org.Name(), "Cannot read the custom repository roles", namespace.Organization))
+		}
+
+		ipAllowList, err = c.collectIPAllowList(org.Name())
+		if err != nil {
+			ipAllowList = nil
+			logger.With(logger.Fields{"org": org.Name()}).WithError(err).Errorf("failed to collect the ip allow list")
+		}
+
+		samlSSO, err = c.collectSamlSSO(org.Name())
+		if err != nil {
+			samlSSO = nil
+			logger.With(logger.Fields{"org": org.Name()}).WithError(err).Errorf("failed to collect saml sso identities")
+		}
+	}
+
+	coverage, err := c.collectGhasCoverage(org.Name())
+	if err != nil {
+		coverage = nil
+		logger.With(logger.Fields{"org": org.Name()}).WithError(err).Errorf("failed to collect advanced security coverage")
 	}
 
 	return ghcollected.Organization{
-		Organization: org,
-		SamlEnabled:  samlEnabled,
-		Hooks:        hooks,
+		Organization:          org,
+		SamlEnabled:           samlEnabled,
+		SamlSSO:               samlSSO,
+		Hooks:                 hooks,
+		HooksSettings:         hooksSettings,
+		HooksHealth:           hooksHealth,
+		AppInstallations:      installations,
+		HighSensitivity:       isHighSensitivity(context_utils.GetHighSensitivityOrgs(c.Context), org.Name()),
+		IPAllowList:           ipAllowList,
+		Codespaces:            codespaces,
+		Packages:              packages,
+		Projects:              projects,
+		InteractionLimit:      interactionLimit,
+		Domains:               domains,
+		SecurityManagerTeams:  securityManagers,
+		RepositoryTransfers:   transfers,
+		OAuthAppRestrictions:  oauthRestrictions,
+		CustomRepositoryRoles: customRoles,
+		GHASCoverage:          coverage,
 	}
 }
 
+// collectGhasCoverage aggregates the GitHub Advanced Security features status of the (non-archived) organization repositories.
+// Code scanning is checked for the repositories it can be enabled for: public ones and ones with advanced security.
+func (c *organizationCollector) collectGhasCoverage(org string) (*ghas.Coverage, error) {
+	repositories, err := c.Client.GetRepositoriesSecurityAndAnalysis(org)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []ghas.RepositoryStatus
+	for _, repo := range repositories {
+		if repo.Archived {
+			continue
+		}
+
+		status := ghas.RepositoryStatus{Name: repo.Name, Private: repo.Private}
+		if settings := repo.SecurityAndAnalysis; settings != nil {
+			if repo.Private {
+				status.AdvancedSecurity = featureEnabled(settings.AdvancedSecurity)
+			}
+			status.SecretScanning = featureEnabled(settings.SecretScanning)
+			status.SecretScanningPushProtection = featureEnabled(settings.SecretScanningPushProtection)
+		}
+
+		if !repo.Private || (status.AdvancedSecurity != nil && *status.AdvancedSecurity) {
+			enabled, err := c.Client.IsCodeScanningEnabled(org, repo.Name)
+			if err != nil {
+				return nil, err
+			}
+			status.CodeScanning = &enabled
+		} else if status.AdvancedSecurity != nil {
+			disabled := false
+			status.CodeScanning = &disabled
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return ghas.Compute(statuses), nil
+}
+
+func featureEnabled(status *types.SecurityAndAnalysisStatus) *bool {
+	if status == nil || status.Status == nil {
+		return nil
+	}
+	enabled := *status.Status == "enabled"
+	return &enabled
+}
+
+// audit log actions of repository transfers, by the direction of the transfer
+var repositoryTransferActions = map[string]string{
+	"repo.transfer":          ghcollected.TransferIncoming,
+	"repo.transfer_outgoing": ghcollected.TransferOutgoing,
+}
+
+// repository transfers older than this are not collected
+const repositoryTransfersPeriod = 90 * 24 * time.Hour
+
+// collectRepositoryTransfers searches the audit log for the repositories that were recently transferred into/out of the organization.
+// Note: Org must be part of an enterprise.
+func (c *organizationCollector) collectRepositoryTransfers(org string) ([]ghcollected.RepositoryTransfer, error) {
+	var result []ghcollected.RepositoryTransfer
+	since := time.Now().Add(-repositoryTransfersPeriod).Format("2006-01-02")
+
+	for action, direction := range repositoryTransferActions {
+		opts := &github.GetAuditLogOptions{
+			Phrase: github.String(fmt.Sprintf("action:%s created:>=%s", action, since)),
+			ListCursorOptions: github.ListCursorOptions{
+				PerPage: 100,
+			},
+		}
+
+		for {
+			entries, resp, err := c.Client.Client().Organizations.GetAuditLog(c.Context, org, opts)
+			if err != nil {
+				perm := orgTransfersPermission.Missing(org)
+				c.IssueMissingPermissions(perm)
+				return nil, err
+			}
+
+			for _, entry := range entries {
+				repository := entry.GetRepo()
+				if repository == "" {
+					repository = entry.GetRepository()
+				}
+				result = append(result, ghcollected.RepositoryTransfer{
+					Repository: repository,
+					Direction:  direction,
+					Actor:      entry.GetActor(),
+					CreatedAt:  entry.GetTimestamp().Format(time.RFC3339),
+				})
+			}
+
+			if resp.After == "" {
+				break
+			}
+			opts.After = resp.After
+		}
+	}
+
+	return result, nil
+}
+
+// audit log actions of the OAuth app access policy, and of the decisions about the applications' access requests
+const (
+	oauthRestrictionsEnabled  = "org.enable_oauth_app_restrictions"
+	oauthRestrictionsDisabled = "org.disable_oauth_app_restrictions"
+	oauthAppApproved          = "org.oauth_app_access_approved"
+	oauthAppDenied            = "org.oauth_app_access_denied"
+)
+
+// oauthAppDecisionsLimit bounds the audit log entries read for the decisions about the applications' access requests
+const oauthAppDecisionsLimit = 1000
+
+// collectOAuthAppRestrictions reconstructs the third-party OAuth application access policy from the organization audit log,
+// since it isn't exposed by the API. Note: Org must be part of an enterprise.
+func (c *organizationCollector) collectOAuthAppRestrictions(org string) (*ghcollected.OAuthAppRestrictions, error) {
+	result := &ghcollected.OAuthAppRestrictions{
+		ApprovedApplications: []string{},
+		DeniedApplications:   []string{},
+	}
+
+	var changedAt int64
+	for _, action := range []string{oauthRestrictionsEnabled, oauthRestrictionsDisabled} {
+		// only the most recent change of the policy matters
+		entries, err := c.Client.GetRecentAuditLogEntries(org, "action:"+action, 1)
+		if err != nil {
+			c.IssueMissingPermissions(orgOAuthAppPolicyPermission.Missing(org))
+			return nil, err
+		}
+		if len(entries) == 0 {
+			continue
+		}
+		if latest := entries[0]; latest.Timestamp > changedAt {
+			enabled := action == oauthRestrictionsEnabled
+			result.Enabled = &enabled
+			result.ChangedAt = time.UnixMilli(latest.Timestamp).UTC().Format(time.RFC3339)
+			result.ChangedBy = latest.Actor
+			changedAt = latest.Timestamp
+		}
+	}
+	if result.Enabled == nil {
+		c.IssueMissingPermissions(collectors.NewPartialVisibility(org,
+			"The OAuth app access policy wasn't changed within the audit log retention, so it's unknown", namespace.Organization))
+	}
+
+	type decision struct {
+		approved  bool
+		timestamp int64
+	}
+	decisions := make(map[string]decision)
+	for _, action := range []string{oauthAppApproved, oauthAppDenied} {
+		entries, err := c.Client.GetRecentAuditLogEntries(org, "action:"+action, oauthAppDecisionsLimit)
+		if err != nil {
+			c.IssueMissingPermissions(orgOAuthAppPolicyPermission.Missing(org))
+			return nil, err
+		}
+		if len(entries) == oauthAppDecisionsLimit {
+			c.IssueMissingPermissions(collectors.NewPartialVisibility(org,
+				fmt.Sprintf("Only the %d most recent OAuth app access decisions were read from the audit log", oauthAppDecisionsLimit), namespace.Organization))
+		}
+		for _, entry := range entries {
+			app := entry.OAuthApplicationDisplayName()
+			if previous, ok := decisions[app]; !ok || entry.Timestamp > previous.timestamp {
+				decisions[app] = decision{approved: action == oauthAppApproved, timestamp: entry.Timestamp}
+			}
+		}
+	}
+	for app, decision := range decisions {
+		if decision.approved {
+			result.ApprovedApplications = append(result.ApprovedApplications, app)
+		} else {
+			result.DeniedApplications = append(result.DeniedApplications, app)
+		}
+	}
+	sort.Strings(result.ApprovedApplications)
+	sort.Strings(result.DeniedApplications)
+
+	return result, nil
+}
+
 func (c *organizationCollector) collectOrgWebhooks(org string) ([]*github.Hook, error) {
 	var result []*github.Hook
 
@@ -107,8 +426,7 @@ func (c *organizationCollector) collectOrgWebhooks(org string) ([]*github.Hook,
 		hooks, resp, err := c.Client.Client().Organizations.ListHooks(c.Context, org, opts)
 		if err != nil {
 			if resp.Response.StatusCode == 404 {
-				perm := collectors.NewMissingPermission(permissions.OrgHookAdmin, org,
-					"Cannot read organization webhooks", namespace.Organization)
+				perm := orgWebhooksPermission.Missing(org)
 				c.IssueMissingPermissions(perm)
 			}
 			return nil, err
@@ -123,6 +441,485 @@ func (c *organizationCollector) collectOrgWebhooks(org string) ([]*github.Hook,
 	return result, nil
 }
 
+// collectWebhookHealth computes the delivery health of the webhook by its recent deliveries (listed by the given function)
+func collectWebhookHealth(hook *github.Hook, list func(opts *github.ListCursorOptions) ([]*github.HookDelivery, *github.Response, error)) (webhooks.Health, error) {
+	health := webhooks.Health{HookID: hook.GetID(), Name: hook.GetName(), URL: hook.GetURL(), Active: hook.GetActive()}
+
+	deliveries, _, err := list(&github.ListCursorOptions{PerPage: webhooks.MaxDeliveries})
+	if err != nil {
+		return health, err
+	}
+
+	var result []webhooks.Delivery
+	for _, delivery := range deliveries {
+		result = append(result, webhooks.Delivery{StatusCode: delivery.GetStatusCode(), DeliveredAt: delivery.GetDeliveredAt().Time})
+	}
+	return webhooks.Compute(health, result), nil
+}
+
+// collectAppInstallations lists the GitHub Apps installed on the organization, with their permissions and repository selection
+func (c *organizationCollector) collectAppInstallations(org string) ([]*github.Installation, error) {
+	var result []*github.Installation
+
+	err := ghclient.PaginateResults(func(opts *github.ListOptions) (*github.Response, error) {
+		installations, resp, err := c.Client.Client().Organizations.ListInstallations(c.Context, org, opts)
+		if err != nil {
+			if resp != nil && (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusForbidden) {
+				perm := orgAppInstallationsPermission.Missing(org)
+				c.IssueMissingPermissions(perm)
+			}
+			return nil, err
+		}
+		result = append(result, installations.Installations...)
+		return resp, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// collectSamlSSO collects the organization's identity provider and compares the members with the linked identities
+func (c *organizationCollector) collectSamlSSO(org string) (*ghcollected.SamlSSO, error) {
+	if !c.Client.IsFeatureSupported(ghclient.FeatureSamlSSOIdentities) {
+		c.IssueMissingPermissions(collectors.NewUnsupportedFeature(org, "Cannot read the organization SAML SSO identities", namespace.Organization))
+		return nil, nil
+	}
+
+	var identitiesQuery struct {
+		Organization struct {
+			SamlIdentityProvider *struct {
+				ExternalIdentities struct {
+					Nodes []struct {
+						User *struct {
+							Login string
+						}
+					}
+					PageInfo struct {
+						EndCursor   githubv4.String
+						HasNextPage bool
+					}
+				} `graphql:"externalIdentities(first: 100, after: $cursor)"`
+			}
+		} `graphql:"organization(login: $login)"`
+	}
+
+	variables := map[string]interface{}{
+		"login":  githubv4.String(org),
+		"cursor": (*githubv4.String)(nil),
+	}
+
+	linked := make(map[string]bool)
+	for {
+		err := c.Client.GraphQLClient().Query(c.Context, &identitiesQuery, variables)
+		if err != nil {
+			perm := orgSamlIdentitiesPermission.Missing(org)
+			c.IssueMissingPermissions(perm)
+			return nil, err
+		}
+
+		provider := identitiesQuery.Organization.SamlIdentityProvider
+		if provider == nil {
+			return &ghcollected.SamlSSO{Configured: false, UnlinkedLogins: []string{}}, nil
+		}
+		for _, node := range provider.ExternalIdentities.Nodes {
+			if node.User != nil {
+				linked[node.User.Login] = true
+			}
+		}
+
+		if !provider.ExternalIdentities.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = githubv4.NewString(provider.ExternalIdentities.PageInfo.EndCursor)
+	}
+
+	result := &ghcollected.SamlSSO{Configured: true, UnlinkedLogins: []string{}}
+	err := ghclient.PaginateResults(func(opts *github.ListOptions) (*github.Response, error) {
+		members, resp, err := c.Client.Client().Organizations.ListMembers(c.Context, org, &github.ListMembersOptions{ListOptions: *opts})
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range members {
+			if linked[member.GetLogin()] {
+				result.LinkedMembers++
+			} else {
+				result.UnlinkedMembers++
+				result.UnlinkedLogins = append(result.UnlinkedLogins, member.GetLogin())
+			}
+		}
+		return resp, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// collectIPAllowList collects the IP allow list settings and entries of the organization
+func (c *organizationCollector) collectIPAllowList(org string) (*ghcollected.IPAllowList, error) {
+	if !c.Client.IsFeatureSupported(ghclient.FeatureIPAllowList) {
+		c.IssueMissingPermissions(collectors.NewUnsupportedFeature(org, "Cannot read the organization IP allow list", namespace.Organization))
+		return nil, nil
+	}
+
+	const enabled = "ENABLED"
+	var query struct {
+		Organization struct {
+			IpAllowListEnabledSetting                 string
+			IpAllowListForInstalledAppsEnabledSetting string
+			IpAllowListEntries                        struct {
+				Nodes []struct {
+					AllowListValue string
+					Name           string
+					IsActive       bool
+				}
+				PageInfo struct {
+					EndCursor   githubv4.String
+					HasNextPage bool
+				}
+			} `graphql:"ipAllowListEntries(first: 100, after: $cursor)"`
+		} `graphql:"organization(login: $login)"`
+	}
+
+	variables := map[string]interface{}{
+		"login":  githubv4.String(org),
+		"cursor": (*githubv4.String)(nil),
+	}
+
+	result := &ghcollected.IPAllowList{Entries: []ghcollected.IPAllowListEntry{}}
+	for {
+		err := c.Client.GraphQLClient().Query(c.Context, &query, variables)
+		if err != nil {
+			perm := orgIPAllowListPermission.Missing(org)
+			c.IssueMissingPermissions(perm)
+			return nil, err
+		}
+
+		result.Enabled = query.Organization.IpAllowListEnabledSetting == enabled
+		result.InstalledAppsAllowed = query.Organization.IpAllowListForInstalledAppsEnabledSetting == enabled
+		for _, node := range query.Organization.IpAllowListEntries.Nodes {
+			result.Entries = append(result.Entries, ghcollected.IPAllowListEntry{Value: node.AllowListValue, Name: node.Name, Active: node.IsActive})
+		}
+
+		if !query.Organization.IpAllowListEntries.PageInfo.HasNextPage {
+			return result, nil
+		}
+		variables["cursor"] = githubv4.NewString(query.Organization.IpAllowListEntries.PageInfo.EndCursor)
+	}
+}
+
+// collectCodespaces collects the organization codespaces secrets and the codespaces of its repositories
+func (c *organizationCollector) collectCodespaces(org string) (*ghcollected.Codespaces, error) {
+	if !c.Client.IsFeatureSupported(ghclient.FeatureCodespaces) {
+		c.IssueMissingPermissions(collectors.NewUnsupportedFeature(org, "Cannot read the organization codespaces", namespace.Organization))
+		return nil, nil
+	}
+
+	secrets, err := c.Client.GetOrganizationCodespacesSecrets(org)
+	if err != nil {
+		perm := orgCodespacesSecretsPermission.Missing(org)
+		c.IssueMissingPermissions(perm)
+		return nil, err
+	}
+
+	codespaces, err := c.Client.GetOrganizationCodespaces(org)
+	if err != nil {
+		perm := orgCodespacesPermission.Missing(org)
+		c.IssueMissingPermissions(perm)
+		return nil, err
+	}
+
+	result := &ghcollected.Codespaces{
+		Secrets:    []ghcollected.CodespacesSecret{},
+		Codespaces: []ghcollected.Codespace{},
+	}
+	for _, secret := range secrets {
+		result.Secrets = append(result.Secrets, ghcollected.CodespacesSecret{Name: secret.Name, Visibility: secret.Visibility})
+	}
+	for _, codespace := range codespaces {
+		collected := ghcollected.Codespace{
+			Name:              codespace.Name,
+			Owner:             codespace.Owner.Login,
+			Repository:        codespace.Repository.FullName,
+			PrivateRepository: codespace.Repository.Private,
+			State:             codespace.State,
+		}
+		if codespace.Machine != nil {
+			collected.Machine = codespace.Machine.Name
+		}
+		result.Codespaces = append(result.Codespaces, collected)
+	}
+
+	return result, nil
+}
+
+// packageTypes are the package types the organization packages are listed by (the API requires one)
+var packageTypes = []string{"npm", "maven", "rubygems", "docker", "nuget", "container"}
+
+// collectPackages lists the packages published under the organization, of all types
+func (c *organizationCollector) collectPackages(org string) ([]ghcollected.Package, error) {
+	result := []ghcollected.Package{}
+
+	for _, packageType := range packageTypes {
+		packageType := packageType
+		err := ghclient.PaginateResults(func(opts *github.ListOptions) (*github.Response, error) {
+			packages, resp, err := c.Client.Client().Organizations.ListPackages(c.Context, org, &github.PackageListOptions{
+				PackageType: &packageType,
+				ListOptions: *opts,
+			})
+			if err != nil {
+				if resp != nil && (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusUnauthorized) {
+					perm := orgPackagesPermission.Missing(org)
+					c.IssueMissingPermissions(perm)
+				}
+				return nil, err
+			}
+
+			for _, p := range packages {
+				collected := ghcollected.Package{
+					Name:       p.GetName(),
+					Type:       p.GetPackageType(),
+					Visibility: p.GetVisibility(),
+				}
+				if p.Repository != nil {
+					collected.Repository = p.Repository.GetFullName()
+					collected.RepositoryPrivate = p.Repository.GetPrivate()
+				}
+				result = append(result, collected)
+			}
+			return resp, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func isHighSensitivity(orgs []string, org string) bool {
+	for _, sensitive := range orgs {
+		if strings.EqualFold(sensitive, org) {
+			return true
+		}
+	}
+	return false
+}
+
+// collectDomains lists the verified & approved domains of the organization
+func (c *organizationCollector) collectDomains(org string) ([]ghcollected.Domain, error) {
+	var query struct {
+		Organization struct {
+			Domains struct {
+				Nodes []struct {
+					Domain     string
+					IsVerified bool
+					IsApproved bool
+				}
+				PageInfo struct {
+					EndCursor   githubv4.String
+					HasNextPage bool
+				}
+			} `graphql:"domains(first: 100, after: $cursor)"`
+		} `graphql:"organization(login: $login)"`
+	}
+
+	variables := map[string]interface{}{
+		"login":  githubv4.String(org),
+		"cursor": (*githubv4.String)(nil),
+	}
+
+	result := []ghcollected.Domain{}
+	for {
+		err := c.Client.GraphQLClient().Query(c.Context, &query, variables)
+		if err != nil {
+			perm := orgVerifiedDomainsPermission.Missing(org)
+			c.IssueMissingPermissions(perm)
+			return nil, err
+		}
+
+		for _, node := range query.Organization.Domains.Nodes {
+			result = append(result, ghcollected.Domain{Domain: node.Domain, Verified: node.IsVerified, Approved: node.IsApproved})
+		}
+
+		if !query.Organization.Domains.PageInfo.HasNextPage {
+			return result, nil
+		}
+		variables["cursor"] = githubv4.NewString(query.Organization.Domains.PageInfo.EndCursor)
+	}
+}
+
+// collectInteractionLimit collects the organization's temporary interaction limit
+func (c *organizationCollector) collectInteractionLimit(org string) (*ghcollected.InteractionLimit, error) {
+	if !c.Client.IsFeatureSupported(ghclient.FeatureInteractionLimits) {
+		c.IssueMissingPermissions(collectors.NewUnsupportedFeature(org, "Cannot read the organization interaction limit", namespace.Organization))
+		return nil, nil
+	}
+
+	restriction, resp, err := c.Client.Client().Interactions.GetRestrictionsForOrg(c.Context, org)
+	if err != nil {
+		if resp != nil && (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusForbidden) {
+			perm := orgInteractionLimitPermission.Missing(org)
+			c.IssueMissingPermissions(perm)
+		}
+		return nil, err
+	}
+	return ghcollected.NewInteractionLimit(restriction), nil
+}
+
+// collectProjects lists the organization projects and classic projects, with their visibility
+// (only the classic projects on server versions without projects)
+func (c *organizationCollector) collectProjects(org string) ([]ghcollected.Project, error) {
+	result := []ghcollected.Project{}
+	if c.Client.IsFeatureSupported(ghclient.FeatureProjectsV2) {
+		projects, err := c.collectProjectsV2(org)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, projects...)
+	} else {
+		c.IssueMissingPermissions(collectors.NewUnsupportedFeature(org, "Cannot read the organization projects (only the classic projects)", namespace.Organization))
+	}
+
+	err := ghclient.PaginateResults(func(opts *github.ListOptions) (*github.Response, error) {
+		projects, resp, err := c.Client.Client().Organizations.ListProjects(c.Context, org, &github.ProjectListOptions{State: "all", ListOptions: *opts})
+		if err != nil {
+			return nil, err
+		}
+		for _, project := range projects {
+			result = append(result, ghcollected.Project{
+				Title:   project.GetName(),
+				Number:  project.GetNumber(),
+				Classic: true,
+				Public:  !project.GetPrivate(),
+				Closed:  project.GetState() == "closed",
+			})
+		}
+		return resp, nil
+	})
+	if err != nil {
+		// classic projects are gone (or disabled for the organization)
+		var errResp *github.ErrorResponse
+		if !errors.As(err, &errResp) || errResp.Response == nil ||
+			(errResp.Response.StatusCode != http.StatusNotFound && errResp.Response.StatusCode != http.StatusGone) {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// collectProjectsV2 lists the organization projects (not including the classic projects)
+func (c *organizationCollector) collectProjectsV2(org string) ([]ghcollected.Project, error) {
+	var query struct {
+		Organization struct {
+			ProjectsV2 struct {
+				Nodes []struct {
+					Title  string
+					Number int
+					Public bool
+					Closed bool
+				}
+				PageInfo struct {
+					EndCursor   githubv4.String
+					HasNextPage bool
+				}
+			} `graphql:"projectsV2(first: 100, after: $cursor)"`
+		} `graphql:"organization(login: $login)"`
+	}
+
+	variables := map[string]interface{}{
+		"login":  githubv4.String(org),
+		"cursor": (*githubv4.String)(nil),
+	}
+
+	var result []ghcollected.Project
+	for {
+		err := c.Client.GraphQLClient().Query(c.Context, &query, variables)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, node := range query.Organization.ProjectsV2.Nodes {
+			result = append(result, ghcollected.Project{Title: node.Title, Number: node.Number, Public: node.Public, Closed: node.Closed})
+		}
+
+		if !query.Organization.ProjectsV2.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = githubv4.NewString(query.Organization.ProjectsV2.PageInfo.EndCursor)
+	}
+
+	return result, nil
+}
+
+// collectSecurityManagers lists the teams with the security manager role and their members.
+// The outside collaborators of the organization are only listed if there are such teams.
+func (c *organizationCollector) collectSecurityManagers(org string) ([]ghcollected.SecurityManagerTeam, error) {
+	if !c.Client.IsFeatureSupported(ghclient.FeatureSecurityManagers) {
+		c.IssueMissingPermissions(collectors.NewUnsupportedFeature(org, "Cannot read the security manager teams", namespace.Organization))
+		return nil, nil
+	}
+
+	teams, err := c.Client.GetSecurityManagerTeams(org)
+	if err != nil {
+		perm := orgSecurityManagersPermission.Missing(org)
+		c.IssueMissingPermissions(perm)
+		return nil, err
+	}
+
+	result := []ghcollected.SecurityManagerTeam{}
+	if len(teams) == 0 {
+		return result, nil
+	}
+
+	outsideCollaborators := make(map[string]bool)
+	err = ghclient.PaginateResults(func(opts *github.ListOptions) (*github.Response, error) {
+		users, resp, err := c.Client.Client().Organizations.ListOutsideCollaborators(c.Context, org, &github.ListOutsideCollaboratorsOptions{ListOptions: *opts})
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			outsideCollaborators[user.GetLogin()] = true
+		}
+		return resp, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, team := range teams {
+		managers := ghcollected.SecurityManagerTeam{
+			Name:                 team.GetName(),
+			Slug:                 team.GetSlug(),
+			Members:              []string{},
+			OutsideCollaborators: []string{},
+		}
+		err = ghclient.PaginateResults(func(opts *github.ListOptions) (*github.Response, error) {
+			members, resp, err := c.Client.Client().Teams.ListTeamMembersBySlug(c.Context, org, team.GetSlug(), &github.TeamListTeamMembersOptions{ListOptions: *opts})
+			if err != nil {
+				return nil, err
+			}
+			for _, member := range members {
+				managers.Members = append(managers.Members, member.GetLogin())
+				if outsideCollaborators[member.GetLogin()] {
+					managers.OutsideCollaborators = append(managers.OutsideCollaborators, member.GetLogin())
+				}
+			}
+			return resp, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, managers)
+	}
+
+	return result, nil
+}
+
 func (c *organizationCollector) collectOrgSamlData(org string) (*bool, error) {
 	variables := map[string]interface{}{
 		"login": githubv4.String(org),
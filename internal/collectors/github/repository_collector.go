@@ -1,17 +1,34 @@
 package github
 
 import (
+	"errors"
 	"fmt"
+	"github.com/Legit-Labs/legitify/internal/branches"
+	"github.com/Legit-Labs/legitify/internal/codeowners"
 	"github.com/Legit-Labs/legitify/internal/collectors"
 	"github.com/Legit-Labs/legitify/internal/common/types"
 	"github.com/Legit-Labs/legitify/internal/context_utils"
+	"github.com/Legit-Labs/legitify/internal/dependabot"
+	"github.com/Legit-Labs/legitify/internal/enforcement"
+	"github.com/Legit-Labs/legitify/internal/logger"
+	"github.com/Legit-Labs/legitify/internal/sampling"
 	"github.com/Legit-Labs/legitify/internal/scorecard"
-	"log"
+	"github.com/Legit-Labs/legitify/internal/suspicious"
+	"github.com/Legit-Labs/legitify/internal/waivers"
+	"github.com/Legit-Labs/legitify/internal/webhooks"
+	"github.com/Legit-Labs/legitify/internal/workflows"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/Legit-Labs/legitify/internal/common/group_waiter"
 	"github.com/Legit-Labs/legitify/internal/common/permissions"
+	"github.com/Legit-Labs/legitify/internal/common/scheduler"
 
 	ghclient "github.com/Legit-Labs/legitify/internal/clients/github"
+	"github.com/Legit-Labs/legitify/internal/clients/github/query_cost"
+	ghtypes "github.com/Legit-Labs/legitify/internal/clients/github/types"
 	ghcollected "github.com/Legit-Labs/legitify/internal/collected/github"
 	"github.com/Legit-Labs/legitify/internal/common/namespace"
 	"github.com/Legit-Labs/legitify/internal/common/utils"
@@ -26,14 +43,34 @@ type repositoryCollector struct {
 	Context          context.Context
 	scorecardEnabled bool
 	contextFactory   *repositoryContextFactory
+	// scheduler bounds the repositories collected concurrently, per organization
+	scheduler *scheduler.Scheduler
+	// collected are the names of the collected repositories, by organization
+	collected      map[string]map[string]bool
+	collectedMutex sync.Mutex
+	// teamMembers caches the members of the teams that own code or bypass the reviews (by @org/team),
+	// as many repositories share the same teams
+	teamMembers      map[string][]string
+	teamMembersMutex sync.Mutex
+	// teamSlugs caches the slugs of the teams of each organization by their ids (the rulesets refer to the teams by id)
+	teamSlugs      map[string]map[int64]string
+	teamSlugsMutex sync.Mutex
+	// defaultSecurityPolicies caches the path of the default security policy of each organization (empty if there is none)
+	defaultSecurityPolicies      map[string]string
+	defaultSecurityPoliciesMutex sync.Mutex
 }
 
 func NewRepositoryCollector(ctx context.Context, client *ghclient.Client) collectors.Collector {
 	c := &repositoryCollector{
-		Client:           client,
-		Context:          ctx,
-		scorecardEnabled: context_utils.GetScorecardEnabled(ctx),
-		contextFactory:   newRepositoryContextFactory(ctx, client),
+		Client:                  client,
+		Context:                 ctx,
+		scorecardEnabled:        context_utils.GetScorecardEnabled(ctx),
+		contextFactory:          newRepositoryContextFactory(ctx, client),
+		scheduler:               scheduler.New(context_utils.GetConcurrency(ctx)),
+		collected:               make(map[string]map[string]bool),
+		teamMembers:             make(map[string][]string),
+		teamSlugs:               make(map[string]map[int64]string),
+		defaultSecurityPolicies: make(map[string]string),
 	}
 	collectors.InitBaseCollector(&c.BaseCollector, c)
 	return c
@@ -63,7 +100,7 @@ func (rc *repositoryCollector) CollectMetadata() collectors.Metadata {
 	orgs, err := rc.Client.CollectOrganizations()
 
 	if err != nil {
-		log.Printf("failed to collect organization %s", err)
+		logger.WithError(err).Errorf("failed to collect organizations")
 		return collectors.Metadata{}
 	}
 
@@ -88,6 +125,13 @@ func (rc *repositoryCollector) CollectMetadata() collectors.Metadata {
 	}
 	gw.Wait()
 
+	if percent, sampled := context_utils.GetSamplePercent(rc.Context); sampled {
+		// approximated: the sample is picked per stratum, so it may be slightly larger
+		return collectors.Metadata{
+			TotalEntities: sampling.Size(int(totalCount), percent),
+		}
+	}
+
 	return collectors.Metadata{
 		TotalEntities: int(totalCount),
 	}
@@ -128,7 +172,7 @@ func (rc *repositoryCollector) collectSpecific(repositories []types.RepositoryWi
 				query := specificRepoQuery{}
 				err := rc.Client.GraphQLClient().Query(rc.Context, &query, variables)
 				if err != nil {
-					log.Println(err.Error())
+					logger.With(logger.Fields{"org": repo.Owner, "repo": repo.String()}).WithError(err).Errorf("failed to collect repository")
 					return
 				}
 
@@ -141,11 +185,13 @@ func (rc *repositoryCollector) collectSpecific(repositories []types.RepositoryWi
 				}
 
 				if err != nil {
-					log.Println(err.Error())
+					logger.With(logger.Fields{"org": repo.Owner, "repo": repo.String()}).WithError(err).Errorf("failed to collect repository")
 					return
 				}
 
-				rc.collectRepository(&query.RepositoryOwner.Repository, repo.Owner, ctx)
+				rc.scheduler.Run(repo.Owner, func() {
+					rc.collectRepository(&query.RepositoryOwner.Repository, repo.Owner, ctx)
+				})
 			})
 		}
 		gw.Wait()
@@ -157,7 +203,7 @@ func (rc *repositoryCollector) collectAll() collectors.SubCollectorChannels {
 		orgs, err := rc.Client.CollectOrganizations()
 
 		if err != nil {
-			log.Printf("failed to collect organizations %s", err)
+			logger.WithError(err).Errorf("failed to collect organizations")
 			return
 		}
 
@@ -169,6 +215,8 @@ func (rc *repositoryCollector) collectAll() collectors.SubCollectorChannels {
 					err := rc.collectRepositories(&localOrg)
 					return true, err
 				}, 5, fmt.Sprintf("collect repositories for %s", *localOrg.Login))
+				rc.checkVisibility(&localOrg)
+				rc.OrganizationFinished(localOrg.Name())
 			})
 		}
 		gw.Wait()
@@ -180,16 +228,49 @@ type repoQuery struct {
 		Repositories struct {
 			PageInfo ghcollected.GitHubQLPageInfo
 			Nodes    []ghcollected.GitHubQLRepository
-		} `graphql:"repositories(first: 50, after: $repositoryCursor)"`
+		} `graphql:"repositories(first: $pageSize, after: $repositoryCursor)"`
 	} `graphql:"organization(login: $login)"`
 }
 
-func (rc *repositoryCollector) collectRepositories(org *ghcollected.ExtendedOrg) error {
+const (
+	maxRepositoriesPageSize = 50
+	// maxRepositoriesQueryNodes keeps the repositories query well below GitHub's node limit,
+	// since the nested connections of each repository multiply the number of nodes of a page.
+	maxRepositoriesQueryNodes = 10000
+)
+
+// repositoriesQueryVariables precomputes the page size of the repositories query, based on its cost
+func (rc *repositoryCollector) repositoriesQueryVariables(org string) (map[string]interface{}, error) {
 	variables := map[string]interface{}{
-		"login":            githubv4.String(org.Name()),
+		"login":            githubv4.String(org),
 		"repositoryCursor": (*githubv4.String)(nil),
 	}
 
+	pageSize, err := query_cost.PageSize(repoQuery{}, variables, "pageSize", maxRepositoriesPageSize, maxRepositoriesQueryNodes)
+	if err != nil {
+		return nil, err
+	}
+	variables["pageSize"] = githubv4.Int(pageSize)
+
+	estimate, err := query_cost.Of(repoQuery{}, variables)
+	if err != nil {
+		return nil, err
+	}
+	logger.With(logger.Fields{"org": org}).Debugf("repositories query: page size %d, up to %d nodes, cost %d", pageSize, estimate.Nodes, estimate.Cost)
+
+	return variables, nil
+}
+
+func (rc *repositoryCollector) collectRepositories(org *ghcollected.ExtendedOrg) error {
+	if percent, sampled := context_utils.GetSamplePercent(rc.Context); sampled {
+		return rc.collectSampledRepositories(org, percent)
+	}
+
+	variables, err := rc.repositoriesQueryVariables(org.Name())
+	if err != nil {
+		return err
+	}
+
 	gw := group_waiter.New()
 	for {
 		query := repoQuery{}
@@ -205,7 +286,9 @@ func (rc *repositoryCollector) collectRepositories(org *ghcollected.ExtendedOrg)
 			for i := range nodes {
 				node := &(nodes[i])
 				extraGw.Do(func() {
-					rc.collectRepository(node, org.Name(), rc.contextFactory.newRepositoryContextForExtendedOrg(org, node))
+					rc.scheduler.Run(org.Name(), func() {
+						rc.collectRepository(node, org.Name(), rc.contextFactory.newRepositoryContextForExtendedOrg(org, node))
+					})
 				})
 			}
 			extraGw.Wait()
@@ -222,13 +305,130 @@ func (rc *repositoryCollector) collectRepositories(org *ghcollected.ExtendedOrg)
 	return nil
 }
 
+// collectSampledRepositories lists all the repositories of the organization first,
+// so the sample can be picked across all of them.
+func (rc *repositoryCollector) collectSampledRepositories(org *ghcollected.ExtendedOrg, percent int) error {
+	variables, err := rc.repositoriesQueryVariables(org.Name())
+	if err != nil {
+		return err
+	}
+
+	var nodes []ghcollected.GitHubQLRepository
+	for {
+		query := repoQuery{}
+		err := rc.Client.GraphQLClient().Query(rc.Context, &query, variables)
+
+		if err != nil {
+			return err
+		}
+
+		nodes = append(nodes, query.Organization.Repositories.Nodes...)
+
+		if !query.Organization.Repositories.PageInfo.HasNextPage {
+			break
+		}
+
+		variables["repositoryCursor"] = query.Organization.Repositories.PageInfo.EndCursor
+	}
+
+	items := make([]sampling.Item, 0, len(nodes))
+	for _, node := range nodes {
+		items = append(items, sampling.Item{ID: node.Url, Stratum: repositoryStratum(&node)})
+	}
+	selected := sampling.Select(items, percent)
+
+	gw := group_waiter.New()
+	for i := range nodes {
+		node := &(nodes[i])
+		if !selected[node.Url] {
+			continue
+		}
+		gw.Do(func() {
+			rc.scheduler.Run(org.Name(), func() {
+				rc.collectRepository(node, org.Name(), rc.contextFactory.newRepositoryContextForExtendedOrg(org, node))
+			})
+		})
+	}
+	gw.Wait()
+
+	logger.With(logger.Fields{"org": org.Name()}).Infof("sampled %d out of %d repositories", len(selected), len(nodes))
+	return nil
+}
+
+// repositories that were not pushed to during this period are considered inactive when sampling
+const sampleActivityPeriod = 90 * 24 * time.Hour
+
+// repositoryStratum groups the repositories by visibility & activity, so the sample represents all kinds of repositories
+func repositoryStratum(repository *ghcollected.GitHubQLRepository) string {
+	if repository.IsArchived {
+		return "archived"
+	}
+
+	visibility := "public"
+	if repository.IsPrivate {
+		visibility = "private"
+	}
+
+	activity := "inactive"
+	if repository.PushedAt != nil && time.Since(repository.PushedAt.Time) < sampleActivityPeriod {
+		activity = "active"
+	}
+
+	return visibility + "/" + activity
+}
+
 func (rc *repositoryCollector) collectRepository(repository *ghcollected.GitHubQLRepository, login string, context *repositoryContext) {
 	repo := rc.collectExtraData(login, repository, context)
 	entityName := collectors.FullRepoName(login, repo.Repository.Name)
 	missingPermissions := rc.checkMissingPermissions(repo, entityName)
 	rc.IssueMissingPermissions(missingPermissions...)
-	rc.CollectDataWithContext(repo, repo.Repository.Url, context)
+	rc.CollectDataWithContext(login, repo, repo.Repository.Url, context)
 	rc.CollectionChangeByOne()
+	rc.markCollected(login, repo.Repository.Name)
+}
+
+func (rc *repositoryCollector) markCollected(org string, name string) {
+	rc.collectedMutex.Lock()
+	defer rc.collectedMutex.Unlock()
+
+	if rc.collected[org] == nil {
+		rc.collected[org] = make(map[string]bool)
+	}
+	rc.collected[org][name] = true
+}
+
+func (rc *repositoryCollector) collectedCount(org string) int {
+	rc.collectedMutex.Lock()
+	defer rc.collectedMutex.Unlock()
+
+	return len(rc.collected[org])
+}
+
+// checkVisibility compares the number of the organization repositories (according to GitHub) with the number of
+// the collected ones. Repositories can be silently invisible to the scan, e.g. because of an IP allow list,
+// an unauthorized SAML SSO session or fine-grained token restrictions, and their policies would be missing from the results.
+func (rc *repositoryCollector) checkVisibility(org *ghcollected.ExtendedOrg) {
+	if _, sampled := context_utils.GetSamplePercent(rc.Context); sampled {
+		return
+	}
+
+	variables := map[string]interface{}{
+		"login": githubv4.String(org.Name()),
+	}
+	totalCountQuery := totalCountRepoQuery{}
+	if err := rc.Client.GraphQLClient().Query(rc.Context, &totalCountQuery, variables); err != nil {
+		logger.With(logger.Fields{"org": org.Name()}).WithError(err).Errorf("failed to count the organization repositories")
+		return
+	}
+
+	expected := int(totalCountQuery.Organization.Repositories.TotalCount)
+	collected := rc.collectedCount(org.Name())
+	if collected >= expected {
+		return
+	}
+
+	effect := fmt.Sprintf("Collected %d out of %d repositories (the rest may be hidden by an IP allow list, SAML SSO or token restrictions)", collected, expected)
+	rc.IssueMissingPermissions(collectors.NewPartialVisibility(fmt.Sprintf("%s/%s", namespace.Organization, org.Name()), effect, namespace.Repository))
 }
 
 func (rc *repositoryCollector) collectExtraData(login string,
@@ -238,49 +438,142 @@ func (rc *repositoryCollector) collectExtraData(login string,
 	repo := ghcollected.Repository{
 		Repository: repository,
 	}
+	repoLog := logger.With(logger.Fields{"org": login, "repo": collectors.FullRepoName(login, repository.Name)})
 
 	repo, err = rc.withVulnerabilityAlerts(repo, login)
 	if err != nil {
 		// If we can't get vulnerability alerts, rego will ignore it (as nil)
-		log.Printf("error getting vulnerability alerts for %s: %s", collectors.FullRepoName(login, repo.Repository.Name), err)
+		repoLog.WithError(err).Errorf("error getting vulnerability alerts")
 	}
 
 	repo, err = rc.withRepositoryHooks(repo, login)
 	if err != nil {
-		log.Printf("error getting repository hooks for %s: %s", collectors.FullRepoName(login, repo.Repository.Name), err)
+		repoLog.WithError(err).Errorf("error getting repository hooks")
 	}
 
 	repo, err = rc.withRepoCollaborators(repo, login)
 	if err != nil {
-		log.Printf("error getting repository collaborators for %s: %s", collectors.FullRepoName(login, repo.Repository.Name), err)
+		repoLog.WithError(err).Errorf("error getting repository collaborators")
 	}
 
 	repo, err = rc.withActionsSettings(repo, login)
 	if err != nil {
-		log.Printf("error getting repository actions settings for %s: %s", collectors.FullRepoName(login, repo.Repository.Name), err)
+		repoLog.WithError(err).Errorf("error getting repository actions settings")
 	}
 
 	repo, err = rc.withDependencyGraphManifestsCount(repo, login)
 	if err != nil {
-		log.Printf("error getting repository dependency manifests for %s: %s", collectors.FullRepoName(login, repo.Repository.Name), err)
+		repoLog.WithError(err).Errorf("error getting repository dependency manifests")
+	}
+
+	repo, err = rc.withDependencyGraphEnabled(repo, login)
+	if err != nil {
+		// If we can't tell whether the dependency graph is enabled, rego will ignore it (as nil)
+		repoLog.WithError(err).Errorf("error getting repository dependency graph status")
+	}
+
+	repo, err = rc.withSecurityAndAnalysis(repo, login)
+	if err != nil {
+		repoLog.WithError(err).Errorf("error getting repository security and analysis settings")
+	}
+
+	repo, err = rc.withSelfHostedRunners(repo, login)
+	if err != nil {
+		repoLog.WithError(err).Errorf("error getting repository self-hosted runners")
+	}
+
+	repo, err = rc.withWaivers(repo, login)
+	if err != nil {
+		repoLog.WithError(err).Errorf("error getting repository waivers")
+	}
+
+	repo, err = rc.withOIDCSubjectClaim(repo, login)
+	if err != nil {
+		repoLog.WithError(err).Errorf("error getting repository actions OIDC subject claim")
+	}
+
+	repo, err = rc.withForkPRApproval(repo, login)
+	if err != nil {
+		repoLog.WithError(err).Errorf("error getting repository fork pull requests approval policy")
+	}
+
+	repo, err = rc.withInteractionLimit(repo, login)
+	if err != nil {
+		repoLog.WithError(err).Errorf("error getting repository interaction limit")
+	}
+
+	repo, err = rc.withRulesets(repo, login)
+	if err != nil {
+		// If we can't get the rulesets, only the branch protection rules are considered
+		repoLog.WithError(err).Errorf("error getting repository rulesets")
+	}
+
+	if patterns := context_utils.GetProtectedBranches(rc.Context); len(patterns) > 0 {
+		repo, err = rc.withBranches(repo, login, patterns)
+		if err != nil {
+			repoLog.WithError(err).Errorf("error getting repository branches protection")
+		}
+	}
+
+	repo, err = rc.withWorkflows(repo, login)
+	if err != nil {
+		repoLog.WithError(err).Errorf("error getting repository workflows")
+	}
+
+	repo, err = rc.withDependabotConfig(repo, login)
+	if err != nil {
+		repoLog.WithError(err).Errorf("error getting repository dependabot configuration")
+	}
+
+	repo, err = rc.withCodeowners(repo, login)
+	if err != nil {
+		repoLog.WithError(err).Errorf("error getting repository CODEOWNERS file")
+	}
+
+	repo, err = rc.withCodeownersTeams(repo, login)
+	if err != nil {
+		// If we can't get the members of a team, the rules it owns are ignored by the self-approval policy
+		repoLog.WithError(err).Errorf("error getting repository CODEOWNERS teams members")
+	}
+
+	repo, err = rc.withSecurityPolicy(repo, login)
+	if err != nil {
+		repoLog.WithError(err).Errorf("error getting repository security policy")
+	}
+
+	repo, err = rc.withPages(repo, login)
+	if err != nil {
+		repoLog.WithError(err).Errorf("error getting repository pages configuration")
+	}
+
+	if context_utils.GetSuspiciousFilesEnabled(rc.Context) {
+		repo, err = rc.withSuspiciousFiles(repo, login)
+		if err != nil {
+			repoLog.WithError(err).Errorf("error getting repository suspicious files")
+		}
 	}
 
 	if context.IsBranchProtectionSupported() {
 		repo, err = rc.fixBranchProtectionInfo(repo, login)
 		if err != nil {
 			// If we can't get branch protection info, rego will ignore it (as nil)
-			log.Printf("error getting branch protection info for %s: %s", repository.Name, err)
+			repoLog.WithError(err).Errorf("error getting branch protection info")
 		}
 	} else {
 		perm := collectors.NewMissingPermission(permissions.RepoAdmin, collectors.FullRepoName(login, repo.Repository.Name), orgIsFreeEffect, namespace.Repository)
 		rc.IssueMissingPermissions(perm)
 	}
 
+	repo, err = rc.withEnforcement(repo, login)
+	if err != nil {
+		repoLog.WithError(err).Errorf("error getting the members of the teams that can bypass the code review")
+	}
+
 	if rc.scorecardEnabled {
 		scResult, err := scorecard.Calculate(rc.Context, repository.Url, repo.Repository.IsPrivate)
 		if err != nil {
 			scResult = nil
-			log.Printf("error getting scorecard result for %s: %s", repository.Name, err)
+			repoLog.WithError(err).Errorf("error getting scorecard result")
 		}
 		repo.Scorecard = scResult
 	}
@@ -312,15 +605,691 @@ func (rc *repositoryCollector) withDependencyGraphManifestsCount(repo ghcollecte
 	return repo, nil
 }
 
+func (rc *repositoryCollector) withDependencyGraphEnabled(repo ghcollected.Repository, org string) (ghcollected.Repository, error) {
+	if !rc.Client.IsFeatureSupported(ghclient.FeatureDependencyGraphSBOM) {
+		rc.IssueMissingPermissions(collectors.NewUnsupportedFeature(org, "Cannot read whether the repositories dependency graph is enabled", namespace.Repository))
+		return repo, nil
+	}
+
+	enabled, err := rc.Client.IsDependencyGraphEnabled(org, repo.Name())
+	if err != nil {
+		return repo, err
+	}
+	repo.DependencyGraphEnabled = &enabled
+	return repo, nil
+}
+
+func (rc *repositoryCollector) withRulesets(repo ghcollected.Repository, org string) (ghcollected.Repository, error) {
+	if !rc.Client.IsFeatureSupported(ghclient.FeatureRulesets) {
+		// only the branch protection rules are considered
+		rc.IssueMissingPermissions(collectors.NewUnsupportedFeature(org, "Cannot read the repositories rulesets", namespace.Repository))
+		return repo, nil
+	}
+
+	rulesets, err := rc.Client.GetRepositoryRulesets(org, repo.Name())
+	if err != nil {
+		return repo, err
+	}
+	repo.Rulesets = rulesets
+
+	if repo.Repository.DefaultBranchRef == nil || repo.Repository.DefaultBranchRef.Name == nil {
+		return repo, nil
+	}
+
+	rules, err := rc.Client.GetBranchRules(org, repo.Name(), *repo.Repository.DefaultBranchRef.Name)
+	if err != nil {
+		return repo, err
+	}
+	repo.DefaultBranchRules = rules
+
+	return repo, nil
+}
+
+// withBranches collects the protection of the non-default branches that match the patterns
+func (rc *repositoryCollector) withBranches(repo ghcollected.Repository, org string, patterns []string) (ghcollected.Repository, error) {
+	var defaultBranch string
+	if repo.Repository.DefaultBranchRef != nil && repo.Repository.DefaultBranchRef.Name != nil {
+		defaultBranch = *repo.Repository.DefaultBranchRef.Name
+	}
+
+	var matched []*github.Branch
+	err := ghclient.PaginateResults(func(opts *github.ListOptions) (*github.Response, error) {
+		page, resp, err := rc.Client.Client().Repositories.ListBranches(rc.Context, org, repo.Name(), &github.BranchListOptions{ListOptions: *opts})
+		if err != nil {
+			return nil, err
+		}
+		for _, branch := range page {
+			if branch.GetName() != defaultBranch && branches.Match(patterns, branch.GetName()) {
+				matched = append(matched, branch)
+			}
+		}
+		return resp, nil
+	})
+	if err != nil {
+		return repo, err
+	}
+
+	repo.Branches = []branches.Branch{}
+	for _, branch := range matched {
+		collected := branches.Branch{
+			Name:      branch.GetName(),
+			Protected: branch.GetProtected(),
+		}
+
+		if collected.Protected {
+			protection, _, err := rc.Client.Client().Repositories.GetBranchProtection(rc.Context, org, repo.Name(), collected.Name)
+			var errResp *github.ErrorResponse
+			if err == nil {
+				approvals := 0
+				if reviews := protection.GetRequiredPullRequestReviews(); reviews != nil {
+					approvals = reviews.RequiredApprovingReviewCount
+				}
+				collected.RequiredApprovingReviewCount = &approvals
+				collected.AllowsForcePushes = github.Bool(protection.GetAllowForcePushes() != nil && protection.GetAllowForcePushes().Enabled)
+			} else if !(errors.As(err, &errResp) && errResp.Response.StatusCode == http.StatusNotFound) {
+				return repo, err
+			}
+			// otherwise, reading the branch protection details requires admin permissions
+		}
+
+		if rc.Client.IsFeatureSupported(ghclient.FeatureRulesets) {
+			rules, err := rc.Client.GetBranchRules(org, repo.Name(), collected.Name)
+			if err != nil {
+				return repo, err
+			}
+			collected.Rules = rules
+		}
+
+		repo.Branches = append(repo.Branches, collected)
+	}
+
+	return repo, nil
+}
+
+func (rc *repositoryCollector) withSecurityAndAnalysis(repo ghcollected.Repository, org string) (ghcollected.Repository, error) {
+	settings, err := rc.Client.GetSecurityAndAnalysis(org, repo.Name())
+	if err != nil {
+		return repo, err
+	}
+	repo.SecurityAndAnalysis = settings
+	if settings != nil && !rc.Client.IsFeatureSupported(ghclient.FeatureSecretScanningPushProtection) {
+		settings.SecretScanningPushProtection = nil
+		rc.IssueMissingPermissions(collectors.NewUnsupportedFeature(org, "Cannot read the repositories secret scanning push protection", namespace.Repository))
+	}
+
+	enabled, err := rc.Client.IsCodeScanningEnabled(org, repo.Name())
+	if err != nil {
+		perm := repoCodeScanningPermission.Missing(collectors.FullRepoName(org, repo.Repository.Name))
+		rc.IssueMissingPermissions(perm)
+		return repo, err
+	}
+	repo.CodeScanningEnabled = &enabled
+
+	return repo, nil
+}
+
 func (rc *repositoryCollector) withActionsSettings(repo ghcollected.Repository, org string) (ghcollected.Repository, error) {
 	settings, err := rc.Client.GetActionsTokenPermissionsForRepository(org, repo.Name())
 	if err != nil {
-		perm := collectors.NewMissingPermission(permissions.RepoAdmin, collectors.FullRepoName(org, repo.Repository.Name),
-			"Cannot read repository actions settings", namespace.Repository)
+		perm := repoActionsSettingsPermission.Missing(collectors.FullRepoName(org, repo.Repository.Name))
 		rc.IssueMissingPermissions(perm)
 		return repo, err
 	}
 	repo.ActionsTokenPermissions = settings
+
+	actionsPermissions, err := rc.Client.GetActionsPermissionsForRepository(org, repo.Name())
+	if err != nil {
+		return repo, err
+	}
+	repo.ActionsPermissions = actionsPermissions
+
+	if actionsPermissions.AllowedActions != nil && *actionsPermissions.AllowedActions == selectedActionsPolicy {
+		selected, _, err := rc.Client.Client().Repositories.GetActionsAllowed(rc.Context, org, repo.Name())
+		if err != nil {
+			return repo, err
+		}
+		repo.SelectedActions = selected
+	}
+
+	return repo, nil
+}
+
+func (rc *repositoryCollector) withSelfHostedRunners(repo ghcollected.Repository, org string) (ghcollected.Repository, error) {
+	runners, err := rc.Client.GetRepositorySelfHostedRunners(org, repo.Name())
+	if err != nil {
+		perm := repoSelfHostedRunnersPermission.Missing(collectors.FullRepoName(org, repo.Repository.Name))
+		rc.IssueMissingPermissions(perm)
+		return repo, err
+	}
+	repo.SelfHostedRunners = runners
+	return repo, nil
+}
+
+// withWaivers reads the accepted violations from the repository topics and the waivers custom property
+func (rc *repositoryCollector) withWaivers(repo ghcollected.Repository, org string) (ghcollected.Repository, error) {
+	repo.AcceptedViolations = waivers.FromTopics(repo.Topics())
+
+	if !rc.Client.IsFeatureSupported(ghclient.FeatureCustomProperties) {
+		rc.IssueMissingPermissions(collectors.NewUnsupportedFeature(org, "Cannot read the waivers custom property", namespace.Repository))
+		return repo, nil
+	}
+
+	value, err := rc.Client.GetRepositoryCustomProperty(org, repo.Name(), waivers.Property)
+	if err != nil {
+		var errResp *github.ErrorResponse
+		if errors.As(err, &errResp) && errResp.Response.StatusCode == http.StatusNotFound {
+			// custom properties are not available (e.g. older GitHub Enterprise Server versions)
+			return repo, nil
+		}
+		return repo, err
+	}
+
+	fromProperty, err := waivers.ParseProperty(value)
+	if err != nil {
+		return repo, err
+	}
+	repo.AcceptedViolations = append(repo.AcceptedViolations, fromProperty...)
+
+	return repo, nil
+}
+
+func (rc *repositoryCollector) withOIDCSubjectClaim(repo ghcollected.Repository, org string) (ghcollected.Repository, error) {
+	if !rc.Client.IsFeatureSupported(ghclient.FeatureOIDCSubjectClaim) {
+		rc.IssueMissingPermissions(collectors.NewUnsupportedFeature(org, "Cannot read the repositories actions OIDC subject claim", namespace.Repository))
+		return repo, nil
+	}
+
+	claim, err := rc.Client.GetOIDCSubjectClaimForRepository(org, repo.Name())
+	if err != nil {
+		return repo, err
+	}
+	repo.OIDCSubjectClaim = claim
+	return repo, nil
+}
+
+// withForkPRApproval collects the fork pull requests approval policy (which applies to public repositories only)
+func (rc *repositoryCollector) withForkPRApproval(repo ghcollected.Repository, org string) (ghcollected.Repository, error) {
+	if repo.Repository.IsPrivate {
+		return repo, nil
+	}
+	if !rc.Client.IsFeatureSupported(ghclient.FeatureForkPRContributorApproval) {
+		rc.IssueMissingPermissions(collectors.NewUnsupportedFeature(org, "Cannot read the repositories fork pull requests approval policy", namespace.Repository))
+		return repo, nil
+	}
+
+	approval, err := rc.Client.GetForkPRContributorApprovalForRepository(org, repo.Name())
+	if err != nil {
+		return repo, err
+	}
+	repo.ForkPRApproval = approval
+	return repo, nil
+}
+
+// withWorkflows parses the workflow files of the default branch
+func (rc *repositoryCollector) withWorkflows(repo ghcollected.Repository, org string) (ghcollected.Repository, error) {
+	repo.TrustedActionPublishers = context_utils.GetTrustedActionPublishers(rc.Context)
+	repo.Workflows = []*workflows.Workflow{}
+
+	_, files, _, err := rc.Client.Client().Repositories.GetContents(rc.Context, org, repo.Name(), workflows.Directory, nil)
+	if err != nil {
+		var errResp *github.ErrorResponse
+		if errors.As(err, &errResp) && errResp.Response.StatusCode == http.StatusNotFound {
+			// no workflows (or an empty repository)
+			return repo, nil
+		}
+		return repo, err
+	}
+
+	for _, file := range files {
+		if file.GetType() != "file" || !workflows.IsWorkflowFile(file.GetName()) {
+			continue
+		}
+
+		content, _, _, err := rc.Client.Client().Repositories.GetContents(rc.Context, org, repo.Name(), file.GetPath(), nil)
+		if err != nil {
+			return repo, err
+		}
+		decoded, err := content.GetContent()
+		if err != nil {
+			return repo, err
+		}
+
+		workflow, err := workflows.Parse(file.GetPath(), []byte(decoded))
+		if err != nil {
+			// an invalid workflow doesn't run, so there's nothing to analyze in it
+			logger.With(logger.Fields{"org": org, "repo": collectors.FullRepoName(org, repo.Name())}).WithError(err).Warnf("skipping an invalid workflow")
+			continue
+		}
+		repo.Workflows = append(repo.Workflows, workflow)
+	}
+
+	return repo, nil
+}
+
+// withDependabotConfig parses the Dependabot version updates configuration of the default branch
+func (rc *repositoryCollector) withDependabotConfig(repo ghcollected.Repository, org string) (ghcollected.Repository, error) {
+	for _, path := range dependabot.Paths {
+		content, _, _, err := rc.Client.Client().Repositories.GetContents(rc.Context, org, repo.Name(), path, nil)
+		if err != nil {
+			var errResp *github.ErrorResponse
+			if errors.As(err, &errResp) && errResp.Response.StatusCode == http.StatusNotFound {
+				continue
+			}
+			return repo, err
+		}
+
+		decoded, err := content.GetContent()
+		if err != nil {
+			return repo, err
+		}
+		config, err := dependabot.Parse(path, []byte(decoded))
+		if err != nil {
+			return repo, err
+		}
+		repo.DependabotConfig = config
+		return repo, nil
+	}
+
+	repo.DependabotConfig = dependabot.Missing()
+	return repo, nil
+}
+
+// withCodeowners parses the CODEOWNERS file of the default branch, including the errors GitHub detected in it (e.g. unknown owners)
+func (rc *repositoryCollector) withCodeowners(repo ghcollected.Repository, org string) (ghcollected.Repository, error) {
+	for _, path := range codeowners.Paths {
+		content, _, _, err := rc.Client.Client().Repositories.GetContents(rc.Context, org, repo.Name(), path, nil)
+		if err != nil {
+			var errResp *github.ErrorResponse
+			if errors.As(err, &errResp) && errResp.Response.StatusCode == http.StatusNotFound {
+				continue
+			}
+			return repo, err
+		}
+
+		decoded, err := content.GetContent()
+		if err != nil {
+			return repo, err
+		}
+		file := codeowners.Parse(path, []byte(decoded))
+
+		codeownersErrors, err := rc.Client.GetCodeownersErrors(org, repo.Name())
+		if err != nil {
+			return repo, err
+		}
+		file.AddErrors(codeownersErrors)
+
+		repo.Codeowners = file
+		return repo, nil
+	}
+
+	repo.Codeowners = codeowners.Missing()
+	return repo, nil
+}
+
+// withCodeownersTeams collects the members of the organization teams that own code, to tell who can approve changes to it
+func (rc *repositoryCollector) withCodeownersTeams(repo ghcollected.Repository, org string) (ghcollected.Repository, error) {
+	if repo.Codeowners == nil {
+		return repo, nil
+	}
+
+	repo.CodeownersTeams = make(map[string][]string)
+	for _, team := range repo.Codeowners.Teams() {
+		teamOrg, slug, _ := strings.Cut(strings.TrimPrefix(team, "@"), "/")
+		if !strings.EqualFold(teamOrg, org) {
+			continue // only teams of the repository's organization can own its code
+		}
+
+		members, err := rc.getTeamMembers(org, slug)
+		if err != nil {
+			return repo, err
+		}
+		repo.CodeownersTeams[team] = members
+	}
+
+	return repo, nil
+}
+
+// getTeamMembers returns the logins of the team members (cached)
+func (rc *repositoryCollector) getTeamMembers(org string, slug string) ([]string, error) {
+	team := strings.ToLower(fmt.Sprintf("@%s/%s", org, slug))
+	rc.teamMembersMutex.Lock()
+	members, cached := rc.teamMembers[team]
+	rc.teamMembersMutex.Unlock()
+	if cached {
+		return members, nil
+	}
+
+	members, err := rc.Client.GetTeamMembers(org, slug)
+	if err != nil {
+		return nil, err
+	}
+	rc.teamMembersMutex.Lock()
+	rc.teamMembers[team] = members
+	rc.teamMembersMutex.Unlock()
+	return members, nil
+}
+
+// getTeamSlug returns the slug of the organization team (cached; empty if the team doesn't exist)
+func (rc *repositoryCollector) getTeamSlug(org string, id int64) (string, error) {
+	rc.teamSlugsMutex.Lock()
+	defer rc.teamSlugsMutex.Unlock()
+	slugs, cached := rc.teamSlugs[org]
+	if !cached {
+		var err error
+		slugs, err = rc.Client.GetTeamSlugs(org)
+		if err != nil {
+			return "", err
+		}
+		rc.teamSlugs[org] = slugs
+	}
+	return slugs[id], nil
+}
+
+// securityPolicyPaths are the possible paths of the security policy, in the order GitHub looks for it
+var securityPolicyPaths = []string{".github/SECURITY.md", "SECURITY.md", "docs/SECURITY.md"}
+
+// organizationDefaultsRepository is the repository of the organization's default community health files
+const organizationDefaultsRepository = ".github"
+
+// findSecurityPolicy returns the path of the security policy of the repository (empty if there is none)
+func (rc *repositoryCollector) findSecurityPolicy(org string, repo string) (string, error) {
+	for _, path := range securityPolicyPaths {
+		_, _, _, err := rc.Client.Client().Repositories.GetContents(rc.Context, org, repo, path, nil)
+		if err != nil {
+			var errResp *github.ErrorResponse
+			if errors.As(err, &errResp) && errResp.Response.StatusCode == http.StatusNotFound {
+				continue
+			}
+			return "", err
+		}
+		return path, nil
+	}
+	return "", nil
+}
+
+// findDefaultSecurityPolicy returns the path of the organization's default security policy (empty if there is none)
+func (rc *repositoryCollector) findDefaultSecurityPolicy(org string) (string, error) {
+	defaults, _, err := rc.Client.Client().Repositories.Get(rc.Context, org, organizationDefaultsRepository)
+	if err != nil {
+		var errResp *github.ErrorResponse
+		if errors.As(err, &errResp) && errResp.Response.StatusCode == http.StatusNotFound {
+			return "", nil
+		}
+		return "", err
+	}
+	if defaults.GetPrivate() {
+		return "", nil // the defaults apply from a public repository only
+	}
+
+	return rc.findSecurityPolicy(org, organizationDefaultsRepository)
+}
+
+// withSecurityPolicy looks for the repository's security policy, or the organization's default one
+func (rc *repositoryCollector) withSecurityPolicy(repo ghcollected.Repository, org string) (ghcollected.Repository, error) {
+	path, err := rc.findSecurityPolicy(org, repo.Name())
+	if err != nil {
+		return repo, err
+	}
+	if path != "" {
+		repo.SecurityPolicy = &ghcollected.SecurityPolicy{Exists: true, Path: path}
+		return repo, nil
+	}
+
+	rc.defaultSecurityPoliciesMutex.Lock()
+	defaultPath, cached := rc.defaultSecurityPolicies[org]
+	rc.defaultSecurityPoliciesMutex.Unlock()
+	if !cached {
+		defaultPath, err = rc.findDefaultSecurityPolicy(org)
+		if err != nil {
+			return repo, err
+		}
+		rc.defaultSecurityPoliciesMutex.Lock()
+		rc.defaultSecurityPolicies[org] = defaultPath
+		rc.defaultSecurityPoliciesMutex.Unlock()
+	}
+
+	if defaultPath != "" {
+		repo.SecurityPolicy = &ghcollected.SecurityPolicy{Exists: true, Path: organizationDefaultsRepository + "/" + defaultPath, Inherited: true}
+		return repo, nil
+	}
+
+	repo.SecurityPolicy = &ghcollected.SecurityPolicy{Exists: false}
+	return repo, nil
+}
+
+// withInteractionLimit collects the temporary interaction limit (which applies to public repositories only)
+func (rc *repositoryCollector) withInteractionLimit(repo ghcollected.Repository, org string) (ghcollected.Repository, error) {
+	if repo.Repository.IsPrivate {
+		return repo, nil
+	}
+	if !rc.Client.IsFeatureSupported(ghclient.FeatureInteractionLimits) {
+		rc.IssueMissingPermissions(collectors.NewUnsupportedFeature(org, "Cannot read the repositories interaction limit", namespace.Repository))
+		return repo, nil
+	}
+
+	restriction, _, err := rc.Client.Client().Interactions.GetRestrictionsForRepo(rc.Context, org, repo.Name())
+	if err != nil {
+		return repo, err
+	}
+	repo.InteractionLimit = ghcollected.NewInteractionLimit(restriction)
+	return repo, nil
+}
+
+// withPages collects the repository's GitHub Pages configuration (not found for repositories without a pages site)
+func (rc *repositoryCollector) withPages(repo ghcollected.Repository, org string) (ghcollected.Repository, error) {
+	pages, resp, err := rc.Client.Client().Repositories.GetPagesInfo(rc.Context, org, repo.Name())
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			repo.Pages = &ghcollected.Pages{Enabled: false}
+			return repo, nil
+		}
+		return repo, err
+	}
+
+	repo.Pages = &ghcollected.Pages{
+		Enabled:       true,
+		CustomDomain:  pages.GetCNAME(),
+		HTTPSEnforced: pages.GetHTTPSEnforced(),
+		Public:        pages.GetPublic(),
+	}
+	if pages.Source != nil {
+		repo.Pages.SourceBranch = pages.Source.GetBranch()
+		repo.Pages.SourcePath = pages.Source.GetPath()
+	}
+	return repo, nil
+}
+
+// withEnforcement computes who can push unreviewed code to the default branch
+// (unknown without the branch protection info, or the collaborators that can push)
+func (rc *repositoryCollector) withEnforcement(repo ghcollected.Repository, org string) (ghcollected.Repository, error) {
+	if repo.NoBranchProtectionPermission || repo.Repository.DefaultBranchRef == nil || repo.Collaborators == nil {
+		return repo, nil
+	}
+
+	var layers []enforcement.Layer
+	if prot := repo.Repository.DefaultBranchRef.BranchProtectionRule; prot != nil && prot.RequiredApprovingReviewCount != nil && *prot.RequiredApprovingReviewCount > 0 {
+		layer := enforcement.Layer{Name: "branch protection rule"}
+		if prot.IsAdminEnforced == nil || !*prot.IsAdminEnforced {
+			layer.Roles = append(layer.Roles, enforcement.Admin)
+		}
+		if prot.BypassPullRequestAllowances != nil {
+			for _, node := range prot.BypassPullRequestAllowances.Nodes {
+				layer.Actors = append(layer.Actors, bypassAllowanceActor(node.Actor))
+			}
+		}
+		layers = append(layers, layer)
+	}
+
+	for _, ruleset := range reviewRequiringRulesets(repo) {
+		layer := enforcement.Layer{Name: fmt.Sprintf("ruleset %s", ruleset.Name)}
+		for _, actor := range ruleset.BypassActors {
+			id := databaseID(actor.ActorID)
+			switch actor.ActorType {
+			case "OrganizationAdmin":
+				// organization owners are admins of all the repositories
+				layer.Roles = append(layer.Roles, enforcement.Admin)
+			case "RepositoryRole":
+				if role, ok := rulesetRepositoryRoles[id]; ok {
+					layer.Roles = append(layer.Roles, role)
+				}
+			case "Team":
+				layer.Actors = append(layer.Actors, enforcement.Actor{Type: enforcement.Team, ID: id})
+			case "Integration":
+				layer.Actors = append(layer.Actors, enforcement.Actor{Type: enforcement.App, ID: id})
+			case "DeployKey":
+				layer.Actors = append(layer.Actors, enforcement.Actor{Type: enforcement.DeployKey, ID: id})
+			}
+		}
+		layers = append(layers, layer)
+	}
+
+	// if the teams can't be resolved, they are still reported if they can bypass all the layers directly
+	memberTeams, err := rc.bypassingTeamsMembers(org, layers)
+
+	var collaborators []enforcement.Collaborator
+	for _, user := range repo.Collaborators {
+		collaborators = append(collaborators, enforcement.Collaborator{
+			ID:    user.GetID(),
+			Login: user.GetLogin(),
+			Role:  collaboratorRole(user.GetPermissions()),
+			Teams: memberTeams[strings.ToLower(user.GetLogin())],
+		})
+	}
+
+	repo.Enforcement = enforcement.Compute(layers, collaborators)
+	return repo, err
+}
+
+// bypassingTeamsMembers returns the bypassing teams of each member (by lowercase login).
+// The ruleset bypass teams only have an id, so their slug is filled in the layers.
+func (rc *repositoryCollector) bypassingTeamsMembers(org string, layers []enforcement.Layer) (map[string][]enforcement.Actor, error) {
+	result := make(map[string][]enforcement.Actor)
+	resolved := make(map[string]bool)
+	for _, layer := range layers {
+		for i, actor := range layer.Actors {
+			if actor.Type != enforcement.Team {
+				continue
+			}
+			if actor.Name == "" {
+				slug, err := rc.getTeamSlug(org, actor.ID)
+				if err != nil {
+					return result, err
+				}
+				actor.Name = slug
+				layer.Actors[i] = actor
+			}
+			if actor.Name == "" || resolved[strings.ToLower(actor.Name)] {
+				continue // deleted team, or already resolved
+			}
+			resolved[strings.ToLower(actor.Name)] = true
+
+			members, err := rc.getTeamMembers(org, actor.Name)
+			if err != nil {
+				return result, err
+			}
+			for _, login := range members {
+				login = strings.ToLower(login)
+				result[login] = append(result[login], actor)
+			}
+		}
+	}
+	return result, nil
+}
+
+// rulesetRepositoryRoles maps the ruleset bypass actor ids of the RepositoryRole type to the base repository roles
+var rulesetRepositoryRoles = map[int64]string{
+	2: enforcement.Maintain,
+	4: enforcement.Write,
+	5: enforcement.Admin,
+}
+
+func collaboratorRole(permissions map[string]bool) string {
+	switch {
+	case permissions["admin"]:
+		return enforcement.Admin
+	case permissions["maintain"]:
+		return enforcement.Maintain
+	case permissions["push"]:
+		return enforcement.Write
+	}
+	return ""
+}
+
+// collaboratorPermission returns the highest repository permission of the collaborator (including the roles that can't push)
+func collaboratorPermission(permissions map[string]bool) string {
+	if role := collaboratorRole(permissions); role != "" {
+		return role
+	}
+	if permissions["triage"] {
+		return "triage"
+	}
+	return "read"
+}
+
+func bypassAllowanceActor(actor ghcollected.GitHubQLBypassActor) enforcement.Actor {
+	switch {
+	case actor.User.Login != "":
+		return enforcement.Actor{Type: enforcement.User, ID: databaseID(actor.User.DatabaseId), Name: actor.User.Login}
+	case actor.Team.Slug != "":
+		return enforcement.Actor{Type: enforcement.Team, ID: databaseID(actor.Team.DatabaseId), Name: actor.Team.Slug}
+	default:
+		return enforcement.Actor{Type: enforcement.App, ID: databaseID(actor.App.DatabaseId), Name: actor.App.Name}
+	}
+}
+
+func databaseID(id *int64) int64 {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
+
+// reviewRequiringRulesets returns the rulesets whose active rules require a review of the default branch changes
+func reviewRequiringRulesets(repo ghcollected.Repository) []ghtypes.Ruleset {
+	var result []ghtypes.Ruleset
+	seen := make(map[int64]bool)
+	for _, rule := range repo.DefaultBranchRules {
+		if rule.Type != "pull_request" || seen[rule.RulesetID] {
+			continue
+		}
+		if count, ok := rule.Parameters["required_approving_review_count"].(float64); !ok || count < 1 {
+			continue
+		}
+		seen[rule.RulesetID] = true
+		for _, ruleset := range repo.Rulesets {
+			if ruleset.ID == rule.RulesetID {
+				result = append(result, ruleset)
+			}
+		}
+	}
+	return result
+}
+
+// withSuspiciousFiles looks for files that usually contain secrets by their names in the default branch tree (without their content)
+func (rc *repositoryCollector) withSuspiciousFiles(repo ghcollected.Repository, org string) (ghcollected.Repository, error) {
+	branch := repo.Repository.DefaultBranchRef
+	if branch == nil || branch.Name == nil {
+		// an empty repository
+		return repo, nil
+	}
+
+	tree, _, err := rc.Client.Client().Git.GetTree(rc.Context, org, repo.Name(), *branch.Name, true)
+	if err != nil {
+		return repo, err
+	}
+	if tree.GetTruncated() {
+		logger.With(logger.Fields{"org": org, "repo": collectors.FullRepoName(org, repo.Name())}).
+			Warnf("the repository tree is too large to be fetched at once, only some of its files are checked for suspicious names")
+	}
+
+	var paths []string
+	for _, entry := range tree.Entries {
+		if entry.GetType() == "blob" {
+			paths = append(paths, entry.GetPath())
+		}
+	}
+	repo.SuspiciousFiles = suspicious.Find(paths)
+
 	return repo, nil
 }
 
@@ -331,8 +1300,7 @@ func (rc *repositoryCollector) withRepositoryHooks(repo ghcollected.Repository,
 		hooks, resp, err := rc.Client.Client().Repositories.ListHooks(rc.Context, org, repo.Repository.Name, opts)
 		if err != nil {
 			if resp.Response.StatusCode == 404 {
-				perm := collectors.NewMissingPermission(permissions.RepoHookRead, collectors.FullRepoName(org, repo.Repository.Name),
-					"Cannot read repository webhooks", namespace.Repository)
+				perm := repoWebhooksPermission.Missing(collectors.FullRepoName(org, repo.Repository.Name))
 				rc.IssueMissingPermissions(perm)
 			}
 			return nil, err
@@ -348,6 +1316,22 @@ func (rc *repositoryCollector) withRepositoryHooks(repo ghcollected.Repository,
 	}
 
 	repo.Hooks = result
+	for _, hook := range result {
+		repo.HooksSettings = append(repo.HooksSettings, webhooks.NewSettings(hook, context_utils.GetWebhookDomains(rc.Context)))
+	}
+	if len(result) > 0 && !rc.Client.IsFeatureSupported(ghclient.FeatureWebhookDeliveries) {
+		rc.IssueMissingPermissions(collectors.NewUnsupportedFeature(org, "Cannot read the repositories webhook deliveries", namespace.Repository))
+		return repo, nil
+	}
+	for _, hook := range result {
+		health, err := collectWebhookHealth(hook, func(opts *github.ListCursorOptions) ([]*github.HookDelivery, *github.Response, error) {
+			return rc.Client.Client().Repositories.ListHookDeliveries(rc.Context, org, repo.Repository.Name, hook.GetID(), opts)
+		})
+		if err != nil {
+			return repo, err
+		}
+		repo.HooksHealth = append(repo.HooksHealth, health)
+	}
 	return repo, nil
 }
 
@@ -364,17 +1348,44 @@ func (rc *repositoryCollector) withVulnerabilityAlerts(repo ghcollected.Reposito
 }
 
 func (rc *repositoryCollector) withRepoCollaborators(repo ghcollected.Repository, org string) (ghcollected.Repository, error) {
-	users, _, err := rc.Client.Client().Repositories.ListCollaborators(rc.Context, org, repo.Repository.Name, &github.ListCollaboratorsOptions{})
-
+	users, err := rc.listCollaborators(org, repo.Repository.Name, "all")
 	if err != nil {
 		return repo, err
 	}
-
 	repo.Collaborators = users
 
+	outside, err := rc.listCollaborators(org, repo.Repository.Name, "outside")
+	if err != nil {
+		return repo, err
+	}
+	repo.OutsideCollaborators = []ghcollected.OutsideCollaborator{}
+	for _, user := range outside {
+		repo.OutsideCollaborators = append(repo.OutsideCollaborators, ghcollected.OutsideCollaborator{
+			Login:      user.GetLogin(),
+			Permission: collaboratorPermission(user.GetPermissions()),
+		})
+	}
+
 	return repo, nil
 }
 
+func (rc *repositoryCollector) listCollaborators(org string, repo string, affiliation string) ([]*github.User, error) {
+	// not nil, so repositories without collaborators aren't mistaken for ones whose collaborators weren't collected
+	result := []*github.User{}
+	err := ghclient.PaginateResults(func(opts *github.ListOptions) (*github.Response, error) {
+		users, resp, err := rc.Client.Client().Repositories.ListCollaborators(rc.Context, org, repo, &github.ListCollaboratorsOptions{
+			Affiliation: affiliation,
+			ListOptions: *opts,
+		})
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, users...)
+		return resp, nil
+	})
+	return result, err
+}
+
 // fixBranchProtectionInfo fixes the branch protection info for the repository,
 // to reflect whether there is no branch protection, or just no permission to fetch the info.
 func (rc *repositoryCollector) fixBranchProtectionInfo(repository ghcollected.Repository, org string) (ghcollected.Repository, error) {
@@ -389,7 +1400,7 @@ func (rc *repositoryCollector) fixBranchProtectionInfo(repository ghcollected.Re
 	branchName := *repository.Repository.DefaultBranchRef.Name
 	_, _, err := rc.Client.Client().Repositories.GetBranchProtection(rc.Context, org, repoName, branchName)
 	if err == nil {
-		log.Printf("inconsistent permissions (GitHub bug): graphQL query failed, but branch protection info is available. Ignoring\n")
+		logger.With(logger.Fields{"org": org, "repo": collectors.FullRepoName(org, repoName)}).Warnf("inconsistent permissions (GitHub bug): graphQL query failed, but branch protection info is available. Ignoring")
 		return repository, nil
 	}
 
@@ -415,8 +1426,7 @@ func (rc *repositoryCollector) fixBranchProtectionInfo(repository ghcollected.Re
 func (rc *repositoryCollector) checkMissingPermissions(repo ghcollected.Repository, entityName string) []collectors.MissingPermission {
 	var missingPermissions []collectors.MissingPermission
 	if repo.NoBranchProtectionPermission {
-		effect := "Cannot read repository branch protection information"
-		perm := collectors.NewMissingPermission(permissions.RepoAdmin, entityName, effect, namespace.Repository)
+		perm := repoBranchProtectionPermission.Missing(entityName)
 		missingPermissions = append(missingPermissions, perm)
 	}
 	return missingPermissions
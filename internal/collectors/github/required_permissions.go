@@ -0,0 +1,111 @@
+package github
+
+import (
+	"github.com/Legit-Labs/legitify/internal/collectors"
+	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/Legit-Labs/legitify/internal/common/permissions"
+)
+
+var (
+	orgCustomRolesPermission = collectors.NewRequiredPermission(permissions.OrgAdmin,
+		"Cannot read the organization custom repository roles", namespace.Organization)
+	orgTransfersPermission = collectors.NewRequiredPermission(permissions.OrgAdmin,
+		"Cannot read repository transfers from the organization audit log", namespace.Organization)
+	orgOAuthAppPolicyPermission = collectors.NewRequiredPermission(permissions.OrgAdmin,
+		"Cannot read the OAuth app access policy from the organization audit log", namespace.Organization)
+	orgWebhooksPermission = collectors.NewRequiredPermission(permissions.OrgHookAdmin,
+		"Cannot read organization webhooks", namespace.Organization)
+	orgAppInstallationsPermission = collectors.NewRequiredPermission(permissions.OrgAdmin,
+		"Cannot read the organization app installations", namespace.Organization)
+	orgSamlIdentitiesPermission = collectors.NewRequiredPermission(permissions.OrgAdmin,
+		"Cannot read the organization saml identities", namespace.Organization)
+	orgIPAllowListPermission = collectors.NewRequiredPermission(permissions.OrgAdmin,
+		"Cannot read the organization ip allow list", namespace.Organization)
+	orgCodespacesSecretsPermission = collectors.NewRequiredPermission(permissions.OrgAdmin,
+		"Cannot read the organization codespaces secrets", namespace.Organization)
+	orgCodespacesPermission = collectors.NewRequiredPermission(permissions.OrgAdmin,
+		"Cannot read the organization codespaces", namespace.Organization)
+	orgPackagesPermission = collectors.NewRequiredPermission(permissions.PackagesRead,
+		"Cannot read the organization packages", namespace.Organization)
+	orgVerifiedDomainsPermission = collectors.NewRequiredPermission(permissions.OrgAdmin,
+		"Cannot read the organization verified domains", namespace.Organization)
+	orgInteractionLimitPermission = collectors.NewRequiredPermission(permissions.OrgAdmin,
+		"Cannot read the organization interaction limit", namespace.Organization)
+	orgSecurityManagersPermission = collectors.NewRequiredPermission(permissions.OrgAdmin,
+		"Cannot read the organization security managers", namespace.Organization)
+
+	orgInfoPermission = collectors.NewRequiredPermission(permissions.OrgRead,
+		"Cannot read organization information", namespace.Organization)
+	orgMemberLastActivePermission = collectors.NewRequiredPermission(permissions.OrgAdmin,
+		"Cannot read organization member last active time", namespace.Member)
+	orgInvitationsPermission = collectors.NewRequiredPermission(permissions.OrgAdmin,
+		"Cannot read organization pending invitations", namespace.Member)
+	orgAuditLogActivityPermission = collectors.NewRequiredPermission(permissions.OrgAdmin,
+		"Cannot read the organization audit log activity of the members", namespace.Member)
+
+	orgActionsSettingsPermission = collectors.NewRequiredPermission(permissions.OrgAdmin,
+		"Cannot read organization actions settings", namespace.Organization)
+	orgActionsSecretsPermission = collectors.NewRequiredPermission(permissions.OrgAdmin,
+		"Cannot read organization actions secrets", namespace.Organization)
+	orgRequiredWorkflowsPermission = collectors.NewRequiredPermission(permissions.OrgAdmin,
+		"Cannot read organization required workflows", namespace.Organization)
+
+	repoBranchProtectionPermission = collectors.NewRequiredPermission(permissions.RepoAdmin,
+		"Cannot read repository branch protection information", namespace.Repository)
+	repoCodeScanningPermission = collectors.NewRequiredPermission(permissions.RepoSecurityEvents,
+		"Cannot read repository code scanning analyses", namespace.Repository)
+	repoActionsSettingsPermission = collectors.NewRequiredPermission(permissions.RepoAdmin,
+		"Cannot read repository actions settings", namespace.Repository)
+	repoSelfHostedRunnersPermission = collectors.NewRequiredPermission(permissions.RepoAdmin,
+		"Cannot read repository self-hosted runners", namespace.Repository)
+	repoWebhooksPermission = collectors.NewRequiredPermission(permissions.RepoHookRead,
+		"Cannot read repository webhooks", namespace.Repository)
+
+	runnerGroupRunnersPermission = collectors.NewRequiredPermission(permissions.OrgAdmin,
+		"Cannot read the runners of the runner groups", namespace.RunnerGroup)
+)
+
+// requiredPermissions are the permissions each collector (by its namespace) needs for a full collection
+var requiredPermissions = map[namespace.Namespace][]collectors.RequiredPermission{
+	namespace.Organization: {
+		orgCustomRolesPermission,
+		orgTransfersPermission,
+		orgOAuthAppPolicyPermission,
+		orgWebhooksPermission,
+		orgAppInstallationsPermission,
+		orgSamlIdentitiesPermission,
+		orgIPAllowListPermission,
+		orgCodespacesSecretsPermission,
+		orgCodespacesPermission,
+		orgPackagesPermission,
+		orgVerifiedDomainsPermission,
+		orgInteractionLimitPermission,
+		orgSecurityManagersPermission,
+	},
+	namespace.Member: {
+		orgInfoPermission,
+		orgMemberLastActivePermission,
+		orgInvitationsPermission,
+		orgAuditLogActivityPermission,
+	},
+	namespace.Actions: {
+		orgActionsSettingsPermission,
+		orgActionsSecretsPermission,
+		orgRequiredWorkflowsPermission,
+	},
+	namespace.Repository: {
+		repoBranchProtectionPermission,
+		repoCodeScanningPermission,
+		repoActionsSettingsPermission,
+		repoSelfHostedRunnersPermission,
+		repoWebhooksPermission,
+	},
+	namespace.RunnerGroup: {
+		runnerGroupRunnersPermission,
+	},
+}
+
+// RequiredPermissions returns the permissions the collector of the namespace needs for a full collection
+func RequiredPermissions(ns namespace.Namespace) []collectors.RequiredPermission {
+	return requiredPermissions[ns]
+}
@@ -7,9 +7,9 @@ import (
 	"github.com/Legit-Labs/legitify/internal/common/group_waiter"
 	"github.com/Legit-Labs/legitify/internal/common/namespace"
 	"github.com/Legit-Labs/legitify/internal/common/permissions"
+	"github.com/Legit-Labs/legitify/internal/logger"
 	"github.com/google/go-github/v44/github"
 	"golang.org/x/net/context"
-	"log"
 	"sync"
 )
 
@@ -38,7 +38,7 @@ func (c *runnersCollector) CollectMetadata() collectors.Metadata {
 	gw := group_waiter.New()
 	orgs, err := c.client.CollectOrganizations()
 	if err != nil {
-		log.Printf("failed to collection organizations %s", err)
+		logger.WithError(err).Errorf("failed to collect organizations")
 		return collectors.Metadata{}
 	}
 
@@ -60,7 +60,7 @@ func (c *runnersCollector) CollectMetadata() collectors.Metadata {
 			})
 
 			if err != nil {
-				log.Printf("Error collecting runner groups for %s - %v", org.Name(), err)
+				logger.With(logger.Fields{"org": org.Name()}).WithError(err).Errorf("error collecting runner groups")
 			} else {
 				mutex.Lock()
 				c.cache[org.Name()] = result
@@ -81,7 +81,7 @@ func (c *runnersCollector) Collect() collectors.SubCollectorChannels {
 		orgs, err := c.client.CollectOrganizations()
 
 		if err != nil {
-			log.Printf("failed to collect organizations %s", err)
+			logger.WithError(err).Errorf("failed to collect organizations")
 			return
 		}
 
@@ -92,13 +92,53 @@ func (c *runnersCollector) Collect() collectors.SubCollectorChannels {
 				c.CollectionChangeByOne()
 
 				c.CollectData(org,
-					ghcollected.RunnerGroup{
-						Organization: org,
-						RunnerGroup:  rg,
-					},
+					c.collectRunnerGroup(org, rg),
 					org.CanonicalLink(),
 					[]permissions.Role{org.Role})
 			}
+			c.OrganizationFinished(org.Name())
 		}
 	})
 }
+
+// collectRunnerGroup collects the runners of the group, and who can use them
+func (c *runnersCollector) collectRunnerGroup(org ghcollected.ExtendedOrg, rg *github.RunnerGroup) ghcollected.RunnerGroup {
+	result := ghcollected.RunnerGroup{
+		Organization: org,
+		RunnerGroup:  rg,
+	}
+	groupLog := logger.With(logger.Fields{"org": org.Name(), "runner_group": rg.GetName()})
+
+	runners, err := c.client.GetRunnerGroupRunners(org.Name(), rg.GetID())
+	if err != nil {
+		groupLog.WithError(err).Errorf("error collecting runner group runners")
+		perm := runnerGroupRunnersPermission.Missing(org.Name())
+		c.IssueMissingPermissions(perm)
+	} else {
+		result.Runners = runners
+	}
+
+	if rg.GetVisibility() == "selected" {
+		count, err := c.client.GetRunnerGroupRepositoriesCount(org.Name(), rg.GetID())
+		if err != nil {
+			groupLog.WithError(err).Errorf("error collecting runner group repositories")
+		} else {
+			result.SelectedRepositoriesCount = &count
+		}
+	}
+
+	if !c.client.IsFeatureSupported(ghclient.FeatureRunnerGroupWorkflowRestrictions) {
+		// the groups can't be restricted to selected workflows, so their runners are available to all the workflows
+		c.IssueMissingPermissions(collectors.NewUnsupportedFeature(org.Name(), "Cannot read the runner groups workflow restrictions", namespace.RunnerGroup))
+		return result
+	}
+
+	restrictions, err := c.client.GetRunnerGroupWorkflowRestrictions(org.Name(), rg.GetID())
+	if err != nil {
+		groupLog.WithError(err).Errorf("error collecting runner group workflow restrictions")
+	} else {
+		result.WorkflowRestrictions = restrictions
+	}
+
+	return result
+}
@@ -1,13 +1,18 @@
 package gitlab
 
 import (
+	"errors"
 	"github.com/Legit-Labs/legitify/internal/clients/gitlab"
 	"github.com/Legit-Labs/legitify/internal/collected/gitlab_collected"
 	"github.com/Legit-Labs/legitify/internal/collectors"
 	"github.com/Legit-Labs/legitify/internal/common/group_waiter"
 	"github.com/Legit-Labs/legitify/internal/common/permissions"
+	"github.com/Legit-Labs/legitify/internal/context_utils"
+	"github.com/Legit-Labs/legitify/internal/logger"
 	gitlab2 "github.com/xanzy/go-gitlab"
-	"log"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/Legit-Labs/legitify/internal/common/namespace"
 	"golang.org/x/net/context"
@@ -37,7 +42,7 @@ func (c *groupCollector) CollectMetadata() collectors.Metadata {
 	res := collectors.Metadata{}
 
 	if err != nil {
-		log.Printf("failed to collect groups %s", err)
+		logger.WithError(err).Errorf("failed to collect groups")
 	} else {
 		res.TotalEntities = len(groups)
 	}
@@ -49,33 +54,92 @@ func (c *groupCollector) Collect() collectors.SubCollectorChannels {
 	return c.WrappedCollection(func() {
 		groups, err := c.Client.Groups()
 		if err != nil {
-			log.Printf("failed to collect groups %s", err)
+			logger.WithError(err).Errorf("failed to collect groups")
 			return
 		}
 
+		maxTokenLifetime := c.collectMaxAccessTokenLifetime()
+
 		gw := group_waiter.New()
 
 		for _, g := range groups {
 			g := g
 			gw.Do(func() {
+				defer c.OrganizationFinished(g.FullPath)
 				fullGroup, _, err := c.Client.Client().Groups.GetGroup(g.ID, &gitlab2.GetGroupOptions{})
 				if err != nil {
-					log.Printf("failed to query group: %d - %s", g.ID, g.Name)
+					logger.With(logger.Fields{"group_id": g.ID, "group": g.Name}).WithError(err).Errorf("failed to query group")
 					return
 				}
 
 				hooks, err := c.Client.GroupHooks(fullGroup.ID)
 
 				if err != nil {
-					log.Printf("failed to query group hooks: %d - %s", g.ID, g.Name)
+					logger.With(logger.Fields{"group_id": g.ID, "group": g.Name}).WithError(err).Errorf("failed to query group hooks")
+				}
+
+				variables, err := c.collectVariables(fullGroup.ID)
+				if err != nil {
+					logger.With(logger.Fields{"group_id": g.ID, "group": g.Name}).WithError(err).Errorf("failed to query group variables")
+				}
+
+				var deployTokens []gitlab_collected.DeployToken
+				tokens, err := c.Client.GroupDeployTokens(fullGroup.ID)
+				if err != nil {
+					logger.With(logger.Fields{"group_id": g.ID, "group": g.Name}).WithError(err).Errorf("failed to query group deploy tokens")
+				} else {
+					deployTokens = gitlab_collected.NewDeployTokens(tokens)
+				}
+
+				var accessTokens []gitlab_collected.AccessToken
+				groupTokens, err := c.Client.GroupAccessTokens(fullGroup.ID)
+				if err != nil {
+					logger.With(logger.Fields{"group_id": g.ID, "group": g.Name}).WithError(err).Errorf("failed to query group access tokens")
+				} else {
+					accessTokens = gitlab_collected.NewGroupAccessTokens(groupTokens)
 				}
 
+				approvalSettings, err := c.collectApprovalSettings(fullGroup.ID)
+				if err != nil {
+					logger.With(logger.Fields{"group_id": g.ID, "group": g.Name}).WithError(err).Errorf("failed to query group approval settings")
+				}
+
+				var auditEvents *gitlab_collected.AuditEvents
+				if days := context_utils.GetAuditLogActivityDays(c.Context); days > 0 {
+					auditEvents, err = c.collectAuditEvents(fullGroup.ID, days)
+					if err != nil {
+						logger.With(logger.Fields{"group_id": g.ID, "group": g.Name}).WithError(err).Errorf("failed to query group audit events")
+					}
+				}
+
+				var samlSSO *gitlab_collected.SamlSSO
+				var groupMaxTokenLifetime *int
+				if fullGroup.ParentID == 0 {
+					groupMaxTokenLifetime = maxTokenLifetime
+					samlSSO, err = c.collectSamlSSO(fullGroup.ID)
+					if err != nil {
+						logger.With(logger.Fields{"group_id": g.ID, "group": g.Name}).WithError(err).Errorf("failed to query group saml identities")
+					}
+				}
+
+				// the registration token allows registering runners to the group, so it shouldn't be part of the output
+				registrationToken := fullGroup.RunnersToken != ""
+				fullGroup.RunnersToken = ""
+
 				entity := gitlab_collected.Organization{
-					Group: fullGroup,
-					Hooks: hooks,
+					Group:                          fullGroup,
+					Hooks:                          hooks,
+					Variables:                      variables,
+					DeployTokens:                   deployTokens,
+					AccessTokens:                   accessTokens,
+					MaxAccessTokenLifetime:         groupMaxTokenLifetime,
+					ApprovalSettings:               approvalSettings,
+					AuditEvents:                    auditEvents,
+					SamlSSO:                        samlSSO,
+					RunnerRegistrationTokenEnabled: registrationToken,
 				}
 
-				c.CollectDataWithContext(&entity, g.WebURL, newCollectionContext(g, []permissions.OrganizationRole{permissions.RepoRoleAdmin}))
+				c.CollectDataWithContext(g.FullPath, &entity, g.WebURL, newCollectionContext(g, []permissions.OrganizationRole{permissions.RepoRoleAdmin}))
 				c.CollectionChangeByOne()
 			})
 		}
@@ -83,3 +147,160 @@ func (c *groupCollector) Collect() collectors.SubCollectorChannels {
 		gw.Wait()
 	})
 }
+
+// collectVariables lists the metadata of the group CI/CD variables
+func (c *groupCollector) collectVariables(gid int) ([]gitlab_collected.Variable, error) {
+	variables, err := c.Client.GroupVariables(gid)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []gitlab_collected.Variable{}
+	for _, v := range variables {
+		result = append(result, gitlab_collected.Variable{
+			Key:              v.Key,
+			Type:             string(v.VariableType),
+			Protected:        v.Protected,
+			Masked:           v.Masked,
+			EnvironmentScope: v.EnvironmentScope,
+		})
+	}
+	return result, nil
+}
+
+// collectSamlSSO compares the group members with their linked SAML identities, and counts the SCIM provisioned identities
+func (c *groupCollector) collectSamlSSO(gid int) (*gitlab_collected.SamlSSO, error) {
+	members, err := c.Client.GroupMembers(gid)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &gitlab_collected.SamlSSO{UnlinkedUsernames: []string{}}
+	for _, member := range members {
+		if isBotUser(member.Username) {
+			continue
+		}
+		if member.GroupSAMLIdentity != nil {
+			result.LinkedMembers++
+		} else {
+			result.UnlinkedMembers++
+			result.UnlinkedUsernames = append(result.UnlinkedUsernames, member.Username)
+		}
+	}
+	result.Configured = result.LinkedMembers > 0
+
+	identities, err := c.Client.GroupScimIdentities(gid)
+	if err != nil {
+		// SCIM isn't available for the group (e.g. on self-managed instances)
+		logger.With(logger.Fields{"group_id": gid}).WithError(err).Debugf("failed to query group scim identities")
+		return result, nil
+	}
+	active := 0
+	for _, identity := range identities {
+		if identity.Active {
+			active++
+		}
+	}
+	result.ScimIdentities = &active
+
+	return result, nil
+}
+
+// collectMaxAccessTokenLifetime returns the instance's maximum lifetime of access tokens (nil if the token isn't of an instance administrator)
+func (c *groupCollector) collectMaxAccessTokenLifetime() *int {
+	settings, _, err := c.Client.Client().Settings.GetSettings()
+	if err != nil {
+		logger.WithError(err).Debugf("failed to query instance settings (requires an instance administrator)")
+		return nil
+	}
+	return &settings.MaxPersonalAccessTokenLifetime
+}
+
+// collectApprovalSettings collects the group merge request approval settings (nil if they aren't available, e.g. on GitLab Free)
+func (c *groupCollector) collectApprovalSettings(gid int) (*gitlab_collected.ApprovalSettings, error) {
+	settings, err := c.Client.GroupApprovalSettings(gid)
+	if err != nil {
+		var errResp *gitlab2.ErrorResponse
+		if errors.As(err, &errResp) && errResp.Response != nil &&
+			(errResp.Response.StatusCode == http.StatusNotFound || errResp.Response.StatusCode == http.StatusForbidden) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &gitlab_collected.ApprovalSettings{
+		ResetApprovalsOnPush:      !settings.RetainApprovalsOnPush.Value,
+		PreventAuthorApproval:     !settings.AllowAuthorApproval.Value,
+		PreventCommittersApproval: !settings.AllowCommitterApproval.Value,
+		PreventApproversOverride:  !settings.AllowOverridesToApproverListPerMergeRequest.Value,
+		RequireReauthentication:   settings.RequirePasswordToApprove.Value,
+	}, nil
+}
+
+// collectAuditEvents summarizes the group audit events of the last days (nil if they aren't available, e.g. on GitLab Free)
+func (c *groupCollector) collectAuditEvents(gid int, days int) (*gitlab_collected.AuditEvents, error) {
+	events, err := c.Client.GroupAuditEvents(gid, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		var errResp *gitlab2.ErrorResponse
+		if errors.As(err, &errResp) && errResp.Response != nil &&
+			(errResp.Response.StatusCode == http.StatusNotFound || errResp.Response.StatusCode == http.StatusForbidden) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	members, err := c.Client.GroupMembers(gid)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &gitlab_collected.AuditEvents{
+		WindowDays:        days,
+		PermissionChanges: []gitlab_collected.AuditEvent{},
+		VisibilityChanges: []gitlab_collected.AuditEvent{},
+		InactiveMembers:   []string{},
+	}
+
+	active := make(map[int]bool)
+	for _, event := range events {
+		active[event.AuthorID] = true
+
+		details := event.Details
+		collected := gitlab_collected.AuditEvent{
+			Author: details.AuthorName,
+			Target: details.TargetDetails,
+			From:   details.From,
+			To:     details.To,
+		}
+		if event.CreatedAt != nil {
+			collected.CreatedAt = event.CreatedAt.Format(time.RFC3339)
+		}
+
+		switch {
+		// a member was added to the group (as a role), or their role was changed
+		case details.Add == "user_access":
+			collected.To = details.As
+			result.PermissionChanges = append(result.PermissionChanges, collected)
+		case details.Change == "access_level":
+			result.PermissionChanges = append(result.PermissionChanges, collected)
+		case strings.Contains(details.Change, "visibility"):
+			if collected.Target == "" {
+				collected.Target = details.EntityPath
+			}
+			result.VisibilityChanges = append(result.VisibilityChanges, collected)
+		}
+	}
+
+	for _, member := range members {
+		if !isBotUser(member.Username) && !active[member.ID] {
+			result.InactiveMembers = append(result.InactiveMembers, member.Username)
+		}
+	}
+
+	return result, nil
+}
+
+// isBotUser returns whether the user is the bot of a group or a project access token
+func isBotUser(username string) bool {
+	return (strings.HasPrefix(username, "group_") || strings.HasPrefix(username, "project_")) && strings.Contains(username, "_bot")
+}
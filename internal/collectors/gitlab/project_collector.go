@@ -0,0 +1,429 @@
+package gitlab
+
+import (
+	"errors"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/Legit-Labs/legitify/internal/clients/gitlab"
+	"github.com/Legit-Labs/legitify/internal/collected/gitlab_collected"
+	"github.com/Legit-Labs/legitify/internal/collectors"
+	"github.com/Legit-Labs/legitify/internal/common/group_waiter"
+	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/Legit-Labs/legitify/internal/common/permissions"
+	"github.com/Legit-Labs/legitify/internal/common/types"
+	"github.com/Legit-Labs/legitify/internal/context_utils"
+	"github.com/Legit-Labs/legitify/internal/logger"
+	gitlab2 "github.com/xanzy/go-gitlab"
+	"golang.org/x/net/context"
+)
+
+// recentReleasesCount is the number of latest releases that are checked for unprotected tags
+const recentReleasesCount = 20
+
+type projectCollector struct {
+	collectors.BaseCollector
+	Client  *gitlab.Client
+	Context context.Context
+}
+
+func NewProjectCollector(ctx context.Context, client *gitlab.Client) collectors.Collector {
+	c := &projectCollector{
+		Client:  client,
+		Context: ctx,
+	}
+	collectors.InitBaseCollector(&c.BaseCollector, c)
+	return c
+}
+
+func (c *projectCollector) Namespace() namespace.Namespace {
+	return namespace.Repository
+}
+
+func (c *projectCollector) CollectMetadata() collectors.Metadata {
+	projects, err := c.Client.Repositories()
+	res := collectors.Metadata{}
+
+	if err != nil {
+		logger.WithError(err).Errorf("failed to collect projects")
+	} else {
+		res.TotalEntities = len(projects)
+	}
+
+	return res
+}
+
+func (c *projectCollector) Collect() collectors.SubCollectorChannels {
+	return c.WrappedCollection(func() {
+		projects, err := c.Client.Repositories()
+		if err != nil {
+			logger.WithError(err).Errorf("failed to collect projects")
+			return
+		}
+
+		// the projects are reported by their namespace (the group), which is finished once all of its projects were collected
+		var namespaces []string
+		for _, p := range projects {
+			namespaces = append(namespaces, projectNamespace(p))
+		}
+		countdown := c.NewOrganizationsCountdown(namespaces)
+		c.finishEmptyGroups(namespaces)
+
+		gw := group_waiter.New()
+
+		for _, p := range projects {
+			p := p
+			gw.Do(func() {
+				defer countdown.Done(projectNamespace(p))
+				project, _, err := c.Client.Client().Projects.GetProject(p.String(), &gitlab2.GetProjectOptions{})
+				if err != nil {
+					logger.With(logger.Fields{"project": p.String()}).WithError(err).Errorf("failed to query project")
+					return
+				}
+
+				environments, err := c.collectEnvironments(project.ID)
+				if err != nil {
+					logger.With(logger.Fields{"project": p.String()}).WithError(err).Errorf("failed to query project environments")
+				}
+
+				variables, err := c.collectVariables(project.ID)
+				if err != nil {
+					logger.With(logger.Fields{"project": p.String()}).WithError(err).Errorf("failed to query project variables")
+				}
+
+				var deployTokens []gitlab_collected.DeployToken
+				tokens, err := c.Client.ProjectDeployTokens(project.ID)
+				if err != nil {
+					logger.With(logger.Fields{"project": p.String()}).WithError(err).Errorf("failed to query project deploy tokens")
+				} else {
+					deployTokens = gitlab_collected.NewDeployTokens(tokens)
+				}
+
+				var accessTokens []gitlab_collected.AccessToken
+				projectTokens, err := c.Client.ProjectAccessTokens(project.ID)
+				if err != nil {
+					logger.With(logger.Fields{"project": p.String()}).WithError(err).Errorf("failed to query project access tokens")
+				} else {
+					accessTokens = gitlab_collected.NewProjectAccessTokens(projectTokens)
+				}
+
+				runners, err := c.collectRunners(project.ID)
+				if err != nil {
+					logger.With(logger.Fields{"project": p.String()}).WithError(err).Errorf("failed to query project runners")
+				}
+
+				schedules, err := c.collectPipelineSchedules(project.ID)
+				if err != nil {
+					logger.With(logger.Fields{"project": p.String()}).WithError(err).Errorf("failed to query project pipeline schedules")
+				}
+
+				protectedTags, releases, err := c.collectTags(project.ID)
+				if err != nil {
+					logger.With(logger.Fields{"project": p.String()}).WithError(err).Errorf("failed to query project protected tags and releases")
+				}
+
+				approvalSettings, err := c.collectApprovalSettings(project.ID)
+				if err != nil {
+					logger.With(logger.Fields{"project": p.String()}).WithError(err).Errorf("failed to query project approval settings")
+				}
+
+				registry, err := c.collectContainerRegistry(project)
+				if err != nil {
+					logger.With(logger.Fields{"project": p.String()}).WithError(err).Errorf("failed to query project container registry")
+				}
+
+				// the registration token allows registering runners to the project, so it shouldn't be part of the output
+				registrationToken := project.RunnersToken != ""
+				project.RunnersToken = ""
+
+				entity := gitlab_collected.Repository{
+					Project:                        project,
+					Environments:                   environments,
+					Variables:                      variables,
+					DeployTokens:                   deployTokens,
+					AccessTokens:                   accessTokens,
+					PipelineSchedules:              schedules,
+					Runners:                        runners,
+					ProtectedTags:                  protectedTags,
+					Releases:                       releases,
+					ApprovalSettings:               approvalSettings,
+					ContainerRegistry:              registry,
+					RunnerRegistrationTokenEnabled: registrationToken,
+					HighSensitivity:                isHighSensitivity(context_utils.GetHighSensitivityOrgs(c.Context), project.Namespace.FullPath),
+				}
+
+				c.CollectDataWithContext(project.Namespace.FullPath, &entity, project.WebURL, newCollectionContext(nil, []permissions.Role{p.Role}))
+				c.CollectionChangeByOne()
+			})
+		}
+
+		gw.Wait()
+	})
+}
+
+// projectNamespace is the full path of the namespace of the project
+func projectNamespace(p types.RepositoryWithOwner) string {
+	return path.Dir(p.String())
+}
+
+// finishEmptyGroups reports the groups that have no (analyzable) projects as finished
+func (c *projectCollector) finishEmptyGroups(namespaces []string) {
+	groups, err := c.Client.Groups()
+	if err != nil {
+		// e.g. a project access token, which can't list groups
+		logger.WithError(err).Debugf("failed to list the groups, the groups without projects will be finished at the end of the collection")
+		return
+	}
+
+	hasProjects := make(map[string]bool)
+	for _, ns := range namespaces {
+		hasProjects[ns] = true
+	}
+	for _, g := range groups {
+		if !hasProjects[g.FullPath] {
+			c.OrganizationFinished(g.FullPath)
+		}
+	}
+}
+
+// collectEnvironments lists the project environments with their protection
+func (c *projectCollector) collectEnvironments(pid int) ([]gitlab_collected.Environment, error) {
+	environments, err := c.Client.Environments(pid)
+	if err != nil {
+		return nil, err
+	}
+
+	protected, err := c.Client.ProtectedEnvironments(pid)
+	if err != nil {
+		// protected environments are only available on GitLab Premium, so none of the environments is protected
+		var errResp *gitlab2.ErrorResponse
+		if !errors.As(err, &errResp) || errResp.Response == nil ||
+			(errResp.Response.StatusCode != http.StatusNotFound && errResp.Response.StatusCode != http.StatusForbidden) {
+			return nil, err
+		}
+		protected = nil
+	}
+
+	protection := make(map[string]*gitlab2.ProtectedEnvironment)
+	for _, p := range protected {
+		protection[p.Name] = p
+	}
+
+	result := []gitlab_collected.Environment{}
+	for _, env := range environments {
+		collected := gitlab_collected.Environment{Name: env.Name, Tier: env.Tier, DeployAccessLevels: []string{}}
+		if p, ok := protection[env.Name]; ok {
+			collected.Protected = true
+			collected.RequiredApprovalCount = p.RequiredApprovalCount
+			for _, level := range p.DeployAccessLevels {
+				collected.DeployAccessLevels = append(collected.DeployAccessLevels, level.AccessLevelDescription)
+			}
+		}
+		result = append(result, collected)
+	}
+
+	return result, nil
+}
+
+// collectVariables lists the metadata of the project CI/CD variables
+func (c *projectCollector) collectVariables(pid int) ([]gitlab_collected.Variable, error) {
+	variables, err := c.Client.ProjectVariables(pid)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []gitlab_collected.Variable{}
+	for _, v := range variables {
+		result = append(result, gitlab_collected.Variable{
+			Key:              v.Key,
+			Type:             string(v.VariableType),
+			Protected:        v.Protected,
+			Masked:           v.Masked,
+			EnvironmentScope: v.EnvironmentScope,
+		})
+	}
+	return result, nil
+}
+
+// collectRunners lists the runners available to the project
+func (c *projectCollector) collectRunners(pid int) ([]gitlab_collected.Runner, error) {
+	runners, err := c.Client.ProjectRunners(pid)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []gitlab_collected.Runner{}
+	for _, r := range runners {
+		result = append(result, gitlab_collected.Runner{
+			ID:          r.ID,
+			Description: r.Description,
+			Type:        r.RunnerType,
+			Shared:      r.IsShared,
+			Paused:      r.Paused,
+			Online:      r.Online,
+		})
+	}
+	return result, nil
+}
+
+// collectPipelineSchedules lists the project pipeline schedules with the status of their owners
+func (c *projectCollector) collectPipelineSchedules(pid int) ([]gitlab_collected.PipelineSchedule, error) {
+	schedules, err := c.Client.PipelineSchedules(pid)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []gitlab_collected.PipelineSchedule{}
+	if len(schedules) == 0 {
+		return result, nil
+	}
+
+	var owners []int
+	for _, schedule := range schedules {
+		if schedule.Owner != nil {
+			owners = append(owners, schedule.Owner.ID)
+		}
+	}
+
+	members := make(map[int]bool)
+	if len(owners) > 0 {
+		projectMembers, err := c.Client.ProjectMembers(pid, owners)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range projectMembers {
+			members[member.ID] = true
+		}
+	}
+
+	for _, schedule := range schedules {
+		collected := gitlab_collected.PipelineSchedule{
+			ID:          schedule.ID,
+			Description: schedule.Description,
+			Ref:         schedule.Ref,
+			Active:      schedule.Active,
+		}
+		if schedule.Owner != nil {
+			collected.Owner = schedule.Owner.Username
+			collected.OwnerState = schedule.Owner.State
+			collected.OwnerIsMember = members[schedule.Owner.ID]
+		}
+		result = append(result, collected)
+	}
+	return result, nil
+}
+
+// collectTags lists the project protected tags, and the latest releases with whether their tags are protected
+func (c *projectCollector) collectTags(pid int) ([]gitlab_collected.ProtectedTag, []gitlab_collected.Release, error) {
+	tags, err := c.Client.ProtectedTags(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	protectedTags := []gitlab_collected.ProtectedTag{}
+	var patterns []string
+	for _, tag := range tags {
+		collected := gitlab_collected.ProtectedTag{Name: tag.Name, CreateAccessLevels: []string{}}
+		for _, level := range tag.CreateAccessLevels {
+			collected.CreateAccessLevels = append(collected.CreateAccessLevels, level.AccessLevelDescription)
+		}
+		protectedTags = append(protectedTags, collected)
+		patterns = append(patterns, tag.Name)
+	}
+
+	releases, err := c.Client.RecentReleases(pid, recentReleasesCount)
+	if err != nil {
+		return protectedTags, nil, err
+	}
+
+	collectedReleases := []gitlab_collected.Release{}
+	for _, release := range releases {
+		collectedReleases = append(collectedReleases, gitlab_collected.Release{
+			Name:         release.Name,
+			TagName:      release.TagName,
+			TagProtected: matchRef(patterns, release.TagName),
+		})
+	}
+
+	return protectedTags, collectedReleases, nil
+}
+
+// collectApprovalSettings collects the project merge request approval settings
+func (c *projectCollector) collectApprovalSettings(pid int) (*gitlab_collected.ApprovalSettings, error) {
+	approvals, _, err := c.Client.Client().Projects.GetApprovalConfiguration(pid)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gitlab_collected.ApprovalSettings{
+		ResetApprovalsOnPush:      approvals.ResetApprovalsOnPush,
+		PreventAuthorApproval:     !approvals.MergeRequestsAuthorApproval,
+		PreventCommittersApproval: approvals.MergeRequestsDisableCommittersApproval,
+		PreventApproversOverride:  approvals.DisableOverridingApproversPerMergeRequest,
+		RequireReauthentication:   approvals.RequirePasswordToApprove,
+	}, nil
+}
+
+// collectContainerRegistry collects the project container registry settings and tag protection rules
+func (c *projectCollector) collectContainerRegistry(project *gitlab2.Project) (*gitlab_collected.ContainerRegistry, error) {
+	registry := &gitlab_collected.ContainerRegistry{
+		AccessLevel: string(project.ContainerRegistryAccessLevel),
+	}
+	if registry.AccessLevel == "" && !project.ContainerRegistryEnabled {
+		registry.AccessLevel = string(gitlab2.DisabledAccessControl)
+	}
+	if policy := project.ContainerExpirationPolicy; policy != nil {
+		registry.CleanupPolicyEnabled = policy.Enabled
+		registry.CleanupCadence = policy.Cadence
+		registry.CleanupKeepN = policy.KeepN
+		registry.CleanupOlderThan = policy.OlderThan
+	}
+	if registry.AccessLevel == string(gitlab2.DisabledAccessControl) {
+		return registry, nil
+	}
+
+	rules, err := c.Client.ContainerRegistryTagProtectionRules(project.ID)
+	if err != nil {
+		// tag protection rules aren't available on older GitLab versions, so they are left uncollected
+		var errResp *gitlab2.ErrorResponse
+		if errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound {
+			return registry, nil
+		}
+		return nil, err
+	}
+
+	registry.ProtectedTagPatterns = []string{}
+	registry.ImmutableTagPatterns = []string{}
+	for _, rule := range rules {
+		if rule.MinimumAccessLevelForPush == nil && rule.MinimumAccessLevelForDelete == nil {
+			registry.ImmutableTagPatterns = append(registry.ImmutableTagPatterns, rule.TagNamePattern)
+		} else {
+			registry.ProtectedTagPatterns = append(registry.ProtectedTagPatterns, rule.TagNamePattern)
+		}
+	}
+
+	return registry, nil
+}
+
+// matchRef returns whether the ref name matches any of the protected ref patterns ('*' matches any characters, including '/')
+func matchRef(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+		if matched, _ := regexp.MatchString(expr, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// isHighSensitivity returns whether the group (full path) is one of the high-sensitivity groups or one of their subgroups
+func isHighSensitivity(groups []string, group string) bool {
+	for _, sensitive := range groups {
+		if strings.EqualFold(sensitive, group) || strings.HasPrefix(strings.ToLower(group), strings.ToLower(sensitive)+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Legit-Labs/legitify/internal/clients/gitlab"
+	"github.com/Legit-Labs/legitify/internal/collectors"
+	"github.com/stretchr/testify/require"
+)
+
+type testProject struct {
+	ID                int    `json:"id"`
+	PathWithNamespace string `json:"path_with_namespace"`
+	Namespace         struct {
+		FullPath string `json:"full_path"`
+	} `json:"namespace"`
+}
+
+func newTestProject(id int, path string) testProject {
+	p := testProject{ID: id, PathWithNamespace: path}
+	p.Namespace.FullPath = path[:strings.LastIndex(path, "/")]
+	return p
+}
+
+// newGitLabServer serves the given groups and projects, and responds with 404 to the rest of the API
+func newGitLabServer(groups []string, projects []testProject) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.EscapedPath()
+		switch {
+		case path == "/api/v4/groups":
+			var result []map[string]interface{}
+			for i, g := range groups {
+				result = append(result, map[string]interface{}{"id": i + 1, "full_path": g})
+			}
+			_ = json.NewEncoder(w).Encode(result)
+			return
+		case strings.HasSuffix(path, "/descendant_groups"):
+			_, _ = w.Write([]byte("[]"))
+			return
+		case path == "/api/v4/projects":
+			_ = json.NewEncoder(w).Encode(projects)
+			return
+		case strings.HasPrefix(path, "/api/v4/projects/"):
+			for _, p := range projects {
+				if path == "/api/v4/projects/"+strings.ReplaceAll(p.PathWithNamespace, "/", "%2F") {
+					_ = json.NewEncoder(w).Encode(p)
+					return
+				}
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"404 Not Found"}`))
+	}))
+}
+
+// collectFinishedOrganizations runs the collection and returns the organizations that were reported as finished
+func collectFinishedOrganizations(c collectors.Collector) (collected int, finished []string) {
+	channels := c.Collect()
+	progress, data, perm := channels.Progress, channels.Collected, channels.MissingPermission
+	for progress != nil || data != nil || perm != nil {
+		select {
+		case x, ok := <-progress:
+			if !ok {
+				progress = nil
+			} else if x.Finished && x.Organization != "" {
+				finished = append(finished, x.Organization)
+			}
+		case _, ok := <-data:
+			if !ok {
+				data = nil
+			} else {
+				collected++
+			}
+		case _, ok := <-perm:
+			if !ok {
+				perm = nil
+			}
+		}
+	}
+	return collected, finished
+}
+
+func TestProjectCollectorFinishesGroups(t *testing.T) {
+	projects := []testProject{
+		newTestProject(1, "group1/project1"),
+		newTestProject(2, "group1/project2"),
+		newTestProject(3, "group1/subgroup/project3"),
+	}
+	server := newGitLabServer([]string{"group1", "group1/subgroup", "group2"}, projects)
+	defer server.Close()
+
+	client, err := gitlab.NewClient(context.Background(), "token", server.URL+"/api/v4/", nil, gitlab.SubgroupsAll, false, nil)
+	require.NoError(t, err)
+
+	collected, finished := collectFinishedOrganizations(NewProjectCollector(context.Background(), client))
+	require.Equal(t, 3, collected)
+	// every group is finished exactly once: the empty group up front, the others after their last project
+	require.ElementsMatch(t, []string{"group1", "group1/subgroup", "group2"}, finished)
+	require.Equal(t, "group2", finished[0])
+}
+
+func TestGroupCollectorFinishesGroups(t *testing.T) {
+	server := newGitLabServer([]string{"group1", "group2"}, nil)
+	defer server.Close()
+
+	client, err := gitlab.NewClient(context.Background(), "token", server.URL+"/api/v4/", nil, gitlab.SubgroupsAll, false, nil)
+	require.NoError(t, err)
+
+	// the groups fail to be queried, but are still finished
+	collected, finished := collectFinishedOrganizations(NewGroupCollector(context.Background(), client))
+	require.Equal(t, 0, collected)
+	require.ElementsMatch(t, []string{"group1", "group2"}, finished)
+}
@@ -2,11 +2,11 @@ package collectors
 
 import (
 	"fmt"
-	"log"
 	"strings"
 
 	"github.com/Legit-Labs/legitify/internal/common/namespace"
 	"github.com/Legit-Labs/legitify/internal/common/utils"
+	"github.com/Legit-Labs/legitify/internal/logger"
 	"github.com/iancoleman/orderedmap"
 )
 
@@ -26,6 +26,43 @@ func NewMissingPermission(permission, entity, effect string, namespace namespace
 	}
 }
 
+// RequiredPermission is a permission a collector needs, with the effect of missing it.
+// The collectors declare their required permissions, and report them as missing for the entities they couldn't use them with.
+type RequiredPermission struct {
+	Permission string
+	Effect     string
+	Namespace  namespace.Namespace
+}
+
+func NewRequiredPermission(permission, effect string, namespace namespace.Namespace) RequiredPermission {
+	return RequiredPermission{
+		Permission: permission,
+		Effect:     effect,
+		Namespace:  namespace,
+	}
+}
+
+// Missing reports the permission as missing for the entity
+func (r RequiredPermission) Missing(entity string) MissingPermission {
+	return NewMissingPermission(r.Permission, entity, r.Effect, r.Namespace)
+}
+
+// PartialVisibility is reported (in place of a permission) for entities that were collected partially although
+// the token should see all of them, e.g. repositories hidden by an IP allow list, SAML SSO or fine-grained token restrictions
+const PartialVisibility = "partial visibility"
+
+func NewPartialVisibility(entity, effect string, namespace namespace.Namespace) MissingPermission {
+	return NewMissingPermission(PartialVisibility, entity, effect, namespace)
+}
+
+// UnsupportedFeature is reported (in place of a permission) for data that wasn't collected because the server version
+// doesn't have the feature, e.g. rulesets on older GitHub Enterprise Server versions
+const UnsupportedFeature = "unsupported feature"
+
+func NewUnsupportedFeature(entity, effect string, namespace namespace.Namespace) MissingPermission {
+	return NewMissingPermission(UnsupportedFeature, entity, effect, namespace)
+}
+
 type effectSet = map[string]bool
 
 func CollectMissingPermissions(missingPermissionChan chan MissingPermission) {
@@ -61,10 +98,8 @@ func CollectMissingPermissions(missingPermissionChan chan MissingPermission) {
 		permMap.Set(permission, entity)
 	}
 
-	// Build missing permissions string
-	var sb strings.Builder
+	// Log the missing permissions (an entry per entity)
 	for _, permission := range permMap.Keys() {
-		sb.WriteString(fmt.Sprintf("missing permission: \"%s\" on:\n", permission))
 		entity := utils.UnsafeGet(permMap, permission).(*orderedmap.OrderedMap)
 		for _, entityName := range entity.Keys() {
 			effects := utils.UnsafeGet(entity, entityName).(effectSet)
@@ -75,9 +110,25 @@ func CollectMissingPermissions(missingPermissionChan chan MissingPermission) {
 				}
 				filteredEffects = append(filteredEffects, effect)
 			}
-			effectString := strings.Join(filteredEffects, ", ")
-			sb.WriteString(fmt.Sprintf("    - %s [%s]\n", entityName, effectString))
+			if permission == PartialVisibility {
+				logger.With(logger.Fields{
+					"entity":  entityName,
+					"effects": strings.Join(filteredEffects, ", "),
+				}).Warnf("partial visibility")
+				continue
+			}
+			if permission == UnsupportedFeature {
+				logger.With(logger.Fields{
+					"entity":  entityName,
+					"effects": strings.Join(filteredEffects, ", "),
+				}).Infof("skipped, unsupported by the server version")
+				continue
+			}
+			logger.With(logger.Fields{
+				"permission": permission,
+				"entity":     entityName,
+				"effects":    strings.Join(filteredEffects, ", "),
+			}).Warnf("missing permission")
 		}
 	}
-	log.Print(sb.String())
 }
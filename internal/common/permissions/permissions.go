@@ -261,6 +261,28 @@ func ParseTokenScopes(scopesList []string) TokenScopes {
 	return scopes
 }
 
+// FullAnalysisScopes are the token scopes needed to analyze all of the GitHub namespaces
+var FullAnalysisScopes = []TokenScope{
+	OrgAdmin,
+	EnterpriseRead,
+	OrgHookAdmin,
+	OrgRead,
+	RepoAdmin,
+	RepoHookRead,
+}
+
+// MissingScopes returns the required scopes that are not granted by the available scopes
+func MissingScopes(availableScopes TokenScopes, requiredScopes []TokenScope) []TokenScope {
+	var missing []TokenScope
+	for _, scope := range requiredScopes {
+		if !availableScopes[scope] {
+			missing = append(missing, scope)
+		}
+	}
+
+	return missing
+}
+
 var orgMemberValidScopes = map[TokenScope]bool{
 	RepoAdmin:          false,
 	RepoRepoStatus:     false,
@@ -314,6 +336,14 @@ var orgMemberValidScopes = map[TokenScope]bool{
 	GpgKeyRead:  true,
 }
 
+// MinimalOrgRole returns the lowest organization role the scope is effective with
+func MinimalOrgRole(scope TokenScope) OrganizationRole {
+	if orgMemberValidScopes[scope] {
+		return OrgRoleMember
+	}
+	return OrgRoleOwner
+}
+
 func HasOrgScope(toCheck TokenScope, scopes TokenScopes, orgRole OrganizationRole) bool {
 	switch orgRole {
 	case OrgRoleOwner:
@@ -503,3 +533,31 @@ func HasRepoScope(toCheck TokenScope, scopes TokenScopes, repoRole RepositoryRol
 	allowed, ok := mapping[toCheck]
 	return ok && allowed && scopes[toCheck]
 }
+
+// MinimalRepoRole returns the lowest repository role the scope is effective with (RepoRoleNone if no repository role is enough)
+func MinimalRepoRole(scope TokenScope) RepositoryRole {
+	for _, role := range []RepositoryRole{RepoRoleRead, RepoRoleTriage, RepoRoleAdmin} {
+		if HasRepoScope(scope, TokenScopes{scope: true}, role) {
+			return role
+		}
+	}
+	return RepoRoleNone
+}
+
+// MinimalScopes removes the scopes that are implied by other scopes of the list (e.g. read:org by admin:org)
+func MinimalScopes(scopes []TokenScope) []TokenScope {
+	var minimal []TokenScope
+	for _, scope := range scopes {
+		implied := false
+		for _, other := range scopes {
+			if other != scope && ParseTokenScopes([]string{other})[scope] {
+				implied = true
+				break
+			}
+		}
+		if !implied {
+			minimal = append(minimal, scope)
+		}
+	}
+	return minimal
+}
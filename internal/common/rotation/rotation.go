@@ -0,0 +1,65 @@
+package rotation
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Legit-Labs/legitify/internal/common/severity"
+)
+
+// MaxAge is the maximal age (in days) of a secret before it should be rotated, by the severity of the violation.
+type MaxAge map[severity.Severity]int
+
+// Severities are the severities of the secret rotation policies
+var Severities = []severity.Severity{severity.High, severity.Medium}
+
+// DefaultMaxAge is used for the severities that are not configured
+var DefaultMaxAge = MaxAge{
+	severity.High:   365,
+	severity.Medium: 180,
+}
+
+// ParseMaxAge configures the maximal secret age per severity, e.g. {"high": 365, "medium": 90}.
+// The keys are case-insensitive and the severities that are not configured keep their default.
+func ParseMaxAge(mapping map[string]int) (MaxAge, error) {
+	result := make(MaxAge, len(DefaultMaxAge))
+	for key, days := range DefaultMaxAge {
+		result[key] = days
+	}
+
+	for key, days := range mapping {
+		sev := strings.ToUpper(strings.TrimSpace(key))
+		if sev != severity.High && sev != severity.Medium {
+			return nil, fmt.Errorf("invalid severity in secret max age: %s (valid severities: %s, %s)", key, severity.High, severity.Medium)
+		}
+		if days <= 0 {
+			return nil, fmt.Errorf("invalid secret max age for severity %s: %d (must be a positive number of days)", key, days)
+		}
+		result[sev] = days
+	}
+
+	if result[severity.High] <= result[severity.Medium] {
+		return nil, fmt.Errorf("the %s secret max age (%d days) must be greater than the %s one (%d days)",
+			severity.High, result[severity.High], severity.Medium, result[severity.Medium])
+	}
+
+	return result, nil
+}
+
+// PolicyInput returns the thresholds as the policies expect them (keyed by lower case severities)
+func (m MaxAge) PolicyInput() map[string]int {
+	result := make(map[string]int, len(m))
+	for sev, days := range m {
+		result[strings.ToLower(sev)] = days
+	}
+	return result
+}
+
+// AgeInDays returns the number of whole days that passed since the given time
+func AgeInDays(since time.Time, now time.Time) int {
+	if now.Before(since) {
+		return 0
+	}
+	return int(now.Sub(since).Hours() / 24)
+}
@@ -0,0 +1,35 @@
+package rotation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Legit-Labs/legitify/internal/common/severity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMaxAge(t *testing.T) {
+	maxAge, err := ParseMaxAge(nil)
+	require.NoError(t, err)
+	require.Equal(t, DefaultMaxAge, maxAge)
+
+	maxAge, err = ParseMaxAge(map[string]int{"Medium": 90})
+	require.NoError(t, err)
+	require.Equal(t, 90, maxAge[severity.Medium])
+	require.Equal(t, DefaultMaxAge[severity.High], maxAge[severity.High])
+	require.Equal(t, map[string]int{"high": 365, "medium": 90}, maxAge.PolicyInput())
+
+	_, err = ParseMaxAge(map[string]int{"low": 30})
+	require.Error(t, err)
+	_, err = ParseMaxAge(map[string]int{"high": 0})
+	require.Error(t, err)
+	_, err = ParseMaxAge(map[string]int{"high": 100, "medium": 200})
+	require.Error(t, err)
+}
+
+func TestAgeInDays(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	require.Equal(t, 0, AgeInDays(now.Add(-time.Hour), now))
+	require.Equal(t, 31, AgeInDays(now.AddDate(0, -1, 0), now))
+	require.Equal(t, 0, AgeInDays(now.Add(time.Hour), now))
+}
@@ -0,0 +1,126 @@
+package scheduler
+
+import (
+	"sync"
+)
+
+// DefaultWorkers is the default number of concurrently running tasks
+const DefaultWorkers = 50
+
+// Scheduler bounds the number of concurrently running tasks, both in total and per key (e.g. per organization).
+// When a worker is released, the waiting keys are served round-robin, so a key with many tasks can't starve the others.
+type Scheduler struct {
+	mutex         sync.Mutex
+	workers       int
+	workersPerKey int
+	running       int
+	runningByKey  map[string]int
+	waiting       map[string][]chan struct{}
+	// order is the round-robin order of the keys that have waiting tasks
+	order []string
+	next  int
+}
+
+// New creates a scheduler with the given number of workers. workersPerKey <= 0 means no limit per key (other than workers).
+func New(workers int, workersPerKey int) *Scheduler {
+	if workers <= 0 {
+		workers = 1
+	}
+	if workersPerKey <= 0 || workersPerKey > workers {
+		workersPerKey = workers
+	}
+
+	return &Scheduler{
+		workers:       workers,
+		workersPerKey: workersPerKey,
+		runningByKey:  make(map[string]int),
+		waiting:       make(map[string][]chan struct{}),
+	}
+}
+
+// Run waits for a worker of the key and runs f on it
+func (s *Scheduler) Run(key string, f func()) {
+	s.Acquire(key)
+	defer s.Release(key)
+	f()
+}
+
+// Acquire blocks until a worker of the key is available
+func (s *Scheduler) Acquire(key string) {
+	s.mutex.Lock()
+	if len(s.waiting[key]) == 0 && s.available(key) {
+		s.start(key)
+		s.mutex.Unlock()
+		return
+	}
+
+	ready := make(chan struct{})
+	if len(s.waiting[key]) == 0 {
+		s.order = append(s.order, key)
+	}
+	s.waiting[key] = append(s.waiting[key], ready)
+	s.mutex.Unlock()
+
+	<-ready
+}
+
+// Release frees a worker of the key and hands it over to the next waiting key
+func (s *Scheduler) Release(key string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.running--
+	s.runningByKey[key]--
+	if s.runningByKey[key] == 0 {
+		delete(s.runningByKey, key)
+	}
+
+	s.dispatch()
+}
+
+func (s *Scheduler) available(key string) bool {
+	return s.running < s.workers && s.runningByKey[key] < s.workersPerKey
+}
+
+func (s *Scheduler) start(key string) {
+	s.running++
+	s.runningByKey[key]++
+}
+
+// dispatch wakes up waiting tasks (one per key at a time) as long as there are available workers
+func (s *Scheduler) dispatch() {
+	for s.running < s.workers && len(s.order) > 0 {
+		started := false
+		for i := 0; i < len(s.order) && s.running < s.workers; i++ {
+			index := (s.next + i) % len(s.order)
+			key := s.order[index]
+			if !s.available(key) {
+				continue
+			}
+
+			ready := s.waiting[key][0]
+			s.waiting[key] = s.waiting[key][1:]
+			s.start(key)
+			close(ready)
+			started = true
+
+			if len(s.waiting[key]) == 0 {
+				delete(s.waiting, key)
+				s.order = append(s.order[:index], s.order[index+1:]...)
+				s.next = index
+			} else {
+				s.next = index + 1
+			}
+			break
+		}
+
+		if !started {
+			return
+		}
+		if len(s.order) > 0 {
+			s.next %= len(s.order)
+		} else {
+			s.next = 0
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package scheduler
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func waitingCount(s *Scheduler) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	count := 0
+	for _, waiting := range s.waiting {
+		count += len(waiting)
+	}
+	return count
+}
+
+func waitForWaiting(t *testing.T, s *Scheduler, count int) {
+	require.Eventually(t, func() bool {
+		return waitingCount(s) == count
+	}, time.Second, time.Millisecond)
+}
+
+func TestRunLimitsWorkersPerKey(t *testing.T) {
+	s := New(4, 2)
+
+	var mutex sync.Mutex
+	running := map[string]int{}
+	maxRunning := map[string]int{}
+	total, maxTotal := 0, 0
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		for _, key := range []string{"org1", "org2", "org3"} {
+			key := key
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.Run(key, func() {
+					mutex.Lock()
+					running[key]++
+					total++
+					if running[key] > maxRunning[key] {
+						maxRunning[key] = running[key]
+					}
+					if total > maxTotal {
+						maxTotal = total
+					}
+					mutex.Unlock()
+
+					time.Sleep(time.Millisecond)
+
+					mutex.Lock()
+					running[key]--
+					total--
+					mutex.Unlock()
+				})
+			}()
+		}
+	}
+	wg.Wait()
+
+	require.LessOrEqual(t, maxTotal, 4)
+	for key, max := range maxRunning {
+		require.LessOrEqual(t, max, 2, key)
+	}
+	require.Equal(t, 0, s.running)
+	require.Empty(t, s.order)
+}
+
+func TestReleaseServesKeysRoundRobin(t *testing.T) {
+	s := New(1, 0)
+	s.Acquire("big")
+
+	var mutex sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	enqueue := func(key string) {
+		waiting := waitingCount(s)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.Acquire(key)
+			mutex.Lock()
+			order = append(order, key)
+			mutex.Unlock()
+			s.Release(key)
+		}()
+		waitForWaiting(t, s, waiting+1)
+	}
+
+	for i := 0; i < 3; i++ {
+		enqueue("big")
+	}
+	enqueue("small")
+
+	s.Release("big")
+	wg.Wait()
+
+	require.Equal(t, []string{"big", "small", "big", "big"}, order)
+}
@@ -5,13 +5,15 @@ import "fmt"
 type ScmType = string
 
 const (
-	GitHub ScmType = "github"
-	GitLab ScmType = "gitlab"
+	GitHub     ScmType = "github"
+	GitLab     ScmType = "gitlab"
+	CodeCommit ScmType = "codecommit"
 )
 
 var All = []ScmType{
 	GitHub,
 	GitLab,
+	CodeCommit,
 }
 
 func Validate(scmType ScmType) error {
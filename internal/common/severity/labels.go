@@ -0,0 +1,37 @@
+package severity
+
+import (
+	"fmt"
+	"strings"
+)
+
+// labels maps the legitify severities to the display labels of the organization's own taxonomy (e.g. P1-P4).
+// Severities without a label are displayed as is.
+var labels = map[Severity]string{}
+
+// SetLabels configures the severity display labels, e.g. {"critical": "P1", "high": "P2"}.
+// The keys are case-insensitive.
+func SetLabels(mapping map[string]string) error {
+	newLabels := make(map[Severity]string, len(mapping))
+	for key, label := range mapping {
+		severity := strings.ToUpper(strings.TrimSpace(key))
+		if !IsValid(severity) {
+			return fmt.Errorf("invalid severity in severity labels: %s (valid severities: %s, %s, %s, %s)", key, Critical, High, Medium, Low)
+		}
+		if strings.TrimSpace(label) == "" {
+			return fmt.Errorf("empty label for severity %s", key)
+		}
+		newLabels[severity] = label
+	}
+
+	labels = newLabels
+	return nil
+}
+
+// Label returns the display label of the given severity.
+func Label(severity Severity) string {
+	if label, ok := labels[severity]; ok {
+		return label
+	}
+	return severity
+}
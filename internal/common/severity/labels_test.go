@@ -0,0 +1,25 @@
+package severity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabels(t *testing.T) {
+	defer func() {
+		require.NoError(t, SetLabels(nil))
+	}()
+
+	require.Equal(t, High, Label(High))
+
+	require.NoError(t, SetLabels(map[string]string{"critical": "P1", "High": "P2"}))
+	require.Equal(t, "P1", Label(Critical))
+	require.Equal(t, "P2", Label(High))
+	require.Equal(t, Medium, Label(Medium))
+
+	require.Error(t, SetLabels(map[string]string{"urgent": "P1"}))
+	require.Error(t, SetLabels(map[string]string{"low": ""}))
+	// a failed configuration keeps the previous labels
+	require.Equal(t, "P1", Label(Critical))
+}
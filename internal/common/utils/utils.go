@@ -1,7 +1,7 @@
 package utils
 
 import (
-	"log"
+	"github.com/Legit-Labs/legitify/internal/logger"
 
 	"github.com/iancoleman/orderedmap"
 )
@@ -27,13 +27,13 @@ func Retry(op func() (shouldRetry bool, err error), max_attempts int, errString
 			return nil
 		}
 		if shouldRetry {
-			log.Printf("attempt %d/%d failed: %s with err: %s\n", i, max_attempts, errString, err)
+			logger.WithError(err).Warnf("attempt %d/%d failed: %s", i, max_attempts, errString)
 		} else {
-			log.Printf("failed: %s with err: %s\n", errString, err)
+			logger.WithError(err).Errorf("failed: %s", errString)
 			return err
 		}
 	}
-	log.Printf("all %d attempts failed (%s) with err: %s", max_attempts, errString, err)
+	logger.WithError(err).Errorf("all %d attempts failed: %s", max_attempts, errString)
 
 	return err
 }
@@ -0,0 +1,306 @@
+package compliance
+
+import (
+	"fmt"
+	"sort"
+)
+
+type FrameworkName = string
+
+const (
+	CIS  FrameworkName = "cis"
+	SOC2 FrameworkName = "soc2"
+	NIST FrameworkName = "nist"
+)
+
+// Control is a single framework control and the built-in policies that provide evidence for it.
+// Policies are referenced by "<namespace>.<policy name>".
+type Control struct {
+	ID       string
+	Title    string
+	Policies []string
+}
+
+type Framework struct {
+	Name     FrameworkName
+	Title    string
+	Controls []Control
+}
+
+var frameworks = map[FrameworkName]Framework{
+	CIS: {
+		Name:  CIS,
+		Title: "CIS Software Supply Chain Security Guide (GitHub)",
+		Controls: []Control{
+			{"1.1.3", "Ensure any change to code receives approval of two strongly authenticated users", []string{
+				"repository.code_review_not_required",
+				"repository.code_review_by_two_members_not_required",
+				"repository.non_default_branch_code_review_not_required",
+				"repository.code_owners_self_approval_possible",
+				"repository.code_review_can_be_bypassed",
+			}},
+			{"1.1.4", "Ensure previous approvals are dismissed when updates are introduced to a code change proposal", []string{
+				"repository.dismisses_stale_reviews",
+				"repository.last_push_approval_not_required",
+				"repository.auto_merge_allowed_without_dismissing_stale_reviews",
+			}},
+			{"1.1.5", "Ensure there are restrictions on who can dismiss code change reviews", []string{
+				"repository.review_dismissal_allowed",
+			}},
+			{"1.1.7", "Ensure code owner's review is required when a change affects owned code", []string{
+				"repository.code_review_not_limited_to_code_owners",
+				"repository.code_owners_review_required_without_code_owners",
+				"repository.code_owners_file_has_errors",
+			}},
+			{"1.1.9", "Ensure all checks have passed before merging new code", []string{
+				"repository.requires_status_checks",
+			}},
+			{"1.1.10", "Ensure open Git branches are up to date before they can be merged into code base", []string{
+				"repository.requires_branches_up_to_date_before_merge",
+			}},
+			{"1.1.11", "Ensure all open comments are resolved before allowing code change merging", []string{
+				"repository.no_conversation_resolution",
+			}},
+			{"1.1.12", "Ensure verification of signed commits for new changes before merging", []string{
+				"repository.no_signed_commits",
+				"repository.merge_methods_dont_preserve_commit_signatures",
+			}},
+			{"1.1.13", "Ensure linear history is required", []string{
+				"repository.non_linear_history",
+			}},
+			{"1.1.15", "Ensure pushing or merging of new code is restricted to specific individuals or teams", []string{
+				"repository.missing_default_branch_protection",
+				"repository.pushes_are_not_restricted",
+				"repository.non_default_branch_not_protected",
+			}},
+			{"1.1.16", "Ensure force push code to branches is denied", []string{
+				"repository.missing_default_branch_protection_force_push",
+			}},
+			{"1.1.17", "Ensure branch deletions are denied", []string{
+				"repository.missing_default_branch_protection_deletion",
+			}},
+			{"1.2.2", "Ensure repository creation is limited to specific members", []string{
+				"organization.non_admins_can_create_public_repositories",
+			}},
+			{"1.2.5", "Ensure all copies (forks) of code are tracked and accounted for", []string{
+				"repository.allow_forking_enabled",
+			}},
+			{"1.2.7", "Ensure inactive repositories are reviewed and archived periodically", []string{
+				"repository.repository_not_maintained",
+			}},
+			{"1.3.1", "Ensure inactive users are reviewed and removed periodically", []string{
+				"member.stale_member_found",
+				"member.dormant_member_found",
+				"member.stale_admin_found",
+				"member.dormant_admin_found",
+				"member.member_not_in_identity_source",
+			}},
+			{"1.3.3", "Ensure minimum number of administrators are set for the organization", []string{
+				"member.organization_has_too_many_admins",
+				"repository.repository_has_too_many_admins",
+			}},
+			{"1.3.5", "Ensure the organization is requiring members to use MFA", []string{
+				"organization.two_factor_authentication_not_required_for_org",
+			}},
+			{"1.3.8", "Ensure strict base permissions are set for repositories", []string{
+				"organization.default_repository_permission_is_not_none",
+				"organization.custom_role_grants_admin_equivalent_permissions",
+			}},
+			{"1.4.1", "Ensure administrators approve the use of third-party actions", []string{
+				"actions.all_github_actions_are_allowed",
+				"actions.selected_actions_allow_any_third_party_action",
+				"repository.all_github_actions_are_allowed",
+			}},
+			{"1.4.3", "Ensure webhooks are secured (SSL verification and secret)", []string{
+				"organization.organization_webhook_no_secret",
+				"organization.organization_webhook_doesnt_require_ssl",
+				"repository.repository_webhook_no_secret",
+				"repository.repository_webhook_doesnt_require_ssl",
+				"organization.organization_webhook_delivers_over_http",
+				"repository.repository_webhook_delivers_over_http",
+			}},
+			{"1.5.4", "Ensure scanners are in place to identify and prevent vulnerable dependencies", []string{
+				"repository.vulnerability_alerts_not_enabled",
+				"repository.dependabot_security_updates_not_enabled",
+				"repository.dependency_graph_not_enabled",
+				"repository.ghas_dependency_review_not_enabled",
+			}},
+			{"2.3.7", "Ensure pipeline permissions follow the least privilege principle", []string{
+				"actions.token_default_permissions_is_read_write",
+				"repository.token_default_permissions_is_read_write",
+				"actions.actions_can_approve_pull_requests",
+				"repository.actions_can_approve_pull_requests",
+			}},
+			{"2.3.8", "Ensure build workers are limited to the repositories that require them", []string{
+				"actions.all_repositories_can_run_github_actions",
+				"runner_group.runner_group_can_be_used_by_public_repositories",
+				"runner_group.runner_group_not_limited_to_selected_repositories",
+				"runner_group.runner_group_persistent_runners_exposed_to_many_repositories",
+				"runner_group.runner_group_runner_uses_only_default_labels",
+			}},
+		},
+	},
+	SOC2: {
+		Name:  SOC2,
+		Title: "SOC 2 Trust Services Criteria",
+		Controls: []Control{
+			{"CC6.1", "Logical access security over protected information assets", []string{
+				"organization.two_factor_authentication_not_required_for_org",
+				"organization.organization_not_using_single_sign_on",
+				"organization.default_repository_permission_is_not_none",
+				"organization.ip_allow_list_not_enabled",
+				"organization.codespaces_created_in_high_sensitivity_organization",
+				"organization.public_package_linked_to_private_repository",
+				"repository.pages_public_for_private_repository",
+				"organization.public_project_in_private_organization",
+			}},
+			{"CC6.2", "User registration, authorization and removal", []string{
+				"member.stale_member_found",
+				"member.dormant_member_found",
+				"member.stale_admin_found",
+				"member.dormant_admin_found",
+				"member.member_not_in_identity_source",
+			}},
+			{"CC6.3", "Role-based access and least privilege", []string{
+				"member.organization_has_too_many_admins",
+				"repository.repository_has_too_many_admins",
+				"actions.token_default_permissions_is_read_write",
+				"repository.token_default_permissions_is_read_write",
+				"runner_group.runner_group_not_limited_to_selected_repositories",
+				"organization.app_has_write_access_to_all_repositories",
+				"organization.oauth_app_access_not_restricted",
+			}},
+			{"CC6.7", "Restriction of the transmission and movement of information", []string{
+				"organization.organization_webhook_no_secret",
+				"organization.organization_webhook_doesnt_require_ssl",
+				"repository.repository_webhook_no_secret",
+				"repository.repository_webhook_doesnt_require_ssl",
+				"organization.non_admins_can_create_public_repositories",
+				"repository.allow_forking_enabled",
+			}},
+			{"CC7.1", "Detection and monitoring of vulnerabilities", []string{
+				"repository.vulnerability_alerts_not_enabled",
+				"repository.dependabot_security_updates_not_enabled",
+				"repository.dependency_graph_not_enabled",
+				"repository.ghas_dependency_review_not_enabled",
+				"repository.scorecard_score_too_low",
+				"organization.no_security_managers",
+			}},
+			{"CC8.1", "Change management: changes are authorized, tested and approved", []string{
+				"repository.missing_default_branch_protection",
+				"repository.missing_default_branch_protection_force_push",
+				"repository.missing_default_branch_protection_deletion",
+				"repository.code_review_not_required",
+				"repository.code_review_by_two_members_not_required",
+				"repository.dismisses_stale_reviews",
+				"repository.last_push_approval_not_required",
+				"repository.requires_status_checks",
+				"repository.review_dismissal_allowed",
+				"repository.pushes_are_not_restricted",
+				"repository.non_default_branch_not_protected",
+				"repository.non_default_branch_code_review_not_required",
+				"actions.actions_can_approve_pull_requests",
+				"repository.actions_can_approve_pull_requests",
+			}},
+		},
+	},
+	NIST: {
+		Name:  NIST,
+		Title: "NIST SP 800-53 Rev. 5",
+		Controls: []Control{
+			{"AC-2", "Account Management", []string{
+				"member.stale_member_found",
+				"member.dormant_member_found",
+				"member.stale_admin_found",
+				"member.dormant_admin_found",
+				"member.member_not_in_identity_source",
+			}},
+			{"AC-6", "Least Privilege", []string{
+				"member.organization_has_too_many_admins",
+				"repository.repository_has_too_many_admins",
+				"organization.default_repository_permission_is_not_none",
+				"actions.token_default_permissions_is_read_write",
+				"repository.token_default_permissions_is_read_write",
+				"organization.app_has_write_access_to_all_repositories",
+				"organization.security_manager_team_has_outside_collaborators",
+				"repository.outside_collaborator_has_elevated_permissions",
+				"organization.codespaces_secret_available_to_all_repositories",
+			}},
+			{"IA-2", "Identification and Authentication (Organizational Users)", []string{
+				"organization.two_factor_authentication_not_required_for_org",
+				"organization.organization_not_using_single_sign_on",
+				"organization.members_without_linked_sso_identity",
+			}},
+			{"CM-3", "Configuration Change Control", []string{
+				"repository.missing_default_branch_protection",
+				"repository.non_default_branch_not_protected",
+				"repository.code_review_not_required",
+				"repository.non_default_branch_code_review_not_required",
+				"repository.code_review_by_two_members_not_required",
+				"repository.dismisses_stale_reviews",
+				"repository.last_push_approval_not_required",
+				"repository.requires_status_checks",
+				"actions.actions_can_approve_pull_requests",
+				"repository.actions_can_approve_pull_requests",
+			}},
+			{"CM-5", "Access Restrictions for Change", []string{
+				"repository.pushes_are_not_restricted",
+				"repository.review_dismissal_allowed",
+				"repository.code_review_can_be_bypassed",
+				"repository.missing_default_branch_protection_force_push",
+				"repository.missing_default_branch_protection_deletion",
+			}},
+			{"SC-8", "Transmission Confidentiality and Integrity", []string{
+				"organization.organization_webhook_doesnt_require_ssl",
+				"repository.repository_webhook_doesnt_require_ssl",
+				"organization.organization_webhook_no_secret",
+				"repository.repository_webhook_no_secret",
+				"organization.organization_webhook_delivers_over_http",
+				"repository.repository_webhook_delivers_over_http",
+			}},
+			{"SI-7", "Software, Firmware, and Information Integrity", []string{
+				"repository.no_signed_commits",
+			}},
+			{"RA-5", "Vulnerability Monitoring and Scanning", []string{
+				"repository.vulnerability_alerts_not_enabled",
+				"repository.dependabot_security_updates_not_enabled",
+				"repository.dependency_graph_not_enabled",
+				"repository.ghas_dependency_review_not_enabled",
+			}},
+			{"SR-3", "Supply Chain Controls and Processes", []string{
+				"actions.all_github_actions_are_allowed",
+				"actions.selected_actions_allow_any_third_party_action",
+				"actions.third_party_actions_not_pinned",
+				"repository.unpinned_third_party_actions",
+				"repository.pull_request_target_workflow_is_dangerous",
+				"actions.all_repositories_can_run_github_actions",
+				"runner_group.runner_group_can_be_used_by_public_repositories",
+				"repository.scorecard_score_too_low",
+			}},
+		},
+	},
+}
+
+func GetFramework(name FrameworkName) (Framework, error) {
+	framework, ok := frameworks[name]
+	if !ok {
+		return Framework{}, fmt.Errorf("unsupported compliance framework: %s", name)
+	}
+
+	return framework, nil
+}
+
+func ValidateFramework(name FrameworkName) error {
+	_, err := GetFramework(name)
+	return err
+}
+
+func FrameworkNames() []FrameworkName {
+	names := []FrameworkName{}
+	for name := range frameworks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,146 @@
+package compliance
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Legit-Labs/legitify/internal/analyzers"
+	"github.com/Legit-Labs/legitify/internal/enricher/enrichers"
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme"
+)
+
+type ControlStatus = string
+
+const (
+	ControlPassed       ControlStatus = "PASSED"
+	ControlFailed       ControlStatus = "FAILED"
+	ControlNotEvaluated ControlStatus = "NOT_EVALUATED"
+)
+
+type PolicyResult struct {
+	PolicyName string `json:"policyName"`
+	Title      string `json:"title"`
+	Severity   string `json:"severity"`
+	Passed     int    `json:"passed"`
+	Failed     int    `json:"failed"`
+	Skipped    int    `json:"skipped"`
+	Waived     int    `json:"waived"`
+	// FailedEntities are the canonical links of the entities that violate the policy (audit evidence)
+	FailedEntities []string `json:"failedEntities"`
+	// WaivedEntities are the canonical links of the entities whose violation was accepted, and the justification
+	WaivedEntities []string `json:"waivedEntities,omitempty"`
+}
+
+type ControlResult struct {
+	ID       string         `json:"id"`
+	Title    string         `json:"title"`
+	Status   ControlStatus  `json:"status"`
+	Policies []PolicyResult `json:"policies"`
+}
+
+// Report groups the analysis results by the controls of a compliance framework.
+// Score is the percentage of evaluated controls that passed.
+type Report struct {
+	Framework    FrameworkName   `json:"framework"`
+	Title        string          `json:"title"`
+	Score        float64         `json:"score"`
+	Passed       int             `json:"passedControls"`
+	Failed       int             `json:"failedControls"`
+	NotEvaluated int             `json:"notEvaluatedControls"`
+	Controls     []ControlResult `json:"controls"`
+}
+
+func policyKey(fullyQualifiedPolicyName string) string {
+	return strings.TrimPrefix(fullyQualifiedPolicyName, "data.")
+}
+
+func NewReport(name FrameworkName, output scheme.FlattenedScheme) (*Report, error) {
+	framework, err := GetFramework(name)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]PolicyResult)
+	for _, policyName := range output.Keys() {
+		data := output.GetPolicyData(policyName)
+		results[policyKey(data.PolicyInfo.FullyQualifiedPolicyName)] = newPolicyResult(data)
+	}
+
+	report := &Report{
+		Framework: framework.Name,
+		Title:     framework.Title,
+		Controls:  []ControlResult{},
+	}
+
+	for _, control := range framework.Controls {
+		controlResult := ControlResult{
+			ID:       control.ID,
+			Title:    control.Title,
+			Status:   ControlNotEvaluated,
+			Policies: []PolicyResult{},
+		}
+
+		for _, policy := range control.Policies {
+			result, ok := results[policy]
+			if !ok {
+				continue
+			}
+			controlResult.Policies = append(controlResult.Policies, result)
+
+			if result.Failed > 0 {
+				controlResult.Status = ControlFailed
+			} else if (result.Passed > 0 || result.Waived > 0) && controlResult.Status == ControlNotEvaluated {
+				controlResult.Status = ControlPassed
+			}
+		}
+
+		switch controlResult.Status {
+		case ControlPassed:
+			report.Passed++
+		case ControlFailed:
+			report.Failed++
+		default:
+			report.NotEvaluated++
+		}
+		report.Controls = append(report.Controls, controlResult)
+	}
+
+	if evaluated := report.Passed + report.Failed; evaluated > 0 {
+		report.Score = float64(report.Passed) * 100 / float64(evaluated)
+	}
+
+	return report, nil
+}
+
+func newPolicyResult(data scheme.OutputData) PolicyResult {
+	result := PolicyResult{
+		PolicyName:     data.PolicyInfo.FullyQualifiedPolicyName,
+		Title:          data.PolicyInfo.Title,
+		Severity:       data.PolicyInfo.Severity,
+		FailedEntities: []string{},
+	}
+
+	for _, violation := range data.Violations {
+		switch violation.Status {
+		case analyzers.PolicyPassed:
+			result.Passed++
+		case analyzers.PolicyFailed:
+			result.Failed++
+			result.FailedEntities = append(result.FailedEntities, violation.CanonicalLink)
+		case analyzers.PolicySkipped:
+			result.Skipped++
+		case analyzers.PolicyWaived:
+			result.Waived++
+			result.WaivedEntities = append(result.WaivedEntities, waivedEntity(violation))
+		}
+	}
+
+	return result
+}
+
+func waivedEntity(violation scheme.Violation) string {
+	if waiver, ok := violation.Aux[enrichers.Waiver].(*enrichers.WaiverEnrichment); ok {
+		return fmt.Sprintf("%s (waived: %s)", violation.CanonicalLink, waiver.Justification())
+	}
+	return violation.CanonicalLink
+}
@@ -0,0 +1,81 @@
+package compliance_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Legit-Labs/legitify/internal/analyzers"
+	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/Legit-Labs/legitify/internal/common/scm_type"
+	"github.com/Legit-Labs/legitify/internal/common/severity"
+	"github.com/Legit-Labs/legitify/internal/compliance"
+	"github.com/Legit-Labs/legitify/internal/opa"
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme"
+	"github.com/stretchr/testify/require"
+)
+
+func outputData(policyName string, statuses ...analyzers.PolicyStatus) scheme.OutputData {
+	data := scheme.NewOutputData(scheme.PolicyInfo{
+		Title:                    policyName,
+		PolicyName:               policyName,
+		FullyQualifiedPolicyName: "data.repository." + policyName,
+		Severity:                 severity.High,
+		Namespace:                namespace.Repository,
+	})
+	for i, status := range statuses {
+		data = scheme.AppendViolations(data, scheme.Violation{
+			CanonicalLink: policyName + string(rune('a'+i)),
+			Status:        status,
+		})
+	}
+
+	return data
+}
+
+func TestComplianceReport(t *testing.T) {
+	output := scheme.NewFlattenedScheme()
+	output.Set("data.repository.no_signed_commits", outputData("no_signed_commits", analyzers.PolicyPassed, analyzers.PolicyFailed))
+	output.Set("data.repository.non_linear_history", outputData("non_linear_history", analyzers.PolicyPassed))
+
+	report, err := compliance.NewReport(compliance.CIS, output)
+	require.Nilf(t, err, "Error creating report: %v", err)
+
+	statuses := make(map[string]compliance.ControlStatus)
+	for _, control := range report.Controls {
+		statuses[control.ID] = control.Status
+	}
+	require.Equal(t, compliance.ControlFailed, statuses["1.1.12"])
+	require.Equal(t, compliance.ControlPassed, statuses["1.1.13"])
+	require.Equal(t, compliance.ControlNotEvaluated, statuses["1.3.5"])
+
+	require.Equal(t, 1, report.Passed)
+	require.Equal(t, 1, report.Failed)
+	require.Equal(t, len(report.Controls)-2, report.NotEvaluated)
+	require.Equal(t, float64(50), report.Score)
+}
+
+func TestComplianceUnknownFramework(t *testing.T) {
+	_, err := compliance.NewReport("unknown", scheme.NewFlattenedScheme())
+	require.NotNil(t, err)
+}
+
+// make sure the mapping doesn't reference policies that were renamed or removed
+func TestComplianceMappedPoliciesExist(t *testing.T) {
+	engine, err := opa.Load([]string{}, scm_type.GitHub)
+	require.Nilf(t, err, "Error loading policies: %v", err)
+
+	existing := make(map[string]bool)
+	for _, annotation := range engine.Annotations().Flatten() {
+		existing[strings.TrimPrefix(annotation.Path.String(), "data.")] = true
+	}
+
+	for _, name := range compliance.FrameworkNames() {
+		framework, err := compliance.GetFramework(name)
+		require.Nil(t, err)
+		for _, control := range framework.Controls {
+			for _, policy := range control.Policies {
+				require.Truef(t, existing[policy], "%s control %s references unknown policy %s", name, control.ID, policy)
+			}
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package confidence
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PolicyRule is the reserved rule a policy package uses to lower the confidence of its results, e.g.:
+//
+//	policy_confidence["code_review_can_be_bypassed"] = {"level": "low", "reason": "partial data: ..."} {
+//		input.collaborators == null
+//	}
+//
+// It isn't a policy by itself.
+const PolicyRule = "policy_confidence"
+
+type Level = string
+
+const (
+	High   Level = "high"
+	Medium Level = "medium"
+	Low    Level = "low"
+)
+
+var levels = map[Level]int{
+	Low:    0,
+	Medium: 1,
+	High:   2,
+}
+
+// Confidence qualifies a policy result that is based on partially collected data.
+// Results without a confidence are of high confidence.
+type Confidence struct {
+	Level  Level  `json:"level"`
+	Reason string `json:"reason,omitempty"`
+}
+
+func (c Confidence) String() string {
+	if c.Reason == "" {
+		return fmt.Sprintf("%s confidence", c.Level)
+	}
+	return fmt.Sprintf("%s confidence: %s", c.Level, c.Reason)
+}
+
+func IsValid(level string) bool {
+	_, ok := levels[strings.ToLower(level)]
+	return ok
+}
+
+func Levels() []Level {
+	return []Level{High, Medium, Low}
+}
+
+// Parse converts the value of a policy_confidence entry (an object with a level and an optional reason)
+func Parse(value interface{}) (*Confidence, error) {
+	raw, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid confidence type %T (expected an object with a level and a reason)", value)
+	}
+
+	level, _ := raw["level"].(string)
+	if !IsValid(level) {
+		return nil, fmt.Errorf("invalid confidence level \"%v\" (expected one of %v)", raw["level"], Levels())
+	}
+	reason, _ := raw["reason"].(string)
+
+	return &Confidence{Level: strings.ToLower(level), Reason: reason}, nil
+}
+
+// Meets returns whether the confidence (nil for high) is at least the minimal level
+func Meets(c *Confidence, minimal Level) bool {
+	if c == nil {
+		return true
+	}
+	return levels[c.Level] >= levels[strings.ToLower(minimal)]
+}
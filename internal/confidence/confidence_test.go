@@ -0,0 +1,35 @@
+package confidence
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	c, err := Parse(map[string]interface{}{"level": "LOW", "reason": "partial data"})
+	require.NoError(t, err)
+	require.Equal(t, Confidence{Level: Low, Reason: "partial data"}, *c)
+	require.Equal(t, "low confidence: partial data", c.String())
+
+	c, err = Parse(map[string]interface{}{"level": "medium"})
+	require.NoError(t, err)
+	require.Equal(t, "medium confidence", c.String())
+
+	_, err = Parse(map[string]interface{}{"level": "somewhat"})
+	require.Error(t, err)
+
+	_, err = Parse("low")
+	require.Error(t, err)
+}
+
+func TestMeets(t *testing.T) {
+	low := &Confidence{Level: Low}
+	medium := &Confidence{Level: Medium}
+
+	require.True(t, Meets(nil, High))
+	require.True(t, Meets(low, Low))
+	require.False(t, Meets(low, Medium))
+	require.True(t, Meets(medium, Medium))
+	require.False(t, Meets(medium, High))
+}
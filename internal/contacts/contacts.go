@@ -0,0 +1,96 @@
+package contacts
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Contact is who to call about the findings of an organization or a repository
+type Contact struct {
+	Name  string `yaml:"name" json:"name,omitempty"`
+	Email string `yaml:"email" json:"email,omitempty"`
+	// Escalation is the escalation channel (e.g. a Slack channel or an on-call rotation)
+	Escalation string `yaml:"escalation" json:"escalation,omitempty"`
+}
+
+func (c Contact) String() string {
+	var parts []string
+	switch {
+	case c.Name != "" && c.Email != "":
+		parts = append(parts, fmt.Sprintf("%s <%s>", c.Name, c.Email))
+	case c.Name != "":
+		parts = append(parts, c.Name)
+	case c.Email != "":
+		parts = append(parts, c.Email)
+	}
+	if c.Escalation != "" {
+		parts = append(parts, "escalation: "+c.Escalation)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (c Contact) empty() bool {
+	return c.Name == "" && c.Email == "" && c.Escalation == ""
+}
+
+// Directory maps organizations and repositories (owner/name) to their security contacts.
+// A repository contact takes precedence over the contact of its organization.
+type Directory struct {
+	Organizations map[string]Contact `yaml:"organizations"`
+	Repositories  map[string]Contact `yaml:"repositories"`
+}
+
+// Load reads a security contacts yaml file, e.g.:
+//
+//	organizations:
+//	  org1: {name: AppSec, email: appsec@example.com, escalation: "#appsec-oncall"}
+//	repositories:
+//	  org1/payments: {name: Payments Security, email: payments-sec@example.com}
+func Load(path string) (*Directory, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file Directory
+	if err = yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse the security contacts file %s: %v", path, err)
+	}
+
+	directory := &Directory{
+		Organizations: make(map[string]Contact),
+		Repositories:  make(map[string]Contact),
+	}
+	for org, contact := range file.Organizations {
+		if contact.empty() {
+			return nil, fmt.Errorf("empty security contact for organization %s", org)
+		}
+		directory.Organizations[strings.ToLower(org)] = contact
+	}
+	for repo, contact := range file.Repositories {
+		if !strings.Contains(repo, "/") {
+			return nil, fmt.Errorf("invalid repository %s in the security contacts file, expected owner/name", repo)
+		}
+		if contact.empty() {
+			return nil, fmt.Errorf("empty security contact for repository %s", repo)
+		}
+		directory.Repositories[strings.ToLower(repo)] = contact
+	}
+
+	return directory, nil
+}
+
+// Lookup returns the contact of the repository (if given), or of its organization
+func (d *Directory) Lookup(org string, repo string) (Contact, bool) {
+	if repo != "" {
+		if contact, ok := d.Repositories[strings.ToLower(org+"/"+repo)]; ok {
+			return contact, true
+		}
+	}
+
+	contact, ok := d.Organizations[strings.ToLower(org)]
+	return contact, ok
+}
@@ -0,0 +1,51 @@
+package contacts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "contacts.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestLoadAndLookup(t *testing.T) {
+	directory, err := Load(writeFile(t, `
+organizations:
+  Org1: {name: AppSec, email: appsec@example.com, escalation: "#appsec-oncall"}
+repositories:
+  org1/payments: {name: Payments Security, email: payments-sec@example.com}
+`))
+	require.NoError(t, err)
+
+	contact, ok := directory.Lookup("org1", "payments")
+	require.True(t, ok)
+	require.Equal(t, "Payments Security <payments-sec@example.com>", contact.String())
+
+	contact, ok = directory.Lookup("ORG1", "other")
+	require.True(t, ok)
+	require.Equal(t, "AppSec <appsec@example.com>, escalation: #appsec-oncall", contact.String())
+
+	contact, ok = directory.Lookup("org1", "")
+	require.True(t, ok)
+	require.Equal(t, "AppSec", contact.Name)
+
+	_, ok = directory.Lookup("org2", "payments")
+	require.False(t, ok)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	_, err := Load(writeFile(t, "repositories:\n  payments: {name: Payments}\n"))
+	require.Error(t, err)
+
+	_, err = Load(writeFile(t, "organizations:\n  org1: {}\n"))
+	require.Error(t, err)
+
+	_, err = Load(writeFile(t, "organizations: [org1]\n"))
+	require.Error(t, err)
+}
@@ -2,19 +2,47 @@ package context_utils
 
 import (
 	"context"
+	"strings"
+
+	"github.com/Legit-Labs/legitify/internal/activity"
 	"github.com/Legit-Labs/legitify/internal/common/types"
+	"github.com/Legit-Labs/legitify/internal/confidence"
+	"github.com/Legit-Labs/legitify/internal/contacts"
+	"github.com/Legit-Labs/legitify/internal/identity"
 
 	"github.com/Legit-Labs/legitify/internal/common/permissions"
+	"github.com/Legit-Labs/legitify/internal/common/rotation"
+	"github.com/Legit-Labs/legitify/internal/common/scheduler"
+	"github.com/Legit-Labs/legitify/internal/workflows"
 )
 
 type contextKey string
 
 const (
-	organizationKey     contextKey = "org"
-	repositoryKey       contextKey = "repo"
-	tokenScopesKey      contextKey = "tokenScopes"
-	scorecardEnabledKey contextKey = "scorecardEnabled"
-	scorecardVerboseKey contextKey = "scorecardVerbose"
+	organizationKey      contextKey = "org"
+	repositoryKey        contextKey = "repo"
+	tokenScopesKey       contextKey = "tokenScopes"
+	scorecardEnabledKey  contextKey = "scorecardEnabled"
+	scorecardVerboseKey  contextKey = "scorecardVerbose"
+	policyTagsKey        contextKey = "policyTags"
+	excludedTagsKey      contextKey = "excludedPolicyTags"
+	identitySourceKey    contextKey = "identitySource"
+	skippedPoliciesKey   contextKey = "skippedPolicies"
+	samplePercentKey     contextKey = "samplePercent"
+	scanIDKey            contextKey = "scanId"
+	minConfidenceKey     contextKey = "minConfidence"
+	secretMaxAgeKey      contextKey = "secretMaxAge"
+	trustedPublishersKey contextKey = "trustedActionPublishers"
+	suspiciousFilesKey   contextKey = "suspiciousFiles"
+	concurrencyKey       contextKey = "concurrency"
+	orgConcurrencyKey    contextKey = "orgConcurrency"
+	protectedBranchesKey contextKey = "protectedBranches"
+	securityContactsKey  contextKey = "securityContacts"
+	webhookDomainsKey    contextKey = "webhookDomains"
+	sensitiveOrgsKey     contextKey = "highSensitivityOrgs"
+	activityDaysKey      contextKey = "auditLogActivityDays"
+	dormantAdminDaysKey  contextKey = "dormantAdminDays"
+	unsupportedKey       contextKey = "unsupportedFeatures"
 )
 
 func NewContextWithRepos(repos []types.RepositoryWithOwner) context.Context {
@@ -35,6 +63,77 @@ func NewContextWithTokenScopes(ctx context.Context, tokenScopes permissions.Toke
 	return context.WithValue(ctx, tokenScopesKey, tokenScopes)
 }
 
+func NewContextWithPolicyTags(ctx context.Context, tags []string, excludedTags []string) context.Context {
+	c := context.WithValue(ctx, policyTagsKey, tags)
+	return context.WithValue(c, excludedTagsKey, excludedTags)
+}
+
+// NewContextWithUnsupportedFeatures sets the features the server version doesn't have (e.g. on older GitHub Enterprise Server versions)
+func NewContextWithUnsupportedFeatures(ctx context.Context, features []string) context.Context {
+	return context.WithValue(ctx, unsupportedKey, features)
+}
+
+func NewContextWithSkippedPolicies(ctx context.Context, policies []string) context.Context {
+	return context.WithValue(ctx, skippedPoliciesKey, policies)
+}
+
+func NewContextWithSample(ctx context.Context, percent int) context.Context {
+	return context.WithValue(ctx, samplePercentKey, percent)
+}
+
+func NewContextWithScanID(ctx context.Context, scanID string) context.Context {
+	return context.WithValue(ctx, scanIDKey, scanID)
+}
+
+func NewContextWithMinConfidence(ctx context.Context, level confidence.Level) context.Context {
+	return context.WithValue(ctx, minConfidenceKey, strings.ToLower(level))
+}
+
+func NewContextWithSecretMaxAge(ctx context.Context, maxAge rotation.MaxAge) context.Context {
+	return context.WithValue(ctx, secretMaxAgeKey, maxAge)
+}
+
+func NewContextWithTrustedActionPublishers(ctx context.Context, publishers []string) context.Context {
+	return context.WithValue(ctx, trustedPublishersKey, publishers)
+}
+
+func NewContextWithSuspiciousFiles(ctx context.Context, enabled bool) context.Context {
+	return context.WithValue(ctx, suspiciousFilesKey, enabled)
+}
+
+func NewContextWithConcurrency(ctx context.Context, workers int, workersPerOrg int) context.Context {
+	c := context.WithValue(ctx, concurrencyKey, workers)
+	return context.WithValue(c, orgConcurrencyKey, workersPerOrg)
+}
+
+func NewContextWithProtectedBranches(ctx context.Context, patterns []string) context.Context {
+	return context.WithValue(ctx, protectedBranchesKey, patterns)
+}
+
+func NewContextWithWebhookDomains(ctx context.Context, domains []string) context.Context {
+	return context.WithValue(ctx, webhookDomainsKey, domains)
+}
+
+func NewContextWithHighSensitivityOrgs(ctx context.Context, orgs []string) context.Context {
+	return context.WithValue(ctx, sensitiveOrgsKey, orgs)
+}
+
+func NewContextWithAuditLogActivityDays(ctx context.Context, days int) context.Context {
+	return context.WithValue(ctx, activityDaysKey, days)
+}
+
+func NewContextWithDormantAdminDays(ctx context.Context, days int) context.Context {
+	return context.WithValue(ctx, dormantAdminDaysKey, days)
+}
+
+func NewContextWithSecurityContacts(ctx context.Context, directory *contacts.Directory) context.Context {
+	return context.WithValue(ctx, securityContactsKey, directory)
+}
+
+func NewContextWithIdentitySource(ctx context.Context, source *identity.Source) context.Context {
+	return context.WithValue(ctx, identitySourceKey, source)
+}
+
 func GetTokenScopes(ctx context.Context) permissions.TokenScopes {
 	return ctx.Value(tokenScopesKey).(permissions.TokenScopes)
 }
@@ -53,3 +152,125 @@ func GetRepositories(ctx context.Context) ([]types.RepositoryWithOwner, bool) {
 	val, ok := ctx.Value(repositoryKey).([]types.RepositoryWithOwner)
 	return val, ok
 }
+
+func GetPolicyTags(ctx context.Context) []string {
+	val, _ := ctx.Value(policyTagsKey).([]string)
+	return val
+}
+
+func GetExcludedPolicyTags(ctx context.Context) []string {
+	val, _ := ctx.Value(excludedTagsKey).([]string)
+	return val
+}
+
+func GetSkippedPolicies(ctx context.Context) []string {
+	val, _ := ctx.Value(skippedPoliciesKey).([]string)
+	return val
+}
+
+// IsFeatureSupported checks whether the server version has the feature (all the features are, unless stated otherwise)
+func IsFeatureSupported(ctx context.Context, feature string) bool {
+	val, _ := ctx.Value(unsupportedKey).([]string)
+	for _, unsupported := range val {
+		if unsupported == feature {
+			return false
+		}
+	}
+	return true
+}
+
+func GetSuspiciousFilesEnabled(ctx context.Context) bool {
+	val, ok := ctx.Value(suspiciousFilesKey).(bool)
+	return ok && val
+}
+
+// GetProtectedBranches returns the name patterns of the non-default branches whose protection is collected
+func GetProtectedBranches(ctx context.Context) []string {
+	val, _ := ctx.Value(protectedBranchesKey).([]string)
+	return val
+}
+
+// GetWebhookDomains returns the domains the webhooks may deliver to (none if it isn't configured)
+func GetWebhookDomains(ctx context.Context) []string {
+	val, _ := ctx.Value(webhookDomainsKey).([]string)
+	return val
+}
+
+// GetHighSensitivityOrgs returns the organizations that were classified as high-sensitivity (none if it isn't configured)
+func GetHighSensitivityOrgs(ctx context.Context) []string {
+	val, _ := ctx.Value(sensitiveOrgsKey).([]string)
+	return val
+}
+
+// GetAuditLogActivityDays returns the number of days of the members' audit log activity to collect (0 if it isn't requested)
+func GetAuditLogActivityDays(ctx context.Context) int {
+	val, _ := ctx.Value(activityDaysKey).(int)
+	return val
+}
+
+// GetDormantAdminDays returns the number of days without activity after which an admin is considered dormant
+func GetDormantAdminDays(ctx context.Context) int {
+	val, ok := ctx.Value(dormantAdminDaysKey).(int)
+	if !ok || val <= 0 {
+		return activity.DefaultDormantAdminDays
+	}
+	return val
+}
+
+func GetSecurityContacts(ctx context.Context) (*contacts.Directory, bool) {
+	val, ok := ctx.Value(securityContactsKey).(*contacts.Directory)
+	return val, ok && val != nil
+}
+
+func GetIdentitySource(ctx context.Context) (*identity.Source, bool) {
+	val, ok := ctx.Value(identitySourceKey).(*identity.Source)
+	return val, ok && val != nil
+}
+
+// GetSamplePercent returns the percentage of the repositories a sampled scan covers
+func GetSamplePercent(ctx context.Context) (int, bool) {
+	val, ok := ctx.Value(samplePercentKey).(int)
+	return val, ok && val > 0 && val < 100
+}
+
+func GetScanID(ctx context.Context) string {
+	val, _ := ctx.Value(scanIDKey).(string)
+	return val
+}
+
+// GetMinConfidence returns the minimal confidence of the reported results (low, i.e. all of them, if it isn't configured)
+func GetMinConfidence(ctx context.Context) confidence.Level {
+	val, ok := ctx.Value(minConfidenceKey).(confidence.Level)
+	if !ok || val == "" {
+		return confidence.Low
+	}
+	return val
+}
+
+// GetSecretMaxAge returns the maximal age of the secrets per severity (the defaults if it isn't configured)
+func GetSecretMaxAge(ctx context.Context) rotation.MaxAge {
+	val, ok := ctx.Value(secretMaxAgeKey).(rotation.MaxAge)
+	if !ok || val == nil {
+		return rotation.DefaultMaxAge
+	}
+	return val
+}
+
+// GetTrustedActionPublishers returns the owners of the actions that don't have to be pinned (the defaults if it isn't configured)
+func GetTrustedActionPublishers(ctx context.Context) []string {
+	val, ok := ctx.Value(trustedPublishersKey).([]string)
+	if !ok {
+		return workflows.DefaultTrustedPublishers
+	}
+	return val
+}
+
+// GetConcurrency returns the number of concurrent collection workers, in total and per organization (0 for no per organization limit)
+func GetConcurrency(ctx context.Context) (int, int) {
+	workers, ok := ctx.Value(concurrencyKey).(int)
+	if !ok || workers <= 0 {
+		workers = scheduler.DefaultWorkers
+	}
+	workersPerOrg, _ := ctx.Value(orgConcurrencyKey).(int)
+	return workers, workersPerOrg
+}
@@ -0,0 +1,56 @@
+package dependabot
+
+import (
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Paths are the possible paths of the Dependabot version updates configuration file
+var Paths = []string{".github/dependabot.yml", ".github/dependabot.yaml"}
+
+// GitHubActions is the package ecosystem of the actions used by the repository workflows
+const GitHubActions = "github-actions"
+
+// Config is the Dependabot version updates configuration of a repository
+type Config struct {
+	Exists bool   `json:"exists"`
+	Path   string `json:"path,omitempty"`
+	// Ecosystems are the (sorted and unique) package ecosystems that Dependabot updates
+	Ecosystems []string `json:"ecosystems"`
+}
+
+type rawConfig struct {
+	Updates []struct {
+		PackageEcosystem string `yaml:"package-ecosystem"`
+	} `yaml:"updates"`
+}
+
+// Missing is the configuration of a repository without a Dependabot configuration file
+func Missing() *Config {
+	return &Config{Exists: false, Ecosystems: []string{}}
+}
+
+// Parse parses the content of a Dependabot configuration file
+func Parse(path string, content []byte) (*Config, error) {
+	var raw rawConfig
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+
+	unique := make(map[string]bool)
+	for _, update := range raw.Updates {
+		if update.PackageEcosystem != "" {
+			unique[update.PackageEcosystem] = true
+		}
+	}
+
+	config := &Config{Exists: true, Path: path, Ecosystems: []string{}}
+	for ecosystem := range unique {
+		config.Ecosystems = append(config.Ecosystems, ecosystem)
+	}
+	sort.Strings(config.Ecosystems)
+
+	return config, nil
+}
@@ -0,0 +1,35 @@
+package dependabot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	content := `
+version: 2
+updates:
+  - package-ecosystem: gomod
+    directory: /
+    schedule:
+      interval: weekly
+  - package-ecosystem: github-actions
+    directory: /
+    schedule:
+      interval: weekly
+  - package-ecosystem: gomod
+    directory: /tools
+    schedule:
+      interval: weekly
+`
+	config, err := Parse(Paths[0], []byte(content))
+	require.Nil(t, err)
+	require.True(t, config.Exists)
+	require.Equal(t, []string{GitHubActions, "gomod"}, config.Ecosystems)
+
+	_, err = Parse(Paths[0], []byte("updates: {\n"))
+	require.NotNil(t, err)
+
+	require.False(t, Missing().Exists)
+}
@@ -0,0 +1,103 @@
+package doctor
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+type Status = string
+
+const (
+	StatusOK      Status = "OK"
+	StatusWarning Status = "WARN"
+	StatusFailed  Status = "FAIL"
+)
+
+// Check is the result of a single preflight check
+type Check struct {
+	Name    string
+	Status  Status
+	Message string
+}
+
+// Report collects the results of the preflight checks, and the policies that will be degraded per organization
+type Report struct {
+	Checks   []Check
+	Degraded map[string][]DegradedPolicy
+	// degradedOrder keeps the organizations in the order they were added
+	degradedOrder []string
+}
+
+func NewReport() *Report {
+	return &Report{
+		Degraded: make(map[string][]DegradedPolicy),
+	}
+}
+
+func (r *Report) Add(name string, status Status, format string, args ...interface{}) {
+	r.Checks = append(r.Checks, Check{
+		Name:    name,
+		Status:  status,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (r *Report) AddDegraded(entity string, degraded []DegradedPolicy) {
+	if _, ok := r.Degraded[entity]; !ok {
+		r.degradedOrder = append(r.degradedOrder, entity)
+	}
+	r.Degraded[entity] = append(r.Degraded[entity], degraded...)
+}
+
+// Failed reports whether any check failed (warnings only degrade the analysis)
+func (r *Report) Failed() bool {
+	for _, check := range r.Checks {
+		if check.Status == StatusFailed {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Report) Print(w io.Writer) {
+	width := 0
+	for _, check := range r.Checks {
+		if len(check.Name) > width {
+			width = len(check.Name)
+		}
+	}
+
+	for _, check := range r.Checks {
+		fmt.Fprintf(w, "[%-4s] %-*s  %s\n", check.Status, width, check.Name, check.Message)
+	}
+
+	if len(r.degradedOrder) == 0 {
+		return
+	}
+	fmt.Fprintln(w)
+	for _, entity := range r.degradedOrder {
+		degraded := r.Degraded[entity]
+		if len(degraded) == 0 {
+			fmt.Fprintf(w, "%s: all the policies can be evaluated\n", entity)
+			continue
+		}
+		fmt.Fprintf(w, "%s: %d policies will be skipped\n", entity, len(degraded))
+		for _, policy := range degraded {
+			fmt.Fprintf(w, "  - %s.%s (%s)\n", policy.Namespace, policy.Name, policy.Reason)
+		}
+	}
+}
+
+// Summary is the one line result of the report
+func (r *Report) Summary() string {
+	counts := make(map[Status]int)
+	for _, check := range r.Checks {
+		counts[check.Status]++
+	}
+	var parts []string
+	for _, status := range []Status{StatusOK, StatusWarning, StatusFailed} {
+		parts = append(parts, fmt.Sprintf("%d %s", counts[status], strings.ToLower(status)))
+	}
+	return strings.Join(parts, ", ")
+}
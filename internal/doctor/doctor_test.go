@@ -0,0 +1,112 @@
+package doctor
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Legit-Labs/legitify/internal/collectors"
+	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/Legit-Labs/legitify/internal/common/permissions"
+	"github.com/Legit-Labs/legitify/internal/common/scm_type"
+	"github.com/Legit-Labs/legitify/internal/opa"
+	"github.com/stretchr/testify/require"
+)
+
+func findDegraded(degraded []DegradedPolicy, name string) *DegradedPolicy {
+	for i := range degraded {
+		if degraded[i].Name == name {
+			return &degraded[i]
+		}
+	}
+	return nil
+}
+
+func TestDegradedPolicies(t *testing.T) {
+	policies := []Policy{
+		{Namespace: namespace.Organization, Name: "org_admin_policy", RequiredScopes: []string{permissions.OrgAdmin}},
+		{Namespace: namespace.Organization, Name: "org_read_policy", RequiredScopes: []string{permissions.OrgRead}},
+		{Namespace: namespace.Organization, Name: "codespaces_policy", Prerequisites: []string{"codespaces"}},
+		{Namespace: namespace.Organization, Name: "premium_policy", Prerequisites: []string{"premium"}},
+	}
+
+	scopes := permissions.ParseTokenScopes([]string{permissions.OrgAdmin})
+	require.Empty(t, DegradedPolicies(policies, []permissions.Role{permissions.OrgRoleOwner}, scopes, nil))
+
+	degraded := DegradedPolicies(policies, []permissions.Role{permissions.OrgRoleMember}, scopes, map[string]bool{"codespaces": true})
+	require.Len(t, degraded, 2)
+	require.Contains(t, findDegraded(degraded, "org_admin_policy").Reason, "higher role")
+	require.Contains(t, findDegraded(degraded, "codespaces_policy").Reason, "codespaces")
+
+	scopes = permissions.ParseTokenScopes([]string{permissions.OrgRead})
+	degraded = DegradedPolicies(policies, []permissions.Role{permissions.OrgRoleOwner}, scopes, nil)
+	require.Len(t, degraded, 1)
+	require.Equal(t, "missing the admin:org scope", degraded[0].Reason)
+}
+
+func TestPoliciesFromAnnotations(t *testing.T) {
+	engine, err := opa.Load(nil, scm_type.GitHub)
+	require.NoError(t, err)
+
+	policies := PoliciesFromAnnotations(engine.Annotations().Flatten(), []namespace.Namespace{namespace.Organization})
+	require.NotEmpty(t, policies)
+	for _, policy := range policies {
+		require.Equal(t, namespace.Organization, policy.Namespace)
+	}
+
+	unavailable := map[string]bool{"codespaces": true}
+	scopes := permissions.ParseTokenScopes(permissions.FullAnalysisScopes)
+	roles := []permissions.Role{permissions.OrgRoleOwner, permissions.RepoRoleAdmin}
+	for _, policy := range DegradedPolicies(policies, roles, scopes, unavailable) {
+		require.Contains(t, policy.Prerequisites, "codespaces")
+	}
+}
+
+func TestReport(t *testing.T) {
+	report := NewReport()
+	report.Add("connectivity", StatusOK, "connected")
+	report.Add("token", StatusWarning, "missing the scopes %s", permissions.OrgAdmin)
+	require.False(t, report.Failed())
+
+	report.AddDegraded("org1 (MEMBER)", []DegradedPolicy{{Policy: Policy{Namespace: namespace.Organization, Name: "policy"}, Reason: "missing the admin:org scope"}})
+	report.AddDegraded("org2 (OWNER)", nil)
+	report.Add("policies", StatusFailed, "failed to load")
+	require.True(t, report.Failed())
+	require.Equal(t, "1 ok, 1 warn, 1 fail", report.Summary())
+
+	var out bytes.Buffer
+	report.Print(&out)
+	require.Contains(t, out.String(), "[WARN] token         missing the scopes admin:org")
+	require.Contains(t, out.String(), "org1 (MEMBER): 1 policies will be skipped\n  - organization.policy (missing the admin:org scope)")
+	require.Contains(t, out.String(), "org2 (OWNER): all the policies can be evaluated")
+}
+
+func TestRequirements(t *testing.T) {
+	collectorPermissions := []collectors.RequiredPermission{
+		collectors.NewRequiredPermission(permissions.RepoAdmin, "Cannot read repository settings", namespace.Repository),
+		collectors.NewRequiredPermission(permissions.RepoHookRead, "Cannot read repository webhooks", namespace.Repository),
+	}
+	policies := []Policy{
+		{Namespace: namespace.Repository, Name: "repository_policy", RequiredScopes: []string{permissions.RepoAdmin, permissions.OrgAdmin}},
+		{Namespace: namespace.Repository, Name: "unrestricted_policy"},
+		{Namespace: namespace.Organization, Name: "organization_policy", RequiredScopes: []string{permissions.OrgRead}},
+	}
+
+	requirements := NewRequirements(namespace.Repository, collectorPermissions, policies)
+	// read:repo_hook is implied by repo
+	require.Equal(t, []string{permissions.OrgAdmin, permissions.RepoAdmin}, requirements.Scopes)
+	require.Len(t, requirements.Collector, 2)
+	require.Equal(t, "repository ADMIN", requirements.Collector[0].Role)
+	require.Equal(t, "repository READ", requirements.Collector[1].Role)
+	require.Len(t, requirements.Policies, 2)
+	require.Equal(t, "organization OWNER", requirements.Policies[1].Role)
+	require.Equal(t, 1, requirements.Unrestricted)
+	require.False(t, requirements.IsEmpty())
+
+	var out bytes.Buffer
+	requirements.Print(&out)
+	require.Contains(t, out.String(), "repository_policy  repo (repository ADMIN), admin:org (organization OWNER)")
+	require.Contains(t, out.String(), "1 other policies need no scope beyond the collection")
+
+	require.True(t, NewRequirements(namespace.RunnerGroup, nil, policies).IsEmpty())
+	require.Equal(t, "organization MEMBER", MinimalRole(namespace.Organization, permissions.OrgRead))
+}
@@ -0,0 +1,89 @@
+package doctor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Legit-Labs/legitify/internal/analyzers/parsing_utils"
+	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/Legit-Labs/legitify/internal/common/permissions"
+	"github.com/open-policy-agent/opa/ast"
+)
+
+// Policy is a policy with the requirements it's evaluated with (from its annotations)
+type Policy struct {
+	Namespace      namespace.Namespace
+	Name           string
+	Title          string
+	RequiredScopes []string
+	Prerequisites  []string
+}
+
+// PoliciesFromAnnotations lists the policies of the annotations in the namespaces, sorted by namespace and name
+func PoliciesFromAnnotations(annotations []*ast.AnnotationsRef, namespaces []namespace.Namespace) []Policy {
+	selected := make(map[namespace.Namespace]bool)
+	for _, ns := range namespaces {
+		selected[ns] = true
+	}
+
+	var policies []Policy
+	for _, annotation := range annotations {
+		parts := strings.Split(strings.TrimPrefix(annotation.Path.String(), "data."), ".")
+		if len(parts) != 2 || !selected[parts[0]] || annotation.Annotations == nil {
+			continue
+		}
+		policies = append(policies, Policy{
+			Namespace:      parts[0],
+			Name:           parts[1],
+			Title:          annotation.Annotations.Title,
+			RequiredScopes: parsing_utils.ResolveAnnotation(annotation.Annotations.Custom["requiredScopes"]),
+			Prerequisites:  parsing_utils.ResolveAnnotation(annotation.Annotations.Custom["prerequisites"]),
+		})
+	}
+
+	sort.Slice(policies, func(i, j int) bool {
+		if policies[i].Namespace != policies[j].Namespace {
+			return policies[i].Namespace < policies[j].Namespace
+		}
+		return policies[i].Name < policies[j].Name
+	})
+	return policies
+}
+
+// DegradedPolicy is a policy that will be skipped, with the reason
+type DegradedPolicy struct {
+	Policy
+	Reason string
+}
+
+// DegradedPolicies returns the policies that will be skipped with the roles and the scopes of the token, or because of an
+// unavailable prerequisite (prerequisites that depend on the analyzed entity, e.g. premium, aren't known in advance)
+func DegradedPolicies(policies []Policy, roles []permissions.Role, scopes permissions.TokenScopes, unavailable map[string]bool) []DegradedPolicy {
+	var degraded []DegradedPolicy
+	for _, policy := range policies {
+		if reason := degradedReason(policy, roles, scopes, unavailable); reason != "" {
+			degraded = append(degraded, DegradedPolicy{Policy: policy, Reason: reason})
+		}
+	}
+	return degraded
+}
+
+func degradedReason(policy Policy, roles []permissions.Role, scopes permissions.TokenScopes, unavailable map[string]bool) string {
+	for _, prerequisite := range policy.Prerequisites {
+		if unavailable[prerequisite] {
+			return fmt.Sprintf("%s is unavailable", prerequisite)
+		}
+	}
+
+	for _, scope := range policy.RequiredScopes {
+		if !permissions.HasScope(scope, scopes, roles) {
+			if !scopes[scope] {
+				return fmt.Sprintf("missing the %s scope", scope)
+			}
+			return fmt.Sprintf("the %s scope requires a higher role than %s", scope, strings.Join(roles, "/"))
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,146 @@
+package doctor
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/Legit-Labs/legitify/internal/collectors"
+	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/Legit-Labs/legitify/internal/common/permissions"
+)
+
+// Requirement is a token scope, the minimal role it's effective with, and what needs it
+type Requirement struct {
+	Scope  permissions.TokenScope
+	Role   string
+	Reason string
+}
+
+// Requirements are the permissions the collection and the policies of a namespace need
+type Requirements struct {
+	Namespace namespace.Namespace
+	// Scopes is the minimal set of token scopes for a full analysis of the namespace
+	Scopes    []permissions.TokenScope
+	Collector []Requirement
+	Policies  []Requirement
+	// Unrestricted is the number of policies that need no scope beyond the collection
+	Unrestricted int
+}
+
+// NewRequirements lists the permissions of the namespace, from the permissions its collector declares and the required scopes
+// of its policies
+func NewRequirements(ns namespace.Namespace, collectorPermissions []collectors.RequiredPermission, policies []Policy) Requirements {
+	requirements := Requirements{Namespace: ns}
+	var scopes []permissions.TokenScope
+	seen := make(map[permissions.TokenScope]bool)
+	addScope := func(scope permissions.TokenScope) {
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	for _, permission := range collectorPermissions {
+		requirements.Collector = append(requirements.Collector, Requirement{
+			Scope:  permission.Permission,
+			Role:   MinimalRole(ns, permission.Permission),
+			Reason: permission.Effect,
+		})
+		addScope(permission.Permission)
+	}
+
+	for _, policy := range policies {
+		if policy.Namespace != ns {
+			continue
+		}
+		if len(policy.RequiredScopes) == 0 {
+			requirements.Unrestricted++
+			continue
+		}
+		for _, scope := range policy.RequiredScopes {
+			requirements.Policies = append(requirements.Policies, Requirement{
+				Scope:  scope,
+				Role:   MinimalRole(ns, scope),
+				Reason: policy.Name,
+			})
+			addScope(scope)
+		}
+	}
+
+	requirements.Scopes = permissions.MinimalScopes(scopes)
+	sort.Strings(requirements.Scopes)
+	return requirements
+}
+
+// IsEmpty reports whether the namespace has no collector permissions nor policies (e.g. it isn't supported by the SCM)
+func (r Requirements) IsEmpty() bool {
+	return len(r.Collector) == 0 && len(r.Policies) == 0 && r.Unrestricted == 0
+}
+
+// MinimalRole describes the lowest role the scope is effective with in the namespace: repositories are evaluated with both
+// the organization and the repository role, so the repository role is enough if there's one
+func MinimalRole(ns namespace.Namespace, scope permissions.TokenScope) string {
+	if ns == namespace.Repository {
+		if role := permissions.MinimalRepoRole(scope); role != permissions.RepoRoleNone {
+			return fmt.Sprintf("repository %s", role)
+		}
+	}
+	return fmt.Sprintf("organization %s", permissions.MinimalOrgRole(scope))
+}
+
+func (r Requirements) Print(w io.Writer) {
+	fmt.Fprintf(w, "%s\n", r.Namespace)
+	if len(r.Scopes) == 0 {
+		fmt.Fprintf(w, "  token scopes: none beyond the default\n")
+	} else {
+		fmt.Fprintf(w, "  token scopes: %s\n", strings.Join(r.Scopes, ", "))
+	}
+
+	if len(r.Collector) > 0 {
+		fmt.Fprintf(w, "  collection:\n")
+		printRequirements(w, r.Collector)
+	}
+	if len(r.Policies) > 0 {
+		fmt.Fprintf(w, "  policies:\n")
+		printPolicyRequirements(w, r.Policies)
+	}
+	if r.Unrestricted > 0 && len(r.Policies) > 0 {
+		fmt.Fprintf(w, "  %d other policies need no scope beyond the collection\n", r.Unrestricted)
+	} else if r.Unrestricted > 0 {
+		fmt.Fprintf(w, "  %d policies, none needs a scope beyond the collection\n", r.Unrestricted)
+	}
+}
+
+func printRequirements(w io.Writer, requirements []Requirement) {
+	width := 0
+	for _, requirement := range requirements {
+		if column := len(requirement.Scope) + len(requirement.Role); column > width {
+			width = column
+		}
+	}
+	for _, requirement := range requirements {
+		scope := fmt.Sprintf("%s (%s)", requirement.Scope, requirement.Role)
+		fmt.Fprintf(w, "    %-*s  %s\n", width+3, scope, requirement.Reason)
+	}
+}
+
+// printPolicyRequirements prints a line per policy, with all of its scopes
+func printPolicyRequirements(w io.Writer, requirements []Requirement) {
+	var names []string
+	scopes := make(map[string][]string)
+	width := 0
+	for _, requirement := range requirements {
+		if _, ok := scopes[requirement.Reason]; !ok {
+			names = append(names, requirement.Reason)
+		}
+		scopes[requirement.Reason] = append(scopes[requirement.Reason], fmt.Sprintf("%s (%s)", requirement.Scope, requirement.Role))
+		if len(requirement.Reason) > width {
+			width = len(requirement.Reason)
+		}
+	}
+	for _, name := range names {
+		fmt.Fprintf(w, "    %-*s  %s\n", width, name, strings.Join(scopes[name], ", "))
+	}
+}
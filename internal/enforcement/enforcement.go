@@ -0,0 +1,143 @@
+package enforcement
+
+import (
+	"sort"
+	"strings"
+)
+
+// The types of the actors that can bypass the required reviews
+const (
+	User      = "User"
+	Team      = "Team"
+	App       = "App"
+	DeployKey = "DeployKey"
+)
+
+// The repository roles, from the highest
+const (
+	Admin    = "admin"
+	Maintain = "maintain"
+	Write    = "write"
+)
+
+var roleRanks = map[string]int{
+	Admin:    3,
+	Maintain: 2,
+	Write:    1,
+}
+
+type Actor struct {
+	Type string `json:"type"`
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// Layer is a protection of the default branch that requires reviews: the branch protection rule, or a ruleset.
+// All the layers apply, so code can be pushed unreviewed only by the actors that can bypass all of them.
+type Layer struct {
+	Name string
+	// Roles are the repository roles that can bypass the layer (and the higher roles)
+	Roles []string
+	// Actors are the users, teams, apps and deploy keys that can bypass the layer
+	Actors []Actor
+}
+
+// Collaborator is a user with access to the repository, with their highest role
+type Collaborator struct {
+	ID    int64
+	Login string
+	Role  string
+	// Teams are the bypassing teams the collaborator is a member of
+	Teams []Actor
+}
+
+// Enforcement is the effective enforcement of the code review of the default branch
+type Enforcement struct {
+	// ReviewRequired is whether changes to the default branch require a review (by the branch protection rule or a ruleset)
+	ReviewRequired bool `json:"review_required"`
+	// Layers are the names of the protections that require a review
+	Layers []string `json:"layers"`
+	// UnreviewedPushers are the actors that can still push (or merge) unreviewed code to the default branch:
+	// the collaborators with write access if no review is required, otherwise the ones that can bypass all the layers
+	// (by their role, directly, or as members of a bypassing team).
+	// Teams, apps and deploy keys are also included if they can bypass all the layers directly.
+	UnreviewedPushers []Actor `json:"unreviewed_pushers"`
+}
+
+// Compute computes who can push unreviewed code, given the layers that require a review and the repository collaborators
+func Compute(layers []Layer, collaborators []Collaborator) *Enforcement {
+	result := &Enforcement{
+		ReviewRequired:    len(layers) > 0,
+		Layers:            []string{},
+		UnreviewedPushers: []Actor{},
+	}
+	for _, layer := range layers {
+		result.Layers = append(result.Layers, layer.Name)
+	}
+
+	for _, c := range collaborators {
+		if roleRanks[c.Role] == 0 {
+			continue // no write access
+		}
+		if bypassesAll(layers, func(layer Layer) bool { return layer.bypassedBy(c) }) {
+			result.UnreviewedPushers = append(result.UnreviewedPushers, Actor{Type: User, ID: c.ID, Name: c.Login})
+		}
+	}
+
+	if len(layers) > 0 {
+		for _, actor := range layers[0].Actors {
+			if actor.Type == User {
+				continue // resolved by the collaborators
+			}
+			actor := actor
+			if bypassesAll(layers, func(layer Layer) bool { return layer.hasActor(actor) }) {
+				result.UnreviewedPushers = append(result.UnreviewedPushers, actor)
+			}
+		}
+	}
+
+	sort.SliceStable(result.UnreviewedPushers, func(i, j int) bool {
+		a, b := result.UnreviewedPushers[i], result.UnreviewedPushers[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+
+	return result
+}
+
+func bypassesAll(layers []Layer, bypasses func(layer Layer) bool) bool {
+	for _, layer := range layers {
+		if !bypasses(layer) {
+			return false
+		}
+	}
+	return true
+}
+
+func (l Layer) bypassedBy(c Collaborator) bool {
+	for _, role := range l.Roles {
+		if roleRanks[c.Role] >= roleRanks[role] && roleRanks[role] > 0 {
+			return true
+		}
+	}
+	for _, team := range c.Teams {
+		if l.hasActor(team) {
+			return true
+		}
+	}
+	return l.hasActor(Actor{Type: User, ID: c.ID, Name: c.Login})
+}
+
+func (l Layer) hasActor(actor Actor) bool {
+	for _, a := range l.Actors {
+		if a.Type != actor.Type {
+			continue
+		}
+		if (a.ID != 0 && a.ID == actor.ID) || (a.Name != "" && strings.EqualFold(a.Name, actor.Name)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package enforcement
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var collaborators = []Collaborator{
+	{ID: 1, Login: "owner", Role: Admin},
+	{ID: 2, Login: "maintainer", Role: Maintain},
+	{ID: 3, Login: "developer", Role: Write},
+	{ID: 4, Login: "reader", Role: "read"},
+}
+
+func TestNoReviewRequired(t *testing.T) {
+	result := Compute(nil, collaborators)
+
+	require.False(t, result.ReviewRequired)
+	require.Equal(t, []Actor{
+		{Type: User, ID: 3, Name: "developer"},
+		{Type: User, ID: 2, Name: "maintainer"},
+		{Type: User, ID: 1, Name: "owner"},
+	}, result.UnreviewedPushers)
+}
+
+func TestAdminsBypass(t *testing.T) {
+	result := Compute([]Layer{{Name: "branch protection", Roles: []string{Admin}}}, collaborators)
+
+	require.True(t, result.ReviewRequired)
+	require.Equal(t, []string{"branch protection"}, result.Layers)
+	require.Equal(t, []Actor{{Type: User, ID: 1, Name: "owner"}}, result.UnreviewedPushers)
+}
+
+func TestAllLayersMustBeBypassed(t *testing.T) {
+	team := Actor{Type: Team, ID: 10, Name: "release"}
+	app := Actor{Type: App, ID: 20, Name: "deployer"}
+	layers := []Layer{
+		{Name: "branch protection", Roles: []string{Admin}, Actors: []Actor{{Type: User, Name: "developer"}, team, app}},
+		{Name: "ruleset main", Roles: []string{Maintain}, Actors: []Actor{{Type: Team, ID: 10}}},
+	}
+	result := Compute(layers, collaborators)
+
+	require.Equal(t, []Actor{
+		{Type: Team, ID: 10, Name: "release"},
+		{Type: User, ID: 1, Name: "owner"},
+	}, result.UnreviewedPushers)
+}
+
+func TestNoBypass(t *testing.T) {
+	result := Compute([]Layer{{Name: "branch protection"}}, collaborators)
+
+	require.True(t, result.ReviewRequired)
+	require.Empty(t, result.UnreviewedPushers)
+}
+
+func TestTeamMembersBypass(t *testing.T) {
+	team := Actor{Type: Team, ID: 10, Name: "release"}
+	layers := []Layer{
+		{Name: "branch protection", Actors: []Actor{team}},
+		{Name: "ruleset main", Roles: []string{Maintain}, Actors: []Actor{{Type: Team, ID: 10}}},
+	}
+	// the developer bypasses both layers as a member of the team, and the maintainer by their role as well
+	members := []Collaborator{
+		{ID: 2, Login: "maintainer", Role: Maintain, Teams: []Actor{team}},
+		{ID: 3, Login: "developer", Role: Write, Teams: []Actor{team}},
+		{ID: 5, Login: "other", Role: Write},
+	}
+	result := Compute(layers, members)
+
+	require.Equal(t, []Actor{
+		team,
+		{Type: User, ID: 3, Name: "developer"},
+		{Type: User, ID: 2, Name: "maintainer"},
+	}, result.UnreviewedPushers)
+}
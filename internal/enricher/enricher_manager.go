@@ -8,6 +8,8 @@ import (
 	"github.com/Legit-Labs/legitify/internal/common/group_waiter"
 	"github.com/Legit-Labs/legitify/internal/common/namespace"
 	"github.com/Legit-Labs/legitify/internal/common/severity"
+	"github.com/Legit-Labs/legitify/internal/confidence"
+	"github.com/Legit-Labs/legitify/internal/context_utils"
 	"github.com/Legit-Labs/legitify/internal/enricher/enrichers"
 	"github.com/open-policy-agent/opa/ast"
 )
@@ -36,6 +38,7 @@ type EnrichedData struct {
 	Severity                 severity.Severity
 	CanonicalLink            string
 	Status                   analyzers.PolicyStatus
+	Confidence               *confidence.Confidence
 }
 
 func NewEnricherManager(ctx context.Context) EnricherManager {
@@ -51,12 +54,18 @@ type enricherManager struct {
 type newEnricherFunc func(ctx context.Context) enrichers.Enricher
 
 var enricherTextToEnricher = map[string]newEnricherFunc{
-	enrichers.EntityId:       enrichers.NewEntityIdEnricher,
-	enrichers.EntityName:     enrichers.NewEntityNameEnricher,
-	enrichers.OrganizationId: enrichers.NewOrganizationIdEnricher,
-	enrichers.Scorecard:      enrichers.NewScorecardEnricher,
-	enrichers.MembersList:    enrichers.NewMembersListEnricher,
-	enrichers.HooksList:      enrichers.NewHooksListEnricher,
+	enrichers.EntityId:        enrichers.NewEntityIdEnricher,
+	enrichers.EntityName:      enrichers.NewEntityNameEnricher,
+	enrichers.OrganizationId:  enrichers.NewOrganizationIdEnricher,
+	enrichers.Scorecard:       enrichers.NewScorecardEnricher,
+	enrichers.MembersList:     enrichers.NewMembersListEnricher,
+	enrichers.HooksList:       enrichers.NewHooksListEnricher,
+	enrichers.SeatsReport:     enrichers.NewSeatsReportEnricher,
+	enrichers.AssetMovement:   enrichers.NewAssetMovementEnricher,
+	enrichers.RunnersList:     enrichers.NewRunnersListEnricher,
+	enrichers.Sample:          enrichers.NewSampleEnricher,
+	enrichers.Waiver:          enrichers.NewWaiverEnricher,
+	enrichers.SecurityContact: enrichers.NewSecurityContactEnricher,
 }
 
 func newEnrichedData(analyzed analyzers.AnalyzedData, enrichments map[string]enrichers.Enrichment) EnrichedData {
@@ -73,6 +82,7 @@ func newEnrichedData(analyzed analyzers.AnalyzedData, enrichments map[string]enr
 		RemediationSteps:         analyzed.RemediationSteps,
 		CanonicalLink:            analyzed.CanonicalLink,
 		Status:                   analyzed.Status,
+		Confidence:               analyzed.Confidence,
 	}
 }
 
@@ -87,6 +97,15 @@ func (e *enricherManager) Enrich(analyzedDataChannel <-chan analyzers.AnalyzedDa
 				gw.Do(func() {
 					requiredEnrichers := analyzedData.RequiredEnrichers
 					requiredEnrichers = append(requiredEnrichers, DefaultEnrichers...)
+					if _, sampled := context_utils.GetSamplePercent(e.ctx); sampled {
+						requiredEnrichers = append(requiredEnrichers, enrichers.Sample)
+					}
+					if analyzedData.Status == analyzers.PolicyWaived {
+						requiredEnrichers = append(requiredEnrichers, enrichers.Waiver)
+					}
+					if _, configured := context_utils.GetSecurityContacts(e.ctx); configured {
+						requiredEnrichers = append(requiredEnrichers, enrichers.SecurityContact)
+					}
 
 					enrichments := make(map[string]enrichers.Enrichment)
 					for _, requiredEnricher := range requiredEnrichers {
@@ -0,0 +1,79 @@
+package enrichers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/Legit-Labs/legitify/internal/analyzers"
+	"github.com/Legit-Labs/legitify/internal/common/utils"
+)
+
+const AssetMovement = "assetMovement"
+
+func NewAssetMovementEnricher(_ context.Context) Enricher {
+	return &assetMovementEnricher{}
+}
+
+type assetMovementEnricher struct {
+}
+
+func (e *assetMovementEnricher) Enrich(data analyzers.AnalyzedData) (Enrichment, bool) {
+	result, err := createAssetMovementEnrichment(data.ExtraData)
+	if err != nil {
+		return nil, false
+	}
+	return result, true
+}
+
+func (e *assetMovementEnricher) Name() string {
+	return AssetMovement
+}
+
+// RepositoryMovement is a single repository transfer, as reported by the policy
+type RepositoryMovement struct {
+	Repository string `json:"repository"`
+	Actor      string `json:"actor"`
+	Date       string `json:"date"`
+}
+
+type AssetMovementEnrichment struct {
+	Repositories []RepositoryMovement `json:"repositories"`
+}
+
+func createAssetMovementEnrichment(extraData interface{}) (Enrichment, error) {
+	casted, ok := extraData.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid asset movement extra data")
+	}
+
+	result := &AssetMovementEnrichment{}
+	for k := range casted {
+		var movement RepositoryMovement
+		if err := json.Unmarshal([]byte(k), &movement); err != nil {
+			return nil, err
+		}
+		result.Repositories = append(result.Repositories, movement)
+	}
+
+	sort.Slice(result.Repositories, func(i, j int) bool {
+		return result.Repositories[i].Date > result.Repositories[j].Date
+	})
+
+	return result, nil
+}
+
+func (se *AssetMovementEnrichment) Name() string {
+	return AssetMovement
+}
+
+func (se *AssetMovementEnrichment) HumanReadable(prepend string) string {
+	sb := utils.NewPrependedStringBuilder(prepend)
+
+	for i, movement := range se.Repositories {
+		sb.WriteString(fmt.Sprintf("%d. %s (transferred by %s at %s)\n", i+1, movement.Repository, movement.Actor, movement.Date))
+	}
+
+	return sb.String()
+}
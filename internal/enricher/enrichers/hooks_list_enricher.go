@@ -18,17 +18,18 @@ type hooksListEnricher struct {
 }
 
 func (e *hooksListEnricher) Enrich(data analyzers.AnalyzedData) (Enrichment, bool) {
-	result, err := createHooksListEnrichment(data.ExtraData)
+	result, err := createGenericListEnrichment(HooksList, data.ExtraData)
 	if err != nil {
 		return nil, false
 	}
 	return result, true
 }
 
-func createHooksListEnrichment(extraData interface{}) (Enrichment, error) {
+// createGenericListEnrichment lists the violated items reported by the policy (as json keys of the extra data)
+func createGenericListEnrichment(name string, extraData interface{}) (Enrichment, error) {
 	casted, ok := extraData.(map[string]interface{})
 	if !ok {
-		return nil, fmt.Errorf("invalid hookslist extra data")
+		return nil, fmt.Errorf("invalid %s extra data", name)
 	}
 	var result []map[string]string
 
@@ -44,6 +45,7 @@ func createHooksListEnrichment(extraData interface{}) (Enrichment, error) {
 	}
 
 	return &GenericListEnrichment{
+		name:               name,
 		GenericEnrichments: result,
 	}, nil
 }
@@ -53,11 +55,12 @@ func (e *hooksListEnricher) Name() string {
 }
 
 type GenericListEnrichment struct {
+	name               string
 	GenericEnrichments []map[string]string
 }
 
 func (se *GenericListEnrichment) Name() string {
-	return HooksList
+	return se.name
 }
 
 func (se *GenericListEnrichment) HumanReadable(prepend string) string {
@@ -0,0 +1,28 @@
+package enrichers
+
+import (
+	"context"
+
+	"github.com/Legit-Labs/legitify/internal/analyzers"
+)
+
+const RunnersList = "runnersList"
+
+func NewRunnersListEnricher(_ context.Context) Enricher {
+	return &runnersListEnricher{}
+}
+
+type runnersListEnricher struct {
+}
+
+func (e *runnersListEnricher) Enrich(data analyzers.AnalyzedData) (Enrichment, bool) {
+	result, err := createGenericListEnrichment(RunnersList, data.ExtraData)
+	if err != nil {
+		return nil, false
+	}
+	return result, true
+}
+
+func (e *runnersListEnricher) Name() string {
+	return RunnersList
+}
@@ -0,0 +1,37 @@
+package enrichers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Legit-Labs/legitify/internal/analyzers"
+	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/Legit-Labs/legitify/internal/context_utils"
+)
+
+const Sample = "sample"
+
+func NewSampleEnricher(ctx context.Context) Enricher {
+	percent, sampled := context_utils.GetSamplePercent(ctx)
+	return &sampleEnricher{
+		percent: percent,
+		sampled: sampled,
+	}
+}
+
+// sampleEnricher marks the repository results of a sampled scan, so they are not mistaken for the full posture
+type sampleEnricher struct {
+	percent int
+	sampled bool
+}
+
+func (e *sampleEnricher) Enrich(data analyzers.AnalyzedData) (Enrichment, bool) {
+	if !e.sampled || data.Namespace != namespace.Repository {
+		return nil, false
+	}
+	return NewBasicEnrichment(fmt.Sprintf("sampled scan (%d%% of the repositories)", e.percent), Sample), true
+}
+
+func (e *sampleEnricher) Name() string {
+	return Sample
+}
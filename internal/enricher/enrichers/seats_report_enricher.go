@@ -0,0 +1,89 @@
+package enrichers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Legit-Labs/legitify/internal/analyzers"
+	githubcollected "github.com/Legit-Labs/legitify/internal/collected/github"
+	"github.com/Legit-Labs/legitify/internal/common/utils"
+)
+
+const SeatsReport = "seatsReport"
+
+// members that weren't active for this long are considered dormant seats
+const dormantSeatPeriod = 90 * 24 * time.Hour
+
+func NewSeatsReportEnricher(_ context.Context) Enricher {
+	return &seatsReportEnricher{}
+}
+
+type seatsReportEnricher struct {
+}
+
+func (e *seatsReportEnricher) Enrich(data analyzers.AnalyzedData) (Enrichment, bool) {
+	members, ok := data.Entity.(githubcollected.OrganizationMembers)
+	if !ok {
+		return nil, false
+	}
+
+	return newSeatsReportEnrichment(members, time.Now()), true
+}
+
+func (e *seatsReportEnricher) Name() string {
+	return SeatsReport
+}
+
+// SeatsReportEnrichment summarizes the licensed seats usage of an organization.
+// DormantSeats is -1 when the members' activity is not available (non-enterprise organizations).
+type SeatsReportEnrichment struct {
+	Seats              int `json:"seats"`
+	FilledSeats        int `json:"filledSeats"`
+	UnusedSeats        int `json:"unusedSeats"`
+	PendingInvitations int `json:"pendingInvitations"`
+	DormantSeats       int `json:"dormantSeats"`
+}
+
+func newSeatsReportEnrichment(members githubcollected.OrganizationMembers, now time.Time) *SeatsReportEnrichment {
+	plan := members.Organization.GetPlan()
+	report := &SeatsReportEnrichment{
+		Seats:              plan.GetSeats(),
+		FilledSeats:        plan.GetFilledSeats(),
+		PendingInvitations: len(members.PendingInvitations),
+		DormantSeats:       -1,
+	}
+
+	if report.Seats > report.FilledSeats {
+		report.UnusedSeats = report.Seats - report.FilledSeats
+	}
+
+	if members.HasLastActive {
+		report.DormantSeats = 0
+		for _, member := range members.Members {
+			if now.Sub(time.Unix(0, int64(member.LastActive))) >= dormantSeatPeriod {
+				report.DormantSeats++
+			}
+		}
+	}
+
+	return report
+}
+
+func (se *SeatsReportEnrichment) Name() string {
+	return SeatsReport
+}
+
+func (se *SeatsReportEnrichment) HumanReadable(prepend string) string {
+	sb := utils.NewPrependedStringBuilder(prepend)
+
+	sb.WriteString(fmt.Sprintf("Licensed seats: %d\n", se.Seats))
+	sb.WriteString(fmt.Sprintf("Filled seats: %d\n", se.FilledSeats))
+	sb.WriteString(fmt.Sprintf("Unused seats: %d\n", se.UnusedSeats))
+	sb.WriteString(fmt.Sprintf("Pending invitations: %d\n", se.PendingInvitations))
+	if se.DormantSeats >= 0 {
+		sb.WriteString(fmt.Sprintf("Dormant seats (inactive for 90 days): %d\n", se.DormantSeats))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,70 @@
+package enrichers
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Legit-Labs/legitify/internal/analyzers"
+	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/Legit-Labs/legitify/internal/contacts"
+	"github.com/Legit-Labs/legitify/internal/context_utils"
+)
+
+const SecurityContact = "securityContact"
+
+func NewSecurityContactEnricher(ctx context.Context) Enricher {
+	directory, _ := context_utils.GetSecurityContacts(ctx)
+	return &securityContactEnricher{
+		directory: directory,
+	}
+}
+
+// securityContactEnricher adds who to call about the violations (see --security-contacts)
+type securityContactEnricher struct {
+	directory *contacts.Directory
+}
+
+func (e *securityContactEnricher) Enrich(data analyzers.AnalyzedData) (Enrichment, bool) {
+	if e.directory == nil || data.Status != analyzers.PolicyFailed {
+		return nil, false
+	}
+
+	var repo string
+	if data.Namespace == namespace.Repository {
+		repo = data.Entity.Name()
+	}
+	contact, ok := e.directory.Lookup(data.Organization, repo)
+	if !ok {
+		return nil, false
+	}
+
+	return NewSecurityContactEnrichment(contact), true
+}
+
+func (e *securityContactEnricher) Name() string {
+	return SecurityContact
+}
+
+type SecurityContactEnrichment struct {
+	contact contacts.Contact
+}
+
+func NewSecurityContactEnrichment(contact contacts.Contact) *SecurityContactEnrichment {
+	return &SecurityContactEnrichment{contact: contact}
+}
+
+func (se *SecurityContactEnrichment) Contact() contacts.Contact {
+	return se.contact
+}
+
+func (se *SecurityContactEnrichment) MarshalJSON() ([]byte, error) {
+	return json.Marshal(se.contact)
+}
+
+func (se *SecurityContactEnrichment) HumanReadable(_ string) string {
+	return se.contact.String()
+}
+
+func (se *SecurityContactEnrichment) Name() string {
+	return SecurityContact
+}
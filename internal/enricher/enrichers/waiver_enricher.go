@@ -0,0 +1,55 @@
+package enrichers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Legit-Labs/legitify/internal/analyzers"
+	"github.com/Legit-Labs/legitify/internal/waivers"
+)
+
+const Waiver = "waiver"
+
+func NewWaiverEnricher(ctx context.Context) Enricher {
+	return &waiverEnricher{}
+}
+
+// waiverEnricher adds the justification of waived violations, for audit transparency
+type waiverEnricher struct {
+}
+
+func (e *waiverEnricher) Enrich(data analyzers.AnalyzedData) (Enrichment, bool) {
+	if data.Status != analyzers.PolicyWaived || data.Waiver == nil {
+		return nil, false
+	}
+	return NewWaiverEnrichment(*data.Waiver), true
+}
+
+func (e *waiverEnricher) Name() string {
+	return Waiver
+}
+
+type WaiverEnrichment struct {
+	waiver waivers.Waiver
+}
+
+func NewWaiverEnrichment(waiver waivers.Waiver) *WaiverEnrichment {
+	return &WaiverEnrichment{waiver: waiver}
+}
+
+func (we *WaiverEnrichment) Justification() string {
+	return we.waiver.Justification
+}
+
+func (we *WaiverEnrichment) MarshalJSON() ([]byte, error) {
+	return json.Marshal(we.waiver)
+}
+
+func (we *WaiverEnrichment) HumanReadable(_ string) string {
+	return fmt.Sprintf("%s (%s)", we.waiver.Justification, we.waiver.Source)
+}
+
+func (we *WaiverEnrichment) Name() string {
+	return Waiver
+}
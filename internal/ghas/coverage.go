@@ -0,0 +1,87 @@
+package ghas
+
+import "sort"
+
+// RepositoryStatus is the status of the GitHub Advanced Security features of a single repository (nil if unknown)
+type RepositoryStatus struct {
+	Name                         string `json:"name"`
+	Private                      bool   `json:"private"`
+	AdvancedSecurity             *bool  `json:"advanced_security"`
+	SecretScanning               *bool  `json:"secret_scanning"`
+	SecretScanningPushProtection *bool  `json:"secret_scanning_push_protection"`
+	CodeScanning                 *bool  `json:"code_scanning"`
+}
+
+// FeatureCoverage is the coverage of a single feature: the repositories it's enabled for, out of the repositories its status is known for
+type FeatureCoverage struct {
+	Enabled int `json:"enabled"`
+	Known   int `json:"known"`
+	Percent int `json:"percent"`
+	// Missing are the repositories the feature is disabled for
+	Missing []string `json:"missing"`
+}
+
+// VisibilityCoverage is the coverage of the features in the repositories of a single visibility
+type VisibilityCoverage struct {
+	Repositories                 int             `json:"repositories"`
+	AdvancedSecurity             FeatureCoverage `json:"advanced_security"`
+	SecretScanning               FeatureCoverage `json:"secret_scanning"`
+	SecretScanningPushProtection FeatureCoverage `json:"secret_scanning_push_protection"`
+	CodeScanning                 FeatureCoverage `json:"code_scanning"`
+}
+
+// Coverage is the GitHub Advanced Security coverage of an organization (archived repositories excluded)
+type Coverage struct {
+	Private VisibilityCoverage `json:"private"`
+	Public  VisibilityCoverage `json:"public"`
+}
+
+// Compute aggregates the repositories statuses into the coverage of each visibility
+func Compute(repositories []RepositoryStatus) *Coverage {
+	var private, public []RepositoryStatus
+	for _, repo := range repositories {
+		if repo.Private {
+			private = append(private, repo)
+		} else {
+			public = append(public, repo)
+		}
+	}
+
+	return &Coverage{
+		Private: computeVisibility(private),
+		Public:  computeVisibility(public),
+	}
+}
+
+func computeVisibility(repositories []RepositoryStatus) VisibilityCoverage {
+	return VisibilityCoverage{
+		Repositories:                 len(repositories),
+		AdvancedSecurity:             computeFeature(repositories, func(r RepositoryStatus) *bool { return r.AdvancedSecurity }),
+		SecretScanning:               computeFeature(repositories, func(r RepositoryStatus) *bool { return r.SecretScanning }),
+		SecretScanningPushProtection: computeFeature(repositories, func(r RepositoryStatus) *bool { return r.SecretScanningPushProtection }),
+		CodeScanning:                 computeFeature(repositories, func(r RepositoryStatus) *bool { return r.CodeScanning }),
+	}
+}
+
+func computeFeature(repositories []RepositoryStatus, status func(RepositoryStatus) *bool) FeatureCoverage {
+	coverage := FeatureCoverage{Missing: []string{}}
+	for _, repo := range repositories {
+		enabled := status(repo)
+		if enabled == nil {
+			continue
+		}
+		coverage.Known++
+		if *enabled {
+			coverage.Enabled++
+		} else {
+			coverage.Missing = append(coverage.Missing, repo.Name)
+		}
+	}
+
+	if coverage.Known > 0 {
+		coverage.Percent = coverage.Enabled * 100 / coverage.Known
+	}
+	sort.Strings(coverage.Missing)
+
+	return coverage
+}
@@ -0,0 +1,26 @@
+package ghas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompute(t *testing.T) {
+	enabled, disabled := true, false
+	coverage := Compute([]RepositoryStatus{
+		{Name: "b", Private: true, AdvancedSecurity: &disabled, SecretScanning: &disabled},
+		{Name: "a", Private: true, AdvancedSecurity: &disabled},
+		{Name: "c", Private: true, AdvancedSecurity: &enabled, SecretScanning: &enabled, CodeScanning: &enabled},
+		{Name: "d", Private: false, SecretScanning: &enabled, CodeScanning: &disabled},
+	})
+
+	require.Equal(t, 3, coverage.Private.Repositories)
+	require.Equal(t, FeatureCoverage{Enabled: 1, Known: 3, Percent: 33, Missing: []string{"a", "b"}}, coverage.Private.AdvancedSecurity)
+	require.Equal(t, FeatureCoverage{Enabled: 1, Known: 2, Percent: 50, Missing: []string{"b"}}, coverage.Private.SecretScanning)
+	require.Equal(t, FeatureCoverage{Missing: []string{}}, coverage.Private.SecretScanningPushProtection)
+
+	require.Equal(t, 1, coverage.Public.Repositories)
+	require.Equal(t, 100, coverage.Public.SecretScanning.Percent)
+	require.Equal(t, []string{"d"}, coverage.Public.CodeScanning.Missing)
+}
@@ -0,0 +1,52 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v44/github"
+)
+
+// FetchEvents reads the organization audit log events of the reconstruction actions between the given times.
+// Note: the audit log is only available for organizations that are part of an enterprise, and it's retained for a limited period.
+func FetchEvents(ctx context.Context, client *github.Client, org string, from time.Time, to time.Time) ([]Event, error) {
+	var result []Event
+	period := fmt.Sprintf("%s..%s", from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339))
+
+	for _, action := range Actions() {
+		opts := &github.GetAuditLogOptions{
+			Phrase: github.String(fmt.Sprintf("action:%s created:%s", action, period)),
+			ListCursorOptions: github.ListCursorOptions{
+				PerPage: 100,
+			},
+		}
+
+		for {
+			entries, resp, err := client.Organizations.GetAuditLog(ctx, org, opts)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read the audit log of %s: %v", org, err)
+			}
+
+			for _, entry := range entries {
+				entity := entry.GetRepo()
+				if entity == "" {
+					entity = entry.GetOrg()
+				}
+				result = append(result, Event{
+					Action: entry.GetAction(),
+					Entity: entity,
+					Actor:  entry.GetActor(),
+					Time:   entry.GetTimestamp().Time,
+				})
+			}
+
+			if resp.After == "" {
+				break
+			}
+			opts.After = resp.After
+		}
+	}
+
+	return result, nil
+}
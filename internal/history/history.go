@@ -0,0 +1,211 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Legit-Labs/legitify/internal/analyzers"
+)
+
+// StatusNotExisting is the status of an entity that was created after the reconstructed date
+const StatusNotExisting = "NOT_EXISTING"
+
+// Event is an audit log event of an entity (an organization, or a repository as owner/name)
+type Event struct {
+	Action string    `yaml:"action" json:"action"`
+	Entity string    `yaml:"entity" json:"entity"`
+	Actor  string    `yaml:"actor,omitempty" json:"actor,omitempty"`
+	Time   time.Time `yaml:"time" json:"time"`
+}
+
+// rule maps an audit log action to the policy status it leaves the entity in
+type rule struct {
+	Action        string
+	Policy        string
+	ViolatedAfter bool
+}
+
+const repositoryCreated = "repo.create"
+
+// rules are the audit log actions the posture can be reconstructed by.
+// Events are assumed to be actual transitions (e.g. a protected_branch.create was preceded by no protection),
+// so the reconstruction is approximate (e.g. it doesn't tell the default branch from other branches).
+var rules = []rule{
+	{"protected_branch.create", "repository.missing_default_branch_protection", false},
+	{"protected_branch.destroy", "repository.missing_default_branch_protection", true},
+	{"repository_vulnerability_alerts.enable", "repository.vulnerability_alerts_not_enabled", false},
+	{"repository_vulnerability_alerts.disable", "repository.vulnerability_alerts_not_enabled", true},
+	{"repository_secret_scanning.enable", "repository.secret_scanning_not_enabled", false},
+	{"repository_secret_scanning.disable", "repository.secret_scanning_not_enabled", true},
+	{"repository_secret_scanning_push_protection.enable", "repository.secret_scanning_push_protection_not_enabled", false},
+	{"repository_secret_scanning_push_protection.disable", "repository.secret_scanning_push_protection_not_enabled", true},
+	{"org.enable_two_factor_requirement", "organization.two_factor_authentication_not_required_for_org", false},
+	{"org.disable_two_factor_requirement", "organization.two_factor_authentication_not_required_for_org", true},
+	{"org.enable_saml", "organization.organization_not_using_single_sign_on", false},
+	{"org.disable_saml", "organization.organization_not_using_single_sign_on", true},
+}
+
+// Actions returns the audit log actions used by the reconstruction
+func Actions() []string {
+	actions := []string{repositoryCreated}
+	for _, r := range rules {
+		actions = append(actions, r.Action)
+	}
+	return actions
+}
+
+// Entry is the reconstructed status of a policy for a single entity
+type Entry struct {
+	Policy         string `yaml:"policy" json:"policy"`
+	Entity         string `yaml:"entity" json:"entity"`
+	SnapshotStatus string `yaml:"snapshot_status" json:"snapshotStatus"`
+	Status         string `yaml:"status" json:"status"`
+	// Reconstructed is false for policies that no audit log action is mapped to (their status is assumed unchanged)
+	Reconstructed bool    `yaml:"reconstructed" json:"reconstructed"`
+	Events        []Event `yaml:"events,omitempty" json:"events,omitempty"`
+}
+
+type Report struct {
+	At           time.Time `yaml:"at" json:"at"`
+	SnapshotTime time.Time `yaml:"snapshot_time" json:"snapshotTime"`
+	Entries      []Entry   `yaml:"entries" json:"entries"`
+}
+
+// serializedViolation mirrors scheme.Violation without the enrichments,
+// which can't be deserialized back into their original types.
+type serializedViolation struct {
+	CanonicalLink string                 `json:"canonicalLink"`
+	Status        analyzers.PolicyStatus `json:"Status"`
+}
+
+type serializedOutputData struct {
+	Violations []serializedViolation `json:"violations"`
+}
+
+// Snapshot is the status of the policies per entity (canonical link) at the time of a previous analysis
+type Snapshot map[string]map[string]analyzers.PolicyStatus
+
+// SnapshotFromFlattenedJson reads a snapshot from a legitify json output (flattened scheme).
+func SnapshotFromFlattenedJson(data []byte) (Snapshot, error) {
+	var findings map[string]serializedOutputData
+	if err := json.Unmarshal(data, &findings); err != nil {
+		return nil, fmt.Errorf("failed to parse results (expecting the json format with the flattened scheme): %v", err)
+	}
+
+	snapshot := make(Snapshot)
+	for policy, outputData := range findings {
+		snapshot[policy] = make(map[string]analyzers.PolicyStatus)
+		for _, v := range outputData.Violations {
+			snapshot[policy][v.CanonicalLink] = v.Status
+		}
+	}
+
+	return snapshot, nil
+}
+
+// Reconstruct approximates the status of the snapshot policies at the given date,
+// by undoing the events that happened between the date and the snapshot time (latest first).
+// Only the given policies are reconstructed (all of them if none are given).
+func Reconstruct(snapshot Snapshot, snapshotTime time.Time, at time.Time, events []Event, policies []string) *Report {
+	report := &Report{
+		At:           at,
+		SnapshotTime: snapshotTime,
+		Entries:      []Entry{},
+	}
+
+	relevant := make([]Event, 0, len(events))
+	for _, e := range events {
+		if e.Time.After(at) && !e.Time.After(snapshotTime) {
+			relevant = append(relevant, e)
+		}
+	}
+	sort.SliceStable(relevant, func(i, j int) bool {
+		return relevant[i].Time.After(relevant[j].Time)
+	})
+
+	for policy, entities := range snapshot {
+		if !isSelected(policy, policies) {
+			continue
+		}
+		for entity, status := range entities {
+			report.Entries = append(report.Entries, reconstructEntry(policy, entity, status, relevant))
+		}
+	}
+
+	sort.Slice(report.Entries, func(i, j int) bool {
+		a, b := report.Entries[i], report.Entries[j]
+		if a.Policy != b.Policy {
+			return a.Policy < b.Policy
+		}
+		return a.Entity < b.Entity
+	})
+
+	return report
+}
+
+func reconstructEntry(policy string, entity string, status analyzers.PolicyStatus, events []Event) Entry {
+	entry := Entry{
+		Policy:         policy,
+		Entity:         entity,
+		SnapshotStatus: status,
+		Status:         status,
+	}
+
+	policyRules := make(map[string]rule)
+	for _, r := range rules {
+		if r.Policy == policy {
+			policyRules[r.Action] = r
+		}
+	}
+	entry.Reconstructed = len(policyRules) > 0
+
+	for _, e := range events {
+		if !matchesEntity(entity, e.Entity) {
+			continue
+		}
+
+		if e.Action == repositoryCreated {
+			entry.Status = StatusNotExisting
+			entry.Events = append(entry.Events, e)
+			break
+		}
+
+		r, ok := policyRules[e.Action]
+		if !ok {
+			continue
+		}
+		entry.Events = append(entry.Events, e)
+		if entry.Status != analyzers.PolicyPassed && entry.Status != analyzers.PolicyFailed {
+			// skipped (or waived) policies can't be reconstructed
+			continue
+		}
+		// before the event, the entity was in the opposite state
+		if r.ViolatedAfter {
+			entry.Status = analyzers.PolicyPassed
+		} else {
+			entry.Status = analyzers.PolicyFailed
+		}
+	}
+
+	return entry
+}
+
+// matchesEntity matches the canonical link of an entity with the audit log entity (org or owner/repo)
+func matchesEntity(canonicalLink string, entity string) bool {
+	return entity != "" && strings.HasSuffix(strings.ToLower(strings.TrimSuffix(canonicalLink, "/")), "/"+strings.ToLower(entity))
+}
+
+func isSelected(policy string, policies []string) bool {
+	if len(policies) == 0 {
+		return true
+	}
+	for _, p := range policies {
+		if p == policy || strings.HasSuffix(policy, "."+p) {
+			return true
+		}
+	}
+	return false
+}
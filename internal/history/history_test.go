@@ -0,0 +1,106 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Legit-Labs/legitify/internal/analyzers"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	branchProtection = "repository.missing_default_branch_protection"
+	twoFactor        = "organization.two_factor_authentication_not_required_for_org"
+	repoLink         = "https://github.com/org1/repo1"
+	orgLink          = "https://github.com/org1"
+)
+
+var (
+	at           = time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	snapshotTime = time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func day(month time.Month, d int) time.Time {
+	return time.Date(2023, month, d, 12, 0, 0, 0, time.UTC)
+}
+
+func findEntry(t *testing.T, report *Report, policy string, entity string) Entry {
+	for _, e := range report.Entries {
+		if e.Policy == policy && e.Entity == entity {
+			return e
+		}
+	}
+	require.Failf(t, "entry not found", "%s %s", policy, entity)
+	return Entry{}
+}
+
+func TestReconstructUndoesEvents(t *testing.T) {
+	snapshot := Snapshot{
+		branchProtection: {repoLink: analyzers.PolicyPassed},
+		twoFactor:        {orgLink: analyzers.PolicyFailed},
+	}
+	events := []Event{
+		// protection was removed in April and added back in May: protected on March 1st
+		{Action: "protected_branch.destroy", Entity: "org1/repo1", Time: day(time.April, 1)},
+		{Action: "protected_branch.create", Entity: "org1/repo1", Time: day(time.May, 1)},
+		// 2FA requirement disabled in April: required on March 1st
+		{Action: "org.disable_two_factor_requirement", Entity: "org1", Time: day(time.April, 10)},
+		// before the reconstructed date: ignored
+		{Action: "org.enable_two_factor_requirement", Entity: "org1", Time: day(time.February, 1)},
+	}
+
+	report := Reconstruct(snapshot, snapshotTime, at, events, nil)
+	require.Len(t, report.Entries, 2)
+
+	protection := findEntry(t, report, branchProtection, repoLink)
+	require.True(t, protection.Reconstructed)
+	require.Equal(t, analyzers.PolicyPassed, protection.Status)
+	require.Len(t, protection.Events, 2)
+
+	mfa := findEntry(t, report, twoFactor, orgLink)
+	require.Equal(t, analyzers.PolicyPassed, mfa.Status)
+	require.Equal(t, analyzers.PolicyFailed, mfa.SnapshotStatus)
+	require.Len(t, mfa.Events, 1)
+}
+
+func TestReconstructFirstChange(t *testing.T) {
+	snapshot := Snapshot{branchProtection: {repoLink: analyzers.PolicyPassed}}
+	events := []Event{
+		{Action: "protected_branch.create", Entity: "org1/repo1", Time: day(time.April, 1)},
+		// another repository's event
+		{Action: "protected_branch.destroy", Entity: "org1/repo10", Time: day(time.April, 2)},
+	}
+
+	report := Reconstruct(snapshot, snapshotTime, at, events, nil)
+	require.Equal(t, analyzers.PolicyFailed, findEntry(t, report, branchProtection, repoLink).Status)
+}
+
+func TestReconstructNewRepository(t *testing.T) {
+	snapshot := Snapshot{branchProtection: {repoLink: analyzers.PolicyFailed}}
+	events := []Event{{Action: "repo.create", Entity: "org1/repo1", Time: day(time.April, 1)}}
+
+	report := Reconstruct(snapshot, snapshotTime, at, events, nil)
+	require.Equal(t, StatusNotExisting, findEntry(t, report, branchProtection, repoLink).Status)
+}
+
+func TestReconstructUnmappedPolicy(t *testing.T) {
+	snapshot := Snapshot{
+		"repository.non_linear_history": {repoLink: analyzers.PolicyFailed},
+		branchProtection:                {repoLink: analyzers.PolicyPassed},
+	}
+
+	report := Reconstruct(snapshot, snapshotTime, at, nil, []string{"non_linear_history"})
+	require.Len(t, report.Entries, 1)
+	require.False(t, report.Entries[0].Reconstructed)
+	require.Equal(t, analyzers.PolicyFailed, report.Entries[0].Status)
+}
+
+func TestSnapshotFromFlattenedJson(t *testing.T) {
+	data := []byte(`{"repository.missing_default_branch_protection": {"policyInfo": {}, "violations": [{"canonicalLink": "https://github.com/org1/repo1", "Status": "FAILED"}]}}`)
+	snapshot, err := SnapshotFromFlattenedJson(data)
+	require.NoError(t, err)
+	require.Equal(t, analyzers.PolicyFailed, snapshot[branchProtection][repoLink])
+
+	_, err = SnapshotFromFlattenedJson([]byte("[1]"))
+	require.Error(t, err)
+}
@@ -0,0 +1,161 @@
+package identity
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Source is the set of users known to the identity provider (e.g. an HR or IdP export).
+// Users are identified by their usernames and emails (case insensitive).
+type Source struct {
+	identifiers map[string]bool
+}
+
+func newSource() *Source {
+	return &Source{
+		identifiers: make(map[string]bool),
+	}
+}
+
+func (s *Source) add(identifier string) {
+	identifier = strings.ToLower(strings.TrimSpace(identifier))
+	if identifier == "" {
+		return
+	}
+	s.identifiers[identifier] = true
+}
+
+// Contains returns true if any of the given identifiers (e.g. login, email) belongs to a known user
+func (s *Source) Contains(identifiers ...string) bool {
+	for _, identifier := range identifiers {
+		if s.identifiers[strings.ToLower(strings.TrimSpace(identifier))] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Source) Size() int {
+	return len(s.identifiers)
+}
+
+// Load reads an identity source export: either a CSV file (.csv) or a SCIM users export (json)
+func Load(path string) (*Source, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var source *Source
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		source, err = parseCsv(file)
+	} else {
+		source, err = parseScim(file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse identity source %s: %v", path, err)
+	}
+
+	if source.Size() == 0 {
+		return nil, fmt.Errorf("identity source %s doesn't contain any users", path)
+	}
+
+	return source, nil
+}
+
+var csvIdentifierColumns = map[string]bool{
+	"login":    true,
+	"username": true,
+	"user":     true,
+	"github":   true,
+	"email":    true,
+	"mail":     true,
+}
+
+// parseCsv expects a header row; all the values of the identifier columns (login/username/email...) are used.
+// If none of the columns is recognized, the first column is used.
+func parseCsv(r io.Reader) (*Source, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	source := newSource()
+	if len(records) == 0 {
+		return source, nil
+	}
+
+	var columns []int
+	for i, header := range records[0] {
+		normalized := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(header)), "_", "")
+		if csvIdentifierColumns[normalized] {
+			columns = append(columns, i)
+		}
+	}
+	if len(columns) == 0 {
+		columns = []int{0}
+	}
+
+	for _, record := range records[1:] {
+		for _, column := range columns {
+			if column < len(record) {
+				source.add(record[column])
+			}
+		}
+	}
+
+	return source, nil
+}
+
+type scimUser struct {
+	UserName string `json:"userName"`
+	Active   *bool  `json:"active"`
+	Emails   []struct {
+		Value string `json:"value"`
+	} `json:"emails"`
+}
+
+type scimListResponse struct {
+	Resources []scimUser `json:"Resources"`
+}
+
+// parseScim accepts either a SCIM ListResponse or a plain list of SCIM users.
+// Deactivated users are not considered part of the identity source.
+func parseScim(r io.Reader) (*Source, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []scimUser
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "[") {
+		err = json.Unmarshal(data, &users)
+	} else {
+		var list scimListResponse
+		err = json.Unmarshal(data, &list)
+		users = list.Resources
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	source := newSource()
+	for _, user := range users {
+		if user.Active != nil && !*user.Active {
+			continue
+		}
+		source.add(user.UserName)
+		for _, email := range user.Emails {
+			source.add(email.Value)
+		}
+	}
+
+	return source, nil
+}
@@ -0,0 +1,63 @@
+package identity
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCsv(t *testing.T) {
+	input := "Name,Login,Email\nJane Doe,jdoe,Jane@example.com\nJohn Roe,,john@example.com\n"
+	source, err := parseCsv(strings.NewReader(input))
+	require.Nilf(t, err, "Error parsing csv: %v", err)
+
+	require.True(t, source.Contains("JDoe"))
+	require.True(t, source.Contains("unknown", "jane@example.com"))
+	require.True(t, source.Contains("john@example.com"))
+	require.False(t, source.Contains("Jane Doe"))
+	require.False(t, source.Contains(""))
+}
+
+func TestParseCsvWithoutKnownColumns(t *testing.T) {
+	source, err := parseCsv(strings.NewReader("handle,team\njdoe,security\n"))
+	require.Nilf(t, err, "Error parsing csv: %v", err)
+
+	require.True(t, source.Contains("jdoe"))
+	require.False(t, source.Contains("security"))
+}
+
+func TestParseScim(t *testing.T) {
+	input := `{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:ListResponse"],
+		"Resources": [
+			{"userName": "jdoe", "active": true, "emails": [{"value": "jdoe@example.com"}]},
+			{"userName": "leaver", "active": false, "emails": [{"value": "leaver@example.com"}]},
+			{"userName": "noactive"}
+		]
+	}`
+	source, err := parseScim(strings.NewReader(input))
+	require.Nilf(t, err, "Error parsing scim: %v", err)
+
+	require.True(t, source.Contains("jdoe"))
+	require.True(t, source.Contains("JDOE@example.com"))
+	require.True(t, source.Contains("noactive"))
+	require.False(t, source.Contains("leaver"))
+	require.False(t, source.Contains("leaver@example.com"))
+}
+
+func TestParseScimList(t *testing.T) {
+	source, err := parseScim(strings.NewReader(`[{"userName": "jdoe"}]`))
+	require.Nilf(t, err, "Error parsing scim: %v", err)
+	require.True(t, source.Contains("jdoe"))
+}
+
+func TestLoadEmpty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.csv")
+	require.Nil(t, os.WriteFile(path, []byte("login\n"), 0600))
+
+	_, err := Load(path)
+	require.NotNil(t, err)
+}
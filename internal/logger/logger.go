@@ -0,0 +1,233 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Level int
+
+const (
+	DebugLevel Level = iota
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+)
+
+var levelNames = map[Level]string{
+	DebugLevel: "debug",
+	InfoLevel:  "info",
+	WarnLevel:  "warn",
+	ErrorLevel: "error",
+}
+
+func (l Level) String() string {
+	return levelNames[l]
+}
+
+type Format = string
+
+const (
+	TextFormat Format = "text"
+	JsonFormat Format = "json"
+)
+
+func Levels() []string {
+	return []string{DebugLevel.String(), InfoLevel.String(), WarnLevel.String(), ErrorLevel.String()}
+}
+
+func Formats() []string {
+	return []string{TextFormat, JsonFormat}
+}
+
+func ParseLevel(level string) (Level, error) {
+	for l, name := range levelNames {
+		if strings.EqualFold(name, level) {
+			return l, nil
+		}
+	}
+	return InfoLevel, fmt.Errorf("invalid log level: %s (expecting one of: %s)", level, strings.Join(Levels(), ", "))
+}
+
+func ValidateFormat(format string) error {
+	for _, f := range Formats() {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid log format: %s (expecting one of: %s)", format, strings.Join(Formats(), ", "))
+}
+
+// Fields are the structured context of an entry (e.g. the org & repo it is about)
+type Fields map[string]interface{}
+
+type sink struct {
+	lock   sync.Mutex
+	out    io.Writer
+	level  Level
+	format Format
+	fields Fields
+}
+
+var std = &sink{
+	out:    os.Stderr,
+	level:  InfoLevel,
+	format: TextFormat,
+	fields: Fields{},
+}
+
+// Init configures the logger. The standard log package is redirected to it as well (at the info level),
+// so all the log lines share the same destination & format.
+func Init(out io.Writer, level Level, format Format, fields Fields) {
+	std.lock.Lock()
+	std.out = out
+	std.level = level
+	std.format = format
+	std.fields = fields
+	std.lock.Unlock()
+
+	log.SetFlags(0)
+	log.SetPrefix("")
+	log.SetOutput(stdLogWriter{})
+}
+
+// stdLogWriter adapts the lines of the standard log package
+type stdLogWriter struct{}
+
+func (stdLogWriter) Write(p []byte) (int, error) {
+	std.write(InfoLevel, nil, strings.TrimRight(string(p), "\n"))
+	return len(p), nil
+}
+
+func (s *sink) write(level Level, fields Fields, msg string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if level < s.level {
+		return
+	}
+
+	all := make(Fields, len(s.fields)+len(fields))
+	for k, v := range s.fields {
+		all[k] = v
+	}
+	for k, v := range fields {
+		all[k] = v
+	}
+
+	var line string
+	now := time.Now().UTC().Format(time.RFC3339)
+	if s.format == JsonFormat {
+		line = jsonLine(now, level, msg, all)
+	} else {
+		line = textLine(now, level, msg, all)
+	}
+	_, _ = io.WriteString(s.out, line+"\n")
+}
+
+func sortedKeys(fields Fields) []string {
+	var keys []string
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func textLine(now string, level Level, msg string, fields Fields) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %-5s %s", now, strings.ToUpper(level.String()), msg))
+	for _, k := range sortedKeys(fields) {
+		sb.WriteString(fmt.Sprintf(" %s=%v", k, fields[k]))
+	}
+	return sb.String()
+}
+
+func jsonLine(now string, level Level, msg string, fields Fields) string {
+	entry := make(map[string]interface{}, len(fields)+3)
+	for k, v := range fields {
+		if err, ok := v.(error); ok {
+			v = err.Error()
+		}
+		entry[k] = v
+	}
+	entry["time"] = now
+	entry["level"] = level.String()
+	entry["msg"] = msg
+
+	encoded, err := json.Marshal(entry)
+	if err != nil {
+		return textLine(now, level, msg, fields)
+	}
+	return string(encoded)
+}
+
+// Entry is a log entry with fields, created by With
+type Entry struct {
+	fields Fields
+}
+
+func With(fields Fields) *Entry {
+	return &Entry{fields: fields}
+}
+
+// WithError is a shorthand for an entry with the error field
+func WithError(err error) *Entry {
+	return With(Fields{"error": err})
+}
+
+// WithError adds the error field to the entry
+func (e *Entry) WithError(err error) *Entry {
+	return e.With(Fields{"error": err})
+}
+
+// With adds fields to the entry
+func (e *Entry) With(fields Fields) *Entry {
+	merged := make(Fields, len(e.fields)+len(fields))
+	for k, v := range e.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return &Entry{fields: merged}
+}
+
+func (e *Entry) Debugf(format string, args ...interface{}) {
+	std.write(DebugLevel, e.fields, fmt.Sprintf(format, args...))
+}
+
+func (e *Entry) Infof(format string, args ...interface{}) {
+	std.write(InfoLevel, e.fields, fmt.Sprintf(format, args...))
+}
+
+func (e *Entry) Warnf(format string, args ...interface{}) {
+	std.write(WarnLevel, e.fields, fmt.Sprintf(format, args...))
+}
+
+func (e *Entry) Errorf(format string, args ...interface{}) {
+	std.write(ErrorLevel, e.fields, fmt.Sprintf(format, args...))
+}
+
+func Debugf(format string, args ...interface{}) {
+	std.write(DebugLevel, nil, fmt.Sprintf(format, args...))
+}
+
+func Infof(format string, args ...interface{}) {
+	std.write(InfoLevel, nil, fmt.Sprintf(format, args...))
+}
+
+func Warnf(format string, args ...interface{}) {
+	std.write(WarnLevel, nil, fmt.Sprintf(format, args...))
+}
+
+func Errorf(format string, args ...interface{}) {
+	std.write(ErrorLevel, nil, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJsonFormat(t *testing.T) {
+	var buf bytes.Buffer
+	Init(&buf, InfoLevel, JsonFormat, Fields{"scan_id": "1234"})
+
+	With(Fields{"org": "org1", "repo": "org1/repo"}).WithError(errors.New("boom")).Errorf("error getting repository hooks")
+	Debugf("filtered out")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 1)
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	require.Equal(t, "error", entry["level"])
+	require.Equal(t, "error getting repository hooks", entry["msg"])
+	require.Equal(t, "org1", entry["org"])
+	require.Equal(t, "org1/repo", entry["repo"])
+	require.Equal(t, "boom", entry["error"])
+	require.Equal(t, "1234", entry["scan_id"])
+}
+
+func TestTextFormat(t *testing.T) {
+	var buf bytes.Buffer
+	Init(&buf, DebugLevel, TextFormat, nil)
+
+	With(Fields{"org": "org1"}).Warnf("missing %s", "permissions")
+	log.Printf("from the standard logger")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	require.Contains(t, lines[0], "WARN  missing permissions org=org1")
+	require.Contains(t, lines[1], "INFO  from the standard logger")
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := ParseLevel("WARN")
+	require.NoError(t, err)
+	require.Equal(t, WarnLevel, level)
+
+	_, err = ParseLevel("verbose")
+	require.Error(t, err)
+	require.Error(t, ValidateFormat("xml"))
+}
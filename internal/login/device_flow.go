@@ -0,0 +1,200 @@
+package login
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	deviceGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+	// slowDownInterval is added to the polling interval when the server asks to slow down
+	slowDownInterval = 5 * time.Second
+)
+
+// DefaultScopes are the read-only scopes of a scan of the public repositories
+var DefaultScopes = []string{"public_repo", "read:org", "read:repo_hook", "read:packages", "read:project"}
+
+// PrivateRepoScope is required to scan the private repositories; it has no read-only alternative, so it also grants write access
+// to all the repositories of the user
+const PrivateRepoScope = "repo"
+
+// DefaultTokenLifetime is how long a stored token is used before the login has to be repeated,
+// like the tokens of GitHub Apps (with user token expiration), which GitHub expires after 8 hours.
+// The tokens of OAuth apps don't expire on GitHub, so they are revoked when they expire.
+const DefaultTokenLifetime = 8 * time.Hour
+
+// Scopes returns the scopes to request, with the repo scope (instead of public_repo) only when the private repositories are scanned
+func Scopes(privateRepos bool) []string {
+	if !privateRepos {
+		return DefaultScopes
+	}
+	scopes := []string{PrivateRepoScope}
+	for _, scope := range DefaultScopes {
+		if scope != "public_repo" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// DeviceCode is the code the user enters at the verification URI to authorize the device
+type DeviceCode struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURI string `json:"verification_uri"`
+	ExpiresIn       int    `json:"expires_in"`
+	Interval        int    `json:"interval"`
+}
+
+// Token is the access token the device flow grants; ExpiresAt is zero for tokens that don't expire
+type Token struct {
+	AccessToken string    `json:"access_token"`
+	Scopes      string    `json:"scopes,omitempty"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	// ClientID is the app that granted the token, to revoke it
+	ClientID string `json:"client_id,omitempty"`
+	// ServerExpiry is whether GitHub expires the token by itself (the tokens of GitHub Apps with user token expiration)
+	ServerExpiry bool `json:"server_expiry,omitempty"`
+}
+
+func (t Token) Expired() bool {
+	return !t.ExpiresAt.IsZero() && time.Now().After(t.ExpiresAt)
+}
+
+// LimitLifetime makes the token expire after the lifetime, unless it expires earlier
+func (t *Token) LimitLifetime(lifetime time.Duration) {
+	if lifetime <= 0 {
+		return
+	}
+	if expiresAt := time.Now().Add(lifetime); t.ExpiresAt.IsZero() || expiresAt.Before(t.ExpiresAt) {
+		t.ExpiresAt = expiresAt
+	}
+}
+
+// DeviceFlow implements GitHub's OAuth device authorization flow
+type DeviceFlow struct {
+	// WebURL is the GitHub web URL, e.g. https://github.com (or the GitHub Enterprise Server URL)
+	WebURL   string
+	ClientID string
+	Scopes   []string
+	client   *http.Client
+	sleep    func(time.Duration)
+}
+
+func NewDeviceFlow(webURL string, clientID string, scopes []string) *DeviceFlow {
+	if webURL == "" {
+		webURL = "https://github.com"
+	}
+	return &DeviceFlow{
+		WebURL:   strings.TrimRight(webURL, "/"),
+		ClientID: clientID,
+		Scopes:   scopes,
+		client:   &http.Client{Timeout: 30 * time.Second},
+		sleep:    time.Sleep,
+	}
+}
+
+// RequestCode starts the flow: the user should enter the returned user code at the verification URI
+func (f *DeviceFlow) RequestCode(ctx context.Context) (*DeviceCode, error) {
+	form := url.Values{}
+	form.Set("client_id", f.ClientID)
+	form.Set("scope", strings.Join(f.Scopes, " "))
+
+	var resp struct {
+		DeviceCode
+		OAuthError
+	}
+	if err := f.post(ctx, "/login/device/code", form, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Code != "" {
+		return nil, &resp.OAuthError
+	}
+	if resp.DeviceCode.DeviceCode == "" {
+		return nil, fmt.Errorf("the device authorization flow isn't enabled for the OAuth app")
+	}
+	return &resp.DeviceCode, nil
+}
+
+// PollToken waits until the user authorizes (or denies) the device, or until the code expires
+func (f *DeviceFlow) PollToken(ctx context.Context, code *DeviceCode) (*Token, error) {
+	interval := time.Duration(code.Interval) * time.Second
+	deadline := time.Now().Add(time.Duration(code.ExpiresIn) * time.Second)
+
+	form := url.Values{}
+	form.Set("client_id", f.ClientID)
+	form.Set("device_code", code.DeviceCode)
+	form.Set("grant_type", deviceGrantType)
+
+	for {
+		f.sleep(interval)
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("the device code expired before it was authorized, run the login again")
+		}
+
+		var resp struct {
+			AccessToken string `json:"access_token"`
+			Scope       string `json:"scope"`
+			ExpiresIn   int    `json:"expires_in"`
+			OAuthError
+		}
+		if err := f.post(ctx, "/login/oauth/access_token", form, &resp); err != nil {
+			return nil, err
+		}
+
+		switch resp.Code {
+		case "":
+			token := &Token{AccessToken: resp.AccessToken, Scopes: resp.Scope, ClientID: f.ClientID}
+			// only GitHub App tokens (with token expiration enabled) expire
+			if resp.ExpiresIn > 0 {
+				token.ExpiresAt = time.Now().Add(time.Duration(resp.ExpiresIn) * time.Second)
+				token.ServerExpiry = true
+			}
+			return token, nil
+		case "authorization_pending":
+		case "slow_down":
+			interval += slowDownInterval
+		default:
+			return nil, &resp.OAuthError
+		}
+	}
+}
+
+// OAuthError is an error response of the device flow (e.g. access_denied or expired_token)
+type OAuthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *OAuthError) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("%s: %s", e.Code, e.Description)
+	}
+	return e.Code
+}
+
+func (f *DeviceFlow) post(ctx context.Context, path string, form url.Values, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.WebURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// the errors of the flow are reported in the response body (with either status)
+	if err = json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("unexpected device authorization response (%s): %v", resp.Status, err)
+	}
+	return nil
+}
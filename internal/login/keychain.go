@@ -0,0 +1,132 @@
+package login
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+const keychainService = "legitify"
+
+// ErrNotFound is returned when no token was stored for the host
+var ErrNotFound = errors.New("no stored token")
+
+// runCommand runs a keychain command with the given stdin and returns its stdout (replaced in the tests)
+var runCommand = func(stdin string, name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s failed: %v %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// StoreToken stores the token (with its expiry) of the host (e.g. github.com) in the OS keychain:
+// the macOS keychain (security), or the Secret Service on Linux (secret-tool, e.g. GNOME Keyring or KWallet).
+// The secret-tool secret is passed through stdin; security only takes it as an argument.
+func StoreToken(host string, token Token) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	// encoded, so it doesn't have to be quoted
+	secret := base64.StdEncoding.EncodeToString(data)
+
+	switch runtime.GOOS {
+	case "darwin":
+		_, err = runCommand("", "security", "add-generic-password", "-U", "-s", keychainService, "-a", host, "-w", secret)
+	case "linux":
+		_, err = runCommand(secret, "secret-tool", "store", "--label", "legitify token ("+host+")", "service", keychainService, "account", host)
+	default:
+		err = unsupportedError()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to store the token in the OS keychain: %v", err)
+	}
+
+	// the token is read back to make sure it was stored
+	stored, err := LoadToken(host)
+	if err != nil || stored.AccessToken != token.AccessToken {
+		return fmt.Errorf("failed to store the token in the OS keychain (it couldn't be read back)")
+	}
+	return nil
+}
+
+// LoadToken reads the token of the host from the OS keychain (ErrNotFound if none was stored)
+func LoadToken(host string) (*Token, error) {
+	var secret string
+	var err error
+	switch runtime.GOOS {
+	case "darwin":
+		secret, err = runCommand("", "security", "find-generic-password", "-s", keychainService, "-a", host, "-w")
+	case "linux":
+		secret, err = runCommand("", "secret-tool", "lookup", "service", keychainService, "account", host)
+	default:
+		return nil, unsupportedError()
+	}
+	if err != nil || strings.TrimSpace(secret) == "" {
+		// both tools fail when the item doesn't exist
+		return nil, ErrNotFound
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(secret))
+	if err != nil {
+		return nil, fmt.Errorf("invalid stored token: %v", err)
+	}
+	var token Token
+	if err = json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("invalid stored token: %v", err)
+	}
+	return &token, nil
+}
+
+// DeleteToken removes the token of the host from the OS keychain
+func DeleteToken(host string) error {
+	var err error
+	switch runtime.GOOS {
+	case "darwin":
+		_, err = runCommand("", "security", "delete-generic-password", "-s", keychainService, "-a", host)
+	case "linux":
+		_, err = runCommand("", "secret-tool", "clear", "service", keychainService, "account", host)
+	default:
+		err = unsupportedError()
+	}
+	return err
+}
+
+// CheckSupported fails on the systems without a supported keychain (e.g. Windows, whose Credential Manager isn't supported)
+func CheckSupported() error {
+	switch runtime.GOOS {
+	case "darwin", "linux":
+		return nil
+	}
+	return unsupportedError()
+}
+
+func unsupportedError() error {
+	return fmt.Errorf("storing the token in the OS keychain isn't supported on %s (use --token-command with your secret manager instead)", runtime.GOOS)
+}
+
+// Host returns the keychain account of the GitHub endpoint (github.com for GitHub Cloud)
+func Host(endpoint string) (string, error) {
+	if endpoint == "" {
+		return "github.com", nil
+	}
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid GitHub Enterprise Server URL %s: %v", endpoint, err)
+	}
+	if parsed.Hostname() == "" {
+		return "", fmt.Errorf("invalid GitHub Enterprise Server URL %s: missing host (e.g. https://github.example.com)", endpoint)
+	}
+	return strings.ToLower(parsed.Hostname()), nil
+}
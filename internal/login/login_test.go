@@ -0,0 +1,189 @@
+package login
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceFlow(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "client", r.PostForm.Get("client_id"))
+		switch r.URL.Path {
+		case "/login/device/code":
+			require.Equal(t, "repo read:org", r.PostForm.Get("scope"))
+			_ = json.NewEncoder(w).Encode(DeviceCode{DeviceCode: "device", UserCode: "ABCD-1234", VerificationURI: "https://github.com/login/device", ExpiresIn: 900, Interval: 5})
+		case "/login/oauth/access_token":
+			require.Equal(t, "device", r.PostForm.Get("device_code"))
+			polls++
+			switch polls {
+			case 1:
+				_ = json.NewEncoder(w).Encode(map[string]string{"error": "authorization_pending"})
+			case 2:
+				_ = json.NewEncoder(w).Encode(map[string]string{"error": "slow_down"})
+			default:
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "ghu_token", "scope": "repo,read:org", "expires_in": 28800})
+			}
+		}
+	}))
+	defer server.Close()
+
+	flow := NewDeviceFlow(server.URL, "client", []string{"repo", "read:org"})
+	var intervals []time.Duration
+	flow.sleep = func(d time.Duration) { intervals = append(intervals, d) }
+
+	code, err := flow.RequestCode(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "ABCD-1234", code.UserCode)
+
+	token, err := flow.PollToken(context.Background(), code)
+	require.NoError(t, err)
+	require.Equal(t, "ghu_token", token.AccessToken)
+	require.Equal(t, "client", token.ClientID)
+	require.False(t, token.Expired())
+	require.False(t, token.ExpiresAt.IsZero())
+	require.True(t, token.ServerExpiry)
+	require.Equal(t, []time.Duration{5 * time.Second, 5 * time.Second, 10 * time.Second}, intervals)
+}
+
+func TestDeviceFlowDenied(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "access_denied", "error_description": "The authorization request was denied."})
+	}))
+	defer server.Close()
+
+	flow := NewDeviceFlow(server.URL, "client", nil)
+	flow.sleep = func(time.Duration) {}
+
+	_, err := flow.RequestCode(context.Background())
+	var oauthErr *OAuthError
+	require.ErrorAs(t, err, &oauthErr)
+
+	_, err = flow.PollToken(context.Background(), &DeviceCode{DeviceCode: "device", ExpiresIn: 900})
+	require.ErrorAs(t, err, &oauthErr)
+	require.Equal(t, "access_denied", oauthErr.Code)
+}
+
+func TestKeychain(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("the keychain is only supported on linux and macOS")
+	}
+
+	stored := map[string]string{}
+	original := runCommand
+	defer func() { runCommand = original }()
+	runCommand = func(stdin string, name string, args ...string) (string, error) {
+		switch {
+		case name == "secret-tool" && args[0] == "store":
+			stored[args[len(args)-1]] = stdin
+		case name == "secret-tool" && args[0] == "lookup":
+			return stored[args[len(args)-1]], nil
+		case name == "security" && args[0] == "add-generic-password":
+			// add-generic-password -U -s legitify -a <host> -w <secret>
+			stored[args[5]] = args[7]
+		case name == "security" && args[0] == "find-generic-password":
+			return stored[args[4]], nil
+		}
+		return "", nil
+	}
+
+	_, err := LoadToken("github.com")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, StoreToken("github.com", Token{AccessToken: "gho_token", ExpiresAt: time.Now().Add(time.Hour)}))
+	token, err := LoadToken("github.com")
+	require.NoError(t, err)
+	require.Equal(t, "gho_token", token.AccessToken)
+	require.False(t, token.Expired())
+
+	// the keychain command succeeded without storing the token
+	runCommand = func(stdin string, name string, args ...string) (string, error) {
+		return "", nil
+	}
+	require.Error(t, StoreToken("github.com", Token{AccessToken: "gho_token"}))
+
+	runCommand = func(stdin string, name string, args ...string) (string, error) {
+		return "", fmt.Errorf("%s failed: the keychain is locked", name)
+	}
+	require.ErrorContains(t, StoreToken("github.com", Token{AccessToken: "gho_token"}), "the keychain is locked")
+}
+
+func TestScopes(t *testing.T) {
+	require.Equal(t, DefaultScopes, Scopes(false))
+	require.NotContains(t, Scopes(false), PrivateRepoScope)
+
+	scopes := Scopes(true)
+	require.Contains(t, scopes, PrivateRepoScope)
+	require.NotContains(t, scopes, "public_repo")
+	require.Len(t, scopes, len(DefaultScopes))
+}
+
+func TestLimitLifetime(t *testing.T) {
+	token := Token{AccessToken: "gho_token"}
+	token.LimitLifetime(time.Hour)
+	require.WithinDuration(t, time.Now().Add(time.Hour), token.ExpiresAt, time.Minute)
+
+	// a token that expires earlier keeps its expiry
+	expiresAt := time.Now().Add(8 * time.Hour)
+	token = Token{AccessToken: "ghu_token", ExpiresAt: expiresAt}
+	token.LimitLifetime(DefaultTokenLifetime)
+	require.Equal(t, expiresAt, token.ExpiresAt)
+
+	token = Token{AccessToken: "gho_token", ExpiresAt: time.Now().Add(-time.Minute)}
+	require.True(t, token.Expired())
+}
+
+func TestHost(t *testing.T) {
+	host, err := Host("")
+	require.NoError(t, err)
+	require.Equal(t, "github.com", host)
+
+	host, err = Host("https://GitHub.example.com/")
+	require.NoError(t, err)
+	require.Equal(t, "github.example.com", host)
+
+	_, err = Host("github.example.com")
+	require.ErrorContains(t, err, "missing host")
+	_, err = Host("https://github.example.com:port")
+	require.Error(t, err)
+}
+
+func TestRevokeToken(t *testing.T) {
+	revoked := map[string]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodDelete, r.Method)
+		require.Equal(t, "/api/v3/applications/client/grant", r.URL.Path)
+		user, password, _ := r.BasicAuth()
+		require.Equal(t, "client", user)
+		require.Equal(t, "secret", password)
+
+		var body struct {
+			AccessToken string `json:"access_token"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if revoked[body.AccessToken] {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		revoked[body.AccessToken] = true
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	token := Token{AccessToken: "gho_token", ClientID: "client"}
+	require.NoError(t, RevokeToken(context.Background(), server.URL, "secret", token))
+	require.True(t, revoked["gho_token"])
+	// revoking again succeeds
+	require.NoError(t, RevokeToken(context.Background(), server.URL, "secret", token))
+
+	require.Error(t, RevokeToken(context.Background(), server.URL, "secret", Token{AccessToken: "gho_token"}))
+}
@@ -0,0 +1,61 @@
+package login
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	gh "github.com/google/go-github/v44/github"
+)
+
+// RevokeToken deletes the app's grant of the token on GitHub (which revokes all the tokens the app got from the user).
+// The OAuth application API authenticates with the app's client id and secret.
+func RevokeToken(ctx context.Context, endpoint string, clientSecret string, token Token) error {
+	if token.ClientID == "" {
+		return fmt.Errorf("the app that granted the token is unknown")
+	}
+
+	transport := &gh.BasicAuthTransport{Username: token.ClientID, Password: clientSecret}
+	httpClient := &http.Client{Transport: transport, Timeout: 30 * time.Second}
+	client := gh.NewClient(httpClient)
+	if endpoint != "" {
+		var err error
+		if client, err = gh.NewEnterpriseClient(endpoint, endpoint, httpClient); err != nil {
+			return err
+		}
+	}
+
+	resp, err := client.Authorizations.DeleteGrant(ctx, token.ClientID, token.AccessToken)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil // already revoked (or expired)
+		}
+		return fmt.Errorf("failed to revoke the token: %v", err)
+	}
+	return nil
+}
+
+// RemoveExpiredToken removes the expired token from the keychain, and revokes it on GitHub.
+// Without the client secret only the tokens that GitHub expires by itself (of GitHub Apps) are removed,
+// and the other ones fail, as they remain valid until they are revoked.
+func RemoveExpiredToken(ctx context.Context, endpoint string, host string, clientSecret string, token Token) error {
+	if err := DeleteToken(host); err != nil {
+		return err
+	}
+	if clientSecret != "" {
+		return RevokeToken(ctx, endpoint, clientSecret, token)
+	}
+	if !token.ServerExpiry {
+		return fmt.Errorf("the token is still valid on GitHub, revoke it in the applications settings of your account (%s)", applicationsSettingsURL(endpoint))
+	}
+	return nil
+}
+
+func applicationsSettingsURL(endpoint string) string {
+	if endpoint == "" {
+		endpoint = "https://github.com"
+	}
+	return strings.TrimRight(endpoint, "/") + "/settings/applications"
+}
@@ -0,0 +1,132 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type metricType = string
+
+const (
+	counterType metricType = "counter"
+	gaugeType   metricType = "gauge"
+)
+
+type Labels map[string]string
+
+func (l Labels) String() string {
+	if len(l) == 0 {
+		return ""
+	}
+
+	var keys []string
+	for k := range l {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", k, strconv.Quote(l[k])))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+type family struct {
+	help       string
+	metricType metricType
+	// values are keyed by the rendered labels
+	values map[string]float64
+}
+
+// Registry holds the metrics and renders them in the Prometheus text exposition format.
+type Registry struct {
+	lock     sync.Mutex
+	families map[string]*family
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		families: make(map[string]*family),
+	}
+}
+
+var defaultRegistry = NewRegistry()
+
+// Default is the registry the metrics of the analysis are recorded to
+func Default() *Registry {
+	return defaultRegistry
+}
+
+func (r *Registry) family(name, help string, t metricType) *family {
+	f, ok := r.families[name]
+	if !ok {
+		f = &family{help: help, metricType: t, values: make(map[string]float64)}
+		r.families[name] = f
+	}
+	return f
+}
+
+func (r *Registry) AddCounter(name, help string, labels Labels, value float64) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.family(name, help, counterType).values[labels.String()] += value
+}
+
+func (r *Registry) SetGauge(name, help string, labels Labels, value float64) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.family(name, help, gaugeType).values[labels.String()] = value
+}
+
+// ResetGauge removes all the values of a gauge (e.g. before recording a new set of findings)
+func (r *Registry) ResetGauge(name string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if f, ok := r.families[name]; ok {
+		f.values = make(map[string]float64)
+	}
+}
+
+func (r *Registry) Write(w io.Writer) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var names []string
+	for name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	for _, name := range names {
+		f := r.families[name]
+		sb.WriteString(fmt.Sprintf("# HELP %s %s\n", name, f.help))
+		sb.WriteString(fmt.Sprintf("# TYPE %s %s\n", name, f.metricType))
+
+		var series []string
+		for labels := range f.values {
+			series = append(series, labels)
+		}
+		sort.Strings(series)
+		for _, labels := range series {
+			sb.WriteString(fmt.Sprintf("%s%s %s\n", name, labels, strconv.FormatFloat(f.values[labels], 'g', -1, 64)))
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// Handler serves the metrics for Prometheus to scrape
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		_ = r.Write(w)
+	})
+}
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryWrite(t *testing.T) {
+	registry := NewRegistry()
+	registry.SetGauge("legitify_findings", "findings", Labels{"severity": "HIGH", "policy": "b"}, 2)
+	registry.SetGauge("legitify_findings", "findings", Labels{"severity": "LOW", "policy": "a"}, 1)
+	registry.AddCounter("legitify_api_calls_total", "calls", Labels{"scm": "github"}, 1)
+	registry.AddCounter("legitify_api_calls_total", "calls", Labels{"scm": "github"}, 1)
+
+	var buf bytes.Buffer
+	require.NoError(t, registry.Write(&buf))
+
+	expected := `# HELP legitify_api_calls_total calls
+# TYPE legitify_api_calls_total counter
+legitify_api_calls_total{scm="github"} 2
+# HELP legitify_findings findings
+# TYPE legitify_findings gauge
+legitify_findings{policy="a",severity="LOW"} 1
+legitify_findings{policy="b",severity="HIGH"} 2
+`
+	require.Equal(t, expected, buf.String())
+
+	registry.ResetGauge("legitify_findings")
+	buf.Reset()
+	require.NoError(t, registry.Write(&buf))
+	require.NotContains(t, buf.String(), "legitify_findings{")
+}
+
+func TestTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+	}))
+	defer server.Close()
+
+	registry := NewRegistry()
+	client := &http.Client{Transport: &transport{base: http.DefaultTransport, scm: "github", registry: registry}}
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	var buf bytes.Buffer
+	require.NoError(t, registry.Write(&buf))
+	require.Contains(t, buf.String(), `legitify_api_calls_total{scm="github",status="200"} 1`)
+	require.Contains(t, buf.String(), `legitify_rate_limit_remaining{scm="github"} 4999`)
+}
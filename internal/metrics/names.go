@@ -0,0 +1,14 @@
+package metrics
+
+import "time"
+
+const (
+	Findings           = "legitify_findings"
+	CollectionDuration = "legitify_collection_duration_seconds"
+	LastRun            = "legitify_last_run_timestamp_seconds"
+	ScanInfo           = "legitify_scan_info"
+)
+
+func (r *Registry) RecordCollectionDuration(namespace string, duration time.Duration) {
+	r.SetGauge(CollectionDuration, "Duration of the collection of a namespace", Labels{"namespace": namespace}, duration.Seconds())
+}
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+)
+
+const (
+	ApiCalls           = "legitify_api_calls_total"
+	RateLimitRemaining = "legitify_rate_limit_remaining"
+)
+
+// rate limit headers of the supported SCMs (GitHub & GitLab respectively)
+var rateLimitHeaders = []string{"X-RateLimit-Remaining", "RateLimit-Remaining"}
+
+type transport struct {
+	base     http.RoundTripper
+	scm      string
+	registry *Registry
+}
+
+// NewTransport records the API calls (and the remaining rate limit) of an SCM client
+func NewTransport(base http.RoundTripper, scm string) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{
+		base:     base,
+		scm:      scm,
+		registry: Default(),
+	}
+}
+
+func (t *transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(request)
+
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(resp.StatusCode)
+		t.recordRateLimit(resp)
+	}
+	t.registry.AddCounter(ApiCalls, "Number of API calls made to the SCM", Labels{"scm": t.scm, "status": status}, 1)
+
+	return resp, err
+}
+
+func (t *transport) recordRateLimit(resp *http.Response) {
+	for _, header := range rateLimitHeaders {
+		value := resp.Header.Get(header)
+		if value == "" {
+			continue
+		}
+		if remaining, err := strconv.ParseFloat(value, 64); err == nil {
+			t.registry.SetGauge(RateLimitRemaining, "Remaining API rate limit of the token", Labels{"scm": t.scm}, remaining)
+		}
+		return
+	}
+}
@@ -0,0 +1,91 @@
+package network
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// Options are the TLS and proxy settings of the connections to the SCM endpoint,
+// for servers behind corporate (TLS-intercepting) proxies
+type Options struct {
+	// CABundle is a PEM file of certificate authorities that are trusted in addition to the system ones
+	CABundle string
+	// ClientCert and ClientKey are the PEM files of a client certificate for mutual TLS
+	ClientCert string
+	ClientKey  string
+	// Proxy is the URL of an HTTP(S) proxy, overriding the HTTPS_PROXY/HTTP_PROXY environment variables
+	Proxy string
+}
+
+func (o Options) IsSet() bool {
+	return o.CABundle != "" || o.ClientCert != "" || o.ClientKey != "" || o.Proxy != ""
+}
+
+// NewTransport returns a copy of the default transport with the TLS and proxy settings
+func NewTransport(options Options) (*http.Transport, error) {
+	base, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("the default transport was already replaced")
+	}
+	transport := base.Clone()
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if transport.TLSClientConfig != nil {
+		tlsConfig = transport.TLSClientConfig.Clone()
+	}
+
+	if options.CABundle != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		pem, err := os.ReadFile(options.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the CA bundle: %v", err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in the CA bundle %s", options.CABundle)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if options.ClientCert != "" || options.ClientKey != "" {
+		if options.ClientCert == "" || options.ClientKey == "" {
+			return nil, fmt.Errorf("the client certificate requires both a certificate and a key file")
+		}
+		certificate, err := tls.LoadX509KeyPair(options.ClientCert, options.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	if options.Proxy != "" {
+		proxy, err := url.Parse(options.Proxy)
+		if err != nil || proxy.Host == "" {
+			return nil, fmt.Errorf("invalid proxy url %s", options.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// Configure replaces the default transport with one with the TLS and proxy settings, for the rest of the process.
+// It must be called before the clients are created (and before the offline mode is enabled, which wraps it).
+func Configure(options Options) error {
+	if !options.IsSet() {
+		return nil
+	}
+
+	transport, err := NewTransport(options)
+	if err != nil {
+		return err
+	}
+	http.DefaultTransport = transport
+	return nil
+}
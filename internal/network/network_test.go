@@ -0,0 +1,52 @@
+package network
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client := http.Client{Transport: http.DefaultTransport}
+	_, err := client.Get(server.URL)
+	require.Error(t, err)
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(bundle, certificate, 0600))
+
+	transport, err := NewTransport(Options{CABundle: bundle})
+	require.NoError(t, err)
+	client = http.Client{Transport: transport}
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+}
+
+func TestInvalidOptions(t *testing.T) {
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	require.NoError(t, os.WriteFile(empty, []byte{}, 0600))
+
+	_, err := NewTransport(Options{CABundle: empty})
+	require.Error(t, err)
+
+	_, err = NewTransport(Options{ClientCert: empty})
+	require.Error(t, err)
+
+	_, err = NewTransport(Options{Proxy: "proxy.example.com"})
+	require.Error(t, err)
+
+	transport, err := NewTransport(Options{Proxy: "http://proxy.example.com:3128"})
+	require.NoError(t, err)
+	proxy, err := transport.Proxy(httptest.NewRequest(http.MethodGet, "https://api.github.com", nil))
+	require.NoError(t, err)
+	require.Equal(t, "proxy.example.com:3128", proxy.Host)
+}
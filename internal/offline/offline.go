@@ -0,0 +1,119 @@
+package offline
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/Legit-Labs/legitify/internal/clients/codecommit"
+	"github.com/Legit-Labs/legitify/internal/common/scm_type"
+)
+
+// the hosts of the cloud APIs, used when no endpoint is configured
+var cloudHosts = map[scm_type.ScmType][]string{
+	scm_type.GitHub: {"api.github.com", "uploads.github.com"},
+	scm_type.GitLab: {"gitlab.com"},
+}
+
+var (
+	enabled bool
+	lock    sync.Mutex
+)
+
+// BlockedError is returned for the requests to hosts other than the SCM endpoint in offline mode
+type BlockedError struct {
+	Host string
+}
+
+func (e *BlockedError) Error() string {
+	return fmt.Sprintf("offline mode: blocked an outbound request to %s (only the SCM endpoint is allowed)", e.Host)
+}
+
+// AllowedHosts returns the hosts of the SCM endpoint (the cloud API if the endpoint is empty)
+func AllowedHosts(scmType scm_type.ScmType, endpoint string) ([]string, error) {
+	if scmType == scm_type.CodeCommit {
+		return codeCommitHosts(endpoint)
+	}
+
+	if endpoint == "" {
+		return cloudHosts[scmType], nil
+	}
+
+	parsed, err := url.Parse(endpoint)
+	if err != nil || parsed.Hostname() == "" {
+		return nil, fmt.Errorf("invalid endpoint %s", endpoint)
+	}
+	return []string{strings.ToLower(parsed.Hostname())}, nil
+}
+
+// codeCommitHosts returns the CodeCommit endpoint (the regional endpoint if the endpoint is empty) and the (global) IAM endpoint
+func codeCommitHosts(endpoint string) ([]string, error) {
+	if endpoint == "" {
+		endpoint = codecommit.Endpoint(codecommit.Region())
+	}
+	parsed, err := url.Parse(endpoint)
+	if err != nil || parsed.Hostname() == "" {
+		return nil, fmt.Errorf("invalid endpoint %s", endpoint)
+	}
+	return []string{strings.ToLower(parsed.Hostname()), "iam.amazonaws.com"}, nil
+}
+
+// Enable blocks the outbound requests to any host other than the SCM endpoint (and the given extra hosts, e.g. of the
+// credentials provider), for the rest of the process.
+// It replaces the default transport, so it must be called before the clients are created.
+func Enable(scmType scm_type.ScmType, endpoint string, extraHosts ...string) error {
+	hosts, err := AllowedHosts(scmType, endpoint)
+	if err != nil {
+		return err
+	}
+	for _, host := range extraHosts {
+		if host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	if !enabled {
+		http.DefaultTransport = NewTransport(http.DefaultTransport, hosts)
+		enabled = true
+	}
+	return nil
+}
+
+// Enabled returns whether the offline mode is enabled
+func Enabled() bool {
+	lock.Lock()
+	defer lock.Unlock()
+	return enabled
+}
+
+type transport struct {
+	base  http.RoundTripper
+	hosts map[string]bool
+}
+
+// NewTransport returns a transport that only sends the requests to the given hosts
+func NewTransport(base http.RoundTripper, hosts []string) http.RoundTripper {
+	allowed := make(map[string]bool)
+	for _, host := range hosts {
+		allowed[strings.ToLower(host)] = true
+	}
+	return &transport{
+		base:  base,
+		hosts: allowed,
+	}
+}
+
+func (t *transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	host := strings.ToLower(request.URL.Hostname())
+	if !t.hosts[host] {
+		if request.Body != nil {
+			_ = request.Body.Close()
+		}
+		return nil, &BlockedError{Host: host}
+	}
+	return t.base.RoundTrip(request)
+}
@@ -0,0 +1,45 @@
+package offline
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Legit-Labs/legitify/internal/common/scm_type"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllowedHosts(t *testing.T) {
+	hosts, err := AllowedHosts(scm_type.GitHub, "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"api.github.com", "uploads.github.com"}, hosts)
+
+	hosts, err = AllowedHosts(scm_type.GitHub, "https://GHES.example.com:8443")
+	require.NoError(t, err)
+	require.Equal(t, []string{"ghes.example.com"}, hosts)
+
+	_, err = AllowedHosts(scm_type.GitLab, "gitlab.example.com")
+	require.Error(t, err)
+
+	t.Setenv("AWS_REGION", "eu-west-1")
+	hosts, err = AllowedHosts(scm_type.CodeCommit, "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"codecommit.eu-west-1.amazonaws.com", "iam.amazonaws.com"}, hosts)
+}
+
+func TestTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client := http.Client{Transport: NewTransport(http.DefaultTransport, []string{"127.0.0.1"})}
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	client = http.Client{Transport: NewTransport(http.DefaultTransport, []string{"api.github.com"})}
+	_, err = client.Get(server.URL)
+	var blocked *BlockedError
+	require.True(t, errors.As(err, &blocked))
+	require.Equal(t, "127.0.0.1", blocked.Host)
+}
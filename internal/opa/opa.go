@@ -50,12 +50,43 @@ func Load(policyPaths []string, scm scm_type.ScmType) (opa_engine.Enginer, error
 	return engine, nil
 }
 
+// VerifyBundles makes sure the embedded policies of all the SCM types are intact (parse & compile)
+func VerifyBundles() error {
+	for _, scmType := range scm_type.All {
+		modules, err := loadModules(scmType)
+		if err != nil {
+			return fmt.Errorf("invalid %s policies: %w", scmType, err)
+		}
+		if len(modules) == 0 {
+			return fmt.Errorf("no %s policies are embedded", scmType)
+		}
+
+		compiler := ast.NewCompiler()
+		compiler.Compile(moduleMap(modules))
+		if compiler.Failed() {
+			return fmt.Errorf("invalid %s policies: %w", scmType, compiler.Errors)
+		}
+	}
+
+	return nil
+}
+
+func moduleMap(modules []*ast.Module) map[string]*ast.Module {
+	result := make(map[string]*ast.Module, len(modules))
+	for _, m := range modules {
+		result[m.Package.Location.File] = m
+	}
+	return result
+}
+
 func loadModules(scmType scm_type.ScmType) ([]*ast.Module, error) {
 	switch scmType {
 	case scm_type.GitHub:
 		return loadModulesFromFs(policies.GitHubBundle, path.Dir(""))
 	case scm_type.GitLab:
 		return loadModulesFromFs(policies.GitLabBundle, path.Dir(""))
+	case scm_type.CodeCommit:
+		return loadModulesFromFs(policies.CodeCommitBundle, path.Dir(""))
 	default:
 		return nil, fmt.Errorf("unknown scm type %s", scmType)
 	}
@@ -6,6 +6,8 @@ import (
 	"os"
 	"strings"
 
+	"github.com/Legit-Labs/legitify/internal/confidence"
+	"github.com/Legit-Labs/legitify/internal/logger"
 	"github.com/open-policy-agent/opa/ast"
 	"github.com/open-policy-agent/opa/rego"
 	"github.com/open-policy-agent/opa/topdown"
@@ -13,6 +15,7 @@ import (
 
 type Enginer interface {
 	Query(ctx context.Context, namespace string, input interface{}) ([]QueryResult, error)
+	Explain(ctx context.Context, namespace string, policyName string, input interface{}) (*Explanation, error)
 	SetTracing(enabled bool)
 	Namespaces() []string
 	Modules() map[string]*ast.Module
@@ -32,6 +35,8 @@ type QueryResult struct {
 	Annotations              *ast.Annotations
 	ExtraData                interface{}
 	IsViolation              bool
+	// Confidence is set by the policy_confidence rule of the package when the policy input was partially collected
+	Confidence *confidence.Confidence
 }
 
 type enginer struct {
@@ -107,16 +112,19 @@ func (engine *enginer) parseResultsSet(rs rego.ResultSet) []QueryResult {
 		for _, exp := range r.Expressions {
 			baseModule := exp.Text
 			matchedPolicies := parseResults(exp.Value, baseModule)
+			confidences := parseConfidences(exp.Value, baseModule)
 
 			for _, m := range matchedPolicies {
 				match := m.fullPolicyName
 				split := strings.Split(match, ".")
+				policyName := split[len(split)-1]
 				current := QueryResult{
 					FullyQualifiedPolicyName: match,
-					PolicyName:               split[len(split)-1],
+					PolicyName:               policyName,
 					Annotations:              engine.findAnnotation(match),
 					ExtraData:                m.extraData,
 					IsViolation:              m.violation,
+					Confidence:               confidences[policyName],
 				}
 				result = append(result, current)
 			}
@@ -147,25 +155,52 @@ func parseResults(i interface{}, path string) []matchedPolicy {
 	mapped := i.(map[string]interface{})
 
 	for k, v := range mapped {
-		fullPath := fmt.Sprintf("%s.%s", path, k)
-
-		violation := true
-		// policies that return value but didn't have a match returns an empty map and should be ignored
-		extra, ok := v.(map[string]interface{})
-		if ok && len(extra) == 0 {
-			violation = false
-		}
-		violated, ok := v.(bool)
-		if ok && !violated {
-			violation = false
+		if k == confidence.PolicyRule {
+			continue
 		}
+		fullPath := fmt.Sprintf("%s.%s", path, k)
 
 		result = append(result, matchedPolicy{
 			fullPolicyName: fullPath,
 			extraData:      v,
-			violation:      violation,
+			violation:      isViolation(v),
 		})
 	}
 
 	return result
 }
+
+// parseConfidences returns the confidence of the package policies, by policy name (see confidence.PolicyRule)
+func parseConfidences(i interface{}, path string) map[string]*confidence.Confidence {
+	confidences := make(map[string]*confidence.Confidence)
+	mapped, _ := i.(map[string]interface{})
+	rule, ok := mapped[confidence.PolicyRule].(map[string]interface{})
+	if !ok {
+		return confidences
+	}
+
+	for policyName, value := range rule {
+		c, err := confidence.Parse(value)
+		if err != nil {
+			logger.With(logger.Fields{"policy": fmt.Sprintf("%s.%s", path, policyName)}).WithError(err).Errorf("ignoring the policy confidence")
+			continue
+		}
+		confidences[policyName] = c
+	}
+
+	return confidences
+}
+
+func isViolation(v interface{}) bool {
+	// policies that return value but didn't have a match returns an empty map and should be ignored
+	extra, ok := v.(map[string]interface{})
+	if ok && len(extra) == 0 {
+		return false
+	}
+	violated, ok := v.(bool)
+	if ok && !violated {
+		return false
+	}
+
+	return true
+}
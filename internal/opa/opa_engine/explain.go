@@ -0,0 +1,146 @@
+package opa_engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/rego"
+	"github.com/open-policy-agent/opa/topdown"
+)
+
+// Explanation is the result of re-evaluating a single policy against a single input
+type Explanation struct {
+	FullyQualifiedPolicyName string
+	Annotations              *ast.Annotations
+	// Result is the value of the policy rule (nil if the rule is undefined for the input)
+	Result      interface{}
+	IsViolation bool
+	InputFields []InputField
+	// Trace is the pretty-printed trace of the rego evaluation
+	Trace string
+}
+
+// InputField is an input field referenced by the policy rules, and its value in the evaluated input
+type InputField struct {
+	Path    string
+	Value   interface{}
+	Defined bool
+}
+
+func (engine *enginer) Explain(ctx context.Context, namespace string, policyName string, input interface{}) (*Explanation, error) {
+	fullName := fmt.Sprintf("data.%s.%s", namespace, policyName)
+	ref, err := ast.ParseRef(fullName)
+	if err != nil {
+		return nil, fmt.Errorf("invalid policy name %s: %v", policyName, err)
+	}
+
+	rules := engine.compiler.GetRulesExact(ref)
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("policy %s.%s not found", namespace, policyName)
+	}
+
+	// evaluate (and look up the fields of) the input as the policies see it: a json document
+	document, err := toDocument(input)
+	if err != nil {
+		return nil, err
+	}
+
+	tracer := topdown.NewBufferTracer()
+	resultSet, err := rego.New(
+		rego.Query(fullName),
+		rego.Input(document),
+		rego.Compiler(engine.compiler),
+		rego.QueryTracer(tracer),
+		rego.StrictBuiltinErrors(true),
+	).Eval(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query eval: %w", err)
+	}
+
+	var trace strings.Builder
+	topdown.PrettyTraceWithLocation(&trace, *tracer)
+
+	explanation := &Explanation{
+		FullyQualifiedPolicyName: fullName,
+		Annotations:              engine.findAnnotation(fullName),
+		InputFields:              inputFields(rules, document),
+		Trace:                    trace.String(),
+	}
+	if len(resultSet) > 0 && len(resultSet[0].Expressions) > 0 {
+		explanation.Result = resultSet[0].Expressions[0].Value
+		explanation.IsViolation = isViolation(explanation.Result)
+	}
+
+	return explanation, nil
+}
+
+func toDocument(input interface{}) (interface{}, error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the input: %v", err)
+	}
+
+	var document interface{}
+	if err = json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the input: %v", err)
+	}
+
+	return document, nil
+}
+
+// inputFields finds the input fields referenced by the rules (up to the first non-constant part of each reference)
+func inputFields(rules []*ast.Rule, document interface{}) []InputField {
+	paths := make(map[string][]string)
+	for _, rule := range rules {
+		ast.WalkRefs(rule, func(ref ast.Ref) bool {
+			if !ref.HasPrefix(ast.InputRootRef) {
+				return false
+			}
+
+			path := []string{}
+			for _, term := range ref[1:] {
+				key, ok := term.Value.(ast.String)
+				if !ok {
+					break
+				}
+				path = append(path, string(key))
+			}
+			paths[strings.Join(append([]string{"input"}, path...), ".")] = path
+			return false
+		})
+	}
+
+	var result []InputField
+	for name, path := range paths {
+		value, defined := lookup(document, path)
+		result = append(result, InputField{
+			Path:    name,
+			Value:   value,
+			Defined: defined,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Path < result[j].Path
+	})
+
+	return result
+}
+
+func lookup(document interface{}, path []string) (interface{}, bool) {
+	current := document
+	for _, key := range path {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = object[key]; !ok {
+			return nil, false
+		}
+	}
+
+	return current, true
+}
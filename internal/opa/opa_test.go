@@ -32,3 +32,37 @@ func TestEngineSanity(t *testing.T) {
 		log.Println(result)
 	}
 }
+
+func TestVerifyBundles(t *testing.T) {
+	if err := opa.VerifyBundles(); err != nil {
+		t.Errorf("embedded policies are invalid: %s", err)
+	}
+}
+
+func TestEngineExplain(t *testing.T) {
+	engine, err := opa.Load([]string{"./testdata"}, scm_type.GitHub)
+	if err != nil {
+		t.Fatalf("Unable to engine with policies")
+	}
+
+	explanation, err := engine.Explain(context.Background(), "test", "bla_bla2_test", map[string]interface{}{
+		"bla": "o2k",
+	})
+	if err != nil {
+		t.Fatalf("Failed to explain policy: %s", err)
+	}
+
+	if !explanation.IsViolation {
+		t.Errorf("expected the policy to be violated, got %v", explanation.Result)
+	}
+	if len(explanation.InputFields) != 1 || explanation.InputFields[0].Path != "input.bla" || explanation.InputFields[0].Value != "o2k" {
+		t.Errorf("unexpected input fields: %v", explanation.InputFields)
+	}
+	if explanation.Trace == "" {
+		t.Errorf("expected an evaluation trace")
+	}
+
+	if _, err = engine.Explain(context.Background(), "test", "no_such_policy", nil); err == nil {
+		t.Errorf("expected an error for an unknown policy")
+	}
+}
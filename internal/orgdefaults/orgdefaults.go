@@ -0,0 +1,151 @@
+package orgdefaults
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/Legit-Labs/legitify/internal/analyzers"
+)
+
+// Repository is the organization repository whose files are used as the defaults of the organization repositories
+const Repository = ".github"
+
+// File is a recommended default file of the organization's .github repository
+type File struct {
+	Path    string `yaml:"path" json:"path"`
+	Content string `yaml:"-" json:"-"`
+	// Policies are the failing policies the file helps to fix
+	Policies []string `yaml:"policies" json:"policies"`
+}
+
+// Proposal is the set of default files proposed to the organization
+type Proposal struct {
+	Organization string `yaml:"organization" json:"organization"`
+	Files        []File `yaml:"files" json:"files"`
+}
+
+// template is a default file, and the policies whose violations it helps to fix
+type template struct {
+	path     string
+	content  string
+	policies []string
+}
+
+var templates = []template{
+	{
+		path:    "SECURITY.md",
+		content: securityPolicy,
+		policies: []string{
+			"repository.vulnerability_alerts_not_enabled",
+			"repository.dependabot_security_updates_not_enabled",
+			"repository.dependency_graph_not_enabled",
+			"repository.secret_scanning_not_enabled",
+			"repository.suspicious_files_present",
+		},
+	},
+	{
+		path:    "workflow-templates/secure-ci.yml",
+		content: ciWorkflow,
+		policies: []string{
+			"actions.token_default_permissions_is_read_write",
+			"actions.third_party_actions_not_pinned",
+			"repository.token_default_permissions_is_read_write",
+			"repository.unpinned_third_party_actions",
+			"repository.pull_request_target_workflow_is_dangerous",
+		},
+	},
+	{
+		path:    "workflow-templates/secure-ci.properties.json",
+		content: ciWorkflowProperties,
+		policies: []string{
+			"actions.token_default_permissions_is_read_write",
+			"actions.third_party_actions_not_pinned",
+			"repository.token_default_permissions_is_read_write",
+			"repository.unpinned_third_party_actions",
+			"repository.pull_request_target_workflow_is_dangerous",
+		},
+	},
+	{
+		path:    "templates/dependabot.yml",
+		content: dependabotConfig,
+		policies: []string{
+			"repository.dependabot_version_updates_not_configured",
+			"repository.dependabot_does_not_update_actions",
+		},
+	},
+}
+
+type serializedViolation struct {
+	CanonicalLink string                 `json:"canonicalLink"`
+	Status        analyzers.PolicyStatus `json:"Status"`
+}
+
+type serializedOutputData struct {
+	Violations []serializedViolation `json:"violations"`
+}
+
+// FailingPoliciesFromFlattenedJson returns the policies that fail for the organization or any of its entities
+// in a legitify json output (flattened scheme).
+func FailingPoliciesFromFlattenedJson(data []byte, org string) ([]string, error) {
+	var findings map[string]serializedOutputData
+	if err := json.Unmarshal(data, &findings); err != nil {
+		return nil, fmt.Errorf("failed to parse results (expecting the json format with the flattened scheme): %v", err)
+	}
+
+	var failing []string
+	for policy, outputData := range findings {
+		for _, v := range outputData.Violations {
+			if v.Status == analyzers.PolicyFailed && belongsTo(v.CanonicalLink, org) {
+				failing = append(failing, policy)
+				break
+			}
+		}
+	}
+	sort.Strings(failing)
+
+	return failing, nil
+}
+
+// belongsTo returns whether the entity link is of the organization (or of one of its entities)
+func belongsTo(link string, org string) bool {
+	u, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) > 1 && (parts[0] == "orgs" || parts[0] == "organizations") {
+		// the organization settings links (e.g. /organizations/<org>/settings/actions)
+		parts = parts[1:]
+	}
+	return strings.EqualFold(parts[0], org)
+}
+
+// Propose returns the default files that help to fix the failing policies
+func Propose(org string, failing []string) *Proposal {
+	isFailing := make(map[string]bool)
+	for _, policy := range failing {
+		isFailing[policy] = true
+	}
+
+	proposal := &Proposal{Organization: org, Files: []File{}}
+	for _, t := range templates {
+		var policies []string
+		for _, policy := range t.policies {
+			if isFailing[policy] {
+				policies = append(policies, policy)
+			}
+		}
+		if len(policies) > 0 {
+			proposal.Files = append(proposal.Files, File{
+				Path:     t.path,
+				Content:  t.content,
+				Policies: policies,
+			})
+		}
+	}
+
+	return proposal
+}
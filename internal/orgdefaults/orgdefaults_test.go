@@ -0,0 +1,57 @@
+package orgdefaults
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const results = `{
+  "repository.unpinned_third_party_actions": {
+    "policyInfo": {},
+    "violations": [
+      {"violationEntityType": "repository", "canonicalLink": "https://github.com/org1/repo1", "Status": "FAILED"},
+      {"violationEntityType": "repository", "canonicalLink": "https://github.com/org2/repo1", "Status": "PASSED"}
+    ]
+  },
+  "actions.token_default_permissions_is_read_write": {
+    "policyInfo": {},
+    "violations": [
+      {"violationEntityType": "actions", "canonicalLink": "https://github.com/organizations/org1/settings/actions", "Status": "FAILED"}
+    ]
+  },
+  "repository.dependabot_version_updates_not_configured": {
+    "policyInfo": {},
+    "violations": [
+      {"violationEntityType": "repository", "canonicalLink": "https://github.com/org2/repo1", "Status": "FAILED"}
+    ]
+  }
+}`
+
+func TestFailingPolicies(t *testing.T) {
+	failing, err := FailingPoliciesFromFlattenedJson([]byte(results), "org1")
+	require.Nil(t, err)
+	require.Equal(t, []string{"actions.token_default_permissions_is_read_write", "repository.unpinned_third_party_actions"}, failing)
+
+	failing, err = FailingPoliciesFromFlattenedJson([]byte(results), "ORG2")
+	require.Nil(t, err)
+	require.Equal(t, []string{"repository.dependabot_version_updates_not_configured"}, failing)
+
+	_, err = FailingPoliciesFromFlattenedJson([]byte("[]"), "org1")
+	require.NotNil(t, err)
+}
+
+func TestPropose(t *testing.T) {
+	proposal := Propose("org1", []string{"repository.unpinned_third_party_actions", "organization.two_factor_authentication_not_required_for_org"})
+	require.Equal(t, "org1", proposal.Organization)
+
+	var paths []string
+	for _, file := range proposal.Files {
+		paths = append(paths, file.Path)
+		require.Equal(t, []string{"repository.unpinned_third_party_actions"}, file.Policies)
+		require.NotEmpty(t, file.Content)
+	}
+	require.Equal(t, []string{"workflow-templates/secure-ci.yml", "workflow-templates/secure-ci.properties.json"}, paths)
+
+	require.Empty(t, Propose("org1", nil).Files)
+}
@@ -0,0 +1,97 @@
+package orgdefaults
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v44/github"
+)
+
+// Branch is the branch the proposed files are committed to
+const Branch = "legitify/org-defaults"
+
+// OpenPullRequest opens a draft pull request that adds the proposed files to the organization's .github repository.
+// Files that already exist in the repository are left as is (and removed from the proposal);
+// no pull request is opened if none of the files are missing.
+func OpenPullRequest(ctx context.Context, client *github.Client, proposal *Proposal) (*github.PullRequest, error) {
+	org := proposal.Organization
+	repository, _, err := client.Repositories.Get(ctx, org, Repository)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("%s has no %s repository, create it to hold the organization defaults", org, Repository)
+		}
+		return nil, err
+	}
+	base := repository.GetDefaultBranch()
+
+	var missing []File
+	for _, file := range proposal.Files {
+		_, _, _, err := client.Repositories.GetContents(ctx, org, Repository, file.Path, &github.RepositoryContentGetOptions{Ref: base})
+		if err == nil {
+			continue
+		}
+		if !isNotFound(err) {
+			return nil, err
+		}
+		missing = append(missing, file)
+	}
+	proposal.Files = missing
+	if len(missing) == 0 {
+		return nil, nil
+	}
+
+	baseRef, _, err := client.Git.GetRef(ctx, org, Repository, "refs/heads/"+base)
+	if err != nil {
+		return nil, err
+	}
+	_, _, err = client.Git.CreateRef(ctx, org, Repository, &github.Reference{
+		Ref:    github.String("refs/heads/" + Branch),
+		Object: &github.GitObject{SHA: baseRef.GetObject().SHA},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the %s branch (a previous proposal may still be open): %v", Branch, err)
+	}
+
+	for _, file := range missing {
+		_, _, err = client.Repositories.CreateFile(ctx, org, Repository, file.Path, &github.RepositoryContentFileOptions{
+			Message: github.String(fmt.Sprintf("Add %s", file.Path)),
+			Content: []byte(file.Content),
+			Branch:  github.String(Branch),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pr, _, err := client.PullRequests.Create(ctx, org, Repository, &github.NewPullRequest{
+		Title: github.String("Add recommended security defaults for the organization repositories"),
+		Head:  github.String(Branch),
+		Base:  github.String(base),
+		Body:  github.String(pullRequestBody(missing)),
+		Draft: github.Bool(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pr, nil
+}
+
+func pullRequestBody(files []File) string {
+	var body strings.Builder
+	body.WriteString("This pull request was opened by legitify, based on the policies that fail in the organization.\n\n")
+	body.WriteString("| File | Helps to fix |\n|------|--------------|\n")
+	for _, file := range files {
+		body.WriteString(fmt.Sprintf("| `%s` | %s |\n", file.Path, strings.Join(file.Policies, "<br>")))
+	}
+	body.WriteString("\nReview and adjust the files to the organization's needs before merging.\n")
+	return body.String()
+}
+
+func isNotFound(err error) bool {
+	var errResp *github.ErrorResponse
+	return errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound
+}
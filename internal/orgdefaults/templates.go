@@ -0,0 +1,65 @@
+package orgdefaults
+
+const securityPolicy = `# Security Policy
+
+## Reporting a Vulnerability
+
+Please do not report security vulnerabilities through public issues, discussions or pull requests.
+
+Instead, report them privately using the "Report a vulnerability" button in the "Security" tab of the affected repository
+(GitHub private vulnerability reporting), and include:
+- the affected repository, version or commit
+- a description of the vulnerability and its impact
+- the steps to reproduce it (or a proof of concept)
+
+We will acknowledge the report, keep you updated on the fix, and credit you in the advisory (unless you prefer otherwise).
+
+## Supported Versions
+
+Unless stated otherwise in the repository, only the latest release receives security fixes.
+`
+
+// ciWorkflow uses a read-only token by default and actions pinned to a commit SHA
+const ciWorkflow = `# A starter CI workflow with secure defaults:
+# - the workflow token is read-only unless a job needs more
+# - actions are pinned to a commit SHA (updated by Dependabot)
+# - pull requests from forks run with the 'pull_request' trigger (without secrets)
+name: CI
+
+on:
+  push:
+    branches: [ $default-branch ]
+  pull_request:
+    branches: [ $default-branch ]
+
+permissions:
+  contents: read
+
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@b4ffde65f46336ab88eb53be808477a3936bae11 # v4.1.1
+        with:
+          persist-credentials: false
+      - name: Build
+        run: echo "Add the build and test steps here"
+`
+
+const ciWorkflowProperties = `{
+  "name": "Secure CI",
+  "description": "A starter CI workflow with a read-only token and actions pinned to a commit SHA.",
+  "iconName": "octicon shield-check",
+  "categories": []
+}
+`
+
+const dependabotConfig = `# A template of the Dependabot version updates configuration:
+# copy it to .github/dependabot.yml in each repository, and add an entry per package ecosystem it uses.
+version: 2
+updates:
+  - package-ecosystem: github-actions
+    directory: /
+    schedule:
+      interval: weekly
+`
@@ -7,6 +7,7 @@ import (
 	"unicode"
 
 	"github.com/Legit-Labs/legitify/internal/analyzers"
+	"github.com/Legit-Labs/legitify/internal/compliance"
 	"github.com/Legit-Labs/legitify/internal/enricher/enrichers"
 	"github.com/olekukonko/tablewriter"
 
@@ -107,7 +108,7 @@ func (f *HumanFormatter) formatPolicyInfo(policyName string, policyInfo scheme.P
 
 	f.sb.WriteString(f.sprintf(1, "Policy Name: %s\n", policyName))
 	f.sb.WriteString(f.sprintf(1, "Namespace: %s\n", policyInfo.Namespace))
-	f.sb.WriteString(f.sprintfWithColor(1, f.colorByPolicy(policyInfo), "Severity: %s\n", policyInfo.Severity))
+	f.sb.WriteString(f.sprintfWithColor(1, f.colorByPolicy(policyInfo), "Severity: %s\n", severity.Label(policyInfo.Severity)))
 	f.sb.WriteString(f.sprintf(1, "Remediation Steps:\n"))
 	for i, step := range policyInfo.RemediationSteps {
 		f.sb.WriteString(f.sprintf(2, "%d. %s\n", i+1, step))
@@ -116,6 +117,9 @@ func (f *HumanFormatter) formatPolicyInfo(policyName string, policyInfo scheme.P
 
 func (f *HumanFormatter) formatViolation(violation scheme.Violation) {
 	f.sb.WriteString(f.sprintf(2, "%sLink to %s: %s\n", f.indent, violation.ViolationEntityType, violation.CanonicalLink))
+	if violation.Confidence != nil {
+		f.sb.WriteString(f.sprintfWithColor(2, color.FgYellow, "%sResult: violated (%s)\n", f.indent, violation.Confidence))
+	}
 	if len(violation.Aux) > 0 {
 		f.sb.WriteString(f.sprintf(2, "%sAuxiliary Info:\n", f.indent))
 		f.formatAux(violation.Aux)
@@ -128,7 +132,7 @@ func (f *HumanFormatter) formatSummaryTable(output scheme.FlattenedScheme) []byt
 	output = scheme.SortSchemeByNamespace(output, false)
 	tw := tablewriter.NewWriter(&buf)
 
-	headers := []string{"#", "Namespace", "Policy", "Severity", "Passed", "Failed", "Skipped"}
+	headers := []string{"#", "Namespace", "Policy", "Severity", "Passed", "Failed", "Skipped", "Waived"}
 	for i, h := range headers {
 		headers[i] = bold(h)
 	}
@@ -142,26 +146,35 @@ func (f *HumanFormatter) formatSummaryTable(output scheme.FlattenedScheme) []byt
 		policyInfo := data.PolicyInfo
 		colorAtt := f.colorByPolicy(policyInfo)
 		title := policyInfo.Title
-		severity := colorize(policyInfo.Severity, colorAtt)
+		severityLabel := colorize(severity.Label(policyInfo.Severity), colorAtt)
 		namespace := policyInfo.Namespace
 
-		var passed, failed, skipped int
+		var passed, failed, uncertain, skipped, waived int
 		for _, violation := range data.Violations {
 			switch violation.Status {
 			case analyzers.PolicyPassed:
 				passed++
 			case analyzers.PolicyFailed:
 				failed++
+				if violation.Confidence != nil {
+					uncertain++
+				}
 			case analyzers.PolicySkipped:
 				skipped++
+			case analyzers.PolicyWaived:
+				waived++
 			}
 		}
 
 		passedStr := colorize(passed, color.FgGreen)
 		failedStr := colorize(failed, color.FgRed)
+		if uncertain > 0 {
+			failedStr = colorize(fmt.Sprintf("%d (%d partial data)", failed, uncertain), color.FgRed)
+		}
 		skippedStr := colorize(skipped, color.FgHiBlue)
+		waivedStr := colorize(waived, color.FgHiMagenta)
 
-		tw.Append([]string{rowNum, namespace, title, severity, passedStr, failedStr, skippedStr})
+		tw.Append([]string{rowNum, namespace, title, severityLabel, passedStr, failedStr, skippedStr, waivedStr})
 	}
 
 	tw.Render()
@@ -200,13 +213,27 @@ func (f *HumanFormatter) Format(output interface{}, failedOnly bool) ([]byte, er
 	var summary, failedViolations []byte
 	var typedOutput scheme.FlattenedScheme
 
+	if report, ok := output.(*compliance.Report); ok {
+		return f.formatComplianceReport(report, failedOnly)
+	}
+
 	typedOutput, ok := output.(scheme.FlattenedScheme)
 	if !ok {
 		return nil, UnsupportedScheme{output}
 	}
 
+	var waivedViolations []byte
 	if !failedOnly {
 		summary = f.formatSummaryTable(typedOutput)
+		waived := scheme.FilterViolationsByStatus(typedOutput, analyzers.PolicyWaived)
+		if len(waived.Keys()) > 0 {
+			formatted, err := f.formatFailedViolations(waived)
+			if err != nil {
+				return nil, err
+			}
+			header := []byte(color.New(color.Bold).Sprintf("\nWaived findings (accepted violations):\n"))
+			waivedViolations = append(header, formatted...)
+		}
 		typedOutput = scheme.OnlyFailedViolations(typedOutput)
 	}
 
@@ -215,6 +242,7 @@ func (f *HumanFormatter) Format(output interface{}, failedOnly bool) ([]byte, er
 		return nil, err
 	}
 
+	failedViolations = append(failedViolations, waivedViolations...)
 	return append(failedViolations, summary...), err
 }
 
@@ -0,0 +1,71 @@
+package formatter
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/Legit-Labs/legitify/internal/common/severity"
+	"github.com/Legit-Labs/legitify/internal/compliance"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+)
+
+var controlStatusToColor = map[compliance.ControlStatus]color.Attribute{
+	compliance.ControlPassed:       color.FgGreen,
+	compliance.ControlFailed:       color.FgRed,
+	compliance.ControlNotEvaluated: color.FgHiBlue,
+}
+
+func (f *HumanFormatter) formatComplianceReport(report *compliance.Report, failedOnly bool) ([]byte, error) {
+	f.sb.Reset()
+
+	for _, control := range report.Controls {
+		if control.Status != compliance.ControlFailed {
+			continue
+		}
+
+		title := fmt.Sprintf("%s %s", control.ID, control.Title)
+		f.sb.WriteString(f.sprintfWithColor(0, controlStatusToColor[control.Status], "%s\n", title))
+		f.sb.WriteString(fmt.Sprintf("%s\n", strings.Repeat("-", len(title))))
+		for _, policy := range control.Policies {
+			if policy.Failed == 0 {
+				continue
+			}
+			f.sb.WriteString(f.sprintfWithColor(1, severityToColor[policy.Severity], "%s (%s)\n", policy.Title, severity.Label(policy.Severity)))
+			for _, entity := range policy.FailedEntities {
+				f.sb.WriteString(f.sprintf(2, "- %s\n", entity))
+			}
+		}
+		f.sb.WriteString("\n")
+	}
+
+	if !failedOnly {
+		f.sb.Write(f.formatComplianceSummaryTable(report))
+	}
+
+	return []byte(f.sb.String()), nil
+}
+
+func (f *HumanFormatter) formatComplianceSummaryTable(report *compliance.Report) []byte {
+	var buf bytes.Buffer
+
+	tw := tablewriter.NewWriter(&buf)
+	headers := []string{"Control", "Title", "Status", "Policies"}
+	for i, h := range headers {
+		headers[i] = bold(h)
+	}
+	tw.SetHeader(headers)
+	tw.SetAutoFormatHeaders(false)
+	tw.SetRowLine(true)
+
+	for _, control := range report.Controls {
+		status := colorize(control.Status, controlStatusToColor[control.Status])
+		tw.Append([]string{bold(control.ID), control.Title, status, fmt.Sprintf("%d", len(control.Policies))})
+	}
+	tw.Render()
+
+	header := color.New(color.Bold).Sprintf("\n%s compliance summary (score: %.1f%%, passed: %d, failed: %d, not evaluated: %d):\n",
+		report.Title, report.Score, report.Passed, report.Failed, report.NotEvaluated)
+	return append([]byte(header), buf.Bytes()...)
+}
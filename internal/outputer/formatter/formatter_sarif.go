@@ -0,0 +1,30 @@
+package formatter
+
+import (
+	"encoding/json"
+
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme"
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme/converter"
+	"github.com/Legit-Labs/legitify/internal/sarif"
+)
+
+type SarifFormatter struct {
+	indent string
+}
+
+func NewSarifFormatter(indent string) OutputFormatter {
+	return &SarifFormatter{indent: indent}
+}
+
+func (f *SarifFormatter) Format(output interface{}, failedOnly bool) ([]byte, error) {
+	flattened, ok := output.(scheme.FlattenedScheme)
+	if !ok {
+		return nil, UnsupportedScheme{output}
+	}
+
+	return json.MarshalIndent(sarif.FromFlattenedScheme(flattened, sarif.EntityLocation, ""), "", f.indent)
+}
+
+func (f *SarifFormatter) IsSchemeSupported(schemeType string) bool {
+	return schemeType == converter.Flattened
+}
@@ -0,0 +1,77 @@
+package formatter
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/Legit-Labs/legitify/internal/common/severity"
+	"github.com/Legit-Labs/legitify/internal/common/utils"
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme"
+	"github.com/iancoleman/orderedmap"
+)
+
+var templateFuncs = template.FuncMap{
+	"get":           templateGet,
+	"join":          strings.Join,
+	"upper":         strings.ToUpper,
+	"lower":         strings.ToLower,
+	"title":         camelCaseToTitle,
+	"severityLabel": severity.Label,
+}
+
+// templateData makes sure flattened schemes are passed by reference,
+// otherwise the template engine can't reach the (pointer receiver) Keys() method.
+func templateData(data interface{}) interface{} {
+	if flattened, ok := data.(scheme.FlattenedScheme); ok {
+		return &flattened
+	}
+	return data
+}
+
+// templateGet allows walking the grouped schemes: {{range $k := .Keys}}{{$sub := get $ $k}}...{{end}}
+func templateGet(m *orderedmap.OrderedMap, key string) interface{} {
+	return templateData(utils.UnsafeGet(m, key))
+}
+
+// TemplateFormatter renders the output scheme through a user-supplied Go text/template.
+// The template receives the converted scheme as its data (e.g. scheme.FlattenedScheme for the default scheme).
+type TemplateFormatter struct {
+	tmpl *template.Template
+}
+
+func NewTemplateFormatter(templatePath string) (OutputFormatter, error) {
+	tmpl, err := template.New(filepath.Base(templatePath)).Funcs(templateFuncs).ParseFiles(templatePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TemplateFormatter{tmpl: tmpl}, nil
+}
+
+func (f *TemplateFormatter) Format(output interface{}, failedOnly bool) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := f.tmpl.Execute(&buf, templateData(output)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (f *TemplateFormatter) IsSchemeSupported(schemeType string) bool {
+	return true
+}
+
+func ValidateOutputTemplate(templatePath string) error {
+	_, err := NewTemplateFormatter(templatePath)
+	return err
+}
+
+func FormatTemplate(templatePath string, output interface{}, failedOnly bool) ([]byte, error) {
+	outputFormatter, err := NewTemplateFormatter(templatePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return outputFormatter.Format(output, failedOnly)
+}
@@ -0,0 +1,36 @@
+package formatter_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Legit-Labs/legitify/internal/outputer/formatter"
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme/scheme_test.go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatTemplate(t *testing.T) {
+	sample := scheme_test.SchemeSample()
+
+	tmplPath := filepath.Join(t.TempDir(), "report.tmpl")
+	tmpl := `{{range $name := .Keys}}{{$data := $.GetPolicyData $name}}{{$name}}: {{upper $data.PolicyInfo.Severity}} ({{len $data.Violations}})
+{{end}}`
+	err := os.WriteFile(tmplPath, []byte(tmpl), 0644)
+	require.Nilf(t, err, "Error writing template: %v", err)
+
+	output, err := formatter.FormatTemplate(tmplPath, sample, true)
+	require.Nilf(t, err, "Error formatting template: %v", err)
+
+	expected := scheme_test.FullyQualifiedPolicyNameSample() + ": LOW (2)\n" +
+		scheme_test.FullyQualifiedPolicyNameSample2() + ": HIGH (2)\n"
+	require.Equal(t, expected, string(output))
+}
+
+func TestFormatTemplateInvalid(t *testing.T) {
+	tmplPath := filepath.Join(t.TempDir(), "broken.tmpl")
+	err := os.WriteFile(tmplPath, []byte("{{range .Keys}"), 0644)
+	require.Nilf(t, err, "Error writing template: %v", err)
+
+	require.NotNil(t, formatter.ValidateOutputTemplate(tmplPath), "Expecting an invalid template error")
+}
@@ -0,0 +1,154 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/iancoleman/orderedmap"
+)
+
+const (
+	policyInfoField = "policyInfo"
+	violationsField = "violations"
+	dropPrefix      = "-"
+)
+
+// fieldTree is a tree of the included fields (a leaf includes the whole field)
+type fieldTree map[string]fieldTree
+
+// FieldSelection selects the fields of the json output: the included fields (all, if none are included) without the dropped ones.
+// The fields are the paths of the policy data fields (e.g. policyInfo.remediationSteps, violations.aux.scorecard).
+type FieldSelection struct {
+	include fieldTree
+	drop    [][]string
+}
+
+// ParseFields parses the fields to include (e.g. policyInfo.title) and to drop (prefixed with '-', e.g. -violations.aux)
+func ParseFields(fields []string) (*FieldSelection, error) {
+	selection := &FieldSelection{include: fieldTree{}}
+	for _, field := range fields {
+		dropped := strings.HasPrefix(field, dropPrefix)
+		path := strings.Split(strings.TrimPrefix(field, dropPrefix), ".")
+		for _, part := range path {
+			if part == "" {
+				return nil, fmt.Errorf("invalid field: %s", field)
+			}
+		}
+		if path[0] != policyInfoField && path[0] != violationsField {
+			return nil, fmt.Errorf("invalid field: %s (fields start with %s or %s)", field, policyInfoField, violationsField)
+		}
+
+		if dropped {
+			selection.drop = append(selection.drop, path)
+			continue
+		}
+		tree := selection.include
+		for _, part := range path {
+			if tree[part] == nil {
+				tree[part] = fieldTree{}
+			}
+			tree = tree[part]
+		}
+	}
+
+	return selection, nil
+}
+
+// SelectJsonFields applies the field selection to each policy data in the json output (of any scheme)
+func SelectJsonFields(output []byte, selection *FieldSelection, indent string) ([]byte, error) {
+	root := orderedmap.New()
+	if err := json.Unmarshal(output, root); err != nil {
+		return nil, err
+	}
+
+	selected := selection.selectIn(*root)
+	return json.MarshalIndent(selected, "", indent)
+}
+
+// selectIn applies the selection to the policy data objects (the objects with the policy info and violations) in the node
+func (s *FieldSelection) selectIn(node interface{}) interface{} {
+	switch v := node.(type) {
+	case orderedmap.OrderedMap:
+		if isPolicyData(v) {
+			return s.apply(v)
+		}
+		for _, key := range v.Keys() {
+			child, _ := v.Get(key)
+			v.Set(key, s.selectIn(child))
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = s.selectIn(v[i])
+		}
+		return v
+	}
+	return node
+}
+
+func isPolicyData(m orderedmap.OrderedMap) bool {
+	_, hasInfo := m.Get(policyInfoField)
+	_, hasViolations := m.Get(violationsField)
+	return hasInfo && hasViolations
+}
+
+func (s *FieldSelection) apply(data orderedmap.OrderedMap) interface{} {
+	var result interface{} = data
+	if len(s.include) > 0 {
+		result = include(result, s.include)
+	}
+	for _, path := range s.drop {
+		result = drop(result, path)
+	}
+	return result
+}
+
+func include(node interface{}, tree fieldTree) interface{} {
+	if len(tree) == 0 {
+		return node
+	}
+
+	switch v := node.(type) {
+	case orderedmap.OrderedMap:
+		keys := append([]string{}, v.Keys()...)
+		for _, key := range keys {
+			subtree, ok := tree[key]
+			if !ok {
+				v.Delete(key)
+				continue
+			}
+			child, _ := v.Get(key)
+			v.Set(key, include(child, subtree))
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = include(v[i], tree)
+		}
+		return v
+	}
+	return node
+}
+
+func drop(node interface{}, path []string) interface{} {
+	switch v := node.(type) {
+	case orderedmap.OrderedMap:
+		child, ok := v.Get(path[0])
+		if !ok {
+			return v
+		}
+		if len(path) == 1 {
+			v.Delete(path[0])
+			return v
+		}
+		v.Set(path[0], drop(child, path[1:]))
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = drop(v[i], path)
+		}
+		return v
+	}
+	return node
+}
@@ -0,0 +1,56 @@
+package formatter_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/Legit-Labs/legitify/internal/outputer/formatter"
+	"github.com/stretchr/testify/require"
+)
+
+const fieldsSample = `{
+  "repository.b_policy": {
+    "policyInfo": {"title": "B", "severity": "HIGH", "remediationSteps": ["fix"]},
+    "violations": [{"canonicalLink": "link", "aux": {"entityId": "1", "scorecard": {"score": 5}}, "Status": "FAILED"}]
+  },
+  "repository.a_policy": {
+    "policyInfo": {"title": "A", "severity": "LOW", "remediationSteps": []},
+    "violations": []
+  }
+}`
+
+func TestSelectJsonFieldsDrop(t *testing.T) {
+	selection, err := formatter.ParseFields([]string{"-policyInfo.remediationSteps", "-violations.aux.scorecard"})
+	require.Nil(t, err)
+
+	output, err := formatter.SelectJsonFields([]byte(fieldsSample), selection, formatter.DefaultOutputIndent)
+	require.Nil(t, err)
+	require.Equal(t, `{"repository.b_policy":{"policyInfo":{"title":"B","severity":"HIGH"},`+
+		`"violations":[{"canonicalLink":"link","aux":{"entityId":"1"},"Status":"FAILED"}]},`+
+		`"repository.a_policy":{"policyInfo":{"title":"A","severity":"LOW"},"violations":[]}}`, compact(t, output))
+}
+
+func TestSelectJsonFieldsInclude(t *testing.T) {
+	selection, err := formatter.ParseFields([]string{"policyInfo.title", "violations.canonicalLink", "violations.Status"})
+	require.Nil(t, err)
+
+	output, err := formatter.SelectJsonFields([]byte(`{"repository": `+fieldsSample+`}`), selection, formatter.DefaultOutputIndent)
+	require.Nil(t, err)
+	require.Equal(t, `{"repository":{"repository.b_policy":{"policyInfo":{"title":"B"},`+
+		`"violations":[{"canonicalLink":"link","Status":"FAILED"}]},`+
+		`"repository.a_policy":{"policyInfo":{"title":"A"},"violations":[]}}}`, compact(t, output))
+}
+
+func TestParseFieldsInvalid(t *testing.T) {
+	for _, fields := range [][]string{{"title"}, {"policyInfo..title"}, {"-"}} {
+		_, err := formatter.ParseFields(fields)
+		require.NotNil(t, err, fields)
+	}
+}
+
+func compact(t *testing.T, output []byte) string {
+	var buf bytes.Buffer
+	require.Nil(t, json.Compact(&buf, output))
+	return buf.String()
+}
@@ -26,7 +26,7 @@ type NewFormatFunc func(indent string) OutputFormatter
 var outputFormatters = map[FormatName]NewFormatFunc{
 	Human: NewHumanFormatter,
 	Json:  NewJsonFormatter,
-	Sarif: nil, // TODO pending implementation of Sarif output
+	Sarif: NewSarifFormatter,
 }
 
 func ValidateOutputFormat(outputFormat FormatName, schemeType converter.SchemeType) error {
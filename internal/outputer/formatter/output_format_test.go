@@ -1,12 +1,14 @@
 package formatter_test
 
 import (
+	"encoding/json"
 	"log"
 	"testing"
 
 	"github.com/Legit-Labs/legitify/internal/outputer/formatter"
 	"github.com/Legit-Labs/legitify/internal/outputer/formatter/formatter_test"
 	"github.com/Legit-Labs/legitify/internal/outputer/scheme/scheme_test.go"
+	"github.com/Legit-Labs/legitify/internal/sarif"
 	"github.com/stretchr/testify/require"
 )
 
@@ -27,6 +29,13 @@ func TestOutputFormats(t *testing.T) {
 			log.Printf("Human-Readable output:\n%s", output)
 			continue // Cannot test human formatter - by definition not machine readable
 
+		case formatter.Sarif:
+			var sarifLog sarif.Log
+			require.Nilf(t, json.Unmarshal(output, &sarifLog), "Error deserializing sarif: %v", err)
+			require.Equal(t, sarif.Version, sarifLog.Version)
+			require.Len(t, sarifLog.Runs, 1)
+			continue // sarif is a lossy format
+
 		case formatter.Json:
 			reversed, err = formatter_test.DeserializeJson(output)
 			require.Nilf(t, err, "Error deserializing json: %v", err)
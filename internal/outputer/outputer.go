@@ -2,9 +2,14 @@ package outputer
 
 import (
 	"context"
+	"fmt"
 	"io"
 
 	"github.com/Legit-Labs/legitify/internal/common/group_waiter"
+	"github.com/Legit-Labs/legitify/internal/common/severity"
+	"github.com/Legit-Labs/legitify/internal/compliance"
+	"github.com/Legit-Labs/legitify/internal/confidence"
+	"github.com/Legit-Labs/legitify/internal/context_utils"
 	"github.com/Legit-Labs/legitify/internal/enricher"
 	"github.com/Legit-Labs/legitify/internal/outputer/formatter"
 	"github.com/Legit-Labs/legitify/internal/outputer/scheme"
@@ -14,44 +19,74 @@ import (
 type Outputer interface {
 	Digest(inputChannel <-chan enricher.EnrichedData) group_waiter.Waitable
 	Output(writer io.Writer) error
+	// Results returns the digested results (sorted by severity, including passed/skipped policies)
+	Results() scheme.FlattenedScheme
 }
 
-func NewOutputer(ctx context.Context, format formatter.FormatName, schemeType converter.SchemeType, failedOnly bool) Outputer {
+func NewOutputer(ctx context.Context, format formatter.FormatName, schemeType converter.SchemeType, failedOnly bool, outputTemplate string, complianceFramework compliance.FrameworkName, fields *formatter.FieldSelection) Outputer {
+	percent, sampled := context_utils.GetSamplePercent(ctx)
 	return &outputer{
-		format:     format,
-		schemeType: schemeType,
-		failedOnly: failedOnly,
+		scanID:              context_utils.GetScanID(ctx),
+		minConfidence:       context_utils.GetMinConfidence(ctx),
+		samplePercent:       percent,
+		sampled:             sampled,
+		format:              format,
+		schemeType:          schemeType,
+		failedOnly:          failedOnly,
+		outputTemplate:      outputTemplate,
+		complianceFramework: complianceFramework,
+		fields:              fields,
 	}
 }
 
 // -----------------------------------------------------------------------------
 
 type outputer struct {
-	format     formatter.FormatName
-	schemeType converter.SchemeType
-	failedOnly bool
-	output     []byte
-	err        error
+	format              formatter.FormatName
+	schemeType          converter.SchemeType
+	failedOnly          bool
+	outputTemplate      string
+	complianceFramework compliance.FrameworkName
+	// fields is the field selection of the json output (nil to output all the fields)
+	fields        *formatter.FieldSelection
+	scanID        string
+	minConfidence confidence.Level
+	samplePercent int
+	sampled       bool
+	results       scheme.FlattenedScheme
+	output        []byte
+	err           error
 }
 
-func enrichedDataToPolicyInfo(enrichedData enricher.EnrichedData) scheme.PolicyInfo {
+func enrichedDataToPolicyInfo(enrichedData enricher.EnrichedData, scanID string) scheme.PolicyInfo {
 	return scheme.PolicyInfo{
 		Title:                    enrichedData.Title,
 		Description:              enrichedData.Description,
 		PolicyName:               enrichedData.PolicyName,
 		FullyQualifiedPolicyName: enrichedData.FullyQualifiedPolicyName,
 		Severity:                 enrichedData.Severity,
+		SeverityLabel:            severityLabel(enrichedData.Severity),
 		RemediationSteps:         enrichedData.RemediationSteps,
 		Namespace:                enrichedData.Namespace,
+		ScanID:                   scanID,
 	}
 }
 
+// severityLabel returns the custom label of the severity, if one was configured
+func severityLabel(s severity.Severity) string {
+	if label := severity.Label(s); label != s {
+		return label
+	}
+	return ""
+}
+
 func enrichedDataToViolation(enrichedData enricher.EnrichedData) scheme.Violation {
 	return scheme.Violation{
 		CanonicalLink:       enrichedData.CanonicalLink,
 		ViolationEntityType: enrichedData.Entity.ViolationEntityType(),
 		Aux:                 enrichedData.Enrichers,
 		Status:              enrichedData.Status,
+		Confidence:          enrichedData.Confidence,
 	}
 }
 
@@ -62,7 +97,7 @@ func (o *outputer) receiveViolations(inputChannel <-chan enricher.EnrichedData)
 		policyName := encrichedData.FullyQualifiedPolicyName
 
 		if _, ok := violations.Get(policyName); !ok {
-			violations.Set(policyName, scheme.NewOutputData(enrichedDataToPolicyInfo(encrichedData)))
+			violations.Set(policyName, scheme.NewOutputData(enrichedDataToPolicyInfo(encrichedData, o.scanID)))
 		}
 		preAppend := violations.GetPolicyData(policyName)
 
@@ -80,23 +115,64 @@ func (o *outputer) Digest(inputChannel <-chan enricher.EnrichedData) group_waite
 		o.err = nil // zero err to allow reuse of the object
 		violations := o.receiveViolations(inputChannel)
 		sorted := scheme.SortSchemeBySeverity(violations, true)
+		o.results = sorted
 
-		if o.failedOnly {
-			sorted = scheme.OnlyFailedViolations(sorted)
-		}
-
-		converted, err := converter.Convert(o.schemeType, sorted)
+		converted, err := o.convert(sorted)
 		if err != nil {
 			o.err = err
 			return
 		}
 
+		if o.outputTemplate != "" {
+			o.output, o.err = formatter.FormatTemplate(o.outputTemplate, converted, o.failedOnly)
+			return
+		}
+
 		o.output, o.err = formatter.Format(o.format, formatter.DefaultOutputIndent, converted, o.failedOnly)
+		if o.err == nil && o.format == formatter.Json && o.fields != nil {
+			o.output, o.err = formatter.SelectJsonFields(o.output, o.fields, formatter.DefaultOutputIndent)
+		}
+		if o.err == nil && o.format == formatter.Human {
+			o.output = append([]byte(o.humanBanner()), o.output...)
+		}
 	})
 
 	return gw
 }
 
+// humanBanner identifies the scan in the human output (the other formats carry the scan ID in the policy info,
+// and mark each repository result of a sampled scan instead)
+func (o *outputer) humanBanner() string {
+	var banner string
+	if o.scanID != "" {
+		banner += fmt.Sprintf("Scan ID: %s\n", o.scanID)
+	}
+	if o.sampled {
+		banner += fmt.Sprintf("NOTE: SAMPLED SCAN - the repository results cover ~%d%% of the repositories (stratified by visibility & activity)\n", o.samplePercent)
+	}
+	if banner != "" {
+		banner += "\n"
+	}
+	return banner
+}
+
+func (o *outputer) convert(sorted scheme.FlattenedScheme) (interface{}, error) {
+	// compliance reports are scored using the passed results as well, so they are created before filtering
+	if o.complianceFramework != "" {
+		return compliance.NewReport(o.complianceFramework, sorted)
+	}
+
+	if o.minConfidence != confidence.Low {
+		sorted = scheme.FilterViolationsByConfidence(sorted, o.minConfidence)
+	}
+
+	if o.failedOnly {
+		sorted = scheme.OnlyFailedViolations(sorted)
+	}
+
+	return converter.Convert(o.schemeType, sorted)
+}
+
 func (o *outputer) Output(writer io.Writer) error {
 	if o.err != nil {
 		return o.err
@@ -109,3 +185,7 @@ func (o *outputer) Output(writer io.Writer) error {
 
 	return nil
 }
+
+func (o *outputer) Results() scheme.FlattenedScheme {
+	return o.results
+}
@@ -1,9 +1,13 @@
 package outputer
 
 import (
+	"bytes"
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/Legit-Labs/legitify/internal/analyzers"
 	"github.com/Legit-Labs/legitify/internal/enricher"
 	"github.com/Legit-Labs/legitify/internal/outputer/formatter"
 	"github.com/Legit-Labs/legitify/internal/outputer/formatter/formatter_test"
@@ -30,7 +34,7 @@ func TestOutputer(t *testing.T) {
 	require.Nilf(t, err, "Error converting struct to map: %v", err)
 
 	inputChannel := make(chan enricher.EnrichedData, len(data))
-	outputer := NewOutputer(context.Background(), formatter.Json, converter.Flattened, false)
+	outputer := NewOutputer(context.Background(), formatter.Json, converter.Flattened, false, "", "", nil)
 	require.NotNilf(t, outputer, "Error creating outputer: %v", err)
 
 	// Setup a channel to get the output from the Writer mock
@@ -62,3 +66,36 @@ func TestOutputer(t *testing.T) {
 	require.NotNil(t, output, "Error deserializing json")
 	require.Equal(t, mapped, reversed, "Expecting output to be the same as the input")
 }
+
+func TestOutputerTemplateFailedOnly(t *testing.T) {
+	data := scheme_test.EnrichedDataSample()
+	data[1].Status = analyzers.PolicyPassed
+	data[2].Status = analyzers.PolicyPassed
+	data[3].Status = analyzers.PolicyPassed
+
+	tmplPath := filepath.Join(t.TempDir(), "report.tmpl")
+	tmpl := `{{range $name := .Keys}}{{$data := $.GetPolicyData $name}}{{$name}}:{{range $data.Violations}} {{.Status}}{{end}}
+{{end}}`
+	err := os.WriteFile(tmplPath, []byte(tmpl), 0644)
+	require.Nilf(t, err, "Error writing template: %v", err)
+
+	for failedOnly, expected := range map[bool]string{
+		false: scheme_test.FullyQualifiedPolicyNameSample2() + ": PASSED PASSED\n" +
+			scheme_test.FullyQualifiedPolicyNameSample() + ": FAILED PASSED\n",
+		true: scheme_test.FullyQualifiedPolicyNameSample() + ": FAILED\n",
+	} {
+		inputChannel := make(chan enricher.EnrichedData, len(data))
+		for _, d := range data {
+			inputChannel <- d
+		}
+		close(inputChannel)
+
+		outputer := NewOutputer(context.Background(), formatter.Json, converter.Flattened, failedOnly, tmplPath, "", nil)
+		outputer.Digest(inputChannel).Wait()
+
+		var output bytes.Buffer
+		err = outputer.Output(&output)
+		require.Nilf(t, err, "Error writing output: %v", err)
+		require.Equal(t, expected, output.String(), "failedOnly=%v", failedOnly)
+	}
+}
@@ -1,6 +1,7 @@
 package converter
 
 import (
+	"github.com/Legit-Labs/legitify/internal/common/severity"
 	"github.com/Legit-Labs/legitify/internal/outputer/scheme"
 	"github.com/iancoleman/orderedmap"
 )
@@ -13,7 +14,7 @@ type bySeverityConverter struct {
 }
 
 func (*bySeverityConverter) Element(policyInfo scheme.PolicyInfo, violation scheme.Violation) string {
-	return policyInfo.Severity
+	return severity.Label(policyInfo.Severity)
 }
 func (*bySeverityConverter) NewScheme() *orderedmap.OrderedMap {
 	return scheme.NewBySeverityScheme()
@@ -6,6 +6,7 @@ import (
 	"github.com/Legit-Labs/legitify/internal/common/namespace"
 
 	"github.com/Legit-Labs/legitify/internal/analyzers"
+	"github.com/Legit-Labs/legitify/internal/confidence"
 	"github.com/Legit-Labs/legitify/internal/enricher/enrichers"
 
 	"github.com/Legit-Labs/legitify/internal/common/severity"
@@ -19,8 +20,11 @@ type PolicyInfo struct {
 	PolicyName               string              `json:"policyName"`
 	FullyQualifiedPolicyName string              `json:"fullyQualifiedPolicyName"`
 	Severity                 severity.Severity   `json:"severity"`
+	SeverityLabel            string              `json:"severityLabel,omitempty"`
 	RemediationSteps         []string            `json:"remediationSteps"`
 	Namespace                namespace.Namespace `json:"namespace"`
+	// ScanID identifies the run that produced the results, to correlate the artifacts of the same run
+	ScanID string `json:"scanId,omitempty"`
 }
 
 type Violation struct { // Must be exported for json marshal
@@ -28,6 +32,8 @@ type Violation struct { // Must be exported for json marshal
 	CanonicalLink       string                          `json:"canonicalLink"`
 	Aux                 map[string]enrichers.Enrichment `json:"aux"`
 	Status              analyzers.PolicyStatus
+	// Confidence is set when the result is based on partially collected data (omitted for high confidence)
+	Confidence *confidence.Confidence `json:"confidence,omitempty"`
 }
 
 type OutputData struct { // Must be exported for json marshal
@@ -133,6 +139,16 @@ func policiesSortByNamespaceLess(i, j *orderedmap.Pair) bool {
 
 type ViolationFilter func(violation Violation) bool
 
+// ScanID returns the ID of the run that produced the results
+func (s FlattenedScheme) ScanID() string {
+	for _, policyName := range s.Keys() {
+		if id := s.GetPolicyData(policyName).PolicyInfo.ScanID; id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
 func FilterPoliciesByViolations(output FlattenedScheme, filter ViolationFilter) FlattenedScheme {
 	filteredScheme := NewFlattenedScheme()
 	for _, policyName := range output.Keys() {
@@ -165,6 +181,14 @@ func OnlyFailedViolations(output FlattenedScheme) FlattenedScheme {
 	return FilterViolationsByStatus(output, analyzers.PolicyFailed)
 }
 
+// FilterViolationsByConfidence drops the results whose confidence is lower than the minimal level
+func FilterViolationsByConfidence(output FlattenedScheme, minimal confidence.Level) FlattenedScheme {
+	filter := func(violation Violation) bool {
+		return confidence.Meets(violation.Confidence, minimal)
+	}
+	return FilterPoliciesByViolations(output, filter)
+}
+
 func sortOutputData(outputData OutputData) OutputData {
 	less := func(i, j int) bool {
 		iLink := outputData.Violations[i].CanonicalLink
@@ -0,0 +1,286 @@
+package packs
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Pack is a community policy pack: a gzipped tarball of rego policies
+type Pack struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Version     string   `json:"version"`
+	Tags        []string `json:"tags,omitempty"`
+	URL         string   `json:"url"`
+	// SHA256 is the hex digest of the archive
+	SHA256 string `json:"sha256"`
+	// Signature is the base64 ed25519 signature of the pack's name, version and digest (see SignedMessage) by one of the trusted keys
+	Signature string `json:"signature"`
+}
+
+// Index is the list of the available packs. It isn't signed itself (and there is no default index), but each of its packs is,
+// so an index can't rename a signed pack or replace its archive with another (e.g. older) signed version.
+type Index struct {
+	Packs []Pack `json:"packs"`
+}
+
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// maxArchiveSize limits the size of a downloaded pack (and of its extracted files)
+const maxArchiveSize = 10 * 1024 * 1024
+
+// LoadIndex reads the index from an https URL or a local file
+func LoadIndex(source string) (*Index, error) {
+	data, err := fetch(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the packs index %s: %v", source, err)
+	}
+
+	var index Index
+	if err = json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse the packs index %s: %v", source, err)
+	}
+
+	for _, pack := range index.Packs {
+		if !validName.MatchString(pack.Name) {
+			return nil, fmt.Errorf("invalid pack name in the packs index: %q", pack.Name)
+		}
+	}
+
+	return &index, nil
+}
+
+// Find returns the pack with the given name
+func (i *Index) Find(name string) (*Pack, error) {
+	for idx := range i.Packs {
+		if i.Packs[idx].Name == name {
+			return &i.Packs[idx], nil
+		}
+	}
+	return nil, fmt.Errorf("pack %s is not in the index", name)
+}
+
+// ParsePublicKeys parses base64 ed25519 public keys
+func ParsePublicKeys(encoded []string) ([]ed25519.PublicKey, error) {
+	var keys []ed25519.PublicKey
+	for _, e := range encoded {
+		raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(e))
+		if err != nil || len(raw) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid public key %s (expecting a base64 ed25519 public key)", e)
+		}
+		keys = append(keys, ed25519.PublicKey(raw))
+	}
+	return keys, nil
+}
+
+// SignedMessage is what the pack signature covers: the pack's name and version, and the digest of its archive
+func SignedMessage(pack *Pack) []byte {
+	return []byte(fmt.Sprintf("legitify-pack\nname: %s\nversion: %s\nsha256: %s\n", pack.Name, pack.Version, strings.ToLower(pack.SHA256)))
+}
+
+// Verify checks the archive digest, and the signature of the pack's name, version and digest by one of the trusted keys
+func Verify(pack *Pack, archive []byte, keys []ed25519.PublicKey) error {
+	digest := sha256.Sum256(archive)
+	if !strings.EqualFold(hex.EncodeToString(digest[:]), pack.SHA256) {
+		return fmt.Errorf("the digest of pack %s doesn't match the index", pack.Name)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(pack.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature of pack %s: %v", pack.Name, err)
+	}
+
+	message := SignedMessage(pack)
+	for _, key := range keys {
+		if ed25519.Verify(key, message, signature) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("the signature of pack %s %s doesn't match any of the trusted keys", pack.Name, pack.Version)
+}
+
+// Download fetches the pack archive and verifies it
+func Download(pack *Pack, keys []ed25519.PublicKey) ([]byte, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("at least one trusted public key is required to verify the packs")
+	}
+
+	archive, err := fetch(pack.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download pack %s: %v", pack.Name, err)
+	}
+
+	if err = Verify(pack, archive, keys); err != nil {
+		return nil, err
+	}
+
+	return archive, nil
+}
+
+// Install extracts the rego files of the (verified) archive into <policiesPath>/<pack name>, replacing a previously installed
+// version of the pack. The files are extracted into a temporary directory next to the target and validated (e.g. compiled with
+// the built-in policies) before they replace the installed version, so a failed upgrade keeps the working pack.
+// It returns the installed files.
+func Install(pack *Pack, archive []byte, policiesPath string, validate func(dir string) error) ([]string, error) {
+	target := filepath.Join(policiesPath, pack.Name)
+	files, err := extractRego(archive)
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive of pack %s: %v", pack.Name, err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("pack %s doesn't contain any policy", pack.Name)
+	}
+
+	if err = os.MkdirAll(policiesPath, 0755); err != nil {
+		return nil, err
+	}
+	staging, err := os.MkdirTemp(policiesPath, "."+pack.Name+"-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(staging)
+
+	var names []string
+	for name, content := range files {
+		path := filepath.Join(staging, name)
+		if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, err
+		}
+		if err = os.WriteFile(path, content, 0644); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	// MkdirTemp creates the directory with 0700
+	if err = os.Chmod(staging, 0755); err != nil {
+		return nil, err
+	}
+
+	if validate != nil {
+		if err = validate(staging); err != nil {
+			return nil, fmt.Errorf("pack %s is invalid and was not installed: %v", pack.Name, err)
+		}
+	}
+
+	if err = replaceDir(staging, target); err != nil {
+		return nil, fmt.Errorf("failed to install pack %s: %v", pack.Name, err)
+	}
+
+	var installed []string
+	for _, name := range names {
+		installed = append(installed, filepath.Join(target, name))
+	}
+	return installed, nil
+}
+
+// replaceDir renames the source directory over the target one; the previous target is restored if the rename fails
+func replaceDir(source string, target string) error {
+	previous := source + ".previous"
+	if _, err := os.Stat(target); err == nil {
+		if err = os.Rename(target, previous); err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	} else {
+		previous = ""
+	}
+
+	if err := os.Rename(source, target); err != nil {
+		if previous != "" {
+			_ = os.Rename(previous, target)
+		}
+		return err
+	}
+
+	if previous != "" {
+		return os.RemoveAll(previous)
+	}
+	return nil
+}
+
+func extractRego(archive []byte) (map[string][]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	files := make(map[string][]byte)
+	total := 0
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if header.Typeflag != tar.TypeReg || !strings.HasSuffix(header.Name, ".rego") {
+			continue
+		}
+
+		name := filepath.Clean(filepath.FromSlash(header.Name))
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("invalid file path %s", header.Name)
+		}
+
+		content, err := io.ReadAll(io.LimitReader(reader, maxArchiveSize-int64(total)+1))
+		if err != nil {
+			return nil, err
+		}
+		total += len(content)
+		if total > maxArchiveSize {
+			return nil, fmt.Errorf("the extracted policies exceed %d bytes", maxArchiveSize)
+		}
+		files[name] = content
+	}
+
+	return files, nil
+}
+
+// fetch reads an https URL or a local file (plain http is refused)
+func fetch(source string) ([]byte, error) {
+	if strings.HasPrefix(strings.ToLower(source), "http://") {
+		return nil, fmt.Errorf("plain http isn't allowed, use https")
+	}
+	if !strings.HasPrefix(strings.ToLower(source), "https://") {
+		return os.ReadFile(source)
+	}
+
+	client := http.Client{Timeout: time.Minute}
+	resp, err := client.Get(source)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxArchiveSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxArchiveSize {
+		return nil, fmt.Errorf("the response exceeds %d bytes", maxArchiveSize)
+	}
+	return data, nil
+}
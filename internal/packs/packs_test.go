@@ -0,0 +1,154 @@
+package packs
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func makeArchive(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func signedPack(t *testing.T, archive []byte, key ed25519.PrivateKey) *Pack {
+	digest := sha256.Sum256(archive)
+	pack := &Pack{
+		Name:    "fintech",
+		Version: "1.2.0",
+		SHA256:  hex.EncodeToString(digest[:]),
+	}
+	pack.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, SignedMessage(pack)))
+	return pack
+}
+
+func TestVerify(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	otherPublic, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	archive := makeArchive(t, map[string]string{"fintech/repository.rego": "package repository"})
+	pack := signedPack(t, archive, private)
+
+	require.NoError(t, Verify(pack, archive, []ed25519.PublicKey{otherPublic, public}))
+	require.Error(t, Verify(pack, archive, []ed25519.PublicKey{otherPublic}))
+
+	tampered := append([]byte{}, archive...)
+	tampered[len(tampered)-1] ^= 0xff
+	require.Error(t, Verify(pack, tampered, []ed25519.PublicKey{public}))
+
+	// the signed archive can't be listed under another name or version
+	renamed := *pack
+	renamed.Name = "healthcare"
+	require.Error(t, Verify(&renamed, archive, []ed25519.PublicKey{public}))
+	downgraded := *pack
+	downgraded.Version = "1.3.0"
+	require.Error(t, Verify(&downgraded, archive, []ed25519.PublicKey{public}))
+
+	keys, err := ParsePublicKeys([]string{base64.StdEncoding.EncodeToString(public)})
+	require.NoError(t, err)
+	require.Equal(t, []ed25519.PublicKey{public}, keys)
+	_, err = ParsePublicKeys([]string{"not-a-key"})
+	require.Error(t, err)
+}
+
+func TestInstall(t *testing.T) {
+	dir := t.TempDir()
+	archive := makeArchive(t, map[string]string{
+		"policies/repository.rego": "package repository",
+		"README.md":                "ignored",
+	})
+	pack := &Pack{Name: "fintech"}
+
+	installed, err := Install(pack, archive, dir, nil)
+	require.NoError(t, err)
+	require.Len(t, installed, 1)
+	require.Equal(t, filepath.Join(dir, "fintech", "policies", "repository.rego"), installed[0])
+
+	content, err := os.ReadFile(filepath.Join(dir, "fintech", "policies", "repository.rego"))
+	require.NoError(t, err)
+	require.Equal(t, "package repository", string(content))
+	_, err = os.Stat(filepath.Join(dir, "fintech", "README.md"))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestInstallRejectsPathTraversal(t *testing.T) {
+	archive := makeArchive(t, map[string]string{"../../evil.rego": "package repository"})
+	_, err := Install(&Pack{Name: "fintech"}, archive, t.TempDir(), nil)
+	require.Error(t, err)
+}
+
+func TestFailedUpgradeKeepsInstalledPack(t *testing.T) {
+	dir := t.TempDir()
+	pack := &Pack{Name: "fintech"}
+	_, err := Install(pack, makeArchive(t, map[string]string{"repository.rego": "package repository"}), dir, nil)
+	require.NoError(t, err)
+
+	invalid := func(string) error { return fmt.Errorf("rego_parse_error") }
+	_, err = Install(pack, makeArchive(t, map[string]string{"repository.rego": "package"}), dir, invalid)
+	require.ErrorContains(t, err, "rego_parse_error")
+
+	content, err := os.ReadFile(filepath.Join(dir, "fintech", "repository.rego"))
+	require.NoError(t, err)
+	require.Equal(t, "package repository", string(content))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "the staging directory should be removed")
+
+	// a valid upgrade replaces the whole pack
+	validated := ""
+	_, err = Install(pack, makeArchive(t, map[string]string{"organization.rego": "package organization"}), dir, func(staging string) error {
+		validated = staging
+		_, err := os.Stat(filepath.Join(staging, "organization.rego"))
+		return err
+	})
+	require.NoError(t, err)
+	require.Equal(t, dir, filepath.Dir(validated))
+	_, err = os.Stat(filepath.Join(dir, "fintech", "repository.rego"))
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(dir, "fintech", "organization.rego"))
+	require.NoError(t, err)
+	entries, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
+
+func TestLoadIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"packs": [{"name": "healthcare", "description": "HIPAA related policies"}]}`), 0644))
+
+	index, err := LoadIndex(path)
+	require.NoError(t, err)
+	pack, err := index.Find("healthcare")
+	require.NoError(t, err)
+	require.Equal(t, "HIPAA related policies", pack.Description)
+	_, err = index.Find("fintech")
+	require.Error(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"packs": [{"name": "../evil"}]}`), 0644))
+	_, err = LoadIndex(path)
+	require.Error(t, err)
+
+	_, err = LoadIndex("http://packs.example.com/index.json")
+	require.ErrorContains(t, err, "plain http isn't allowed")
+}
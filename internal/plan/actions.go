@@ -0,0 +1,344 @@
+package plan
+
+import (
+	"github.com/Legit-Labs/legitify/internal/common/scm_type"
+)
+
+// APICall is the API request that applies a remediation action; the {placeholders} of the path are filled per affected entity
+type APICall struct {
+	Method string `yaml:"method" json:"method"`
+	Path   string `yaml:"path" json:"path"`
+}
+
+// actionInfo describes a remediation action: a change of a single setting (or a group of related settings) that fixes one or more policies
+type actionInfo struct {
+	Name      string
+	APICall   *APICall
+	Terraform string
+}
+
+// manualAction groups the policies that have no known API call (e.g. settings that can only be changed in the UI)
+var manualAction = actionInfo{Name: "Manual remediation"}
+
+var (
+	githubOrgSettings = actionInfo{
+		Name:    "Update the organization settings",
+		APICall: &APICall{Method: "PATCH", Path: "/orgs/{org}"},
+		Terraform: `resource "github_organization_settings" "org" {
+  billing_email                          = "<billing_email>"
+  default_repository_permission          = "none"
+  members_can_create_public_repositories = false
+}`,
+	}
+	githubOrgActionsPermissions = actionInfo{
+		Name:    "Restrict the organization actions permissions",
+		APICall: &APICall{Method: "PUT", Path: "/orgs/{org}/actions/permissions"},
+		Terraform: `resource "github_actions_organization_permissions" "org" {
+  enabled_repositories = "selected"
+  allowed_actions      = "selected"
+  allowed_actions_config {
+    github_owned_allowed = true
+    verified_allowed     = true
+  }
+}`,
+	}
+	githubOrgSelectedActions = actionInfo{
+		Name:    "Restrict the allowed actions of the organization",
+		APICall: &APICall{Method: "PUT", Path: "/orgs/{org}/actions/permissions/selected-actions"},
+	}
+	githubOrgWorkflowPermissions = actionInfo{
+		Name:    "Restrict the organization default workflow permissions",
+		APICall: &APICall{Method: "PUT", Path: "/orgs/{org}/actions/permissions/workflow"},
+	}
+	githubOrgOIDCSubjectClaim = actionInfo{
+		Name:    "Scope the organization OIDC subject claim",
+		APICall: &APICall{Method: "PUT", Path: "/orgs/{org}/actions/oidc/customization/sub"},
+		Terraform: `resource "github_actions_organization_oidc_subject_claim_customization_template" "org" {
+  include_claim_keys = ["repo", "context"]
+}`,
+	}
+	githubOrgWebhook = actionInfo{
+		Name:    "Update the organization webhook configuration",
+		APICall: &APICall{Method: "PATCH", Path: "/orgs/{org}/hooks/{hook_id}/config"},
+		Terraform: `resource "github_organization_webhook" "hook" {
+  events = ["<event>"]
+  configuration {
+    url          = "https://<url>"
+    content_type = "json"
+    secret       = var.webhook_secret
+    insecure_ssl = false
+  }
+}`,
+	}
+	githubOrgMember = actionInfo{
+		Name:    "Remove the organization member",
+		APICall: &APICall{Method: "DELETE", Path: "/orgs/{org}/memberships/{username}"},
+	}
+	githubOrgInvitation = actionInfo{
+		Name:    "Cancel the organization invitation",
+		APICall: &APICall{Method: "DELETE", Path: "/orgs/{org}/invitations/{invitation_id}"},
+	}
+	githubRunnerGroup = actionInfo{
+		Name:    "Restrict the runner group",
+		APICall: &APICall{Method: "PATCH", Path: "/orgs/{org}/actions/runner-groups/{runner_group_id}"},
+		Terraform: `resource "github_actions_runner_group" "group" {
+  name                       = "<runner_group>"
+  visibility                 = "selected"
+  selected_repository_ids    = [<repository_ids>]
+  allows_public_repositories = false
+}`,
+	}
+	githubRepoSettings = actionInfo{
+		Name:    "Update the repository settings",
+		APICall: &APICall{Method: "PATCH", Path: "/repos/{owner}/{repo}"},
+		Terraform: `resource "github_repository" "repo" {
+  name          = "<repo>"
+  allow_forking = false
+  security_and_analysis {
+    secret_scanning {
+      status = "enabled"
+    }
+    secret_scanning_push_protection {
+      status = "enabled"
+    }
+  }
+}`,
+	}
+	githubBranchProtection = actionInfo{
+		Name:    "Protect the default branch",
+		APICall: &APICall{Method: "PUT", Path: "/repos/{owner}/{repo}/branches/{branch}/protection"},
+		Terraform: `resource "github_branch_protection" "default_branch" {
+  repository_id                   = "<repo>"
+  pattern                         = "<branch>"
+  enforce_admins                  = true
+  allows_deletions                = false
+  allows_force_pushes             = false
+  require_signed_commits          = true
+  required_linear_history         = true
+  require_conversation_resolution = true
+  required_status_checks {
+    strict = true
+  }
+  required_pull_request_reviews {
+    required_approving_review_count = 2
+    dismiss_stale_reviews           = true
+    require_code_owner_reviews      = true
+    require_last_push_approval      = true
+    restrict_dismissals             = true
+  }
+}`,
+	}
+	githubVulnerabilityAlerts = actionInfo{
+		Name:    "Enable the vulnerability alerts",
+		APICall: &APICall{Method: "PUT", Path: "/repos/{owner}/{repo}/vulnerability-alerts"},
+		Terraform: `resource "github_repository" "repo" {
+  name                 = "<repo>"
+  vulnerability_alerts = true
+}`,
+	}
+	githubDependabotSecurityUpdates = actionInfo{
+		Name:    "Enable the dependabot security updates",
+		APICall: &APICall{Method: "PUT", Path: "/repos/{owner}/{repo}/automated-security-fixes"},
+		Terraform: `resource "github_repository_dependabot_security_updates" "repo" {
+  repository = "<repo>"
+  enabled    = true
+}`,
+	}
+	githubCodeScanning = actionInfo{
+		Name:    "Enable the code scanning default setup",
+		APICall: &APICall{Method: "PATCH", Path: "/repos/{owner}/{repo}/code-scanning/default-setup"},
+	}
+	githubRepoActionsPermissions = actionInfo{
+		Name:    "Restrict the repository actions permissions",
+		APICall: &APICall{Method: "PUT", Path: "/repos/{owner}/{repo}/actions/permissions"},
+		Terraform: `resource "github_actions_repository_permissions" "repo" {
+  repository      = "<repo>"
+  allowed_actions = "selected"
+  allowed_actions_config {
+    github_owned_allowed = true
+    verified_allowed     = true
+  }
+}`,
+	}
+	githubRepoWorkflowPermissions = actionInfo{
+		Name:    "Restrict the repository default workflow permissions",
+		APICall: &APICall{Method: "PUT", Path: "/repos/{owner}/{repo}/actions/permissions/workflow"},
+	}
+	githubRepoOIDCSubjectClaim = actionInfo{
+		Name:    "Scope the repository OIDC subject claim",
+		APICall: &APICall{Method: "PUT", Path: "/repos/{owner}/{repo}/actions/oidc/customization/sub"},
+		Terraform: `resource "github_actions_repository_oidc_subject_claim_customization_template" "repo" {
+  repository         = "<repo>"
+  use_default        = false
+  include_claim_keys = ["repo", "context"]
+}`,
+	}
+	githubRepoWebhook = actionInfo{
+		Name:    "Update the repository webhook configuration",
+		APICall: &APICall{Method: "PATCH", Path: "/repos/{owner}/{repo}/hooks/{hook_id}/config"},
+		Terraform: `resource "github_repository_webhook" "hook" {
+  repository = "<repo>"
+  events     = ["<event>"]
+  configuration {
+    url          = "https://<url>"
+    content_type = "json"
+    secret       = var.webhook_secret
+    insecure_ssl = false
+  }
+}`,
+	}
+	githubRepoPages = actionInfo{
+		Name:    "Update the repository pages settings",
+		APICall: &APICall{Method: "PUT", Path: "/repos/{owner}/{repo}/pages"},
+	}
+
+	gitlabGroupSettings = actionInfo{
+		Name:    "Update the group settings",
+		APICall: &APICall{Method: "PUT", Path: "/groups/{id}"},
+		Terraform: `resource "gitlab_group" "group" {
+  name                              = "<group>"
+  path                              = "<group>"
+  require_two_factor_authentication = true
+  prevent_forking_outside_group     = true
+}`,
+	}
+	gitlabGroupApprovalSettings = actionInfo{
+		Name:    "Update the group merge request approval settings",
+		APICall: &APICall{Method: "PUT", Path: "/groups/{id}/merge_request_approval_setting"},
+	}
+	gitlabGroupWebhook = actionInfo{
+		Name:    "Update the group webhook",
+		APICall: &APICall{Method: "PUT", Path: "/groups/{id}/hooks/{hook_id}"},
+		Terraform: `resource "gitlab_group_hook" "hook" {
+  group                   = "<group>"
+  url                     = "https://<url>"
+  enable_ssl_verification = true
+}`,
+	}
+	gitlabProjectApprovalSettings = actionInfo{
+		Name:    "Update the project merge request approval settings",
+		APICall: &APICall{Method: "POST", Path: "/projects/{id}/approvals"},
+		Terraform: `resource "gitlab_project_level_mr_approvals" "project" {
+  project                                        = "<project>"
+  reset_approvals_on_push                        = true
+  disable_overriding_approvers_per_merge_request = true
+  merge_requests_author_approval                 = false
+  merge_requests_disable_committers_approval     = true
+}`,
+	}
+	gitlabProjectSettings = actionInfo{
+		Name:    "Update the project settings",
+		APICall: &APICall{Method: "PUT", Path: "/projects/{id}"},
+		Terraform: `resource "gitlab_project" "project" {
+  name                   = "<project>"
+  shared_runners_enabled = false
+  container_expiration_policy {
+    enabled = true
+  }
+}`,
+	}
+	gitlabProtectedTag = actionInfo{
+		Name:    "Protect the release tags",
+		APICall: &APICall{Method: "POST", Path: "/projects/{id}/protected_tags"},
+		Terraform: `resource "gitlab_tag_protection" "releases" {
+  project             = "<project>"
+  tag                 = "v*"
+  create_access_level = "maintainer"
+}`,
+	}
+
+	codecommitApprovalRule = actionInfo{
+		Name:    "Associate an approval rule template with the repository",
+		APICall: &APICall{Method: "POST", Path: "/ (X-Amz-Target: CodeCommit_20150413.AssociateApprovalRuleTemplateWithRepository)"},
+		Terraform: `resource "aws_codecommit_approval_rule_template_association" "repo" {
+  approval_rule_template_name = "<template>"
+  repository_name             = "<repo>"
+}`,
+	}
+)
+
+// actions maps the policies (by their qualified name, without the "data." prefix) of each SCM to the action that fixes them
+var actions = map[scm_type.ScmType]map[string]actionInfo{
+	scm_type.GitHub: {
+		"organization.default_repository_permission_is_not_none": githubOrgSettings,
+		"organization.non_admins_can_create_public_repositories": githubOrgSettings,
+		"organization.organization_webhook_no_secret":            githubOrgWebhook,
+		"organization.organization_webhook_doesnt_require_ssl":   githubOrgWebhook,
+		"organization.organization_webhook_delivers_over_http":   githubOrgWebhook,
+
+		"actions.all_github_actions_are_allowed":                         githubOrgActionsPermissions,
+		"actions.all_repositories_can_run_github_actions":                githubOrgActionsPermissions,
+		"actions.selected_actions_allow_any_third_party_action":          githubOrgSelectedActions,
+		"actions.token_default_permissions_is_read_write":                githubOrgWorkflowPermissions,
+		"actions.actions_can_approve_pull_requests":                      githubOrgWorkflowPermissions,
+		"actions.oidc_subject_claim_not_scoped_to_context":               githubOrgOIDCSubjectClaim,
+		"actions.oidc_subject_claim_not_scoped_to_repository":            githubOrgOIDCSubjectClaim,
+		"runner_group.runner_group_can_be_used_by_public_repositories":   githubRunnerGroup,
+		"runner_group.runner_group_not_limited_to_selected_repositories": githubRunnerGroup,
+
+		"member.stale_member_found":     githubOrgMember,
+		"member.stale_admin_found":      githubOrgMember,
+		"member.dormant_member_found":   githubOrgMember,
+		"member.dormant_admin_found":    githubOrgMember,
+		"member.stale_invitation_found": githubOrgInvitation,
+
+		"repository.allow_forking_enabled":                        githubRepoSettings,
+		"repository.secret_scanning_not_enabled":                  githubRepoSettings,
+		"repository.secret_scanning_push_protection_not_enabled":  githubRepoSettings,
+		"repository.missing_default_branch_protection":            githubBranchProtection,
+		"repository.missing_default_branch_protection_deletion":   githubBranchProtection,
+		"repository.missing_default_branch_protection_force_push": githubBranchProtection,
+		"repository.code_review_not_required":                     githubBranchProtection,
+		"repository.code_review_by_two_members_not_required":      githubBranchProtection,
+		"repository.code_review_not_limited_to_code_owners":       githubBranchProtection,
+		"repository.code_review_can_be_bypassed":                  githubBranchProtection,
+		"repository.dismisses_stale_reviews":                      githubBranchProtection,
+		"repository.review_dismissal_allowed":                     githubBranchProtection,
+		"repository.last_push_approval_not_required":              githubBranchProtection,
+		"repository.requires_status_checks":                       githubBranchProtection,
+		"repository.requires_branches_up_to_date_before_merge":    githubBranchProtection,
+		"repository.no_conversation_resolution":                   githubBranchProtection,
+		"repository.no_signed_commits":                            githubBranchProtection,
+		"repository.non_linear_history":                           githubBranchProtection,
+		"repository.pushes_are_not_restricted":                    githubBranchProtection,
+		"repository.vulnerability_alerts_not_enabled":             githubVulnerabilityAlerts,
+		"repository.dependabot_security_updates_not_enabled":      githubDependabotSecurityUpdates,
+		"repository.code_scanning_not_enabled":                    githubCodeScanning,
+		"repository.all_github_actions_are_allowed":               githubRepoActionsPermissions,
+		"repository.token_default_permissions_is_read_write":      githubRepoWorkflowPermissions,
+		"repository.actions_can_approve_pull_requests":            githubRepoWorkflowPermissions,
+		"repository.oidc_subject_claim_not_scoped_to_context":     githubRepoOIDCSubjectClaim,
+		"repository.oidc_subject_claim_not_scoped_to_repository":  githubRepoOIDCSubjectClaim,
+		"repository.repository_webhook_no_secret":                 githubRepoWebhook,
+		"repository.repository_webhook_doesnt_require_ssl":        githubRepoWebhook,
+		"repository.repository_webhook_delivers_over_http":        githubRepoWebhook,
+		"repository.pages_custom_domain_without_https":            githubRepoPages,
+		"repository.pages_public_for_private_repository":          githubRepoPages,
+	},
+	scm_type.GitLab: {
+		"organization.two_factor_authentication_not_required_for_group":           gitlabGroupSettings,
+		"organization.collaborators_can_fork_repositories_to_external_namespaces": gitlabGroupSettings,
+		"organization.approvals_not_reset_on_push":                                gitlabGroupApprovalSettings,
+		"organization.author_can_approve_merge_request":                           gitlabGroupApprovalSettings,
+		"organization.committers_can_approve_merge_request":                       gitlabGroupApprovalSettings,
+		"organization.organization_webhook_doesnt_require_ssl":                    gitlabGroupWebhook,
+
+		"repository.approvals_not_reset_on_push":                  gitlabProjectApprovalSettings,
+		"repository.author_can_approve_merge_request":             gitlabProjectApprovalSettings,
+		"repository.committers_can_approve_merge_request":         gitlabProjectApprovalSettings,
+		"repository.container_registry_cleanup_policy_disabled":   gitlabProjectSettings,
+		"repository.high_sensitivity_project_uses_shared_runners": gitlabProjectSettings,
+		"repository.release_tag_not_protected":                    gitlabProtectedTag,
+	},
+	scm_type.CodeCommit: {
+		"repository.repository_has_no_approval_rule": codecommitApprovalRule,
+	},
+}
+
+// actionOf returns the action that fixes the policy, or the manual action if there's no known one
+func actionOf(scmType scm_type.ScmType, policyName string) actionInfo {
+	if action, ok := actions[scmType][policyName]; ok {
+		return action
+	}
+	return manualAction
+}
@@ -0,0 +1,26 @@
+package plan
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Legit-Labs/legitify/internal/common/scm_type"
+	"github.com/Legit-Labs/legitify/internal/opa"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionsReferExistingPolicies(t *testing.T) {
+	for scmType, policies := range actions {
+		engine, err := opa.Load(nil, scmType)
+		require.NoError(t, err)
+
+		existing := make(map[string]bool)
+		for _, annotation := range engine.Annotations().Flatten() {
+			existing[strings.TrimPrefix(annotation.Path.String(), "data.")] = true
+		}
+		for policyName := range policies {
+			require.Truef(t, existing[policyName], "unknown %s policy %s", scmType, policyName)
+		}
+	}
+	require.Equal(t, manualAction, actionOf(scm_type.GitHub, "repository.unknown"))
+}
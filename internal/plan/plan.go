@@ -0,0 +1,183 @@
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Legit-Labs/legitify/internal/analyzers"
+	"github.com/Legit-Labs/legitify/internal/common/scm_type"
+	"github.com/Legit-Labs/legitify/internal/common/severity"
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme"
+)
+
+// Step is a single remediation action: fixing one policy across all of its affected entities.
+type Step struct {
+	Order            int      `yaml:"order" json:"order"`
+	PolicyName       string   `yaml:"policy_name" json:"policyName"`
+	Title            string   `yaml:"title" json:"title"`
+	Namespace        string   `yaml:"namespace" json:"namespace"`
+	Severity         string   `yaml:"severity" json:"severity"`
+	SeverityLabel    string   `yaml:"severity_label,omitempty" json:"severityLabel,omitempty"`
+	AffectedCount    int      `yaml:"affected_count" json:"affectedCount"`
+	AffectedEntities []string `yaml:"affected_entities" json:"affectedEntities"`
+	RemediationSteps []string `yaml:"remediation_steps" json:"remediationSteps"`
+}
+
+// Action is a remediation action (e.g. an API call that changes a group of related settings) with the steps it fixes.
+type Action struct {
+	Order   int      `yaml:"order" json:"order"`
+	Name    string   `yaml:"name" json:"name"`
+	APICall *APICall `yaml:"api_call,omitempty" json:"apiCall,omitempty"`
+	// Terraform is an example of the equivalent terraform configuration, when there's a resource for it
+	Terraform        string   `yaml:"terraform,omitempty" json:"terraform,omitempty"`
+	AffectedCount    int      `yaml:"affected_count" json:"affectedCount"`
+	AffectedEntities []string `yaml:"affected_entities" json:"affectedEntities"`
+	Steps            []Step   `yaml:"steps" json:"steps"`
+}
+
+type Plan struct {
+	TotalActions  int      `yaml:"total_actions" json:"totalActions"`
+	TotalSteps    int      `yaml:"total_steps" json:"totalSteps"`
+	TotalAffected int      `yaml:"total_affected" json:"totalAffected"`
+	Actions       []Action `yaml:"actions" json:"actions"`
+}
+
+// serializedViolation mirrors scheme.Violation without the enrichments,
+// which can't be deserialized back into their original types.
+type serializedViolation struct {
+	ViolationEntityType string                 `json:"violationEntityType"`
+	CanonicalLink       string                 `json:"canonicalLink"`
+	Status              analyzers.PolicyStatus `json:"Status"`
+}
+
+type serializedOutputData struct {
+	PolicyInfo scheme.PolicyInfo     `json:"policyInfo"`
+	Violations []serializedViolation `json:"violations"`
+}
+
+// FromFlattenedJson builds a remediation plan out of a legitify json output (flattened scheme) of the given SCM.
+func FromFlattenedJson(data []byte, scmType scm_type.ScmType) (*Plan, error) {
+	var findings map[string]serializedOutputData
+	if err := json.Unmarshal(data, &findings); err != nil {
+		return nil, fmt.Errorf("failed to parse results (expecting the json format with the flattened scheme): %v", err)
+	}
+
+	return newPlan(findings, scmType), nil
+}
+
+func newPlan(findings map[string]serializedOutputData, scmType scm_type.ScmType) *Plan {
+	plan := &Plan{
+		Actions: []Action{},
+	}
+
+	var steps []Step
+	for policyName, outputData := range findings {
+		affected := affectedEntities(outputData.Violations)
+		if len(affected) == 0 {
+			continue
+		}
+
+		steps = append(steps, Step{
+			PolicyName:       policyName,
+			Title:            outputData.PolicyInfo.Title,
+			Namespace:        outputData.PolicyInfo.Namespace,
+			Severity:         outputData.PolicyInfo.Severity,
+			SeverityLabel:    outputData.PolicyInfo.SeverityLabel,
+			AffectedCount:    len(affected),
+			AffectedEntities: affected,
+			RemediationSteps: outputData.PolicyInfo.RemediationSteps,
+		})
+		plan.TotalAffected += len(affected)
+	}
+
+	sort.Slice(steps, func(i, j int) bool {
+		a, b := steps[i], steps[j]
+		if a.Severity != b.Severity {
+			return severity.Less(a.Severity, b.Severity)
+		}
+		if a.AffectedCount != b.AffectedCount {
+			return a.AffectedCount > b.AffectedCount
+		}
+		return a.PolicyName < b.PolicyName
+	})
+
+	for i := range steps {
+		steps[i].Order = i + 1
+	}
+	plan.TotalSteps = len(steps)
+	plan.Actions = groupByAction(steps, scmType)
+	plan.TotalActions = len(plan.Actions)
+
+	return plan
+}
+
+// groupByAction groups the (ordered) steps by the action that fixes them; the actions are ordered by their first step,
+// with the manual remediation last
+func groupByAction(steps []Step, scmType scm_type.ScmType) []Action {
+	result := []Action{}
+	index := make(map[string]int)
+	var manual *Action
+
+	for _, step := range steps {
+		info := actionOf(scmType, strings.TrimPrefix(step.PolicyName, "data."))
+		var action *Action
+		if info.APICall == nil {
+			if manual == nil {
+				manual = &Action{Name: info.Name}
+			}
+			action = manual
+		} else {
+			i, ok := index[info.Name]
+			if !ok {
+				i = len(result)
+				index[info.Name] = i
+				result = append(result, Action{Name: info.Name, APICall: info.APICall, Terraform: info.Terraform})
+			}
+			action = &result[i]
+		}
+		action.Steps = append(action.Steps, step)
+		action.AffectedEntities = append(action.AffectedEntities, step.AffectedEntities...)
+	}
+	if manual != nil {
+		result = append(result, *manual)
+	}
+
+	for i := range result {
+		result[i].Order = i + 1
+		result[i].AffectedEntities = uniqueSorted(result[i].AffectedEntities)
+		result[i].AffectedCount = len(result[i].AffectedEntities)
+	}
+
+	return result
+}
+
+func uniqueSorted(values []string) []string {
+	seen := make(map[string]bool)
+	result := []string{}
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func affectedEntities(violations []serializedViolation) []string {
+	seen := make(map[string]bool)
+	result := []string{}
+
+	for _, v := range violations {
+		if v.Status != analyzers.PolicyFailed || seen[v.CanonicalLink] {
+			continue
+		}
+		seen[v.CanonicalLink] = true
+		result = append(result, v.CanonicalLink)
+	}
+	sort.Strings(result)
+
+	return result
+}
@@ -0,0 +1,100 @@
+package plan_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Legit-Labs/legitify/internal/analyzers"
+	"github.com/Legit-Labs/legitify/internal/common/scm_type"
+	"github.com/Legit-Labs/legitify/internal/common/severity"
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme"
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme/scheme_test.go"
+	"github.com/Legit-Labs/legitify/internal/plan"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanFromFlattenedJson(t *testing.T) {
+	sample := scheme_test.SchemeSample()
+	data, err := json.Marshal(sample)
+	require.Nilf(t, err, "Error marshaling sample: %v", err)
+
+	result, err := plan.FromFlattenedJson(data, scm_type.GitHub)
+	require.Nilf(t, err, "Error creating plan: %v", err)
+
+	require.Equal(t, 2, result.TotalSteps)
+	require.Equal(t, 4, result.TotalAffected)
+
+	// the sample policies have no known action
+	require.Equal(t, 1, result.TotalActions)
+	manual := result.Actions[0]
+	require.Nil(t, manual.APICall)
+	require.Len(t, manual.Steps, 2)
+
+	// higher severity comes first
+	first := manual.Steps[0]
+	require.Equal(t, 1, first.Order)
+	require.Equal(t, scheme_test.FullyQualifiedPolicyNameSample2(), first.PolicyName)
+	require.Equal(t, scheme_test.RemediationStepsSample2, first.RemediationSteps)
+	require.Equal(t, 2, first.AffectedCount)
+
+	second := manual.Steps[1]
+	require.Equal(t, 2, second.Order)
+	require.Equal(t, scheme_test.FullyQualifiedPolicyNameSample(), second.PolicyName)
+}
+
+func TestPlanSkipsPassedPolicies(t *testing.T) {
+	sample := scheme.FilterViolationsByStatus(scheme_test.SchemeSample(), analyzers.PolicyPassed)
+	data, err := json.Marshal(sample)
+	require.Nilf(t, err, "Error marshaling sample: %v", err)
+
+	result, err := plan.FromFlattenedJson(data, scm_type.GitHub)
+	require.Nilf(t, err, "Error creating plan: %v", err)
+	require.Equal(t, 0, result.TotalSteps)
+	require.Empty(t, result.Actions)
+}
+
+func TestPlanGroupsByAction(t *testing.T) {
+	violation := func(link string) map[string]interface{} {
+		return map[string]interface{}{"canonicalLink": link, "Status": analyzers.PolicyFailed}
+	}
+	policy := func(sev string, links ...string) map[string]interface{} {
+		var violations []map[string]interface{}
+		for _, link := range links {
+			violations = append(violations, violation(link))
+		}
+		return map[string]interface{}{"policyInfo": map[string]interface{}{"severity": sev}, "violations": violations}
+	}
+	data, err := json.Marshal(map[string]interface{}{
+		"data.repository.missing_default_branch_protection": policy(severity.High, "repo1"),
+		"data.repository.code_review_not_required":          policy(severity.Medium, "repo1", "repo2"),
+		"data.repository.vulnerability_alerts_not_enabled":  policy(severity.Medium, "repo3"),
+		"data.repository.missing_license":                   policy(severity.Critical, "repo1"),
+	})
+	require.Nil(t, err)
+
+	result, err := plan.FromFlattenedJson(data, scm_type.GitHub)
+	require.Nil(t, err)
+	require.Equal(t, 4, result.TotalSteps)
+	require.Equal(t, 3, result.TotalActions)
+
+	protection := result.Actions[0]
+	require.Equal(t, 1, protection.Order)
+	require.Equal(t, &plan.APICall{Method: "PUT", Path: "/repos/{owner}/{repo}/branches/{branch}/protection"}, protection.APICall)
+	require.Contains(t, protection.Terraform, "github_branch_protection")
+	require.Len(t, protection.Steps, 2)
+	require.Equal(t, []string{"repo1", "repo2"}, protection.AffectedEntities)
+	require.Equal(t, 2, protection.AffectedCount)
+
+	require.Equal(t, "PUT", result.Actions[1].APICall.Method)
+	require.Equal(t, []string{"repo3"}, result.Actions[1].AffectedEntities)
+
+	// the manual remediation comes last, regardless of the severity
+	require.Nil(t, result.Actions[2].APICall)
+	require.Equal(t, "data.repository.missing_license", result.Actions[2].Steps[0].PolicyName)
+	require.Equal(t, 1, result.Actions[2].Steps[0].Order)
+}
+
+func TestPlanInvalidInput(t *testing.T) {
+	_, err := plan.FromFlattenedJson([]byte("[1, 2]"), scm_type.GitHub)
+	require.NotNil(t, err)
+}
@@ -0,0 +1,288 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Legit-Labs/legitify/internal/analyzers"
+	ghclient "github.com/Legit-Labs/legitify/internal/clients/github"
+	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/Legit-Labs/legitify/internal/common/severity"
+	"github.com/Legit-Labs/legitify/internal/enricher/enrichers"
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme"
+	"github.com/Legit-Labs/legitify/internal/publishers"
+	"github.com/google/go-github/v44/github"
+)
+
+const (
+	checkRunName = "legitify"
+	// the checks API accepts up to 50 annotations per request
+	maxAnnotationsPerRequest = 50
+	// findings are about the repository settings rather than a specific file
+	annotationPath = ".github"
+)
+
+type checksPublisher struct {
+	ctx    context.Context
+	client *ghclient.Client
+}
+
+// NewChecksPublisher creates a check run on each scanned repository, summarizing its violations.
+// Note: check runs can only be created using a GitHub App token (e.g. the GITHUB_TOKEN of a GitHub Actions workflow).
+func NewChecksPublisher(ctx context.Context, client *ghclient.Client) publishers.Publisher {
+	return &checksPublisher{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+func (p *checksPublisher) Name() string {
+	return "GitHub Check Runs"
+}
+
+// PublishesPerOrganization is true since each repository gets its own check run
+func (p *checksPublisher) PublishesPerOrganization() bool {
+	return true
+}
+
+type repositoryFinding struct {
+	policyInfo scheme.PolicyInfo
+	status     analyzers.PolicyStatus
+}
+
+type repositoryFindings struct {
+	link     string
+	owner    string
+	name     string
+	findings []repositoryFinding
+	// contact is the security contact of the repository (see --security-contacts)
+	contact string
+}
+
+func (r *repositoryFindings) scanID() string {
+	for _, f := range r.findings {
+		if f.policyInfo.ScanID != "" {
+			return f.policyInfo.ScanID
+		}
+	}
+	return ""
+}
+
+func (r *repositoryFindings) failed() []repositoryFinding {
+	var result []repositoryFinding
+	for _, f := range r.findings {
+		if f.status == analyzers.PolicyFailed {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+func (p *checksPublisher) Publish(results scheme.FlattenedScheme) error {
+	repositories := groupByRepository(results)
+
+	var failures []string
+	for _, repo := range repositories {
+		if err := p.publishRepository(repo); err != nil {
+			log.Printf("failed to create check run for %s/%s: %v", repo.owner, repo.name, err)
+			failures = append(failures, fmt.Sprintf("%s/%s", repo.owner, repo.name))
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("failed to create check runs for: %s", strings.Join(failures, ", "))
+	}
+
+	return nil
+}
+
+func groupByRepository(results scheme.FlattenedScheme) []*repositoryFindings {
+	byLink := make(map[string]*repositoryFindings)
+
+	for _, policyName := range results.Keys() {
+		data := results.GetPolicyData(policyName)
+		if data.PolicyInfo.Namespace != namespace.Repository {
+			continue
+		}
+
+		for _, violation := range data.Violations {
+			owner, name, ok := parseRepositoryLink(violation.CanonicalLink)
+			if !ok {
+				continue
+			}
+
+			repo, exists := byLink[violation.CanonicalLink]
+			if !exists {
+				repo = &repositoryFindings{link: violation.CanonicalLink, owner: owner, name: name}
+				byLink[violation.CanonicalLink] = repo
+			}
+			if contact, ok := violation.Aux[enrichers.SecurityContact].(*enrichers.SecurityContactEnrichment); ok {
+				repo.contact = contact.Contact().String()
+			}
+			repo.findings = append(repo.findings, repositoryFinding{
+				policyInfo: data.PolicyInfo,
+				status:     violation.Status,
+			})
+		}
+	}
+
+	var repositories []*repositoryFindings
+	for _, repo := range byLink {
+		repositories = append(repositories, repo)
+	}
+	sort.Slice(repositories, func(i, j int) bool {
+		return repositories[i].owner+"/"+repositories[i].name < repositories[j].owner+"/"+repositories[j].name
+	})
+
+	return repositories
+}
+
+// parseRepositoryLink extracts the owner and name out of a repository url (e.g. https://github.com/owner/name)
+func parseRepositoryLink(link string) (owner, name string, ok bool) {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return "", "", false
+	}
+
+	parts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	if len(parts) < 2 {
+		return "", "", false
+	}
+
+	return parts[len(parts)-2], parts[len(parts)-1], true
+}
+
+func (p *checksPublisher) publishRepository(repo *repositoryFindings) error {
+	_, sha, err := repositoryHead(p.ctx, p.client, repo.owner, repo.name)
+	if err != nil {
+		return err
+	}
+
+	failed := repo.failed()
+	conclusion := "success"
+	if len(failed) > 0 {
+		conclusion = "failure"
+	}
+
+	annotations := buildAnnotations(failed)
+	batch, remaining := splitAnnotations(annotations)
+	now := github.Timestamp{Time: time.Now()}
+	output := buildOutput(repo, failed, batch)
+
+	checkRun, _, err := p.client.Client().Checks.CreateCheckRun(p.ctx, repo.owner, repo.name, github.CreateCheckRunOptions{
+		Name:        checkRunName,
+		HeadSHA:     sha,
+		Status:      github.String("completed"),
+		Conclusion:  github.String(conclusion),
+		CompletedAt: &now,
+		Output:      output,
+	})
+	if err != nil {
+		return err
+	}
+
+	// the rest of the annotations are appended by updating the check run
+	for len(remaining) > 0 {
+		batch, remaining = splitAnnotations(remaining)
+		output.Annotations = batch
+		_, _, err = p.client.Client().Checks.UpdateCheckRun(p.ctx, repo.owner, repo.name, checkRun.GetID(), github.UpdateCheckRunOptions{
+			Name:   checkRunName,
+			Output: output,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func annotationLevel(s severity.Severity) string {
+	switch s {
+	case severity.Critical, severity.High:
+		return "failure"
+	case severity.Medium:
+		return "warning"
+	default:
+		return "notice"
+	}
+}
+
+func buildAnnotations(failed []repositoryFinding) []*github.CheckRunAnnotation {
+	var annotations []*github.CheckRunAnnotation
+
+	for _, finding := range failed {
+		info := finding.policyInfo
+		var sb strings.Builder
+		sb.WriteString(info.Description)
+		sb.WriteString("\n\nRemediation:\n")
+		for i, step := range info.RemediationSteps {
+			sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, step))
+		}
+
+		annotations = append(annotations, &github.CheckRunAnnotation{
+			Path:            github.String(annotationPath),
+			StartLine:       github.Int(1),
+			EndLine:         github.Int(1),
+			AnnotationLevel: github.String(annotationLevel(info.Severity)),
+			Title:           github.String(fmt.Sprintf("[%s] %s", severity.Label(info.Severity), info.Title)),
+			Message:         github.String(sb.String()),
+			RawDetails:      github.String(info.FullyQualifiedPolicyName),
+		})
+	}
+
+	return annotations
+}
+
+func splitAnnotations(annotations []*github.CheckRunAnnotation) (batch, remaining []*github.CheckRunAnnotation) {
+	if len(annotations) <= maxAnnotationsPerRequest {
+		return annotations, nil
+	}
+
+	return annotations[:maxAnnotationsPerRequest], annotations[maxAnnotationsPerRequest:]
+}
+
+func buildOutput(repo *repositoryFindings, failed []repositoryFinding, annotations []*github.CheckRunAnnotation) *github.CheckRunOutput {
+	var passed, skipped int
+	for _, f := range repo.findings {
+		switch f.status {
+		case analyzers.PolicyPassed:
+			passed++
+		case analyzers.PolicySkipped:
+			skipped++
+		}
+	}
+
+	title := "No policy violations found"
+	if len(failed) > 0 {
+		title = fmt.Sprintf("%d policy violations found", len(failed))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Passed: %d, Failed: %d, Skipped: %d\n\n", passed, len(failed), skipped))
+	if len(failed) > 0 {
+		sb.WriteString("| Severity | Policy |\n|---|---|\n")
+		for _, f := range failed {
+			sb.WriteString(fmt.Sprintf("| %s | %s |\n", severity.Label(f.policyInfo.Severity), f.policyInfo.Title))
+		}
+	}
+
+	if repo.contact != "" {
+		sb.WriteString(fmt.Sprintf("\n**Security contact:** %s\n", repo.contact))
+	}
+
+	if scanID := repo.scanID(); scanID != "" {
+		sb.WriteString(fmt.Sprintf("\n_Scan ID: %s_\n", scanID))
+	}
+
+	return &github.CheckRunOutput{
+		Title:       github.String(title),
+		Summary:     github.String(sb.String()),
+		Annotations: annotations,
+	}
+}
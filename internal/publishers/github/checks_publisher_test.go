@@ -0,0 +1,56 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/Legit-Labs/legitify/internal/analyzers"
+	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/Legit-Labs/legitify/internal/common/severity"
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRepositoryLink(t *testing.T) {
+	owner, name, ok := parseRepositoryLink("https://github.com/Legit-Labs/legitify")
+	require.True(t, ok)
+	require.Equal(t, "Legit-Labs", owner)
+	require.Equal(t, "legitify", name)
+
+	owner, name, ok = parseRepositoryLink("https://github.example.com/org/repo/")
+	require.True(t, ok)
+	require.Equal(t, "org", owner)
+	require.Equal(t, "repo", name)
+
+	_, _, ok = parseRepositoryLink("https://github.com/orgs")
+	require.False(t, ok)
+}
+
+func TestGroupByRepository(t *testing.T) {
+	results := scheme.NewFlattenedScheme()
+	repoPolicy := scheme.NewOutputData(scheme.PolicyInfo{
+		Title:     "repo policy",
+		Severity:  severity.High,
+		Namespace: namespace.Repository,
+	})
+	repoPolicy = scheme.AppendViolations(repoPolicy,
+		scheme.Violation{CanonicalLink: "https://github.com/org/b", Status: analyzers.PolicyFailed},
+		scheme.Violation{CanonicalLink: "https://github.com/org/a", Status: analyzers.PolicyPassed},
+	)
+	orgPolicy := scheme.NewOutputData(scheme.PolicyInfo{
+		Title:     "org policy",
+		Severity:  severity.High,
+		Namespace: namespace.Organization,
+	})
+	orgPolicy = scheme.AppendViolations(orgPolicy,
+		scheme.Violation{CanonicalLink: "https://github.com/org", Status: analyzers.PolicyFailed},
+	)
+	results.Set("data.repository.policy", repoPolicy)
+	results.Set("data.organization.policy", orgPolicy)
+
+	repositories := groupByRepository(results)
+	require.Len(t, repositories, 2)
+	require.Equal(t, "a", repositories[0].name)
+	require.Empty(t, repositories[0].failed())
+	require.Equal(t, "b", repositories[1].name)
+	require.Len(t, repositories[1].failed(), 1)
+}
@@ -0,0 +1,176 @@
+package github
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	ghclient "github.com/Legit-Labs/legitify/internal/clients/github"
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme"
+	"github.com/Legit-Labs/legitify/internal/publishers"
+	"github.com/Legit-Labs/legitify/internal/sarif"
+	"github.com/google/go-github/v44/github"
+)
+
+const (
+	// the code scanning category of the per-repository uploads
+	repositoryCategory = "legitify/repository"
+	// the code scanning category of the uploads to a central repository
+	centralCategory = "legitify/central"
+)
+
+type codeScanningPublisher struct {
+	ctx    context.Context
+	client *ghclient.Client
+	// centralRepository (owner/name) receives the results of all scanned entities, instead of each repository receiving its own
+	centralRepository string
+}
+
+// NewCodeScanningPublisher uploads the results as SARIF to the code scanning alerts
+// of each scanned repository, or of a single central repository when one is given.
+func NewCodeScanningPublisher(ctx context.Context, client *ghclient.Client, centralRepository string) publishers.Publisher {
+	return &codeScanningPublisher{
+		ctx:               ctx,
+		client:            client,
+		centralRepository: centralRepository,
+	}
+}
+
+func (p *codeScanningPublisher) Name() string {
+	if p.centralRepository != "" {
+		return fmt.Sprintf("GitHub Code Scanning (%s)", p.centralRepository)
+	}
+	return "GitHub Code Scanning"
+}
+
+// PublishesPerOrganization is true unless a central repository receives the results of all the organizations
+// (each upload replaces the previous analysis of the category)
+func (p *codeScanningPublisher) PublishesPerOrganization() bool {
+	return p.centralRepository == ""
+}
+
+func (p *codeScanningPublisher) Publish(results scheme.FlattenedScheme) error {
+	if p.centralRepository != "" {
+		owner, name, ok := strings.Cut(p.centralRepository, "/")
+		if !ok {
+			return fmt.Errorf("invalid code scanning repository (expecting owner/name): %s", p.centralRepository)
+		}
+		return p.upload(owner, name, sarif.FromFlattenedScheme(results, sarif.EntityLocation, centralCategory))
+	}
+
+	var failures []string
+	for _, repo := range groupByRepository(results) {
+		link := repo.link
+		repoResults := scheme.FilterPoliciesByViolations(results, func(violation scheme.Violation) bool {
+			return violation.CanonicalLink == link
+		})
+
+		sarifLog := sarif.FromFlattenedScheme(repoResults, sarif.FixedLocation(annotationPath), repositoryCategory)
+		if err := p.upload(repo.owner, repo.name, sarifLog); err != nil {
+			failures = append(failures, fmt.Sprintf("%s/%s (%v)", repo.owner, repo.name, err))
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("failed to upload code scanning results for: %s", strings.Join(failures, ", "))
+	}
+
+	return nil
+}
+
+func (p *codeScanningPublisher) upload(owner, name string, sarifLog *sarif.Log) error {
+	// the results dismissed in the GitHub UI are uploaded as suppressed, so they don't resurrect as new alerts
+	dismissals, err := p.dismissedAlerts(owner, name)
+	if err != nil {
+		log.Printf("failed to list the dismissed code scanning alerts of %s/%s (uploading without suppressions): %v", owner, name, err)
+	} else if suppressed := sarifLog.Suppress(dismissals); suppressed > 0 {
+		log.Printf("suppressed %d previously dismissed results on %s/%s", suppressed, owner, name)
+	}
+
+	encoded, err := encodeSarif(sarifLog)
+	if err != nil {
+		return err
+	}
+
+	ref, sha, err := repositoryHead(p.ctx, p.client, owner, name)
+	if err != nil {
+		return err
+	}
+
+	id, _, err := p.client.Client().CodeScanning.UploadSarif(p.ctx, owner, name, &github.SarifAnalysis{
+		CommitSHA: github.String(sha),
+		Ref:       github.String(ref),
+		Sarif:     github.String(encoded),
+		ToolName:  github.String(sarif.ToolName),
+	})
+	// the upload is processed asynchronously, so the API responds with 202 (which go-github reports as an AcceptedError)
+	if _, accepted := err.(*github.AcceptedError); err != nil && !accepted {
+		return err
+	}
+
+	log.Printf("uploaded code scanning results to %s/%s (%s)", owner, name, id.GetID())
+	return nil
+}
+
+// dismissedAlerts lists the legitify alerts that were dismissed on the repository
+func (p *codeScanningPublisher) dismissedAlerts(owner, name string) ([]sarif.Dismissal, error) {
+	var dismissals []sarif.Dismissal
+
+	err := ghclient.PaginateResults(func(opts *github.ListOptions) (*github.Response, error) {
+		alerts, resp, err := p.client.Client().CodeScanning.ListAlertsForRepo(p.ctx, owner, name, &github.AlertListOptions{
+			State:       "dismissed",
+			ListOptions: *opts,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, alert := range alerts {
+			if alert.GetTool().GetName() != sarif.ToolName {
+				continue
+			}
+			dismissals = append(dismissals, alertDismissal(alert))
+		}
+
+		return resp, nil
+	})
+
+	return dismissals, err
+}
+
+func alertDismissal(alert *github.Alert) sarif.Dismissal {
+	justification := alert.GetDismissedReason()
+	if login := alert.GetDismissedBy().GetLogin(); login != "" {
+		justification = fmt.Sprintf("%s (dismissed by %s)", justification, login)
+	}
+
+	return sarif.Dismissal{
+		RuleID:        alert.GetRule().GetID(),
+		Location:      alert.GetMostRecentInstance().GetLocation().GetPath(),
+		Justification: justification,
+	}
+}
+
+// encodeSarif compresses & encodes the SARIF log as expected by the code scanning API
+func encodeSarif(sarifLog *sarif.Log) (string, error) {
+	raw, err := json.Marshal(sarifLog)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err = writer.Write(raw); err != nil {
+		return "", err
+	}
+	if err = writer.Close(); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
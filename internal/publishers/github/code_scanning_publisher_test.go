@@ -0,0 +1,50 @@
+package github
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme"
+	"github.com/Legit-Labs/legitify/internal/sarif"
+	"github.com/google/go-github/v44/github"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeSarif(t *testing.T) {
+	sarifLog := sarif.FromFlattenedScheme(scheme.NewFlattenedScheme(), sarif.EntityLocation, repositoryCategory)
+
+	encoded, err := encodeSarif(sarifLog)
+	require.NoError(t, err)
+
+	compressed, err := base64.StdEncoding.DecodeString(encoded)
+	require.NoError(t, err)
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	require.NoError(t, err)
+	raw, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	var decoded sarif.Log
+	require.NoError(t, json.Unmarshal(raw, &decoded))
+	require.Equal(t, *sarifLog, decoded)
+}
+
+func TestAlertDismissal(t *testing.T) {
+	alert := &github.Alert{
+		Rule:            &github.Rule{ID: github.String("data.repository.policy")},
+		DismissedReason: github.String("won't fix"),
+		DismissedBy:     &github.User{Login: github.String("octocat")},
+		MostRecentInstance: &github.MostRecentInstance{
+			Location: &github.Location{Path: github.String(annotationPath)},
+		},
+	}
+
+	require.Equal(t, sarif.Dismissal{
+		RuleID:        "data.repository.policy",
+		Location:      annotationPath,
+		Justification: "won't fix (dismissed by octocat)",
+	}, alertDismissal(alert))
+}
@@ -0,0 +1,156 @@
+package github
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/Legit-Labs/legitify/internal/analyzers"
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme"
+	"github.com/Legit-Labs/legitify/internal/publishers"
+)
+
+type ghasFeatureStatus string
+
+const (
+	ghasEnabled  ghasFeatureStatus = "enabled"
+	ghasDisabled ghasFeatureStatus = "disabled"
+	ghasUnknown  ghasFeatureStatus = "unknown"
+)
+
+type ghasFeature struct {
+	title string
+	// the repository policy that fails when the feature is disabled
+	policyName string
+}
+
+var ghasFeatures = []ghasFeature{
+	{title: "Secret Scanning", policyName: "secret_scanning_not_enabled"},
+	{title: "Push Protection", policyName: "secret_scanning_push_protection_not_enabled"},
+	{title: "Code Scanning", policyName: "code_scanning_not_enabled"},
+	{title: "Dependabot Alerts", policyName: "vulnerability_alerts_not_enabled"},
+	{title: "Dependency Review", policyName: "ghas_dependency_review_not_enabled"},
+}
+
+type ghasMatrixRow struct {
+	owner    string
+	name     string
+	features []ghasFeatureStatus
+}
+
+type ghasMatrixPublisher struct {
+	outputFile string
+}
+
+// NewGhasMatrixPublisher writes a per-repository GitHub Advanced Security coverage matrix
+// as a csv file and logs a summary of the enabled features.
+func NewGhasMatrixPublisher(outputFile string) publishers.Publisher {
+	return &ghasMatrixPublisher{
+		outputFile: outputFile,
+	}
+}
+
+func (p *ghasMatrixPublisher) Name() string {
+	return fmt.Sprintf("GHAS coverage matrix (%s)", p.outputFile)
+}
+
+func (p *ghasMatrixPublisher) Publish(results scheme.FlattenedScheme) error {
+	rows := buildGhasMatrix(results)
+
+	file, err := os.Create(p.outputFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err = writeGhasMatrixCsv(file, rows); err != nil {
+		return err
+	}
+
+	log.Print(ghasMatrixSummary(rows))
+	return nil
+}
+
+func featureStatus(status analyzers.PolicyStatus) ghasFeatureStatus {
+	switch status {
+	case analyzers.PolicyPassed:
+		return ghasEnabled
+	case analyzers.PolicyFailed:
+		return ghasDisabled
+	default:
+		return ghasUnknown
+	}
+}
+
+func buildGhasMatrix(results scheme.FlattenedScheme) []ghasMatrixRow {
+	var rows []ghasMatrixRow
+
+	for _, repo := range groupByRepository(results) {
+		row := ghasMatrixRow{
+			owner:    repo.owner,
+			name:     repo.name,
+			features: make([]ghasFeatureStatus, len(ghasFeatures)),
+		}
+		for i := range row.features {
+			row.features[i] = ghasUnknown
+		}
+
+		for _, finding := range repo.findings {
+			for i, feature := range ghasFeatures {
+				if finding.policyInfo.PolicyName == feature.policyName {
+					row.features[i] = featureStatus(finding.status)
+				}
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+func writeGhasMatrixCsv(w io.Writer, rows []ghasMatrixRow) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{"Repository"}
+	for _, feature := range ghasFeatures {
+		header = append(header, feature.title)
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		record := []string{row.owner + "/" + row.name}
+		for _, status := range row.features {
+			record = append(record, string(status))
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func ghasMatrixSummary(rows []ghasMatrixRow) string {
+	summary := fmt.Sprintf("GitHub Advanced Security coverage (%d repositories):\n", len(rows))
+	for i, feature := range ghasFeatures {
+		enabled := 0
+		for _, row := range rows {
+			if row.features[i] == ghasEnabled {
+				enabled++
+			}
+		}
+		percentage := 0
+		if len(rows) > 0 {
+			percentage = enabled * 100 / len(rows)
+		}
+		summary += fmt.Sprintf("  %-18s %d/%d (%d%%)\n", feature.title+":", enabled, len(rows), percentage)
+	}
+
+	return summary
+}
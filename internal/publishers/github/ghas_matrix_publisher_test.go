@@ -0,0 +1,45 @@
+package github
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Legit-Labs/legitify/internal/analyzers"
+	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGhasMatrix(t *testing.T) {
+	results := scheme.NewFlattenedScheme()
+	secretScanning := scheme.NewOutputData(scheme.PolicyInfo{
+		PolicyName: "secret_scanning_not_enabled",
+		Namespace:  namespace.Repository,
+	})
+	secretScanning = scheme.AppendViolations(secretScanning,
+		scheme.Violation{CanonicalLink: "https://github.com/org/a", Status: analyzers.PolicyPassed},
+		scheme.Violation{CanonicalLink: "https://github.com/org/b", Status: analyzers.PolicyFailed},
+	)
+	codeScanning := scheme.NewOutputData(scheme.PolicyInfo{
+		PolicyName: "code_scanning_not_enabled",
+		Namespace:  namespace.Repository,
+	})
+	codeScanning = scheme.AppendViolations(codeScanning,
+		scheme.Violation{CanonicalLink: "https://github.com/org/a", Status: analyzers.PolicyFailed},
+		scheme.Violation{CanonicalLink: "https://github.com/org/b", Status: analyzers.PolicySkipped},
+	)
+	results.Set("data.repository.secret_scanning_not_enabled", secretScanning)
+	results.Set("data.repository.code_scanning_not_enabled", codeScanning)
+
+	rows := buildGhasMatrix(results)
+	require.Len(t, rows, 2)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeGhasMatrixCsv(&buf, rows))
+	expected := "Repository,Secret Scanning,Push Protection,Code Scanning,Dependabot Alerts,Dependency Review\n" +
+		"org/a,enabled,unknown,disabled,unknown,unknown\n" +
+		"org/b,disabled,unknown,unknown,unknown,unknown\n"
+	require.Equal(t, expected, buf.String())
+
+	require.Contains(t, ghasMatrixSummary(rows), "Secret Scanning:   1/2 (50%)")
+}
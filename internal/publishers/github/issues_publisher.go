@@ -0,0 +1,254 @@
+package github
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/Legit-Labs/legitify/internal/analyzers"
+	ghclient "github.com/Legit-Labs/legitify/internal/clients/github"
+	"github.com/Legit-Labs/legitify/internal/common/severity"
+	"github.com/Legit-Labs/legitify/internal/enricher/enrichers"
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme"
+	"github.com/Legit-Labs/legitify/internal/publishers"
+	"github.com/google/go-github/v44/github"
+)
+
+const (
+	issueLabel         = "legitify"
+	issueSeverityLabel = "severity: %s"
+)
+
+// the fingerprint is kept as a hidden comment in the issue body, to find the issue again on the next runs
+var fingerprintPattern = regexp.MustCompile(`<!-- legitify-fingerprint: ([0-9a-f]+) -->`)
+
+// trackedIssue is the desired state of the issue of a single policy
+type trackedIssue struct {
+	fingerprint string
+	title       string
+	body        string
+	labels      []string
+	// violated issues are opened (or updated), resolved ones are closed
+	violated bool
+}
+
+type issuesPublisher struct {
+	ctx    context.Context
+	client *ghclient.Client
+	// centralRepository (owner/name) tracks the violations of all scanned entities, instead of each repository tracking its own
+	centralRepository string
+}
+
+// NewIssuesPublisher opens a tracking issue per violated policy in each affected repository
+// (or in a single central repository when one is given), and closes the issues of the resolved violations.
+func NewIssuesPublisher(ctx context.Context, client *ghclient.Client, centralRepository string) publishers.Publisher {
+	return &issuesPublisher{
+		ctx:               ctx,
+		client:            client,
+		centralRepository: centralRepository,
+	}
+}
+
+func (p *issuesPublisher) Name() string {
+	if p.centralRepository != "" {
+		return fmt.Sprintf("GitHub Issues (%s)", p.centralRepository)
+	}
+	return "GitHub Issues"
+}
+
+// PublishesPerOrganization is true unless a central repository tracks the violations of all the organizations
+// (which would close the issues of the organizations missing from each partial result)
+func (p *issuesPublisher) PublishesPerOrganization() bool {
+	return p.centralRepository == ""
+}
+
+func (p *issuesPublisher) Publish(results scheme.FlattenedScheme) error {
+	if p.centralRepository != "" {
+		owner, name, ok := strings.Cut(p.centralRepository, "/")
+		if !ok {
+			return fmt.Errorf("invalid issues repository (expecting owner/name): %s", p.centralRepository)
+		}
+		return p.sync(owner, name, centralIssues(results))
+	}
+
+	var failures []string
+	for _, repo := range groupByRepository(results) {
+		if err := p.sync(repo.owner, repo.name, repositoryIssues(repo)); err != nil {
+			failures = append(failures, fmt.Sprintf("%s/%s (%v)", repo.owner, repo.name, err))
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("failed to sync issues for: %s", strings.Join(failures, ", "))
+	}
+
+	return nil
+}
+
+func fingerprint(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(sum[:8])
+}
+
+func issueLabels(info scheme.PolicyInfo) []string {
+	return []string{issueLabel, fmt.Sprintf(issueSeverityLabel, severity.Label(info.Severity))}
+}
+
+func issueBody(info scheme.PolicyInfo, fp string, entities []string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("**Severity:** %s\n\n", severity.Label(info.Severity)))
+	sb.WriteString(info.Description)
+	sb.WriteString("\n\n### Affected entities\n")
+	for _, entity := range entities {
+		sb.WriteString(fmt.Sprintf("- %s\n", entity))
+	}
+	sb.WriteString("\n### Remediation\n")
+	for i, step := range info.RemediationSteps {
+		sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, step))
+	}
+	sb.WriteString(fmt.Sprintf("\n_This issue is managed by legitify (policy `%s`) and is closed automatically once resolved._\n", info.PolicyName))
+	if info.ScanID != "" {
+		sb.WriteString(fmt.Sprintf("_Last updated by scan %s._\n", info.ScanID))
+	}
+	sb.WriteString(fmt.Sprintf("<!-- legitify-fingerprint: %s -->\n", fp))
+	return sb.String()
+}
+
+func withContact(entity string, contact string) string {
+	if contact == "" {
+		return entity
+	}
+	return fmt.Sprintf("%s (security contact: %s)", entity, contact)
+}
+
+func issueTitle(info scheme.PolicyInfo) string {
+	return fmt.Sprintf("[legitify] %s", info.Title)
+}
+
+// repositoryIssues tracks each of the repository policies on the repository itself
+func repositoryIssues(repo *repositoryFindings) []trackedIssue {
+	var issues []trackedIssue
+	for _, finding := range repo.findings {
+		if finding.status != analyzers.PolicyFailed && finding.status != analyzers.PolicyPassed {
+			continue // skipped policies are neither violated nor resolved
+		}
+
+		fp := fingerprint(finding.policyInfo.FullyQualifiedPolicyName, repo.link)
+		issues = append(issues, trackedIssue{
+			fingerprint: fp,
+			title:       issueTitle(finding.policyInfo),
+			body:        issueBody(finding.policyInfo, fp, []string{withContact(repo.link, repo.contact)}),
+			labels:      issueLabels(finding.policyInfo),
+			violated:    finding.status == analyzers.PolicyFailed,
+		})
+	}
+	return issues
+}
+
+// centralIssues tracks each of the policies, of all namespaces, by a single issue listing all of its violating entities
+func centralIssues(results scheme.FlattenedScheme) []trackedIssue {
+	var issues []trackedIssue
+	for _, policyName := range results.Keys() {
+		data := results.GetPolicyData(policyName)
+
+		var failed []string
+		passed := false
+		for _, violation := range data.Violations {
+			switch violation.Status {
+			case analyzers.PolicyFailed:
+				entity := violation.CanonicalLink
+				if contact, ok := violation.Aux[enrichers.SecurityContact].(*enrichers.SecurityContactEnrichment); ok {
+					entity = withContact(entity, contact.Contact().String())
+				}
+				failed = append(failed, entity)
+			case analyzers.PolicyPassed:
+				passed = true
+			}
+		}
+		if len(failed) == 0 && !passed {
+			continue
+		}
+
+		fp := fingerprint(data.PolicyInfo.FullyQualifiedPolicyName)
+		issues = append(issues, trackedIssue{
+			fingerprint: fp,
+			title:       issueTitle(data.PolicyInfo),
+			body:        issueBody(data.PolicyInfo, fp, failed),
+			labels:      issueLabels(data.PolicyInfo),
+			violated:    len(failed) > 0,
+		})
+	}
+	return issues
+}
+
+// existingIssues maps the fingerprints of the issues legitify previously opened on the repository
+func (p *issuesPublisher) existingIssues(owner, name string) (map[string]*github.Issue, error) {
+	existing := make(map[string]*github.Issue)
+
+	err := ghclient.PaginateResults(func(opts *github.ListOptions) (*github.Response, error) {
+		issues, resp, err := p.client.Client().Issues.ListByRepo(p.ctx, owner, name, &github.IssueListByRepoOptions{
+			State:       "all",
+			Labels:      []string{issueLabel},
+			ListOptions: *opts,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, issue := range issues {
+			if match := fingerprintPattern.FindStringSubmatch(issue.GetBody()); match != nil {
+				existing[match[1]] = issue
+			}
+		}
+
+		return resp, nil
+	})
+
+	return existing, err
+}
+
+func (p *issuesPublisher) sync(owner, name string, issues []trackedIssue) error {
+	existing, err := p.existingIssues(owner, name)
+	if err != nil {
+		return err
+	}
+
+	var opened, updated, closed int
+	for _, issue := range issues {
+		current, exists := existing[issue.fingerprint]
+
+		switch {
+		case issue.violated && !exists:
+			_, _, err = p.client.Client().Issues.Create(p.ctx, owner, name, &github.IssueRequest{
+				Title:  github.String(issue.title),
+				Body:   github.String(issue.body),
+				Labels: &issue.labels,
+			})
+			opened++
+		case issue.violated:
+			_, _, err = p.client.Client().Issues.Edit(p.ctx, owner, name, current.GetNumber(), &github.IssueRequest{
+				Title:  github.String(issue.title),
+				Body:   github.String(issue.body),
+				Labels: &issue.labels,
+				State:  github.String("open"),
+			})
+			updated++
+		case exists && current.GetState() == "open":
+			_, _, err = p.client.Client().Issues.Edit(p.ctx, owner, name, current.GetNumber(), &github.IssueRequest{
+				State: github.String("closed"),
+			})
+			closed++
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Printf("synced issues on %s/%s: %d opened, %d updated, %d closed", owner, name, opened, updated, closed)
+	return nil
+}
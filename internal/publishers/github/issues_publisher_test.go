@@ -0,0 +1,84 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/Legit-Labs/legitify/internal/analyzers"
+	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/Legit-Labs/legitify/internal/common/severity"
+	"github.com/Legit-Labs/legitify/internal/contacts"
+	"github.com/Legit-Labs/legitify/internal/enricher/enrichers"
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme"
+	"github.com/stretchr/testify/require"
+)
+
+func issuesSample() scheme.FlattenedScheme {
+	results := scheme.NewFlattenedScheme()
+	violated := scheme.NewOutputData(scheme.PolicyInfo{
+		Title:                    "violated policy",
+		FullyQualifiedPolicyName: "data.repository.violated",
+		Severity:                 severity.High,
+		Namespace:                namespace.Repository,
+	})
+	violated = scheme.AppendViolations(violated,
+		scheme.Violation{CanonicalLink: "https://github.com/org/a", Status: analyzers.PolicyFailed},
+		scheme.Violation{CanonicalLink: "https://github.com/org/b", Status: analyzers.PolicyPassed},
+	)
+	skipped := scheme.NewOutputData(scheme.PolicyInfo{
+		Title:                    "skipped policy",
+		FullyQualifiedPolicyName: "data.repository.skipped",
+		Severity:                 severity.Low,
+		Namespace:                namespace.Repository,
+	})
+	skipped = scheme.AppendViolations(skipped,
+		scheme.Violation{CanonicalLink: "https://github.com/org/a", Status: analyzers.PolicySkipped},
+	)
+	results.Set("data.repository.violated", violated)
+	results.Set("data.repository.skipped", skipped)
+	return results
+}
+
+func TestRepositoryIssues(t *testing.T) {
+	repositories := groupByRepository(issuesSample())
+	require.Len(t, repositories, 2)
+
+	issues := repositoryIssues(repositories[0])
+	require.Len(t, issues, 1)
+	require.True(t, issues[0].violated)
+	require.Equal(t, "[legitify] violated policy", issues[0].title)
+	require.ElementsMatch(t, []string{"legitify", "severity: HIGH"}, issues[0].labels)
+
+	match := fingerprintPattern.FindStringSubmatch(issues[0].body)
+	require.NotNil(t, match)
+	require.Equal(t, issues[0].fingerprint, match[1])
+
+	resolved := repositoryIssues(repositories[1])
+	require.Len(t, resolved, 1)
+	require.False(t, resolved[0].violated)
+	require.NotEqual(t, issues[0].fingerprint, resolved[0].fingerprint)
+}
+
+func TestCentralIssues(t *testing.T) {
+	issues := centralIssues(issuesSample())
+	require.Len(t, issues, 1)
+	require.True(t, issues[0].violated)
+	require.Contains(t, issues[0].body, "- https://github.com/org/a\n")
+	require.NotContains(t, issues[0].body, "https://github.com/org/b")
+	require.Equal(t, fingerprint("data.repository.violated"), issues[0].fingerprint)
+}
+
+func TestIssuesSecurityContact(t *testing.T) {
+	results := issuesSample()
+	data := results.GetPolicyData("data.repository.violated")
+	data.Violations[0].Aux = map[string]enrichers.Enrichment{
+		enrichers.SecurityContact: enrichers.NewSecurityContactEnrichment(contacts.Contact{Name: "AppSec", Escalation: "#appsec-oncall"}),
+	}
+	results.Set("data.repository.violated", data)
+
+	repositories := groupByRepository(results)
+	require.Equal(t, "AppSec, escalation: #appsec-oncall", repositories[0].contact)
+	require.Contains(t, repositoryIssues(repositories[0])[0].body, "https://github.com/org/a (security contact: AppSec, escalation: #appsec-oncall)")
+
+	issues := centralIssues(results)
+	require.Contains(t, issues[0].body, "https://github.com/org/a (security contact: AppSec, escalation: #appsec-oncall)")
+}
@@ -0,0 +1,31 @@
+package github
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	ghclient "github.com/Legit-Labs/legitify/internal/clients/github"
+)
+
+// repositoryHead resolves the ref & commit the results of a repository are attached to:
+// the workflow commit when scanning the repository the workflow runs on, otherwise the head of the default branch.
+func repositoryHead(ctx context.Context, client *ghclient.Client, owner, name string) (ref, sha string, err error) {
+	if sha := os.Getenv("GITHUB_SHA"); sha != "" && strings.EqualFold(os.Getenv("GITHUB_REPOSITORY"), owner+"/"+name) {
+		if ref := os.Getenv("GITHUB_REF"); ref != "" {
+			return ref, sha, nil
+		}
+	}
+
+	repository, _, err := client.Client().Repositories.Get(ctx, owner, name)
+	if err != nil {
+		return "", "", err
+	}
+
+	branch, _, err := client.Client().Repositories.GetBranch(ctx, owner, name, repository.GetDefaultBranch(), true)
+	if err != nil {
+		return "", "", err
+	}
+
+	return "refs/heads/" + repository.GetDefaultBranch(), branch.GetCommit().GetSHA(), nil
+}
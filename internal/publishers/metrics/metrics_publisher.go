@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"os"
+	"time"
+
+	"github.com/Legit-Labs/legitify/internal/analyzers"
+	"github.com/Legit-Labs/legitify/internal/metrics"
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme"
+	"github.com/Legit-Labs/legitify/internal/publishers"
+)
+
+type metricsPublisher struct {
+	registry *metrics.Registry
+	// outputFile is optional; e.g. for the node_exporter textfile collector
+	outputFile string
+}
+
+// NewMetricsPublisher records the findings as Prometheus metrics, and writes all the metrics to the output file (if given).
+func NewMetricsPublisher(outputFile string) publishers.Publisher {
+	return &metricsPublisher{
+		registry:   metrics.Default(),
+		outputFile: outputFile,
+	}
+}
+
+func (p *metricsPublisher) Name() string {
+	return "Prometheus metrics"
+}
+
+func (p *metricsPublisher) Publish(results scheme.FlattenedScheme) error {
+	recordFindings(p.registry, results)
+
+	if p.outputFile == "" {
+		return nil
+	}
+
+	file, err := os.Create(p.outputFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return p.registry.Write(file)
+}
+
+// recordFindings sets the number of violating entities of each policy (replacing the findings of previous runs)
+func recordFindings(registry *metrics.Registry, results scheme.FlattenedScheme) {
+	registry.ResetGauge(metrics.Findings)
+
+	for _, policyName := range results.Keys() {
+		data := results.GetPolicyData(policyName)
+
+		failed := 0
+		for _, violation := range data.Violations {
+			if violation.Status == analyzers.PolicyFailed {
+				failed++
+			}
+		}
+
+		labels := metrics.Labels{
+			"severity":  data.PolicyInfo.Severity,
+			"namespace": data.PolicyInfo.Namespace,
+			"policy":    data.PolicyInfo.PolicyName,
+		}
+		registry.SetGauge(metrics.Findings, "Number of entities violating the policy", labels, float64(failed))
+	}
+
+	registry.SetGauge(metrics.LastRun, "Time the last analysis finished", nil, float64(time.Now().Unix()))
+
+	registry.ResetGauge(metrics.ScanInfo)
+	if scanID := results.ScanID(); scanID != "" {
+		registry.SetGauge(metrics.ScanInfo, "ID of the last analysis (to correlate with its other artifacts)", metrics.Labels{"scan_id": scanID}, 1)
+	}
+}
@@ -0,0 +1,26 @@
+package publishers
+
+import (
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme"
+)
+
+// Publisher reports the analysis results to an external destination (e.g. the scanned SCM itself),
+// after the main output was written.
+type Publisher interface {
+	Publish(results scheme.FlattenedScheme) error
+	Name() string
+}
+
+// OrganizationPublisher is a publisher that may publish the results of each organization separately,
+// as soon as the organization is analyzed (see --publish-per-org).
+// Publishers that aggregate all the results (e.g. into a single file or repository) publish once, after the whole analysis.
+type OrganizationPublisher interface {
+	Publisher
+	PublishesPerOrganization() bool
+}
+
+// PublishesPerOrganization returns whether the publisher may publish the results of each organization separately
+func PublishesPerOrganization(publisher Publisher) bool {
+	p, ok := publisher.(OrganizationPublisher)
+	return ok && p.PublishesPerOrganization()
+}
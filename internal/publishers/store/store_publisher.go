@@ -0,0 +1,26 @@
+package store
+
+import (
+	"time"
+
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme"
+	"github.com/Legit-Labs/legitify/internal/publishers"
+	resultstore "github.com/Legit-Labs/legitify/internal/store"
+)
+
+type storePublisher struct {
+	store resultstore.Store
+}
+
+// NewStorePublisher saves the results of each analysis in the store (see --store), for later commands such as history
+func NewStorePublisher(store resultstore.Store) publishers.Publisher {
+	return &storePublisher{store: store}
+}
+
+func (p *storePublisher) Name() string {
+	return p.store.Name()
+}
+
+func (p *storePublisher) Publish(results scheme.FlattenedScheme) error {
+	return p.store.Save(results, time.Now())
+}
@@ -0,0 +1,135 @@
+package telemetry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/Legit-Labs/legitify/internal/logger"
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme"
+	"github.com/Legit-Labs/legitify/internal/publishers"
+	"github.com/Legit-Labs/legitify/internal/version"
+)
+
+// Report is the anonymous usage report of a single run.
+// It doesn't include any identifying data: no names, links, tokens, findings or option values.
+type Report struct {
+	Version         string `json:"version"`
+	OS              string `json:"os"`
+	Arch            string `json:"arch"`
+	ScmType         string `json:"scm_type"`
+	DurationSeconds int64  `json:"duration_seconds"`
+	// Entities is the number of analyzed entities per namespace
+	Entities map[string]int `json:"entities"`
+	Policies int            `json:"policies"`
+	// Flags are the names of the options set for the run
+	Flags []string `json:"flags"`
+}
+
+type telemetryPublisher struct {
+	endpoint string
+	// logFile is an optional local copy of exactly what is sent (appended as a json line per run)
+	logFile string
+	scmType string
+	flags   []string
+	start   time.Time
+	client  *http.Client
+}
+
+// NewTelemetryPublisher sends the anonymous usage report of the run to the endpoint (if given), and logs it to the log file (if given)
+func NewTelemetryPublisher(endpoint string, logFile string, scmType string, flags []string) publishers.Publisher {
+	return &telemetryPublisher{
+		endpoint: endpoint,
+		logFile:  logFile,
+		scmType:  scmType,
+		flags:    flags,
+		start:    time.Now(),
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *telemetryPublisher) Name() string {
+	return "usage telemetry"
+}
+
+func (p *telemetryPublisher) Publish(results scheme.FlattenedScheme) error {
+	payload, err := json.Marshal(p.report(results))
+	if err != nil {
+		return err
+	}
+
+	if p.logFile != "" {
+		if err = appendLine(p.logFile, payload); err != nil {
+			return fmt.Errorf("failed to write the telemetry log: %v", err)
+		}
+	}
+
+	if p.endpoint == "" {
+		return nil
+	}
+	// telemetry is best effort: it shouldn't fail the analysis
+	if err = p.send(payload); err != nil {
+		logger.WithError(err).Warnf("failed to send usage telemetry")
+	}
+	return nil
+}
+
+func (p *telemetryPublisher) report(results scheme.FlattenedScheme) Report {
+	entities := make(map[string]map[string]bool)
+	for _, policyName := range results.Keys() {
+		for _, violation := range results.GetPolicyData(policyName).Violations {
+			if entities[violation.ViolationEntityType] == nil {
+				entities[violation.ViolationEntityType] = make(map[string]bool)
+			}
+			entities[violation.ViolationEntityType][violation.CanonicalLink] = true
+		}
+	}
+
+	counts := make(map[string]int)
+	for entityType, links := range entities {
+		counts[entityType] = len(links)
+	}
+
+	flags := append([]string{}, p.flags...)
+	sort.Strings(flags)
+
+	return Report{
+		Version:         version.Version,
+		OS:              runtime.GOOS,
+		Arch:            runtime.GOARCH,
+		ScmType:         p.scmType,
+		DurationSeconds: int64(time.Since(p.start).Seconds()),
+		Entities:        counts,
+		Policies:        len(results.Keys()),
+		Flags:           flags,
+	}
+}
+
+func (p *telemetryPublisher) send(payload []byte) error {
+	resp, err := p.client.Post(p.endpoint, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+func appendLine(path string, line []byte) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
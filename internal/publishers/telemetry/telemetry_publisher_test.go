@@ -0,0 +1,73 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Legit-Labs/legitify/internal/analyzers"
+	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme"
+	"github.com/stretchr/testify/require"
+)
+
+func testResults() scheme.FlattenedScheme {
+	results := scheme.NewFlattenedScheme()
+	data := scheme.NewOutputData(scheme.PolicyInfo{PolicyName: "missing_license", Namespace: namespace.Repository})
+	data = scheme.AppendViolations(data,
+		scheme.Violation{ViolationEntityType: namespace.Repository, CanonicalLink: "https://github.com/org/a", Status: analyzers.PolicyPassed},
+		scheme.Violation{ViolationEntityType: namespace.Repository, CanonicalLink: "https://github.com/org/b", Status: analyzers.PolicyFailed},
+	)
+	results.Set("data.repository.missing_license", data)
+
+	other := scheme.NewOutputData(scheme.PolicyInfo{PolicyName: "non_linear_history", Namespace: namespace.Repository})
+	other = scheme.AppendViolations(other,
+		scheme.Violation{ViolationEntityType: namespace.Repository, CanonicalLink: "https://github.com/org/a", Status: analyzers.PolicyFailed},
+	)
+	results.Set("data.repository.non_linear_history", other)
+	return results
+}
+
+func TestPublish(t *testing.T) {
+	var received []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	logFile := filepath.Join(t.TempDir(), "telemetry.log")
+	publisher := NewTelemetryPublisher(server.URL, logFile, "github", []string{"org", "scorecard"})
+	require.NoError(t, publisher.Publish(testResults()))
+
+	logged, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+	require.Equal(t, string(received)+"\n", string(logged))
+
+	var report Report
+	require.NoError(t, json.Unmarshal(received, &report))
+	require.Equal(t, map[string]int{namespace.Repository: 2}, report.Entities)
+	require.Equal(t, 2, report.Policies)
+	require.Equal(t, []string{"org", "scorecard"}, report.Flags)
+	require.NotContains(t, string(received), "github.com/org")
+}
+
+func TestPublishLogOnly(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "telemetry.log")
+	publisher := NewTelemetryPublisher("", logFile, "gitlab", nil)
+	require.NoError(t, publisher.Publish(testResults()))
+	require.NoError(t, publisher.Publish(testResults()))
+
+	logged, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+	require.Len(t, strings.Split(strings.TrimSpace(string(logged)), "\n"), 2)
+}
+
+func TestPublishUnreachable(t *testing.T) {
+	publisher := NewTelemetryPublisher("http://127.0.0.1:1", "", "github", nil)
+	require.NoError(t, publisher.Publish(testResults()))
+}
@@ -0,0 +1,57 @@
+package sampling
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Item is an entity that can be sampled, and the stratum (e.g. "public/active") it belongs to
+type Item struct {
+	ID      string
+	Stratum string
+}
+
+// ParsePercent parses a sample size such as "10%" (or "10")
+func ParsePercent(value string) (int, error) {
+	percent, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(value), "%"))
+	if err != nil || percent <= 0 || percent > 100 {
+		return 0, fmt.Errorf("invalid sample size %s (expecting a percentage between 1%% and 100%%)", value)
+	}
+	return percent, nil
+}
+
+// Size is the number of items a sample of the given percentage picks out of total
+func Size(total, percent int) int {
+	return int(math.Ceil(float64(total) * float64(percent) / 100))
+}
+
+func rank(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return fmt.Sprintf("%x", sum)
+}
+
+// Select picks the given percentage of the items of each stratum (at least one per stratum),
+// so every kind of item is represented in the sample. The selection is deterministic, so repeated
+// quick scans cover the same items and can be compared to each other.
+func Select(items []Item, percent int) map[string]bool {
+	strata := make(map[string][]string)
+	for _, item := range items {
+		strata[item.Stratum] = append(strata[item.Stratum], item.ID)
+	}
+
+	selected := make(map[string]bool)
+	for _, ids := range strata {
+		sort.Slice(ids, func(i, j int) bool {
+			return rank(ids[i]) < rank(ids[j])
+		})
+		for _, id := range ids[:Size(len(ids), percent)] {
+			selected[id] = true
+		}
+	}
+
+	return selected
+}
@@ -0,0 +1,46 @@
+package sampling
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePercent(t *testing.T) {
+	percent, err := ParsePercent("10%")
+	require.NoError(t, err)
+	require.Equal(t, 10, percent)
+
+	percent, err = ParsePercent("25")
+	require.NoError(t, err)
+	require.Equal(t, 25, percent)
+
+	for _, invalid := range []string{"0%", "101%", "abc", ""} {
+		_, err = ParsePercent(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	var items []Item
+	for i := 0; i < 100; i++ {
+		items = append(items, Item{ID: fmt.Sprintf("public-%d", i), Stratum: "public"})
+	}
+	for i := 0; i < 5; i++ {
+		items = append(items, Item{ID: fmt.Sprintf("private-%d", i), Stratum: "private"})
+	}
+
+	selected := Select(items, 10)
+	require.Len(t, selected, 11) // 10 public & at least 1 private
+
+	private := 0
+	for id := range selected {
+		if id[:7] == "private" {
+			private++
+		}
+	}
+	require.Equal(t, 1, private)
+
+	require.Equal(t, selected, Select(items, 10), "the selection should be deterministic")
+}
@@ -0,0 +1,332 @@
+package sarif
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/Legit-Labs/legitify/internal/analyzers"
+	"github.com/Legit-Labs/legitify/internal/common/severity"
+	"github.com/Legit-Labs/legitify/internal/enricher/enrichers"
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme"
+)
+
+const (
+	Version   = "2.1.0"
+	SchemaUri = "https://json.schemastore.org/sarif-2.1.0.json"
+	ToolName  = "legitify"
+	toolUri   = "https://github.com/Legit-Labs/legitify"
+)
+
+type Log struct {
+	Version string `json:"version"`
+	Schema  string `json:"$schema"`
+	Runs    []Run  `json:"runs"`
+}
+
+type Run struct {
+	Tool              Tool               `json:"tool"`
+	AutomationDetails *AutomationDetails `json:"automationDetails,omitempty"`
+	Results           []Result           `json:"results"`
+	Properties        *RunProperties     `json:"properties,omitempty"`
+}
+
+type RunProperties struct {
+	ScanID string `json:"scanId"`
+}
+
+// AutomationDetails identifies the analysis category, so uploads of different scopes don't override each other
+type AutomationDetails struct {
+	ID string `json:"id"`
+	// Guid is the unique ID of the run (the scan ID, when it is a valid GUID)
+	Guid string `json:"guid,omitempty"`
+}
+
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+type Driver struct {
+	Name           string `json:"name"`
+	InformationUri string `json:"informationUri"`
+	Rules          []Rule `json:"rules"`
+}
+
+type Message struct {
+	Text string `json:"text"`
+}
+
+type Rule struct {
+	ID               string         `json:"id"`
+	Name             string         `json:"name"`
+	ShortDescription Message        `json:"shortDescription"`
+	FullDescription  Message        `json:"fullDescription"`
+	Help             Help           `json:"help"`
+	Properties       RuleProperties `json:"properties"`
+}
+
+type Help struct {
+	Text     string `json:"text"`
+	Markdown string `json:"markdown"`
+}
+
+type RuleProperties struct {
+	Tags []string `json:"tags"`
+	// SecuritySeverity is the CVSS-like score GitHub code scanning uses to display the alert severity
+	SecuritySeverity string `json:"security-severity"`
+}
+
+type Result struct {
+	RuleID    string     `json:"ruleId"`
+	RuleIndex int        `json:"ruleIndex"`
+	Level     string     `json:"level"`
+	Message   Message    `json:"message"`
+	Locations []Location `json:"locations"`
+	// PartialFingerprints identify the result across runs (the entity & policy, rather than the fixed location)
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+	Suppressions        []Suppression     `json:"suppressions,omitempty"`
+}
+
+// Suppression marks a result that was dismissed (e.g. in the GitHub UI), so it is not reported as a new alert
+type Suppression struct {
+	Kind          string `json:"kind"`
+	Status        string `json:"status"`
+	Justification string `json:"justification,omitempty"`
+}
+
+const (
+	// FingerprintKey is the partial fingerprint legitify reports its results with
+	FingerprintKey = "legitifyFingerprint/v1"
+
+	suppressionExternal = "external"
+	suppressionAccepted = "accepted"
+)
+
+// Dismissal is a previously reported result that was dismissed, identified by its rule & location
+type Dismissal struct {
+	RuleID        string
+	Location      string
+	Justification string
+}
+
+type Location struct {
+	PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+}
+
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Region           Region           `json:"region"`
+}
+
+type ArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type Region struct {
+	StartLine int `json:"startLine"`
+}
+
+// LocationFunc returns the file the result of the violation is reported on.
+// Findings are about settings rather than code, so there is no real file to point at.
+type LocationFunc func(violation scheme.Violation) string
+
+// EntityLocation reports each result on the path of its entity (e.g. org/repo),
+// which keeps the results of different entities apart when they are uploaded together.
+func EntityLocation(violation scheme.Violation) string {
+	parsed, err := url.Parse(violation.CanonicalLink)
+	if err != nil || strings.Trim(parsed.Path, "/") == "" {
+		return violation.CanonicalLink
+	}
+	return strings.Trim(parsed.Path, "/")
+}
+
+// FixedLocation reports all results on the same path (e.g. when the results of a single repository are uploaded to it).
+func FixedLocation(path string) LocationFunc {
+	return func(violation scheme.Violation) string {
+		return path
+	}
+}
+
+var guidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func fingerprint(ruleID string, violation scheme.Violation) string {
+	sum := sha256.Sum256([]byte(ruleID + "\n" + violation.CanonicalLink))
+	return hex.EncodeToString(sum[:])
+}
+
+func (r *Result) location() string {
+	if len(r.Locations) == 0 {
+		return ""
+	}
+	return r.Locations[0].PhysicalLocation.ArtifactLocation.URI
+}
+
+// Suppress marks the results matching the dismissals as suppressed, and returns the number of suppressed results
+func (l *Log) Suppress(dismissals []Dismissal) int {
+	type key struct {
+		ruleID   string
+		location string
+	}
+	dismissed := make(map[key]Dismissal)
+	for _, d := range dismissals {
+		dismissed[key{d.RuleID, d.Location}] = d
+	}
+
+	suppressed := 0
+	for i := range l.Runs {
+		for j := range l.Runs[i].Results {
+			result := &l.Runs[i].Results[j]
+			d, ok := dismissed[key{result.RuleID, result.location()}]
+			if !ok {
+				continue
+			}
+			result.Suppressions = []Suppression{
+				{Kind: suppressionExternal, Status: suppressionAccepted, Justification: d.Justification},
+			}
+			suppressed++
+		}
+	}
+
+	return suppressed
+}
+
+func level(s severity.Severity) string {
+	switch s {
+	case severity.Critical, severity.High:
+		return "error"
+	case severity.Medium:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+func securitySeverity(s severity.Severity) string {
+	switch s {
+	case severity.Critical:
+		return "9.5"
+	case severity.High:
+		return "8.0"
+	case severity.Medium:
+		return "5.5"
+	default:
+		return "2.0"
+	}
+}
+
+func newRule(info scheme.PolicyInfo) Rule {
+	var text, markdown strings.Builder
+	text.WriteString(info.Description)
+	text.WriteString("\n\nRemediation:\n")
+	markdown.WriteString(info.Description)
+	markdown.WriteString("\n\n**Remediation:**\n")
+	for i, step := range info.RemediationSteps {
+		text.WriteString(fmt.Sprintf("%d. %s\n", i+1, step))
+		markdown.WriteString(fmt.Sprintf("%d. %s\n", i+1, step))
+	}
+
+	return Rule{
+		ID:               info.FullyQualifiedPolicyName,
+		Name:             info.PolicyName,
+		ShortDescription: Message{Text: info.Title},
+		FullDescription:  Message{Text: info.Description},
+		Help: Help{
+			Text:     text.String(),
+			Markdown: markdown.String(),
+		},
+		Properties: RuleProperties{
+			Tags:             []string{"security", info.Namespace},
+			SecuritySeverity: securitySeverity(info.Severity),
+		},
+	}
+}
+
+// FromFlattenedScheme converts the results into a SARIF log.
+// Every policy is reported as a rule and every failed violation as a result.
+func FromFlattenedScheme(results scheme.FlattenedScheme, location LocationFunc, category string) *Log {
+	run := Run{
+		Tool: Tool{
+			Driver: Driver{
+				Name:           ToolName,
+				InformationUri: toolUri,
+				Rules:          []Rule{},
+			},
+		},
+		Results: []Result{},
+	}
+	if category != "" {
+		run.AutomationDetails = &AutomationDetails{ID: category}
+	}
+	if scanID := results.ScanID(); scanID != "" {
+		run.Properties = &RunProperties{ScanID: scanID}
+		if guidPattern.MatchString(scanID) {
+			if run.AutomationDetails == nil {
+				run.AutomationDetails = &AutomationDetails{}
+			}
+			run.AutomationDetails.Guid = scanID
+		}
+	}
+
+	for _, policyName := range results.Keys() {
+		data := results.GetPolicyData(policyName)
+		ruleIndex := len(run.Tool.Driver.Rules)
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, newRule(data.PolicyInfo))
+
+		for _, violation := range data.Violations {
+			if violation.Status != analyzers.PolicyFailed && violation.Status != analyzers.PolicyWaived {
+				continue
+			}
+			run.Results = append(run.Results, Result{
+				Suppressions: waiverSuppressions(violation),
+				RuleID:       data.PolicyInfo.FullyQualifiedPolicyName,
+				RuleIndex:    ruleIndex,
+				Level:        level(data.PolicyInfo.Severity),
+				Message: Message{
+					Text: message(data.PolicyInfo, violation),
+				},
+				PartialFingerprints: map[string]string{
+					FingerprintKey: fingerprint(data.PolicyInfo.FullyQualifiedPolicyName, violation),
+				},
+				Locations: []Location{
+					{
+						PhysicalLocation: PhysicalLocation{
+							ArtifactLocation: ArtifactLocation{URI: location(violation)},
+							Region:           Region{StartLine: 1},
+						},
+					},
+				},
+			})
+		}
+	}
+
+	return &Log{
+		Version: Version,
+		Schema:  SchemaUri,
+		Runs:    []Run{run},
+	}
+}
+
+// message describes the result, noting a confidence lowered by partially collected data
+func message(info scheme.PolicyInfo, violation scheme.Violation) string {
+	text := fmt.Sprintf("%s: %s", info.Title, violation.CanonicalLink)
+	if violation.Confidence != nil {
+		text += fmt.Sprintf(" (%s)", violation.Confidence)
+	}
+	return text
+}
+
+// waiverSuppressions reports waived violations as accepted suppressions, with the waiver justification
+func waiverSuppressions(violation scheme.Violation) []Suppression {
+	if violation.Status != analyzers.PolicyWaived {
+		return nil
+	}
+
+	suppression := Suppression{Kind: suppressionExternal, Status: suppressionAccepted}
+	if waiver, ok := violation.Aux[enrichers.Waiver].(*enrichers.WaiverEnrichment); ok {
+		suppression.Justification = waiver.Justification()
+	}
+	return []Suppression{suppression}
+}
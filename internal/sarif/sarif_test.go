@@ -0,0 +1,110 @@
+package sarif
+
+import (
+	"testing"
+
+	"github.com/Legit-Labs/legitify/internal/analyzers"
+	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/Legit-Labs/legitify/internal/common/severity"
+	"github.com/Legit-Labs/legitify/internal/enricher/enrichers"
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme"
+	"github.com/Legit-Labs/legitify/internal/waivers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromFlattenedScheme(t *testing.T) {
+	results := scheme.NewFlattenedScheme()
+	data := scheme.NewOutputData(scheme.PolicyInfo{
+		Title:                    "policy title",
+		PolicyName:               "policy",
+		FullyQualifiedPolicyName: "data.repository.policy",
+		Severity:                 severity.High,
+		Namespace:                namespace.Repository,
+		RemediationSteps:         []string{"fix it"},
+	})
+	data = scheme.AppendViolations(data,
+		scheme.Violation{CanonicalLink: "https://github.com/org/a", Status: analyzers.PolicyFailed},
+		scheme.Violation{CanonicalLink: "https://github.com/org/b", Status: analyzers.PolicyPassed},
+	)
+	results.Set("data.repository.policy", data)
+
+	log := FromFlattenedScheme(results, EntityLocation, "category")
+	require.Equal(t, Version, log.Version)
+	require.Len(t, log.Runs, 1)
+
+	run := log.Runs[0]
+	require.Equal(t, "category", run.AutomationDetails.ID)
+	require.Len(t, run.Tool.Driver.Rules, 1)
+	require.Equal(t, "data.repository.policy", run.Tool.Driver.Rules[0].ID)
+	require.Contains(t, run.Tool.Driver.Rules[0].Help.Text, "1. fix it")
+
+	require.Len(t, run.Results, 1)
+	require.Equal(t, "error", run.Results[0].Level)
+	require.Equal(t, "org/a", run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+
+	log = FromFlattenedScheme(results, FixedLocation(".github"), "")
+	require.Nil(t, log.Runs[0].AutomationDetails)
+	require.Equal(t, ".github", log.Runs[0].Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+}
+
+func TestScanID(t *testing.T) {
+	results := scheme.NewFlattenedScheme()
+	results.Set("data.repository.policy", scheme.NewOutputData(scheme.PolicyInfo{
+		FullyQualifiedPolicyName: "data.repository.policy",
+		ScanID:                   "0f8fad5b-d9cb-469f-a165-70867728950e",
+	}))
+
+	run := FromFlattenedScheme(results, EntityLocation, "").Runs[0]
+	require.Equal(t, "0f8fad5b-d9cb-469f-a165-70867728950e", run.Properties.ScanID)
+	require.Equal(t, "0f8fad5b-d9cb-469f-a165-70867728950e", run.AutomationDetails.Guid)
+
+	results.Set("data.repository.policy", scheme.NewOutputData(scheme.PolicyInfo{
+		FullyQualifiedPolicyName: "data.repository.policy",
+		ScanID:                   "nightly-42",
+	}))
+
+	run = FromFlattenedScheme(results, EntityLocation, "category").Runs[0]
+	require.Equal(t, "nightly-42", run.Properties.ScanID)
+	require.Empty(t, run.AutomationDetails.Guid, "only GUIDs are valid SARIF run GUIDs")
+}
+
+func TestSuppress(t *testing.T) {
+	results := scheme.NewFlattenedScheme()
+	data := scheme.NewOutputData(scheme.PolicyInfo{FullyQualifiedPolicyName: "data.repository.policy"})
+	data = scheme.AppendViolations(data,
+		scheme.Violation{CanonicalLink: "https://github.com/org/a", Status: analyzers.PolicyFailed},
+		scheme.Violation{CanonicalLink: "https://github.com/org/b", Status: analyzers.PolicyFailed},
+	)
+	results.Set("data.repository.policy", data)
+
+	log := FromFlattenedScheme(results, EntityLocation, "")
+	first, second := log.Runs[0].Results[0], log.Runs[0].Results[1]
+	require.NotEmpty(t, first.PartialFingerprints[FingerprintKey])
+	require.NotEqual(t, first.PartialFingerprints[FingerprintKey], second.PartialFingerprints[FingerprintKey])
+	require.Equal(t, first.PartialFingerprints, FromFlattenedScheme(results, EntityLocation, "").Runs[0].Results[0].PartialFingerprints)
+
+	suppressed := log.Suppress([]Dismissal{
+		{RuleID: "data.repository.policy", Location: "org/b", Justification: "false positive"},
+		{RuleID: "data.repository.other", Location: "org/a"},
+	})
+	require.Equal(t, 1, suppressed)
+	require.Empty(t, log.Runs[0].Results[0].Suppressions)
+	require.Equal(t, []Suppression{{Kind: "external", Status: "accepted", Justification: "false positive"}}, log.Runs[0].Results[1].Suppressions)
+}
+
+func TestWaivedViolations(t *testing.T) {
+	waiver := enrichers.NewWaiverEnrichment(waivers.Waiver{Policy: "policy", Justification: "accepted risk", Source: waivers.SourceProperty})
+	results := scheme.NewFlattenedScheme()
+	data := scheme.NewOutputData(scheme.PolicyInfo{FullyQualifiedPolicyName: "data.repository.policy"})
+	data = scheme.AppendViolations(data,
+		scheme.Violation{CanonicalLink: "https://github.com/org/a", Status: analyzers.PolicyFailed},
+		scheme.Violation{CanonicalLink: "https://github.com/org/b", Status: analyzers.PolicyWaived,
+			Aux: map[string]enrichers.Enrichment{enrichers.Waiver: waiver}},
+	)
+	results.Set("data.repository.policy", data)
+
+	run := FromFlattenedScheme(results, EntityLocation, "").Runs[0]
+	require.Len(t, run.Results, 2)
+	require.Empty(t, run.Results[0].Suppressions)
+	require.Equal(t, []Suppression{{Kind: "external", Status: "accepted", Justification: "accepted risk"}}, run.Results[1].Suppressions)
+}
@@ -0,0 +1,239 @@
+package store
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// The dynamodb table has a string partition key named "store" and a string sort key named "analysis"
+const (
+	dynamoPartitionKey = "store"
+	dynamoSortKey      = "analysis"
+	dynamoPartition    = "legitify"
+	// the results are gzipped and split to chunks, as an item is limited to 400KB
+	dynamoChunkSize = 350 * 1024
+)
+
+// dynamoAPI is the part of the dynamodb client the store uses
+type dynamoAPI interface {
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+}
+
+// dynamoStore keeps each analysis in items of the table: an item per chunk, sorted by the analysis ID and the chunk index
+type dynamoStore struct {
+	client    dynamoAPI
+	table     string
+	chunkSize int
+}
+
+// openDynamoDBStore connects with the default AWS credentials chain (environment, profiles, SSO, instance and task roles);
+// the region and endpoint (e.g. of DynamoDB Local) can be set in the DSN: dynamodb://<table>?region=<region>&endpoint=<url>
+func openDynamoDBStore(table string, params url.Values) (Store, error) {
+	ctx := context.Background()
+	var options []func(*config.LoadOptions) error
+	if region := params.Get("region"); region != "" {
+		options = append(options, config.WithRegion(region))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the AWS configuration: %v", err)
+	}
+
+	var clientOptions []func(*dynamodb.Options)
+	if endpoint := params.Get("endpoint"); endpoint != "" {
+		clientOptions = append(clientOptions, func(o *dynamodb.Options) {
+			o.EndpointResolver = dynamodb.EndpointResolverFromURL(endpoint)
+		})
+	}
+	client := dynamodb.NewFromConfig(cfg, clientOptions...)
+
+	// makes sure the table can be read
+	s := &dynamoStore{client: client, table: table, chunkSize: dynamoChunkSize}
+	if _, err = s.queryLatestItem(ctx); err != nil && err != ErrEmpty {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *dynamoStore) Name() string {
+	return fmt.Sprintf("the dynamodb store (%s)", s.table)
+}
+
+func (s *dynamoStore) Save(results scheme.FlattenedScheme, at time.Time) error {
+	data, err := json.Marshal(results)
+	if err != nil {
+		return err
+	}
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	if _, err = writer.Write(data); err != nil {
+		return err
+	}
+	if err = writer.Close(); err != nil {
+		return err
+	}
+	id, err := newAnalysisID(at)
+	if err != nil {
+		return err
+	}
+
+	chunks := splitChunks(compressed.Bytes(), s.chunkSize)
+	// the chunks are written in order, so the last chunk of an analysis marks it as complete
+	for i, chunk := range chunks {
+		_, err = s.client.PutItem(context.Background(), &dynamodb.PutItemInput{
+			TableName: aws.String(s.table),
+			Item: map[string]types.AttributeValue{
+				dynamoPartitionKey: &types.AttributeValueMemberS{Value: dynamoPartition},
+				dynamoSortKey:      &types.AttributeValueMemberS{Value: chunkKey(id, i)},
+				"analyzed_at":      &types.AttributeValueMemberN{Value: strconv.FormatInt(at.UTC().UnixNano(), 10)},
+				"chunks":           &types.AttributeValueMemberN{Value: strconv.Itoa(len(chunks))},
+				"data":             &types.AttributeValueMemberB{Value: chunk},
+			},
+			// never overwrite stored results
+			ConditionExpression:      aws.String("attribute_not_exists(#a)"),
+			ExpressionAttributeNames: map[string]string{"#a": dynamoSortKey},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *dynamoStore) Latest() ([]byte, time.Time, error) {
+	ctx := context.Background()
+	item, err := s.queryLatestItem(ctx)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	id, _, err := parseChunkKey(stringAttribute(item, dynamoSortKey))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	chunks, err := strconv.Atoi(numberAttribute(item, "chunks"))
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("invalid chunks count of the stored results %s: %v", id, err)
+	}
+	analyzedAt, err := strconv.ParseInt(numberAttribute(item, "analyzed_at"), 10, 64)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("invalid analysis time of the stored results %s: %v", id, err)
+	}
+
+	var compressed []byte
+	var read int
+	var startKey map[string]types.AttributeValue
+	for {
+		output, err := s.client.Query(ctx, &dynamodb.QueryInput{
+			TableName:                aws.String(s.table),
+			KeyConditionExpression:   aws.String("#s = :s AND begins_with(#a, :id)"),
+			ExpressionAttributeNames: map[string]string{"#s": dynamoPartitionKey, "#a": dynamoSortKey},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":s":  &types.AttributeValueMemberS{Value: dynamoPartition},
+				":id": &types.AttributeValueMemberS{Value: id + "#"},
+			},
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		for _, chunk := range output.Items {
+			data, ok := chunk["data"].(*types.AttributeValueMemberB)
+			if !ok {
+				return nil, time.Time{}, fmt.Errorf("invalid chunk of the stored results %s", id)
+			}
+			compressed = append(compressed, data.Value...)
+			read++
+		}
+		if len(output.LastEvaluatedKey) == 0 {
+			break
+		}
+		startKey = output.LastEvaluatedKey
+	}
+	if read != chunks {
+		return nil, time.Time{}, fmt.Errorf("the stored results %s are incomplete (%d of %d chunks)", id, read, chunks)
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return data, time.Unix(0, analyzedAt).UTC(), nil
+}
+
+// queryLatestItem returns the item with the highest sort key: the last chunk of the latest analysis
+func (s *dynamoStore) queryLatestItem(ctx context.Context) (map[string]types.AttributeValue, error) {
+	output, err := s.client.Query(ctx, &dynamodb.QueryInput{
+		TableName:                 aws.String(s.table),
+		KeyConditionExpression:    aws.String("#s = :s"),
+		ExpressionAttributeNames:  map[string]string{"#s": dynamoPartitionKey},
+		ExpressionAttributeValues: map[string]types.AttributeValue{":s": &types.AttributeValueMemberS{Value: dynamoPartition}},
+		ScanIndexForward:          aws.Bool(false),
+		Limit:                     aws.Int32(1),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the %s table: %v", s.table, err)
+	}
+	if len(output.Items) == 0 {
+		return nil, ErrEmpty
+	}
+	return output.Items[0], nil
+}
+
+func splitChunks(data []byte, size int) [][]byte {
+	var chunks [][]byte
+	for len(data) > size {
+		chunks = append(chunks, data[:size])
+		data = data[size:]
+	}
+	return append(chunks, data)
+}
+
+// chunkKey is the sort key of a chunk: the analysis ID and the (zero padded, so sorted) chunk index
+func chunkKey(id string, index int) string {
+	return fmt.Sprintf("%s#%05d", id, index)
+}
+
+func parseChunkKey(key string) (id string, index int, err error) {
+	i := strings.LastIndex(key, "#")
+	if i < 0 {
+		return "", 0, fmt.Errorf("invalid stored results key %s", key)
+	}
+	index, err = strconv.Atoi(key[i+1:])
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid stored results key %s: %v", key, err)
+	}
+	return key[:i], index, nil
+}
+
+func stringAttribute(item map[string]types.AttributeValue, name string) string {
+	if value, ok := item[name].(*types.AttributeValueMemberS); ok {
+		return value.Value
+	}
+	return ""
+}
+
+func numberAttribute(item map[string]types.AttributeValue, name string) string {
+	if value, ok := item[name].(*types.AttributeValueMemberN); ok {
+		return value.Value
+	}
+	return ""
+}
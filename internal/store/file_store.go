@@ -0,0 +1,126 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme"
+)
+
+const (
+	filePrefix = "legitify-"
+	fileSuffix = ".json"
+	// legacyFileTimeFormat is the file name of the results stored before the names included the analysis ID
+	legacyFileTimeFormat = "20060102T150405Z"
+)
+
+// fileStore keeps each analysis in its own file (legitify-<analysis ID>.json) in a directory
+type fileStore struct {
+	dir string
+}
+
+func NewFileStore(dir string) Store {
+	return &fileStore{dir: dir}
+}
+
+// openFileStore creates the directory, so a directory that can't be written fails before the analysis
+func openFileStore(dir string) (Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	probe, err := os.CreateTemp(dir, "."+filePrefix+"*")
+	if err != nil {
+		return nil, err
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	return NewFileStore(dir), nil
+}
+
+func (s *fileStore) Name() string {
+	return fmt.Sprintf("the file store (%s)", s.dir)
+}
+
+func (s *fileStore) Save(results scheme.FlattenedScheme, at time.Time) error {
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+	id, err := newAnalysisID(at)
+	if err != nil {
+		return err
+	}
+
+	// written to a temporary file first, so a partially written document is never read as the latest
+	temp, err := os.CreateTemp(s.dir, "."+filePrefix+"*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	if _, err = temp.Write(data); err != nil {
+		temp.Close()
+		return err
+	}
+	if err = temp.Close(); err != nil {
+		return err
+	}
+
+	// linking fails if the file exists, so stored results are never overwritten
+	return os.Link(temp.Name(), filepath.Join(s.dir, filePrefix+id+fileSuffix))
+}
+
+func (s *fileStore) Latest() ([]byte, time.Time, error) {
+	entries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil, time.Time{}, ErrEmpty
+	} else if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	var latest string
+	var latestTime time.Time
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, filePrefix) || !strings.HasSuffix(name, fileSuffix) {
+			continue
+		}
+		at, err := parseFileTime(name)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		if latest == "" || at.After(latestTime) || (at.Equal(latestTime) && name > latest) {
+			latest, latestTime = name, at
+		}
+	}
+	if latest == "" {
+		return nil, time.Time{}, ErrEmpty
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.dir, latest))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return data, latestTime, nil
+}
+
+// parseFileTime reads the analysis time from the file name (legitify-<time>-<random>.json, or legitify-<time>.json)
+func parseFileTime(name string) (time.Time, error) {
+	id := strings.TrimSuffix(strings.TrimPrefix(name, filePrefix), fileSuffix)
+	format := legacyFileTimeFormat
+	if i := strings.LastIndex(id, "-"); i >= 0 {
+		id, format = id[:i], idTimeFormat
+	}
+	at, err := time.Parse(format, id)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid stored results file name %s: %v", name, err)
+	}
+	return at, nil
+}
@@ -0,0 +1,96 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme"
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
+)
+
+// dialect is a database/sql driver, and how its queries refer to the arguments
+type dialect struct {
+	name        string
+	driver      string
+	placeholder func(index int) string
+}
+
+var (
+	sqliteDialect = dialect{
+		name:        schemeSQLite,
+		driver:      "sqlite",
+		placeholder: func(int) string { return "?" },
+	}
+	postgresDialect = dialect{
+		name:        schemePostgres,
+		driver:      "postgres",
+		placeholder: func(index int) string { return fmt.Sprintf("$%d", index) },
+	}
+)
+
+const resultsTable = "legitify_results"
+
+// sqlStore keeps each analysis in a row of the legitify_results table (created if it doesn't exist)
+type sqlStore struct {
+	dialect dialect
+	db      *sql.DB
+	// location describes the database in the store name (without the credentials)
+	location string
+}
+
+func openSQLStore(d dialect, dataSource string, location string) (Store, error) {
+	db, err := sql.Open(d.driver, dataSource)
+	if err != nil {
+		return nil, err
+	}
+
+	// the analyzed_at column is the UTC time of the analysis in unix nanoseconds, which both databases sort the same
+	create := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id VARCHAR(64) PRIMARY KEY,
+	analyzed_at BIGINT NOT NULL,
+	results TEXT NOT NULL
+)`, resultsTable)
+	if _, err = db.Exec(create); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create the %s table: %v", resultsTable, err)
+	}
+
+	return &sqlStore{dialect: d, db: db, location: location}, nil
+}
+
+func (s *sqlStore) Name() string {
+	return fmt.Sprintf("the %s store (%s)", s.dialect.name, s.location)
+}
+
+func (s *sqlStore) Save(results scheme.FlattenedScheme, at time.Time) error {
+	data, err := json.Marshal(results)
+	if err != nil {
+		return err
+	}
+	id, err := newAnalysisID(at)
+	if err != nil {
+		return err
+	}
+
+	insert := fmt.Sprintf("INSERT INTO %s (id, analyzed_at, results) VALUES (%s, %s, %s)",
+		resultsTable, s.dialect.placeholder(1), s.dialect.placeholder(2), s.dialect.placeholder(3))
+	_, err = s.db.Exec(insert, id, at.UTC().UnixNano(), string(data))
+	return err
+}
+
+func (s *sqlStore) Latest() ([]byte, time.Time, error) {
+	query := fmt.Sprintf("SELECT results, analyzed_at FROM %s ORDER BY analyzed_at DESC, id DESC LIMIT 1", resultsTable)
+
+	var results string
+	var analyzedAt int64
+	err := s.db.QueryRow(query).Scan(&results, &analyzedAt)
+	if err == sql.ErrNoRows {
+		return nil, time.Time{}, ErrEmpty
+	} else if err != nil {
+		return nil, time.Time{}, err
+	}
+	return []byte(results), time.Unix(0, analyzedAt).UTC(), nil
+}
@@ -0,0 +1,132 @@
+package store
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme"
+)
+
+// Store keeps the results of the analyses (as json documents of the flattened scheme), so later commands (e.g. history)
+// can read them without passing the output files around
+type Store interface {
+	// Save stores the results of an analysis that ran at the given time
+	Save(results scheme.FlattenedScheme, at time.Time) error
+	// Latest returns the most recent results and the time of their analysis (ErrEmpty if no results were stored)
+	Latest() ([]byte, time.Time, error)
+	Name() string
+}
+
+var ErrEmpty = fmt.Errorf("no results were stored")
+
+const (
+	schemeFile       = "file"
+	schemeSQLite     = "sqlite"
+	schemePostgres   = "postgres"
+	schemeDynamoDB   = "dynamodb"
+	supportedSchemes = "file://<directory>, sqlite://<file>, postgres://<user>:<password>@<host>/<database>, dynamodb://<table>"
+)
+
+// schemeAliases are the alternative names of the schemes
+var schemeAliases = map[string]string{
+	"sqlite3":    schemeSQLite,
+	"postgresql": schemePostgres,
+}
+
+// location is a parsed DSN: the scheme of the store, and where it keeps the results
+type location struct {
+	scheme string
+	// path is the directory of the file store, the database file of the sqlite store, or the table of the dynamodb store
+	path   string
+	dsn    string
+	params url.Values
+}
+
+func parseDSN(dsn string) (location, error) {
+	if dsn == "" {
+		return location{}, fmt.Errorf("missing store DSN")
+	}
+	if !strings.Contains(dsn, "://") {
+		return location{scheme: schemeFile, path: dsn, dsn: dsn}, nil
+	}
+
+	parsed, err := url.Parse(dsn)
+	if err != nil {
+		return location{}, fmt.Errorf("invalid store DSN: %v", err)
+	}
+	storeScheme := strings.ToLower(parsed.Scheme)
+	if alias, ok := schemeAliases[storeScheme]; ok {
+		storeScheme = alias
+	}
+
+	path := parsed.Path
+	if parsed.Host != "" {
+		// a relative path (e.g. file://results) or the dynamodb table
+		path = parsed.Host + parsed.Path
+	}
+	result := location{scheme: storeScheme, path: path, dsn: dsn, params: parsed.Query()}
+
+	switch storeScheme {
+	case schemeFile, schemeSQLite, schemeDynamoDB:
+		if path == "" {
+			return location{}, fmt.Errorf("the %s store DSN is missing its location (supported: %s)", storeScheme, supportedSchemes)
+		}
+	case schemePostgres:
+		// the DSN is passed to the driver as is
+	default:
+		return location{}, fmt.Errorf("unknown store scheme %s (supported: %s)", storeScheme, supportedSchemes)
+	}
+	return result, nil
+}
+
+// Open selects the store by the scheme of the DSN, and connects to it (so an unusable store fails before the analysis):
+// file://<directory> (or a plain directory path), sqlite://<file>, postgres://... (passed to the driver as is)
+// and dynamodb://<table>?region=<region> (with the default AWS credentials chain)
+func Open(dsn string) (Store, error) {
+	loc, err := parseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	switch loc.scheme {
+	case schemeFile:
+		return openFileStore(loc.path)
+	case schemeSQLite:
+		return openSQLStore(sqliteDialect, loc.path, loc.path)
+	case schemePostgres:
+		return openSQLStore(postgresDialect, loc.dsn, redact(loc.dsn))
+	default:
+		return openDynamoDBStore(loc.path, loc.params)
+	}
+}
+
+// Validate makes sure the DSN selects a supported store, without connecting to it
+func Validate(dsn string) error {
+	_, err := parseDSN(dsn)
+	return err
+}
+
+// newAnalysisID identifies the stored results of an analysis: the time of the analysis (sorted chronologically),
+// and a random suffix, so analyses that ran at the same time don't overwrite each other
+func newAnalysisID(at time.Time) (string, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+	return at.UTC().Format(idTimeFormat) + "-" + hex.EncodeToString(suffix), nil
+}
+
+const idTimeFormat = "20060102T150405.000000000Z"
+
+// redact hides the password of the DSN
+func redact(dsn string) string {
+	parsed, err := url.Parse(dsn)
+	if err != nil {
+		return "postgres"
+	}
+	return parsed.Redacted()
+}
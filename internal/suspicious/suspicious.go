@@ -0,0 +1,59 @@
+package suspicious
+
+import (
+	"path"
+	"strings"
+)
+
+// File is a file whose name suggests it contains secrets
+type File struct {
+	Path string `json:"path"`
+	// Pattern is the name pattern the file matched
+	Pattern string `json:"pattern"`
+}
+
+// patterns are (path.Match) patterns of the base names of files that usually contain secrets
+var patterns = []string{
+	".env",
+	".env.local",
+	".env.production",
+	"id_rsa",
+	"id_dsa",
+	"id_ecdsa",
+	"id_ed25519",
+	"*.pem",
+	"*.key",
+	"*.p12",
+	"*.pfx",
+	"*.jks",
+	"*.keystore",
+	".npmrc",
+	".pypirc",
+	".netrc",
+	".htpasswd",
+	".git-credentials",
+	"credentials.json",
+	"*.tfstate",
+}
+
+// Match returns the pattern that the file matches (if any)
+func Match(filePath string) (string, bool) {
+	name := strings.ToLower(path.Base(filePath))
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+// Find returns the suspicious files among the given paths
+func Find(paths []string) []File {
+	result := []File{}
+	for _, p := range paths {
+		if pattern, ok := Match(p); ok {
+			result = append(result, File{Path: p, Pattern: pattern})
+		}
+	}
+	return result
+}
@@ -0,0 +1,30 @@
+package suspicious
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatch(t *testing.T) {
+	pattern, ok := Match("config/.env")
+	require.True(t, ok)
+	require.Equal(t, ".env", pattern)
+
+	pattern, ok = Match("deploy/keys/server.PEM")
+	require.True(t, ok)
+	require.Equal(t, "*.pem", pattern)
+
+	for _, path := range []string{".env.example", "src/keys.go", "README.md", "docs/id_rsa.md"} {
+		_, ok = Match(path)
+		require.False(t, ok, path)
+	}
+}
+
+func TestFind(t *testing.T) {
+	files := Find([]string{"main.go", ".ssh/id_ed25519", "infra/terraform.tfstate"})
+	require.Equal(t, []File{
+		{Path: ".ssh/id_ed25519", Pattern: "id_ed25519"},
+		{Path: "infra/terraform.tfstate", Pattern: "*.tfstate"},
+	}, files)
+}
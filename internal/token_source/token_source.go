@@ -0,0 +1,62 @@
+package token_source
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Stdin is the token file name that reads the token from the standard input
+const Stdin = "-"
+
+// FromFile reads the token from a file (or from stdin, for "-"), ignoring the surrounding whitespace
+func FromFile(path string, stdin io.Reader) (string, error) {
+	var data []byte
+	var err error
+	if path == Stdin {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read the token: %v", err)
+	}
+
+	return nonEmpty(string(data), "the token file is empty")
+}
+
+// FromCommand runs a shell command (e.g. "op read op://vault/legitify/token") and reads the token from its output,
+// so the token can be taken from any secret manager without passing it as an argument.
+// The command's stderr is passed through, e.g. for the secret manager's prompts.
+func FromCommand(command string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+	if err := cmd.Run(); err != nil {
+		// the command itself is not reported, as it might include secrets
+		return "", fmt.Errorf("the token command failed: %v", err)
+	}
+
+	return nonEmpty(stdout.String(), "the token command returned an empty token")
+}
+
+func nonEmpty(token string, message string) (string, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", errors.New(message)
+	}
+	return token, nil
+}
@@ -0,0 +1,46 @@
+package token_source
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte("  ghp_token\n"), 0600))
+
+	token, err := FromFile(path, nil)
+	require.NoError(t, err)
+	require.Equal(t, "ghp_token", token)
+
+	token, err = FromFile(Stdin, strings.NewReader("glpat-token\n"))
+	require.NoError(t, err)
+	require.Equal(t, "glpat-token", token)
+
+	_, err = FromFile(Stdin, strings.NewReader("\n"))
+	require.Error(t, err)
+
+	_, err = FromFile(filepath.Join(t.TempDir(), "missing"), nil)
+	require.Error(t, err)
+}
+
+func TestFromCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test commands are posix shell commands")
+	}
+
+	token, err := FromCommand("echo ghp_token")
+	require.NoError(t, err)
+	require.Equal(t, "ghp_token", token)
+
+	_, err = FromCommand("true")
+	require.Error(t, err)
+
+	_, err = FromCommand("exit 1")
+	require.Error(t, err)
+}
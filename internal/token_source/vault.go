@@ -0,0 +1,291 @@
+package token_source
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	VaultAuthToken      = "token"
+	VaultAuthAppRole    = "approle"
+	VaultAuthKubernetes = "kubernetes"
+
+	DefaultVaultField = "token"
+
+	kubernetesTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	// refreshMargin is how long before its expiry the token is read again, so requests don't start with a token that is about to expire
+	refreshMargin = time.Minute
+)
+
+func VaultAuthMethods() []string {
+	return []string{VaultAuthToken, VaultAuthAppRole, VaultAuthKubernetes}
+}
+
+// Refresher returns the current token, reading a new one when the previous one expires
+type Refresher interface {
+	Token() (string, error)
+}
+
+// VaultOptions configure how the token is read from Vault.
+// The address and the credentials are taken from the standard Vault environment variables (VAULT_ADDR, VAULT_NAMESPACE,
+// VAULT_TOKEN for the token auth method, VAULT_ROLE_ID and VAULT_SECRET_ID for AppRole).
+type VaultOptions struct {
+	// Path is the secret path, e.g. "secret/data/legitify" (KV v2) or "github/token" (a secrets engine that issues tokens)
+	Path string
+	// Field is the field of the secret that holds the token
+	Field string
+	// AuthMethod is one of VaultAuthMethods
+	AuthMethod string
+	// AuthMount is the mount path of the auth method (defaults to the method name)
+	AuthMount string
+	// Role is the role of the kubernetes auth method
+	Role string
+}
+
+// VaultSource reads the token from Vault, and reads it again when its lease expires (e.g. during long scans)
+type VaultSource struct {
+	options   VaultOptions
+	address   string
+	namespace string
+	client    *http.Client
+
+	lock       sync.Mutex
+	vaultToken string
+	token      string
+	expiry     time.Time
+}
+
+func NewVaultSource(options VaultOptions) (*VaultSource, error) {
+	address := strings.TrimRight(os.Getenv("VAULT_ADDR"), "/")
+	if address == "" {
+		return nil, fmt.Errorf("missing Vault address (set VAULT_ADDR)")
+	}
+	if options.Field == "" {
+		options.Field = DefaultVaultField
+	}
+	switch options.AuthMethod {
+	case "":
+		options.AuthMethod = VaultAuthToken
+	case VaultAuthToken, VaultAuthAppRole, VaultAuthKubernetes:
+	default:
+		return nil, fmt.Errorf("invalid Vault auth method %s (expected one of %s)", options.AuthMethod, strings.Join(VaultAuthMethods(), ", "))
+	}
+	if options.AuthMount == "" {
+		options.AuthMount = options.AuthMethod
+	}
+
+	source := &VaultSource{
+		options:   options,
+		address:   address,
+		namespace: os.Getenv("VAULT_NAMESPACE"),
+		// the default transport is read per request, so the proxy & TLS options apply
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+	if options.AuthMethod == VaultAuthToken {
+		source.vaultToken = os.Getenv("VAULT_TOKEN")
+		if source.vaultToken == "" {
+			return nil, fmt.Errorf("missing Vault token (set VAULT_TOKEN)")
+		}
+	}
+
+	return source, nil
+}
+
+// Address returns the Vault address
+func (s *VaultSource) Address() string {
+	return s.address
+}
+
+func (s *VaultSource) Token() (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.token != "" && (s.expiry.IsZero() || time.Now().Add(refreshMargin).Before(s.expiry)) {
+		return s.token, nil
+	}
+
+	token, expiry, err := s.read()
+	if err != nil {
+		return "", err
+	}
+	s.token = token
+	s.expiry = expiry
+	return token, nil
+}
+
+type vaultResponse struct {
+	LeaseDuration int                    `json:"lease_duration"`
+	Data          map[string]interface{} `json:"data"`
+	Auth          *struct {
+		ClientToken string `json:"client_token"`
+	} `json:"auth"`
+	Errors []string `json:"errors"`
+}
+
+type vaultError struct {
+	status int
+	errors []string
+}
+
+func (e *vaultError) Error() string {
+	return fmt.Sprintf("vault request failed (%d): %s", e.status, strings.Join(e.errors, "; "))
+}
+
+// read reads the secret (logging in again if the Vault token expired) and returns the token and its expiry (zero if it doesn't expire)
+func (s *VaultSource) read() (string, time.Time, error) {
+	if s.vaultToken == "" {
+		if err := s.login(); err != nil {
+			return "", time.Time{}, err
+		}
+	}
+
+	resp, err := s.request(http.MethodGet, s.options.Path, nil)
+	var verr *vaultError
+	if errors.As(err, &verr) && verr.status == http.StatusForbidden && s.options.AuthMethod != VaultAuthToken {
+		if err = s.login(); err != nil {
+			return "", time.Time{}, err
+		}
+		resp, err = s.request(http.MethodGet, s.options.Path, nil)
+	}
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	data := resp.Data
+	// KV v2 secrets are nested in data.data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		data = nested
+	}
+	token, ok := data[s.options.Field].(string)
+	if !ok || token == "" {
+		return "", time.Time{}, fmt.Errorf("the Vault secret %s has no %s field", s.options.Path, s.options.Field)
+	}
+
+	var expiry time.Time
+	if resp.LeaseDuration > 0 {
+		expiry = time.Now().Add(time.Duration(resp.LeaseDuration) * time.Second)
+	}
+	if expiresAt, ok := data["expires_at"].(string); ok {
+		if parsed, err := time.Parse(time.RFC3339, expiresAt); err == nil && (expiry.IsZero() || parsed.Before(expiry)) {
+			expiry = parsed
+		}
+	}
+
+	return token, expiry, nil
+}
+
+func (s *VaultSource) login() error {
+	var body map[string]string
+	switch s.options.AuthMethod {
+	case VaultAuthAppRole:
+		body = map[string]string{
+			"role_id":   os.Getenv("VAULT_ROLE_ID"),
+			"secret_id": os.Getenv("VAULT_SECRET_ID"),
+		}
+		if body["role_id"] == "" {
+			return fmt.Errorf("missing AppRole role id (set VAULT_ROLE_ID)")
+		}
+	case VaultAuthKubernetes:
+		jwt, err := os.ReadFile(kubernetesTokenPath)
+		if err != nil {
+			return fmt.Errorf("failed to read the kubernetes service account token: %v", err)
+		}
+		body = map[string]string{
+			"role": s.options.Role,
+			"jwt":  strings.TrimSpace(string(jwt)),
+		}
+	default:
+		return fmt.Errorf("the Vault token is invalid or expired")
+	}
+
+	resp, err := s.request(http.MethodPost, fmt.Sprintf("auth/%s/login", s.options.AuthMount), body)
+	if err != nil {
+		return err
+	}
+	if resp.Auth == nil || resp.Auth.ClientToken == "" {
+		return fmt.Errorf("the Vault login returned no token")
+	}
+	s.vaultToken = resp.Auth.ClientToken
+	return nil
+}
+
+func (s *VaultSource) request(method string, path string, body interface{}) (*vaultResponse, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	u := fmt.Sprintf("%s/v1/%s", s.address, (&url.URL{Path: strings.Trim(path, "/")}).EscapedPath())
+	req, err := http.NewRequest(method, u, reader)
+	if err != nil {
+		return nil, err
+	}
+	if s.vaultToken != "" {
+		req.Header.Set("X-Vault-Token", s.vaultToken)
+	}
+	if s.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", s.namespace)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result vaultResponse
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if resp.StatusCode >= http.StatusBadRequest {
+		// the errors are only reported if the response is a Vault error
+		return nil, &vaultError{status: resp.StatusCode, errors: result.Errors}
+	}
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	return &result, nil
+}
+
+type headerTransport struct {
+	base      http.RoundTripper
+	header    string
+	prefix    string
+	refresher Refresher
+}
+
+// NewHeaderTransport returns a transport that sets the header to the current token of the refresher (after the prefix, e.g. "Bearer ")
+// on every request
+func NewHeaderTransport(base http.RoundTripper, header string, prefix string, refresher Refresher) http.RoundTripper {
+	return &headerTransport{
+		base:      base,
+		header:    header,
+		prefix:    prefix,
+		refresher: refresher,
+	}
+}
+
+func (t *headerTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	token, err := t.refresher.Token()
+	if err != nil {
+		if request.Body != nil {
+			_ = request.Body.Close()
+		}
+		return nil, err
+	}
+
+	clone := request.Clone(request.Context())
+	clone.Header.Set(t.header, t.prefix+token)
+	return t.base.RoundTrip(clone)
+}
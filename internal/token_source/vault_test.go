@@ -0,0 +1,114 @@
+package token_source
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVaultKVSecret(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/secret/data/legitify", r.URL.Path)
+		require.Equal(t, "vault-token", r.Header.Get("X-Vault-Token"))
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{"data": map[string]string{"token": "ghp_token"}},
+		})
+	}))
+	defer server.Close()
+	t.Setenv("VAULT_ADDR", server.URL)
+	t.Setenv("VAULT_TOKEN", "vault-token")
+
+	source, err := NewVaultSource(VaultOptions{Path: "secret/data/legitify"})
+	require.NoError(t, err)
+	token, err := source.Token()
+	require.NoError(t, err)
+	require.Equal(t, "ghp_token", token)
+
+	source, err = NewVaultSource(VaultOptions{Path: "secret/data/legitify", Field: "pat"})
+	require.NoError(t, err)
+	_, err = source.Token()
+	require.Error(t, err)
+}
+
+func TestVaultAppRoleRefresh(t *testing.T) {
+	logins, reads := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/auth/approle/login":
+			logins++
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"auth": map[string]string{"client_token": "vault-token"}})
+		case "/v1/github/token":
+			reads++
+			if reads == 2 {
+				// the Vault token expired
+				w.WriteHeader(http.StatusForbidden)
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{"errors": []string{"permission denied"}})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"lease_duration": 30,
+				"data":           map[string]string{"token": "ghs_token"},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("VAULT_ADDR", server.URL)
+	t.Setenv("VAULT_ROLE_ID", "role")
+	t.Setenv("VAULT_SECRET_ID", "secret")
+
+	source, err := NewVaultSource(VaultOptions{Path: "github/token", AuthMethod: VaultAuthAppRole})
+	require.NoError(t, err)
+	token, err := source.Token()
+	require.NoError(t, err)
+	require.Equal(t, "ghs_token", token)
+	require.Equal(t, 1, logins)
+
+	// the lease (30s) is within the refresh margin, so the token is read again
+	require.True(t, source.expiry.Before(time.Now().Add(refreshMargin)))
+	token, err = source.Token()
+	require.NoError(t, err)
+	require.Equal(t, "ghs_token", token)
+	require.Equal(t, 2, logins)
+	require.Equal(t, 3, reads)
+}
+
+func TestVaultInvalidOptions(t *testing.T) {
+	t.Setenv("VAULT_ADDR", "")
+	_, err := NewVaultSource(VaultOptions{Path: "secret/data/legitify"})
+	require.Error(t, err)
+
+	t.Setenv("VAULT_ADDR", "https://vault.example.com")
+	t.Setenv("VAULT_TOKEN", "")
+	_, err = NewVaultSource(VaultOptions{Path: "secret/data/legitify"})
+	require.Error(t, err)
+
+	_, err = NewVaultSource(VaultOptions{Path: "secret/data/legitify", AuthMethod: "ldap"})
+	require.Error(t, err)
+}
+
+type staticRefresher string
+
+func (r staticRefresher) Token() (string, error) {
+	return string(r), nil
+}
+
+func TestHeaderTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "glpat-new", r.Header.Get("PRIVATE-TOKEN"))
+	}))
+	defer server.Close()
+
+	client := http.Client{Transport: NewHeaderTransport(http.DefaultTransport, "PRIVATE-TOKEN", "", staticRefresher("glpat-new"))}
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("PRIVATE-TOKEN", "glpat-old")
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+}
@@ -0,0 +1,183 @@
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	tracesPath  = "/v1/traces"
+	serviceName = "legitify"
+)
+
+// exporter sends the spans to an OTLP/HTTP collector, using the JSON encoding of the protocol
+type exporter struct {
+	url    string
+	client *http.Client
+}
+
+func newExporter(endpoint string) *exporter {
+	url := strings.TrimSuffix(endpoint, "/")
+	if !strings.HasSuffix(url, tracesPath) {
+		url += tracesPath
+	}
+	return &exporter{
+		url:    url,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpScopeSpans struct {
+	Scope struct {
+		Name string `json:"name"`
+	} `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpResourceSpans struct {
+	Resource struct {
+		Attributes []otlpAttribute `json:"attributes"`
+	} `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+// status codes as defined by OTLP
+const (
+	statusOk    = 1
+	statusError = 2
+)
+
+func toValue(value interface{}) otlpValue {
+	switch v := value.(type) {
+	case string:
+		return otlpValue{StringValue: &v}
+	case bool:
+		return otlpValue{BoolValue: &v}
+	case int:
+		s := strconv.Itoa(v)
+		return otlpValue{IntValue: &s}
+	case int64:
+		s := strconv.FormatInt(v, 10)
+		return otlpValue{IntValue: &s}
+	case float64:
+		return otlpValue{DoubleValue: &v}
+	default:
+		s := fmt.Sprint(v)
+		return otlpValue{StringValue: &s}
+	}
+}
+
+func toAttributes(attributes map[string]interface{}) []otlpAttribute {
+	var keys []string
+	for k := range attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	result := []otlpAttribute{}
+	for _, k := range keys {
+		result = append(result, otlpAttribute{Key: k, Value: toValue(attributes[k])})
+	}
+	return result
+}
+
+func toOtlpSpan(span *Span) otlpSpan {
+	span.lock.Lock()
+	defer span.lock.Unlock()
+
+	status := otlpStatus{Code: statusOk}
+	if span.err != nil {
+		status = otlpStatus{Code: statusError, Message: span.err.Error()}
+	}
+
+	return otlpSpan{
+		TraceID:           span.traceID,
+		SpanID:            span.spanID,
+		ParentSpanID:      span.parentID,
+		Name:              span.name,
+		Kind:              span.kind,
+		StartTimeUnixNano: strconv.FormatInt(span.start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(span.end.UnixNano(), 10),
+		Attributes:        toAttributes(span.attributes),
+		Status:            status,
+	}
+}
+
+func newOtlpRequest(spans []*Span) otlpRequest {
+	scopeSpans := otlpScopeSpans{}
+	scopeSpans.Scope.Name = serviceName
+	for _, span := range spans {
+		scopeSpans.Spans = append(scopeSpans.Spans, toOtlpSpan(span))
+	}
+
+	resourceSpans := otlpResourceSpans{ScopeSpans: []otlpScopeSpans{scopeSpans}}
+	resourceSpans.Resource.Attributes = toAttributes(map[string]interface{}{"service.name": serviceName})
+
+	return otlpRequest{ResourceSpans: []otlpResourceSpans{resourceSpans}}
+}
+
+func (e *exporter) export(spans []*Span) error {
+	if len(spans) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(newOtlpRequest(spans))
+	if err != nil {
+		return err
+	}
+
+	resp, err := e.client.Post(e.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		log.Printf("failed to export %d spans to %s: %v", len(spans), e.url, err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		err = fmt.Errorf("failed to export %d spans to %s: %s", len(spans), e.url, resp.Status)
+		log.Print(err)
+		return err
+	}
+
+	return nil
+}
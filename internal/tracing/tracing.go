@@ -0,0 +1,174 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+type spanKind = int
+
+// span kinds as defined by OTLP
+const (
+	kindInternal spanKind = 1
+	kindClient   spanKind = 3
+)
+
+type contextKey string
+
+const spanKey contextKey = "span"
+
+// Span is a single timed operation of the analysis (e.g. the collection of a namespace or an API call).
+// All of its methods are safe to call on a nil span, which is what Start returns when tracing is disabled.
+type Span struct {
+	lock       sync.Mutex
+	tracer     *Tracer
+	name       string
+	kind       spanKind
+	traceID    string
+	spanID     string
+	parentID   string
+	start      time.Time
+	end        time.Time
+	attributes map[string]interface{}
+	err        error
+}
+
+// SetAttribute annotates the span (values are strings, bools or numbers)
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.attributes[key] = value
+}
+
+// End completes the span, marking it as failed when an error is given
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	s.end = time.Now()
+	s.err = err
+	s.lock.Unlock()
+	s.tracer.finished(s)
+}
+
+// Tracer keeps the finished spans and exports them in batches
+type Tracer struct {
+	lock     sync.Mutex
+	exporter *exporter
+	root     *Span
+	pending  []*Span
+	exports  sync.WaitGroup
+}
+
+var defaultTracer *Tracer
+
+// Init enables tracing, exporting the spans to an OTLP/HTTP collector (e.g. http://localhost:4318).
+// All the spans of the run are children of a single root span, which is completed by Shutdown.
+func Init(endpoint string, rootName string) {
+	t := &Tracer{
+		exporter: newExporter(endpoint),
+	}
+	t.root = t.newSpan(nil, rootName, kindInternal)
+	defaultTracer = t
+}
+
+// RootSpan returns the span of the whole run (nil when tracing is disabled)
+func RootSpan() *Span {
+	if defaultTracer == nil {
+		return nil
+	}
+	return defaultTracer.root
+}
+
+// Shutdown completes the root span and exports all the remaining spans
+func Shutdown(err error) error {
+	t := defaultTracer
+	if t == nil {
+		return nil
+	}
+	defaultTracer = nil
+
+	t.root.End(err)
+	t.exports.Wait()
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	pending := t.pending
+	t.pending = nil
+	return t.exporter.export(pending)
+}
+
+// Start opens a span as a child of the span of the context (or of the root span).
+// The returned context carries the new span, so spans started with it become its children.
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	return start(ctx, name, kindInternal)
+}
+
+func start(ctx context.Context, name string, kind spanKind) (context.Context, *Span) {
+	t := defaultTracer
+	if t == nil {
+		return ctx, nil
+	}
+
+	parent := t.root
+	if ctx == nil {
+		ctx = context.Background()
+	} else if s, ok := ctx.Value(spanKey).(*Span); ok && s != nil {
+		parent = s
+	}
+
+	span := t.newSpan(parent, name, kind)
+	return context.WithValue(ctx, spanKey, span), span
+}
+
+func (t *Tracer) newSpan(parent *Span, name string, kind spanKind) *Span {
+	span := &Span{
+		tracer:     t,
+		name:       name,
+		kind:       kind,
+		spanID:     randomID(8),
+		start:      time.Now(),
+		attributes: make(map[string]interface{}),
+	}
+	if parent != nil {
+		span.traceID = parent.traceID
+		span.parentID = parent.spanID
+	} else {
+		span.traceID = randomID(16)
+	}
+	return span
+}
+
+// spans are exported once a batch is full, so long runs don't keep all of their spans in memory
+const batchSize = 512
+
+func (t *Tracer) finished(span *Span) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.pending = append(t.pending, span)
+	if len(t.pending) < batchSize {
+		return
+	}
+
+	batch := t.pending
+	t.pending = nil
+	t.exports.Add(1)
+	go func() {
+		defer t.exports.Done()
+		_ = t.exporter.export(batch)
+	}()
+}
+
+func randomID(size int) string {
+	id := make([]byte, size)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
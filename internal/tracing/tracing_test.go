@@ -0,0 +1,70 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisabled(t *testing.T) {
+	ctx, span := Start(context.Background(), "noop")
+	require.Nil(t, span)
+	require.NotNil(t, ctx)
+
+	// nil spans are no-ops
+	span.SetAttribute("key", "value")
+	span.End(nil)
+	require.NoError(t, Shutdown(nil))
+}
+
+func TestExport(t *testing.T) {
+	var lock sync.Mutex
+	var received []otlpSpan
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, tracesPath, r.URL.Path)
+		var request otlpRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		lock.Lock()
+		defer lock.Unlock()
+		for _, rs := range request.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				received = append(received, ss.Spans...)
+			}
+		}
+	}))
+	defer server.Close()
+
+	Init(server.URL, "analyze")
+
+	ctx, collect := Start(context.Background(), "collect")
+	collect.SetAttribute("namespace", "repository")
+	_, query := Start(ctx, "query")
+	query.End(errors.New("failed"))
+	collect.End(nil)
+
+	require.NoError(t, Shutdown(nil))
+	require.Len(t, received, 3)
+
+	byName := make(map[string]otlpSpan)
+	for _, span := range received {
+		byName[span.Name] = span
+	}
+
+	root := byName["analyze"]
+	require.Empty(t, root.ParentSpanID)
+	require.Equal(t, root.SpanID, byName["collect"].ParentSpanID)
+	require.Equal(t, byName["collect"].SpanID, byName["query"].ParentSpanID)
+	require.Equal(t, root.TraceID, byName["query"].TraceID)
+
+	require.Equal(t, statusError, byName["query"].Status.Code)
+	require.Equal(t, "failed", byName["query"].Status.Message)
+	require.Equal(t, statusOk, byName["collect"].Status.Code)
+	require.Equal(t, "namespace", byName["collect"].Attributes[0].Key)
+	require.Equal(t, "repository", *byName["collect"].Attributes[0].Value.StringValue)
+}
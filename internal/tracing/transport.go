@@ -0,0 +1,42 @@
+package tracing
+
+import (
+	"fmt"
+	"net/http"
+)
+
+type transport struct {
+	base http.RoundTripper
+	scm  string
+}
+
+// NewTransport traces the API calls of an SCM client.
+// The calls are children of the span of the request context (e.g. the collection of a namespace).
+func NewTransport(base http.RoundTripper, scm string) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{
+		base: base,
+		scm:  scm,
+	}
+}
+
+func (t *transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	_, span := start(request.Context(), fmt.Sprintf("%s %s", request.Method, request.URL.Path), kindClient)
+	span.SetAttribute("scm", t.scm)
+	span.SetAttribute("http.method", request.Method)
+	span.SetAttribute("http.url", request.URL.String())
+
+	resp, err := t.base.RoundTrip(request)
+	if err == nil {
+		span.SetAttribute("http.status_code", resp.StatusCode)
+		if resp.StatusCode >= http.StatusBadRequest {
+			span.End(fmt.Errorf("%s", resp.Status))
+			return resp, err
+		}
+	}
+
+	span.End(err)
+	return resp, err
+}
@@ -0,0 +1,91 @@
+package waivers
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// Property is the repository custom property waivers are read from, formatted as:
+	// "<policy>[,<policy>...]: <justification>", with multiple waivers separated by ";"
+	Property = "legitify-waiver"
+	// TopicPrefix marks repository topics that waive a policy, e.g. legitify-waiver-code-review-not-required
+	// (topics can't contain underscores or free text, so the justification is fixed)
+	TopicPrefix = Property + "-"
+
+	SourceProperty = "custom property " + Property
+	SourceTopic    = "repository topic"
+
+	topicJustification = "waived by a repository topic"
+)
+
+// Waiver is an accepted violation of a policy, with the justification for accepting it
+type Waiver struct {
+	Policy        string `json:"policy"`
+	Justification string `json:"justification"`
+	Source        string `json:"source"`
+}
+
+// Waivable is implemented by the collected entities that carry waivers
+type Waivable interface {
+	Waivers() []Waiver
+}
+
+// ParseProperty parses the waivers of the custom property value
+func ParseProperty(value string) ([]Waiver, error) {
+	var result []Waiver
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.SplitN(entry, ":", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[1]) == "" {
+			return nil, fmt.Errorf("invalid waiver %q, expected \"<policy>: <justification>\"", entry)
+		}
+
+		justification := strings.TrimSpace(parts[1])
+		for _, policy := range strings.Split(parts[0], ",") {
+			if policy = strings.TrimSpace(policy); policy != "" {
+				result = append(result, Waiver{
+					Policy:        policy,
+					Justification: justification,
+					Source:        SourceProperty,
+				})
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// FromTopics returns the waivers of the waiver topics among the given topics
+func FromTopics(topics []string) []Waiver {
+	var result []Waiver
+	for _, topic := range topics {
+		if !strings.HasPrefix(topic, TopicPrefix) || len(topic) == len(TopicPrefix) {
+			continue
+		}
+
+		result = append(result, Waiver{
+			Policy:        strings.ReplaceAll(strings.TrimPrefix(topic, TopicPrefix), "-", "_"),
+			Justification: topicJustification,
+			Source:        fmt.Sprintf("%s %s", SourceTopic, topic),
+		})
+	}
+
+	return result
+}
+
+// Find returns the waiver of the policy, matched by either the policy name (e.g. code_review_not_required)
+// or its namespace-qualified name (e.g. repository.code_review_not_required)
+func Find(waivers []Waiver, policyName string, qualifiedName string) (*Waiver, bool) {
+	for i, waiver := range waivers {
+		if waiver.Policy == policyName || waiver.Policy == qualifiedName {
+			return &waivers[i], true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,45 @@
+package waivers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProperty(t *testing.T) {
+	waivers, err := ParseProperty("code_review_not_required, repository.forking_allowed: accepted by security (TICKET-1); stale_admin_found: break-glass account")
+	require.Nil(t, err)
+	require.Equal(t, []Waiver{
+		{Policy: "code_review_not_required", Justification: "accepted by security (TICKET-1)", Source: SourceProperty},
+		{Policy: "repository.forking_allowed", Justification: "accepted by security (TICKET-1)", Source: SourceProperty},
+		{Policy: "stale_admin_found", Justification: "break-glass account", Source: SourceProperty},
+	}, waivers)
+
+	waivers, err = ParseProperty("")
+	require.Nil(t, err)
+	require.Empty(t, waivers)
+
+	_, err = ParseProperty("code_review_not_required")
+	require.NotNil(t, err, "a waiver must have a justification")
+}
+
+func TestFromTopics(t *testing.T) {
+	waivers := FromTopics([]string{"go", "legitify-waiver-code-review-not-required", "legitify-waiver-"})
+	require.Len(t, waivers, 1)
+	require.Equal(t, "code_review_not_required", waivers[0].Policy)
+	require.Equal(t, "repository topic legitify-waiver-code-review-not-required", waivers[0].Source)
+}
+
+func TestFind(t *testing.T) {
+	waivers := []Waiver{
+		{Policy: "code_review_not_required"},
+		{Policy: "repository.forking_allowed"},
+	}
+
+	_, found := Find(waivers, "code_review_not_required", "repository.code_review_not_required")
+	require.True(t, found)
+	_, found = Find(waivers, "forking_allowed", "repository.forking_allowed")
+	require.True(t, found)
+	_, found = Find(waivers, "forking_allowed", "organization.forking_allowed")
+	require.False(t, found)
+}
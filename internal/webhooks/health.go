@@ -0,0 +1,64 @@
+package webhooks
+
+import "time"
+
+// MaxDeliveries is the number of recent deliveries the health of a webhook is computed by
+const MaxDeliveries = 100
+
+// FailingPercent is the failure percentage from which a webhook is considered failing
+const FailingPercent = 50
+
+// Delivery is a single delivery attempt of a webhook
+type Delivery struct {
+	StatusCode  int
+	DeliveredAt time.Time
+}
+
+// Succeeded is whether the receiver accepted the delivery (a 2xx status code)
+func (d Delivery) Succeeded() bool {
+	return d.StatusCode >= 200 && d.StatusCode < 300
+}
+
+// Health is the delivery health of a webhook, by its recent deliveries
+type Health struct {
+	HookID int64  `json:"hook_id"`
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Active bool   `json:"active"`
+	// Deliveries is the number of recent deliveries (up to MaxDeliveries)
+	Deliveries     int `json:"deliveries"`
+	Failures       int `json:"failures"`
+	FailurePercent int `json:"failure_percent"`
+	// LastStatusCode is the status code of the most recent delivery (0 if it couldn't be delivered, e.g. a timeout)
+	LastStatusCode  int        `json:"last_status_code"`
+	LastDeliveredAt *time.Time `json:"last_delivered_at"`
+	LastSucceededAt *time.Time `json:"last_succeeded_at"`
+	// Failing is whether the most recent delivery failed, and at least FailingPercent of the recent deliveries failed
+	Failing bool `json:"failing"`
+}
+
+// Compute summarizes the recent deliveries (in any order) of the webhook
+func Compute(health Health, deliveries []Delivery) Health {
+	var last *Delivery
+	for i, delivery := range deliveries {
+		delivery := delivery
+		health.Deliveries++
+		if !delivery.Succeeded() {
+			health.Failures++
+		} else if health.LastSucceededAt == nil || delivery.DeliveredAt.After(*health.LastSucceededAt) {
+			health.LastSucceededAt = &delivery.DeliveredAt
+		}
+		if last == nil || delivery.DeliveredAt.After(last.DeliveredAt) {
+			last = &deliveries[i]
+		}
+	}
+
+	if last == nil {
+		return health
+	}
+	health.FailurePercent = health.Failures * 100 / health.Deliveries
+	health.LastStatusCode = last.StatusCode
+	health.LastDeliveredAt = &last.DeliveredAt
+	health.Failing = !last.Succeeded() && health.FailurePercent >= FailingPercent
+	return health
+}
@@ -0,0 +1,43 @@
+package webhooks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompute(t *testing.T) {
+	now := time.Now()
+	health := Compute(Health{HookID: 1}, []Delivery{
+		{StatusCode: 500, DeliveredAt: now},
+		{StatusCode: 0, DeliveredAt: now.Add(-time.Hour)},
+		{StatusCode: 200, DeliveredAt: now.Add(-2 * time.Hour)},
+		{StatusCode: 502, DeliveredAt: now.Add(-3 * time.Hour)},
+	})
+	require.Equal(t, int64(1), health.HookID)
+	require.Equal(t, 4, health.Deliveries)
+	require.Equal(t, 3, health.Failures)
+	require.Equal(t, 75, health.FailurePercent)
+	require.Equal(t, 500, health.LastStatusCode)
+	require.Equal(t, now, *health.LastDeliveredAt)
+	require.Equal(t, now.Add(-2*time.Hour), *health.LastSucceededAt)
+	require.True(t, health.Failing)
+}
+
+func TestComputeRecovered(t *testing.T) {
+	now := time.Now()
+	health := Compute(Health{}, []Delivery{
+		{StatusCode: 500, DeliveredAt: now.Add(-time.Hour)},
+		{StatusCode: 204, DeliveredAt: now},
+	})
+	require.Equal(t, 50, health.FailurePercent)
+	require.False(t, health.Failing)
+}
+
+func TestComputeNoDeliveries(t *testing.T) {
+	health := Compute(Health{}, nil)
+	require.Zero(t, health.Deliveries)
+	require.Nil(t, health.LastDeliveredAt)
+	require.False(t, health.Failing)
+}
@@ -0,0 +1,73 @@
+package webhooks
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-github/v44/github"
+)
+
+// Settings are the security settings of a webhook, parsed from its configuration
+type Settings struct {
+	HookID int64  `json:"hook_id"`
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Active bool   `json:"active"`
+	// Scheme and Host are of the delivery target (the configured payload url)
+	Scheme      string `json:"scheme"`
+	Host        string `json:"host"`
+	InsecureSSL bool   `json:"insecure_ssl"`
+	HasSecret   bool   `json:"has_secret"`
+	// AllowedHost is whether the host is in the allowed domains (nil if no allowed domains are configured)
+	AllowedHost *bool `json:"allowed_host"`
+}
+
+// NormalizeDomains lower cases the domains and drops their leading dots, since the hosts are compared in lower case
+func NormalizeDomains(domains []string) []string {
+	var result []string
+	for _, domain := range domains {
+		result = append(result, strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), "."))
+	}
+	return result
+}
+
+// AllowedHost returns whether the host is one of the domains or a subdomain of one of them
+func AllowedHost(domains []string, host string) bool {
+	host = strings.ToLower(host)
+	for _, domain := range domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewSettings parses the webhook configuration, checking its delivery target against the allowed domains (if any)
+func NewSettings(hook *github.Hook, allowedDomains []string) Settings {
+	settings := Settings{
+		HookID: hook.GetID(),
+		Name:   hook.GetName(),
+		URL:    hook.GetURL(),
+		Active: hook.GetActive(),
+	}
+
+	if value, ok := hook.Config["insecure_ssl"]; ok {
+		settings.InsecureSSL = fmt.Sprint(value) == "1"
+	}
+	if _, ok := hook.Config["secret"]; ok {
+		settings.HasSecret = true
+	}
+	if target, ok := hook.Config["url"].(string); ok {
+		if parsed, err := url.Parse(target); err == nil {
+			settings.Scheme = strings.ToLower(parsed.Scheme)
+			settings.Host = strings.ToLower(parsed.Hostname())
+		}
+	}
+	if len(allowedDomains) > 0 && settings.Host != "" {
+		allowed := AllowedHost(allowedDomains, settings.Host)
+		settings.AllowedHost = &allowed
+	}
+
+	return settings
+}
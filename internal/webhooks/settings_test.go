@@ -0,0 +1,44 @@
+package webhooks
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v44/github"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSettings(t *testing.T) {
+	hook := &github.Hook{
+		ID:     github.Int64(1),
+		Name:   github.String("web"),
+		Active: github.Bool(true),
+		Config: map[string]interface{}{
+			"url":          "http://SIEM.example.com:8080/github",
+			"insecure_ssl": "1",
+			"secret":       "********",
+		},
+	}
+
+	settings := NewSettings(hook, nil)
+	require.Equal(t, int64(1), settings.HookID)
+	require.True(t, settings.Active)
+	require.Equal(t, "http", settings.Scheme)
+	require.Equal(t, "siem.example.com", settings.Host)
+	require.True(t, settings.InsecureSSL)
+	require.True(t, settings.HasSecret)
+	require.Nil(t, settings.AllowedHost)
+
+	settings = NewSettings(hook, NormalizeDomains([]string{".Example.com"}))
+	require.True(t, *settings.AllowedHost)
+
+	settings = NewSettings(hook, NormalizeDomains([]string{"other.com", "ample.com"}))
+	require.False(t, *settings.AllowedHost)
+}
+
+func TestNewSettingsDefaults(t *testing.T) {
+	settings := NewSettings(&github.Hook{Config: map[string]interface{}{"insecure_ssl": "0"}}, []string{"example.com"})
+	require.False(t, settings.InsecureSSL)
+	require.False(t, settings.HasSecret)
+	require.Empty(t, settings.Host)
+	require.Nil(t, settings.AllowedHost)
+}
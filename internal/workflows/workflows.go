@@ -0,0 +1,236 @@
+package workflows
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Directory is where the repository workflows are stored
+const Directory = ".github/workflows"
+
+// DefaultTrustedPublishers are the owners of the actions that don't have to be pinned to a commit SHA by default
+var DefaultTrustedPublishers = []string{"actions", "github"}
+
+// IsWorkflowFile returns whether the file (in the workflows directory) is a workflow
+func IsWorkflowFile(name string) bool {
+	return strings.HasSuffix(name, ".yml") || strings.HasSuffix(name, ".yaml")
+}
+
+// NormalizePublishers lower cases the publishers, since the owners of the references are compared in lower case
+func NormalizePublishers(publishers []string) []string {
+	result := []string{}
+	for _, publisher := range publishers {
+		if publisher = strings.ToLower(strings.TrimSpace(publisher)); publisher != "" {
+			result = append(result, publisher)
+		}
+	}
+	return result
+}
+
+// Workflow is the structured subset of a GitHub Actions workflow file that the policies use
+type Workflow struct {
+	Path     string   `json:"path"`
+	Name     string   `json:"name,omitempty"`
+	Triggers []string `json:"triggers"`
+	Jobs     []Job    `json:"jobs"`
+}
+
+type Job struct {
+	ID string `json:"id"`
+	// Uses is set for jobs that call a reusable workflow
+	Uses  *Reference `json:"uses,omitempty"`
+	Steps []Step     `json:"steps"`
+	// Secrets are the names of the secrets the job passes to all of its steps (in its env) or to the reusable workflow it calls
+	Secrets []string `json:"secrets"`
+	// InheritsSecrets is set when the job passes all the secrets to the reusable workflow it calls
+	InheritsSecrets bool `json:"inherits_secrets"`
+}
+
+type Step struct {
+	Name string            `json:"name,omitempty"`
+	Uses *Reference        `json:"uses,omitempty"`
+	With map[string]string `json:"with,omitempty"`
+	Run  string            `json:"run,omitempty"`
+	// Secrets are the names of the secrets the step uses (in its inputs, env or script)
+	Secrets []string `json:"secrets"`
+}
+
+// Reference is an action (or reusable workflow) reference: {owner}/{repo}[/{path}]@{ref}, ./{local path} or docker://{image}
+type Reference struct {
+	Raw   string `json:"raw"`
+	Owner string `json:"owner,omitempty"`
+	Repo  string `json:"repo,omitempty"`
+	Path  string `json:"path,omitempty"`
+	Ref   string `json:"ref,omitempty"`
+	// Local references point to the same repository (./path)
+	Local  bool `json:"local"`
+	Docker bool `json:"docker"`
+	// PinnedToSHA is set when the ref is a full-length commit SHA (and is immutable)
+	PinnedToSHA bool `json:"pinned_to_sha"`
+}
+
+var (
+	fullSHA         = regexp.MustCompile(`^[0-9a-fA-F]{40}$`)
+	secretReference = regexp.MustCompile(`secrets\.([A-Za-z0-9_-]+)|secrets\[\s*'([^']+)'\s*\]`)
+)
+
+// ParseReference parses the value of a 'uses' key
+func ParseReference(uses string) *Reference {
+	uses = strings.TrimSpace(uses)
+	ref := &Reference{Raw: uses}
+
+	switch {
+	case strings.HasPrefix(uses, "./"):
+		ref.Local = true
+		ref.Path = uses
+		return ref
+	case strings.HasPrefix(uses, "docker://"):
+		ref.Docker = true
+		image := strings.TrimPrefix(uses, "docker://")
+		ref.PinnedToSHA = strings.Contains(image, "@sha256:")
+		return ref
+	}
+
+	name, version, _ := strings.Cut(uses, "@")
+	ref.Ref = version
+	ref.PinnedToSHA = fullSHA.MatchString(version)
+
+	parts := strings.SplitN(name, "/", 3)
+	ref.Owner = strings.ToLower(parts[0])
+	if len(parts) > 1 {
+		ref.Repo = parts[1]
+	}
+	if len(parts) > 2 {
+		ref.Path = parts[2]
+	}
+
+	return ref
+}
+
+// SecretsOf returns the (sorted and unique) names of the secrets referenced by the expressions in the given texts
+func SecretsOf(texts ...string) []string {
+	names := make(map[string]bool)
+	for _, text := range texts {
+		for _, match := range secretReference.FindAllStringSubmatch(text, -1) {
+			name := match[1]
+			if name == "" {
+				name = match[2]
+			}
+			names[name] = true
+		}
+	}
+
+	result := []string{}
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+type rawStep struct {
+	Name string            `yaml:"name"`
+	Uses string            `yaml:"uses"`
+	With map[string]string `yaml:"with"`
+	Env  map[string]string `yaml:"env"`
+	Run  string            `yaml:"run"`
+}
+
+type rawJob struct {
+	Uses    string            `yaml:"uses"`
+	Steps   []rawStep         `yaml:"steps"`
+	Env     map[string]string `yaml:"env"`
+	Secrets yaml.Node         `yaml:"secrets"`
+}
+
+type rawWorkflow struct {
+	Name string            `yaml:"name"`
+	On   yaml.Node         `yaml:"on"`
+	Jobs map[string]rawJob `yaml:"jobs"`
+}
+
+// Parse parses the content of a workflow file
+func Parse(path string, content []byte) (*Workflow, error) {
+	var raw rawWorkflow
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow %s: %v", path, err)
+	}
+
+	workflow := &Workflow{
+		Path:     path,
+		Name:     raw.Name,
+		Triggers: triggers(&raw.On),
+		Jobs:     []Job{},
+	}
+
+	ids := make([]string, 0, len(raw.Jobs))
+	for id := range raw.Jobs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		rj := raw.Jobs[id]
+		job := Job{ID: id, Steps: []Step{}}
+		if rj.Uses != "" {
+			job.Uses = ParseReference(rj.Uses)
+		}
+
+		texts := values(rj.Env)
+		switch rj.Secrets.Kind {
+		case yaml.ScalarNode:
+			job.InheritsSecrets = rj.Secrets.Value == "inherit"
+		case yaml.MappingNode:
+			for i := 1; i < len(rj.Secrets.Content); i += 2 {
+				texts = append(texts, rj.Secrets.Content[i].Value)
+			}
+		}
+		job.Secrets = SecretsOf(texts...)
+
+		for _, rs := range rj.Steps {
+			step := Step{
+				Name:    rs.Name,
+				With:    rs.With,
+				Run:     rs.Run,
+				Secrets: SecretsOf(append(append(values(rs.With), values(rs.Env)...), rs.Run)...),
+			}
+			if rs.Uses != "" {
+				step.Uses = ParseReference(rs.Uses)
+			}
+			job.Steps = append(job.Steps, step)
+		}
+		workflow.Jobs = append(workflow.Jobs, job)
+	}
+
+	return workflow, nil
+}
+
+// triggers returns the events of the 'on' key, which is either an event, a list of events or a map of events to their filters
+func triggers(on *yaml.Node) []string {
+	result := []string{}
+	switch on.Kind {
+	case yaml.ScalarNode:
+		result = append(result, on.Value)
+	case yaml.SequenceNode:
+		for _, n := range on.Content {
+			result = append(result, n.Value)
+		}
+	case yaml.MappingNode:
+		for i := 0; i < len(on.Content); i += 2 {
+			result = append(result, on.Content[i].Value)
+		}
+	}
+	return result
+}
+
+func values(m map[string]string) []string {
+	result := make([]string, 0, len(m))
+	for _, value := range m {
+		result = append(result, value)
+	}
+	return result
+}
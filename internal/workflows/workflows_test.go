@@ -0,0 +1,109 @@
+package workflows
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReference(t *testing.T) {
+	ref := ParseReference("Some-Owner/some-action/sub/path@v1")
+	require.Equal(t, "some-owner", ref.Owner)
+	require.Equal(t, "some-action", ref.Repo)
+	require.Equal(t, "sub/path", ref.Path)
+	require.Equal(t, "v1", ref.Ref)
+	require.False(t, ref.PinnedToSHA)
+
+	ref = ParseReference("owner/action@8f4b7f84864484a7bf31766abe9204da3cbe65b3")
+	require.True(t, ref.PinnedToSHA)
+
+	// a short SHA is not immutable
+	ref = ParseReference("owner/action@8f4b7f8")
+	require.False(t, ref.PinnedToSHA)
+
+	ref = ParseReference("./.github/actions/build")
+	require.True(t, ref.Local)
+
+	ref = ParseReference("docker://alpine@sha256:bc41182d7ef5ffc53a40b044e725193bc10142a1243f395ee852a8d9730fc2ad")
+	require.True(t, ref.Docker)
+	require.True(t, ref.PinnedToSHA)
+}
+
+func TestParse(t *testing.T) {
+	content := `
+name: CI
+on:
+  push:
+    branches: [main]
+  pull_request_target:
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v3
+      - name: Test
+        run: make test
+  release:
+    uses: owner/workflows/.github/workflows/release.yml@main
+`
+	workflow, err := Parse(".github/workflows/ci.yml", []byte(content))
+	require.Nil(t, err)
+	require.Equal(t, "CI", workflow.Name)
+	require.Equal(t, []string{"push", "pull_request_target"}, workflow.Triggers)
+	require.Len(t, workflow.Jobs, 2)
+
+	release := workflow.Jobs[0]
+	require.Equal(t, "release", release.ID)
+	require.Equal(t, "owner", release.Uses.Owner)
+	require.Equal(t, "main", release.Uses.Ref)
+
+	test := workflow.Jobs[1]
+	require.Len(t, test.Steps, 2)
+	require.Equal(t, "actions", test.Steps[0].Uses.Owner)
+	require.Nil(t, test.Steps[1].Uses)
+	require.Equal(t, "make test", test.Steps[1].Run)
+
+	workflow, err = Parse("single.yml", []byte("on: [push, workflow_dispatch]\njobs: {}\n"))
+	require.Nil(t, err)
+	require.Equal(t, []string{"push", "workflow_dispatch"}, workflow.Triggers)
+
+	_, err = Parse("invalid.yml", []byte("jobs: [\n"))
+	require.NotNil(t, err)
+}
+
+func TestSecrets(t *testing.T) {
+	require.Equal(t, []string{"A", "B_2", "c-d"}, SecretsOf("${{ secrets.B_2 }}", "echo ${{ secrets.A }} ${{ secrets['c-d'] }}", "${{ secrets.A }}"))
+	require.Equal(t, []string{}, SecretsOf("${{ github.token }}"))
+
+	content := `
+on: pull_request_target
+jobs:
+  call:
+    uses: owner/workflows/.github/workflows/ci.yml@v1
+    secrets:
+      token: ${{ secrets.DEPLOY_TOKEN }}
+  inherit:
+    uses: owner/workflows/.github/workflows/ci.yml@v1
+    secrets: inherit
+  build:
+    env:
+      KEY: ${{ secrets.KEY }}
+    steps:
+      - uses: actions/checkout@v3
+        with:
+          ref: ${{ github.event.pull_request.head.sha }}
+          fetch-depth: 0
+      - run: ./deploy.sh
+        env:
+          TOKEN: ${{ secrets.TOKEN }}
+`
+	workflow, err := Parse("ci.yml", []byte(content))
+	require.Nil(t, err)
+
+	build, call, inherit := workflow.Jobs[0], workflow.Jobs[1], workflow.Jobs[2]
+	require.Equal(t, []string{"KEY"}, build.Secrets)
+	require.Equal(t, "0", build.Steps[0].With["fetch-depth"])
+	require.Equal(t, []string{"TOKEN"}, build.Steps[1].Secrets)
+	require.Equal(t, []string{"DEPLOY_TOKEN"}, call.Secrets)
+	require.True(t, inherit.InheritsSecrets)
+}
@@ -50,6 +50,21 @@ func (mr *MockEnginerMockRecorder) Annotations() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Annotations", reflect.TypeOf((*MockEnginer)(nil).Annotations))
 }
 
+// Explain mocks base method.
+func (m *MockEnginer) Explain(ctx context.Context, namespace, policyName string, input interface{}) (*opa_engine.Explanation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Explain", ctx, namespace, policyName, input)
+	ret0, _ := ret[0].(*opa_engine.Explanation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Explain indicates an expected call of Explain.
+func (mr *MockEnginerMockRecorder) Explain(ctx, namespace, policyName, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Explain", reflect.TypeOf((*MockEnginer)(nil).Explain), ctx, namespace, policyName, input)
+}
+
 // Modules mocks base method.
 func (m *MockEnginer) Modules() map[string]*ast.Module {
 	m.ctrl.T.Helper()
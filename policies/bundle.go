@@ -9,3 +9,6 @@ var GitHubBundle embed.FS
 
 //go:embed gitlab/*
 var GitLabBundle embed.FS
+
+//go:embed codecommit/*
+var CodeCommitBundle embed.FS
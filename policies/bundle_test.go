@@ -46,5 +46,19 @@ func TestPoliciesBundle(t *testing.T) {
 	count, err := countBundles()
 
 	require.Nilf(t, err, "counting files: %v", err)
-	require.Equal(t, count, 6, "Expecting 6 files in bundle")
+	require.Equal(t, count, 7, "Expecting 7 files in bundle")
+}
+
+func TestDigest(t *testing.T) {
+	github, err := Digest(GitHubBundle)
+	require.NoError(t, err)
+	require.Regexp(t, "^sha256:[0-9a-f]{64}$", github)
+
+	again, err := Digest(GitHubBundle)
+	require.NoError(t, err)
+	require.Equal(t, github, again)
+
+	gitlab, err := Digest(GitLabBundle)
+	require.NoError(t, err)
+	require.NotEqual(t, github, gitlab)
 }
@@ -0,0 +1,44 @@
+package repository
+
+# METADATA
+# scope: rule
+# title: Repository Has No Approval Rule
+# description: No approval rule template that requires at least one approval is associated with the repository for pull requests into its default branch, so code can be merged without being reviewed by anyone other than its author. Note that CodeCommit doesn't prevent pushing to the default branch directly; restrict git pushes to it with an IAM policy (denying codecommit:GitPush on refs/heads/<default branch>). The repository's approval rule templates are available to custom policies as input.approval_rules.
+# custom:
+#   severity: MEDIUM
+#   tags: [code-review]
+#   remediationSteps:
+#     - Go to the CodeCommit console
+#     - Press Approval rule templates -> Create template
+#     - Set the number of approvals needed (at least 1) and the approval pool members
+#     - Set the default branch as a destination branch (or leave the destination branches empty)
+#     - Associate the template with the repository
+#   threat:
+#     - A developer merges malicious code into the default branch, which is then deployed to production without anyone else seeing it.
+default repository_has_no_approval_rule = false
+repository_has_no_approval_rule {
+    is_array(input.approval_rules)
+    count([rule | rule := input.approval_rules[_]; rule.applies_to_default_branch; rule.required_approvals > 0]) == 0
+}
+
+# METADATA
+# scope: rule
+# title: Repository Trigger Delivers To Another Account
+# description: A trigger of the repository notifies an SNS topic or invokes a Lambda function of a different AWS account. Trigger notifications include the repository, the branch and the commits of every push, and the destination's owner may also invoke the automation the trigger was set up for. Verify the destination account is trusted.
+# custom:
+#   severity: LOW
+#   tags: [data-exposure]
+#   remediationSteps:
+#     - Go to the CodeCommit console
+#     - Open the repository and press Settings -> Triggers
+#     - Verify the reported trigger's destination account is trusted, or delete the trigger
+#   threat:
+#     - A trigger left over from a vendor integration keeps sending the company's push events (branch names and commit messages) to the vendor's account after the engagement ended.
+trigger_destination_in_other_account[violated] = true {
+    trigger := input.triggers[_]
+    trigger.cross_account
+    violated := {
+        "trigger": trigger.name,
+        "destination": trigger.destination_arn
+    }
+}
@@ -0,0 +1,31 @@
+package policies
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"io/fs"
+)
+
+// Digest is a checksum of the policy files of the bundle, to verify which policies a binary (or image) was built with
+func Digest(bundle embed.FS) (string, error) {
+	hash := sha256.New()
+	err := fs.WalkDir(bundle, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := bundle.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		// WalkDir visits the files in lexical order, so the digest is stable
+		hash.Write([]byte(p))
+		hash.Write(data)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
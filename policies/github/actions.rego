@@ -1,5 +1,7 @@
 package actions
 
+import data.common.oidc as oidcUtils
+
 # METADATA
 # scope: rule
 # title: GitHub Actions Is Not Restricted To Selected Repositories
@@ -8,6 +10,7 @@ package actions
 #   requiredEnrichers: [organizationId]
 #   remediationSteps: [Make sure you have admin permissions, Go to the org's settings page, Enter the "Actions - General" tab, Under "Policies", Change "All repositories" to "Selected repositories" and select repositories that should be able to run actions, Click "Save"]
 #   severity: MEDIUM
+#   tags: [actions, supply-chain]
 #   requiredScopes: [admin:org]
 #   threat:
 #     - "This misconfiguration could lead to the following attack:"
@@ -30,6 +33,7 @@ all_repositories_can_run_github_actions {
 #   requiredEnrichers: [organizationId]
 #   remediationSteps: [Make sure you have admin permissions, Go to the org's settings page, Enter "Actions - General" tab, Under "Policies", 'Select "Allow enterprise, and select non-enterprise, actions and reusable workflows"', Check "Allow actions created by GitHub" and "Allow actions by Marketplace verified creators", Set any other used trusted actions under "Allow specified actions and reusable workflows", Click "Save"]
 #   severity: MEDIUM
+#   tags: [actions, supply-chain]
 #   requiredScopes: [admin:org]
 #   threat:
 #     - "This misconfiguration could lead to the following attack:"
@@ -55,6 +59,7 @@ all_github_actions_are_allowed {
 #     - Select 'Read repository contents permission'
 #     - Click 'Save'
 #   severity: MEDIUM
+#   tags: [actions, supply-chain, least-privilege]
 #   requiredScopes: [admin:org]
 #   threat: In case of token compromise (due to a vulnerability or malicious third-party GitHub actions), an attacker can use this token to sabotage various assets in your CI/CD pipeline, such as packages, pull-requests, deployments, and more.
 default token_default_permissions_is_read_write  = false
@@ -76,9 +81,256 @@ token_default_permissions_is_read_write {
 #     - Uncheck 'Allow GitHub actions to create and approve pull requests.
 #     - Click 'Save'
 #   severity: HIGH
+#   tags: [actions, supply-chain, code-review]
 #   requiredScopes: [admin:org]
 #   threat: Attackers can exploit this misconfiguration to bypass code-review restrictions by creating a workflow that approves their own pull request and then merging the pull request without anyone noticing, introducing malicious code that would go straight ahead to production.
 default actions_can_approve_pull_requests  = false
 actions_can_approve_pull_requests {
     input.token_permissions.can_approve_pull_request_reviews
-}
\ No newline at end of file
+}
+
+# METADATA
+# scope: rule
+# title: Actions OIDC Subject Claim Is Not Scoped To A Repository
+# description: The organization's customized OIDC subject ('sub') claim template doesn't include the repository (e.g. only 'repository_owner' or 'environment'). Cloud providers federate with GitHub Actions by matching the subject claim, so every repository in the organization gets the same subject, and can assume the cloud roles that were meant for a single repository.
+# custom:
+#   prerequisites: [oidc_subject_claim]
+#   requiredEnrichers: [organizationId]
+#   remediationSteps:
+#     - Make sure you have admin permissions
+#     - Use the REST API to update the organization's subject claim template ("PUT /orgs/{org}/actions/oidc/customization/sub")
+#     - Include the "repo" (or "repository_id") claim in "include_claim_keys"
+#     - Update the trust policies of your cloud roles to match the new subject
+#   severity: HIGH
+#   tags: [actions, supply-chain, least-privilege]
+#   requiredScopes: [admin:org]
+#   threat:
+#     - "1. A cloud role trusts the subject claim of the organization's deployment repository"
+#     - "2. An attacker with write access to any other repository in the organization creates a workflow that requests an OIDC token"
+#     - "3. The token has the same subject claim, so the attacker assumes the cloud role and gains access to the production environment"
+default oidc_subject_claim_not_scoped_to_repository = false
+oidc_subject_claim_not_scoped_to_repository {
+    oidcUtils.customized(input.oidc_subject_claim)
+    not oidcUtils.scoped_to_repository(input.oidc_subject_claim)
+}
+
+# METADATA
+# scope: rule
+# title: Actions OIDC Subject Claim Is Not Scoped To A Branch, Environment Or Workflow
+# description: The organization's customized OIDC subject ('sub') claim template doesn't include the context of the job (the branch, the environment or the workflow). Cloud roles that trust this subject can be assumed by any workflow, from any branch, of the matching repositories - including unreviewed code pushed to a feature branch.
+# custom:
+#   prerequisites: [oidc_subject_claim]
+#   requiredEnrichers: [organizationId]
+#   remediationSteps:
+#     - Make sure you have admin permissions
+#     - Use the REST API to update the organization's subject claim template ("PUT /orgs/{org}/actions/oidc/customization/sub")
+#     - Include the "context" claim (or "ref", "environment" or "job_workflow_ref") in "include_claim_keys"
+#     - Update the trust policies of your cloud roles to match the new subject
+#   severity: MEDIUM
+#   tags: [actions, supply-chain, least-privilege]
+#   requiredScopes: [admin:org]
+#   threat: An attacker that can push a branch to a repository can run a workflow from it that assumes the repository's cloud roles, bypassing the code review and environment protection rules that guard the deployments.
+default oidc_subject_claim_not_scoped_to_context = false
+oidc_subject_claim_not_scoped_to_context {
+    oidcUtils.customized(input.oidc_subject_claim)
+    not oidcUtils.scoped_to_context(input.oidc_subject_claim)
+}
+
+# METADATA
+# scope: rule
+# title: GitHub Actions Allow-List Permits Any Third-Party Action
+# description: The organization restricts GitHub Actions to selected actions, but the allow-list includes a pattern with a wildcard owner (e.g. "*/*"). Such a pattern permits any third-party action, which defeats the purpose of the allow-list and exposes your pipelines to unaudited and potentially malicious actions.
+# custom:
+#   requiredEnrichers: [organizationId]
+#   remediationSteps:
+#     - Make sure you have admin permissions
+#     - Go to the org's settings page
+#     - Enter "Actions - General" tab
+#     - Under "Policies", remove the wildcard owner patterns from "Allow specified actions and reusable workflows"
+#     - List the trusted actions explicitly (e.g. "owner/action@ref" or "owner/*")
+#     - Click "Save"
+#   severity: MEDIUM
+#   tags: [actions, supply-chain]
+#   requiredScopes: [admin:org]
+#   threat:
+#     - "1. Attacker creates a repository with a tempting but malicious custom GitHub Action"
+#     - "2. The action matches the wildcard pattern, so the allow-list doesn't stop an innocent developer from using it"
+#     - "3. The malicious action has access to the developer repository and could steal its secrets or modify its content"
+selected_actions_allow_any_third_party_action[violated] = true {
+    input.actions_permissions.allowed_actions == "selected"
+    pattern := input.selected_actions.patterns_allowed[_]
+    wildcard_owner(pattern)
+    violated := {
+        "name": pattern,
+    }
+}
+
+# METADATA
+# scope: rule
+# title: Third-Party Actions Are Not Required To Be Pinned To A Commit SHA
+# description: The organization allows third-party GitHub Actions (all actions, Marketplace verified creators, or a wildcard allow-list) but doesn't require actions to be pinned to a full-length commit SHA. Tags and branches of third-party actions are mutable, so a compromised or malicious action owner can change the code that runs in your workflows without any change on your side.
+# custom:
+#   requiredEnrichers: [organizationId]
+#   remediationSteps:
+#     - Make sure you have admin permissions
+#     - Go to the org's settings page
+#     - Enter "Actions - General" tab
+#     - Under "Policies", check "Require actions to be pinned to a full-length commit SHA"
+#     - Click "Save"
+#   severity: MEDIUM
+#   tags: [actions, supply-chain]
+#   requiredScopes: [admin:org]
+#   threat:
+#     - "1. A workflow uses a third-party action by a tag (e.g. 'some-owner/some-action@v1')"
+#     - "2. The action owner's account is compromised, and the attacker moves the tag to a malicious commit"
+#     - "3. The next workflow run executes the malicious code with access to the repository secrets and token"
+default third_party_actions_not_pinned = false
+third_party_actions_not_pinned {
+    third_party_actions_allowed(input.actions_permissions, input.selected_actions)
+    not input.actions_permissions.sha_pinning_required
+}
+
+third_party_actions_allowed(permissions, selected) {
+    permissions.allowed_actions == "all"
+}
+
+third_party_actions_allowed(permissions, selected) {
+    permissions.allowed_actions == "selected"
+    selected.verified_allowed
+}
+
+third_party_actions_allowed(permissions, selected) {
+    permissions.allowed_actions == "selected"
+    wildcard_owner(selected.patterns_allowed[_])
+}
+
+wildcard_owner(pattern) {
+    owner := split(pattern, "/")[0]
+    contains(owner, "*")
+}
+
+# METADATA
+# scope: rule
+# title: Organization Actions Secret Was Not Rotated Recently
+# description: An organization GitHub Actions secret wasn't updated for longer than the configured medium severity maximal age (180 days by default, see the --secret-max-age option). Long-lived secrets are more likely to have leaked (e.g. through workflow logs, former employees or compromised third-party actions), and the longer they are valid, the longer an attacker can use them.
+# custom:
+#   requiredEnrichers: [organizationId]
+#   remediationSteps:
+#     - Make sure you have admin permissions
+#     - Rotate the credential in the service it belongs to
+#     - Go to the org's settings page
+#     - Enter "Secrets and variables - Actions" tab
+#     - Update the secret with the new credential
+#     - Revoke the old credential
+#   severity: MEDIUM
+#   tags: [actions, secrets]
+#   requiredScopes: [admin:org]
+#   threat: A leaked secret that is never rotated gives an attacker a long-lasting access to the service it belongs to, even after the leak source was fixed.
+organization_secret_not_rotated[violated] = true {
+    secret := input.secrets[_]
+    secret.age_days > input.secret_max_age_days.medium
+    not secret.age_days > input.secret_max_age_days.high
+    violated := {
+        "name": secret.name,
+        "updated_at": object.get(secret, "updated_at", null),
+        "age_days": secret.age_days,
+    }
+}
+
+# METADATA
+# scope: rule
+# title: Organization Actions Secret Was Not Rotated For A Long Time
+# description: An organization GitHub Actions secret wasn't updated for longer than the configured high severity maximal age (365 days by default, see the --secret-max-age option). Long-lived secrets are more likely to have leaked (e.g. through workflow logs, former employees or compromised third-party actions), and the longer they are valid, the longer an attacker can use them.
+# custom:
+#   requiredEnrichers: [organizationId]
+#   remediationSteps:
+#     - Make sure you have admin permissions
+#     - Rotate the credential in the service it belongs to
+#     - Go to the org's settings page
+#     - Enter "Secrets and variables - Actions" tab
+#     - Update the secret with the new credential
+#     - Revoke the old credential
+#   severity: HIGH
+#   tags: [actions, secrets]
+#   requiredScopes: [admin:org]
+#   threat: A leaked secret that is never rotated gives an attacker a long-lasting access to the service it belongs to, even after the leak source was fixed.
+organization_secret_not_rotated_for_long[violated] = true {
+    secret := input.secrets[_]
+    secret.age_days > input.secret_max_age_days.high
+    violated := {
+        "name": secret.name,
+        "updated_at": object.get(secret, "updated_at", null),
+        "age_days": secret.age_days,
+    }
+}
+
+# METADATA
+# scope: rule
+# title: Required Workflow Is Not Enforced On All Repositories
+# description: A workflow that the organization requires (a required workflow, or a workflows rule of an organization ruleset) is not enforced on all the organization repositories - it's limited to selected repositories, excludes some of them, or its ruleset is not active. Mandatory security workflows (e.g. code scanning or secrets detection) only protect the repositories they are enforced on.
+# custom:
+#   requiredEnrichers: [organizationId]
+#   remediationSteps:
+#     - Make sure you have admin permissions
+#     - Go to the org's settings page
+#     - Enter "Repository - Rulesets" tab
+#     - Open the ruleset that requires the workflow (or create one with a "Require workflows to pass before merging" rule)
+#     - Set "Enforcement status" to "Active"
+#     - Under "Target repositories", select "All repositories" and remove the exclusions
+#     - Click "Save changes"
+#   severity: MEDIUM
+#   tags: [actions, supply-chain]
+#   requiredScopes: [admin:org]
+#   threat: Repositories that are not covered by the mandatory workflow can merge code that never went through the organization's security checks (e.g. code scanning, secrets detection or dependency review).
+required_workflow_not_enforced_on_all_repositories[violated] = true {
+    workflow := input.required_workflows[_]
+    workflow.scope != "all"
+    violated := {
+        "name": workflow.name,
+        "path": workflow.path,
+    }
+}
+
+required_workflow_not_enforced_on_all_repositories[violated] = true {
+    ruleset := input.workflow_rulesets[_]
+    not ruleset_enforced_on_all_repositories(ruleset)
+    violated := {
+        "name": ruleset.name,
+        "enforcement": ruleset.enforcement,
+    }
+}
+
+ruleset_enforced_on_all_repositories(ruleset) {
+    ruleset.enforcement == "active"
+    ruleset.conditions.repository_name.include[_] == "~ALL"
+    not has_exclusions(ruleset.conditions.repository_name)
+}
+
+has_exclusions(condition) {
+    _ = condition.exclude[_]
+}
+
+# METADATA
+# scope: rule
+# title: Fork Pull Request Workflows Of Outside Contributors Run Without Approval
+# description: The organization requires an approval to run workflows only for the pull requests of first-time contributors, so any outside contributor that has already contributed (e.g. a merged typo fix) can run workflows on the organization's public repositories without a maintainer's approval. Workflows that run on fork pull requests can consume the self-hosted runners and Actions minutes, and if they are triggered by pull_request_target or workflow_run - access the repository secrets.
+# custom:
+#   prerequisites: [fork_pr_contributor_approval]
+#   requiredEnrichers: [organizationId]
+#   remediationSteps:
+#     - Make sure you have admin permissions
+#     - Go to the org's settings page
+#     - Enter "Actions - General" tab
+#     - Under "Approval for running fork pull request workflows from contributors", select "Require approval for all external contributors"
+#     - Click "Save"
+#   severity: MEDIUM
+#   tags: [actions, supply-chain]
+#   requiredScopes: [admin:org]
+#   threat:
+#     - "1. An attacker gets a harmless pull request merged into a public repository of the organization"
+#     - "2. The attacker opens another pull request from a fork that modifies a workflow, or abuses a workflow that checks out the pull request code"
+#     - "3. The workflow runs without approval, letting the attacker run code on the organization's runners and potentially exfiltrate secrets"
+default fork_pull_request_workflows_run_without_approval = false
+fork_pull_request_workflows_run_without_approval {
+    input.fork_pr_approval.approval_policy != "all_external_contributors"
+}
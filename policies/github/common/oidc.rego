@@ -0,0 +1,20 @@
+package common.oidc
+
+# claims that tell apart the repository the token was issued to
+repository_claims := {"repo", "repository", "repository_id"}
+
+# claims that tell apart the branch, environment or workflow the token was issued to
+context_claims := {"context", "ref", "environment", "job_workflow_ref", "workflow_ref"}
+
+customized(claim) {
+    not claim.use_default == true
+    _ = claim.include_claim_keys[_]
+}
+
+scoped_to_repository(claim) {
+    repository_claims[claim.include_claim_keys[_]]
+}
+
+scoped_to_context(claim) {
+    context_claims[claim.include_claim_keys[_]]
+}
@@ -7,6 +7,7 @@ package member
 # custom:
 #   remediationSteps: [Make sure you have admin permissions, Go to the organization People page, Select the unwanted owners, Using the "X members selected" - change role to member]
 #   severity: MEDIUM
+#   tags: [access-control, least-privilege]
 #   requiredScopes: [admin:org]
 #   threat:
 #     - "1. An organization has a permissive attitude and provides an owner role to all developers."
@@ -26,6 +27,7 @@ organization_has_too_many_admins {
 #   requiredEnrichers: [entityId, violatedUsers]
 #   remediationSteps: [Make sure you have admin permissions, Go to the org's People page, Select all stale members, Using the "X members selected" - remove members from organization]
 #   severity: LOW
+#   tags: [access-control]
 #   requiredScopes: [admin:org]
 #   prerequisites: [premium]
 #   threat:
@@ -34,6 +36,7 @@ stale_member_found[mem] = true {
     some member
     mem := input.members[member]
     mem.is_admin == false
+    mem.last_active > 0
     isStale(mem.last_active, 6)
 }
 
@@ -46,6 +49,7 @@ stale_member_found[mem] = true {
 #   requiredEnrichers: [entityId, violatedUsers]
 #   remediationSteps: [Make sure you have admin permissions, Go to the org's People page, Select all stale admins, Using the "X members selected" - remove members from organization]
 #   severity: MEDIUM
+#   tags: [access-control, least-privilege]
 #   requiredScopes: [admin:org]
 #   prerequisites: [premium]
 #   threat:
@@ -54,9 +58,113 @@ stale_admin_found[mem] = true {
     some member
     mem := input.members[member]
     mem.is_admin == true
+    mem.last_active > 0
     isStale(mem.last_active, 6)
 }
 
+# METADATA
+# scope: rule
+# title: Dormant Member Found
+# description: A member has no activity at all (web or git) in the organization audit log of the collected period (see --audit-log-activity-days). Unlike the stale member check, the whole period of the audit log is read, so members without any recorded activity are detected as well. Dormant accounts are rarely monitored by their owners, so their compromise may go unnoticed. Consider removing the member's access.
+# custom:
+#   requiredEnrichers: [entityId, violatedUsers]
+#   remediationSteps: [Make sure you have admin permissions, Verify the member no longer needs access, Go to the org's People page, Select the dormant members, Using the "X members selected" - remove members from organization]
+#   severity: LOW
+#   tags: [access-control, offboarding]
+#   requiredScopes: [admin:org]
+#   prerequisites: [premium]
+#   threat:
+#     - "The account of a member who no longer uses it is compromised (e.g. by a reused password), and the attacker's access to the organization goes unnoticed."
+dormant_member_found[mem] = true {
+    input.activity_window_days > 0
+    some member
+    mem := input.members[member]
+    mem.is_admin == false
+    mem.audit_log_last_active == 0
+}
+
+# METADATA
+# scope: rule
+# title: Dormant Organization Admin Found
+# description: An organization owner has no activity in the configured number of days (see --dormant-admin-days, 90 by default). Owner accounts that aren't used are prime takeover targets, since they have full control over the organization and their compromise is unlikely to be noticed by their owners. The activity is taken from the audit log for enterprise organizations, and from the admin's recent events otherwise (which only cover the last 90 days, so such results are of lower confidence). The admins' activity is available to custom policies as the activity of the members.
+# custom:
+#   requiredEnrichers: [entityId, violatedUsers]
+#   remediationSteps: [Make sure you have admin permissions, Verify the admin still needs the owner role, Go to the org's People page, Select the dormant admins, Using the "X members selected" - change role to member or remove members from organization]
+#   severity: HIGH
+#   tags: [access-control, least-privilege]
+#   requiredScopes: [admin:org]
+#   threat:
+#     - "An attacker takes over the unused account of an organization owner (e.g. with a leaked password), and uses it to add backdoor collaborators and disable the organization's security settings."
+dormant_admin_found[mem] = true {
+    some member
+    mem := input.members[member]
+    mem.is_admin == true
+    (time.now_ns() - mem.activity.last_active) / (24 * 60 * 60 * 1000000000) >= input.dormant_admin_days
+}
+
+# The events API only covers the last 90 days, and only includes the events visible to the token
+policy_confidence["dormant_admin_found"] = {
+    "level": "medium",
+    "reason": "partial data: the admins' activity was taken from their recent events rather than the audit log"
+} {
+    input.members[_].activity.source == "events"
+}
+
+# METADATA
+# scope: rule
+# title: Member Not Found In Identity Source
+# description: An organization member is not present in the provided identity source (HR/IdP export). This usually means the user has left the company or was never provisioned through the identity provider, yet still has access to the organization. Consider removing the user's access.
+# custom:
+#   requiredEnrichers: [entityId, violatedUsers]
+#   remediationSteps: [Make sure you have admin permissions, Verify the member is not an active employee or contractor, Go to the org's People page, Select the members not found in the identity source, Using the "X members selected" - remove members from organization]
+#   severity: HIGH
+#   tags: [access-control, offboarding]
+#   requiredScopes: [read:org]
+#   prerequisites: [identity_source]
+#   threat:
+#     - "Leavers that keep their access can still read (and possibly modify) the organization's code, whether intentionally or because their unmanaged accounts were compromised."
+member_not_in_identity_source[mem] = true {
+    some member
+    mem := input.members[member]
+    mem.in_identity_source == false
+}
+
+# METADATA
+# scope: rule
+# title: Organization Has Unused Licensed Seats
+# description: The organization pays for more seats than it uses. Consider reducing the number of licensed seats to the actual need.
+# custom:
+#   requiredEnrichers: [seatsReport]
+#   remediationSteps: [Make sure you have owner or billing manager permissions, Go to the organization settings page, Enter "Billing and plans", Under "Current plan", Click "Edit" and remove the unused seats]
+#   severity: LOW
+#   tags: [cost, hygiene]
+#   requiredScopes: [admin:org]
+#   threat:
+#     - "Unused seats are a cost without any benefit, and usually indicate that the organization's membership isn't reviewed."
+default unused_seats_found = false
+unused_seats_found {
+    input.organization.plan.seats > input.organization.plan.filled_seats
+}
+
+# METADATA
+# scope: rule
+# title: Stale Pending Invitation Found
+# description: An invitation to the organization has been pending for over a month. Pending invitations occupy licensed seats and may be accepted by an unintended account long after they were sent. Consider canceling the invitation.
+# custom:
+#   requiredEnrichers: [seatsReport]
+#   remediationSteps: [Make sure you have admin permissions, Go to the org's People page, Enter the "Invitations" tab, Cancel the stale invitations]
+#   severity: LOW
+#   tags: [cost, hygiene, access-control]
+#   requiredScopes: [admin:org]
+#   threat:
+#     - "A forgotten invitation can be accepted by its recipient after they no longer need access to the organization."
+default stale_invitation_found = false
+stale_invitation_found {
+    some index
+    invitation := input.pending_invitations[index]
+    isStale(invitation.created_at, 1)
+}
+
 isStale(target_last_active, count_months) {
     now := time.now_ns()
     diff := time.diff(now, target_last_active)
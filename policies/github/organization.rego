@@ -8,6 +8,7 @@ import data.common.webhooks as webhookUtils
 # custom:
 #   requiredEnrichers: [hooksList]
 #   severity: LOW
+#   tags: [webhooks]
 #   remediationSteps: [Make sure you have admin permissions, Go to the organization settings page, Select "Webhooks", Press on the insecure webhook, Configure a secret , Click "Update webhook"]
 #   requiredScopes: [admin:org_hook]
 organization_webhook_no_secret[violated] = true {
@@ -27,6 +28,7 @@ organization_webhook_no_secret[violated] = true {
 # custom:
 #   requiredEnrichers: [hooksList]
 #   severity: LOW
+#   tags: [webhooks]
 #   remediationSteps: [Make sure you have admin permissions, Go to the organization settings page, Select "Webhooks", Press on the insecure webhook, Verify url starts with https, Enable "SSL verification" , Click "Update webhook"]
 #   requiredScopes: [admin:org_hook]
 organization_webhook_doesnt_require_ssl[violated] = true {
@@ -39,12 +41,83 @@ organization_webhook_doesnt_require_ssl[violated] = true {
     }
 }
 
+# METADATA
+# scope: rule
+# title: Webhook Delivers Over Plain HTTP
+# description: The webhook delivers its payloads to an http:// url. The payloads (e.g. code changes and member events) and the signature header are sent unencrypted, so anyone on the network path can read or tamper with them.
+# custom:
+#   requiredEnrichers: [hooksList]
+#   prerequisites: [webhook_deliveries]
+#   severity: MEDIUM
+#   tags: [webhooks]
+#   remediationSteps: [Make sure you have admin permissions, Go to the organization settings page, Select "Webhooks", Press on the webhook, Change the payload url to an https:// url, Enable "SSL verification", Click "Update webhook"]
+#   requiredScopes: [admin:org_hook]
+#   threat:
+#     - "An attacker on the network path reads the webhook payloads, or forges deliveries to the receiver."
+organization_webhook_delivers_over_http[violated] = true {
+    hook := input.hooks_settings[_]
+    hook.scheme == "http"
+    violated := {
+        "name": hook.name,
+        "url": hook.url,
+        "host": hook.host
+    }
+}
+
+# METADATA
+# scope: rule
+# title: Webhook Delivers To An Unrecognized Domain
+# description: The webhook delivers its payloads to a host outside the allowed domains (configured with --webhook-allowed-domains). Webhook payloads contain the organization's activity and sometimes code, and a webhook to an unknown host may be used to exfiltrate them.
+# custom:
+#   requiredEnrichers: [hooksList]
+#   severity: MEDIUM
+#   tags: [webhooks, data-exposure]
+#   remediationSteps: [Make sure you have admin permissions, Go to the organization settings page, Select "Webhooks", Press on the webhook, Verify the receiver of the webhook is expected, Remove the webhook if it isn't, Otherwise, add its domain to the allowed domains]
+#   requiredScopes: [admin:org_hook]
+#   threat:
+#     - "An attacker with admin permissions adds a webhook that sends every push to a server they control, and keeps receiving the code after their access is revoked."
+organization_webhook_to_unrecognized_domain[violated] = true {
+    hook := input.hooks_settings[_]
+    hook.allowed_host == false
+    violated := {
+        "name": hook.name,
+        "url": hook.url,
+        "host": hook.host
+    }
+}
+
+# METADATA
+# scope: rule
+# title: Webhook Deliveries Are Failing
+# description: Most of the recent deliveries of an active webhook failed, including the last one. Webhooks often carry security-relevant events to other systems (e.g. a SIEM or an audit pipeline), and a webhook that silently stopped delivering leaves those systems blind to the organization's activity.
+# custom:
+#   requiredEnrichers: [hooksList]
+#   prerequisites: [webhook_deliveries]
+#   severity: MEDIUM
+#   tags: [webhooks]
+#   remediationSteps: [Make sure you have admin permissions, Go to the organization settings page, Select "Webhooks", Press on the failing webhook, Review the "Recent Deliveries" responses, Fix the receiver or the webhook url, Redeliver the failed deliveries, Remove the webhook if it's no longer used]
+#   requiredScopes: [admin:org_hook]
+#   threat:
+#     - "An attacker's activity in the organization goes unnoticed, since the webhook that should have sent the audit events to the SIEM stopped delivering them."
+organization_webhook_deliveries_failing[violated] = true {
+    health := input.hooks_health[_]
+    health.active
+    health.failing
+    violated := {
+        "name": health.name,
+        "url": health.url,
+        "failure_percent": sprintf("%d%%", [health.failure_percent]),
+        "last_status_code": sprintf("%d", [health.last_status_code])
+    }
+}
+
 # METADATA
 # scope: rule
 # title: Two-Factor Authentication Is Not Enforced For The Organization
 # description: The two-factor authentication requirement is not enabled at the organization level. Regardless of whether users are managed externally by SSO, it is highly recommended to enable this option, to reduce the risk of a deliberate or accidental user creation without MFA.
 # custom:
 #   severity: HIGH
+#   tags: [authentication, access-control]
 #   remediationSteps: [Make sure you have admin permissions, Go to the organization settings page, Enter "Authentication security" tab, Under "Two-factor authentication", Toggle on "Require two-factor authentication for everyone in the <ORG> organization", Click "Save"]
 #   requiredScopes: [admin:org]
 #   threat:
@@ -60,6 +133,7 @@ two_factor_authentication_not_required_for_org {
 # description: An organization allows non-admin members to create public repositories. Creating a public repository can be done by mistake, and may expose sensitive organization code, which, once exposed, may be copied, cached or stored by external parties. Therefore, it is highly recommended to restrict the option to create public repositories to admins only and reduce the risk of unintentional code exposure.
 # custom:
 #   severity: MEDIUM
+#   tags: [access-control, data-exposure]
 #   remediationSteps: [Make sure you have admin permissions, Go to the organization settings page, Enter "Member privileges" tab, Under "Repository creation", Toggle off "Public", Click "Save"]
 #   requiredScopes: [read:org]
 #   threat:
@@ -75,6 +149,7 @@ non_admins_can_create_public_repositories {
 # description: Default repository permissions configuration is not set in the organization, thus every new repository will be accessible by default to all users. It is strongly recommended to remove the default permissions and assign them on demand.
 # custom:
 #   severity: HIGH
+#   tags: [access-control, least-privilege]
 #   remediationSteps: [Make sure you have admin permissions, Go to the organization settings page, Enter "Member privileges" tab, Under "Base permissions", Set permissions to "No permissions", Click "Save"]
 #   requiredScopes: [read:enterprise]
 #   threat:
@@ -88,6 +163,7 @@ default_repository_permission_is_not_none {
 # scope: rule
 # custom:
 #   severity: MEDIUM
+#   tags: [authentication, access-control]
 # title: Organization Not Using Single-Sign-On
 # description: It is recommended to enable access to an organization via SAML single sign-on (SSO) by authenticating through an identity provider (IdP).
 # custom:
@@ -97,3 +173,365 @@ default organization_not_using_single_sign_on = false
 organization_not_using_single_sign_on {
     input.saml_enabled == false
 }
+
+# METADATA
+# scope: rule
+# title: Organization Members Without Linked SSO Identities
+# description: SAML single sign-on is configured for the organization, but some members haven't linked an SSO identity. GitHub doesn't expose whether SSO is enforced, but enforcing it removes the members without a linked identity, so they indicate that SSO isn't enforced and that these members can access the organization's resources without authenticating through the identity provider (and aren't deprovisioned with it). The organization's SSO configuration and linked member counts are available to custom policies as input.saml_sso.
+# custom:
+#   prerequisites: [premium, saml_sso_identities]
+#   severity: MEDIUM
+#   tags: [authentication, access-control]
+#   remediationSteps: [Make sure you have owner permissions, Ask the reported members to authenticate through the identity provider and link their identities, Go to the organization settings page, Enter "Authentication security" tab, Check "Require SAML SSO authentication for all members of the <ORG> organization", Click "Save"]
+#   requiredScopes: [admin:org]
+#   threat:
+#     - "A member who left the company and was deprovisioned in the identity provider keeps accessing the organization's repositories with their GitHub account."
+members_without_linked_sso_identity[violated] = true {
+    input.saml_sso.configured
+    login := input.saml_sso.unlinked_logins[_]
+    violated := {
+        "login": login
+    }
+}
+
+# METADATA
+# scope: rule
+# title: OAuth App Access To The Organization Is Not Restricted
+# description: The organization's third-party application access policy is disabled, so any OAuth App a member authorizes can access the organization's resources (including private repositories) with the member's permissions, without an owner's approval. The policy isn't exposed by the API and is read from the audit log, which is only available for enterprise organizations, so the policy is skipped for other organizations; if the policy wasn't changed within the audit log retention it's unknown, and the result is of low confidence. The approved and denied applications are available to custom policies as input.oauth_app_restrictions.
+# custom:
+#   severity: MEDIUM
+#   tags: [access-control, supply-chain]
+#   remediationSteps: [Make sure you have owner permissions, Go to the organization settings page, Select "Third-party access" and then "OAuth application policy", Click "Setup application access restrictions", Review the applications that already have access and approve only the needed ones]
+#   requiredScopes: [admin:org]
+#   prerequisites: [premium]
+#   threat:
+#     - "A member is phished into authorizing a malicious OAuth App, which then reads and modifies the organization's private repositories on their behalf."
+default oauth_app_access_not_restricted = false
+oauth_app_access_not_restricted {
+    input.oauth_app_restrictions.enabled == false
+}
+
+policy_confidence["oauth_app_access_not_restricted"] = {
+    "level": "low",
+    "reason": "unknown: the OAuth app access policy wasn't found in the audit log (it wasn't changed within the retention, or the audit log couldn't be read)"
+} {
+    not is_oauth_app_policy_known(input)
+}
+
+is_oauth_app_policy_known(org) {
+    is_boolean(org.oauth_app_restrictions.enabled)
+}
+
+# the fine-grained repository permissions that are normally reserved to admins, since they disable or bypass the repository's protections
+is_admin_equivalent_permission(permission) {
+    admin_equivalent := {
+        "bypass_branch_protection",
+        "edit_repo_protections",
+        "manage_deploy_keys",
+        "manage_webhooks",
+        "delete_alerts_code_scanning",
+        "resolve_secret_scanning_alerts"
+    }
+    admin_equivalent[permission]
+}
+
+# METADATA
+# scope: rule
+# title: Custom Repository Role Grants Admin-Equivalent Permissions
+# description: A custom repository role whose name doesn't mention admin grants permissions normally reserved to repository admins, such as bypassing or editing the branch protection, managing deploy keys and webhooks, or dismissing security alerts. Users and teams assigned the innocuously named role can disable the repository's protections, without being counted (or reviewed) as admins. The organization's custom repository roles are available to custom policies as input.custom_repository_roles.
+# custom:
+#   prerequisites: [custom_repository_roles]
+#   severity: MEDIUM
+#   tags: [access-control, least-privilege]
+#   remediationSteps: [Make sure you have owner permissions, Go to the organization settings page, Select "Repository roles", Edit the role, Remove the admin-equivalent permissions or rename the role to reflect them, Review the users and teams assigned the role]
+#   requiredScopes: [admin:org]
+#   threat:
+#     - "A contractor assigned a 'triage-plus' role that includes bypassing the branch protection pushes unreviewed code to the default branch."
+custom_role_grants_admin_equivalent_permissions[violated] = true {
+    role := input.custom_repository_roles[_]
+    not contains(lower(role.name), "admin")
+    permission := role.permissions[_]
+    is_admin_equivalent_permission(permission)
+    violated := {
+        "role": role.name,
+        "permission": permission
+    }
+}
+
+# METADATA
+# scope: rule
+# title: Repository Was Recently Transferred Into The Organization
+# description: A repository was transferred into the organization in the last 90 days. A transferred repository keeps its code, workflows, webhooks and deploy keys, which were not reviewed by the organization. Review the repository settings and contents, and make sure the transfer was expected.
+# custom:
+#   requiredEnrichers: [assetMovement]
+#   severity: LOW
+#   tags: [asset-movement, supply-chain]
+#   remediationSteps: [Make sure you have owner permissions, Go to the transferred repository settings page, Review its collaborators, webhooks, deploy keys and actions settings, Review the repository workflows and code for unexpected content]
+#   requiredScopes: [admin:org]
+#   threat:
+#     - "An attacker that controls a repository in another organization can transfer it in, introducing unreviewed workflows and integrations into the organization."
+repository_transferred_in[violated] = true {
+    some index
+    transfer := input.repository_transfers[index]
+    transfer.direction == "incoming"
+    violated := {
+        "repository": transfer.repository,
+        "actor": transfer.actor,
+        "date": transfer.created_at
+    }
+}
+
+# METADATA
+# scope: rule
+# title: Repository Was Recently Transferred Out Of The Organization
+# description: A repository was transferred out of the organization in the last 90 days. Once transferred, the organization loses control over the repository code and settings. Make sure the transfer was approved.
+# custom:
+#   requiredEnrichers: [assetMovement]
+#   severity: MEDIUM
+#   tags: [asset-movement, data-exposure]
+#   remediationSteps: [Make sure you have owner permissions, Verify the transfer with its actor, If the transfer was not approved contact the new owner to transfer the repository back, Go to the organization settings page, Enter "Member privileges", Under "Repository transfers" disable member transfers]
+#   requiredScopes: [admin:org]
+#   threat:
+#     - "A malicious insider (or a compromised account) can transfer a private repository to an organization they control, exfiltrating its code."
+repository_transferred_out[violated] = true {
+    some index
+    transfer := input.repository_transfers[index]
+    transfer.direction == "outgoing"
+    violated := {
+        "repository": transfer.repository,
+        "actor": transfer.actor,
+        "date": transfer.created_at
+    }
+}
+
+# METADATA
+# scope: rule
+# title: GitHub Advanced Security Is Not Enabled For All Private Repositories
+# description: Some of the organization's private repositories don't have GitHub Advanced Security enabled, so their code and secrets are not covered by code scanning, secret scanning and dependency review. The organization's advanced security coverage (per visibility and feature) is available to custom policies as input.ghas_coverage.
+# custom:
+#   prerequisites: [premium]
+#   severity: MEDIUM
+#   tags: [ghas]
+#   remediationSteps: [Make sure you have owner permissions, Go to the organization settings page, Enter "Code security and analysis" tab, Next to "GitHub Advanced Security" click "Enable all", "Optionally, check \"Automatically enable for new private repositories\""]
+#   requiredScopes: [repo, read:org]
+#   threat:
+#     - "Vulnerabilities and secrets in the uncovered private repositories go undetected, and can be exploited by anyone who gains access to them."
+private_repositories_without_advanced_security[violated] = true {
+    some index
+    repository := input.ghas_coverage["private"].advanced_security.missing[index]
+    violated := {
+        "repository": repository
+    }
+}
+
+# METADATA
+# scope: rule
+# title: GitHub App Has Write Access To All Repositories
+# description: A GitHub App is installed on all the organization's repositories (including future ones) with write access to their administration or contents. Anyone who compromises the app (e.g. its private key or its vendor) can push code to, or change the settings and branch protection of, every repository. Suspended installations are not reported.
+# custom:
+#   severity: HIGH
+#   tags: [access-control, least-privilege, supply-chain]
+#   remediationSteps: [Make sure you have owner permissions, Go to the organization settings page, Select "GitHub Apps", Press "Configure" next to the app, Under "Repository access", select "Only select repositories" and choose the repositories the app needs, Uninstall the app if it isn't needed]
+#   requiredScopes: [admin:org]
+#   threat:
+#     - "An attacker who compromises a vendor's GitHub App pushes malicious code to all the repositories the app is installed on."
+app_has_write_access_to_all_repositories[violated] = true {
+    installation := input.app_installations[_]
+    installation.repository_selection == "all"
+    not installation.suspended_at
+    # the repository permissions that give the app control over all the organization's code
+    permission := ["administration", "contents"][_]
+    installation.permissions[permission] == "write"
+    violated := {
+        "app": installation.app_slug,
+        "permission": sprintf("%s:write", [permission])
+    }
+}
+
+# METADATA
+# scope: rule
+# title: High-Sensitivity Organization Does Not Restrict Access By IP
+# description: The organization was classified as high-sensitivity (with --high-sensitivity-orgs), but its IP allow list is not enabled, so its resources can be accessed (with valid credentials) from any network. Restricting access to the organization's networks limits the use of stolen credentials and tokens. The organization's IP allow list is available to custom policies as input.ip_allow_list.
+# custom:
+#   prerequisites: [premium, ip_allow_list]
+#   severity: MEDIUM
+#   tags: [access-control]
+#   remediationSteps: [Make sure you have owner permissions, Go to the organization settings page, Enter "Authentication security" tab, Under "IP allow list", add the IP ranges of the organization's networks (and CI systems), Enable the IP allow list, "Optionally, check \"Enable IP allow list configuration for installed GitHub Apps\""]
+#   requiredScopes: [admin:org]
+#   threat:
+#     - "An attacker who steals a member's personal access token uses it from their own network to clone the organization's sensitive repositories."
+default ip_allow_list_not_enabled = false
+ip_allow_list_not_enabled {
+    input.high_sensitivity
+    input.ip_allow_list.enabled == false
+}
+
+# METADATA
+# scope: rule
+# title: Organization Has No Verified Domains
+# description: The organization hasn't verified any domain. Verifying the organization's domains confirms its identity (with a "Verified" badge) and allows restricting the email notifications about the organization's repositories to addresses in the verified domains, so they don't leak to personal email addresses. The verified domains are also the basis for trusting the email addresses of the identity provider in the SAML configuration. The organization's domains are available to custom policies as input.domains.
+# custom:
+#   prerequisites: [premium]
+#   severity: LOW
+#   tags: [authentication, data-exposure]
+#   remediationSteps: [Make sure you have owner permissions, Go to the organization settings page, Select "Verified and approved domains", Click "Add a domain" and follow the instructions to verify it, "Optionally, enable \"Restrict email notifications to only approved or verified domains\""]
+#   requiredScopes: [admin:org]
+#   threat:
+#     - "Email notifications about the organization's private repositories (including security alerts) are sent to members' personal email addresses, outside of the organization's control."
+#     - "Users can't tell the organization apart from an impersonating organization with a similar name."
+default no_verified_domains = false
+no_verified_domains {
+    not is_null(input.domains)
+    count([domain | domain := input.domains[_]; domain.verified]) == 0
+}
+
+# METADATA
+# scope: rule
+# title: Organization Has No Security Managers
+# description: No team is assigned the security manager role of the organization. Security managers can view and manage the security alerts and settings of all the organization's repositories, without being organization owners. Without them, the security alerts are only visible to the owners and repository admins, so they are likely to be left unattended. The teams with the security manager role are available to custom policies as input.security_manager_teams.
+# custom:
+#   prerequisites: [security_managers]
+#   severity: LOW
+#   tags: [vulnerability-management]
+#   remediationSteps: [Make sure you have owner permissions, Go to the organization settings page, Select "Roles" (or "Security managers" on older versions), Assign the security manager role to the team responsible for the organization's security]
+#   requiredScopes: [admin:org]
+#   threat:
+#     - "Security alerts of critical vulnerabilities and leaked secrets go unnoticed, since no one other than the owners and the repository admins can see them."
+default no_security_managers = false
+no_security_managers {
+    not is_null(input.security_manager_teams)
+    count(input.security_manager_teams) == 0
+}
+
+# METADATA
+# scope: rule
+# title: Security Manager Team Includes Outside Collaborators
+# description: A team that is assigned the security manager role of the organization includes users that aren't members of the organization. Security managers can read the security alerts (including leaked secrets) of all the organization's repositories and change their security settings, so the role should only be granted to trusted members of the organization.
+# custom:
+#   prerequisites: [security_managers]
+#   severity: HIGH
+#   tags: [access-control, least-privilege, vulnerability-management]
+#   remediationSteps: [Make sure you have owner permissions, Go to the organization settings page, Select "Teams" and open the reported team, Remove the outside collaborators from the team (or assign the security manager role to a different team)]
+#   requiredScopes: [admin:org]
+#   threat:
+#     - "A contractor who is a member of the security manager team reads the leaked secrets reported by secret scanning in all the organization's repositories, and uses them to access production systems."
+security_manager_team_has_outside_collaborators[violated] = true {
+    team := input.security_manager_teams[_]
+    collaborator := team.outside_collaborators[_]
+    violated := {
+        "team": team.name,
+        "collaborator": collaborator
+    }
+}
+
+# METADATA
+# scope: rule
+# title: Codespaces Secret Is Available To All Repositories
+# description: An organization codespaces secret is available to the codespaces of all the organization's repositories, including public ones. Any member who can create a codespace for one of the repositories can read the secret from within the codespace, so codespaces secrets should only be available to the repositories that need them. The organization's codespaces secrets are available to custom policies as input.codespaces.secrets.
+# custom:
+#   severity: MEDIUM
+#   tags: [least-privilege, data-exposure]
+#   remediationSteps: [Make sure you have owner permissions, Go to the organization settings page, Under "Secrets and variables", select "Codespaces", Press on the reported secret, Under "Repository access", select "Selected repositories" and choose the repositories that need the secret]
+#   prerequisites: [codespaces]
+#   requiredScopes: [admin:org]
+#   threat:
+#     - "A member creates a codespace for an unimportant repository and reads the organization's deployment credentials from its environment variables."
+codespaces_secret_available_to_all_repositories[violated] = true {
+    secret := input.codespaces.secrets[_]
+    secret.visibility == "all"
+    violated := {
+        "secret": secret.name
+    }
+}
+
+# METADATA
+# scope: rule
+# title: High-Sensitivity Organization Allows Creating Codespaces
+# description: The organization was classified as high-sensitivity (with --high-sensitivity-orgs), but codespaces were created for its repositories. Codespaces copy the repository's code (and the codespaces secrets) to cloud machines that can forward their ports publicly, so creating codespaces for sensitive repositories should be disabled or limited to selected members. The codespaces access and port forwarding policies aren't exposed by the API, so this is inferred from the existing codespaces - review the policies manually. The codespaces of the organization's repositories are available to custom policies as input.codespaces.codespaces.
+# custom:
+#   severity: MEDIUM
+#   tags: [access-control, data-exposure]
+#   remediationSteps: [Make sure you have owner permissions, Go to the organization settings page, Under "Codespaces", select "General", Under "Codespaces access", select "Disabled" (or "Enable for specific members or teams" and choose them), Under "Codespaces" select "Policies" and add a "Port forwarding" constraint that doesn't allow public ports]
+#   prerequisites: [codespaces]
+#   requiredScopes: [admin:org]
+#   threat:
+#     - "A member runs the sensitive service in a codespace and forwards its port publicly, exposing the service and its data to anyone with the link."
+codespaces_created_in_high_sensitivity_organization[violated] = true {
+    input.high_sensitivity
+    codespace := input.codespaces.codespaces[_]
+    violated := {
+        "repository": codespace.repository,
+        "owner": codespace.owner
+    }
+}
+
+policy_confidence["codespaces_created_in_high_sensitivity_organization"] = {
+    "level": "medium",
+    "reason": "partial data: the codespaces access policy isn't exposed by the API, so it was inferred from the existing codespaces"
+} {
+    input.high_sensitivity
+}
+
+# METADATA
+# scope: rule
+# title: Public Package Is Linked To A Private Repository
+# description: A public package published under the organization is linked to a private (or internal) repository. The package is built from code that isn't meant to be public, and anyone can download it and inspect its content (e.g. source files, configuration and embedded credentials). The organization's packages are available to custom policies as input.packages.
+# custom:
+#   severity: HIGH
+#   tags: [data-exposure]
+#   remediationSteps: [Make sure you have admin permissions on the package, Go to the organization's "Packages" page and open the reported package, Select "Package settings", Under "Danger Zone", press "Change visibility" and select "Private" (or "Internal")]
+#   requiredScopes: [read:packages]
+#   threat:
+#     - "An attacker pulls a public container image that was built from a private repository and extracts the internal service's source code and credentials from its layers."
+public_package_linked_to_private_repository[violated] = true {
+    pkg := input.packages[_]
+    pkg.visibility == "public"
+    pkg.repository_private
+    violated := {
+        "package": pkg.name,
+        "type": pkg.type,
+        "repository": pkg.repository
+    }
+}
+
+# METADATA
+# scope: rule
+# title: Package Is Not Linked To A Repository
+# description: A package published under the organization isn't linked to a repository. A linked package is owned by the repository's maintainers and can inherit its access permissions, while an unlinked package has no clear owner, its access is managed separately, and its provenance can't be traced to source code. The organization's packages are available to custom policies as input.packages.
+# custom:
+#   severity: LOW
+#   tags: [supply-chain, access-control]
+#   remediationSteps: [Make sure you have admin permissions on the package, Go to the organization's "Packages" page and open the reported package, Press "Connect Repository" and choose the repository the package is built from (for container images, add the "org.opencontainers.image.source" label when building them), Delete the package if it isn't needed]
+#   requiredScopes: [read:packages]
+#   threat:
+#     - "A package that was published manually by a former member is still consumed by the organization's services, and no one maintains it or notices when it's replaced by a malicious version."
+package_not_linked_to_repository[violated] = true {
+    pkg := input.packages[_]
+    pkg.repository == ""
+    violated := {
+        "package": pkg.name,
+        "type": pkg.type
+    }
+}
+
+# METADATA
+# scope: rule
+# title: Private Organization Has Public Projects
+# description: The organization has no public repositories, but some of its project boards are public, so anyone can read their items (which may include internal roadmaps, customer names and unfixed security issues). Projects of private organizations should be private as well. The organization's projects (and classic projects) are available to custom policies as input.projects.
+# custom:
+#   severity: MEDIUM
+#   tags: [data-exposure]
+#   remediationSteps: [Make sure you have owner permissions (or admin permissions on the project), Go to the organization's "Projects" page and open the reported project, Select "Settings", Under "Danger zone", change the visibility to "Private"]
+#   requiredScopes: [read:project]
+#   threat:
+#     - "An attacker browses the public security backlog of the organization and exploits the reported vulnerabilities before they're fixed."
+public_project_in_private_organization[violated] = true {
+    input.organization.public_repos == 0
+    project := input.projects[_]
+    project.public
+    violated := {
+        "project": project.title,
+        "number": project.number,
+        "classic": project.classic
+    }
+}
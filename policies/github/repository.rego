@@ -1,5 +1,6 @@
 package repository
 import data.common.webhooks as webhookUtils
+import data.common.oidc as oidcUtils
 
 # METADATA
 # scope: rule
@@ -8,6 +9,7 @@ import data.common.webhooks as webhookUtils
 # custom:
 #   remediationSteps: [Make sure you have admin permissions, Either Delete or Archive the repository]
 #   severity: HIGH
+#   tags: [maintenance]
 #   requiredScopes: [repo]
 default repository_not_maintained = false
 repository_not_maintained {
@@ -28,6 +30,7 @@ repository_not_maintained {
 # description: Repository are admins highly privileged and could create great damage if being compromised, it's recommeneded to limit them to the minimum required (recommended maximum 3 admins).
 # custom:
 #   severity: LOW
+#   tags: [access-control, least-privilege]
 #   remediationSteps: [Make sure you have admin permissions, Go to the repository settings page, Press "Collaborators and teams", Select the unwanted admin users, Select "Change Role"]
 #   requiredScopes: [read:org,repo]
 default repository_has_too_many_admins  = false
@@ -43,6 +46,7 @@ repository_has_too_many_admins {
 # custom:
 #   requiredEnrichers: [hooksList]
 #   severity: LOW
+#   tags: [webhooks]
 #   remediationSteps: [Make sure you can manage webhooks for the repository, Go to the repository settings page, Select "Webhooks", Press on the insecure webhook, Confiure a secret , Click "Update webhook"]
 #   requiredScopes: [read:repo_hook, repo]
 repository_webhook_no_secret[violated] = true {
@@ -55,6 +59,76 @@ repository_webhook_no_secret[violated] = true {
     }
 }
 
+# METADATA
+# scope: rule
+# title: Webhook Delivers Over Plain HTTP
+# description: The webhook delivers its payloads to an http:// url. The payloads (e.g. code changes and member events) and the signature header are sent unencrypted, so anyone on the network path can read or tamper with them.
+# custom:
+#   requiredEnrichers: [hooksList]
+#   prerequisites: [webhook_deliveries]
+#   severity: MEDIUM
+#   tags: [webhooks]
+#   remediationSteps: [Make sure you can manage webhooks for the repository, Go to the repository settings page, Select "Webhooks", Press on the webhook, Change the payload url to an https:// url, Enable "SSL verification", Click "Update webhook"]
+#   requiredScopes: [read:repo_hook, repo]
+#   threat:
+#     - "An attacker on the network path reads the webhook payloads, or forges deliveries to the receiver."
+repository_webhook_delivers_over_http[violated] = true {
+    hook := input.hooks_settings[_]
+    hook.scheme == "http"
+    violated := {
+        "name": hook.name,
+        "url": hook.url,
+        "host": hook.host
+    }
+}
+
+# METADATA
+# scope: rule
+# title: Webhook Delivers To An Unrecognized Domain
+# description: The webhook delivers its payloads to a host outside the allowed domains (configured with --webhook-allowed-domains). Webhook payloads contain the organization's activity and sometimes code, and a webhook to an unknown host may be used to exfiltrate them.
+# custom:
+#   requiredEnrichers: [hooksList]
+#   severity: MEDIUM
+#   tags: [webhooks, data-exposure]
+#   remediationSteps: [Make sure you can manage webhooks for the repository, Go to the repository settings page, Select "Webhooks", Press on the webhook, Verify the receiver of the webhook is expected, Remove the webhook if it isn't, Otherwise, add its domain to the allowed domains]
+#   requiredScopes: [read:repo_hook, repo]
+#   threat:
+#     - "An attacker with admin permissions adds a webhook that sends every push to a server they control, and keeps receiving the code after their access is revoked."
+repository_webhook_to_unrecognized_domain[violated] = true {
+    hook := input.hooks_settings[_]
+    hook.allowed_host == false
+    violated := {
+        "name": hook.name,
+        "url": hook.url,
+        "host": hook.host
+    }
+}
+
+# METADATA
+# scope: rule
+# title: Webhook Deliveries Are Failing
+# description: Most of the recent deliveries of an active webhook failed, including the last one. Webhooks often carry security-relevant events to other systems (e.g. a SIEM or a deployment pipeline), and a webhook that silently stopped delivering leaves those systems blind to the repository's activity.
+# custom:
+#   requiredEnrichers: [hooksList]
+#   prerequisites: [webhook_deliveries]
+#   severity: MEDIUM
+#   tags: [webhooks]
+#   remediationSteps: [Make sure you can manage webhooks for the repository, Go to the repository settings page, Select "Webhooks", Press on the failing webhook, Review the "Recent Deliveries" responses, Fix the receiver or the webhook url, Redeliver the failed deliveries, Remove the webhook if it's no longer used]
+#   requiredScopes: [read:repo_hook, repo]
+#   threat:
+#     - "Pushes to the repository go unnoticed, since the webhook that should have sent them to the monitoring system stopped delivering them."
+repository_webhook_deliveries_failing[violated] = true {
+    health := input.hooks_health[_]
+    health.active
+    health.failing
+    violated := {
+        "name": health.name,
+        "url": health.url,
+        "failure_percent": sprintf("%d%%", [health.failure_percent]),
+        "last_status_code": sprintf("%d", [health.last_status_code])
+    }
+}
+
 # METADATA
 # scope: rule
 # title: Webhook Configured Without SSL
@@ -62,6 +136,7 @@ repository_webhook_no_secret[violated] = true {
 # custom:
 #   requiredEnrichers: [hooksList]
 #   severity: LOW
+#   tags: [webhooks]
 #   remediationSteps: [Make sure you can manage webhooks for the repository, Go to the repository settings page, Select "Webhooks", Verify url starts with https, Press on the insecure webhook, Enable "SSL verfication", Click "Update webhook"]
 #   requiredScopes: [read:repo_hook, repo]
 repository_webhook_doesnt_require_ssl[violated] = true {
@@ -81,6 +156,7 @@ repository_webhook_doesnt_require_ssl[violated] = true {
 # custom:
 #   remediationSteps: [Make sure you have admin permissions, Go to the repo's settings page, Enter "General" tab, Under "Features", Toggle off "Allow forking"]
 #   severity: LOW
+#   tags: [data-exposure]
 #   requiredScopes: [read:org]
 default allow_forking_enabled = false
 allow_forking_enabled {
@@ -101,18 +177,47 @@ has_branch_protection_info(_input) {
     not is_null(_input.repository.default_branch) # protect against empty repos 
 }
 
+# the active ruleset rules of the default branch are equivalent to its branch protection
+has_ruleset_rules(_input) {
+    _input.default_branch_rules[_]
+}
+
+has_ruleset_rule(_input, rule_type) {
+    _input.default_branch_rules[_].type == rule_type
+}
+
+ruleset_requires_pull_request_option(_input, option) {
+    rule := _input.default_branch_rules[_]
+    rule.type == "pull_request"
+    rule.parameters[option] == true
+}
+
+ruleset_requires_approvals(_input, approvals) {
+    rule := _input.default_branch_rules[_]
+    rule.type == "pull_request"
+    rule.parameters.required_approving_review_count >= approvals
+}
+
+ruleset_requires_strict_status_checks(_input) {
+    rule := _input.default_branch_rules[_]
+    rule.type == "required_status_checks"
+    rule.parameters.strict_required_status_checks_policy == true
+}
+
 # METADATA
 # scope: rule
 # title: Default Branch Is Not Protected
-# description: Branch protection is not enabled for this repository’s default branch. Protecting branches ensures new code changes must go through a controlled merge process and allows enforcement of code review as well as other security tests. This issue is raised if the default branch protection is turned off.
+# description: Branch protection (a branch protection rule or an active ruleset) is not enabled for this repository’s default branch. Protecting branches ensures new code changes must go through a controlled merge process and allows enforcement of code review as well as other security tests. This issue is raised if the default branch protection is turned off.
 # custom:
 #   remediationSteps: [Make sure you have admin permissions, Go to the repo's settings page, Enter "Branches" tab, Under "Branch protection rules", Click "Add rule", Set "Branch name pattern" as the default branch name (usually "main" or "master"), Set desired protections, Click "Create" and save the rule]
 #   severity: MEDIUM
+#   tags: [branch-protection, supply-chain]
 #   requiredScopes: [repo]
 default missing_default_branch_protection = false
 missing_default_branch_protection {
     has_branch_protection_info(input)
     is_null(input.repository.default_branch.branch_protection_rule)
+    not has_ruleset_rules(input)
 }
 
 # METADATA
@@ -122,6 +227,7 @@ missing_default_branch_protection {
 # custom:
 #   remediationSteps: [Make sure you have admin permissions, Go to the repo's settings page, Enter "Branches" tab ,Under "Branch protection rules", Click "Edit" on the default branch rule, Uncheck "Allow deletions", Click "Save changes"]
 #   severity: MEDIUM
+#   tags: [branch-protection]
 #   requiredScopes: [repo]
 #   threat:
 #     - "Users could merge code without any restrictions which could lead to insecure code reaching your main branch and production."
@@ -129,6 +235,7 @@ default missing_default_branch_protection_deletion = false
 missing_default_branch_protection_deletion {
     has_branch_protection_info(input)
 	input.repository.default_branch.branch_protection_rule.allows_deletions == true
+    not has_ruleset_rule(input, "deletion")
 }
 
 # METADATA
@@ -138,11 +245,13 @@ missing_default_branch_protection_deletion {
 # custom:
 #   remediationSteps: [Make sure you have admin permissions, Go to the repo's settings page, Enter "Branches" tab, Under "Branch protection rules", Click "Edit" on the default branch rule, Uncheck "Allow force pushes", Click "Save changes"]
 #   severity: MEDIUM
+#   tags: [branch-protection, supply-chain]
 #   requiredScopes: [repo]
 default missing_default_branch_protection_force_push = false
 missing_default_branch_protection_force_push {
     has_branch_protection_info(input)
 	input.repository.default_branch.branch_protection_rule.allows_force_pushes == true
+    not has_ruleset_rule(input, "non_fast_forward")
 }
 
 # METADATA
@@ -152,6 +261,7 @@ missing_default_branch_protection_force_push {
 # custom:
 #   remediationSteps: [Make sure you have admin permissions, Go to the repo's settings page, Enter "Branches" tab, Under "Branch protection rules", Click "Edit" on the default branch rule, Check "Require status checks to pass before merging", "Add the required checks that must pass before merging (tests, lint, etc...)", Click "Save changes"]
 #   severity: MEDIUM
+#   tags: [branch-protection, supply-chain]
 #   requiredScopes: [repo]
 #   threat:
 #     - "Users could merge its code without all required checks passes what could lead to insecure code reaching your main branch and production."
@@ -159,6 +269,7 @@ default requires_status_checks = false
 requires_status_checks {
     has_branch_protection_info(input)
     input.repository.default_branch.branch_protection_rule.requires_status_checks == false
+    not has_ruleset_rule(input, "required_status_checks")
 }
 # METADATA
 # scope: rule
@@ -167,11 +278,13 @@ requires_status_checks {
 # custom:
 #   remediationSteps: [Make sure you have admin permissions, Go to the repo's settings page, Enter "Branches" tab, Under "Branch protection rules", Click "Edit" on the default branch rule, Check "Require status checks to pass before merging", Check "Require branches to be up to date before merging", Click "Save changes"]
 #   severity: MEDIUM
+#   tags: [branch-protection]
 #   requiredScopes: [repo]
 default requires_branches_up_to_date_before_merge = false
 requires_branches_up_to_date_before_merge {
     has_branch_protection_info(input)
     input.repository.default_branch.branch_protection_rule.requires_strict_status_checks == false
+    not ruleset_requires_strict_status_checks(input)
 }
 
 # METADATA
@@ -181,11 +294,13 @@ requires_branches_up_to_date_before_merge {
 # custom:
 #   remediationSteps: [Make sure you have admin permissions, Go to the repo's settings page, Enter "Branches" tab, Under "Branch protection rules", Click "Edit" on the default branch rule, Check "Require a pull request before merging", Check "Dismiss stale pull request approvals when new commits are pushed", Click "Save changes"]
 #   severity: LOW
+#   tags: [branch-protection, code-review, supply-chain]
 #   requiredScopes: [repo]
 default dismisses_stale_reviews = false
 dismisses_stale_reviews {
     has_branch_protection_info(input)
     not input.repository.default_branch.branch_protection_rule.dismisses_stale_reviews
+    not ruleset_requires_pull_request_option(input, "dismiss_stale_reviews_on_push")
 }
 
 # METADATA
@@ -195,6 +310,7 @@ dismisses_stale_reviews {
 # custom:
 #   remediationSteps: [Make sure you have admin permissions, Go to the repo's settings page, Enter "Branches" tab, Under "Branch protection rules", Click "Edit" on the default branch rule, Check "Require a pull request before merging", Check "Require approvals", Set "Required number of approvals before merging" to 1 or more, Click "Save changes"]
 #   severity: HIGH
+#   tags: [branch-protection, code-review, supply-chain]
 #   requiredScopes: [repo]
 #   threat:
 #    - "Users can merge code without being reviewed which can lead to insecure code reaching the main branch and production."
@@ -202,6 +318,7 @@ default code_review_not_required = false
 code_review_not_required {
     has_branch_protection_info(input)
     input.repository.default_branch.branch_protection_rule.required_approving_review_count < 1
+    not ruleset_requires_approvals(input, 1)
 }
 
 # METADATA
@@ -211,6 +328,7 @@ code_review_not_required {
 # custom:
 #   remediationSteps: [Make sure you have admin permissions, Go to the repo's settings page, Enter "Branches" tab, Under "Branch protection rules", Click "Edit" on the default branch rule, Check "Require a pull request before merging", Check "Require approvals", Set "Required number of approvals before merging" to 1 or more, Click "Save changes"]
 #   severity: MEDIUM
+#   tags: [branch-protection, code-review, supply-chain]
 #   requiredScopes: [repo]
 #   threat:
 #    - "Users can merge code without being reviewed which can lead to insecure code reaching the main branch and production."
@@ -218,6 +336,7 @@ default code_review_by_two_members_not_required = false
 code_review_by_two_members_not_required {
     has_branch_protection_info(input)
     input.repository.default_branch.branch_protection_rule.required_approving_review_count < 2
+    not ruleset_requires_approvals(input, 2)
 }
 
 # METADATA
@@ -227,11 +346,125 @@ code_review_by_two_members_not_required {
 # custom:
 #   remediationSteps: [Make sure you have admin permissions, Go to the repo's settings page, Enter "Branches" tab, Under "Branch protection rules", Click "Edit" on the default branch rule, Check "Require a pull request before merging", Check "Require review from Code Owners", Click "Save changes"]
 #   severity: LOW
+#   tags: [branch-protection, code-review]
 #   requiredScopes: [repo]
 default code_review_not_limited_to_code_owners = false
 code_review_not_limited_to_code_owners {
     has_branch_protection_info(input)
     input.repository.default_branch.branch_protection_rule.requires_code_owner_reviews == false
+    not ruleset_requires_pull_request_option(input, "require_code_owner_review")
+}
+
+requires_code_owner_reviews(_input) {
+    _input.repository.default_branch.branch_protection_rule.requires_code_owner_reviews == true
+}
+
+requires_code_owner_reviews(_input) {
+    ruleset_requires_pull_request_option(_input, "require_code_owner_review")
+}
+
+# METADATA
+# scope: rule
+# title: Code Owners Review Is Required But No Code Owners Are Defined
+# description: The default branch requires a review from code owners, but the repository has no CODEOWNERS file (or it has no valid rules). Without code owners, the requirement has no effect and any reviewer can approve changes to sensitive code.
+# custom:
+#   remediationSteps: [Add a CODEOWNERS file to the .github directory of the default branch, Assign the owners (users or teams) of the repository code using patterns, e.g. "* @org/team"]
+#   severity: MEDIUM
+#   tags: [code-review]
+#   requiredScopes: [repo]
+default code_owners_review_required_without_code_owners = false
+code_owners_review_required_without_code_owners {
+    has_branch_protection_info(input)
+    requires_code_owner_reviews(input)
+    input.codeowners.rules == 0
+}
+
+# METADATA
+# scope: rule
+# title: CODEOWNERS File Has Errors
+# description: The repository's CODEOWNERS file has invalid lines (e.g. unsupported patterns or invalid owners), or refers to users and teams that don't exist or don't have write access to the repository. GitHub ignores invalid lines, so the matching code may have no owners, and code owners reviews aren't requested from unknown owners.
+# custom:
+#   remediationSteps: [Open the CODEOWNERS file in the default branch on GitHub, Review the errors GitHub highlights in the file, Fix the invalid lines and make sure the owners exist and have write access to the repository]
+#   severity: LOW
+#   tags: [code-review]
+#   requiredScopes: [repo]
+default code_owners_file_has_errors = false
+code_owners_file_has_errors {
+    input.codeowners.exists
+    count(input.codeowners.errors) > 0
+}
+
+# the owners that can't be resolved to users: email addresses and teams of other organizations (or whose members couldn't be collected)
+code_owners_rule_has_unresolved_owner(rule) {
+    owner := rule.owners[_]
+    not startswith(owner, "@")
+}
+
+code_owners_rule_has_unresolved_owner(rule) {
+    owner := rule.owners[_]
+    contains(owner, "/")
+    not input.codeowners_teams[lower(owner)]
+}
+
+# the users that can approve changes to the code owned by the rule
+code_owners_rule_approvers(rule) = approvers {
+    users := {lower(trim_prefix(owner, "@")) | owner := rule.owners[_]; startswith(owner, "@"); not contains(owner, "/")}
+    members := {lower(member) | owner := rule.owners[_]; contains(owner, "/"); member := input.codeowners_teams[lower(owner)][_]}
+    approvers := users | members
+}
+
+# METADATA
+# scope: rule
+# title: Code Owners Can Approve Their Own Changes
+# description: The default branch requires a review from code owners, but some code is owned by a single user (directly or through a team with a single member). GitHub doesn't let authors approve their own pull requests, so the changes of the only owner can't be approved by another owner, and are merged by bypassing the code owners review (e.g. by an admin) or approved using an alternate account added to the owning team - in effect, the owner approves their own changes.
+# custom:
+#   remediationSteps: [Edit the CODEOWNERS file in the default branch, Make sure each pattern is owned by at least two users (e.g. a team with several members), Make sure the owning teams have at least two members]
+#   severity: MEDIUM
+#   tags: [code-review]
+#   requiredScopes: [repo, read:org]
+#   threat:
+#     - "The only owner of sensitive code (e.g. the deployment workflows) merges their own changes to it without an independent review."
+code_owners_self_approval_possible[violated] = true {
+    has_branch_protection_info(input)
+    requires_code_owner_reviews(input)
+    rule := input.codeowners.ownership[_]
+    not code_owners_rule_has_unresolved_owner(rule)
+    approvers := code_owners_rule_approvers(rule)
+    count(approvers) == 1
+    violated := {
+        "pattern": rule.pattern,
+        "approver": approvers[_]
+    }
+}
+
+# METADATA
+# scope: rule
+# title: Public Repository Has No Security Policy
+# description: The public repository has no security policy (SECURITY.md), and the organization has no default one. Without a vulnerability disclosure policy, researchers who find vulnerabilities don't know how to report them privately, and may disclose them publicly instead.
+# custom:
+#   remediationSteps: [Add a SECURITY.md file to the root, docs or .github directory of the repository, describing how to report vulnerabilities (e.g. enable private vulnerability reporting), Alternatively, add a default SECURITY.md to the organization's public .github repository]
+#   severity: LOW
+#   tags: [vulnerability-management]
+#   requiredScopes: [repo]
+default missing_security_policy = false
+missing_security_policy {
+    input.repository.is_private == false
+    input.security_policy.exists == false
+}
+
+# METADATA
+# scope: rule
+# title: Public Repository Has No License
+# description: The public repository has no license that GitHub can detect. Without a license, the terms under which others may use, modify and distribute the code are unclear, which exposes both the organization and the users of the code to legal risk.
+# custom:
+#   remediationSteps: [Choose a license approved by your organization, Add a LICENSE file to the root of the repository]
+#   severity: LOW
+#   tags: [licensing]
+#   requiredScopes: [repo]
+default missing_license = false
+missing_license {
+    input.repository.is_private == false
+    is_null(input.repository.license_info)
 }
 
 # METADATA
@@ -241,11 +474,13 @@ code_review_not_limited_to_code_owners {
 # custom:
 #    remediationSteps: [Make sure you have admin permissions, Go to the repo's settings page, Enter "Branches" tab, Under "Branch protection rules", Click "Edit" on the default branch rule, Check "Require linear history", Click "Save changes"]
 #    severity: MEDIUM
+#    tags: [branch-protection]
 #    requiredScopes: [repo]
 default non_linear_history = false
 non_linear_history {
     has_branch_protection_info(input)
     input.repository.default_branch.branch_protection_rule.requires_linear_history == false
+    not has_ruleset_rule(input, "required_linear_history")
 }
 
 # METADATA
@@ -255,11 +490,13 @@ non_linear_history {
 # custom:
 #    remediationSteps: [Make sure you have admin permissions, Go to the repo's settings page, Enter "Branches" tab, Under "Branch protection rules", Click "Edit" on the default branch rule, Check "Require conversation resolution before merging", Click "Save changes"]
 #    severity: LOW
+#    tags: [branch-protection, code-review]
 #    requiredScopes: [repo]
 default no_conversation_resolution = false
 no_conversation_resolution {
     has_branch_protection_info(input)
     input.repository.default_branch.branch_protection_rule.requires_conversation_resolution == false
+    not ruleset_requires_pull_request_option(input, "required_review_thread_resolution")
 }
 
 # METADATA
@@ -269,11 +506,13 @@ no_conversation_resolution {
 # custom:
 #    remediationSteps: [Make sure you have admin permissions, Go to the repo's settings page, Enter "Branches" tab, Under "Branch protection rules", Click "Edit" on the default branch rule, Check "Require signed commits", Click "Save changes"]
 #    severity: LOW
+#    tags: [branch-protection, supply-chain]
 #    requiredScopes: [repo]
 default no_signed_commits = false
 no_signed_commits {
     has_branch_protection_info(input)
     input.repository.default_branch.branch_protection_rule.requires_commit_signatures == false
+    not has_ruleset_rule(input, "required_signatures")
 }
 
 # METADATA
@@ -283,6 +522,7 @@ no_signed_commits {
 # custom:
 #    remediationSteps: [Make sure you have admin permissions, Go to the repo's settings page, Enter "Branches" tab, Under "Branch protection rules", Click "Edit" on the default branch rule, Check "Restrict who can dismiss pull request reviews", Click "Save changes"]
 #    severity: LOW
+#    tags: [branch-protection, code-review]
 #    requiredScopes: [repo]
 default review_dismissal_allowed = false
 review_dismissal_allowed {
@@ -297,11 +537,178 @@ review_dismissal_allowed {
 # custom:
 #    remediationSteps: [Make sure you have admin permissions, Go to the repo's settings page, Enter "Branches" tab, Under "Branch protection rules", Click "Edit" on the default branch rule, Check "Restrict who can push to matching branches", Click "Save changes"]
 #    severity: MEDIUM
+#    tags: [branch-protection, access-control]
 #    requiredScopes: [repo]
 default pushes_are_not_restricted = false
 pushes_are_not_restricted {
     has_branch_protection_info(input)
     input.repository.default_branch.branch_protection_rule.restricts_pushes == false
+    not input.repository.default_branch.branch_protection_rule.lock_branch
+    not has_ruleset_rule(input, "pull_request")
+    not has_ruleset_rule(input, "update")
+}
+
+# METADATA
+# scope: rule
+# title: Default Branch Doesn't Require Approval Of The Most Recent Push
+# description: The most recent push to a pull request can be merged without being approved by someone other than the user who pushed it. A user can push unreviewed changes to a pull request after it was approved by others (unless stale approvals are dismissed) and merge it, bypassing the code review.
+# custom:
+#    remediationSteps: [Make sure you have admin permissions, Go to the repo's settings page, Enter "Branches" tab, Under "Branch protection rules", Click "Edit" on the default branch rule, Check "Require a pull request before merging", Check "Require approval of the most recent reviewable push", Click "Save changes"]
+#    severity: MEDIUM
+#    tags: [branch-protection, code-review]
+#    requiredScopes: [repo]
+#    threat:
+#     - "A user pushes malicious code to an already approved pull request and merges it without it being reviewed."
+default last_push_approval_not_required = false
+last_push_approval_not_required {
+    has_branch_protection_info(input)
+    input.repository.default_branch.branch_protection_rule.require_last_push_approval == false
+    not ruleset_requires_pull_request_option(input, "require_last_push_approval")
+}
+
+requires_signed_commits(_input) {
+    _input.repository.default_branch.branch_protection_rule.requires_commit_signatures == true
+}
+
+requires_signed_commits(_input) {
+    has_ruleset_rule(_input, "required_signatures")
+}
+
+# squash merges replace the pull request's commits by a single commit signed by GitHub, and rebase merges recreate them unsigned
+merge_method_rewrites_commits(repository) {
+    repository.squash_merge_allowed == true
+}
+
+merge_method_rewrites_commits(repository) {
+    repository.rebase_merge_allowed == true
+}
+
+# METADATA
+# scope: rule
+# title: Allowed Merge Methods Don't Preserve Commit Signatures
+# description: The default branch requires signed commits, but squash merging or rebase merging is allowed. Squash merges replace the signed commits of the pull request by a single commit signed by GitHub, and rebase merges recreate them without their signatures, so the default branch history doesn't show who authored the merged changes. Allow merge commits only, which keep the signed commits in the history.
+# custom:
+#    remediationSteps: [Make sure you have admin permissions, Go to the repo's settings page, Under "Pull Requests", Uncheck "Allow squash merging" and "Allow rebase merging", Make sure "Allow merge commits" is checked]
+#    severity: LOW
+#    tags: [branch-protection, code-review]
+#    requiredScopes: [repo]
+default merge_methods_dont_preserve_commit_signatures = false
+merge_methods_dont_preserve_commit_signatures {
+    has_branch_protection_info(input)
+    requires_signed_commits(input)
+    merge_method_rewrites_commits(input.repository)
+}
+
+requires_code_review(_input) {
+    is_number(_input.repository.default_branch.branch_protection_rule.required_approving_review_count)
+    _input.repository.default_branch.branch_protection_rule.required_approving_review_count >= 1
+}
+
+requires_code_review(_input) {
+    ruleset_requires_approvals(_input, 1)
+}
+
+# METADATA
+# scope: rule
+# title: Auto-Merge Is Allowed Without Dismissing Stale Approvals
+# description: Auto-merge is allowed, while the default branch requires code review without dismissing stale approvals (or requiring approval of the most recent push). Once a pull request is approved and set to auto-merge, changes pushed to it afterwards are merged automatically as soon as the status checks pass, without being reviewed.
+# custom:
+#    remediationSteps: [Make sure you have admin permissions, Go to the repo's settings page, Enter "Branches" tab, Under "Branch protection rules", Click "Edit" on the default branch rule, Check "Dismiss stale pull request approvals when new commits are pushed" or "Require approval of the most recent reviewable push", Click "Save changes"]
+#    severity: MEDIUM
+#    tags: [branch-protection, code-review]
+#    requiredScopes: [repo]
+#    threat:
+#     - "A user gets a pull request approved and sets it to auto-merge, then pushes malicious code to it, which is merged automatically without being reviewed."
+default auto_merge_allowed_without_dismissing_stale_reviews = false
+auto_merge_allowed_without_dismissing_stale_reviews {
+    has_branch_protection_info(input)
+    input.repository.auto_merge_allowed == true
+    requires_code_review(input)
+    not input.repository.default_branch.branch_protection_rule.dismisses_stale_reviews
+    not input.repository.default_branch.branch_protection_rule.require_last_push_approval
+    not ruleset_requires_pull_request_option(input, "dismiss_stale_reviews_on_push")
+    not ruleset_requires_pull_request_option(input, "require_last_push_approval")
+}
+
+# METADATA
+# scope: rule
+# title: Default Branch Doesn't Require Successful Deployments Before Merging
+# description: Changes can be merged to the default branch without being successfully deployed to an environment first (e.g. staging). Requiring deployments before merging makes sure changes are tested in a deployment environment before they reach the default branch (and production).
+# custom:
+#    remediationSteps: [Make sure you have admin permissions, Go to the repo's settings page, Enter "Branches" tab, Under "Branch protection rules", Click "Edit" on the default branch rule, Check "Require deployments to succeed before merging", Select the environments that must be successfully deployed to, Click "Save changes"]
+#    severity: LOW
+#    tags: [branch-protection]
+#    requiredScopes: [repo]
+default deployments_not_required = false
+deployments_not_required {
+    has_branch_protection_info(input)
+    input.repository.default_branch.branch_protection_rule.requires_deployments == false
+    not has_ruleset_rule(input, "required_deployments")
+}
+
+branch_has_rules(branch) {
+    branch.rules[_]
+}
+
+branch_requires_approvals(branch, approvals) {
+    branch.required_approving_review_count >= approvals
+}
+
+branch_requires_approvals(branch, approvals) {
+    rule := branch.rules[_]
+    rule.type == "pull_request"
+    rule.parameters.required_approving_review_count >= approvals
+}
+
+# METADATA
+# scope: rule
+# title: Branches Matching The Protected Branch Patterns Are Not Protected
+# description: Non-default branches that match the configured protected branch patterns (--protected-branches, e.g. release/*) are not protected by a branch protection rule or an active ruleset. Organizations that ship from release or hotfix branches should protect them like the default branch, otherwise code can reach production without any review.
+# custom:
+#   remediationSteps: [Make sure you have admin permissions, Go to the repo's settings page, Enter "Branches" tab, Under "Branch protection rules", Click "Add rule", Set "Branch name pattern" to the pattern of the reported branches (e.g. release/*), Set desired protections, Click "Create" and save the rule]
+#   severity: MEDIUM
+#   tags: [branch-protection, supply-chain]
+#   requiredScopes: [repo]
+#   threat:
+#     - "Users could push code directly to the release branches, which could lead to insecure code reaching production."
+non_default_branch_not_protected[violated] = true {
+    branch := input.branches[_]
+    not branch.protected
+    not branch_has_rules(branch)
+    violated := {
+        "branch": branch.name
+    }
+}
+
+# METADATA
+# scope: rule
+# title: Branches Matching The Protected Branch Patterns Don't Require Code Review
+# description: Non-default branches that match the configured protected branch patterns (--protected-branches, e.g. release/*) are protected, but their protection doesn't require approving reviews before merging. In order to comply with separation of duties principle, a code review should be mandatory for the branches that are shipped from as well.
+# custom:
+#   remediationSteps: [Make sure you have admin permissions, Go to the repo's settings page, Enter "Branches" tab, Under "Branch protection rules", Click "Edit" on the rule of the reported branches, Check "Require a pull request before merging", Check "Require approvals", Set "Required number of approvals before merging" to 1 or more, Click "Save changes"]
+#   severity: HIGH
+#   tags: [branch-protection, code-review, supply-chain]
+#   requiredScopes: [repo]
+#   threat:
+#     - "Users can merge code to the release branches without being reviewed, which can lead to insecure code reaching production."
+non_default_branch_code_review_not_required[violated] = true {
+    branch := input.branches[_]
+    is_number(branch.required_approving_review_count)
+    branch.required_approving_review_count < 1
+    not branch_requires_approvals(branch, 1)
+    violated := {
+        "branch": branch.name
+    }
+}
+
+non_default_branch_code_review_not_required[violated] = true {
+    branch := input.branches[_]
+    not branch.protected
+    branch_has_rules(branch)
+    not branch_requires_approvals(branch, 1)
+    violated := {
+        "branch": branch.name
+    }
 }
 
 # METADATA
@@ -311,6 +718,7 @@ pushes_are_not_restricted {
 # custom:
 #   remediationSteps: [Make sure you have admin permissions, Go to the repo's settings page, Enter "Code security and analysis" tab, Set "Dependabot alerts" as Enabled]
 #   severity: MEDIUM
+#   tags: [vulnerability-management, supply-chain, ghas]
 #   requiredScopes: [repo]
 default vulnerability_alerts_not_enabled = false
 vulnerability_alerts_not_enabled {
@@ -318,6 +726,24 @@ vulnerability_alerts_not_enabled {
     input.vulnerability_alerts_enabled == false
 }
 
+# METADATA
+# scope: rule
+# title: Dependency Graph Is Not Enabled For A Repository
+# description: The dependency graph identifies the repository's dependencies from its manifest and lock files. Dependabot alerts, Dependabot security updates and dependency review all rely on it, so without it the repository's vulnerable dependencies are not detected (on GitHub Enterprise Server and for private repositories it has to be enabled explicitly).
+# custom:
+#    prerequisites: [dependency_graph_sbom]
+#    remediationSteps: [Make sure you have admin permissions, Go to the repo's settings page, Enter "Code security and analysis" tab, Set "Dependency graph" as Enabled]
+#    severity: MEDIUM
+#    tags: [vulnerability-management, supply-chain]
+#    requiredScopes: [repo]
+#    threat:
+#      - "Vulnerable dependencies of the repository are not reported, so they remain exploitable until someone notices them manually."
+default dependency_graph_not_enabled = false
+dependency_graph_not_enabled {
+    # deliberately ignoring nil value (in case this data is unavailable)
+    input.dependency_graph_enabled == false
+}
+
 # METADATA
 # scope: rule
 # title: GitHub Advanced Security – Dependency Review Is Disabled For A Repository
@@ -325,6 +751,7 @@ vulnerability_alerts_not_enabled {
 # custom:
 #    remediationSteps: [Make sure you have admin permissions, Go to the repo's settings page, Enter "Code security and analysis" tab, Set "Dependency graph" as Enabled]
 #    severity: MEDIUM
+#    tags: [vulnerability-management, supply-chain, ghas]
 #    requiredScopes: [repo]
 #    threat:
 #      - "A user can add dependencies to vulnerable third-party dependencies therefore introducing vulnerabilities to your application."
@@ -333,6 +760,55 @@ ghas_dependency_review_not_enabled {
     input.dependency_graph_manifests.total_count == 0
 }
 
+# METADATA
+# scope: rule
+# title: GitHub Advanced Security – Secret Scanning Is Disabled For A Repository
+# description: Enable secret scanning to detect secrets (e.g. tokens, private keys) that were committed to the repository.
+# custom:
+#    remediationSteps: [Make sure you have admin permissions, Go to the repo's settings page, Enter "Code security and analysis" tab, Set "Secret scanning" as Enabled]
+#    severity: MEDIUM
+#    tags: [ghas, secrets]
+#    requiredScopes: [repo]
+#    threat:
+#      - "Secrets committed to the repository can be used by anyone with read access to the repository (or to its history) to access the systems they protect."
+default secret_scanning_not_enabled = false
+secret_scanning_not_enabled {
+    input.security_and_analysis.secret_scanning.status == "disabled"
+}
+
+# METADATA
+# scope: rule
+# title: GitHub Advanced Security – Secret Scanning Push Protection Is Disabled For A Repository
+# description: Enable secret scanning push protection to block pushes that contain secrets before they reach the repository.
+# custom:
+#    prerequisites: [secret_scanning_push_protection]
+#    remediationSteps: [Make sure you have admin permissions, Go to the repo's settings page, Enter "Code security and analysis" tab, Under "Secret scanning", Set "Push protection" as Enabled]
+#    severity: LOW
+#    tags: [ghas, secrets]
+#    requiredScopes: [repo]
+#    threat:
+#      - "A secret that was pushed is exposed until it is rotated, even if the commit is removed later."
+default secret_scanning_push_protection_not_enabled = false
+secret_scanning_push_protection_not_enabled {
+    input.security_and_analysis.secret_scanning_push_protection.status == "disabled"
+}
+
+# METADATA
+# scope: rule
+# title: GitHub Advanced Security – Code Scanning Is Not Enabled For A Repository
+# description: The repository has no code scanning analyses. Enable code scanning (e.g. CodeQL) to find vulnerabilities and coding errors in the repository code.
+# custom:
+#    remediationSteps: [Make sure you have admin permissions, Go to the repo's settings page, Enter "Code security and analysis" tab, Under "Code scanning", Set up "CodeQL analysis"]
+#    severity: LOW
+#    tags: [ghas, vulnerability-management]
+#    requiredScopes: [security_events]
+#    threat:
+#      - "Vulnerabilities introduced to the code are not detected before they reach production."
+default code_scanning_not_enabled = false
+code_scanning_not_enabled {
+    input.code_scanning_enabled == false
+}
+
 # METADATA
 # scope: rule
 # title: Low scorecard score for repository indicates poor security posture
@@ -341,6 +817,7 @@ ghas_dependency_review_not_enabled {
 #    requiredEnrichers: [scorecard]
 #    remediationSteps: [Get scorecard output by either:, "- Run legitify with --scorecard verbose", "- Run scorecard manually", Fix the failed checks]
 #    severity: MEDIUM
+#    tags: [supply-chain]
 #    requiredScopes: [repo, read:repo_hook]
 #    prerequisites: [scorecard_enabled]
 default scorecard_score_too_low = false
@@ -352,18 +829,19 @@ scorecard_score_too_low {
 # METADATA
 # scope: rule
 # title: Default workflow token permission is not read only
-# description: Your default GitHub Action workflow token permission is set to read-write. When creating workflow tokens, it is highly recommended to follow the Principle of Least Privilege and force workflow authors to specify explicitly which permissions they need.
+# description: The repository's default GitHub Action workflow token (GITHUB_TOKEN) permission is set to read-write. When creating workflow tokens, it is highly recommended to follow the Principle of Least Privilege and force workflow authors to specify explicitly which permissions they need.
 # custom:
-#   requiredEnrichers: [organizationId]
 #   remediationSteps:
 #     - Make sure you have admin permissions
-#     - Go to the org's settings page
+#     - Go to the repository settings page
 #     - Enter "Actions - General" tab
 #     - Under 'Workflow permissions'
-#     - Select 'Read repository contents permission'
+#     - Select 'Read repository contents and packages permissions'
 #     - Click 'Save'
+#     - "Note: the repository setting can't be more permissive than the organization's, so you may need to change the organization's setting first"
 #   severity: MEDIUM
-#   requiredScopes: [admin:org]
+#   tags: [actions, supply-chain, least-privilege]
+#   requiredScopes: [repo]
 #   threat: In case of token compromise (due to a vulnerability or malicious third-party GitHub actions), an attacker can use this token to sabotage various assets in your CI/CD pipeline, such as packages, pull-requests, deployments, and more.
 default token_default_permissions_is_read_write  = false
 token_default_permissions_is_read_write {
@@ -373,20 +851,435 @@ token_default_permissions_is_read_write {
 # METADATA
 # scope: rule
 # title: Workflows Are Allowed To Approve Pull Requests
-# description: Your default GitHub Actions configuration allows for workflows to approve pull requests. This could allow users to bypass code-review restrictions.
+# description: The repository's GitHub Actions configuration allows for workflows to approve pull requests. This could allow users to bypass code-review restrictions.
 # custom:
-#   requiredEnrichers: [organizationId]
 #   remediationSteps:
 #     - Make sure you have admin permissions
-#     - Go to the org's settings page
+#     - Go to the repository settings page
 #     - Enter "Actions - General" tab
 #     - Under 'Workflow permissions'
 #     - Uncheck 'Allow GitHub actions to create and approve pull requests.
 #     - Click 'Save'
 #   severity: HIGH
-#   requiredScopes: [admin:org]
+#   tags: [actions, supply-chain, code-review]
+#   requiredScopes: [repo]
 #   threat: Attackers can exploit this misconfiguration to bypass code-review restrictions by creating a workflow that approves their own pull request and then merging the pull request without anyone noticing, introducing malicious code that would go straight ahead to production.
 default actions_can_approve_pull_requests  = false
 actions_can_approve_pull_requests {
     input.actions_token_permissions.can_approve_pull_request_reviews
 }
+
+# METADATA
+# scope: rule
+# title: Repository Allows All GitHub Actions
+# description: The repository's GitHub Actions configuration allows any action or reusable workflow to run. By not restricting which actions are permitted, developers can use actions that were not audited and are potentially malicious, exposing the pipeline to supply chain attacks.
+# custom:
+#   remediationSteps:
+#     - Make sure you have admin permissions
+#     - Go to the repository settings page
+#     - Enter "Actions - General" tab
+#     - Under "Actions permissions", select "Allow enterprise, and select non-enterprise, actions and reusable workflows"
+#     - Check "Allow actions created by GitHub" and set any other used trusted actions
+#     - Click "Save"
+#     - "Note: the repository setting can't be more permissive than the organization's, so you may need to change the organization's setting first"
+#   severity: MEDIUM
+#   tags: [actions, supply-chain]
+#   requiredScopes: [repo]
+#   threat:
+#     - "1. Attacker creates a repository with a tempting but malicious custom GitHub Action"
+#     - "2. An innocent developer uses this malicious action in the repository's workflows"
+#     - "3. The malicious action has access to the repository and could steal its secrets or modify its content"
+default all_github_actions_are_allowed = false
+all_github_actions_are_allowed {
+    input.actions_permissions.enabled
+    input.actions_permissions.allowed_actions == "all"
+}
+
+# METADATA
+# scope: rule
+# title: Fork Pull Request Workflows Of Outside Contributors Run Without Approval
+# description: The public repository requires an approval to run workflows only for the pull requests of first-time contributors, so any outside contributor that has already contributed (e.g. a merged typo fix) can run workflows without a maintainer's approval. Workflows that run on fork pull requests can consume the self-hosted runners and Actions minutes, and if they are triggered by pull_request_target or workflow_run - access the repository secrets.
+# custom:
+#   prerequisites: [fork_pr_contributor_approval]
+#   remediationSteps:
+#     - Make sure you have admin permissions
+#     - Go to the repository settings page
+#     - Enter "Actions - General" tab
+#     - Under "Approval for running fork pull request workflows from contributors", select "Require approval for all external contributors"
+#     - Click "Save"
+#   severity: MEDIUM
+#   tags: [actions, supply-chain]
+#   requiredScopes: [repo]
+#   threat:
+#     - "1. An attacker gets a harmless pull request merged into the repository"
+#     - "2. The attacker opens another pull request from a fork that modifies a workflow, or abuses a workflow that checks out the pull request code"
+#     - "3. The workflow runs without approval, letting the attacker run code on the repository's runners and potentially exfiltrate secrets"
+default fork_pull_request_workflows_run_without_approval = false
+fork_pull_request_workflows_run_without_approval {
+    not input.repository.is_private
+    input.fork_pr_approval.approval_policy != "all_external_contributors"
+}
+
+# METADATA
+# scope: rule
+# title: Persistent Self-Hosted Runner Is Attached To A Public Repository
+# description: Anyone can open a pull request on a public repository and have its workflows run on the repository's self-hosted runners. Persistent (non-ephemeral) runners keep their state between jobs, so a single malicious job can take over the runner machine, and with it every later job (and secret) that runs on it.
+# custom:
+#   requiredEnrichers: [runnersList]
+#   remediationSteps:
+#     - Make sure you have admin permissions
+#     - Go to the repository settings page
+#     - Enter "Actions - Runners" tab
+#     - Remove the persistent self-hosted runners
+#     - Use GitHub-hosted runners, or register ephemeral self-hosted runners (with the "--ephemeral" flag) instead
+#   severity: HIGH
+#   tags: [actions, runners, supply-chain]
+#   requiredScopes: [repo]
+#   threat: An attacker can open a pull request from a fork that runs a malicious workflow on the persistent runner, install a backdoor on it and steal the secrets and artifacts of the following builds.
+persistent_self_hosted_runner_on_public_repository[violated] = true {
+    not input.repository.is_private
+    some index
+    runner := input.self_hosted_runners[index]
+    not runner.ephemeral
+    violated := {
+        "name": runner.name,
+        "os": runner.os,
+        "status": runner.status,
+        "labels": concat(", ", [label.name | label := runner.labels[_]])
+    }
+}
+
+# METADATA
+# scope: rule
+# title: Actions OIDC Subject Claim Is Not Scoped To A Repository
+# description: The repository's customized OIDC subject ('sub') claim template doesn't include the repository (e.g. only 'repository_owner' or 'environment'). Cloud providers federate with GitHub Actions by matching the subject claim, so other repositories using the same template get the same subject, and can assume the cloud roles that were meant for this repository.
+# custom:
+#   prerequisites: [oidc_subject_claim]
+#   remediationSteps:
+#     - Make sure you have admin permissions
+#     - Use the REST API to update the repository's subject claim template ("PUT /repos/{owner}/{repo}/actions/oidc/customization/sub")
+#     - Include the "repo" (or "repository_id") claim in "include_claim_keys"
+#     - Update the trust policies of your cloud roles to match the new subject
+#   severity: HIGH
+#   tags: [actions, supply-chain, least-privilege]
+#   requiredScopes: [repo]
+#   threat:
+#     - "1. A cloud role trusts the subject claim of the repository"
+#     - "2. An attacker with write access to another repository that uses the same template creates a workflow that requests an OIDC token"
+#     - "3. The token has the same subject claim, so the attacker assumes the cloud role and gains access to the production environment"
+default oidc_subject_claim_not_scoped_to_repository = false
+oidc_subject_claim_not_scoped_to_repository {
+    oidcUtils.customized(input.oidc_subject_claim)
+    not oidcUtils.scoped_to_repository(input.oidc_subject_claim)
+}
+
+# METADATA
+# scope: rule
+# title: Actions OIDC Subject Claim Is Not Scoped To A Branch, Environment Or Workflow
+# description: The repository's customized OIDC subject ('sub') claim template doesn't include the context of the job (the branch, the environment or the workflow). Cloud roles that trust this subject can be assumed by any workflow, from any branch, of the repository - including unreviewed code pushed to a feature branch.
+# custom:
+#   prerequisites: [oidc_subject_claim]
+#   remediationSteps:
+#     - Make sure you have admin permissions
+#     - Use the REST API to update the repository's subject claim template ("PUT /repos/{owner}/{repo}/actions/oidc/customization/sub")
+#     - Include the "context" claim (or "ref", "environment" or "job_workflow_ref") in "include_claim_keys"
+#     - Update the trust policies of your cloud roles to match the new subject
+#   severity: MEDIUM
+#   tags: [actions, supply-chain, least-privilege]
+#   requiredScopes: [repo]
+#   threat: An attacker that can push a branch to a repository can run a workflow from it that assumes the repository's cloud roles, bypassing the code review and environment protection rules that guard the deployments.
+default oidc_subject_claim_not_scoped_to_context = false
+oidc_subject_claim_not_scoped_to_context {
+    oidcUtils.customized(input.oidc_subject_claim)
+    not oidcUtils.scoped_to_context(input.oidc_subject_claim)
+}
+
+# METADATA
+# scope: rule
+# title: Workflow Uses Third-Party Actions That Are Not Pinned To A Commit SHA
+# description: The repository's workflows use third-party actions (or reusable workflows) by a tag or a branch. Tags and branches are mutable, so the owner of the action (or anyone who compromises it) can change the code that runs in the workflows, with access to the repository's secrets and token. Pinning the action to a full-length commit SHA is the only way to use it as an immutable release. Actions of the trusted publishers (by default 'actions' and 'github', configurable with --trusted-action-publishers) are not reported.
+# custom:
+#   remediationSteps:
+#     - Find the commit SHA of the audited version of each reported action (e.g. in its releases page)
+#     - "Replace the tag or branch in the workflow with the commit SHA, e.g. 'uses: owner/action@<commit SHA> # v1.2.3'"
+#     - Keep the pinned actions up to date using Dependabot version updates for GitHub Actions
+#   severity: MEDIUM
+#   tags: [actions, supply-chain]
+#   requiredScopes: [repo]
+#   threat:
+#     - "1. An attacker compromises a popular third-party action and moves its release tag to a malicious commit"
+#     - "2. The repository's workflows run the malicious version of the action on their next run"
+#     - "3. The malicious action steals the repository's secrets or pushes code using the workflow token"
+unpinned_third_party_actions[violated] = true {
+    some workflow_index, job_index, step_index
+    workflow := input.workflows[workflow_index]
+    job := workflow.jobs[job_index]
+    reference := job.steps[step_index].uses
+    unpinned_third_party_action(reference, input.trusted_action_publishers)
+    violated := {
+        "workflow": workflow.path,
+        "job": job.id,
+        "action": reference.raw
+    }
+}
+
+unpinned_third_party_actions[violated] = true {
+    some workflow_index, job_index
+    workflow := input.workflows[workflow_index]
+    job := workflow.jobs[job_index]
+    unpinned_third_party_action(job.uses, input.trusted_action_publishers)
+    violated := {
+        "workflow": workflow.path,
+        "job": job.id,
+        "action": job.uses.raw
+    }
+}
+
+unpinned_third_party_action(reference, trusted) {
+    not reference.local
+    not reference.docker
+    not reference.pinned_to_sha
+    not trusted_publisher(reference.owner, trusted)
+}
+
+trusted_publisher(owner, trusted) {
+    owner == trusted[_]
+}
+
+# METADATA
+# scope: rule
+# title: Workflow Triggered By pull_request_target Checks Out The Pull Request Code Or Exposes Secrets
+# description: Workflows triggered by 'pull_request_target' run for pull requests from forks in the context of the base repository, with its secrets and a write token. Checking out (and then building or testing) the pull request code in such a workflow runs attacker-controlled code with these privileges, and passing secrets to its steps exposes them to any step that handles the pull request content.
+# custom:
+#   remediationSteps:
+#     - Use the 'pull_request' trigger for workflows that build or test the pull request code, since it runs fork pull requests without secrets and with a read-only token
+#     - If the workflow needs privileges (e.g. to comment on the pull request), split it into a 'pull_request' workflow that processes the code and a 'workflow_run' workflow that uses its results
+#     - Don't pass secrets to steps of 'pull_request_target' workflows, and never check out the pull request head in them
+#   severity: HIGH
+#   tags: [actions, supply-chain]
+#   requiredScopes: [repo]
+#   threat:
+#     - "1. An attacker forks the repository and opens a pull request that modifies a build script"
+#     - "2. The pull_request_target workflow checks out the pull request head and runs the build"
+#     - "3. The attacker's code runs with the repository's secrets and write token, and exfiltrates them or pushes malicious code"
+pull_request_target_workflow_is_dangerous[violated] = true {
+    some workflow_index, job_index, step_index
+    workflow := input.workflows[workflow_index]
+    triggered_by_pull_request_target(workflow)
+    job := workflow.jobs[job_index]
+    checks_out_pull_request_head(job.steps[step_index])
+    violated := {
+        "workflow": workflow.path,
+        "job": job.id,
+        "reason": "checks out the pull request head"
+    }
+}
+
+pull_request_target_workflow_is_dangerous[violated] = true {
+    some workflow_index, job_index
+    workflow := input.workflows[workflow_index]
+    triggered_by_pull_request_target(workflow)
+    job := workflow.jobs[job_index]
+    exposes_secrets(job)
+    violated := {
+        "workflow": workflow.path,
+        "job": job.id,
+        "reason": "exposes secrets"
+    }
+}
+
+triggered_by_pull_request_target(workflow) {
+    workflow.triggers[_] == "pull_request_target"
+}
+
+checks_out_pull_request_head(step) {
+    step.uses.owner == "actions"
+    step.uses.repo == "checkout"
+    untrusted_ref(step["with"].ref)
+}
+
+checks_out_pull_request_head(step) {
+    contains(step.run, "gh pr checkout")
+}
+
+untrusted_ref(ref) {
+    contains(ref, "github.event.pull_request.head")
+}
+
+untrusted_ref(ref) {
+    contains(ref, "github.head_ref")
+}
+
+untrusted_ref(ref) {
+    contains(ref, "refs/pull/")
+}
+
+exposes_secrets(job) {
+    job.inherits_secrets
+}
+
+exposes_secrets(job) {
+    job.secrets[_] != "GITHUB_TOKEN"
+}
+
+exposes_secrets(job) {
+    job.steps[_].secrets[_] != "GITHUB_TOKEN"
+}
+
+# METADATA
+# scope: rule
+# title: Dependabot Security Updates Are Not Enabled For A Repository
+# description: Dependabot security updates open pull requests that upgrade vulnerable dependencies to a patched version as soon as an alert is raised. Without them, fixing the vulnerable dependencies depends on someone noticing the alerts.
+# custom:
+#   remediationSteps: [Make sure you have admin permissions, Go to the repo's settings page, Enter "Code security and analysis" tab, Under "Dependabot", Set "Dependabot security updates" as Enabled]
+#   severity: MEDIUM
+#   tags: [supply-chain, dependencies]
+#   requiredScopes: [repo]
+#   threat:
+#     - "Vulnerable dependencies stay in the repository long after a fix is available, and can be exploited by attackers who track the published vulnerabilities."
+default dependabot_security_updates_not_enabled = false
+dependabot_security_updates_not_enabled {
+    input.security_and_analysis.dependabot_security_updates.status == "disabled"
+}
+
+# METADATA
+# scope: rule
+# title: Dependabot Version Updates Are Not Configured For A Repository
+# description: The repository has no Dependabot configuration file (.github/dependabot.yml), so its dependencies are not updated automatically. Outdated dependencies accumulate known vulnerabilities and are harder to upgrade once a security fix is needed.
+# custom:
+#   remediationSteps:
+#     - "Add a .github/dependabot.yml file with an 'updates' entry for each package ecosystem the repository uses (see https://docs.github.com/en/code-security/dependabot/dependabot-version-updates/configuring-dependabot-version-updates)"
+#   severity: LOW
+#   tags: [supply-chain, dependencies]
+#   requiredScopes: [repo]
+#   threat:
+#     - "Dependencies that are never updated fall behind their security fixes, leaving the repository exposed to known vulnerabilities."
+default dependabot_version_updates_not_configured = false
+dependabot_version_updates_not_configured {
+    input.dependabot_config.exists == false
+}
+
+# METADATA
+# scope: rule
+# title: Dependabot Doesn't Update The Actions Used By The Repository Workflows
+# description: The repository has workflows, but its Dependabot configuration doesn't include the 'github-actions' ecosystem. Actions that are pinned to a commit SHA (or to an old version) are not updated with their security fixes unless Dependabot updates them.
+# custom:
+#   remediationSteps:
+#     - "Add an 'updates' entry with 'package-ecosystem: github-actions' (and 'directory: /') to the repository's .github/dependabot.yml"
+#   severity: LOW
+#   tags: [actions, supply-chain, dependencies]
+#   requiredScopes: [repo]
+#   threat:
+#     - "The workflows keep running vulnerable versions of actions after a fix was released, with access to the repository's secrets and token."
+default dependabot_does_not_update_actions = false
+dependabot_does_not_update_actions {
+    input.dependabot_config.exists
+    count(input.workflows) > 0
+    not updates_actions(input.dependabot_config)
+}
+
+updates_actions(config) {
+    config.ecosystems[_] == "github-actions"
+}
+
+# METADATA
+# scope: rule
+# title: Files That Usually Contain Secrets Are Present In The Repository
+# description: The default branch of the repository has files whose names suggest they contain secrets (e.g. .env files, private keys, credentials files or Terraform state). Secrets that are committed to the repository are exposed to anyone with read access to it, and stay in its history even after they are removed. This check is based on the file names only (enabled with --suspicious-files), and complements secret scanning for repositories that don't have it.
+# custom:
+#   remediationSteps:
+#     - Review the reported files and check whether they contain secrets
+#     - Rotate any secret that was committed, since it remains in the repository history
+#     - Remove the files from the repository and add their names to the .gitignore file
+#     - Enable secret scanning (and push protection) to detect secrets in the repository content
+#   severity: MEDIUM
+#   tags: [secrets]
+#   requiredScopes: [repo]
+#   threat:
+#     - "Anyone with read access to the repository (or to a fork or clone of it) can use the committed secrets to access the systems they protect."
+suspicious_files_present[violated] = true {
+    some index
+    file := input.suspicious_files[index]
+    violated := {
+        "path": file.path,
+        "pattern": file.pattern
+    }
+}
+
+# METADATA
+# scope: rule
+# title: Outside Collaborator Has Admin Or Maintain Permissions On A Private Repository
+# description: A user who isn't a member of the organization has the admin or maintain permission on a private repository. Outside collaborators are not subject to the organization's membership policies (such as SSO and two-factor authentication requirements, or the offboarding process), yet these permissions allow them to change the repository settings, including its visibility, collaborators and branch protection. The repository's outside collaborators and their permissions are available to custom policies as input.outside_collaborators.
+# custom:
+#   severity: HIGH
+#   tags: [access-control, least-privilege]
+#   remediationSteps: [Make sure you have admin permissions, Go to the repository settings page, Select "Collaborators and teams", Lower the outside collaborator's role to write (or less) or remove them, Prefer granting elevated permissions to organization members]
+#   requiredScopes: [repo]
+#   threat:
+#     - "A contractor's account, which isn't protected by the organization's SSO, is compromised and used to make the private repository public."
+outside_collaborator_has_elevated_permissions[violated] = true {
+    input.repository.is_private
+    collaborator := input.outside_collaborators[_]
+    collaborator.permission == ["admin", "maintain"][_]
+    violated := {
+        "login": collaborator.login,
+        "permission": collaborator.permission
+    }
+}
+
+# METADATA
+# scope: rule
+# title: Required Code Review Can Be Bypassed
+# description: The default branch requires a code review, but some users, teams, apps or deploy keys can bypass every protection that requires it (the branch protection rule and the rulesets), so they can push or merge unreviewed code to the default branch. The bypassing actors are collected from the repository collaborators (including the members of the bypassing teams) and the bypass lists of the protections.
+# custom:
+#   remediationSteps: [Make sure you have admin permissions, Go to the repository settings, Under "Branches" and "Rules", review the actors allowed to bypass the required pull request reviews, Enable "Do not allow bypassing the above settings" in the branch protection rule, Remove the unnecessary bypass actors from the rulesets]
+#   severity: MEDIUM
+#   tags: [code-review]
+#   requiredScopes: [repo]
+#   threat:
+#     - "A compromised admin account or an integration allowed to bypass the reviews pushes malicious code to the default branch without anyone reviewing it."
+code_review_can_be_bypassed[violated] = true {
+    input.enforcement.review_required
+    pusher := input.enforcement.unreviewed_pushers[_]
+    violated := {
+        "type": pusher.type,
+        "id": pusher.id,
+        "name": pusher.name
+    }
+}
+
+# METADATA
+# scope: rule
+# title: Private Repository Pages Site Is Publicly Accessible
+# description: The repository is private, but its GitHub Pages site is publicly accessible, so anyone can read the content it publishes (e.g. internal documentation). On GitHub Enterprise Cloud, the site of a private repository can be restricted to the people who have read access to the repository. The repository's pages configuration is available to custom policies as input.pages.
+# custom:
+#   severity: MEDIUM
+#   tags: [data-exposure]
+#   remediationSteps: [Make sure you have admin permissions, Go to the repository settings, Select "Pages", Under "Visibility", select "Private" (or unpublish the site if it isn't needed)]
+#   requiredScopes: [repo]
+#   threat:
+#     - "An internal documentation site published from a private repository exposes the organization's architecture and internal endpoints to anyone who finds its URL."
+default pages_public_for_private_repository = false
+pages_public_for_private_repository {
+    input.repository.is_private == true
+    input.pages.enabled
+    input.pages.public
+}
+
+# METADATA
+# scope: rule
+# title: Pages Custom Domain Does Not Enforce HTTPS
+# description: The repository's GitHub Pages site is served from a custom domain without enforcing HTTPS, so its visitors can be served over plain HTTP, and the site's content can be intercepted and modified in transit. The repository's pages configuration is available to custom policies as input.pages.
+# custom:
+#   severity: MEDIUM
+#   tags: [data-exposure]
+#   remediationSteps: [Make sure you have admin permissions, Go to the repository settings, Select "Pages", Wait for the custom domain's certificate to be provisioned, Check "Enforce HTTPS"]
+#   requiredScopes: [repo]
+#   threat:
+#     - "An attacker on the same network as a visitor modifies the site's download instructions to point to a malicious installer."
+default pages_custom_domain_without_https = false
+pages_custom_domain_without_https {
+    input.pages.enabled
+    input.pages.custom_domain != ""
+    input.pages.https_enforced == false
+}
@@ -12,6 +12,7 @@ package runner_group
 #       that create a workflow that exploits these vulnerabilities and move laterally inside your network.
 # custom:
 #   severity: HIGH
+#   tags: [runners, supply-chain]
 #   requiredEnrichers: [organizationId]
 #   requiredScopes: [admin:org]
 #   remediationSteps:
@@ -39,6 +40,7 @@ runner_group_can_be_used_by_public_repositories {
 #       malicious insider could create a repository with a workflow that exploits the runner's vulnerabilities to move laterally inside your network.
 # custom:
 #   severity: MEDIUM
+#   tags: [runners, least-privilege]
 #   requiredEnrichers: [organizationId]
 #   requiredScopes: [admin:org]
 #   remediationSteps:
@@ -55,3 +57,83 @@ default runner_group_not_limited_to_selected_repositories = false
 runner_group_not_limited_to_selected_repositories {
     input.runner_group.visibility != "selected"
 }
+
+# a group is exposed to many repositories unless it is limited to a few selected ones
+exposed_to_many_repositories(group) {
+    group.runner_group.visibility != "selected"
+}
+
+exposed_to_many_repositories(group) {
+    group.selected_repositories_count > 10
+}
+
+restricted_to_workflows(group) {
+    group.workflow_restrictions.restricted_to_workflows == true
+}
+
+# METADATA
+# scope: rule
+# title: Runner group exposes persistent runners to many repositories
+# description: |
+#       Persistent (non-ephemeral) self-hosted runners keep their state between jobs.
+#       When such runners are shared by many repositories (the group isn't limited to a few selected repositories),
+#       a single compromised workflow can tamper with the runner and affect the builds, and secrets, of every other repository using it.
+# custom:
+#   severity: MEDIUM
+#   tags: [runners, supply-chain]
+#   requiredEnrichers: [organizationId, runnersList]
+#   requiredScopes: [admin:org]
+#   remediationSteps:
+#     - "Register the group's self-hosted runners as ephemeral runners (using the '--ephemeral' flag), so each runner runs a single job"
+#     - "Or, go to the organization settings page"
+#     - "Go to Actions ➝ Runner groups"
+#     - "Under the 'Repository Access' section, select 'Selected repositories' and limit the group to the repositories that need it"
+#   threat:
+#     - "A persistent runner that is shared by many repositories is a single point of compromise:"
+#     - "1. An attacker gains control of a workflow of one of the repositories"
+#     - "2. The workflow installs a backdoor on the runner"
+#     - "3. The backdoor tampers with the artifacts and steals the secrets of the following jobs, of all the repositories using the group"
+runner_group_persistent_runners_exposed_to_many_repositories[violated] = true {
+    exposed_to_many_repositories(input)
+    some index
+    runner := input.runners[index]
+    not runner.ephemeral
+    violated := {
+        "name": runner.name,
+        "os": runner.os,
+        "status": runner.status
+    }
+}
+
+# METADATA
+# scope: rule
+# title: Runner can be targeted by the generic self-hosted label
+# description: |
+#       Runners that only have the default labels (e.g. 'self-hosted', 'linux', 'x64') are picked by any workflow that targets the generic 'self-hosted' label.
+#       Unless the runner group is restricted to selected workflows, any workflow of the repositories that can use the group could end up running on these runners,
+#       instead of only the workflows that were meant to run on them.
+# custom:
+#   severity: LOW
+#   tags: [runners, least-privilege]
+#   requiredEnrichers: [organizationId, runnersList]
+#   requiredScopes: [admin:org]
+#   remediationSteps:
+#     - "Add custom labels to the self-hosted runners, and target these labels in the workflows that should run on them"
+#     - "Or, go to the organization settings page"
+#     - "Go to Actions ➝ Runner groups"
+#     - "Under the 'Workflow Access' section, select 'Selected workflows' and add the workflows that should run on the group"
+#   threat:
+#     - "Any workflow using 'runs-on: self-hosted' can run on the runner, including workflows that should have used GitHub-hosted runners,"
+#     - "which exposes the runner's network and credentials to code it was never meant to run."
+runner_group_runner_uses_only_default_labels[violated] = true {
+    not restricted_to_workflows(input)
+    some index
+    runner := input.runners[index]
+    custom_labels := [label | label := runner.labels[_]; label.type == "custom"]
+    count(custom_labels) == 0
+    violated := {
+        "name": runner.name,
+        "os": runner.os,
+        "labels": concat(", ", [label.name | label := runner.labels[_]])
+    }
+}
@@ -6,6 +6,7 @@ package organization
 # description: The two-factor authentication requirement is not enabled at the group level. Regardless of whether users are managed externally by SSO, it is highly recommended to enable this option, to reduce the risk of a deliberate or accidental user creation without MFA.
 # custom:
 #   severity: HIGH
+#   tags: [authentication, access-control]
 #   remediationSteps:
 #     - Go to the group page
 #     - Press Settings -> General
@@ -25,6 +26,7 @@ two_factor_authentication_not_required_for_group {
 # description: The ability to fork project to external namespaces is turned on. Forking repositories poses security issues due to the loss of control over the code. It is recommended to disable this feature if it is not explicitly needed, in order to proactively prevent code leakage.
 # custom:
 #   severity: MEDIUM
+#   tags: [data-exposure]
 #   remediationSteps:
 #     - "Go to the top-level groups Settings > General page"
 #     - "Expand the Permissions and group features section"
@@ -43,6 +45,7 @@ collaborators_can_fork_repositories_to_external_namespaces {
 # description: Webhooks that are not configured with SSL enabled could expose your software to man in the middle attacks (MITM).
 # custom:
 #   severity: LOW
+#   tags: [webhooks]
 #   requiredEnrichers: [hooksList]
 #   remediationSteps:
 #     - Go to the group Settings -> Webhooks page
@@ -62,6 +65,7 @@ organization_webhook_doesnt_require_ssl[violation] = true {
 # description: You do not have a default full branch protection for a specific group, which means any new repository will be created without it. In fully protected level, developers cannot push new commits, and no one can force push or delete the branch. Protecting branches ensures new code changes must go through a controlled merge process and it allows enforcement of code review and other security tests.
 # custom:
 #   severity: MEDIUM
+#   tags: [branch-protection, supply-chain]
 #   remediationSteps:
 #     - Go to the group page
 #     - Press Settings -> Repository
@@ -74,4 +78,371 @@ organization_webhook_doesnt_require_ssl[violation] = true {
 default group_does_not_enforce_branch_protection_by_default  = false
 group_does_not_enforce_branch_protection_by_default {
     input.default_branch_protection == 0
-}
\ No newline at end of file
+}
+# METADATA
+# scope: rule
+# title: Secret CI/CD Variable Is Not Protected Or Masked
+# description: A CI/CD variable of the group whose name suggests it holds a secret (e.g. a token, a password or a private key) isn't protected or isn't masked. Unprotected variables are available to pipelines of every branch of the group's projects, so any developer can push a branch whose pipeline prints or exfiltrates them, and unmasked variables are printed as-is in the job logs. The metadata of the group's CI/CD variables (never their values) is available to custom policies as input.variables.
+# custom:
+#   severity: MEDIUM
+#   tags: [secret-management, data-exposure]
+#   remediationSteps:
+#     - Go to the group page
+#     - Press Settings -> CI/CD
+#     - Expand "Variables"
+#     - Edit the reported variable
+#     - Check "Protect variable" and "Mask variable"
+#     - Press "Update variable"
+#   threat:
+#     - A developer pushes a branch with a pipeline that sends the group's registry token to an external server, and uses it to publish malicious packages.
+secret_variable_not_protected_or_masked[violated] = true {
+    variable := input.variables[_]
+    secret_like_key(variable.key)
+    not all([variable.protected, variable.masked])
+    violated := {
+        "key": variable.key,
+        "protected": variable.protected,
+        "masked": variable.masked
+    }
+}
+
+# METADATA
+# scope: rule
+# title: Secret CI/CD Variable Is Exposed To All Projects Of The Group
+# description: A CI/CD variable of the group whose name suggests it holds a secret is available to all environments, so the pipelines of every project in the group (and its subgroups) can read it. Group variables should only hold secrets that all the projects need; scope the secret to the environments that use it, or move it to the projects that need it.
+# custom:
+#   severity: LOW
+#   tags: [secret-management, least-privilege]
+#   remediationSteps:
+#     - Go to the group page
+#     - Press Settings -> CI/CD
+#     - Expand "Variables"
+#     - Edit the reported variable
+#     - Set its "Environment scope" to the environments that need it (or delete it and define it in the projects that need it)
+#     - Press "Update variable"
+#   threat:
+#     - An attacker who compromises the pipeline of an unimportant project in the group reads the production credentials that were defined for a different project.
+group_secret_variable_exposed_to_all_projects[violated] = true {
+    variable := input.variables[_]
+    secret_like_key(variable.key)
+    variable.environment_scope == "*"
+    violated := {
+        "key": variable.key
+    }
+}
+
+secret_like_key(key) {
+    regex.match(`(?i)(token|secret|passw(or)?d|api_?key|private_?key|credential)`, key)
+}
+
+# METADATA
+# scope: rule
+# title: Deploy Token Has Write Permissions
+# description: A deploy token of the group can write to its repositories or registries (it has the write_repository, write_registry or write_package_registry scope). Deploy tokens aren't tied to a user, are often stored in external systems, and are meant for read-only access (e.g. cloning and pulling images); a leaked write-scoped token lets an attacker push code or publish malicious images and packages. The group's deploy tokens (never their values) are available to custom policies as input.deploy_tokens.
+# custom:
+#   severity: MEDIUM
+#   tags: [access-control, least-privilege, supply-chain]
+#   remediationSteps:
+#     - Go to the group page
+#     - Press Settings -> Repository
+#     - Expand "Deploy tokens"
+#     - Revoke the reported token, and create a new one with the read scopes only (if it's still needed)
+#   threat:
+#     - An attacker who finds a deploy token in a CI system's configuration uses it to push a malicious image to the container registry, which is then deployed to production.
+deploy_token_has_write_permissions[violated] = true {
+    token := input.deploy_tokens[_]
+    scope := token.scopes[_]
+    scope == ["write_repository", "write_registry", "write_package_registry"][_]
+    violated := {
+        "token": token.name,
+        "scope": scope
+    }
+}
+
+# METADATA
+# scope: rule
+# title: Deploy Token Never Expires
+# description: A deploy token of the group doesn't have an expiration date, so it remains valid until it's revoked manually, even after the system it was created for is no longer in use. Expiring tokens limit the window in which a leaked token can be used. The group's deploy tokens (never their values) are available to custom policies as input.deploy_tokens.
+# custom:
+#   severity: LOW
+#   tags: [access-control]
+#   remediationSteps:
+#     - Go to the group page
+#     - Press Settings -> Repository
+#     - Expand "Deploy tokens"
+#     - Revoke the reported token, and create a new one with an expiration date (if it's still needed)
+#   threat:
+#     - A deploy token that was created for a decommissioned build server years ago leaks from an old backup and still grants access to the group's code.
+deploy_token_never_expires[violated] = true {
+    token := input.deploy_tokens[_]
+    object.get(token, "expires_at", "") == ""
+    violated := {
+        "token": token.name
+    }
+}
+
+# METADATA
+# scope: rule
+# title: Group Members Without A Linked SSO Identity
+# description: SAML single sign-on is configured for the group, but some of its members haven't linked their identity provider account. Unless SSO is enforced, these members access the group with their GitLab credentials only, so they aren't subject to the identity provider's authentication policies, and removing them from the identity provider doesn't revoke their access. Whether SSO is enforced isn't exposed by the API. The group's SSO status is available to custom policies as input.saml_sso.
+# custom:
+#   severity: MEDIUM
+#   tags: [authentication, access-control]
+#   remediationSteps:
+#     - Ask the reported members to link their identity provider account (or remove them from the group)
+#     - Go to the group page
+#     - Press Settings -> SAML SSO
+#     - Check "Enforce SSO-only authentication for web activity for this group" and "Enforce SSO-only authentication for Git and Dependency Proxy activity for this group"
+#     - Press "Save changes"
+#   threat:
+#     - A former employee who was removed from the identity provider keeps accessing the group's projects with their GitLab credentials.
+members_without_linked_sso_identity[violated] = true {
+    input.saml_sso.configured
+    username := input.saml_sso.unlinked_usernames[_]
+    violated := {
+        "username": username
+    }
+}
+
+# METADATA
+# scope: rule
+# title: SCIM Provisioning Is Not Configured For The Group
+# description: SAML single sign-on is configured for the group, but no identities were provisioned with SCIM. Without SCIM, deprovisioning a user in the identity provider doesn't remove their membership in the group, so leavers keep their access until they're removed manually. The group's SSO status is available to custom policies as input.saml_sso.
+# custom:
+#   severity: LOW
+#   tags: [access-control]
+#   remediationSteps:
+#     - Go to the group page
+#     - Press Settings -> SAML SSO
+#     - Under "SCIM Token", generate a SCIM token
+#     - Configure SCIM provisioning in the identity provider with the token and the SCIM API endpoint URL
+#   threat:
+#     - An employee leaves the company and is deprovisioned in the identity provider, but remains a member of the group and keeps accessing it with a personal access token.
+default scim_provisioning_not_configured = false
+scim_provisioning_not_configured {
+    input.saml_sso.configured
+    not is_null(input.saml_sso.scim_identities)
+    input.saml_sso.scim_identities == 0
+}
+
+# METADATA
+# scope: rule
+# title: Approvals Are Not Removed When New Commits Are Pushed
+# description: Merge request approvals in the group's projects are kept when new commits are pushed to the merge request, so code that was pushed after the approval can be merged without being reviewed. The group's approval settings are available to custom policies as input.approval_settings. Group approval settings are available on GitLab Premium, and apply to all the group's projects.
+# custom:
+#   severity: LOW
+#   tags: [code-review]
+#   remediationSteps:
+#     - Go to the group page
+#     - Press Settings -> General
+#     - Expand "Merge request approvals"
+#     - Under "Approval settings", check "Remove all approvals when commits are added to the source branch"
+#     - Press "Save changes"
+#   threat:
+#     - A developer gets their merge request approved, pushes a malicious commit to it and merges it without another review.
+default approvals_not_reset_on_push = false
+approvals_not_reset_on_push {
+    input.approval_settings.reset_approvals_on_push == false
+}
+
+# METADATA
+# scope: rule
+# title: Merge Request Authors Can Approve Their Own Merge Requests
+# description: The group allows the author of a merge request to approve it, so a single user can satisfy the required approvals of their own change. In order to comply with the separation of duties principle, a merge request should be approved by someone other than its author. Group approval settings are available on GitLab Premium, and apply to all the group's projects.
+# custom:
+#   severity: MEDIUM
+#   tags: [code-review]
+#   remediationSteps:
+#     - Go to the group page
+#     - Press Settings -> General
+#     - Expand "Merge request approvals"
+#     - Under "Approval settings", check "Prevent approval by author"
+#     - Press "Save changes"
+#   threat:
+#     - A user with a compromised account opens a merge request with malicious code, approves it by themselves and merges it.
+default author_can_approve_merge_request = false
+author_can_approve_merge_request {
+    input.approval_settings.prevent_author_approval == false
+}
+
+# METADATA
+# scope: rule
+# title: Committers Can Approve Merge Requests They Committed To
+# description: The group allows users who added commits to a merge request to approve it, so a user can push code to someone else's merge request and approve it, bypassing the separation between the code's author and its reviewer. Group approval settings are available on GitLab Premium, and apply to all the group's projects.
+# custom:
+#   severity: MEDIUM
+#   tags: [code-review]
+#   remediationSteps:
+#     - Go to the group page
+#     - Press Settings -> General
+#     - Expand "Merge request approvals"
+#     - Under "Approval settings", check "Prevent approvals by users who add commits"
+#     - Press "Save changes"
+#   threat:
+#     - A developer pushes a malicious commit to a colleague's merge request and approves the merge request themselves, so the malicious code is never reviewed by anyone else.
+default committers_can_approve_merge_request = false
+committers_can_approve_merge_request {
+    input.approval_settings.prevent_committers_approval == false
+}
+
+# METADATA
+# scope: rule
+# title: Access Token Never Expires
+# description: An active group access token has no expiration date, so it stays valid until it's revoked manually, and a leaked token grants access indefinitely. The group's active access tokens are available to custom policies as input.access_tokens.
+# custom:
+#   severity: MEDIUM
+#   tags: [access-control]
+#   remediationSteps:
+#     - Go to the group page
+#     - Press Settings -> Access Tokens
+#     - Create a replacement token with an expiration date and update the integrations that use the reported token
+#     - Revoke the reported token
+#   threat:
+#     - A token that was committed to a repository years ago is found by an attacker, and still grants access since it never expires.
+access_token_never_expires[violated] = true {
+    token := input.access_tokens[_]
+    object.get(token, "expires_at", "") == ""
+    violated := {
+        "token": token.name
+    }
+}
+
+# METADATA
+# scope: rule
+# title: Access Token Has Excessive Permissions
+# description: An active group access token has the full API scope (api, sudo or admin_mode) or the owner role. Tokens are usually used by integrations that need a narrow set of permissions (e.g. read_repository or read_api), so a token with full access widens the impact of its leakage for no reason.
+# custom:
+#   severity: MEDIUM
+#   tags: [access-control, least-privilege]
+#   remediationSteps:
+#     - Go to the group page
+#     - Press Settings -> Access Tokens
+#     - Create a replacement token with the narrowest role and scopes the integration needs
+#     - Update the integration to use the new token and revoke the reported token
+#   threat:
+#     - A token used by a CI integration to read the repository leaks, and since it has the api scope, the attacker uses it to change the group's settings and push malicious code.
+access_token_has_excessive_scopes[violated] = true {
+    token := input.access_tokens[_]
+    excessive_token_permissions(token)
+    violated := {
+        "token": token.name,
+        "scopes": token.scopes,
+        "access_level": token.access_level
+    }
+}
+
+excessive_token_permissions(token) {
+    token.scopes[_] == ["api", "sudo", "admin_mode"][_]
+}
+
+excessive_token_permissions(token) {
+    token.access_level >= 50
+}
+
+# METADATA
+# scope: rule
+# title: Access Token Lifetime Is Not Enforced
+# description: The GitLab instance doesn't limit the lifetime of personal, project and group access tokens, so users can create tokens that stay valid for years (or forever, on versions before GitLab 16.0). This setting is collected only when legitify runs with the token of an instance administrator, and is enforced on GitLab Ultimate.
+# custom:
+#   severity: LOW
+#   tags: [access-control]
+#   remediationSteps:
+#     - Go to the Admin Area
+#     - Press Settings -> General
+#     - Expand "Account and limit"
+#     - Set "Maximum allowable lifetime for access tokens (days)"
+#     - Press "Save changes"
+#   threat:
+#     - A user creates a token that never expires for a script, and the token keeps working long after the user left the company and the script was forgotten.
+default access_token_lifetime_not_enforced = false
+access_token_lifetime_not_enforced {
+    input.max_access_token_lifetime == 0
+}
+
+# METADATA
+# scope: rule
+# title: Group Member Was Granted A Privileged Role
+# description: A member was added to the group as a Maintainer or an Owner, or their role was raised to Maintainer or Owner, during the collected period of the group audit events (see --audit-log-activity-days). Privileged role grants should be rare and intentional, so each one is reported for review. The summarized audit events are available to custom policies as input.audit_events (on GitLab Premium).
+# custom:
+#   severity: LOW
+#   tags: [access-control, monitoring]
+#   remediationSteps:
+#     - Verify the reported role grant was approved
+#     - If it wasn't, go to the group page
+#     - Press Manage -> Members
+#     - Lower the member's role or remove them from the group
+#   prerequisites: [premium]
+#   threat:
+#     - An attacker who compromised an owner account grants the Owner role to an account they control, to keep their access after the compromised account is recovered.
+member_granted_privileged_role[violated] = true {
+    change := input.audit_events.permission_changes[_]
+    privileged_role(change.to)
+    violated := {
+        "member": change.target,
+        "author": change.author,
+        "from": change.from,
+        "to": change.to,
+        "created_at": change.created_at
+    }
+}
+
+privileged_role(role) {
+    lower(role) == ["maintainer", "owner"][_]
+}
+
+# METADATA
+# scope: rule
+# title: Group Or Project Was Made Public
+# description: The visibility of the group or one of its projects was changed to public during the collected period of the group audit events (see --audit-log-activity-days). Making code public by mistake is one of the most common causes of source code and secret leakage, so each such change is reported for review.
+# custom:
+#   severity: MEDIUM
+#   tags: [data-exposure, monitoring]
+#   remediationSteps:
+#     - Verify the reported visibility change was intended
+#     - If it wasn't, go to the group (or project) page
+#     - Press Settings -> General
+#     - Expand "Visibility, project features, permissions" and change the visibility back to private or internal
+#     - Rotate the secrets that were exposed in the code while it was public
+#   prerequisites: [premium]
+#   threat:
+#     - A developer makes an internal project public to share it with a vendor, exposing the credentials committed to its history to the whole internet.
+visibility_changed_to_public[violated] = true {
+    change := input.audit_events.visibility_changes[_]
+    lower(change.to) == "public"
+    violated := {
+        "entity": change.target,
+        "author": change.author,
+        "from": change.from,
+        "created_at": change.created_at
+    }
+}
+
+# METADATA
+# scope: rule
+# title: Dormant Group Member Found
+# description: A group member didn't author any group audit event during the collected period (see --audit-log-activity-days). Group audit events only record administrative actions (e.g. membership, settings and protection changes), so members who only push code and review merge requests are reported as well; review the reported members before removing their access. Dormant accounts are rarely monitored by their owners, so their compromise may go unnoticed.
+# custom:
+#   severity: LOW
+#   tags: [access-control, offboarding]
+#   remediationSteps:
+#     - Verify the member no longer needs access (e.g. by their last activity in the Admin Area or their profile)
+#     - Go to the group page
+#     - Press Manage -> Members
+#     - Remove the member from the group
+#   prerequisites: [premium]
+#   threat:
+#     - The account of a member who no longer uses it is compromised (e.g. by a reused password), and the attacker's access to the group goes unnoticed.
+dormant_member_found[violated] = true {
+    username := input.audit_events.inactive_members[_]
+    violated := {
+        "username": username
+    }
+}
+
+# Audit events only record administrative actions, so members without any event may still be active
+policy_confidence["dormant_member_found"] = {
+    "level": "low",
+    "reason": "partial data: group audit events only record administrative actions"
+} {
+    input.audit_events.window_days > 0
+}
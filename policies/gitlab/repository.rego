@@ -0,0 +1,334 @@
+package repository
+
+# METADATA
+# scope: rule
+# title: Production Environment Can Be Deployed Without Approval
+# description: A production environment of the project doesn't require approvals for deployments, so anyone who can run a pipeline that deploys to it (or, if it isn't protected, any developer of the project) can deploy unreviewed changes to production. Protecting the environment and requiring deployment approvals ensures that production deployments are approved by someone other than the deployer. Protected environments are available on GitLab Premium.
+# custom:
+#   severity: MEDIUM
+#   tags: [access-control, supply-chain]
+#   remediationSteps:
+#     - Go to the project page
+#     - Press Settings -> CI/CD
+#     - Expand "Protected environments"
+#     - Protect the production environment, choosing who can deploy to it
+#     - Set the number of "Required approvals" (and who can approve)
+#   threat:
+#     - A developer (or an attacker with a developer's credentials) runs a pipeline that deploys a malicious change to production without anyone approving it.
+production_environment_deployable_without_approval[violated] = true {
+    env := input.environments[_]
+    env.tier == "production"
+    env.required_approval_count == 0
+    violated := {
+        "environment": env.name,
+        "protected": env.protected
+    }
+}
+
+# METADATA
+# scope: rule
+# title: Secret CI/CD Variable Is Not Protected Or Masked
+# description: A CI/CD variable of the project whose name suggests it holds a secret (e.g. a token, a password or a private key) isn't protected or isn't masked. Unprotected variables are available to pipelines of every branch, so any developer can push a branch whose pipeline prints or exfiltrates them, and unmasked variables are printed as-is in the job logs. The metadata of the project's CI/CD variables (never their values) is available to custom policies as input.variables.
+# custom:
+#   severity: MEDIUM
+#   tags: [secret-management, data-exposure]
+#   remediationSteps:
+#     - Go to the project page
+#     - Press Settings -> CI/CD
+#     - Expand "Variables"
+#     - Edit the reported variable
+#     - Check "Protect variable" and "Mask variable"
+#     - Press "Update variable"
+#   threat:
+#     - A developer pushes a branch with a pipeline that sends the project's deployment token to an external server, and uses it to access production.
+secret_variable_not_protected_or_masked[violated] = true {
+    variable := input.variables[_]
+    secret_like_key(variable.key)
+    not all([variable.protected, variable.masked])
+    violated := {
+        "key": variable.key,
+        "protected": variable.protected,
+        "masked": variable.masked
+    }
+}
+
+secret_like_key(key) {
+    regex.match(`(?i)(token|secret|passw(or)?d|api_?key|private_?key|credential)`, key)
+}
+
+# METADATA
+# scope: rule
+# title: Deploy Token Has Write Permissions
+# description: A deploy token of the project can write to its repositories or registries (it has the write_repository, write_registry or write_package_registry scope). Deploy tokens aren't tied to a user, are often stored in external systems, and are meant for read-only access (e.g. cloning and pulling images); a leaked write-scoped token lets an attacker push code or publish malicious images and packages. The project's deploy tokens (never their values) are available to custom policies as input.deploy_tokens.
+# custom:
+#   severity: MEDIUM
+#   tags: [access-control, least-privilege, supply-chain]
+#   remediationSteps:
+#     - Go to the project page
+#     - Press Settings -> Repository
+#     - Expand "Deploy tokens"
+#     - Revoke the reported token, and create a new one with the read scopes only (if it's still needed)
+#   threat:
+#     - An attacker who finds a deploy token in a CI system's configuration uses it to push a malicious image to the container registry, which is then deployed to production.
+deploy_token_has_write_permissions[violated] = true {
+    token := input.deploy_tokens[_]
+    scope := token.scopes[_]
+    scope == ["write_repository", "write_registry", "write_package_registry"][_]
+    violated := {
+        "token": token.name,
+        "scope": scope
+    }
+}
+
+# METADATA
+# scope: rule
+# title: Deploy Token Never Expires
+# description: A deploy token of the project doesn't have an expiration date, so it remains valid until it's revoked manually, even after the system it was created for is no longer in use. Expiring tokens limit the window in which a leaked token can be used. The project's deploy tokens (never their values) are available to custom policies as input.deploy_tokens.
+# custom:
+#   severity: LOW
+#   tags: [access-control]
+#   remediationSteps:
+#     - Go to the project page
+#     - Press Settings -> Repository
+#     - Expand "Deploy tokens"
+#     - Revoke the reported token, and create a new one with an expiration date (if it's still needed)
+#   threat:
+#     - A deploy token that was created for a decommissioned build server years ago leaks from an old backup and still grants access to the project's code.
+deploy_token_never_expires[violated] = true {
+    token := input.deploy_tokens[_]
+    object.get(token, "expires_at", "") == ""
+    violated := {
+        "token": token.name
+    }
+}
+
+# METADATA
+# scope: rule
+# title: High-Sensitivity Project Uses Shared Runners
+# description: The project's group was classified as high-sensitivity (with --high-sensitivity-orgs), but shared runners are enabled for the project, so its jobs (with its code and CI/CD secrets) can run on runners that are shared with other projects of the instance. Sensitive projects should run their jobs on dedicated group or project runners. The runners available to the project are available to custom policies as input.runners.
+# custom:
+#   severity: MEDIUM
+#   tags: [runners, data-exposure]
+#   remediationSteps:
+#     - Register dedicated group or project runners for the project
+#     - Go to the project page
+#     - Press Settings -> CI/CD
+#     - Expand "Runners"
+#     - Disable "Enable shared runners for this project"
+#   threat:
+#     - A malicious job of another project escapes its container on a shared runner and reads the sensitive project's code and secrets from the jobs that run on the same machine.
+default high_sensitivity_project_uses_shared_runners = false
+high_sensitivity_project_uses_shared_runners {
+    input.high_sensitivity
+    input.shared_runners_enabled
+}
+
+# METADATA
+# scope: rule
+# title: Pipeline Schedule Is Owned By An Inactive User
+# description: An active pipeline schedule of the project is owned by a user who is blocked or deactivated, or who is no longer a member of the project. Scheduled pipelines run with their owner's permissions, so they either fail silently or keep running with the permissions of someone who left. The project's pipeline schedules are available to custom policies as input.pipeline_schedules.
+# custom:
+#   severity: LOW
+#   tags: [access-control]
+#   remediationSteps:
+#     - Go to the project page
+#     - Press Build -> Pipeline schedules
+#     - Press "Take ownership" on the reported schedule (or delete it if it isn't needed)
+#   threat:
+#     - A nightly deployment pipeline keeps running with the permissions of a contractor whose engagement ended, and breaks silently once their account is blocked.
+pipeline_schedule_owner_is_inactive[violated] = true {
+    schedule := input.pipeline_schedules[_]
+    schedule.active
+    reason := inactive_owner_reason(schedule)
+    violated := {
+        "schedule": schedule.description,
+        "owner": schedule.owner,
+        "reason": reason
+    }
+}
+
+inactive_owner_reason(schedule) = reason {
+    schedule.owner_state != "active"
+    reason := sprintf("the owner is %s", [schedule.owner_state])
+}
+
+inactive_owner_reason(schedule) = "the owner is not a member of the project" {
+    schedule.owner_state == "active"
+    not schedule.owner_is_member
+}
+
+# METADATA
+# scope: rule
+# title: Container Registry Has No Cleanup Policy
+# description: The project's container registry is enabled, but its cleanup policy is disabled, so old image tags are kept forever. Stale images keep outdated dependencies with known vulnerabilities available for pulling, and the registry storage grows indefinitely. The container registry settings are available to custom policies as input.container_registry.
+# custom:
+#   severity: LOW
+#   tags: [container-registry]
+#   remediationSteps:
+#     - Go to the project page
+#     - Press Settings -> Packages and registries
+#     - Under "Cleanup policies", press "Set cleanup rules"
+#     - Enable the cleanup policy and set the tags to keep and to remove
+#   threat:
+#     - An old image with a vulnerable base layer is still pulled by a forgotten deployment, because nothing ever removed it from the registry.
+default container_registry_cleanup_policy_disabled = false
+container_registry_cleanup_policy_disabled {
+    input.container_registry.access_level != "disabled"
+    not input.container_registry.cleanup_policy_enabled
+}
+
+# METADATA
+# scope: rule
+# title: Container Registry Tags Are Not Protected
+# description: The project's container registry is enabled, but none of its image tags are protected or immutable, so every developer of the project can overwrite or delete any image tag (including the tags deployed to production). Tag protection rules are available since GitLab 17.8, and are not checked on older versions.
+# custom:
+#   severity: MEDIUM
+#   tags: [container-registry, supply-chain]
+#   remediationSteps:
+#     - Go to the project page
+#     - Press Settings -> Packages and registries
+#     - Under "Container registry", expand "Protected container tags"
+#     - Add a rule for the release tags (e.g. v*) with a minimum access level of Maintainer, or make them immutable
+#   threat:
+#     - A developer (or a stolen developer token) pushes a malicious image over an existing release tag, and the next deployment pulls it instead of the reviewed image.
+default container_registry_tags_not_protected = false
+container_registry_tags_not_protected {
+    input.container_registry.access_level != "disabled"
+    is_array(input.container_registry.protected_tag_patterns)
+    count(input.container_registry.protected_tag_patterns) == 0
+    count(input.container_registry.immutable_tag_patterns) == 0
+}
+
+# METADATA
+# scope: rule
+# title: Project Releases Are Created From Unprotected Tags
+# description: Some of the latest releases of the project were created from tags that don't match any protected tag pattern. Every developer of the project can create, move (delete and recreate) or delete unprotected tags, so the code a release points to can be replaced after it was reviewed. The project's protected tags and latest releases are available to custom policies as input.protected_tags and input.releases.
+# custom:
+#   severity: MEDIUM
+#   tags: [supply-chain]
+#   remediationSteps:
+#     - Go to the project page
+#     - Press Settings -> Repository
+#     - Expand "Protected tags"
+#     - Protect the release tags pattern (e.g. v*) and allow only Maintainers to create them
+#   threat:
+#     - A developer deletes a release tag and recreates it on an unreviewed commit, so the pipelines and users that consume the release get the unreviewed code.
+release_tag_not_protected[violated] = true {
+    release := input.releases[_]
+    not release.tag_protected
+    violated := {
+        "release": release.name,
+        "tag": release.tag_name
+    }
+}
+
+# METADATA
+# scope: rule
+# title: Approvals Are Not Removed When New Commits Are Pushed
+# description: The project's merge request approvals are kept when new commits are pushed to the merge request, so code that was pushed after the approval can be merged without being reviewed. The project's approval settings are available to custom policies as input.approval_settings.
+# custom:
+#   severity: LOW
+#   tags: [code-review]
+#   remediationSteps:
+#     - Go to the project page
+#     - Press Settings -> Merge requests
+#     - Under "Approval settings", check "Remove all approvals when commits are added to the source branch"
+#     - Press "Save changes"
+#   threat:
+#     - A developer gets their merge request approved, pushes a malicious commit to it and merges it without another review.
+default approvals_not_reset_on_push = false
+approvals_not_reset_on_push {
+    input.approval_settings.reset_approvals_on_push == false
+}
+
+# METADATA
+# scope: rule
+# title: Merge Request Authors Can Approve Their Own Merge Requests
+# description: The project allows the author of a merge request to approve it, so a single user can satisfy the required approvals of their own change. In order to comply with the separation of duties principle, a merge request should be approved by someone other than its author.
+# custom:
+#   severity: MEDIUM
+#   tags: [code-review]
+#   remediationSteps:
+#     - Go to the project page
+#     - Press Settings -> Merge requests
+#     - Under "Approval settings", check "Prevent approval by author"
+#     - Press "Save changes"
+#   threat:
+#     - A user with a compromised account opens a merge request with malicious code, approves it by themselves and merges it.
+default author_can_approve_merge_request = false
+author_can_approve_merge_request {
+    input.approval_settings.prevent_author_approval == false
+}
+
+# METADATA
+# scope: rule
+# title: Committers Can Approve Merge Requests They Committed To
+# description: The project allows users who added commits to a merge request to approve it, so a user can push code to someone else's merge request and approve it, bypassing the separation between the code's author and its reviewer.
+# custom:
+#   severity: MEDIUM
+#   tags: [code-review]
+#   remediationSteps:
+#     - Go to the project page
+#     - Press Settings -> Merge requests
+#     - Under "Approval settings", check "Prevent approvals by users who add commits"
+#     - Press "Save changes"
+#   threat:
+#     - A developer pushes a malicious commit to a colleague's merge request and approves the merge request themselves, so the malicious code is never reviewed by anyone else.
+default committers_can_approve_merge_request = false
+committers_can_approve_merge_request {
+    input.approval_settings.prevent_committers_approval == false
+}
+
+# METADATA
+# scope: rule
+# title: Access Token Never Expires
+# description: An active project access token has no expiration date, so it stays valid until it's revoked manually, and a leaked token grants access indefinitely. The project's active access tokens are available to custom policies as input.access_tokens.
+# custom:
+#   severity: MEDIUM
+#   tags: [access-control]
+#   remediationSteps:
+#     - Go to the project page
+#     - Press Settings -> Access Tokens
+#     - Create a replacement token with an expiration date and update the integrations that use the reported token
+#     - Revoke the reported token
+#   threat:
+#     - A token that was committed to a repository years ago is found by an attacker, and still grants access since it never expires.
+access_token_never_expires[violated] = true {
+    token := input.access_tokens[_]
+    object.get(token, "expires_at", "") == ""
+    violated := {
+        "token": token.name
+    }
+}
+
+# METADATA
+# scope: rule
+# title: Access Token Has Excessive Permissions
+# description: An active project access token has the full API scope (api, sudo or admin_mode) or the owner role. Tokens are usually used by integrations that need a narrow set of permissions (e.g. read_repository or read_api), so a token with full access widens the impact of its leakage for no reason.
+# custom:
+#   severity: MEDIUM
+#   tags: [access-control, least-privilege]
+#   remediationSteps:
+#     - Go to the project page
+#     - Press Settings -> Access Tokens
+#     - Create a replacement token with the narrowest role and scopes the integration needs
+#     - Update the integration to use the new token and revoke the reported token
+#   threat:
+#     - A token used by a CI integration to read the repository leaks, and since it has the api scope, the attacker uses it to change the project's settings and push malicious code.
+access_token_has_excessive_scopes[violated] = true {
+    token := input.access_tokens[_]
+    excessive_token_permissions(token)
+    violated := {
+        "token": token.name,
+        "scopes": token.scopes,
+        "access_level": token.access_level
+    }
+}
+
+excessive_token_permissions(token) {
+    token.scopes[_] == ["api", "sudo", "admin_mode"][_]
+}
+
+excessive_token_permissions(token) {
+    token.access_level >= 50
+}
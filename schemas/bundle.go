@@ -0,0 +1,48 @@
+package schemas
+
+import (
+	"embed"
+	"fmt"
+
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme/converter"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// Bundle holds the published JSON schemas of the json output, one per output scheme (<scheme>.schema.json).
+// The schemas are generated by Generate (go generate ./schemas), so changes to the output show up in their diff.
+//
+//go:embed *.schema.json
+var Bundle embed.FS
+
+func FileName(schemeType converter.SchemeType) string {
+	return schemeType + ".schema.json"
+}
+
+// Get returns the JSON schema of the json output of the scheme type
+func Get(schemeType converter.SchemeType) ([]byte, error) {
+	schema, err := Bundle.ReadFile(FileName(schemeType))
+	if err != nil {
+		return nil, fmt.Errorf("no JSON schema for output scheme %s", schemeType)
+	}
+	return schema, nil
+}
+
+// Validate validates a json output against the JSON schema of its scheme type.
+// It returns the validation errors (none if the output is valid).
+func Validate(schemeType converter.SchemeType, output []byte) ([]string, error) {
+	schema, err := Get(schemeType)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(schema), gojsonschema.NewBytesLoader(output))
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate the output: %v", err)
+	}
+
+	var errors []string
+	for _, e := range result.Errors() {
+		errors = append(errors, e.String())
+	}
+	return errors, nil
+}
@@ -0,0 +1,26 @@
+// gen writes the JSON schemas of the output schemes into the schemas directory (run by go generate ./schemas)
+package main
+
+import (
+	"log"
+	"os"
+	"sort"
+
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme/converter"
+	"github.com/Legit-Labs/legitify/schemas"
+)
+
+func main() {
+	schemeTypes := converter.SchemeTypes()
+	sort.Strings(schemeTypes)
+
+	for _, schemeType := range schemeTypes {
+		schema, err := schemas.Generate(schemeType)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err = os.WriteFile(schemas.FileName(schemeType), schema, 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
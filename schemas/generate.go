@@ -0,0 +1,110 @@
+package schemas
+
+//go:generate go run ./gen
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Legit-Labs/legitify/internal/analyzers"
+	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/Legit-Labs/legitify/internal/common/severity"
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme/converter"
+)
+
+const draft = "http://json-schema.org/draft-07/schema#"
+
+type object = map[string]interface{}
+
+// policyData is the schema of the output of a single policy (scheme.OutputData).
+// Additional properties are allowed, so adding fields is a compatible change.
+func policyData() object {
+	return object{
+		"type":     "object",
+		"required": []string{"policyInfo", "violations"},
+		"properties": object{
+			"policyInfo": object{
+				"type":     "object",
+				"required": []string{"title", "description", "policyName", "fullyQualifiedPolicyName", "severity", "namespace"},
+				"properties": object{
+					"title":                    object{"type": "string"},
+					"description":              object{"type": "string"},
+					"policyName":               object{"type": "string"},
+					"fullyQualifiedPolicyName": object{"type": "string"},
+					"severity":                 object{"enum": []string{severity.Critical, severity.High, severity.Medium, severity.Low, severity.Unknown}},
+					"severityLabel":            object{"type": "string"},
+					"remediationSteps":         object{"type": []string{"array", "null"}, "items": object{"type": "string"}},
+					"namespace":                object{"enum": namespace.All},
+					"scanId":                   object{"type": "string"},
+				},
+			},
+			"violations": object{
+				"type": "array",
+				"items": object{
+					"type":     "object",
+					"required": []string{"violationEntityType", "canonicalLink", "Status"},
+					"properties": object{
+						"violationEntityType": object{"type": "string"},
+						"canonicalLink":       object{"type": "string"},
+						"aux":                 object{"type": []string{"object", "null"}},
+						"Status":              object{"enum": []string{analyzers.PolicyPassed, analyzers.PolicyFailed, analyzers.PolicySkipped, analyzers.PolicyWaived}},
+					},
+				},
+			},
+		},
+	}
+}
+
+// flattened maps the fully qualified policy names to their policy data
+func flattened() object {
+	return object{
+		"type":                 "object",
+		"additionalProperties": object{"$ref": "#/definitions/policyData"},
+	}
+}
+
+// groupBy maps the groups (e.g. namespaces) to the flattened output of their policies
+func groupBy() object {
+	return object{
+		"type":                 "object",
+		"additionalProperties": object{"$ref": "#/definitions/flattened"},
+	}
+}
+
+var descriptions = map[converter.SchemeType]string{
+	converter.Flattened:        "policies (by fully qualified policy name)",
+	converter.GroupByNamespace: "namespaces to the policies of the namespace",
+	converter.GroupByResource:  "resources (by canonical link) to the policies of the resource",
+	converter.GroupBySeverity:  "severities to the policies of the severity",
+}
+
+// Generate generates the JSON schema of the json output of the scheme type
+func Generate(schemeType converter.SchemeType) ([]byte, error) {
+	description, ok := descriptions[schemeType]
+	if !ok {
+		return nil, fmt.Errorf("no JSON schema for output scheme %s", schemeType)
+	}
+
+	schema := object{
+		"$schema":     draft,
+		"title":       fmt.Sprintf("legitify %s output", schemeType),
+		"description": fmt.Sprintf("The json output of the %s scheme, mapping the %s", schemeType, description),
+		"definitions": object{
+			"policyData": policyData(),
+			"flattened":  flattened(),
+		},
+	}
+	root := flattened()
+	if schemeType != converter.Flattened {
+		root = groupBy()
+	}
+	for k, v := range root {
+		schema[k] = v
+	}
+
+	bytes, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(bytes, '\n'), nil
+}
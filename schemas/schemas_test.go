@@ -0,0 +1,77 @@
+package schemas
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Legit-Labs/legitify/internal/analyzers"
+	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/Legit-Labs/legitify/internal/common/severity"
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme"
+	"github.com/Legit-Labs/legitify/internal/outputer/scheme/converter"
+	"github.com/stretchr/testify/require"
+)
+
+func outputSample() scheme.FlattenedScheme {
+	output := scheme.NewFlattenedScheme()
+	data := scheme.NewOutputData(scheme.PolicyInfo{
+		Title:                    "Default Branch Is Not Protected",
+		Description:              "description",
+		PolicyName:               "missing_default_branch_protection",
+		FullyQualifiedPolicyName: "data.repository.missing_default_branch_protection",
+		Severity:                 severity.Medium,
+		RemediationSteps:         []string{"step"},
+		Namespace:                namespace.Repository,
+		ScanID:                   "scan",
+	})
+	data = scheme.AppendViolations(data,
+		scheme.Violation{ViolationEntityType: namespace.Repository, CanonicalLink: "https://github.com/org/a", Status: analyzers.PolicyFailed},
+		scheme.Violation{ViolationEntityType: namespace.Repository, CanonicalLink: "https://github.com/org/b", Status: analyzers.PolicyPassed},
+	)
+	output.Set(data.PolicyInfo.FullyQualifiedPolicyName, data)
+	return output
+}
+
+func TestSchemasAreUpToDate(t *testing.T) {
+	for _, schemeType := range converter.SchemeTypes() {
+		generated, err := Generate(schemeType)
+		require.NoError(t, err)
+		published, err := Get(schemeType)
+		require.NoError(t, err)
+		require.Equal(t, string(generated), string(published), "%s is outdated, run: go generate ./schemas", FileName(schemeType))
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, schemeType := range converter.SchemeTypes() {
+		converted, err := converter.Convert(schemeType, outputSample())
+		require.NoError(t, err)
+		output, err := json.Marshal(converted)
+		require.NoError(t, err)
+
+		errors, err := Validate(schemeType, output)
+		require.NoError(t, err)
+		require.Empty(t, errors, schemeType)
+	}
+}
+
+func TestValidateInvalidOutput(t *testing.T) {
+	errors, err := Validate(converter.Flattened, []byte(`{"data.repository.policy": {"violations": [{"Status": "BROKEN"}]}}`))
+	require.NoError(t, err)
+	require.NotEmpty(t, errors)
+
+	// a group-by output isn't a valid flattened output
+	converted, err := converter.Convert(converter.GroupByNamespace, outputSample())
+	require.NoError(t, err)
+	output, err := json.Marshal(converted)
+	require.NoError(t, err)
+	errors, err = Validate(converter.Flattened, output)
+	require.NoError(t, err)
+	require.NotEmpty(t, errors)
+
+	_, err = Validate(converter.Flattened, []byte("not json"))
+	require.Error(t, err)
+
+	_, err = Validate(converter.Object, []byte("{}"))
+	require.Error(t, err)
+}
@@ -1,7 +1,9 @@
 package test
 
 import (
+	"fmt"
 	"github.com/Legit-Labs/legitify/internal/clients/github/types"
+	"github.com/Legit-Labs/legitify/internal/common/rotation"
 	"testing"
 
 	githubcollected "github.com/Legit-Labs/legitify/internal/collected/github"
@@ -14,25 +16,72 @@ type organizationActionsMockConfiguration struct {
 	enabledRepositories    *string
 	tokenDefaultPermission string
 	workflowsCanApprovePRs bool
+	oidcClaimKeys          []string
+	shaPinningRequired     bool
+	verifiedAllowed        bool
+	patternsAllowed        []string
+	secretsAgeDays         []int
+	requiredWorkflows      []types.RequiredWorkflow
+	workflowRulesets       []types.Ruleset
+	forkPRApprovalPolicy   *string
 }
 
 func newOrganizationActionsMock(config organizationActionsMockConfiguration) githubcollected.OrganizationActions {
+	var secrets []githubcollected.ActionsSecret
+	for i := range config.secretsAgeDays {
+		secrets = append(secrets, githubcollected.ActionsSecret{
+			Name:    fmt.Sprintf("SECRET_%d", i),
+			AgeDays: &config.secretsAgeDays[i],
+		})
+	}
+
 	return githubcollected.OrganizationActions{
 		Organization: defaultOrg,
-		ActionsPermissions: &github.ActionsPermissions{
+		ActionsPermissions: &types.ActionsPermissions{
 			EnabledRepositories: config.enabledRepositories,
 			AllowedActions:      config.allowedActions,
+			ShaPinningRequired:  &config.shaPinningRequired,
+		},
+		SelectedActions: &github.ActionsAllowed{
+			VerifiedAllowed: &config.verifiedAllowed,
+			PatternsAllowed: config.patternsAllowed,
 		},
 		TokenPermissions: &types.TokenPermissions{
 			DefaultWorkflowPermissions:   &config.tokenDefaultPermission,
 			CanApprovePullRequestReviews: &config.workflowsCanApprovePRs,
 		},
+		OIDCSubjectClaim: &types.OIDCSubjectClaim{
+			IncludeClaimKeys: config.oidcClaimKeys,
+		},
+		Secrets:           secrets,
+		SecretMaxAge:      rotation.DefaultMaxAge.PolicyInput(),
+		RequiredWorkflows: config.requiredWorkflows,
+		WorkflowRulesets:  config.workflowRulesets,
+		ForkPRApproval: &types.ForkPRContributorApproval{
+			ApprovalPolicy: config.forkPRApprovalPolicy,
+		},
+	}
+}
+
+func newWorkflowRuleset(enforcement string, include []string, exclude []string) types.Ruleset {
+	return types.Ruleset{
+		Name:        "security workflows",
+		Enforcement: enforcement,
+		Conditions: &types.RulesetConditions{
+			RepositoryName: &types.RulesetNameCondition{
+				Include: include,
+				Exclude: exclude,
+			},
+		},
+		Rules: []types.RulesetRule{{Type: "workflows"}},
 	}
 }
 
 func TestActions(t *testing.T) {
 	all := "all"
 	selected := "selected"
+	firstTimeContributors := "first_time_contributors"
+	allExternalContributors := "all_external_contributors"
 	tests := []struct {
 		name             string
 		policyName       string
@@ -107,6 +156,187 @@ func TestActions(t *testing.T) {
 				tokenDefaultPermission: "read",
 			},
 		},
+		{
+			name:             "OIDC subject claim is scoped to the repository owner only",
+			policyName:       "oidc_subject_claim_not_scoped_to_repository",
+			shouldBeViolated: true,
+			args: organizationActionsMockConfiguration{
+				oidcClaimKeys: []string{"repository_owner", "context"},
+			},
+		},
+		{
+			name:             "OIDC subject claim is scoped to the repository",
+			policyName:       "oidc_subject_claim_not_scoped_to_repository",
+			shouldBeViolated: false,
+			args: organizationActionsMockConfiguration{
+				oidcClaimKeys: []string{"repo", "context"},
+			},
+		},
+		{
+			name:             "OIDC subject claim is not scoped to the job context",
+			policyName:       "oidc_subject_claim_not_scoped_to_context",
+			shouldBeViolated: true,
+			args: organizationActionsMockConfiguration{
+				oidcClaimKeys: []string{"repo"},
+			},
+		},
+		{
+			name:             "OIDC subject claim is scoped to the job workflow",
+			policyName:       "oidc_subject_claim_not_scoped_to_context",
+			shouldBeViolated: false,
+			args: organizationActionsMockConfiguration{
+				oidcClaimKeys: []string{"repo", "job_workflow_ref"},
+			},
+		},
+		{
+			name:             "selected actions allow-list has a wildcard owner pattern",
+			policyName:       "selected_actions_allow_any_third_party_action",
+			shouldBeViolated: true,
+			args: organizationActionsMockConfiguration{
+				allowedActions:  &selected,
+				patternsAllowed: []string{"my-org/*", "*/*"},
+			},
+		},
+		{
+			name:             "selected actions allow-list has explicit owners only",
+			policyName:       "selected_actions_allow_any_third_party_action",
+			shouldBeViolated: false,
+			args: organizationActionsMockConfiguration{
+				allowedActions:  &selected,
+				patternsAllowed: []string{"my-org/*", "actions/checkout@v3"},
+			},
+		},
+		{
+			name:             "all actions are allowed without requiring SHA pinning",
+			policyName:       "third_party_actions_not_pinned",
+			shouldBeViolated: true,
+			args: organizationActionsMockConfiguration{
+				allowedActions: &all,
+			},
+		},
+		{
+			name:             "verified creators actions are allowed without requiring SHA pinning",
+			policyName:       "third_party_actions_not_pinned",
+			shouldBeViolated: true,
+			args: organizationActionsMockConfiguration{
+				allowedActions:  &selected,
+				verifiedAllowed: true,
+			},
+		},
+		{
+			name:             "all actions are allowed and SHA pinning is required",
+			policyName:       "third_party_actions_not_pinned",
+			shouldBeViolated: false,
+			args: organizationActionsMockConfiguration{
+				allowedActions:     &all,
+				shaPinningRequired: true,
+			},
+		},
+		{
+			name:             "only explicitly selected actions are allowed",
+			policyName:       "third_party_actions_not_pinned",
+			shouldBeViolated: false,
+			args: organizationActionsMockConfiguration{
+				allowedActions:  &selected,
+				patternsAllowed: []string{"my-org/*"},
+			},
+		},
+		{
+			name:             "organization secret is older than the medium severity max age",
+			policyName:       "organization_secret_not_rotated",
+			shouldBeViolated: true,
+			args: organizationActionsMockConfiguration{
+				secretsAgeDays: []int{10, 200},
+			},
+		},
+		{
+			name:             "organization secrets are rotated",
+			policyName:       "organization_secret_not_rotated",
+			shouldBeViolated: false,
+			args: organizationActionsMockConfiguration{
+				secretsAgeDays: []int{10, 179},
+			},
+		},
+		{
+			name:             "organization secret older than the high severity max age is reported once",
+			policyName:       "organization_secret_not_rotated",
+			shouldBeViolated: false,
+			args: organizationActionsMockConfiguration{
+				secretsAgeDays: []int{400},
+			},
+		},
+		{
+			name:             "organization secret is older than the high severity max age",
+			policyName:       "organization_secret_not_rotated_for_long",
+			shouldBeViolated: true,
+			args: organizationActionsMockConfiguration{
+				secretsAgeDays: []int{400},
+			},
+		},
+		{
+			name:             "organization secret is younger than the high severity max age",
+			policyName:       "organization_secret_not_rotated_for_long",
+			shouldBeViolated: false,
+			args: organizationActionsMockConfiguration{
+				secretsAgeDays: []int{200},
+			},
+		},
+		{
+			name:             "required workflow is enforced on selected repositories",
+			policyName:       "required_workflow_not_enforced_on_all_repositories",
+			shouldBeViolated: true,
+			args: organizationActionsMockConfiguration{
+				requiredWorkflows: []types.RequiredWorkflow{{Name: "codeql", Path: ".github/workflows/codeql.yml", Scope: "selected"}},
+			},
+		},
+		{
+			name:             "required workflow is enforced on all repositories",
+			policyName:       "required_workflow_not_enforced_on_all_repositories",
+			shouldBeViolated: false,
+			args: organizationActionsMockConfiguration{
+				requiredWorkflows: []types.RequiredWorkflow{{Name: "codeql", Path: ".github/workflows/codeql.yml", Scope: "all"}},
+			},
+		},
+		{
+			name:             "workflow ruleset is in evaluate mode",
+			policyName:       "required_workflow_not_enforced_on_all_repositories",
+			shouldBeViolated: true,
+			args: organizationActionsMockConfiguration{
+				workflowRulesets: []types.Ruleset{newWorkflowRuleset("evaluate", []string{"~ALL"}, nil)},
+			},
+		},
+		{
+			name:             "workflow ruleset excludes repositories",
+			policyName:       "required_workflow_not_enforced_on_all_repositories",
+			shouldBeViolated: true,
+			args: organizationActionsMockConfiguration{
+				workflowRulesets: []types.Ruleset{newWorkflowRuleset("active", []string{"~ALL"}, []string{"sandbox-*"})},
+			},
+		},
+		{
+			name:             "workflow ruleset is active on all repositories",
+			policyName:       "required_workflow_not_enforced_on_all_repositories",
+			shouldBeViolated: false,
+			args: organizationActionsMockConfiguration{
+				workflowRulesets: []types.Ruleset{newWorkflowRuleset("active", []string{"~ALL"}, nil)},
+			},
+		},
+		{
+			name:             "fork pull requests of returning contributors run workflows without approval",
+			policyName:       "fork_pull_request_workflows_run_without_approval",
+			shouldBeViolated: true,
+			args: organizationActionsMockConfiguration{
+				forkPRApprovalPolicy: &firstTimeContributors,
+			},
+		},
+		{
+			name:             "fork pull requests of all external contributors require approval",
+			policyName:       "fork_pull_request_workflows_run_without_approval",
+			shouldBeViolated: false,
+			args: organizationActionsMockConfiguration{
+				forkPRApprovalPolicy: &allExternalContributors,
+			},
+		},
 	}
 
 	for _, test := range tests {
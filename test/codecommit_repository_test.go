@@ -0,0 +1,35 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/Legit-Labs/legitify/internal/collected/codecommit_collected"
+	"github.com/Legit-Labs/legitify/internal/common/namespace"
+)
+
+func codecommitRepositoryTestTemplate(t *testing.T, name string, mockData codecommit_collected.Repository, testedPolicyName string, expectFailure bool) {
+	PolicyTestTemplateCodeCommit(t, name, mockData, namespace.Repository, testedPolicyName, expectFailure)
+}
+
+func TestCodeCommitRepositoryApprovalRules(t *testing.T) {
+	name := "repositories should have an approval rule for the default branch"
+	testedPolicyName := "repository_has_no_approval_rule"
+	makeMockData := func(rules []codecommit_collected.ApprovalRule) codecommit_collected.Repository {
+		return codecommit_collected.Repository{RepositoryName: "payments", DefaultBranch: "main", ApprovalRules: rules}
+	}
+	codecommitRepositoryTestTemplate(t, name, makeMockData([]codecommit_collected.ApprovalRule{}), testedPolicyName, true)
+	codecommitRepositoryTestTemplate(t, name, makeMockData([]codecommit_collected.ApprovalRule{{Template: "release", RequiredApprovals: 1, DestinationReferences: []string{"refs/heads/release"}}}), testedPolicyName, true)
+	codecommitRepositoryTestTemplate(t, name, makeMockData([]codecommit_collected.ApprovalRule{{Template: "review", RequiredApprovals: 1, AppliesToDefaultBranch: true}}), testedPolicyName, false)
+	// approval rules that couldn't be collected aren't reported
+	codecommitRepositoryTestTemplate(t, name, makeMockData(nil), testedPolicyName, false)
+}
+
+func TestCodeCommitRepositoryTriggers(t *testing.T) {
+	name := "repository triggers should deliver to the repository's account"
+	testedPolicyName := "trigger_destination_in_other_account"
+	makeMockData := func(crossAccount bool) codecommit_collected.Repository {
+		return codecommit_collected.Repository{Triggers: []codecommit_collected.Trigger{{Name: "notify", DestinationArn: "arn:aws:sns:us-east-1:222222222222:pushes", CrossAccount: crossAccount}}}
+	}
+	codecommitRepositoryTestTemplate(t, name, makeMockData(true), testedPolicyName, true)
+	codecommitRepositoryTestTemplate(t, name, makeMockData(false), testedPolicyName, false)
+}
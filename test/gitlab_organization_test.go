@@ -0,0 +1,117 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/Legit-Labs/legitify/internal/collected/gitlab_collected"
+	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/xanzy/go-gitlab"
+)
+
+func gitlabOrganizationTestTemplate(t *testing.T, name string, mockData gitlab_collected.Organization, testedPolicyName string, expectFailure bool) {
+	if mockData.Group == nil {
+		mockData.Group = &gitlab.Group{}
+	}
+	PolicyTestTemplateGitLab(t, name, mockData, namespace.Organization, testedPolicyName, expectFailure)
+}
+
+func TestGitLabOrganizationSecretVariables(t *testing.T) {
+	name := "group secret variables should be protected and masked"
+	testedPolicyName := "secret_variable_not_protected_or_masked"
+	makeMockData := func(variable gitlab_collected.Variable) gitlab_collected.Organization {
+		return gitlab_collected.Organization{Variables: []gitlab_collected.Variable{variable}}
+	}
+	gitlabOrganizationTestTemplate(t, name, makeMockData(gitlab_collected.Variable{Key: "REGISTRY_API_KEY", Masked: true, EnvironmentScope: "*"}), testedPolicyName, true)
+	gitlabOrganizationTestTemplate(t, name, makeMockData(gitlab_collected.Variable{Key: "REGISTRY_API_KEY", Protected: true, Masked: true, EnvironmentScope: "*"}), testedPolicyName, false)
+
+	name = "group secret variables should not be exposed to all projects"
+	testedPolicyName = "group_secret_variable_exposed_to_all_projects"
+	gitlabOrganizationTestTemplate(t, name, makeMockData(gitlab_collected.Variable{Key: "PROD_SECRET", Protected: true, Masked: true, EnvironmentScope: "*"}), testedPolicyName, true)
+	gitlabOrganizationTestTemplate(t, name, makeMockData(gitlab_collected.Variable{Key: "PROD_SECRET", Protected: true, Masked: true, EnvironmentScope: "production"}), testedPolicyName, false)
+	gitlabOrganizationTestTemplate(t, name, makeMockData(gitlab_collected.Variable{Key: "DOCKER_DRIVER", EnvironmentScope: "*"}), testedPolicyName, false)
+}
+
+func TestGitLabOrganizationDeployTokens(t *testing.T) {
+	name := "group deploy tokens should not have write permissions"
+	testedPolicyName := "deploy_token_has_write_permissions"
+	makeMockData := func(token gitlab_collected.DeployToken) gitlab_collected.Organization {
+		return gitlab_collected.Organization{DeployTokens: []gitlab_collected.DeployToken{token}}
+	}
+	gitlabOrganizationTestTemplate(t, name, makeMockData(gitlab_collected.DeployToken{Name: "registry", Scopes: []string{"write_package_registry"}}), testedPolicyName, true)
+	gitlabOrganizationTestTemplate(t, name, makeMockData(gitlab_collected.DeployToken{Name: "registry", Scopes: []string{"read_package_registry"}}), testedPolicyName, false)
+
+	name = "group deploy tokens should expire"
+	testedPolicyName = "deploy_token_never_expires"
+	gitlabOrganizationTestTemplate(t, name, makeMockData(gitlab_collected.DeployToken{Name: "registry", Scopes: []string{"read_package_registry"}}), testedPolicyName, true)
+	gitlabOrganizationTestTemplate(t, name, makeMockData(gitlab_collected.DeployToken{Name: "registry", Scopes: []string{"read_package_registry"}, ExpiresAt: "2030-01-01T00:00:00Z"}), testedPolicyName, false)
+}
+
+func TestGitLabOrganizationSamlSSO(t *testing.T) {
+	name := "group members should link their sso identity"
+	testedPolicyName := "members_without_linked_sso_identity"
+	makeMockData := func(sso *gitlab_collected.SamlSSO) gitlab_collected.Organization {
+		return gitlab_collected.Organization{SamlSSO: sso}
+	}
+	none, some := 0, 3
+	gitlabOrganizationTestTemplate(t, name, makeMockData(&gitlab_collected.SamlSSO{Configured: true, LinkedMembers: 3, UnlinkedMembers: 1, UnlinkedUsernames: []string{"alice"}, ScimIdentities: &some}), testedPolicyName, true)
+	gitlabOrganizationTestTemplate(t, name, makeMockData(&gitlab_collected.SamlSSO{Configured: true, LinkedMembers: 3, UnlinkedUsernames: []string{}, ScimIdentities: &some}), testedPolicyName, false)
+	gitlabOrganizationTestTemplate(t, name, makeMockData(&gitlab_collected.SamlSSO{UnlinkedMembers: 1, UnlinkedUsernames: []string{"alice"}}), testedPolicyName, false)
+
+	name = "groups with sso should provision identities with scim"
+	testedPolicyName = "scim_provisioning_not_configured"
+	gitlabOrganizationTestTemplate(t, name, makeMockData(&gitlab_collected.SamlSSO{Configured: true, LinkedMembers: 3, UnlinkedUsernames: []string{}, ScimIdentities: &none}), testedPolicyName, true)
+	gitlabOrganizationTestTemplate(t, name, makeMockData(&gitlab_collected.SamlSSO{Configured: true, LinkedMembers: 3, UnlinkedUsernames: []string{}, ScimIdentities: &some}), testedPolicyName, false)
+	gitlabOrganizationTestTemplate(t, name, makeMockData(&gitlab_collected.SamlSSO{Configured: true, LinkedMembers: 3, UnlinkedUsernames: []string{}}), testedPolicyName, false)
+}
+
+func TestGitLabGroupApprovalSettings(t *testing.T) {
+	makeMockData := func(settings *gitlab_collected.ApprovalSettings) gitlab_collected.Organization {
+		return gitlab_collected.Organization{ApprovalSettings: settings}
+	}
+	secure := gitlab_collected.ApprovalSettings{ResetApprovalsOnPush: true, PreventAuthorApproval: true, PreventCommittersApproval: true}
+
+	name := "group merge request authors should not approve their merge requests"
+	testedPolicyName := "author_can_approve_merge_request"
+	settings := secure
+	settings.PreventAuthorApproval = false
+	gitlabOrganizationTestTemplate(t, name, makeMockData(&settings), testedPolicyName, true)
+	gitlabOrganizationTestTemplate(t, name, makeMockData(&secure), testedPolicyName, false)
+	// approval settings aren't available on GitLab Free
+	gitlabOrganizationTestTemplate(t, name, makeMockData(nil), testedPolicyName, false)
+}
+
+func TestGitLabGroupAccessTokenLifetime(t *testing.T) {
+	name := "access token lifetime should be enforced"
+	testedPolicyName := "access_token_lifetime_not_enforced"
+	makeMockData := func(lifetime *int) gitlab_collected.Organization {
+		return gitlab_collected.Organization{MaxAccessTokenLifetime: lifetime}
+	}
+	unlimited, limited := 0, 90
+	gitlabOrganizationTestTemplate(t, name, makeMockData(&unlimited), testedPolicyName, true)
+	gitlabOrganizationTestTemplate(t, name, makeMockData(&limited), testedPolicyName, false)
+	// the instance settings are collected only with an administrator token
+	gitlabOrganizationTestTemplate(t, name, makeMockData(nil), testedPolicyName, false)
+}
+
+func TestGitLabGroupAuditEvents(t *testing.T) {
+	makeMockData := func(events gitlab_collected.AuditEvents) gitlab_collected.Organization {
+		events.WindowDays = 90
+		return gitlab_collected.Organization{AuditEvents: &events}
+	}
+
+	name := "privileged role grants should be reported"
+	testedPolicyName := "member_granted_privileged_role"
+	gitlabOrganizationTestTemplate(t, name, makeMockData(gitlab_collected.AuditEvents{PermissionChanges: []gitlab_collected.AuditEvent{{Target: "bob", From: "Developer", To: "Owner"}}}), testedPolicyName, true)
+	gitlabOrganizationTestTemplate(t, name, makeMockData(gitlab_collected.AuditEvents{PermissionChanges: []gitlab_collected.AuditEvent{{Target: "bob", To: "Reporter"}}}), testedPolicyName, false)
+
+	name = "visibility changes to public should be reported"
+	testedPolicyName = "visibility_changed_to_public"
+	gitlabOrganizationTestTemplate(t, name, makeMockData(gitlab_collected.AuditEvents{VisibilityChanges: []gitlab_collected.AuditEvent{{Target: "group1/project1", From: "Private", To: "Public"}}}), testedPolicyName, true)
+	gitlabOrganizationTestTemplate(t, name, makeMockData(gitlab_collected.AuditEvents{VisibilityChanges: []gitlab_collected.AuditEvent{{Target: "group1/project1", From: "Public", To: "Private"}}}), testedPolicyName, false)
+
+	name = "dormant group members should be reported"
+	testedPolicyName = "dormant_member_found"
+	gitlabOrganizationTestTemplate(t, name, makeMockData(gitlab_collected.AuditEvents{InactiveMembers: []string{"alice"}}), testedPolicyName, true)
+	gitlabOrganizationTestTemplate(t, name, makeMockData(gitlab_collected.AuditEvents{InactiveMembers: []string{}}), testedPolicyName, false)
+	gitlabOrganizationTestTemplate(t, name, gitlab_collected.Organization{}, testedPolicyName, false)
+}
@@ -0,0 +1,158 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/Legit-Labs/legitify/internal/collected/gitlab_collected"
+	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/xanzy/go-gitlab"
+)
+
+func gitlabRepositoryTestTemplate(t *testing.T, name string, mockData gitlab_collected.Repository, testedPolicyName string, expectFailure bool) {
+	if mockData.Project == nil {
+		mockData.Project = &gitlab.Project{}
+	}
+	PolicyTestTemplateGitLab(t, name, mockData, namespace.Repository, testedPolicyName, expectFailure)
+}
+
+func TestGitLabRepositoryProductionEnvironment(t *testing.T) {
+	name := "production environments should require deployment approvals"
+	testedPolicyName := "production_environment_deployable_without_approval"
+	makeMockData := func(env gitlab_collected.Environment) gitlab_collected.Repository {
+		return gitlab_collected.Repository{Environments: []gitlab_collected.Environment{env}}
+	}
+	gitlabRepositoryTestTemplate(t, name, makeMockData(gitlab_collected.Environment{Name: "production", Tier: "production"}), testedPolicyName, true)
+	gitlabRepositoryTestTemplate(t, name, makeMockData(gitlab_collected.Environment{Name: "production", Tier: "production", Protected: true}), testedPolicyName, true)
+	gitlabRepositoryTestTemplate(t, name, makeMockData(gitlab_collected.Environment{Name: "production", Tier: "production", Protected: true, RequiredApprovalCount: 1}), testedPolicyName, false)
+	gitlabRepositoryTestTemplate(t, name, makeMockData(gitlab_collected.Environment{Name: "review/feature", Tier: "development"}), testedPolicyName, false)
+}
+
+func TestGitLabRepositorySecretVariables(t *testing.T) {
+	name := "secret variables should be protected and masked"
+	testedPolicyName := "secret_variable_not_protected_or_masked"
+	makeMockData := func(variable gitlab_collected.Variable) gitlab_collected.Repository {
+		return gitlab_collected.Repository{Variables: []gitlab_collected.Variable{variable}}
+	}
+	gitlabRepositoryTestTemplate(t, name, makeMockData(gitlab_collected.Variable{Key: "DEPLOY_TOKEN", Masked: true}), testedPolicyName, true)
+	gitlabRepositoryTestTemplate(t, name, makeMockData(gitlab_collected.Variable{Key: "DB_PASSWORD", Protected: true}), testedPolicyName, true)
+	gitlabRepositoryTestTemplate(t, name, makeMockData(gitlab_collected.Variable{Key: "DEPLOY_TOKEN", Protected: true, Masked: true}), testedPolicyName, false)
+	gitlabRepositoryTestTemplate(t, name, makeMockData(gitlab_collected.Variable{Key: "LOG_LEVEL"}), testedPolicyName, false)
+}
+
+func TestGitLabRepositoryDeployTokens(t *testing.T) {
+	name := "deploy tokens should not have write permissions"
+	testedPolicyName := "deploy_token_has_write_permissions"
+	makeMockData := func(token gitlab_collected.DeployToken) gitlab_collected.Repository {
+		return gitlab_collected.Repository{DeployTokens: []gitlab_collected.DeployToken{token}}
+	}
+	expiresAt := "2030-01-01T00:00:00Z"
+	gitlabRepositoryTestTemplate(t, name, makeMockData(gitlab_collected.DeployToken{Name: "ci", Scopes: []string{"read_repository", "write_registry"}, ExpiresAt: expiresAt}), testedPolicyName, true)
+	gitlabRepositoryTestTemplate(t, name, makeMockData(gitlab_collected.DeployToken{Name: "ci", Scopes: []string{"read_repository", "read_registry"}, ExpiresAt: expiresAt}), testedPolicyName, false)
+
+	name = "deploy tokens should expire"
+	testedPolicyName = "deploy_token_never_expires"
+	gitlabRepositoryTestTemplate(t, name, makeMockData(gitlab_collected.DeployToken{Name: "ci", Scopes: []string{"read_repository"}}), testedPolicyName, true)
+	gitlabRepositoryTestTemplate(t, name, makeMockData(gitlab_collected.DeployToken{Name: "ci", Scopes: []string{"read_repository"}, ExpiresAt: expiresAt}), testedPolicyName, false)
+}
+
+func TestGitLabRepositorySharedRunners(t *testing.T) {
+	name := "high-sensitivity projects should not use shared runners"
+	testedPolicyName := "high_sensitivity_project_uses_shared_runners"
+	makeMockData := func(sensitive bool, sharedRunners bool) gitlab_collected.Repository {
+		return gitlab_collected.Repository{
+			Project:         &gitlab.Project{SharedRunnersEnabled: sharedRunners},
+			HighSensitivity: sensitive,
+		}
+	}
+	gitlabRepositoryTestTemplate(t, name, makeMockData(true, true), testedPolicyName, true)
+	gitlabRepositoryTestTemplate(t, name, makeMockData(true, false), testedPolicyName, false)
+	gitlabRepositoryTestTemplate(t, name, makeMockData(false, true), testedPolicyName, false)
+}
+
+func TestGitLabRepositoryPipelineSchedules(t *testing.T) {
+	name := "pipeline schedules should be owned by active members"
+	testedPolicyName := "pipeline_schedule_owner_is_inactive"
+	makeMockData := func(schedule gitlab_collected.PipelineSchedule) gitlab_collected.Repository {
+		return gitlab_collected.Repository{PipelineSchedules: []gitlab_collected.PipelineSchedule{schedule}}
+	}
+	gitlabRepositoryTestTemplate(t, name, makeMockData(gitlab_collected.PipelineSchedule{Description: "nightly", Active: true, Owner: "bob", OwnerState: "blocked", OwnerIsMember: true}), testedPolicyName, true)
+	gitlabRepositoryTestTemplate(t, name, makeMockData(gitlab_collected.PipelineSchedule{Description: "nightly", Active: true, Owner: "bob", OwnerState: "active"}), testedPolicyName, true)
+	gitlabRepositoryTestTemplate(t, name, makeMockData(gitlab_collected.PipelineSchedule{Description: "nightly", Active: true, Owner: "bob", OwnerState: "active", OwnerIsMember: true}), testedPolicyName, false)
+	gitlabRepositoryTestTemplate(t, name, makeMockData(gitlab_collected.PipelineSchedule{Description: "nightly", Owner: "bob", OwnerState: "deactivated"}), testedPolicyName, false)
+}
+
+func TestGitLabRepositoryContainerRegistry(t *testing.T) {
+	name := "container registry should have a cleanup policy"
+	testedPolicyName := "container_registry_cleanup_policy_disabled"
+	makeMockData := func(registry gitlab_collected.ContainerRegistry) gitlab_collected.Repository {
+		return gitlab_collected.Repository{ContainerRegistry: &registry}
+	}
+	gitlabRepositoryTestTemplate(t, name, makeMockData(gitlab_collected.ContainerRegistry{AccessLevel: "enabled"}), testedPolicyName, true)
+	gitlabRepositoryTestTemplate(t, name, makeMockData(gitlab_collected.ContainerRegistry{AccessLevel: "private", CleanupPolicyEnabled: true}), testedPolicyName, false)
+	gitlabRepositoryTestTemplate(t, name, makeMockData(gitlab_collected.ContainerRegistry{AccessLevel: "disabled"}), testedPolicyName, false)
+
+	name = "container registry tags should be protected"
+	testedPolicyName = "container_registry_tags_not_protected"
+	gitlabRepositoryTestTemplate(t, name, makeMockData(gitlab_collected.ContainerRegistry{AccessLevel: "enabled", ProtectedTagPatterns: []string{}, ImmutableTagPatterns: []string{}}), testedPolicyName, true)
+	gitlabRepositoryTestTemplate(t, name, makeMockData(gitlab_collected.ContainerRegistry{AccessLevel: "enabled", ProtectedTagPatterns: []string{"v*"}, ImmutableTagPatterns: []string{}}), testedPolicyName, false)
+	gitlabRepositoryTestTemplate(t, name, makeMockData(gitlab_collected.ContainerRegistry{AccessLevel: "enabled", ProtectedTagPatterns: []string{}, ImmutableTagPatterns: []string{"release-*"}}), testedPolicyName, false)
+	// tag protection rules that couldn't be collected (older GitLab versions) aren't reported
+	gitlabRepositoryTestTemplate(t, name, makeMockData(gitlab_collected.ContainerRegistry{AccessLevel: "enabled"}), testedPolicyName, false)
+}
+
+func TestGitLabRepositoryReleaseTags(t *testing.T) {
+	name := "releases should be created from protected tags"
+	testedPolicyName := "release_tag_not_protected"
+	makeMockData := func(protected bool) gitlab_collected.Repository {
+		return gitlab_collected.Repository{Releases: []gitlab_collected.Release{{Name: "v1.0.0", TagName: "v1.0.0", TagProtected: protected}}}
+	}
+	gitlabRepositoryTestTemplate(t, name, makeMockData(false), testedPolicyName, true)
+	gitlabRepositoryTestTemplate(t, name, makeMockData(true), testedPolicyName, false)
+}
+
+func TestGitLabRepositoryApprovalSettings(t *testing.T) {
+	makeMockData := func(settings *gitlab_collected.ApprovalSettings) gitlab_collected.Repository {
+		return gitlab_collected.Repository{ApprovalSettings: settings}
+	}
+	secure := gitlab_collected.ApprovalSettings{ResetApprovalsOnPush: true, PreventAuthorApproval: true, PreventCommittersApproval: true}
+
+	name := "approvals should be removed on new commits"
+	testedPolicyName := "approvals_not_reset_on_push"
+	settings := secure
+	settings.ResetApprovalsOnPush = false
+	gitlabRepositoryTestTemplate(t, name, makeMockData(&settings), testedPolicyName, true)
+	gitlabRepositoryTestTemplate(t, name, makeMockData(&secure), testedPolicyName, false)
+	gitlabRepositoryTestTemplate(t, name, makeMockData(nil), testedPolicyName, false)
+
+	name = "merge request authors should not approve their merge requests"
+	testedPolicyName = "author_can_approve_merge_request"
+	settings = secure
+	settings.PreventAuthorApproval = false
+	gitlabRepositoryTestTemplate(t, name, makeMockData(&settings), testedPolicyName, true)
+	gitlabRepositoryTestTemplate(t, name, makeMockData(&secure), testedPolicyName, false)
+
+	name = "committers should not approve merge requests they committed to"
+	testedPolicyName = "committers_can_approve_merge_request"
+	settings = secure
+	settings.PreventCommittersApproval = false
+	gitlabRepositoryTestTemplate(t, name, makeMockData(&settings), testedPolicyName, true)
+	gitlabRepositoryTestTemplate(t, name, makeMockData(&secure), testedPolicyName, false)
+}
+
+func TestGitLabRepositoryAccessTokens(t *testing.T) {
+	makeMockData := func(token gitlab_collected.AccessToken) gitlab_collected.Repository {
+		return gitlab_collected.Repository{AccessTokens: []gitlab_collected.AccessToken{token}}
+	}
+	expiresAt := "2030-01-01"
+
+	name := "access tokens should expire"
+	testedPolicyName := "access_token_never_expires"
+	gitlabRepositoryTestTemplate(t, name, makeMockData(gitlab_collected.AccessToken{Name: "ci", Scopes: []string{"read_api"}, AccessLevel: 20}), testedPolicyName, true)
+	gitlabRepositoryTestTemplate(t, name, makeMockData(gitlab_collected.AccessToken{Name: "ci", Scopes: []string{"read_api"}, AccessLevel: 20, ExpiresAt: expiresAt}), testedPolicyName, false)
+
+	name = "access tokens should not have excessive permissions"
+	testedPolicyName = "access_token_has_excessive_scopes"
+	gitlabRepositoryTestTemplate(t, name, makeMockData(gitlab_collected.AccessToken{Name: "ci", Scopes: []string{"api"}, AccessLevel: 20, ExpiresAt: expiresAt}), testedPolicyName, true)
+	gitlabRepositoryTestTemplate(t, name, makeMockData(gitlab_collected.AccessToken{Name: "ci", Scopes: []string{"read_repository"}, AccessLevel: 50, ExpiresAt: expiresAt}), testedPolicyName, true)
+	gitlabRepositoryTestTemplate(t, name, makeMockData(gitlab_collected.AccessToken{Name: "ci", Scopes: []string{"read_repository"}, AccessLevel: 20, ExpiresAt: expiresAt}), testedPolicyName, false)
+}
@@ -48,6 +48,14 @@ func PolicyTestTemplateGitHub(t *testing.T, name string, mockData interface{}, n
 	PolicyTestTemplate(t, name, mockData, ns, testedPolicyName, expectFailure, scm_type.GitHub)
 }
 
+func PolicyTestTemplateGitLab(t *testing.T, name string, mockData interface{}, ns namespace.Namespace, testedPolicyName string, expectFailure bool) {
+	PolicyTestTemplate(t, name, mockData, ns, testedPolicyName, expectFailure, scm_type.GitLab)
+}
+
+func PolicyTestTemplateCodeCommit(t *testing.T, name string, mockData interface{}, ns namespace.Namespace, testedPolicyName string, expectFailure bool) {
+	PolicyTestTemplate(t, name, mockData, ns, testedPolicyName, expectFailure, scm_type.CodeCommit)
+}
+
 func PolicyTestTemplate(t *testing.T, name string, mockData interface{}, ns namespace.Namespace, testedPolicyName string, expectFailure bool, scmType scm_type.ScmType) {
 	t.Run(name, func(t *testing.T) {
 		engine, err := opa.Load([]string{}, scmType)
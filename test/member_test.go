@@ -6,18 +6,29 @@ import (
 
 	githubcollected "github.com/Legit-Labs/legitify/internal/collected/github"
 	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/google/go-github/v44/github"
 )
 
 type memberMockConfiguration struct {
 	hasLastActive bool
 	members       []githubcollected.OrganizationMember
+	plan          *github.Plan
+	invitations   []githubcollected.OrganizationInvitation
+	activityDays  int
+	dormantDays   int
 }
 
 func newMemberMock(config memberMockConfiguration) githubcollected.OrganizationMembers {
+	org := defaultOrg
+	org.Plan = config.plan
+
 	return githubcollected.OrganizationMembers{
-		Organization:  defaultOrg,
-		HasLastActive: config.hasLastActive,
-		Members:       config.members,
+		Organization:       org,
+		HasLastActive:      config.hasLastActive,
+		Members:            config.members,
+		PendingInvitations: config.invitations,
+		ActivityWindowDays: config.activityDays,
+		DormantAdminDays:   config.dormantDays,
 	}
 }
 func TestMember(t *testing.T) {
@@ -83,6 +94,163 @@ func TestMember(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:             "member without any activity in the audit log",
+			policyName:       "dormant_member_found",
+			shouldBeViolated: true,
+			args: memberMockConfiguration{
+				activityDays: 90,
+				members: []githubcollected.OrganizationMember{
+					{AuditLogLastActive: github.Int(0)},
+				},
+			},
+		},
+		{
+			name:             "member with activity in the audit log",
+			policyName:       "dormant_member_found",
+			shouldBeViolated: false,
+			args: memberMockConfiguration{
+				activityDays: 90,
+				members: []githubcollected.OrganizationMember{
+					{AuditLogLastActive: github.Int(int(time.Now().AddDate(0, 0, -10).UnixNano()))},
+				},
+			},
+		},
+		{
+			name:             "audit log activity wasn't collected",
+			policyName:       "dormant_member_found",
+			shouldBeViolated: false,
+			args: memberMockConfiguration{
+				members: []githubcollected.OrganizationMember{
+					{},
+				},
+			},
+		},
+		{
+			name:             "member without audit log events shouldn't be stale",
+			policyName:       "stale_member_found",
+			shouldBeViolated: false,
+			args: memberMockConfiguration{
+				hasLastActive: true,
+				members: []githubcollected.OrganizationMember{
+					{LastActive: 0},
+				},
+			},
+		},
+		{
+			name:             "admin without activity in the configured period",
+			policyName:       "dormant_admin_found",
+			shouldBeViolated: true,
+			args: memberMockConfiguration{
+				dormantDays: 30,
+				members: []githubcollected.OrganizationMember{
+					{IsAdmin: true, Activity: &githubcollected.MemberActivity{LastActive: int(time.Now().AddDate(0, 0, -45).UnixNano()), Source: githubcollected.ActivitySourceAuditLog}},
+				},
+			},
+		},
+		{
+			name:             "admin without any recent events",
+			policyName:       "dormant_admin_found",
+			shouldBeViolated: true,
+			args: memberMockConfiguration{
+				dormantDays: 90,
+				members: []githubcollected.OrganizationMember{
+					{IsAdmin: true, Activity: &githubcollected.MemberActivity{LastActive: 0, Source: githubcollected.ActivitySourceEvents}},
+				},
+			},
+		},
+		{
+			name:             "admin with recent activity",
+			policyName:       "dormant_admin_found",
+			shouldBeViolated: false,
+			args: memberMockConfiguration{
+				dormantDays: 30,
+				members: []githubcollected.OrganizationMember{
+					{IsAdmin: true, Activity: &githubcollected.MemberActivity{LastActive: int(time.Now().AddDate(0, 0, -10).UnixNano()), Source: githubcollected.ActivitySourceAuditLog}},
+				},
+			},
+		},
+		{
+			name:             "admin activity wasn't collected",
+			policyName:       "dormant_admin_found",
+			shouldBeViolated: false,
+			args: memberMockConfiguration{
+				dormantDays: 30,
+				members: []githubcollected.OrganizationMember{
+					{IsAdmin: true},
+				},
+			},
+		},
+		{
+			name:             "member not in identity source",
+			policyName:       "member_not_in_identity_source",
+			shouldBeViolated: true,
+			args: memberMockConfiguration{
+				members: []githubcollected.OrganizationMember{
+					{
+						InIdentitySource: github.Bool(false),
+					},
+				},
+			},
+		},
+		{
+			name:             "member in identity source",
+			policyName:       "member_not_in_identity_source",
+			shouldBeViolated: false,
+			args: memberMockConfiguration{
+				members: []githubcollected.OrganizationMember{
+					{
+						InIdentitySource: github.Bool(true),
+					},
+				},
+			},
+		},
+		{
+			name:             "no identity source",
+			policyName:       "member_not_in_identity_source",
+			shouldBeViolated: false,
+			args: memberMockConfiguration{
+				members: []githubcollected.OrganizationMember{
+					{},
+				},
+			},
+		},
+		{
+			name:             "unused seats",
+			policyName:       "unused_seats_found",
+			shouldBeViolated: true,
+			args: memberMockConfiguration{
+				plan: &github.Plan{Seats: github.Int(10), FilledSeats: github.Int(7)},
+			},
+		},
+		{
+			name:             "all seats are used",
+			policyName:       "unused_seats_found",
+			shouldBeViolated: false,
+			args: memberMockConfiguration{
+				plan: &github.Plan{Seats: github.Int(10), FilledSeats: github.Int(10)},
+			},
+		},
+		{
+			name:             "stale pending invitation",
+			policyName:       "stale_invitation_found",
+			shouldBeViolated: true,
+			args: memberMockConfiguration{
+				invitations: []githubcollected.OrganizationInvitation{
+					{CreatedAt: int(time.Now().AddDate(0, -2, 0).UnixNano())},
+				},
+			},
+		},
+		{
+			name:             "recent pending invitation",
+			policyName:       "stale_invitation_found",
+			shouldBeViolated: false,
+			args: memberMockConfiguration{
+				invitations: []githubcollected.OrganizationInvitation{
+					{CreatedAt: int(time.Now().AddDate(0, 0, -3).UnixNano())},
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
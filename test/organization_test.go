@@ -1,11 +1,21 @@
 package test
 
 import (
+	"context"
 	"github.com/google/go-github/v44/github"
 	"testing"
+	"time"
 
+	"github.com/Legit-Labs/legitify/internal/clients/github/types"
 	githubcollected "github.com/Legit-Labs/legitify/internal/collected/github"
 	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/Legit-Labs/legitify/internal/common/scm_type"
+	"github.com/Legit-Labs/legitify/internal/confidence"
+	"github.com/Legit-Labs/legitify/internal/ghas"
+	"github.com/Legit-Labs/legitify/internal/opa"
+	"github.com/Legit-Labs/legitify/internal/opa/opa_engine"
+	"github.com/Legit-Labs/legitify/internal/webhooks"
+	"github.com/stretchr/testify/require"
 )
 
 type organizationMockConfiguration struct {
@@ -13,6 +23,22 @@ type organizationMockConfiguration struct {
 	ssoEnabled *bool
 	name       string
 	url        string
+	transfers  []githubcollected.RepositoryTransfer
+	coverage   *ghas.Coverage
+	health     []webhooks.Health
+	settings   []webhooks.Settings
+	apps       []*github.Installation
+	oauth      *githubcollected.OAuthAppRestrictions
+	roles      []types.CustomRepositoryRole
+	managers   []githubcollected.SecurityManagerTeam
+	domains    []githubcollected.Domain
+	sensitive  bool
+	ipAllow    *githubcollected.IPAllowList
+	sso        *githubcollected.SamlSSO
+	codespaces *githubcollected.Codespaces
+	packages   []githubcollected.Package
+	projects   []githubcollected.Project
+	org        *githubcollected.ExtendedOrg
 }
 
 func newOrganizationMock(config organizationMockConfiguration) githubcollected.Organization {
@@ -31,9 +57,24 @@ func newOrganizationMock(config organizationMockConfiguration) githubcollected.O
 	}
 
 	return githubcollected.Organization{
-		Organization: nil,
-		SamlEnabled:  &samlEnabledMockResult,
-		Hooks:        hooks,
+		Organization:          config.org,
+		SamlEnabled:           &samlEnabledMockResult,
+		Hooks:                 hooks,
+		RepositoryTransfers:   config.transfers,
+		GHASCoverage:          config.coverage,
+		HooksHealth:           config.health,
+		HooksSettings:         config.settings,
+		AppInstallations:      config.apps,
+		OAuthAppRestrictions:  config.oauth,
+		CustomRepositoryRoles: config.roles,
+		SecurityManagerTeams:  config.managers,
+		Domains:               config.domains,
+		HighSensitivity:       config.sensitive,
+		IPAllowList:           config.ipAllow,
+		SamlSSO:               config.sso,
+		Codespaces:            config.codespaces,
+		Packages:              config.packages,
+		Projects:              config.projects,
 	}
 }
 
@@ -95,6 +136,72 @@ func TestOrganization(t *testing.T) {
 				url:  "test",
 			},
 		},
+		// -- webhook settings tests
+		{
+			name:             "webhook delivers over http",
+			policyName:       "organization_webhook_delivers_over_http",
+			shouldBeViolated: true,
+			args: organizationMockConfiguration{
+				settings: []webhooks.Settings{{Name: "web", URL: "test", Scheme: "http", Host: "siem.example.com"}},
+			},
+		},
+		{
+			name:             "webhook delivers over https",
+			policyName:       "organization_webhook_delivers_over_http",
+			shouldBeViolated: false,
+			args: organizationMockConfiguration{
+				settings: []webhooks.Settings{{Name: "web", URL: "test", Scheme: "https", Host: "siem.example.com"}},
+			},
+		},
+		{
+			name:             "webhook delivers to an unrecognized domain",
+			policyName:       "organization_webhook_to_unrecognized_domain",
+			shouldBeViolated: true,
+			args: organizationMockConfiguration{
+				settings: []webhooks.Settings{{Name: "web", URL: "test", Scheme: "https", Host: "evil.com", AllowedHost: &boolFalse}},
+			},
+		},
+		{
+			name:             "webhook delivers to an allowed domain",
+			policyName:       "organization_webhook_to_unrecognized_domain",
+			shouldBeViolated: false,
+			args: organizationMockConfiguration{
+				settings: []webhooks.Settings{{Name: "web", URL: "test", Scheme: "https", Host: "siem.example.com", AllowedHost: &boolTrue}},
+			},
+		},
+		{
+			name:             "webhook domains are not checked without allowed domains",
+			policyName:       "organization_webhook_to_unrecognized_domain",
+			shouldBeViolated: false,
+			args: organizationMockConfiguration{
+				settings: []webhooks.Settings{{Name: "web", URL: "test", Scheme: "https", Host: "evil.com"}},
+			},
+		},
+		// -- webhook deliveries tests
+		{
+			name:             "webhook deliveries are failing",
+			policyName:       "organization_webhook_deliveries_failing",
+			shouldBeViolated: true,
+			args: organizationMockConfiguration{
+				health: []webhooks.Health{{Name: "web", URL: "test", Active: true, FailurePercent: 100, LastStatusCode: 503, Failing: true}},
+			},
+		},
+		{
+			name:             "webhook deliveries are healthy",
+			policyName:       "organization_webhook_deliveries_failing",
+			shouldBeViolated: false,
+			args: organizationMockConfiguration{
+				health: []webhooks.Health{{Name: "web", URL: "test", Active: true, LastStatusCode: 200}},
+			},
+		},
+		{
+			name:             "inactive webhook deliveries are not checked",
+			policyName:       "organization_webhook_deliveries_failing",
+			shouldBeViolated: false,
+			args: organizationMockConfiguration{
+				health: []webhooks.Health{{Name: "web", URL: "test", FailurePercent: 100, LastStatusCode: 503, Failing: true}},
+			},
+		},
 		// -- SSO tests
 		{
 			name:             "SSO should be disabled",
@@ -112,6 +219,388 @@ func TestOrganization(t *testing.T) {
 				ssoEnabled: &boolTrue,
 			},
 		},
+		// -- repository transfers tests
+		{
+			name:             "no repository was transferred in",
+			policyName:       "repository_transferred_in",
+			shouldBeViolated: false,
+			args: organizationMockConfiguration{
+				transfers: []githubcollected.RepositoryTransfer{
+					{Repository: "org/repo", Direction: githubcollected.TransferOutgoing},
+				},
+			},
+		},
+		{
+			name:             "repository was transferred in",
+			policyName:       "repository_transferred_in",
+			shouldBeViolated: true,
+			args: organizationMockConfiguration{
+				transfers: []githubcollected.RepositoryTransfer{
+					{Repository: "org/repo", Direction: githubcollected.TransferIncoming},
+				},
+			},
+		},
+		{
+			name:             "no repository was transferred out",
+			policyName:       "repository_transferred_out",
+			shouldBeViolated: false,
+			args: organizationMockConfiguration{
+				transfers: []githubcollected.RepositoryTransfer{
+					{Repository: "org/repo", Direction: githubcollected.TransferIncoming},
+				},
+			},
+		},
+		{
+			name:             "repository was transferred out",
+			policyName:       "repository_transferred_out",
+			shouldBeViolated: true,
+			args: organizationMockConfiguration{
+				transfers: []githubcollected.RepositoryTransfer{
+					{Repository: "org/repo", Direction: githubcollected.TransferOutgoing},
+				},
+			},
+		},
+		// -- app installations tests
+		{
+			name:             "app has contents write access to all repositories",
+			policyName:       "app_has_write_access_to_all_repositories",
+			shouldBeViolated: true,
+			args: organizationMockConfiguration{
+				apps: []*github.Installation{{
+					AppSlug:             github.String("deployer"),
+					RepositorySelection: github.String("all"),
+					Permissions:         &github.InstallationPermissions{Contents: github.String("write")},
+				}},
+			},
+		},
+		{
+			name:             "app has administration write access to selected repositories",
+			policyName:       "app_has_write_access_to_all_repositories",
+			shouldBeViolated: false,
+			args: organizationMockConfiguration{
+				apps: []*github.Installation{{
+					AppSlug:             github.String("deployer"),
+					RepositorySelection: github.String("selected"),
+					Permissions:         &github.InstallationPermissions{Administration: github.String("write")},
+				}},
+			},
+		},
+		{
+			name:             "app has read access to all repositories",
+			policyName:       "app_has_write_access_to_all_repositories",
+			shouldBeViolated: false,
+			args: organizationMockConfiguration{
+				apps: []*github.Installation{{
+					AppSlug:             github.String("reader"),
+					RepositorySelection: github.String("all"),
+					Permissions:         &github.InstallationPermissions{Contents: github.String("read"), Metadata: github.String("read")},
+				}},
+			},
+		},
+		{
+			name:             "suspended app has administration write access to all repositories",
+			policyName:       "app_has_write_access_to_all_repositories",
+			shouldBeViolated: false,
+			args: organizationMockConfiguration{
+				apps: []*github.Installation{{
+					AppSlug:             github.String("deployer"),
+					RepositorySelection: github.String("all"),
+					Permissions:         &github.InstallationPermissions{Administration: github.String("write")},
+					SuspendedAt:         &github.Timestamp{Time: time.Now()},
+				}},
+			},
+		},
+		// -- oauth app restrictions tests
+		{
+			name:             "oauth app access is not restricted",
+			policyName:       "oauth_app_access_not_restricted",
+			shouldBeViolated: true,
+			args: organizationMockConfiguration{
+				oauth: &githubcollected.OAuthAppRestrictions{Enabled: &boolFalse},
+			},
+		},
+		{
+			name:             "oauth app access is restricted",
+			policyName:       "oauth_app_access_not_restricted",
+			shouldBeViolated: false,
+			args: organizationMockConfiguration{
+				oauth: &githubcollected.OAuthAppRestrictions{Enabled: &boolTrue, ApprovedApplications: []string{"ci"}},
+			},
+		},
+		{
+			name:             "oauth app access policy is unknown",
+			policyName:       "oauth_app_access_not_restricted",
+			shouldBeViolated: false,
+			args: organizationMockConfiguration{
+				oauth: &githubcollected.OAuthAppRestrictions{},
+			},
+		},
+		// -- custom repository roles tests
+		{
+			name:             "innocuous custom role can bypass the branch protection",
+			policyName:       "custom_role_grants_admin_equivalent_permissions",
+			shouldBeViolated: true,
+			args: organizationMockConfiguration{
+				roles: []types.CustomRepositoryRole{{Name: "triage-plus", BaseRole: "triage", Permissions: []string{"add_label", "bypass_branch_protection"}}},
+			},
+		},
+		{
+			name:             "admin custom role can bypass the branch protection",
+			policyName:       "custom_role_grants_admin_equivalent_permissions",
+			shouldBeViolated: false,
+			args: organizationMockConfiguration{
+				roles: []types.CustomRepositoryRole{{Name: "Release Admin", BaseRole: "maintain", Permissions: []string{"bypass_branch_protection"}}},
+			},
+		},
+		{
+			name:             "custom role has no admin-equivalent permissions",
+			policyName:       "custom_role_grants_admin_equivalent_permissions",
+			shouldBeViolated: false,
+			args: organizationMockConfiguration{
+				roles: []types.CustomRepositoryRole{{Name: "triage-plus", BaseRole: "triage", Permissions: []string{"add_label", "set_milestone"}}},
+			},
+		},
+		// -- saml sso tests
+		{
+			name:             "members without linked sso identities",
+			policyName:       "members_without_linked_sso_identity",
+			shouldBeViolated: true,
+			args: organizationMockConfiguration{
+				sso: &githubcollected.SamlSSO{Configured: true, LinkedMembers: 3, UnlinkedMembers: 1, UnlinkedLogins: []string{"bob"}},
+			},
+		},
+		{
+			name:             "all members linked sso identities",
+			policyName:       "members_without_linked_sso_identity",
+			shouldBeViolated: false,
+			args: organizationMockConfiguration{
+				sso: &githubcollected.SamlSSO{Configured: true, LinkedMembers: 4, UnlinkedLogins: []string{}},
+			},
+		},
+		{
+			name:             "sso isn't configured for the organization",
+			policyName:       "members_without_linked_sso_identity",
+			shouldBeViolated: false,
+			args: organizationMockConfiguration{
+				sso: &githubcollected.SamlSSO{Configured: false, UnlinkedLogins: []string{}},
+			},
+		},
+		// -- ip allow list tests
+		{
+			name:             "high-sensitivity organization doesn't restrict access by ip",
+			policyName:       "ip_allow_list_not_enabled",
+			shouldBeViolated: true,
+			args: organizationMockConfiguration{
+				sensitive: true,
+				ipAllow:   &githubcollected.IPAllowList{Entries: []githubcollected.IPAllowListEntry{}},
+			},
+		},
+		{
+			name:             "high-sensitivity organization restricts access by ip",
+			policyName:       "ip_allow_list_not_enabled",
+			shouldBeViolated: false,
+			args: organizationMockConfiguration{
+				sensitive: true,
+				ipAllow:   &githubcollected.IPAllowList{Enabled: true, Entries: []githubcollected.IPAllowListEntry{{Value: "192.0.2.0/24", Name: "office", Active: true}}},
+			},
+		},
+		{
+			name:             "organization isn't classified as high-sensitivity",
+			policyName:       "ip_allow_list_not_enabled",
+			shouldBeViolated: false,
+			args: organizationMockConfiguration{
+				ipAllow: &githubcollected.IPAllowList{Entries: []githubcollected.IPAllowListEntry{}},
+			},
+		},
+		// -- verified domains tests
+		{
+			name:             "organization has no verified domains",
+			policyName:       "no_verified_domains",
+			shouldBeViolated: true,
+			args: organizationMockConfiguration{
+				domains: []githubcollected.Domain{{Domain: "partner.com", Approved: true}},
+			},
+		},
+		{
+			name:             "organization has a verified domain",
+			policyName:       "no_verified_domains",
+			shouldBeViolated: false,
+			args: organizationMockConfiguration{
+				domains: []githubcollected.Domain{{Domain: "example.com", Verified: true}},
+			},
+		},
+		{
+			name:             "the domains weren't collected",
+			policyName:       "no_verified_domains",
+			shouldBeViolated: false,
+			args:             organizationMockConfiguration{},
+		},
+		// -- security managers tests
+		{
+			name:             "no team is assigned the security manager role",
+			policyName:       "no_security_managers",
+			shouldBeViolated: true,
+			args: organizationMockConfiguration{
+				managers: []githubcollected.SecurityManagerTeam{},
+			},
+		},
+		{
+			name:             "a team is assigned the security manager role",
+			policyName:       "no_security_managers",
+			shouldBeViolated: false,
+			args: organizationMockConfiguration{
+				managers: []githubcollected.SecurityManagerTeam{{Name: "AppSec", Slug: "appsec", Members: []string{"alice"}, OutsideCollaborators: []string{}}},
+			},
+		},
+		{
+			name:             "the security managers weren't collected",
+			policyName:       "no_security_managers",
+			shouldBeViolated: false,
+			args:             organizationMockConfiguration{},
+		},
+		{
+			name:             "security manager team includes an outside collaborator",
+			policyName:       "security_manager_team_has_outside_collaborators",
+			shouldBeViolated: true,
+			args: organizationMockConfiguration{
+				managers: []githubcollected.SecurityManagerTeam{{Name: "AppSec", Slug: "appsec", Members: []string{"alice", "contractor"}, OutsideCollaborators: []string{"contractor"}}},
+			},
+		},
+		{
+			name:             "security manager team includes only members",
+			policyName:       "security_manager_team_has_outside_collaborators",
+			shouldBeViolated: false,
+			args: organizationMockConfiguration{
+				managers: []githubcollected.SecurityManagerTeam{{Name: "AppSec", Slug: "appsec", Members: []string{"alice"}, OutsideCollaborators: []string{}}},
+			},
+		},
+		{
+			name:             "codespaces secret is available to all repositories",
+			policyName:       "codespaces_secret_available_to_all_repositories",
+			shouldBeViolated: true,
+			args: organizationMockConfiguration{
+				codespaces: &githubcollected.Codespaces{
+					Secrets:    []githubcollected.CodespacesSecret{{Name: "DEPLOY_TOKEN", Visibility: "all"}},
+					Codespaces: []githubcollected.Codespace{},
+				},
+			},
+		},
+		{
+			name:             "codespaces secret is available to selected repositories",
+			policyName:       "codespaces_secret_available_to_all_repositories",
+			shouldBeViolated: false,
+			args: organizationMockConfiguration{
+				codespaces: &githubcollected.Codespaces{
+					Secrets:    []githubcollected.CodespacesSecret{{Name: "DEPLOY_TOKEN", Visibility: "selected"}},
+					Codespaces: []githubcollected.Codespace{},
+				},
+			},
+		},
+		{
+			name:             "codespaces were created in a high-sensitivity organization",
+			policyName:       "codespaces_created_in_high_sensitivity_organization",
+			shouldBeViolated: true,
+			args: organizationMockConfiguration{
+				sensitive: true,
+				codespaces: &githubcollected.Codespaces{
+					Secrets:    []githubcollected.CodespacesSecret{},
+					Codespaces: []githubcollected.Codespace{{Name: "cs1", Owner: "alice", Repository: "org/payments", PrivateRepository: true}},
+				},
+			},
+		},
+		{
+			name:             "codespaces were created in an organization that isn't high-sensitivity",
+			policyName:       "codespaces_created_in_high_sensitivity_organization",
+			shouldBeViolated: false,
+			args: organizationMockConfiguration{
+				codespaces: &githubcollected.Codespaces{
+					Secrets:    []githubcollected.CodespacesSecret{},
+					Codespaces: []githubcollected.Codespace{{Name: "cs1", Owner: "alice", Repository: "org/payments", PrivateRepository: true}},
+				},
+			},
+		},
+		{
+			name:             "public package is linked to a private repository",
+			policyName:       "public_package_linked_to_private_repository",
+			shouldBeViolated: true,
+			args: organizationMockConfiguration{
+				packages: []githubcollected.Package{{Name: "api", Type: "container", Visibility: "public", Repository: "org/api", RepositoryPrivate: true}},
+			},
+		},
+		{
+			name:             "public package is linked to a public repository",
+			policyName:       "public_package_linked_to_private_repository",
+			shouldBeViolated: false,
+			args: organizationMockConfiguration{
+				packages: []githubcollected.Package{{Name: "sdk", Type: "npm", Visibility: "public", Repository: "org/sdk"}},
+			},
+		},
+		{
+			name:             "package is not linked to a repository",
+			policyName:       "package_not_linked_to_repository",
+			shouldBeViolated: true,
+			args: organizationMockConfiguration{
+				packages: []githubcollected.Package{{Name: "legacy", Type: "maven", Visibility: "private"}},
+			},
+		},
+		{
+			name:             "package is linked to a repository",
+			policyName:       "package_not_linked_to_repository",
+			shouldBeViolated: false,
+			args: organizationMockConfiguration{
+				packages: []githubcollected.Package{{Name: "sdk", Type: "npm", Visibility: "public", Repository: "org/sdk"}},
+			},
+		},
+		{
+			name:             "private organization has a public project",
+			policyName:       "public_project_in_private_organization",
+			shouldBeViolated: true,
+			args: organizationMockConfiguration{
+				org:      &githubcollected.ExtendedOrg{Organization: github.Organization{PublicRepos: github.Int(0)}},
+				projects: []githubcollected.Project{{Title: "Security backlog", Number: 1, Public: true}},
+			},
+		},
+		{
+			name:             "private organization has only private projects",
+			policyName:       "public_project_in_private_organization",
+			shouldBeViolated: false,
+			args: organizationMockConfiguration{
+				org:      &githubcollected.ExtendedOrg{Organization: github.Organization{PublicRepos: github.Int(0)}},
+				projects: []githubcollected.Project{{Title: "Roadmap", Number: 1, Classic: true}},
+			},
+		},
+		{
+			name:             "public organization has a public project",
+			policyName:       "public_project_in_private_organization",
+			shouldBeViolated: false,
+			args: organizationMockConfiguration{
+				org:      &githubcollected.ExtendedOrg{Organization: github.Organization{PublicRepos: github.Int(3)}},
+				projects: []githubcollected.Project{{Title: "Roadmap", Number: 1, Public: true}},
+			},
+		},
+		// -- advanced security coverage tests
+		{
+			name:             "advanced security is not enabled for some private repositories",
+			policyName:       "private_repositories_without_advanced_security",
+			shouldBeViolated: true,
+			args: organizationMockConfiguration{
+				coverage: ghas.Compute([]ghas.RepositoryStatus{
+					{Name: "repo1", Private: true, AdvancedSecurity: &boolTrue},
+					{Name: "repo2", Private: true, AdvancedSecurity: &boolFalse},
+				}),
+			},
+		},
+		{
+			name:             "advanced security is enabled for all private repositories",
+			policyName:       "private_repositories_without_advanced_security",
+			shouldBeViolated: false,
+			args: organizationMockConfiguration{
+				coverage: ghas.Compute([]ghas.RepositoryStatus{
+					{Name: "repo1", Private: true, AdvancedSecurity: &boolTrue},
+					{Name: "repo2", Private: false},
+				}),
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -119,3 +608,26 @@ func TestOrganization(t *testing.T) {
 			namespace.Organization, test.policyName, test.shouldBeViolated)
 	}
 }
+
+func TestOrganizationOAuthAppRestrictionsConfidence(t *testing.T) {
+	engine, err := opa.Load([]string{}, scm_type.GitHub)
+	require.NoError(t, err)
+	query := func(oauth *githubcollected.OAuthAppRestrictions) *opa_engine.QueryResult {
+		results, err := engine.Query(context.Background(), namespace.Organization, newOrganizationMock(organizationMockConfiguration{oauth: oauth}))
+		require.NoError(t, err)
+		result, err := FindPolicy(results, "oauth_app_access_not_restricted")
+		require.NoError(t, err)
+		return result
+	}
+
+	enabled := true
+	require.Nil(t, query(&githubcollected.OAuthAppRestrictions{Enabled: &enabled}).Confidence)
+
+	// the policy wasn't found in the audit log, or the audit log wasn't read
+	for _, oauth := range []*githubcollected.OAuthAppRestrictions{{}, nil} {
+		result := query(oauth)
+		require.False(t, result.IsViolation)
+		require.NotNil(t, result.Confidence)
+		require.Equal(t, confidence.Low, result.Confidence.Level)
+	}
+}
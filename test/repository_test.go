@@ -1,12 +1,24 @@
 package test
 
 import (
+	"github.com/Legit-Labs/legitify/internal/branches"
 	"github.com/Legit-Labs/legitify/internal/clients/github/types"
+	"github.com/Legit-Labs/legitify/internal/codeowners"
+	"github.com/Legit-Labs/legitify/internal/common/scm_type"
+	"github.com/Legit-Labs/legitify/internal/enforcement"
+	"github.com/Legit-Labs/legitify/internal/webhooks"
 	"testing"
+	"time"
 
 	githubcollected "github.com/Legit-Labs/legitify/internal/collected/github"
 	"github.com/Legit-Labs/legitify/internal/common/namespace"
+	"github.com/Legit-Labs/legitify/internal/dependabot"
+	"github.com/Legit-Labs/legitify/internal/doctor"
+	"github.com/Legit-Labs/legitify/internal/opa"
+	"github.com/Legit-Labs/legitify/internal/suspicious"
+	"github.com/Legit-Labs/legitify/internal/workflows"
 	"github.com/google/go-github/v44/github"
+	"github.com/stretchr/testify/require"
 )
 
 func repositoryTestTemplate(t *testing.T, name string, mockData interface{}, testedPolicyName string, expectFailure bool) {
@@ -118,6 +130,189 @@ func TestRepositoryCodeOwnersOnly(t *testing.T) {
 	}
 }
 
+func TestRepositoryCodeOwnersDefined(t *testing.T) {
+	name := "repository requiring code owners review should define code owners"
+	testedPolicyName := "code_owners_review_required_without_code_owners"
+	makeMockData := func(requiresCodeOwners bool, file *codeowners.File) githubcollected.Repository {
+		repo := makeRepoForBranchProtection(githubcollected.GitHubQLBranchProtectionRule{
+			RequiresCodeOwnerReviews: github.Bool(requiresCodeOwners),
+		})
+		repo.Codeowners = file
+		return repo
+	}
+	defined := codeowners.Parse("CODEOWNERS", []byte("* @org/team\n"))
+	repositoryTestTemplate(t, name, makeMockData(true, codeowners.Missing()), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData(true, codeowners.Parse("CODEOWNERS", []byte("* not-an-owner\n"))), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData(true, defined), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeMockData(false, codeowners.Missing()), testedPolicyName, false)
+
+	name = "repository should have a valid CODEOWNERS file"
+	testedPolicyName = "code_owners_file_has_errors"
+	unknown := codeowners.Parse("CODEOWNERS", []byte("* @ghost\n"))
+	unknown.AddErrors([]types.CodeownersError{{Line: 1, Column: 3, Kind: codeowners.UnknownOwner, Source: "* @ghost"}})
+	repositoryTestTemplate(t, name, makeMockData(true, unknown), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData(true, defined), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeMockData(true, codeowners.Missing()), testedPolicyName, false)
+}
+
+func TestRepositoryCodeOwnersSelfApproval(t *testing.T) {
+	name := "code owners should not be able to approve their own changes"
+	testedPolicyName := "code_owners_self_approval_possible"
+	makeMockData := func(content string, teams map[string][]string) githubcollected.Repository {
+		repo := makeRepoForBranchProtection(githubcollected.GitHubQLBranchProtectionRule{
+			RequiresCodeOwnerReviews: github.Bool(true),
+		})
+		repo.Codeowners = codeowners.Parse("CODEOWNERS", []byte(content))
+		repo.CodeownersTeams = teams
+		return repo
+	}
+	repositoryTestTemplate(t, name, makeMockData("* @octocat\n", nil), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData("* @org/solo\n", map[string][]string{"@org/solo": {"octocat"}}), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData("* @org/solo @Octocat\n", map[string][]string{"@org/solo": {"octocat"}}), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData("* @org/team\n", map[string][]string{"@org/team": {"octocat", "hubot"}}), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeMockData("* @octocat @hubot\n", nil), testedPolicyName, false)
+	// unresolved owners can't be assessed
+	repositoryTestTemplate(t, name, makeMockData("* @other-org/team\n", map[string][]string{}), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeMockData("* octocat@example.com\n", nil), testedPolicyName, false)
+
+	notRequired := makeMockData("* @octocat\n", nil)
+	notRequired.Repository.DefaultBranchRef.BranchProtectionRule.RequiresCodeOwnerReviews = github.Bool(false)
+	repositoryTestTemplate(t, name, notRequired, testedPolicyName, false)
+}
+
+func TestRepositoryWebhookSettings(t *testing.T) {
+	makeMockData := func(url string, allowedDomains []string) githubcollected.Repository {
+		hook := &github.Hook{Name: github.String("web"), URL: github.String("test"), Config: map[string]interface{}{"url": url}}
+		return githubcollected.Repository{
+			Repository:    &githubcollected.GitHubQLRepository{},
+			HooksSettings: []webhooks.Settings{webhooks.NewSettings(hook, allowedDomains)},
+		}
+	}
+
+	name := "repository webhook should not deliver over plain http"
+	testedPolicyName := "repository_webhook_delivers_over_http"
+	repositoryTestTemplate(t, name, makeMockData("http://siem.example.com", nil), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData("https://siem.example.com", nil), testedPolicyName, false)
+
+	name = "repository webhook should deliver to an allowed domain"
+	testedPolicyName = "repository_webhook_to_unrecognized_domain"
+	repositoryTestTemplate(t, name, makeMockData("https://evil.com", []string{"example.com"}), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData("https://siem.example.com", []string{"example.com"}), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeMockData("https://evil.com", nil), testedPolicyName, false)
+}
+
+func TestRepositoryWebhookDeliveries(t *testing.T) {
+	name := "repository webhook deliveries should not be failing"
+	testedPolicyName := "repository_webhook_deliveries_failing"
+	makeMockData := func(health webhooks.Health) githubcollected.Repository {
+		return githubcollected.Repository{
+			Repository:  &githubcollected.GitHubQLRepository{},
+			HooksHealth: []webhooks.Health{health},
+		}
+	}
+	now := time.Now()
+	failing := webhooks.Compute(webhooks.Health{Name: "web", URL: "test", Active: true}, []webhooks.Delivery{
+		{StatusCode: 404, DeliveredAt: now},
+		{StatusCode: 404, DeliveredAt: now.Add(-time.Hour)},
+	})
+	recovered := webhooks.Compute(webhooks.Health{Name: "web", URL: "test", Active: true}, []webhooks.Delivery{
+		{StatusCode: 200, DeliveredAt: now},
+		{StatusCode: 404, DeliveredAt: now.Add(-time.Hour)},
+	})
+	repositoryTestTemplate(t, name, makeMockData(failing), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData(recovered), testedPolicyName, false)
+}
+
+func TestRepositoryCodeReviewBypass(t *testing.T) {
+	name := "required code review should not be bypassable"
+	testedPolicyName := "code_review_can_be_bypassed"
+	makeMockData := func(layers []enforcement.Layer, collaborators []enforcement.Collaborator) githubcollected.Repository {
+		return githubcollected.Repository{
+			Repository:  &githubcollected.GitHubQLRepository{},
+			Enforcement: enforcement.Compute(layers, collaborators),
+		}
+	}
+	collaborators := []enforcement.Collaborator{
+		{ID: 1, Login: "admin", Role: enforcement.Admin},
+		{ID: 2, Login: "writer", Role: enforcement.Write},
+	}
+	adminsBypass := enforcement.Layer{Name: "branch protection rule", Roles: []string{enforcement.Admin}}
+	adminsEnforced := enforcement.Layer{Name: "branch protection rule"}
+	rulesetAppBypass := enforcement.Layer{Name: "ruleset main", Actors: []enforcement.Actor{{Type: enforcement.App, ID: 7}}}
+
+	repositoryTestTemplate(t, name, makeMockData([]enforcement.Layer{adminsBypass}, collaborators), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData([]enforcement.Layer{adminsEnforced}, collaborators), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeMockData([]enforcement.Layer{adminsBypass, rulesetAppBypass}, collaborators), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeMockData([]enforcement.Layer{rulesetAppBypass}, collaborators), testedPolicyName, true)
+	// nothing to bypass when no review is required
+	repositoryTestTemplate(t, name, makeMockData(nil, collaborators), testedPolicyName, false)
+}
+
+func TestRepositoryOutsideCollaborators(t *testing.T) {
+	name := "outside collaborators should not have elevated permissions on private repositories"
+	testedPolicyName := "outside_collaborator_has_elevated_permissions"
+	makeMockData := func(isPrivate bool, permission string) githubcollected.Repository {
+		return githubcollected.Repository{
+			Repository:           &githubcollected.GitHubQLRepository{IsPrivate: isPrivate},
+			OutsideCollaborators: []githubcollected.OutsideCollaborator{{Login: "contractor", Permission: permission}},
+		}
+	}
+	repositoryTestTemplate(t, name, makeMockData(true, "admin"), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData(true, "maintain"), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData(true, "write"), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeMockData(false, "admin"), testedPolicyName, false)
+}
+
+func TestRepositoryPages(t *testing.T) {
+	name := "private repository pages site should not be public"
+	testedPolicyName := "pages_public_for_private_repository"
+	makeMockData := func(isPrivate bool, pages githubcollected.Pages) githubcollected.Repository {
+		return githubcollected.Repository{
+			Repository: &githubcollected.GitHubQLRepository{IsPrivate: isPrivate},
+			Pages:      &pages,
+		}
+	}
+	repositoryTestTemplate(t, name, makeMockData(true, githubcollected.Pages{Enabled: true, Public: true}), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData(true, githubcollected.Pages{Enabled: true, Public: false}), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeMockData(false, githubcollected.Pages{Enabled: true, Public: true}), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeMockData(true, githubcollected.Pages{Enabled: false}), testedPolicyName, false)
+
+	name = "pages custom domain should enforce https"
+	testedPolicyName = "pages_custom_domain_without_https"
+	repositoryTestTemplate(t, name, makeMockData(false, githubcollected.Pages{Enabled: true, CustomDomain: "docs.example.com"}), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData(false, githubcollected.Pages{Enabled: true, CustomDomain: "docs.example.com", HTTPSEnforced: true}), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeMockData(false, githubcollected.Pages{Enabled: true}), testedPolicyName, false)
+}
+
+func TestRepositorySecurityPolicy(t *testing.T) {
+	name := "public repository should have a security policy"
+	testedPolicyName := "missing_security_policy"
+	makeMockData := func(isPrivate bool, policy githubcollected.SecurityPolicy) githubcollected.Repository {
+		return githubcollected.Repository{
+			Repository:     &githubcollected.GitHubQLRepository{IsPrivate: isPrivate},
+			SecurityPolicy: &policy,
+		}
+	}
+	repositoryTestTemplate(t, name, makeMockData(false, githubcollected.SecurityPolicy{Exists: false}), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData(false, githubcollected.SecurityPolicy{Exists: true, Path: "SECURITY.md"}), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeMockData(false, githubcollected.SecurityPolicy{Exists: true, Path: ".github/SECURITY.md", Inherited: true}), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeMockData(true, githubcollected.SecurityPolicy{Exists: false}), testedPolicyName, false)
+}
+
+func TestRepositoryLicense(t *testing.T) {
+	name := "public repository should have a license"
+	testedPolicyName := "missing_license"
+	makeMockData := func(isPrivate bool, license *githubcollected.GitHubQLLicense) githubcollected.Repository {
+		return githubcollected.Repository{
+			Repository: &githubcollected.GitHubQLRepository{IsPrivate: isPrivate, LicenseInfo: license},
+		}
+	}
+	mit := &githubcollected.GitHubQLLicense{Key: "mit", Name: "MIT License", SpdxId: github.String("MIT")}
+	repositoryTestTemplate(t, name, makeMockData(false, nil), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData(false, mit), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeMockData(true, nil), testedPolicyName, false)
+}
+
 func TestRepositoryLinearHistory(t *testing.T) {
 	name := "repository should require linear history"
 	testedPolicyName := "non_linear_history"
@@ -157,6 +352,88 @@ func TestRepositoryRestrictPushes(t *testing.T) {
 	}
 }
 
+func TestRepositoryLockedBranchRestrictsPushes(t *testing.T) {
+	name := "locked repository branch should restrict pushes"
+	testedPolicyName := "pushes_are_not_restricted"
+	makeMockData := func(flag bool) githubcollected.Repository {
+		return makeRepoForBranchProtection(githubcollected.GitHubQLBranchProtectionRule{
+			RestrictsPushes: github.Bool(false),
+			LockBranch:      github.Bool(flag),
+		})
+	}
+	for _, flag := range bools {
+		repositoryTestTemplate(t, name, makeMockData(flag), testedPolicyName, !flag)
+	}
+}
+
+func TestRepositoryLastPushApproval(t *testing.T) {
+	name := "repository should require approval of the most recent push"
+	testedPolicyName := "last_push_approval_not_required"
+	makeMockData := func(flag bool) githubcollected.Repository {
+		return makeRepoForBranchProtection(githubcollected.GitHubQLBranchProtectionRule{
+			RequireLastPushApproval: github.Bool(flag),
+		})
+	}
+	for _, flag := range bools {
+		repositoryTestTemplate(t, name, makeMockData(flag), testedPolicyName, !flag)
+	}
+}
+
+func TestRepositoryRequiredDeployments(t *testing.T) {
+	name := "repository should require successful deployments before merging"
+	testedPolicyName := "deployments_not_required"
+	makeMockData := func(flag bool) githubcollected.Repository {
+		return makeRepoForBranchProtection(githubcollected.GitHubQLBranchProtectionRule{
+			RequiresDeployments: github.Bool(flag),
+		})
+	}
+	for _, flag := range bools {
+		repositoryTestTemplate(t, name, makeMockData(flag), testedPolicyName, !flag)
+	}
+}
+
+func TestRepositoryMergeMethodsPreserveCommitSignatures(t *testing.T) {
+	name := "repository requiring signed commits should allow merge commits only"
+	testedPolicyName := "merge_methods_dont_preserve_commit_signatures"
+	makeMockData := func(squash bool, rebase bool) githubcollected.Repository {
+		repo := makeRepoForBranchProtection(githubcollected.GitHubQLBranchProtectionRule{
+			RequiresCommitSignatures: github.Bool(true),
+		})
+		repo.Repository.MergeCommitAllowed = true
+		repo.Repository.SquashMergeAllowed = squash
+		repo.Repository.RebaseMergeAllowed = rebase
+		return repo
+	}
+	repositoryTestTemplate(t, name, makeMockData(true, false), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData(false, true), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData(false, false), testedPolicyName, false)
+
+	unsigned := makeMockData(true, true)
+	unsigned.Repository.DefaultBranchRef.BranchProtectionRule.RequiresCommitSignatures = github.Bool(false)
+	repositoryTestTemplate(t, name, unsigned, testedPolicyName, false)
+}
+
+func TestRepositoryAutoMergeWithoutDismissingStaleReviews(t *testing.T) {
+	name := "repository allowing auto-merge should dismiss stale reviews"
+	testedPolicyName := "auto_merge_allowed_without_dismissing_stale_reviews"
+	makeMockData := func(autoMerge bool, dismissStale bool) githubcollected.Repository {
+		repo := makeRepoForBranchProtection(githubcollected.GitHubQLBranchProtectionRule{
+			RequiredApprovingReviewCount: github.Int(1),
+			DismissesStaleReviews:        github.Bool(dismissStale),
+			RequireLastPushApproval:      github.Bool(false),
+		})
+		repo.Repository.AutoMergeAllowed = autoMerge
+		return repo
+	}
+	repositoryTestTemplate(t, name, makeMockData(true, false), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData(true, true), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeMockData(false, false), testedPolicyName, false)
+
+	noReviews := makeMockData(true, false)
+	noReviews.Repository.DefaultBranchRef.BranchProtectionRule.RequiredApprovingReviewCount = github.Int(0)
+	repositoryTestTemplate(t, name, noReviews, testedPolicyName, false)
+}
+
 func TestRepositoryRequireConversationResolution(t *testing.T) {
 	name := "repository should require all conversations resolved before merge"
 	testedPolicyName := "no_conversation_resolution"
@@ -302,3 +579,377 @@ func TestRepositoryActionsSettingsActionsCanApprovePullRequests(t *testing.T) {
 		repositoryTestTemplate(t, name, makeMockData(flag), testedPolicyName, expectFailure)
 	}
 }
+
+func TestRepositoryActionsSettingsWorkflowTokenScopes(t *testing.T) {
+	engine, err := opa.Load([]string{}, scm_type.GitHub)
+	require.NoError(t, err)
+
+	// the repository settings are readable by repository admins, so the policies don't depend on the organization's scopes
+	policies := doctor.PoliciesFromAnnotations(engine.Annotations().Flatten(), []namespace.Namespace{namespace.Repository})
+	expected := map[string]bool{"token_default_permissions_is_read_write": true, "actions_can_approve_pull_requests": true}
+	for _, policy := range policies {
+		if expected[policy.Name] {
+			require.Equal(t, []string{"repo"}, policy.RequiredScopes, policy.Name)
+			delete(expected, policy.Name)
+		}
+	}
+	require.Empty(t, expected)
+
+	// the settings aren't collected without admin permissions, which isn't a violation
+	repositoryTestTemplate(t, "repository actions settings weren't collected", githubcollected.Repository{}, "token_default_permissions_is_read_write", false)
+	repositoryTestTemplate(t, "repository actions settings weren't collected", githubcollected.Repository{}, "actions_can_approve_pull_requests", false)
+}
+
+func TestRepositoryAllGitHubActionsAreAllowed(t *testing.T) {
+	name := "repository allows all github actions"
+	testedPolicyName := "all_github_actions_are_allowed"
+	enabled := true
+	makeMockData := func(allowedActions string) githubcollected.Repository {
+		return githubcollected.Repository{
+			ActionsPermissions: &types.ActionsPermissions{
+				Enabled:        &enabled,
+				AllowedActions: &allowedActions,
+			},
+		}
+	}
+
+	options := map[bool]string{
+		false: "local_only",
+		true:  "all",
+	}
+
+	for _, expectFailure := range bools {
+		allowedActions := options[expectFailure]
+		repositoryTestTemplate(t, name, makeMockData(allowedActions), testedPolicyName, expectFailure)
+	}
+}
+
+func TestRepositoryForkPullRequestWorkflowsApproval(t *testing.T) {
+	name := "repository fork pull request workflows run without approval"
+	testedPolicyName := "fork_pull_request_workflows_run_without_approval"
+	makeMockData := func(isPrivate bool, approvalPolicy string) githubcollected.Repository {
+		return githubcollected.Repository{
+			Repository: &githubcollected.GitHubQLRepository{
+				IsPrivate: isPrivate,
+			},
+			ForkPRApproval: &types.ForkPRContributorApproval{
+				ApprovalPolicy: &approvalPolicy,
+			},
+		}
+	}
+
+	repositoryTestTemplate(t, name, makeMockData(false, "first_time_contributors_new_to_github"), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData(false, "all_external_contributors"), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeMockData(true, "first_time_contributors"), testedPolicyName, false)
+}
+
+func TestRepositorySecretScanning(t *testing.T) {
+	name := "repository secret scanning is disabled"
+	testedPolicyName := "secret_scanning_not_enabled"
+	makeMockData := func(status string) githubcollected.Repository {
+		return githubcollected.Repository{
+			SecurityAndAnalysis: &types.SecurityAndAnalysis{
+				SecretScanning: &types.SecurityAndAnalysisStatus{Status: &status},
+			},
+		}
+	}
+
+	options := map[bool]string{
+		false: "enabled",
+		true:  "disabled",
+	}
+
+	for _, expectFailure := range bools {
+		repositoryTestTemplate(t, name, makeMockData(options[expectFailure]), testedPolicyName, expectFailure)
+	}
+}
+
+func TestRepositorySecretScanningPushProtection(t *testing.T) {
+	name := "repository secret scanning push protection is disabled"
+	testedPolicyName := "secret_scanning_push_protection_not_enabled"
+	makeMockData := func(status string) githubcollected.Repository {
+		return githubcollected.Repository{
+			SecurityAndAnalysis: &types.SecurityAndAnalysis{
+				SecretScanningPushProtection: &types.SecurityAndAnalysisStatus{Status: &status},
+			},
+		}
+	}
+
+	options := map[bool]string{
+		false: "enabled",
+		true:  "disabled",
+	}
+
+	for _, expectFailure := range bools {
+		repositoryTestTemplate(t, name, makeMockData(options[expectFailure]), testedPolicyName, expectFailure)
+	}
+}
+
+func TestRepositoryCodeScanning(t *testing.T) {
+	name := "repository code scanning is not enabled"
+	testedPolicyName := "code_scanning_not_enabled"
+	makeMockData := func(enabled bool) githubcollected.Repository {
+		return githubcollected.Repository{
+			CodeScanningEnabled: &enabled,
+		}
+	}
+
+	for _, expectFailure := range bools {
+		repositoryTestTemplate(t, name, makeMockData(!expectFailure), testedPolicyName, expectFailure)
+	}
+}
+
+func TestRepositoryPersistentSelfHostedRunner(t *testing.T) {
+	name := "public repository has a persistent self-hosted runner"
+	testedPolicyName := "persistent_self_hosted_runner_on_public_repository"
+	makeMockData := func(isPrivate bool, ephemeral bool) githubcollected.Repository {
+		return githubcollected.Repository{
+			Repository: &githubcollected.GitHubQLRepository{IsPrivate: isPrivate},
+			SelfHostedRunners: []types.SelfHostedRunner{
+				{
+					Name:      "runner",
+					OS:        "linux",
+					Status:    "online",
+					Labels:    []types.RunnerLabel{{Name: "self-hosted", Type: "read-only"}},
+					Ephemeral: ephemeral,
+				},
+			},
+		}
+	}
+
+	repositoryTestTemplate(t, name, makeMockData(false, false), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData(false, true), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeMockData(true, false), testedPolicyName, false)
+}
+
+func TestRepositoryOIDCSubjectClaim(t *testing.T) {
+	makeMockData := func(useDefault bool, claimKeys ...string) githubcollected.Repository {
+		return githubcollected.Repository{
+			OIDCSubjectClaim: &types.OIDCSubjectClaim{
+				UseDefault:       &useDefault,
+				IncludeClaimKeys: claimKeys,
+			},
+		}
+	}
+
+	name := "repository OIDC subject claim is not scoped to the repository"
+	testedPolicyName := "oidc_subject_claim_not_scoped_to_repository"
+	repositoryTestTemplate(t, name, makeMockData(false, "repository_owner", "context"), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData(false, "repository_id", "context"), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeMockData(true), testedPolicyName, false)
+
+	name = "repository OIDC subject claim is not scoped to the job context"
+	testedPolicyName = "oidc_subject_claim_not_scoped_to_context"
+	repositoryTestTemplate(t, name, makeMockData(false, "repo"), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData(false, "repo", "environment"), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeMockData(true), testedPolicyName, false)
+}
+
+func TestRepositoryUnpinnedThirdPartyActions(t *testing.T) {
+	makeMockData := func(stepUses string, jobUses string) githubcollected.Repository {
+		job := workflows.Job{ID: "build", Steps: []workflows.Step{{Uses: workflows.ParseReference(stepUses)}}}
+		if jobUses != "" {
+			job.Uses = workflows.ParseReference(jobUses)
+		}
+		return githubcollected.Repository{
+			Workflows: []*workflows.Workflow{{
+				Path: ".github/workflows/build.yml",
+				Jobs: []workflows.Job{job},
+			}},
+			TrustedActionPublishers: workflows.DefaultTrustedPublishers,
+		}
+	}
+
+	name := "repository workflows use third-party actions that are not pinned to a commit SHA"
+	testedPolicyName := "unpinned_third_party_actions"
+	repositoryTestTemplate(t, name, makeMockData("some-owner/some-action@v1", ""), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData("some-owner/some-action@main", ""), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData("actions/checkout@v3", "some-owner/workflows/.github/workflows/ci.yml@v1"), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData("some-owner/some-action@8f4b7f84864484a7bf31766abe9204da3cbe65b3", ""), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeMockData("actions/checkout@v3", ""), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeMockData("./.github/actions/local", ""), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeMockData("docker://alpine:3.16", ""), testedPolicyName, false)
+}
+
+func TestRepositoryPullRequestTargetWorkflow(t *testing.T) {
+	makeMockData := func(trigger string, content string) githubcollected.Repository {
+		workflow, err := workflows.Parse(".github/workflows/pr.yml", []byte("on: "+trigger+"\n"+content))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return githubcollected.Repository{Workflows: []*workflows.Workflow{workflow}}
+	}
+
+	checkoutHead := `
+jobs:
+  build:
+    steps:
+      - uses: actions/checkout@v3
+        with:
+          ref: ${{ github.event.pull_request.head.sha }}
+      - run: make
+`
+	withSecret := `
+jobs:
+  label:
+    steps:
+      - uses: some-owner/labeler@v1
+        with:
+          token: ${{ secrets.BOT_TOKEN }}
+`
+	inheritSecrets := `
+jobs:
+  call:
+    uses: some-owner/workflows/.github/workflows/ci.yml@v1
+    secrets: inherit
+`
+	safe := `
+jobs:
+  label:
+    steps:
+      - uses: actions/labeler@v4
+        with:
+          repo-token: ${{ secrets.GITHUB_TOKEN }}
+`
+
+	name := "pull_request_target workflow checks out the pull request code or exposes secrets"
+	testedPolicyName := "pull_request_target_workflow_is_dangerous"
+	repositoryTestTemplate(t, name, makeMockData("pull_request_target", checkoutHead), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData("[pull_request_target]", withSecret), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData("pull_request_target", inheritSecrets), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData("pull_request_target", safe), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeMockData("pull_request", checkoutHead), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeMockData("pull_request", withSecret), testedPolicyName, false)
+}
+
+func TestRepositoryDependabot(t *testing.T) {
+	makeMockData := func(status string) githubcollected.Repository {
+		return githubcollected.Repository{
+			SecurityAndAnalysis: &types.SecurityAndAnalysis{
+				DependabotSecurityUpdates: &types.SecurityAndAnalysisStatus{Status: &status},
+			},
+		}
+	}
+
+	name := "repository dependabot security updates are not enabled"
+	testedPolicyName := "dependabot_security_updates_not_enabled"
+	repositoryTestTemplate(t, name, makeMockData("disabled"), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData("enabled"), testedPolicyName, false)
+
+	makeConfigMockData := func(config *dependabot.Config, workflowsCount int) githubcollected.Repository {
+		repo := githubcollected.Repository{DependabotConfig: config, Workflows: []*workflows.Workflow{}}
+		for i := 0; i < workflowsCount; i++ {
+			repo.Workflows = append(repo.Workflows, &workflows.Workflow{Path: ".github/workflows/ci.yml"})
+		}
+		return repo
+	}
+	withActions := &dependabot.Config{Exists: true, Ecosystems: []string{dependabot.GitHubActions, "gomod"}}
+	withoutActions := &dependabot.Config{Exists: true, Ecosystems: []string{"gomod"}}
+
+	name = "repository dependabot version updates are not configured"
+	testedPolicyName = "dependabot_version_updates_not_configured"
+	repositoryTestTemplate(t, name, makeConfigMockData(dependabot.Missing(), 0), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeConfigMockData(withoutActions, 0), testedPolicyName, false)
+
+	name = "repository dependabot doesn't update the workflows actions"
+	testedPolicyName = "dependabot_does_not_update_actions"
+	repositoryTestTemplate(t, name, makeConfigMockData(withoutActions, 1), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeConfigMockData(withActions, 1), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeConfigMockData(withoutActions, 0), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeConfigMockData(dependabot.Missing(), 1), testedPolicyName, false)
+}
+
+func TestRepositorySuspiciousFiles(t *testing.T) {
+	makeMockData := func(paths ...string) githubcollected.Repository {
+		return githubcollected.Repository{SuspiciousFiles: suspicious.Find(paths)}
+	}
+
+	name := "repository has files that usually contain secrets"
+	testedPolicyName := "suspicious_files_present"
+	repositoryTestTemplate(t, name, makeMockData("main.go", "deploy/.env"), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData("main.go", ".env.example"), testedPolicyName, false)
+	repositoryTestTemplate(t, name, githubcollected.Repository{}, testedPolicyName, false)
+}
+
+func TestRepositoryDependencyGraph(t *testing.T) {
+	name := "repository dependency graph is not enabled"
+	testedPolicyName := "dependency_graph_not_enabled"
+	for _, enabled := range bools {
+		enabled := enabled
+		repositoryTestTemplate(t, name, githubcollected.Repository{DependencyGraphEnabled: &enabled}, testedPolicyName, !enabled)
+	}
+	repositoryTestTemplate(t, name, githubcollected.Repository{}, testedPolicyName, false)
+}
+
+func TestRepositoryRulesetsAreEquivalentToBranchProtection(t *testing.T) {
+	makeMockData := func(prot *githubcollected.GitHubQLBranchProtectionRule, rules ...types.BranchRule) githubcollected.Repository {
+		repo := makeRepoForBranch(githubcollected.GitHubQLBranch{BranchProtectionRule: prot})
+		repo.DefaultBranchRules = rules
+		return repo
+	}
+	rule := func(ruleType string, parameters map[string]interface{}) types.BranchRule {
+		return types.BranchRule{
+			RulesetRule:       types.RulesetRule{Type: ruleType, Parameters: parameters},
+			RulesetSourceType: "Organization",
+			RulesetSource:     "org",
+		}
+	}
+
+	name := "repository protected by a ruleset should have branch protection"
+	testedPolicyName := "missing_default_branch_protection"
+	repositoryTestTemplate(t, name, makeMockData(nil, rule("deletion", nil)), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeMockData(nil), testedPolicyName, true)
+
+	noReviews := &githubcollected.GitHubQLBranchProtectionRule{RequiredApprovingReviewCount: github.Int(0)}
+	pullRequest := rule("pull_request", map[string]interface{}{"required_approving_review_count": 1})
+
+	name = "repository ruleset requiring code review"
+	testedPolicyName = "code_review_not_required"
+	repositoryTestTemplate(t, name, makeMockData(noReviews, pullRequest), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeMockData(noReviews), testedPolicyName, true)
+
+	name = "repository ruleset requiring code review by one member"
+	testedPolicyName = "code_review_by_two_members_not_required"
+	repositoryTestTemplate(t, name, makeMockData(noReviews, pullRequest), testedPolicyName, true)
+
+	unsigned := &githubcollected.GitHubQLBranchProtectionRule{RequiresCommitSignatures: github.Bool(false)}
+	name = "repository ruleset requiring signed commits"
+	testedPolicyName = "no_signed_commits"
+	repositoryTestTemplate(t, name, makeMockData(unsigned, rule("required_signatures", nil)), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeMockData(unsigned, rule("deletion", nil)), testedPolicyName, true)
+
+	lastPush := &githubcollected.GitHubQLBranchProtectionRule{RequireLastPushApproval: github.Bool(false)}
+	name = "repository ruleset requiring approval of the most recent push"
+	testedPolicyName = "last_push_approval_not_required"
+	repositoryTestTemplate(t, name, makeMockData(lastPush, rule("pull_request", map[string]interface{}{"require_last_push_approval": true})), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeMockData(lastPush, pullRequest), testedPolicyName, true)
+
+	deployments := &githubcollected.GitHubQLBranchProtectionRule{RequiresDeployments: github.Bool(false)}
+	name = "repository ruleset requiring deployments"
+	testedPolicyName = "deployments_not_required"
+	repositoryTestTemplate(t, name, makeMockData(deployments, rule("required_deployments", map[string]interface{}{"required_deployment_environments": []string{"staging"}})), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeMockData(deployments), testedPolicyName, true)
+}
+
+func TestRepositoryNonDefaultBranches(t *testing.T) {
+	makeMockData := func(branch branches.Branch) githubcollected.Repository {
+		return githubcollected.Repository{Branches: []branches.Branch{branch}}
+	}
+	pullRequest := types.BranchRule{RulesetRule: types.RulesetRule{Type: "pull_request", Parameters: map[string]interface{}{"required_approving_review_count": 1}}}
+	deletion := types.BranchRule{RulesetRule: types.RulesetRule{Type: "deletion"}}
+
+	name := "non-default branch should be protected"
+	testedPolicyName := "non_default_branch_not_protected"
+	repositoryTestTemplate(t, name, makeMockData(branches.Branch{Name: "release/1.0"}), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData(branches.Branch{Name: "release/1.0", Protected: true}), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeMockData(branches.Branch{Name: "release/1.0", Rules: []types.BranchRule{deletion}}), testedPolicyName, false)
+	repositoryTestTemplate(t, name, githubcollected.Repository{}, testedPolicyName, false)
+
+	name = "non-default branch should require code review"
+	testedPolicyName = "non_default_branch_code_review_not_required"
+	repositoryTestTemplate(t, name, makeMockData(branches.Branch{Name: "release/1.0", Protected: true, RequiredApprovingReviewCount: github.Int(0)}), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData(branches.Branch{Name: "release/1.0", Protected: true, RequiredApprovingReviewCount: github.Int(1)}), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeMockData(branches.Branch{Name: "release/1.0", Protected: true}), testedPolicyName, false)
+	repositoryTestTemplate(t, name, makeMockData(branches.Branch{Name: "release/1.0", Rules: []types.BranchRule{deletion}}), testedPolicyName, true)
+	repositoryTestTemplate(t, name, makeMockData(branches.Branch{Name: "release/1.0", Rules: []types.BranchRule{pullRequest}}), testedPolicyName, false)
+}
@@ -3,24 +3,45 @@ package test
 import (
 	"testing"
 
+	"github.com/Legit-Labs/legitify/internal/clients/github/types"
 	githubcollected "github.com/Legit-Labs/legitify/internal/collected/github"
 	"github.com/Legit-Labs/legitify/internal/common/namespace"
 	"github.com/google/go-github/v44/github"
 )
 
 type runnerGroupMockConfiguration struct {
-	allowedByPublic bool
+	allowedByPublic       bool
+	visibility            string
+	selectedRepositories  int
+	runners               []types.SelfHostedRunner
+	restrictedToWorkflows bool
 }
 
 func newRunnerGroupMock(config runnerGroupMockConfiguration) githubcollected.RunnerGroup {
+	visibility := config.visibility
+	if visibility == "" {
+		visibility = "selected"
+	}
 	return githubcollected.RunnerGroup{
 		Organization: defaultOrg,
 		RunnerGroup: &github.RunnerGroup{
 			AllowsPublicRepositories: &config.allowedByPublic,
+			Visibility:               &visibility,
+		},
+		Runners:                   config.runners,
+		SelectedRepositoriesCount: &config.selectedRepositories,
+		WorkflowRestrictions: &types.RunnerGroupWorkflowRestrictions{
+			RestrictedToWorkflows: &config.restrictedToWorkflows,
 		},
 	}
 }
 
+var (
+	persistentRunner = types.SelfHostedRunner{Name: "persistent", Labels: []types.RunnerLabel{{Name: "self-hosted", Type: "read-only"}, {Name: "build", Type: "custom"}}}
+	ephemeralRunner  = types.SelfHostedRunner{Name: "ephemeral", Ephemeral: true, Labels: []types.RunnerLabel{{Name: "build", Type: "custom"}}}
+	defaultLabels    = types.SelfHostedRunner{Name: "default", Ephemeral: true, Labels: []types.RunnerLabel{{Name: "self-hosted", Type: "read-only"}, {Name: "linux", Type: "read-only"}}}
+)
+
 func TestRunnerGroup(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -44,6 +65,67 @@ func TestRunnerGroup(t *testing.T) {
 				allowedByPublic: false,
 			},
 		},
+		{
+			name:             "persistent runner in a group available to all repositories",
+			policyName:       "runner_group_persistent_runners_exposed_to_many_repositories",
+			shouldBeViolated: true,
+			args: runnerGroupMockConfiguration{
+				visibility: "all",
+				runners:    []types.SelfHostedRunner{persistentRunner},
+			},
+		},
+		{
+			name:             "persistent runner in a group available to many selected repositories",
+			policyName:       "runner_group_persistent_runners_exposed_to_many_repositories",
+			shouldBeViolated: true,
+			args: runnerGroupMockConfiguration{
+				selectedRepositories: 25,
+				runners:              []types.SelfHostedRunner{persistentRunner},
+			},
+		},
+		{
+			name:             "persistent runner in a group available to few selected repositories",
+			policyName:       "runner_group_persistent_runners_exposed_to_many_repositories",
+			shouldBeViolated: false,
+			args: runnerGroupMockConfiguration{
+				selectedRepositories: 2,
+				runners:              []types.SelfHostedRunner{persistentRunner},
+			},
+		},
+		{
+			name:             "ephemeral runner in a group available to all repositories",
+			policyName:       "runner_group_persistent_runners_exposed_to_many_repositories",
+			shouldBeViolated: false,
+			args: runnerGroupMockConfiguration{
+				visibility: "all",
+				runners:    []types.SelfHostedRunner{ephemeralRunner},
+			},
+		},
+		{
+			name:             "runner with only default labels",
+			policyName:       "runner_group_runner_uses_only_default_labels",
+			shouldBeViolated: true,
+			args: runnerGroupMockConfiguration{
+				runners: []types.SelfHostedRunner{defaultLabels},
+			},
+		},
+		{
+			name:             "runner with only default labels in a group restricted to workflows",
+			policyName:       "runner_group_runner_uses_only_default_labels",
+			shouldBeViolated: false,
+			args: runnerGroupMockConfiguration{
+				runners:               []types.SelfHostedRunner{defaultLabels},
+				restrictedToWorkflows: true,
+			},
+		},
+		{
+			name:             "runner with custom labels",
+			policyName:       "runner_group_runner_uses_only_default_labels",
+			shouldBeViolated: false,
+			args: runnerGroupMockConfiguration{
+				runners: []types.SelfHostedRunner{ephemeralRunner},
+			},
+		},
 	}
 
 	for _, test := range tests {